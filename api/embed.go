@@ -0,0 +1,9 @@
+// Package api хранит спецификацию OpenAPI сервиса и встраивает её в бинарник.
+package api
+
+import _ "embed"
+
+// Spec — спецификация HTTP API в формате OpenAPI 3.0 (YAML).
+//
+//go:embed openapi.yml
+var Spec []byte
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const importUsage = "usage: pr-manager import [-org id] [-format csv|json] [-on-conflict skip|update|fail] [-dry-run] <file>"
+
+// runImportCommand загружает пользователей и команды из файла напрямую в хранилище.
+// Формат по умолчанию определяется расширением файла; "-" читает стандартный ввод.
+func runImportCommand(ctx context.Context, repo service.UserTeamRepository, orgs service.OrganizationLister, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	org := organizationFlag(fs)
+	format := fs.String("format", "", "file format: csv or json (by extension if empty)")
+	onConflict := fs.String("on-conflict", string(models.ImportConflictSkip), "what to do with existing users: skip, update or fail")
+	dryRun := fs.Bool("dry-run", false, "validate and print the report without saving")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w; %s", err, importUsage)
+	}
+	if fs.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+	ctx, err := organizationContext(ctx, orgs, *org)
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open import file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	rows, err := service.ParseUserImport(in, *format)
+	if err != nil {
+		return err
+	}
+
+	report, err := service.NewUserManager(repo).ImportUsers(ctx, rows, models.ImportOptions{
+		DryRun:     *dryRun,
+		OnConflict: models.ImportConflictPolicy(*onConflict),
+	})
+	if err != nil {
+		return err
+	}
+	printImportReport(report)
+	if report.Failed > 0 {
+		return fmt.Errorf("import rejected: %d invalid rows", report.Failed)
+	}
+	return nil
+}
+
+// printImportReport печатает итог импорта и таблицу строк.
+func printImportReport(report *models.ImportReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tUSER ID\tACTION\tERROR")
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.Row, row.UserId, row.Action, row.Error)
+	}
+	_ = w.Flush()
+
+	state := "applied"
+	switch {
+	case report.DryRun:
+		state = "dry run, nothing saved"
+	case !report.Applied:
+		state = "nothing saved"
+	}
+	fmt.Printf("created %d, updated %d, skipped %d, failed %d, new teams %v (%s)\n",
+		report.Created, report.Updated, report.Skipped, report.Failed, report.TeamsCreated, state)
+}
+
+// organizationFlag добавляет флаг -org: организация, в которой работает команда.
+func organizationFlag(fs *flag.FlagSet) *string {
+	return fs.String("org", "", "organization id; "+models.DefaultOrganization+" if empty")
+}
+
+// organizationContext переключает ctx на организацию из флага -org; пустое значение оставляет организацию default.
+// Неизвестная организация — ошибка: иначе экспорт молча вернул бы пустой архив.
+func organizationContext(ctx context.Context, orgs service.OrganizationLister, organizationID string) (context.Context, error) {
+	organizationID = strings.TrimSpace(organizationID)
+	if organizationID == "" {
+		return ctx, nil
+	}
+	list, err := orgs.ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations: %w", err)
+	}
+	for _, org := range list {
+		if org.OrganizationId == organizationID {
+			return tenant.WithOrganization(ctx, organizationID), nil
+		}
+	}
+	return nil, fmt.Errorf("organization %q does not exist", organizationID)
+}
@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/grpcserver"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/logging"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/metrics"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tracing"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/web"
 )
 
@@ -24,53 +31,232 @@ func main() {
 
 	// Загружаем конфигурацию.
 	config := conf.MustLoad(cfgPath)
-	slog.Info("Configuration loaded successfully", "config_path", cfgPath)
-	slog.Info("Database configuration", "host", config.DBConf.Host, "port", config.DBConf.Port, "user", config.DBConf.User, "database", config.DBConf.Name)
 
-	fmt.Println("DB Config:", config.DBConf.Name, config.DBConf.User, config.DBConf.Host, config.DBConf.Port)
-	// Создаём подключение к базе данных.
+	// Настраиваем журнал до первых сообщений, чтобы весь вывод шёл в одном формате.
+	logger, err := logging.New(config.Log, os.Stderr)
+	if err != nil {
+		slog.Error("Logger initialization failed", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	slog.Info("Configuration loaded successfully", "config_path", cfgPath, "storage_driver", config.Storage.Driver)
+	switch config.Storage.Driver {
+	case conf.StorageDriverSQLite:
+		slog.Info("Database configuration", "path", config.DBConf.Path)
+	case conf.StorageDriverPostgres:
+		slog.Info("Database configuration", "host", config.DBConf.Host, "port", config.DBConf.Port, "user", config.DBConf.User, "database", config.DBConf.Name)
+	}
+
 	ctx := context.Background()
-	DBase, err := repository.NewStorage(ctx, &config.DBConf)
+
+	// Настраиваем экспорт трейсов до создания хранилища, чтобы спаны SQL попадали в провайдер.
+	shutdownTracing, err := tracing.Setup(ctx, config.Tracing)
 	if err != nil {
-		slog.Error("Database initialization failed", "error", err)
+		slog.Error("Tracing initialization failed", "error", err)
 		os.Exit(1)
 	}
-	slog.Info("Database storage initialized successfully")
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("Tracing shutdown failed", "error", err)
+		}
+	}()
+	slog.Info("Tracing configured", "exporter", config.Tracing.Exporter)
+
+	// Создаём хранилище согласно storage.driver.
+	DBase, err := openStorage(ctx, config)
+	if err != nil {
+		slog.Error("Storage initialization failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Storage initialized successfully")
+
+	// Подкоманды (например, migrate) выполняются вместо запуска сервера.
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, DBase, os.Args[1:]); err != nil {
+			slog.Error("Command failed", "command", os.Args[1], "error", err)
+			DBase.Close()
+			os.Exit(1)
+		}
+		DBase.Close()
+		return
+	}
+
+	if m, fsys, ok := migratorFor(DBase); ok && config.AutoMigrate {
+		applied, err := applyMigrations(ctx, m, fsys)
+		if err != nil {
+			slog.Error("Database migration failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Database migrations applied", "versions", applied)
+	}
 
 	// Создаём менеджер пользователей (реализация UserTeamService).
 	userManager := service.NewUserManager(DBase)
+	userManager.SetAbsences(DBase)
+	userManager.SetWorkingHours(DBase)
+	userManager.SetReviewLoad(DBase, config.ReviewLoad.DefaultMaxOpenReviews)
+	userManager.SetAffinityRules(DBase)
 	slog.Info("User manager created successfully")
 
 	// Создаём менеджер Pull Request (реализация PullRequestService).
 	prManager := &service.PullRequestManager{}
 	prManager = prManager.NewPullRequestService(DBase, userManager)
+	prManager.SetReviewQueue(DBase)
+	prManager.SetRepositories(DBase)
+	prManager.SetAffinityRules(DBase)
+	prManager.SetShadowReviews(DBase)
+	prManager.SetDecisions(DBase)
+	prManager.SetOrganizations(DBase)
+	prManager.ConfigureStats(config.Stats.CacheTTLDuration(), config.Stats.TurnaroundWindowDuration())
 	slog.Info("Pull request manager created successfully")
 
+	// Регистрируем метрики Prometheus.
+	appMetrics := metrics.New()
+	prManager.SetMetrics(appMetrics)
+	appMetrics.RegisterOpenReviews(prManager.OpenReviewsByOrganization)
+	appMetrics.RegisterCacheSize(userManager.CacheSize)
+	if pg, ok := DBase.(*repository.Storage); ok {
+		appMetrics.RegisterDBPool(pg.PoolStat)
+	}
+
 	// Поднимаем HTTP-сервер.
-	server := web.New(config.HTTPServConf, prManager, userManager)
+	snapshots := service.NewSnapshotManager(DBase, userManager)
+	staleReviews := service.NewStaleReviewManager(DBase, prManager, service.StaleReviewConfig{
+		Interval:     config.StaleReviews.IntervalDuration(),
+		SLA:          config.StaleReviews.SLADuration(),
+		TeamSLA:      config.StaleReviews.TeamSLADurations(),
+		MaxRotations: config.StaleReviews.MaxRotations,
+		LeaseTTL:     config.StaleReviews.LeaseTTLDuration(),
+	})
+	staleReviews.SetWorkingHours(DBase)
+	absences := service.NewAbsenceManager(DBase, prManager, service.AbsenceConfig{
+		Interval: config.Absences.IntervalDuration(),
+		LeaseTTL: config.Absences.LeaseTTLDuration(),
+	})
+	// Планировщики обходят все организации.
+	staleReviews.SetOrganizations(DBase)
+	absences.SetOrganizations(DBase)
+	webOpts := []web.Option{
+		web.WithMetrics(appMetrics), web.WithTracing(), web.WithSnapshots(snapshots), web.WithStaleReviews(staleReviews),
+		web.WithAbsences(absences), web.WithWorkingHours(service.NewWorkingHoursManager(DBase)),
+		web.WithCapacity(service.NewCapacityManager(DBase, prManager, config.ReviewLoad.DefaultMaxOpenReviews)),
+		web.WithRepositories(service.NewRepositoryManager(DBase)), web.WithAffinityRules(service.NewAffinityRuleManager(DBase)),
+		web.WithLearners(service.NewLearnerManager(DBase)),
+		web.WithDecisions(service.NewDecisionManager(DBase)),
+		web.WithRequestValidation(),
+	}
+	// С auth.admin_token каждый запрос требует токен организации; без него всё работает в организации default.
+	var grpcOpts []grpcserver.Option
+	if config.Auth.Enabled() {
+		organizations := service.NewOrganizationManager(DBase)
+		webOpts = append(webOpts, web.WithOrganizations(organizations, config.Auth.AdminToken))
+		grpcOpts = append(grpcOpts, grpcserver.WithOrganizations(organizations, config.Auth.AdminToken))
+	}
+	server := web.New(config.HTTPServConf, prManager, userManager, webOpts...)
 	slog.Info("HTTP server created successfully", "address", server.Address)
 
-	// Запускаем сервер в отдельной горутине.
-	go func() {
-		if err := server.Start(); err != nil && err.Error() != "http: Server closed" {
-			slog.Error("Server failed to start", "error", err)
-			os.Exit(1)
-		}
-	}()
+	// Поднимаем gRPC-сервер на том же сервисном слое, если задан grpcServer.port.
+	servers := []runner{server}
+	if config.GRPCServConf.Enabled() {
+		grpcServer := grpcserver.New(config.GRPCServConf, prManager, userManager, grpcOpts...)
+		servers = append(servers, grpcServer)
+		slog.Info("gRPC server created successfully", "address", grpcServer.Address)
+	}
+
+	// Запускаем серверы в отдельных горутинах; падение любого из них останавливает сервис.
+	failed := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- err
+			}
+		}()
+	}
+
+	// Планировщики работают на каждом инстансе, но проверки выполняет только лидер своей аренды.
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+	schedulers := []func(context.Context){absences.Run}
+	if config.StaleReviews.Enabled {
+		schedulers = append(schedulers, staleReviews.Run)
+	}
+	var schedulersDone sync.WaitGroup
+	for _, run := range schedulers {
+		schedulersDone.Add(1)
+		go func() {
+			defer schedulersDone.Done()
+			run(schedulerCtx)
+		}()
+	}
 
 	slog.Info("PR Manager service started successfully", "address", server.Address)
 
-	// Ожидаем сигнал остановки для плавного завершения работы.
+	// Ожидаем сигнал остановки или ошибку сервера.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	slog.Info("Shutting down server...")
+	exitCode := 0
+	select {
+	case <-quit:
+		slog.Info("Shutting down servers...")
+	case err := <-failed:
+		slog.Error("Server failed", "error", err)
+		exitCode = 1
+	}
 
-	// Выполняем корректное завершение сервера с тайм-аутом.
-	if err := server.Shutdown(ctx); err != nil {
+	// Выполняем корректное завершение всех серверов с общим тайм-аутом.
+	if err := shutdownAll(ctx, servers); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
-		os.Exit(1)
+		exitCode = 1
 	}
 
+	// Дожидаемся текущих проходов планировщиков до закрытия хранилища.
+	stopScheduler()
+	schedulersDone.Wait()
+	DBase.Close()
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 	slog.Info("Server exited properly")
 }
+
+// runner — сервер с блокирующим запуском и корректной остановкой.
+type runner interface {
+	Start() error
+	Shutdown(ctx context.Context) error
+}
+
+// shutdownAll останавливает серверы параллельно, чтобы их таймауты не складывались.
+func shutdownAll(ctx context.Context, servers []runner) error {
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = srv.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runCommand выполняет подкоманду из аргументов командной строки.
+func runCommand(ctx context.Context, storage storageBackend, args []string) error {
+	switch args[0] {
+	case "migrate":
+		m, fsys, ok := migratorFor(storage)
+		if !ok {
+			return fmt.Errorf("migrate requires a SQL storage driver: %q or %q", conf.StorageDriverPostgres, conf.StorageDriverSQLite)
+		}
+		return runMigrateCommand(ctx, m, fsys, args[1:])
+	case "import":
+		return runImportCommand(ctx, storage, storage, args[1:])
+	case "export":
+		return runExportCommand(ctx, storage, storage, args[1:])
+	case "import-snapshot":
+		return runImportSnapshotCommand(ctx, storage, storage, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
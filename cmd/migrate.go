@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/migrate"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/sqlite"
+	"github.com/AlekseyZapadovnikov/pr-manager/migrations"
+)
+
+const migrateUsage = "usage: pr-manager migrate up|down [steps]|status"
+
+// migrator — хранилище, умеющее применять и откатывать версионированные миграции.
+type migrator interface {
+	MigrateUp(ctx context.Context, migrations []migrate.Migration) ([]int64, error)
+	MigrateDown(ctx context.Context, migrations []migrate.Migration, steps int) ([]int64, error)
+	MigrationStatus(ctx context.Context, migrations []migrate.Migration) ([]migrate.Status, error)
+}
+
+// migratorFor возвращает мигратор и набор миграций для SQL-хранилища; ok=false для хранилища без схемы.
+func migratorFor(storage storageBackend) (m migrator, fsys fs.FS, ok bool) {
+	switch s := storage.(type) {
+	case *repository.Storage:
+		return s, migrations.FS, true
+	case *sqlite.Storage:
+		return s, migrations.SQLite(), true
+	default:
+		return nil, nil, false
+	}
+}
+
+// runMigrateCommand выполняет подкоманду migrate: up, down [steps] или status.
+func runMigrateCommand(ctx context.Context, storage migrator, fsys fs.FS, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	ms, err := migrate.Load(fsys)
+	if err != nil {
+		return fmt.Errorf("load migrations: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := storage.MigrateUp(ctx, ms)
+		for _, v := range applied {
+			fmt.Printf("applied %d\n", v)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive integer, got %q", args[1])
+			}
+		}
+		reverted, err := storage.MigrateDown(ctx, ms, steps)
+		for _, v := range reverted {
+			fmt.Printf("reverted %d\n", v)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("nothing to revert")
+		}
+		return nil
+	case "status":
+		status, err := storage.MigrationStatus(ctx, ms)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(status)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", args[0], migrateUsage)
+	}
+}
+
+// applyMigrations применяет встроенные миграции при старте сервиса.
+func applyMigrations(ctx context.Context, storage migrator, fsys fs.FS) ([]int64, error) {
+	ms, err := migrate.Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return storage.MigrateUp(ctx, ms)
+}
+
+// printMigrationStatus печатает таблицу состояния миграций.
+func printMigrationStatus(status []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range status {
+		appliedAt := "pending"
+		if st.AppliedAt != nil {
+			appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,703 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/pkg/client"
+)
+
+// newFlagSet создаёт набор флагов подкоманды, который не печатает ошибки сам.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs разбирает флаги и проверяет число позиционных аргументов (max < 0 — без ограничения).
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		return usagef("%s: %v", fs.Name(), err)
+	}
+	n := fs.NArg()
+	if n < min || (max >= 0 && n > max) {
+		return usagef("%s: unexpected number of arguments", fs.Name())
+	}
+	return nil
+}
+
+// openInput открывает файл или стандартный ввод для "-".
+func (a *app) openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(a.stdin), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open input: %w", err)
+	}
+	return f, nil
+}
+
+// ---------- служебные ----------
+
+func runHealth(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("health")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := a.api.Health(ctx); err != nil {
+		return err
+	}
+	status := struct {
+		Status string `json:"status"`
+	}{Status: "ok"}
+	return a.out.print(status, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s is ok\n", a.api.BaseURL())
+	})
+}
+
+// ---------- команды ----------
+
+func runTeamAdd(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team add")
+	file := fs.String("f", "", "JSON file with the team in the /team/add format")
+	inactive := fs.String("inactive", "", "comma-separated user ids to add as inactive")
+	if err := parseArgs(fs, args, 0, -1); err != nil {
+		return err
+	}
+
+	var team client.Team
+	switch {
+	case *file != "":
+		if fs.NArg() > 0 {
+			return usagef("team add: -f cannot be combined with positional arguments")
+		}
+		in, err := a.openInput(*file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		if err := json.NewDecoder(in).Decode(&team); err != nil {
+			return fmt.Errorf("decode team: %w", err)
+		}
+	case fs.NArg() < 2:
+		return usagef("team add: team name and at least one member are required")
+	default:
+		off := make(map[string]bool)
+		for _, id := range strings.Split(*inactive, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				off[id] = true
+			}
+		}
+		team.TeamName = fs.Arg(0)
+		for _, arg := range fs.Args()[1:] {
+			id, username, ok := strings.Cut(arg, "=")
+			if !ok || id == "" || username == "" {
+				return usagef("team add: member %q must look like user_id=username", arg)
+			}
+			team.Members = append(team.Members, client.TeamMember{UserId: id, Username: username, IsActive: !off[id]})
+		}
+	}
+
+	created, err := a.api.AddTeam(ctx, team)
+	if err != nil {
+		return err
+	}
+	return a.out.print(created, teamTable(created))
+}
+
+func runTeamGet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team get")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	team, err := a.api.GetTeam(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(team, teamTable(team))
+}
+
+func runTeamDeactivate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team deactivate")
+	if err := parseArgs(fs, args, 2, -1); err != nil {
+		return err
+	}
+	res, err := a.api.DeactivateTeamMembers(ctx, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	return a.out.print(res, deactivateTable(res))
+}
+
+func runTeamAddRule(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team add-rule")
+	description := fs.String("description", "", "why the rule exists")
+	if err := parseArgs(fs, args, 4, 4); err != nil {
+		return err
+	}
+	rule, err := a.api.AddTeamRule(ctx, client.AddTeamRuleRequest{
+		TeamName:    fs.Arg(0),
+		Kind:        client.AffinityRuleKind(strings.ToUpper(fs.Arg(1))),
+		UserId:      fs.Arg(2),
+		WithUserIds: strings.Split(fs.Arg(3), ","),
+		Description: *description,
+	})
+	if err != nil {
+		return err
+	}
+	return a.out.print(rule, rulesTable([]client.AffinityRule{*rule}))
+}
+
+func runTeamRules(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team rules")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	rules, err := a.api.TeamRules(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(rules, rulesTable(rules))
+}
+
+func runTeamDeleteRule(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team delete-rule")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil || id <= 0 {
+		return usagef("team delete-rule: %q is not a rule id", fs.Arg(0))
+	}
+	if err := a.api.DeleteTeamRule(ctx, id); err != nil {
+		return err
+	}
+	deleted := struct {
+		RuleId int64 `json:"rule_id"`
+	}{id}
+	return a.out.print(deleted, func(w *tabwriter.Writer) { fmt.Fprintf(w, "rule %d deleted\n", id) })
+}
+
+func runTeamSetLearners(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team set-learners")
+	if err := parseArgs(fs, args, 1, -1); err != nil {
+		return err
+	}
+	learners, err := a.api.SetTeamLearners(ctx, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	return a.out.print(learners, learnersTable(learners))
+}
+
+func runTeamLearners(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("team learners")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	learners, err := a.api.TeamLearners(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(learners, learnersTable(learners))
+}
+
+// ---------- пользователи ----------
+
+func runUserSetActive(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user set-active")
+	if err := parseArgs(fs, args, 2, 2); err != nil {
+		return err
+	}
+	active, err := strconv.ParseBool(fs.Arg(1))
+	if err != nil {
+		return usagef("user set-active: %q is not a boolean", fs.Arg(1))
+	}
+	user, err := a.api.SetUserActive(ctx, fs.Arg(0), active)
+	if err != nil {
+		return err
+	}
+	return a.out.print(user, userTable(user))
+}
+
+func runUserReviews(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user reviews")
+	repository := fs.String("repository", "", "only PRs of this repository")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	var reviews *client.UserReviews
+	var err error
+	if *repository != "" {
+		reviews, err = a.api.GetUserReviewsInRepository(ctx, fs.Arg(0), *repository)
+	} else {
+		reviews, err = a.api.GetUserReviews(ctx, fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	return a.out.print(reviews, reviewsTable(reviews))
+}
+
+func runUserAbsenceAdd(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user absence-add")
+	reason := fs.String("reason", "", "why the user is away")
+	if err := parseArgs(fs, args, 3, 3); err != nil {
+		return err
+	}
+	var from, to timeFlag
+	if err := from.Set(fs.Arg(1)); err != nil {
+		return usagef("user absence-add: start: %v", err)
+	}
+	if err := to.Set(fs.Arg(2)); err != nil {
+		return usagef("user absence-add: end: %v", err)
+	}
+	absence, err := a.api.AddAbsence(ctx, client.AddAbsenceRequest{
+		UserId: fs.Arg(0), StartsAt: from.t, EndsAt: to.t, Reason: *reason,
+	})
+	if err != nil {
+		return err
+	}
+	return a.out.print(absence, absencesTable([]client.Absence{*absence}))
+}
+
+func runUserAbsences(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user absences")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	absences, err := a.api.UserAbsences(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(absences, absencesTable(absences))
+}
+
+func runUserAbsenceCancel(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user absence-cancel")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil || id <= 0 {
+		return usagef("user absence-cancel: %q is not an absence id", fs.Arg(0))
+	}
+	absence, err := a.api.CancelAbsence(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.out.print(absence, absencesTable([]client.Absence{*absence}))
+}
+
+func runUserSetHours(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user set-hours")
+	if err := parseArgs(fs, args, 4, 4); err != nil {
+		return err
+	}
+	hours, err := a.api.SetWorkingHours(ctx, client.WorkingHours{
+		UserId: fs.Arg(0), TimeZone: fs.Arg(1), Start: fs.Arg(2), End: fs.Arg(3),
+	})
+	if err != nil {
+		return err
+	}
+	return a.out.print(hours, workingHoursTable(hours))
+}
+
+func runUserHours(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user hours")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	hours, err := a.api.GetWorkingHours(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(hours, workingHoursTable(hours))
+}
+
+func runUserClearHours(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user clear-hours")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	hours, err := a.api.ClearWorkingHours(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(hours, workingHoursTable(hours))
+}
+
+func runUserSetCapacity(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user set-capacity")
+	if err := parseArgs(fs, args, 2, 2); err != nil {
+		return err
+	}
+	limit, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || limit < 0 {
+		return usagef("user set-capacity: %q is not a review limit", fs.Arg(1))
+	}
+	load, err := a.api.SetCapacity(ctx, fs.Arg(0), limit)
+	if err != nil {
+		return err
+	}
+	return a.out.print(load, reviewerLoadTable([]client.ReviewerLoad{*load}))
+}
+
+func runUserLoad(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("user load")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	load, err := a.api.GetReviewerLoad(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(load, reviewerLoadTable([]client.ReviewerLoad{*load}))
+}
+
+// ---------- репозитории ----------
+
+func runRepoSet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("repo set")
+	owner := fs.String("owner", "", "team that reviews the repository PRs")
+	reviewers := fs.Int("reviewers", 0, "reviewers per PR, 1 or 2")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	repo, err := a.api.SetRepository(ctx, client.Repository{RepositoryName: fs.Arg(0), OwnerTeam: *owner, RequiredReviewers: *reviewers})
+	if err != nil {
+		return err
+	}
+	return a.out.print(repo, repositoriesTable([]client.Repository{*repo}))
+}
+
+func runRepoGet(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("repo get")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	repo, err := a.api.GetRepository(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(repo, repositoriesTable([]client.Repository{*repo}))
+}
+
+func runRepoList(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("repo list")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	repos, err := a.api.ListRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	return a.out.print(repos, repositoriesTable(repos))
+}
+
+// ---------- pull requests ----------
+
+func runPRCreate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr create")
+	added := fs.Int("lines-added", 0, "added lines")
+	deleted := fs.Int("lines-deleted", 0, "deleted lines")
+	files := fs.Int("files", 0, "changed files")
+	priority := fs.String("priority", "", "LOW, NORMAL, HIGH or URGENT")
+	labels := fs.String("labels", "", "comma-separated labels")
+	repository := fs.String("repository", "", "repository of the PR")
+	number := fs.Int("number", 0, "PR number in the repository")
+	if err := parseArgs(fs, args, 3, -1); err != nil {
+		return err
+	}
+	req := client.CreatePullRequestRequest{
+		PullRequestId:   fs.Arg(0),
+		Repository:      *repository,
+		Number:          *number,
+		AuthorId:        fs.Arg(1),
+		PullRequestName: strings.Join(fs.Args()[2:], " "),
+		LinesAdded:      *added,
+		LinesDeleted:    *deleted,
+		FilesChanged:    *files,
+		Priority:        client.PullRequestPriority(strings.ToUpper(*priority)),
+	}
+	if *labels != "" {
+		req.Labels = strings.Split(*labels, ",")
+	}
+	// С номером идентификатор выводится сервером из репозитория и номера.
+	if *number > 0 && req.PullRequestId == "-" {
+		req.PullRequestId = ""
+	}
+	res, err := a.api.CreatePullRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.out.print(res, createTable(res))
+}
+
+func runPRMerge(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr merge")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	pr, err := a.api.MergePullRequest(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(pr, pullRequestTable(pr))
+}
+
+func runPRReassign(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr reassign")
+	if err := parseArgs(fs, args, 2, 2); err != nil {
+		return err
+	}
+	res, err := a.api.ReassignReviewer(ctx, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return a.out.print(res, reassignTable(res))
+}
+
+func runPRActivity(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr activity")
+	if err := parseArgs(fs, args, 2, 2); err != nil {
+		return err
+	}
+	activity, err := a.api.RecordReviewActivity(ctx, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	return a.out.print(activity, activityTable(activity))
+}
+
+func runPRRotations(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr rotations")
+	limit := fs.Int("limit", 0, "max rows, newest first")
+	if err := parseArgs(fs, args, 0, 1); err != nil {
+		return err
+	}
+	rotations, err := a.api.ReviewRotations(ctx, client.ReviewRotationFilter{PullRequestId: fs.Arg(0), Limit: *limit})
+	if err != nil {
+		return err
+	}
+	return a.out.print(rotations, rotationsTable(rotations))
+}
+
+func runPRExplain(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("pr explain")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	explanation, err := a.api.ExplainPullRequest(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.out.print(explanation, explanationTable(explanation))
+}
+
+// ---------- статистика ----------
+
+// timeFlag принимает время в RFC 3339 или дату YYYY-MM-DD (полночь UTC).
+type timeFlag struct {
+	t time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.t.IsZero() {
+		return ""
+	}
+	return f.t.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(s string) error {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			f.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("expected RFC 3339 time or YYYY-MM-DD date")
+}
+
+func runStatsAssignments(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("stats assignments")
+	team := fs.String("team", "", "author team")
+	repository := fs.String("repository", "", "repository of the PRs")
+	status := fs.String("status", "", "OPEN or MERGED")
+	limit := fs.Int("limit", 0, "max rows in per-user and per-PR lists")
+	var from, to timeFlag
+	fs.Var(&from, "from", "created at or after (RFC 3339 or YYYY-MM-DD)")
+	fs.Var(&to, "to", "created before (RFC 3339 or YYYY-MM-DD)")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	stats, err := a.api.AssignmentStats(ctx, client.AssignmentStatsFilter{
+		TeamName:   *team,
+		Repository: *repository,
+		Status:     client.PullRequestStatus(strings.ToUpper(*status)),
+		From:       from.t,
+		To:         to.t,
+		Limit:      *limit,
+	})
+	if err != nil {
+		return err
+	}
+	return a.out.print(stats, assignmentStatsTable(stats))
+}
+
+func runStatsTurnaround(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("stats turnaround")
+	repository := fs.String("repository", "", "repository of the PRs")
+	var from, to timeFlag
+	fs.Var(&from, "from", "merged at or after (RFC 3339 or YYYY-MM-DD)")
+	fs.Var(&to, "to", "merged before (RFC 3339 or YYYY-MM-DD)")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	stats, err := a.api.TurnaroundStats(ctx, client.TurnaroundFilter{Repository: *repository, From: from.t, To: to.t})
+	if err != nil {
+		return err
+	}
+	return a.out.print(stats, turnaroundTable(stats))
+}
+
+// ---------- администрирование ----------
+
+func runAdminImport(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("admin import")
+	format := fs.String("format", "", "file format: csv or json (by extension if empty)")
+	onConflict := fs.String("on-conflict", "", "what to do with existing users: skip, update or fail")
+	dryRun := fs.Bool("dry-run", false, "validate and print the report without saving")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if *format != client.ImportFormatCSV && *format != client.ImportFormatJSON {
+		return usagef("admin import: -format must be csv or json")
+	}
+
+	in, err := a.openInput(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	report, err := a.api.ImportUsers(ctx, in, *format, client.ImportOptions{
+		DryRun:     *dryRun,
+		OnConflict: client.ImportConflictPolicy(*onConflict),
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.out.print(report, importReportTable(report)); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("import rejected: %d invalid rows", report.Failed)
+	}
+	return nil
+}
+
+// runAdminExport сохраняет архив в JSON независимо от -o: это формат, который принимает admin restore.
+func runAdminExport(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("admin export")
+	file := fs.String("f", "", "write the snapshot to this file instead of stdout")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	snap, err := a.api.ExportSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := a.out.w
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return fmt.Errorf("create snapshot file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(snap); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if *file != "" {
+		counts := snap.Counts()
+		return a.out.print(counts, countsTable(&counts))
+	}
+	return nil
+}
+
+func runAdminRestore(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("admin restore")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	in, err := a.openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var snap client.Snapshot
+	if err := json.NewDecoder(in).Decode(&snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	counts, err := a.api.RestoreSnapshot(ctx, &snap)
+	if err != nil {
+		return err
+	}
+	return a.out.print(counts, countsTable(counts))
+}
+
+// ---------- организации ----------
+
+func runOrgCreate(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("org create")
+	name := fs.String("name", "", "display name, defaults to the id")
+	if err := parseArgs(fs, args, 1, 1); err != nil {
+		return err
+	}
+	org, token, err := a.api.CreateOrganization(ctx, fs.Arg(0), *name)
+	if err != nil {
+		return err
+	}
+	created := struct {
+		Organization *client.Organization `json:"organization"`
+		Token        string               `json:"token"`
+	}{org, token}
+	return a.out.print(created, organizationCreatedTable(org, token))
+}
+
+func runOrgList(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("org list")
+	if err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	orgs, err := a.api.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	return a.out.print(orgs, organizationsTable(orgs))
+}
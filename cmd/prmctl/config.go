@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	defaultBaseURL = "http://localhost:8080"
+
+	envURL    = "PRMCTL_URL"
+	envToken  = "PRMCTL_TOKEN"
+	envConfig = "PRMCTL_CONFIG"
+)
+
+// config — адрес сервиса и токен доступа.
+type config struct {
+	BaseURL string `json:"base_url"`
+	Token   string `json:"token"`
+}
+
+// loadConfig собирает настройки по приоритету: флаги, переменные окружения, файл конфигурации, значения по умолчанию.
+// Отсутствие файла по умолчанию не ошибка; явно указанный файл должен существовать.
+func loadConfig(path, flagURL, flagToken string, getenv func(string) string) (config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = getenv(envConfig)
+		explicit = path != ""
+	}
+	if !explicit {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "prmctl", "config.json")
+		}
+	}
+
+	var cfg config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return config{}, fmt.Errorf("parse config %s: %w", path, err)
+			}
+		case errors.Is(err, fs.ErrNotExist) && !explicit:
+		default:
+			return config{}, fmt.Errorf("read config: %w", err)
+		}
+	}
+
+	cfg.BaseURL = firstNonEmpty(flagURL, getenv(envURL), cfg.BaseURL, defaultBaseURL)
+	cfg.Token = firstNonEmpty(flagToken, getenv(envToken), cfg.Token)
+	return cfg, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+// Команда prmctl — клиент командной строки для HTTP API сервиса PR Manager.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/pkg/client"
+)
+
+const usage = `usage: prmctl [-config file] [-url url] [-token token] [-o table|json|yaml] <command> [args]
+
+commands:
+  health
+  team add [-f file.json] [-inactive id,...] <team_name> <user_id>=<username>...
+  team get <team_name>
+  team deactivate <team_name> <user_id>...
+  team add-rule [-description text] <team_name> NEVER_PAIR|ALWAYS_INCLUDE|REQUIRE_PAIR <user_id> <user_id,...>
+  team rules <team_name>
+  team delete-rule <rule_id>
+  team set-learners <team_name> [user_id...]
+  team learners <team_name>
+  user set-active <user_id> true|false
+  user reviews [-repository name] <user_id>
+  user absence-add [-reason text] <user_id> <from> <to>
+  user absences <user_id>
+  user absence-cancel <absence_id>
+  user set-hours <user_id> <time_zone> <HH:MM> <HH:MM>
+  user hours <user_id>
+  user clear-hours <user_id>
+  user set-capacity <user_id> <max_open_reviews>
+  user load <user_id>
+  pr create [-repository name] [-number n] [-lines-added n] [-lines-deleted n] [-files n] [-priority level] [-labels a,b]
+            <pull_request_id|-> <author_id> <name>
+  pr merge <pull_request_id>
+  pr reassign <pull_request_id> <old_user_id>
+  pr activity <pull_request_id> <user_id>
+  pr rotations [-limit n] [pull_request_id]
+  pr explain <pull_request_id>
+  repo set [-owner team] [-reviewers n] <repository_name>
+  repo get <repository_name>
+  repo list
+  stats assignments [-team name] [-repository name] [-status OPEN|MERGED] [-from time] [-to time] [-limit n]
+  stats turnaround [-repository name] [-from time] [-to time]
+  admin import [-format csv|json] [-on-conflict skip|update|fail] [-dry-run] <file|->
+  admin export [-f file]
+  admin restore <file|->
+  org create [-name text] <organization_id>
+  org list
+
+The service address and token are taken from flags, then PRMCTL_URL and PRMCTL_TOKEN,
+then the config file (PRMCTL_CONFIG or <user config dir>/prmctl/config.json).`
+
+// Коды завершения.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// usageError — ошибка в аргументах командной строки.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// app — общее состояние для подкоманд.
+type app struct {
+	api   *client.Client
+	out   printer
+	stdin io.Reader
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+// commands — подкоманды по группам; команда без группы хранится под пустым именем.
+var commands = map[string]map[string]command{
+	"health": {"": runHealth},
+	"team": {
+		"add":          runTeamAdd,
+		"get":          runTeamGet,
+		"deactivate":   runTeamDeactivate,
+		"add-rule":     runTeamAddRule,
+		"rules":        runTeamRules,
+		"delete-rule":  runTeamDeleteRule,
+		"set-learners": runTeamSetLearners,
+		"learners":     runTeamLearners,
+	},
+	"user": {
+		"set-active":     runUserSetActive,
+		"reviews":        runUserReviews,
+		"absence-add":    runUserAbsenceAdd,
+		"absences":       runUserAbsences,
+		"absence-cancel": runUserAbsenceCancel,
+		"set-hours":      runUserSetHours,
+		"hours":          runUserHours,
+		"clear-hours":    runUserClearHours,
+		"set-capacity":   runUserSetCapacity,
+		"load":           runUserLoad,
+	},
+	"pr": {
+		"create":    runPRCreate,
+		"merge":     runPRMerge,
+		"reassign":  runPRReassign,
+		"activity":  runPRActivity,
+		"rotations": runPRRotations,
+		"explain":   runPRExplain,
+	},
+	"repo": {
+		"set":  runRepoSet,
+		"get":  runRepoGet,
+		"list": runRepoList,
+	},
+	"stats": {
+		"assignments": runStatsAssignments,
+		"turnaround":  runStatsTurnaround,
+	},
+	"admin": {
+		"import":  runAdminImport,
+		"export":  runAdminExport,
+		"restore": runAdminRestore,
+	},
+	"org": {
+		"create": runOrgCreate,
+		"list":   runOrgList,
+	},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run разбирает аргументы, выполняет команду и возвращает код завершения.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("prmctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("config", "", "path to the JSON config file")
+	baseURL := fs.String("url", "", "service base url")
+	token := fs.String("token", "", "access token")
+	format := fs.String("o", formatTable, "output format: table, json or yaml")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, usage)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "error: %v\n%s\n", err, usage)
+		return exitUsage
+	}
+
+	err := dispatch(ctx, fs.Args(), *configPath, *baseURL, *token, *format, stdin, stdout, getenv)
+	var uerr usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &uerr):
+		fmt.Fprintf(stderr, "error: %v\n%s\n", err, usage)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "error: %s\n", describeError(err))
+		return exitError
+	}
+}
+
+func dispatch(ctx context.Context, args []string, configPath, baseURL, token, format string,
+	stdin io.Reader, stdout io.Writer, getenv func(string) string,
+) error {
+	if format != formatTable && format != formatJSON && format != formatYAML {
+		return usagef("unknown output format %q", format)
+	}
+	if len(args) == 0 {
+		return usagef("command is required")
+	}
+
+	name := args[0]
+	group, ok := commands[name]
+	if !ok {
+		return usagef("unknown command %q", name)
+	}
+	args = args[1:]
+	cmd, ok := group[""]
+	if !ok {
+		if len(args) == 0 {
+			return usagef("%s: subcommand is required, one of %s", name, subcommandNames(group))
+		}
+		if cmd, ok = group[args[0]]; !ok {
+			return usagef("unknown subcommand %q, expected one of %s", args[0], subcommandNames(group))
+		}
+		args = args[1:]
+	}
+
+	cfg, err := loadConfig(configPath, baseURL, token, getenv)
+	if err != nil {
+		return err
+	}
+	api, err := client.New(cfg.BaseURL, client.WithToken(cfg.Token))
+	if err != nil {
+		return err
+	}
+	return cmd(ctx, &app{api: api, out: printer{format: format, w: stdout}, stdin: stdin}, args)
+}
+
+func subcommandNames(group map[string]command) string {
+	names := make([]string, 0, len(group))
+	for name := range group {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/pkg/client"
+)
+
+// Форматы вывода.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// printer печатает ответы API в выбранном формате.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+// print выводит v как JSON или YAML, а в табличном формате вызывает table.
+func (p printer) print(v any, table func(w *tabwriter.Writer)) error {
+	switch p.format {
+	case formatJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		return writeYAML(p.w, v)
+	default:
+		w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+}
+
+// writeYAML печатает v в YAML с теми же именами полей, что и в JSON API, сохраняя порядок полей.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetStyle убирает JSON-стиль ({...}, [...], кавычки), унаследованный при разборе.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		resetStyle(child)
+	}
+}
+
+// ---------- таблицы ----------
+
+func teamTable(team *client.Team) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "TEAM\t%s\n\n", team.TeamName)
+		fmt.Fprintln(w, "USER ID\tUSERNAME\tACTIVE")
+		for _, m := range team.Members {
+			fmt.Fprintf(w, "%s\t%s\t%t\n", m.UserId, m.Username, m.IsActive)
+		}
+	}
+}
+
+func userTable(user *client.User) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "USER ID\tUSERNAME\tTEAM\tACTIVE")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", user.UserId, user.Username, user.TeamName, user.IsActive)
+	}
+}
+
+func pullRequestTable(pr *client.PullRequest) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		writePullRequestRow(w, pr)
+	}
+}
+
+func writePullRequestRow(w *tabwriter.Writer, pr *client.PullRequest) {
+	fmt.Fprintln(w, "PR ID\tNAME\tAUTHOR\tSTATUS\tREVIEWERS\tSHADOWS\tCREATED\tMERGED")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status,
+		orDash(strings.Join(pr.AssignedReviewers, ",")), orDash(strings.Join(pr.ShadowReviewers, ",")),
+		formatTimePtr(pr.CreatedAt), formatTimePtr(pr.MergedAt))
+}
+
+func createTable(res *client.CreatePullRequestResult) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		writePullRequestRow(w, res.PR)
+		if len(res.ReviewerLoad) > 0 {
+			fmt.Fprintln(w)
+			reviewerLoadTable(res.ReviewerLoad)(w)
+		}
+		if res.PendingReviewers > 0 {
+			fmt.Fprintf(w, "\nqueued: %d reviewer(s) pending\n", res.PendingReviewers)
+		}
+	}
+}
+
+func reassignTable(res *client.ReassignResult) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		writePullRequestRow(w, res.PR)
+		fmt.Fprintf(w, "\nreplaced by %s\n", res.ReplacedBy)
+	}
+}
+
+func activityTable(activity *client.ReviewActivity) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "PR ID\tREVIEWER\tLAST ACTIVITY")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", activity.PullRequestId, activity.UserId, formatTimePtr(&activity.LastActivityAt))
+	}
+}
+
+func rotationsTable(rotations []client.ReviewRotation) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "PR ID\tTEAM\tOLD\tNEW\tIDLE\tROTATED")
+		for _, r := range rotations {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.PullRequestId, orDash(r.TeamName), r.OldUserId, r.NewUserId,
+				time.Duration(r.IdleSeconds)*time.Second, formatTimePtr(&r.RotatedAt))
+		}
+	}
+}
+
+func explanationTable(explanation *client.PullRequestExplanation) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "PR ID\t%s\n", explanation.PullRequestId)
+		for _, d := range explanation.Decisions {
+			fmt.Fprintf(w, "\n%s\t%s\tteam %s\t%s\n", d.Operation, d.Strategy, d.TeamName, formatTimePtr(&d.DecidedAt))
+			fmt.Fprintf(w, "SELECTED\t%s\n", orDash(strings.Join(d.Selected, ",")))
+			if len(d.Replaced) > 0 {
+				fmt.Fprintf(w, "REPLACED\t%s\n", strings.Join(d.Replaced, ","))
+			}
+			if d.Pending > 0 {
+				fmt.Fprintf(w, "PENDING\t%d\n", d.Pending)
+			}
+			for _, rule := range d.Rules {
+				fmt.Fprintf(w, "RULE\t%s\n", rule)
+			}
+			fmt.Fprintln(w, "CANDIDATE\tOUTCOME\tOPEN REVIEWS\tWEIGHTED\tLIMIT\tREASON")
+			for _, c := range d.Candidates {
+				open, weighted, limit := "-", "-", "-"
+				if c.Load != nil {
+					open, weighted = strconv.Itoa(c.Load.OpenReviews), strconv.Itoa(c.Load.WeightedLoad)
+					if c.Load.MaxOpenReviews > 0 {
+						limit = strconv.Itoa(c.Load.MaxOpenReviews)
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.UserId, c.Outcome, open, weighted, limit, orDash(c.Reason))
+			}
+		}
+	}
+}
+
+func absencesTable(absences []client.Absence) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tUSER\tFROM\tTO\tREASON\tREASSIGNED")
+		for _, a := range absences {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", a.AbsenceId, a.UserId, formatTimePtr(&a.StartsAt),
+				formatTimePtr(&a.EndsAt), orDash(a.Reason), formatTimePtr(a.ReassignedAt))
+		}
+	}
+}
+
+func workingHoursTable(hours *client.WorkingHours) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "USER\tTIME ZONE\tHOURS")
+		fmt.Fprintf(w, "%s\t%s\t%s-%s Mon-Fri\n", hours.UserId, hours.TimeZone, hours.Start, hours.End)
+	}
+}
+
+func reviewerLoadTable(loads []client.ReviewerLoad) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "USER\tOPEN REVIEWS\tWEIGHTED\tLIMIT")
+		for _, l := range loads {
+			limit := "-"
+			if l.MaxOpenReviews > 0 {
+				limit = strconv.Itoa(l.MaxOpenReviews)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", l.UserId, l.OpenReviews, l.WeightedLoad, limit)
+		}
+	}
+}
+
+func repositoriesTable(repos []client.Repository) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "REPOSITORY\tOWNER TEAM\tREVIEWERS")
+		for _, r := range repos {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", r.RepositoryName, orDash(r.OwnerTeam), r.RequiredReviewers)
+		}
+	}
+}
+
+func rulesTable(rules []client.AffinityRule) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tTEAM\tKIND\tUSER\tWITH\tDESCRIPTION")
+		for _, r := range rules {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", r.RuleId, r.TeamName, r.Kind, r.UserId,
+				strings.Join(r.WithUserIds, ","), orDash(r.Description))
+		}
+	}
+}
+
+func learnersTable(learners *client.TeamLearners) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "TEAM\t%s\n\n", learners.TeamName)
+		fmt.Fprintln(w, "LEARNER\tOPEN SHADOW REVIEWS")
+		for _, l := range learners.Learners {
+			fmt.Fprintf(w, "%s\t%d\n", l.UserId, l.OpenShadowReviews)
+		}
+	}
+}
+
+func organizationsTable(orgs []client.Organization) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ORGANIZATION\tNAME\tCREATED")
+		for _, o := range orgs {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", o.OrganizationId, o.Name, formatTimePtr(&o.CreatedAt))
+		}
+	}
+}
+
+// organizationCreatedTable печатает токен отдельной строкой: он показывается один раз.
+func organizationCreatedTable(org *client.Organization, token string) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		organizationsTable([]client.Organization{*org})(w)
+		fmt.Fprintf(w, "\nTOKEN\t%s\n", token)
+	}
+}
+
+func reviewsTable(reviews *client.UserReviews) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "REVIEWER\t%s\n\n", reviews.UserId)
+		fmt.Fprintln(w, "PR ID\tNAME\tAUTHOR\tSTATUS\tROLE")
+		for _, pr := range reviews.PullRequests {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, orDash(string(pr.Role)))
+		}
+	}
+}
+
+func deactivateTable(res *client.TeamBulkDeactivateResult) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "TEAM\t%s\n", res.TeamName)
+		fmt.Fprintf(w, "DEACTIVATED\t%s\n\n", orDash(strings.Join(res.Deactivated, ",")))
+		fmt.Fprintln(w, "PR ID\tOLD REVIEWER\tNEW REVIEWER")
+		for _, r := range res.Reassignments {
+			for _, rep := range r.Replacements {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", r.PullRequestId, rep.OldUserId, orDash(rep.NewUserId))
+			}
+		}
+	}
+}
+
+func assignmentStatsTable(stats *client.AssignmentStats) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "TEAM\tOPEN\tMERGED\tAVG REVIEWERS\tLOAD IMBALANCE")
+		for _, t := range stats.ByTeam {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.2f\n", t.TeamName, t.OpenCount, t.MergedCount, t.AvgReviewers, t.LoadImbalance)
+		}
+		fmt.Fprintln(w, "\nUSER ID\tUSERNAME\tASSIGNMENTS\tWEIGHTED")
+		for _, u := range stats.ByUser {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", u.UserId, u.Username, u.Assignments, u.WeightedLoad)
+		}
+		fmt.Fprintln(w, "\nPR ID\tNAME\tREVIEWERS")
+		for _, pr := range stats.ByPullRequest {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", pr.PullRequestId, pr.PullRequestName, pr.ReviewerCount)
+		}
+	}
+}
+
+func turnaroundTable(stats *client.TurnaroundStats) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "WINDOW\t%s .. %s\n\n", stats.From.Format(time.RFC3339), stats.To.Format(time.RFC3339))
+		fmt.Fprintln(w, "GROUP\tKEY\tCOUNT\tP50\tP90\tP99")
+		row := func(group, key string, p client.TurnaroundPercentiles) {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", group, key, p.Count,
+				formatSeconds(p.P50Seconds), formatSeconds(p.P90Seconds), formatSeconds(p.P99Seconds))
+		}
+		row("overall", "-", stats.Overall)
+		for _, t := range stats.ByTeam {
+			row("team", t.TeamName, t.TurnaroundPercentiles)
+		}
+		for _, u := range stats.ByAuthor {
+			row("author", u.UserId, u.TurnaroundPercentiles)
+		}
+		for _, u := range stats.ByReviewer {
+			row("reviewer", u.UserId, u.TurnaroundPercentiles)
+		}
+	}
+}
+
+func importReportTable(report *client.ImportReport) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ROW\tUSER ID\tACTION\tERROR")
+		for _, row := range report.Rows {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.Row, row.UserId, row.Action, row.Error)
+		}
+
+		state := "applied"
+		switch {
+		case report.DryRun:
+			state = "dry run, nothing saved"
+		case !report.Applied:
+			state = "nothing saved"
+		}
+		fmt.Fprintf(w, "\ncreated %d, updated %d, skipped %d, failed %d, new teams %v (%s)\n",
+			report.Created, report.Updated, report.Skipped, report.Failed, report.TeamsCreated, state)
+	}
+}
+
+func countsTable(counts *client.SnapshotCounts) func(w *tabwriter.Writer) {
+	return func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "TEAMS\tUSERS\tPULL REQUESTS\tREVIEWERS")
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\n", counts.Teams, counts.Users, counts.PullRequests, counts.Reviewers)
+	}
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+func formatSeconds(s float64) string {
+	return (time.Duration(s) * time.Second).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ---------- ошибки ----------
+
+// errorHints поясняют коды ошибок API.
+var errorHints = map[string]string{
+	client.CodeTeamExists:     "team already exists",
+	client.CodePRExists:       "pull request already exists",
+	client.CodePRMerged:       "pull request is already merged and cannot be changed",
+	client.CodeNotAssigned:    "user is not a reviewer of this pull request",
+	client.CodeNoCandidate:    "no active team member is available to review under the team's rules",
+	client.CodeNotFound:       "not found",
+	client.CodeNotEmpty:       "target database already contains data",
+	client.CodeInvalidParam:   "invalid parameter",
+	client.CodeInvalidPayload: "invalid request body",
+	client.CodeMissingParam:   "required parameter is missing",
+	client.CodeUnauthorized:   "not authorized, check the token",
+	client.CodeInternalError:  "server error, see service logs",
+}
+
+// describeError превращает ошибку API в понятное сообщение.
+func describeError(err error) string {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	hint, ok := errorHints[apiErr.Code]
+	if !ok {
+		return apiErr.Error()
+	}
+	// Сообщения доменных ошибок начинаются с кода, который и так выводится в скобках.
+	msg := strings.TrimPrefix(apiErr.Message, apiErr.Code+": ")
+	if len(apiErr.Details) > 0 {
+		msg += " (" + strings.Join(apiErr.Details, "; ") + ")"
+	}
+	if msg == "" {
+		return fmt.Sprintf("%s [%s]", hint, apiErr.Code)
+	}
+	return fmt.Sprintf("%s: %s [%s]", hint, msg, apiErr.Code)
+}
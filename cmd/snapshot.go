@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+)
+
+const (
+	exportUsage         = "usage: pr-manager export [-org id] [-o file]"
+	importSnapshotUsage = "usage: pr-manager import-snapshot [-org id] <file>"
+)
+
+// runExportCommand пишет архив состояния в файл или в стандартный вывод.
+func runExportCommand(ctx context.Context, repo service.SnapshotRepository, orgs service.OrganizationLister, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	org := organizationFlag(fs)
+	output := fs.String("o", "-", "output file; - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w; %s", err, exportUsage)
+	}
+	if fs.NArg() != 0 {
+		return errors.New(exportUsage)
+	}
+	ctx, err := organizationContext(ctx, orgs, *org)
+	if err != nil {
+		return err
+	}
+
+	snap, err := service.NewSnapshotManager(repo, nil).Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("create snapshot file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(snap); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if *output != "-" {
+		counts := snap.Counts()
+		fmt.Printf("exported %d teams, %d users, %d pull requests, %d reviewers to %s\n",
+			counts.Teams, counts.Users, counts.PullRequests, counts.Reviewers, *output)
+	}
+	return nil
+}
+
+// runImportSnapshotCommand восстанавливает архив состояния в пустую базу; "-" читает стандартный ввод.
+func runImportSnapshotCommand(ctx context.Context, repo service.SnapshotRepository, orgs service.OrganizationLister, args []string) error {
+	fs := flag.NewFlagSet("import-snapshot", flag.ContinueOnError)
+	org := organizationFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w; %s", err, importSnapshotUsage)
+	}
+	if fs.NArg() != 1 {
+		return errors.New(importSnapshotUsage)
+	}
+	ctx, err := organizationContext(ctx, orgs, *org)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open snapshot file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var snap models.Snapshot
+	if err := json.NewDecoder(in).Decode(&snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	counts, err := service.NewSnapshotManager(repo, nil).Restore(ctx, &snap)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %d teams, %d users, %d pull requests, %d reviewers\n",
+		counts.Teams, counts.Users, counts.PullRequests, counts.Reviewers)
+	return nil
+}
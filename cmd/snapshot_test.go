@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+func TestCommandsUseOrganizationFlag(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	for _, id := range []string{"payments", "search"} {
+		require.NoError(t, storage.CreateOrganization(ctx, &models.Organization{OrganizationId: id, Name: id}, "hash-"+id))
+	}
+	dir := t.TempDir()
+	users := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(users, []byte("user_id,username,team_name,is_active\nu1,Alice,backend,true\n"), 0o600))
+
+	require.NoError(t, runImportCommand(ctx, storage, storage, []string{"-org", "payments", users}))
+	_, err := storage.GetTeam(ctx, "backend")
+	require.Error(t, err, "import without -org must not reach the default organization")
+	_, err = storage.GetTeam(tenant.WithOrganization(ctx, "payments"), "backend")
+	require.NoError(t, err)
+
+	archive := filepath.Join(dir, "payments.json")
+	require.NoError(t, runExportCommand(ctx, storage, storage, []string{"-org", "payments", "-o", archive}))
+	data, err := os.ReadFile(archive)
+	require.NoError(t, err)
+	var snap models.Snapshot
+	require.NoError(t, json.Unmarshal(data, &snap))
+	require.Equal(t, []models.SnapshotTeam{{TeamName: "backend"}}, snap.Teams)
+
+	require.NoError(t, runImportSnapshotCommand(ctx, storage, storage, []string{"-org", "search", archive}))
+	_, err = storage.GetTeam(tenant.WithOrganization(ctx, "search"), "backend")
+	require.NoError(t, err, "the archive is restored into the organization of -org")
+	_, err = storage.GetTeam(ctx, "backend")
+	require.Error(t, err)
+
+	err = runExportCommand(ctx, storage, storage, []string{"-org", "ghost", "-o", filepath.Join(dir, "ghost.json")})
+	require.ErrorContains(t, err, `organization "ghost" does not exist`)
+	require.NoFileExists(t, filepath.Join(dir, "ghost.json"))
+	err = runImportSnapshotCommand(ctx, storage, storage, []string{"-org", "ghost", archive})
+	require.ErrorContains(t, err, `organization "ghost" does not exist`)
+}
+
+// TestCommandsReachRunningServer проверяет, что пользователи, записанные командами мимо сервера,
+// сразу доступны его UserManager без перезапуска.
+func TestCommandsReachRunningServer(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	users := service.NewUserManager(storage)
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	require.NoError(t, users.AddTeam(ctx, models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "Alice", IsActive: true},
+	}}))
+
+	dir := t.TempDir()
+	csv := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(csv, []byte("user_id,username,team_name,is_active\nu2,Bob,backend,true\nu3,Carol,backend,true\n"), 0o600))
+	require.NoError(t, runImportCommand(ctx, storage, storage, []string{csv}))
+
+	created, err := prs.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", PullRequestName: "Fix", AuthorId: "u1"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, created.PR.AssignedReviewers, "imported users are review candidates")
+
+	archive := filepath.Join(dir, "snapshot.json")
+	require.NoError(t, runExportCommand(ctx, storage, storage, []string{"-o", archive}))
+	require.NoError(t, storage.CreateOrganization(ctx, &models.Organization{OrganizationId: "search", Name: "search"}, "hash"))
+	require.NoError(t, runImportSnapshotCommand(ctx, storage, storage, []string{"-org", "search", archive}))
+
+	searchCtx := tenant.WithOrganization(ctx, "search")
+	user, err := users.SetUserActivity(searchCtx, "u3", false)
+	require.NoError(t, err, "restored users are found without a cache warm-up")
+	require.False(t, user.IsActive)
+	stored, err := storage.GetUser(searchCtx, "u3")
+	require.NoError(t, err)
+	require.False(t, stored.IsActive)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/sqlite"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+)
+
+// storageBackend объединяет репозитории, которые main передаёт сервисам.
+type storageBackend interface {
+	service.PullRequestRepository
+	service.UserTeamRepository
+	service.SnapshotRepository
+	service.StaleReviewRepository
+	service.AbsenceRepository
+	service.WorkingHoursRepository
+	service.CapacityRepository
+	service.ReviewQueueRepository
+	service.RepositoryStore
+	service.OrganizationRepository
+	service.AffinityRuleRepository
+	service.LearnerRepository
+	service.DecisionRepository
+	Close()
+}
+
+// openStorage создаёт хранилище, выбранное в storage.driver.
+func openStorage(ctx context.Context, cfg *conf.Config) (storageBackend, error) {
+	switch cfg.Storage.Driver {
+	case conf.StorageDriverMemory:
+		return memory.NewStorage(), nil
+	case conf.StorageDriverSQLite:
+		return sqlite.NewStorage(ctx, cfg.DBConf.Path)
+	case conf.StorageDriverPostgres:
+		return repository.NewStorage(ctx, &cfg.DBConf)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
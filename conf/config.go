@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -14,9 +16,33 @@ var (
 	numberRegex     = regexp.MustCompile(`^\d+$`)
 )
 
+// Поддерживаемые значения storage.driver.
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverSQLite   = "sqlite"
+	StorageDriverMemory   = "memory"
+)
+
+// Поддерживаемые значения dataBase.driver.
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+)
+
 type Config struct {
-	HTTPServConf HttpServConf `json:"httpServer" validate:"required"`
-	DBConf       DbConf       `json:"dataBase" validate:"required"`
+	HTTPServConf HttpServConf     `json:"httpServer" validate:"required"`
+	GRPCServConf GRPCServConf     `json:"grpcServer"`
+	DBConf       DbConf           `json:"dataBase" validate:"required"`
+	Storage      StorageConf      `json:"storage"`
+	Tracing      TracingConf      `json:"tracing"`
+	Log          LogConf          `json:"log"`
+	Stats        StatsConf        `json:"stats"`
+	StaleReviews StaleReviewsConf `json:"staleReviews"`
+	Absences     AbsencesConf     `json:"absences"`
+	ReviewLoad   ReviewLoadConf   `json:"reviewLoad"`
+	Auth         AuthConf         `json:"auth"`
+	// AutoMigrate включает применение встроенных миграций при старте сервиса.
+	AutoMigrate bool `json:"auto_migrate"`
 }
 
 type HttpServConf struct {
@@ -30,12 +56,179 @@ func (s *HttpServConf) GetAddress() string {
 	return fmt.Sprintf("%s:%s", s.Host, s.Port)
 }
 
+// GRPCServConf настраивает gRPC-сервер; пустой Port отключает его.
+type GRPCServConf struct {
+	// Host — адрес прослушивания; по умолчанию совпадает с httpServer.host.
+	Host string `json:"host"`
+	Port string `json:"port" validate:"omitempty,is-number"`
+}
+
+// Enabled сообщает, нужно ли поднимать gRPC-сервер.
+func (s *GRPCServConf) Enabled() bool {
+	return s.Port != ""
+}
+
+// GetAddress возвращает строку host:port для запуска gRPC-сервера.
+func (s *GRPCServConf) GetAddress() string {
+	return fmt.Sprintf("%s:%s", s.Host, s.Port)
+}
+
+// StorageConf выбирает реализацию хранилища.
+type StorageConf struct {
+	// Driver — "memory" для запуска без базы данных или SQL-движок: "postgres" (по умолчанию) либо "sqlite".
+	// SQL-движок задаёт dataBase.driver; здесь его можно не указывать, а указанный должен с ним совпадать.
+	Driver string `json:"driver" validate:"omitempty,oneof=memory postgres sqlite"`
+}
+
+// Поддерживаемые значения log.format.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LogConf настраивает журнал сервиса.
+type LogConf struct {
+	// Format — "text" (по умолчанию) или "json".
+	Format string `json:"format" validate:"omitempty,oneof=text json"`
+	// Level — debug, info (по умолчанию), warn или error.
+	Level string `json:"level" validate:"omitempty,oneof=debug info warn error"`
+}
+
+// StatsConf настраивает расчёт статистики; длительности задаются строками time.ParseDuration.
+type StatsConf struct {
+	// CacheTTL — время жизни кэша статистики времени до слияния; по умолчанию 1m.
+	CacheTTL string `json:"cache_ttl" validate:"omitempty,duration"`
+	// TurnaroundWindow — окно /stats/turnaround без параметра from; по умолчанию 720h.
+	TurnaroundWindow string `json:"turnaround_window" validate:"omitempty,duration"`
+}
+
+// CacheTTLDuration возвращает CacheTTL; пустое значение даёт 0, то есть умолчание сервиса.
+func (s StatsConf) CacheTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(s.CacheTTL)
+	return d
+}
+
+// TurnaroundWindowDuration возвращает TurnaroundWindow; пустое значение даёт 0, то есть умолчание сервиса.
+func (s StatsConf) TurnaroundWindowDuration() time.Duration {
+	d, _ := time.ParseDuration(s.TurnaroundWindow)
+	return d
+}
+
+// StaleReviewsConf настраивает планировщик замены неактивных ревьюеров; длительности задаются строками time.ParseDuration.
+type StaleReviewsConf struct {
+	// Enabled запускает планировщик; API активности и истории замен доступно и без него.
+	Enabled bool `json:"enabled"`
+	// Interval — период проверки открытых PR; по умолчанию 5m.
+	Interval string `json:"interval" validate:"omitempty,duration"`
+	// SLA — допустимое время без активности ревьюера; по умолчанию 48h.
+	SLA string `json:"sla" validate:"omitempty,duration"`
+	// TeamSLA переопределяет SLA для отдельных команд.
+	TeamSLA map[string]string `json:"team_sla" validate:"omitempty,dive,duration"`
+	// MaxRotations — сколько раз можно менять ревьюеров одного PR; по умолчанию 2.
+	MaxRotations int `json:"max_rotations" validate:"gte=0"`
+	// LeaseTTL — срок аренды лидера; по умолчанию два интервала.
+	LeaseTTL string `json:"lease_ttl" validate:"omitempty,duration"`
+}
+
+// IntervalDuration возвращает Interval; пустое значение даёт 0, то есть умолчание сервиса.
+func (s StaleReviewsConf) IntervalDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Interval)
+	return d
+}
+
+// SLADuration возвращает SLA; пустое значение даёт 0, то есть умолчание сервиса.
+func (s StaleReviewsConf) SLADuration() time.Duration {
+	d, _ := time.ParseDuration(s.SLA)
+	return d
+}
+
+// TeamSLADurations возвращает TeamSLA в виде длительностей.
+func (s StaleReviewsConf) TeamSLADurations() map[string]time.Duration {
+	result := make(map[string]time.Duration, len(s.TeamSLA))
+	for team, sla := range s.TeamSLA {
+		d, _ := time.ParseDuration(sla)
+		result[team] = d
+	}
+	return result
+}
+
+// LeaseTTLDuration возвращает LeaseTTL; пустое значение даёт 0, то есть умолчание сервиса.
+func (s StaleReviewsConf) LeaseTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(s.LeaseTTL)
+	return d
+}
+
+// AbsencesConf настраивает планировщик, передающий ревью отсутствующих пользователей в начале периода.
+type AbsencesConf struct {
+	// Interval — период проверки начавшихся отсутствий; по умолчанию 1m.
+	Interval string `json:"interval" validate:"omitempty,duration"`
+	// LeaseTTL — срок аренды лидера; по умолчанию два интервала.
+	LeaseTTL string `json:"lease_ttl" validate:"omitempty,duration"`
+}
+
+// IntervalDuration возвращает Interval; пустое значение даёт 0, то есть умолчание сервиса.
+func (a AbsencesConf) IntervalDuration() time.Duration {
+	d, _ := time.ParseDuration(a.Interval)
+	return d
+}
+
+// LeaseTTLDuration возвращает LeaseTTL; пустое значение даёт 0, то есть умолчание сервиса.
+func (a AbsencesConf) LeaseTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(a.LeaseTTL)
+	return d
+}
+
+// ReviewLoadConf настраивает лимиты открытых ревью на одного ревьювера.
+type ReviewLoadConf struct {
+	// DefaultMaxOpenReviews — лимит для пользователей без личного лимита; 0 — без лимита.
+	DefaultMaxOpenReviews int `json:"default_max_open_reviews" validate:"gte=0"`
+}
+
+// AuthConf настраивает доступ к API по токенам организаций.
+type AuthConf struct {
+	// AdminToken включает организации: API требует токен, администратор создаёт организации
+	// и работает в организации default. Пустое значение оставляет API открытым в одной организации default.
+	AdminToken string `json:"admin_token"`
+}
+
+// Enabled сообщает, включены ли организации и проверка токенов.
+func (a AuthConf) Enabled() bool {
+	return a.AdminToken != ""
+}
+
+// Поддерживаемые значения tracing.exporter.
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+// TracingConf настраивает экспорт трейсов OpenTelemetry.
+type TracingConf struct {
+	// Exporter — "none" (по умолчанию), "otlp" (OTLP/HTTP), "stdout" или "file".
+	Exporter string `json:"exporter" validate:"omitempty,oneof=none otlp stdout file"`
+	// Endpoint — host:port коллектора OTLP; пустое значение берёт OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4318.
+	Endpoint string `json:"endpoint"`
+	// Insecure отключает TLS при отправке в коллектор.
+	Insecure bool `json:"insecure"`
+	// File — путь к файлу для экспортёра "file".
+	File string `json:"file" validate:"required_if=Exporter file"`
+	// ServiceName попадает в ресурс service.name; по умолчанию "pr-manager".
+	ServiceName string `json:"service_name"`
+	// SampleRatio — доля сэмплируемых корневых трейсов от 0 до 1; 0 означает 1.
+	SampleRatio float64 `json:"sample_ratio" validate:"gte=0,lte=1"`
+}
+
 type DbConf struct {
-	Host     string `json:"host" validate:"required"`
-	Port     string `json:"port" validate:"required,is-number"`
-	User     string `json:"user" validate:"required"`
-	Password string `json:"password" validate:"required"`
-	Name     string `json:"name" validate:"required"`
+	// Driver — SQL-движок хранилища: "postgres" (по умолчанию) или "sqlite"; для SQLite нужен только Path.
+	Driver   string `json:"driver" validate:"omitempty,oneof=postgres sqlite"`
+	Path     string `json:"path" validate:"required_if=Driver sqlite"`
+	Host     string `json:"host" validate:"required_unless=Driver sqlite"`
+	Port     string `json:"port" validate:"required_unless=Driver sqlite,omitempty,is-number"`
+	User     string `json:"user" validate:"required_unless=Driver sqlite"`
+	Password string `json:"password" validate:"required_unless=Driver sqlite"`
+	Name     string `json:"name" validate:"required_unless=Driver sqlite"`
 }
 
 // MustLoad читает файл конфигурации, применяет значения из окружения и валидирует структуру.
@@ -51,14 +244,48 @@ func MustLoad(path string) *Config {
 	}
 
 	applyEnvOverrides(&cfg)
+	if err := resolveStorageDriver(&cfg); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+	if cfg.GRPCServConf.Host == "" {
+		cfg.GRPCServConf.Host = cfg.HTTPServConf.Host
+	}
+	if cfg.Tracing.Exporter == "" {
+		cfg.Tracing.Exporter = TracingExporterNone
+	}
 
-	if err := configValidator.Struct(cfg); err != nil {
+	// Хранилищу в памяти настройки базы данных не нужны.
+	if cfg.Storage.Driver == StorageDriverMemory {
+		err = configValidator.StructExcept(cfg, "DBConf")
+	} else {
+		err = configValidator.Struct(cfg)
+	}
+	if err != nil {
 		panic("invalid config: " + err.Error())
 	}
 
 	return &cfg
 }
 
+// resolveStorageDriver согласует storage.driver с dataBase.driver, который и выбирает SQL-движок.
+// Пустое значение берётся из другого поля, по умолчанию — PostgreSQL; противоречащие значения — ошибка,
+// а не догадка о том, какое из них имелось в виду.
+func resolveStorageDriver(cfg *Config) error {
+	if cfg.Storage.Driver == StorageDriverMemory {
+		return nil
+	}
+	switch {
+	case cfg.DBConf.Driver == "" && cfg.Storage.Driver == "":
+		cfg.DBConf.Driver = DBDriverPostgres
+	case cfg.DBConf.Driver == "":
+		cfg.DBConf.Driver = cfg.Storage.Driver
+	case cfg.Storage.Driver != "" && cfg.Storage.Driver != cfg.DBConf.Driver:
+		return fmt.Errorf("storage.driver %q conflicts with dataBase.driver %q", cfg.Storage.Driver, cfg.DBConf.Driver)
+	}
+	cfg.Storage.Driver = cfg.DBConf.Driver
+	return nil
+}
+
 // applyEnvOverrides подменяет поля конфигурации значениями из переменных окружения.
 func applyEnvOverrides(cfg *Config) {
 	override := func(key string, target *string) {
@@ -71,11 +298,65 @@ func applyEnvOverrides(cfg *Config) {
 	override("HTTP_PORT", &cfg.HTTPServConf.Port)
 	override("HTTP_BASE_URL", &cfg.HTTPServConf.BaseURL)
 
+	override("GRPC_HOST", &cfg.GRPCServConf.Host)
+	override("GRPC_PORT", &cfg.GRPCServConf.Port)
+
+	override("DB_DRIVER", &cfg.DBConf.Driver)
+	override("DB_PATH", &cfg.DBConf.Path)
 	override("DB_HOST", &cfg.DBConf.Host)
 	override("DB_PORT", &cfg.DBConf.Port)
 	override("DB_USER", &cfg.DBConf.User)
 	override("DB_PASSWORD", &cfg.DBConf.Password)
 	override("DB_NAME", &cfg.DBConf.Name)
+
+	override("STORAGE_DRIVER", &cfg.Storage.Driver)
+
+	override("LOG_FORMAT", &cfg.Log.Format)
+	override("LOG_LEVEL", &cfg.Log.Level)
+
+	override("STATS_CACHE_TTL", &cfg.Stats.CacheTTL)
+	override("STATS_TURNAROUND_WINDOW", &cfg.Stats.TurnaroundWindow)
+
+	overrideBool("STALE_REVIEWS_ENABLED", &cfg.StaleReviews.Enabled)
+	override("STALE_REVIEWS_INTERVAL", &cfg.StaleReviews.Interval)
+	override("STALE_REVIEWS_SLA", &cfg.StaleReviews.SLA)
+	override("ABSENCES_INTERVAL", &cfg.Absences.Interval)
+	overrideInt("REVIEW_LOAD_DEFAULT_MAX", &cfg.ReviewLoad.DefaultMaxOpenReviews)
+
+	override("AUTH_ADMIN_TOKEN", &cfg.Auth.AdminToken)
+
+	override("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	override("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	override("TRACING_FILE", &cfg.Tracing.File)
+	overrideBool("TRACING_INSECURE", &cfg.Tracing.Insecure)
+
+	overrideBool("AUTO_MIGRATE", &cfg.AutoMigrate)
+}
+
+// overrideBool подменяет булево поле, если переменная окружения содержит корректное значение.
+func overrideBool(key string, target *bool) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		panic(fmt.Sprintf("invalid boolean in %s: %s", key, val))
+	}
+	*target = parsed
+}
+
+// overrideInt подменяет целое поле, если переменная окружения содержит корректное значение.
+func overrideInt(key string, target *int) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		panic(fmt.Sprintf("invalid integer in %s: %s", key, val))
+	}
+	*target = parsed
 }
 
 // newConfigValidator настраивает валидатор и регистрирует пользовательские проверки.
@@ -86,5 +367,11 @@ func newConfigValidator() *validator.Validate {
 	}); err != nil {
 		panic("failed to register is-number validation: " + err.Error())
 	}
+	if err := v.RegisterValidation("duration", func(fl validator.FieldLevel) bool {
+		d, err := time.ParseDuration(fl.Field().String())
+		return err == nil && d > 0
+	}); err != nil {
+		panic("failed to register duration validation: " + err.Error())
+	}
 	return v
 }
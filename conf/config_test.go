@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStorageDriver(t *testing.T) {
+	tests := []struct {
+		name          string
+		storage, db   string
+		wantStorage   string
+		wantDB        string
+		wantErrSubstr string
+	}{
+		{name: "defaults to postgres", wantStorage: StorageDriverPostgres, wantDB: DBDriverPostgres},
+		{name: "database driver selects sqlite", db: DBDriverSQLite, wantStorage: StorageDriverSQLite, wantDB: DBDriverSQLite},
+		{name: "storage driver selects sqlite", storage: StorageDriverSQLite, wantStorage: StorageDriverSQLite, wantDB: DBDriverSQLite},
+		{name: "matching values", storage: StorageDriverSQLite, db: DBDriverSQLite, wantStorage: StorageDriverSQLite, wantDB: DBDriverSQLite},
+		{name: "memory ignores database", storage: StorageDriverMemory, db: DBDriverSQLite, wantStorage: StorageDriverMemory, wantDB: DBDriverSQLite},
+		{
+			name: "postgres storage with sqlite database", storage: StorageDriverPostgres, db: DBDriverSQLite,
+			wantErrSubstr: `storage.driver "postgres" conflicts with dataBase.driver "sqlite"`,
+		},
+		{
+			name: "sqlite storage with postgres database", storage: StorageDriverSQLite, db: DBDriverPostgres,
+			wantErrSubstr: `storage.driver "sqlite" conflicts with dataBase.driver "postgres"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Storage: StorageConf{Driver: tt.storage}, DBConf: DbConf{Driver: tt.db}}
+			err := resolveStorageDriver(cfg)
+			if tt.wantErrSubstr != "" {
+				require.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStorage, cfg.Storage.Driver)
+			require.Equal(t, tt.wantDB, cfg.DBConf.Driver)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Сентинельные ошибки домена, используемые сервисами, репозиториями и веб-слоем.
@@ -14,7 +15,11 @@ var (
 	ErrNoCandidate  = errors.New("NO_CANDIDATE")
 	ErrNotFound     = errors.New("NOT_FOUND")
 	ErrUnauthorized = errors.New("UNAUTHORIZED")
+	ErrForbidden    = errors.New("FORBIDDEN")
 	ErrTeamIsEmty   = errors.New("EMPTY_TEAM")
+	ErrInvalidParam = errors.New("INVALID_PARAM")
+	ErrNotEmpty     = errors.New("NOT_EMPTY")
+	ErrOrgExists    = errors.New("ORG_EXISTS")
 )
 
 // NewTeamExistsError возвращает ошибку о том, что команда с таким названием уже существует.
@@ -52,7 +57,53 @@ func NewUnauthorizedError(action string) error {
 	return fmt.Errorf("%w: not authorized to %s", ErrUnauthorized, action)
 }
 
+// NewForbiddenError используется, когда вызывающий опознан, но действие ему не разрешено.
+func NewForbiddenError(action string) error {
+	return fmt.Errorf("%w: not allowed to %s", ErrForbidden, action)
+}
+
+// NewInvalidParamError сообщает о недопустимом значении параметра запроса.
+func NewInvalidParamError(param, reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidParam, param, reason)
+}
+
 // NewErrTeamIsEmty возвращает ошибку о пустой команде.
 func NewErrTeamIsEmty(teamID string) error {
 	return fmt.Errorf("team with id %s is emty", teamID)
 }
+
+// NewOrgExistsError возвращает ошибку о том, что организация с таким идентификатором уже существует.
+func NewOrgExistsError(organizationID string) error {
+	return fmt.Errorf("%w: organization %s already exists", ErrOrgExists, organizationID)
+}
+
+// NewNotEmptyError сообщает, что операция требует пустого хранилища, а в нём уже есть данные.
+func NewNotEmptyError(what string) error {
+	return fmt.Errorf("%w: %s is not empty", ErrNotEmpty, what)
+}
+
+// RejectedCandidatesError — NO_CANDIDATE с объяснением, почему ни одно назначение не удовлетворяет правилам подбора.
+type RejectedCandidatesError struct {
+	PullRequestID string
+	// Reasons — нарушенные правила и причины отказа отдельным кандидатам, по строке на каждую.
+	Reasons []string
+}
+
+// NewRejectedCandidatesError сообщает, что правила подбора не оставили допустимого назначения ревьюеров для PR.
+func NewRejectedCandidatesError(prID string, reasons []string) error {
+	return &RejectedCandidatesError{PullRequestID: prID, Reasons: reasons}
+}
+
+func (e *RejectedCandidatesError) Error() string {
+	return e.Summary() + ": " + strings.Join(e.Reasons, "; ")
+}
+
+// Summary описывает ошибку без причин — для ответов, где причины передаются отдельным списком.
+func (e *RejectedCandidatesError) Summary() string {
+	return fmt.Sprintf("%s: no reviewer assignment for pull request %s satisfies the affinity rules", ErrNoCandidate, e.PullRequestID)
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrNoCandidate).
+func (e *RejectedCandidatesError) Unwrap() error {
+	return ErrNoCandidate
+}
@@ -7,3 +7,11 @@ type ReassignResponse struct {
 	PR         *models.PullRequest
 	ReplacedBy string
 }
+
+// CreateResponse описывает результат создания PR: нагрузку назначенных ревьюеров и ревью, ждущие в очереди.
+type CreateResponse struct {
+	PR           *models.PullRequest
+	ReviewerLoad []models.ReviewerLoad
+	// PendingReviewers — сколько ревьюеров будет назначено позже, когда у коллег освободится лимит.
+	PendingReviewers int
+}
@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// authorizationMetadataKey — ключ метаданных с токеном; совпадает с HTTP-заголовком Authorization.
+const authorizationMetadataKey = "authorization"
+
+// publicServices не требуют токена: по ним балансировщики и grpcurl проверяют сервер.
+var publicServices = []string{"/grpc.health.", "/grpc.reflection."}
+
+func (s *Server) authUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize кладёт в контекст организацию токена из метаданных authorization: Bearer.
+// Токен администратора действует в организации default.
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationMetadataKey); len(values) > 0 {
+			token, _ = strings.CutPrefix(values[0], "Bearer ")
+			token = strings.TrimSpace(token)
+		}
+	}
+	if token == "" {
+		return nil, newStatusError(codes.Unauthenticated, "UNAUTHORIZED", "bearer token is required")
+	}
+	if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+		return tenant.WithOrganization(ctx, models.DefaultOrganization), nil
+	}
+	organizationID, err := s.organizations.Authenticate(ctx, token)
+	if err != nil {
+		return nil, domainError(ctx, method, err)
+	}
+	return tenant.WithOrganization(ctx, organizationID), nil
+}
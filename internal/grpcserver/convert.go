@@ -0,0 +1,240 @@
+package grpcserver
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	pb "github.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1"
+)
+
+// ---------- модели → protobuf ----------
+
+func toPBStatus(s string) pb.PullRequestStatus {
+	switch s {
+	case string(models.PullRequestStatusOPEN):
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case string(models.PullRequestStatusMERGED):
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func toPBTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toPBPullRequest(pr *models.PullRequest) *pb.PullRequest {
+	if pr == nil {
+		return nil
+	}
+	return &pb.PullRequest{
+		PullRequestId:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorId,
+		Status:            toPBStatus(string(pr.Status)),
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         toPBTime(pr.CreatedAt),
+		MergedAt:          toPBTime(pr.MergedAt),
+	}
+}
+
+func toPBPullRequestShort(pr models.PullRequestShort) *pb.PullRequestShort {
+	return &pb.PullRequestShort{
+		PullRequestId:   pr.PullRequestId,
+		PullRequestName: pr.PullRequestName,
+		AuthorId:        pr.AuthorId,
+		Status:          toPBStatus(string(pr.Status)),
+	}
+}
+
+func toPBTeam(team *models.Team) *pb.Team {
+	if team == nil {
+		return nil
+	}
+	members := make([]*pb.TeamMember, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, &pb.TeamMember{UserId: m.UserId, Username: m.Username, IsActive: m.IsActive})
+	}
+	return &pb.Team{TeamName: team.TeamName, Members: members}
+}
+
+func toPBUser(user *models.User) *pb.User {
+	if user == nil {
+		return nil
+	}
+	return &pb.User{
+		UserId:   user.UserId,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+}
+
+func toPBDeactivateResult(res *models.TeamBulkDeactivateResult) *pb.DeactivateTeamMembersResponse {
+	out := &pb.DeactivateTeamMembersResponse{TeamName: res.TeamName, Deactivated: res.Deactivated}
+	for _, r := range res.Reassignments {
+		item := &pb.TeamPullRequestReassignment{PullRequestId: r.PullRequestId}
+		for _, rep := range r.Replacements {
+			item.Replacements = append(item.Replacements, &pb.ReviewerReplacement{OldUserId: rep.OldUserId, NewUserId: rep.NewUserId})
+		}
+		out.Reassignments = append(out.Reassignments, item)
+	}
+	return out
+}
+
+func toPBUserStat(s models.UserAssignmentStat) *pb.UserAssignmentStat {
+	return &pb.UserAssignmentStat{UserId: s.UserId, Username: s.Username, Assignments: int32(s.Assignments)}
+}
+
+func toPBPullRequestStat(s models.PullRequestAssignmentStat) *pb.PullRequestAssignmentStat {
+	return &pb.PullRequestAssignmentStat{
+		PullRequestId:   s.PullRequestId,
+		PullRequestName: s.PullRequestName,
+		ReviewerCount:   int32(s.ReviewerCount),
+	}
+}
+
+func toPBTeamStats(stats []models.TeamAssignmentStat) []*pb.TeamAssignmentStat {
+	out := make([]*pb.TeamAssignmentStat, 0, len(stats))
+	for _, s := range stats {
+		out = append(out, &pb.TeamAssignmentStat{
+			TeamName:      s.TeamName,
+			OpenCount:     int32(s.OpenCount),
+			MergedCount:   int32(s.MergedCount),
+			AvgReviewers:  s.AvgReviewers,
+			LoadImbalance: s.LoadImbalance,
+		})
+	}
+	return out
+}
+
+func toPBPercentiles(p models.TurnaroundPercentiles) *pb.TurnaroundPercentiles {
+	return &pb.TurnaroundPercentiles{
+		Count:      int32(p.Count),
+		P50Seconds: p.P50Seconds,
+		P90Seconds: p.P90Seconds,
+		P99Seconds: p.P99Seconds,
+	}
+}
+
+func toPBUserTurnaround(items []models.UserTurnaround) []*pb.UserTurnaround {
+	out := make([]*pb.UserTurnaround, 0, len(items))
+	for _, it := range items {
+		out = append(out, &pb.UserTurnaround{UserId: it.UserId, Percentiles: toPBPercentiles(it.TurnaroundPercentiles)})
+	}
+	return out
+}
+
+func toPBTurnaround(stats *models.TurnaroundStats) *pb.GetTurnaroundStatsResponse {
+	out := &pb.GetTurnaroundStatsResponse{
+		From:       timestamppb.New(stats.From),
+		To:         timestamppb.New(stats.To),
+		Overall:    toPBPercentiles(stats.Overall),
+		ByAuthor:   toPBUserTurnaround(stats.ByAuthor),
+		ByReviewer: toPBUserTurnaround(stats.ByReviewer),
+	}
+	for _, t := range stats.ByTeam {
+		out.ByTeam = append(out.ByTeam, &pb.TeamTurnaround{TeamName: t.TeamName, Percentiles: toPBPercentiles(t.TurnaroundPercentiles)})
+	}
+	return out
+}
+
+func toPBImportReport(report *models.ImportReport) *pb.ImportReport {
+	out := &pb.ImportReport{
+		DryRun:       report.DryRun,
+		Applied:      report.Applied,
+		OnConflict:   report.OnConflict,
+		TeamsCreated: report.TeamsCreated,
+		Created:      int32(report.Created),
+		Updated:      int32(report.Updated),
+		Skipped:      int32(report.Skipped),
+		Failed:       int32(report.Failed),
+	}
+	for _, row := range report.Rows {
+		out.Rows = append(out.Rows, &pb.ImportRowResult{
+			Row:    int32(row.Row),
+			UserId: row.UserId,
+			Action: row.Action,
+			Error:  row.Error,
+		})
+	}
+	return out
+}
+
+// ---------- protobuf → модели ----------
+
+// fromPBStatus возвращает пустой статус для UNSPECIFIED, то есть «без фильтра».
+func fromPBStatus(s pb.PullRequestStatus) (models.PullRequestStatus, bool) {
+	switch s {
+	case pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED:
+		return "", true
+	case pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN:
+		return models.PullRequestStatusOPEN, true
+	case pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED:
+		return models.PullRequestStatusMERGED, true
+	default:
+		return "", false
+	}
+}
+
+func fromPBTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// fromPBFilter проверяет и переводит фильтр статистики; ошибка уже оформлена статусом gRPC.
+func fromPBFilter(f *pb.AssignmentStatsFilter) (models.AssignmentStatsFilter, error) {
+	if f == nil {
+		return models.AssignmentStatsFilter{}, nil
+	}
+	st, ok := fromPBStatus(f.GetStatus())
+	if !ok {
+		return models.AssignmentStatsFilter{}, invalidParam("status must be OPEN or MERGED")
+	}
+	if f.GetLimit() < 0 {
+		return models.AssignmentStatsFilter{}, invalidParam("limit must be a non-negative integer")
+	}
+	return models.AssignmentStatsFilter{
+		TeamName: f.GetTeam(),
+		From:     fromPBTime(f.GetFrom()),
+		To:       fromPBTime(f.GetTo()),
+		Status:   st,
+		Limit:    int(f.GetLimit()),
+	}, nil
+}
+
+func fromPBTeam(team *pb.Team) models.Team {
+	out := models.Team{TeamName: team.GetTeamName(), Members: make([]models.TeamMember, 0, len(team.GetMembers()))}
+	for _, m := range team.GetMembers() {
+		out.Members = append(out.Members, models.TeamMember{UserId: m.GetUserId(), Username: m.GetUsername(), IsActive: m.GetIsActive()})
+	}
+	return out
+}
+
+// fromPBImportRows нумерует строки с единицы, как JSON-импорт HTTP API; is_active по умолчанию true.
+func fromPBImportRows(rows []*pb.ImportUserRow) []models.ImportUserRow {
+	out := make([]models.ImportUserRow, 0, len(rows))
+	for i, r := range rows {
+		isActive := true
+		if r.IsActive != nil {
+			isActive = r.GetIsActive()
+		}
+		out = append(out, models.ImportUserRow{
+			Row:      i + 1,
+			UserId:   strings.TrimSpace(r.GetUserId()),
+			Username: strings.TrimSpace(r.GetUsername()),
+			TeamName: strings.TrimSpace(r.GetTeamName()),
+			IsActive: isActive,
+		})
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+)
+
+// errorDomain попадает в google.rpc.ErrorInfo.domain всех ошибок сервиса.
+const errorDomain = "pr-manager"
+
+// mapDomainError переводит доменные ошибки в коды gRPC и коды ошибок HTTP API.
+// Соответствие повторяет web.mapDomainError: клиенты обоих API видят одинаковые коды в ErrorInfo.reason.
+func mapDomainError(err error) (code codes.Code, reason string) {
+	switch {
+	case errors.Is(err, domain.ErrTeamExists):
+		return codes.AlreadyExists, "TEAM_EXISTS"
+	case errors.Is(err, domain.ErrPRExists):
+		return codes.AlreadyExists, "PR_EXISTS"
+	case errors.Is(err, domain.ErrPRMerged):
+		return codes.FailedPrecondition, "PR_MERGED"
+	case errors.Is(err, domain.ErrNotAssigned):
+		return codes.FailedPrecondition, "NOT_ASSIGNED"
+	case errors.Is(err, domain.ErrNoCandidate):
+		return codes.FailedPrecondition, "NO_CANDIDATE"
+	case errors.Is(err, domain.ErrNotFound):
+		return codes.NotFound, "NOT_FOUND"
+	case errors.Is(err, domain.ErrUnauthorized):
+		return codes.Unauthenticated, "UNAUTHORIZED"
+	case errors.Is(err, domain.ErrForbidden):
+		return codes.PermissionDenied, "FORBIDDEN"
+	case errors.Is(err, domain.ErrInvalidParam):
+		return codes.InvalidArgument, "INVALID_PARAM"
+	case errors.Is(err, domain.ErrNotEmpty):
+		return codes.FailedPrecondition, "NOT_EMPTY"
+	case errors.Is(err, domain.ErrOrgExists):
+		return codes.AlreadyExists, "ORG_EXISTS"
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled, "INTERNAL_ERROR"
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded, "INTERNAL_ERROR"
+	default:
+		return codes.Internal, "INTERNAL_ERROR"
+	}
+}
+
+// newStatusError собирает статус gRPC с ErrorInfo, в котором reason — код ошибки HTTP API.
+func newStatusError(code codes.Code, reason, msg string) error {
+	st := status.New(code, msg)
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
+
+// domainError переводит ошибку сервиса в статус gRPC и единожды логирует её вместе с кодом.
+func domainError(ctx context.Context, method string, err error) error {
+	code, reason := mapDomainError(err)
+	level := slog.LevelWarn
+	if code == codes.Internal {
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, "rpc failed",
+		"method", method,
+		"code", code.String(),
+		"reason", reason,
+		"err", err.Error(),
+	)
+	return newStatusError(code, reason, err.Error())
+}
+
+// missingParam сообщает об отсутствующем обязательном поле запроса.
+func missingParam(msg string) error {
+	return newStatusError(codes.InvalidArgument, "MISSING_PARAM", msg)
+}
+
+// invalidParam сообщает о недопустимом значении поля запроса.
+func invalidParam(msg string) error {
+	return newStatusError(codes.InvalidArgument, "INVALID_PARAM", msg)
+}
@@ -0,0 +1,36 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// PullRequestService описывает операции над Pull Request, которые нужны gRPC-слою.
+type PullRequestService interface {
+	CreatePullRequest(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error)
+	Merge(ctx context.Context, payload models.PostPullRequestMergeJSONBody) (*models.PullRequest, error)
+	Reassign(ctx context.Context, oldUsId, prId string) (*domain.ReassignResponse, error)
+	ListForReviewer(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error)
+	ExportReviewerPullRequests(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error
+	AssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error)
+	TeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error)
+	ExportUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error
+	ExportPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error
+	TurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error)
+	BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.TeamBulkDeactivateResult, error)
+}
+
+// UserTeamService объединяет операции с командами и пользователями.
+type UserTeamService interface {
+	AddTeam(ctx context.Context, team models.Team) error
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	SetUserActivity(ctx context.Context, userID string, isActive bool) (*models.User, error)
+	ImportUsers(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error)
+}
+
+// Authenticator определяет организацию по токену доступа.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
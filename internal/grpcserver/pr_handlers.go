@@ -0,0 +1,201 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	pb "github.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1"
+)
+
+// pullRequestServer реализует pb.PullRequestServiceServer поверх PullRequestService.
+type pullRequestServer struct {
+	pb.UnimplementedPullRequestServiceServer
+	svc PullRequestService
+}
+
+// CreatePullRequest создаёт PR и назначает ревьюверов.
+func (s *pullRequestServer) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.CreatePullRequestResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetPullRequestName() == "" || req.GetAuthorId() == "" {
+		return nil, missingParam("pull_request_id, pull_request_name and author_id are required")
+	}
+
+	resp, err := s.svc.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{
+		PullRequestId:   req.GetPullRequestId(),
+		PullRequestName: req.GetPullRequestName(),
+		AuthorId:        req.GetAuthorId(),
+	})
+	if err != nil {
+		return nil, domainError(ctx, "CreatePullRequest", err)
+	}
+	return &pb.CreatePullRequestResponse{Pr: toPBPullRequest(resp.PR)}, nil
+}
+
+// MergePullRequest помечает PR как слитый.
+func (s *pullRequestServer) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.MergePullRequestResponse, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, missingParam("pull_request_id is required")
+	}
+
+	pr, err := s.svc.Merge(ctx, models.PostPullRequestMergeJSONBody{PullRequestId: req.GetPullRequestId()})
+	if err != nil {
+		return nil, domainError(ctx, "MergePullRequest", err)
+	}
+	return &pb.MergePullRequestResponse{Pr: toPBPullRequest(pr)}, nil
+}
+
+// ReassignReviewer заменяет ревьювера PR.
+func (s *pullRequestServer) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetOldUserId() == "" {
+		return nil, missingParam("pull_request_id and old_user_id are required")
+	}
+
+	res, err := s.svc.Reassign(ctx, req.GetOldUserId(), req.GetPullRequestId())
+	if err != nil {
+		return nil, domainError(ctx, "ReassignReviewer", err)
+	}
+	return &pb.ReassignReviewerResponse{Pr: toPBPullRequest(res.PR), ReplacedBy: res.ReplacedBy}, nil
+}
+
+// ListReviewerPullRequests возвращает PR, назначенные ревьюверу.
+func (s *pullRequestServer) ListReviewerPullRequests(ctx context.Context, req *pb.ListReviewerPullRequestsRequest) (*pb.ListReviewerPullRequestsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, missingParam("user_id is required")
+	}
+
+	prs, err := s.svc.ListForReviewer(ctx, req.GetUserId(), "")
+	if err != nil {
+		return nil, domainError(ctx, "ListReviewerPullRequests", err)
+	}
+	out := &pb.ListReviewerPullRequestsResponse{UserId: req.GetUserId()}
+	for _, pr := range prs {
+		out.PullRequests = append(out.PullRequests, toPBPullRequestShort(pr))
+	}
+	return out, nil
+}
+
+// StreamReviewerPullRequests отдаёт PR ревьювера по мере чтения из хранилища.
+func (s *pullRequestServer) StreamReviewerPullRequests(req *pb.StreamReviewerPullRequestsRequest, stream grpc.ServerStreamingServer[pb.StreamReviewerPullRequestsResponse]) error {
+	if req.GetUserId() == "" {
+		return missingParam("user_id is required")
+	}
+
+	ctx := stream.Context()
+	err := s.svc.ExportReviewerPullRequests(ctx, req.GetUserId(), "", func(pr models.PullRequestShort) error {
+		return stream.Send(&pb.StreamReviewerPullRequestsResponse{PullRequest: toPBPullRequestShort(pr)})
+	})
+	if err != nil {
+		return domainError(ctx, "StreamReviewerPullRequests", err)
+	}
+	return nil
+}
+
+// DeactivateTeamMembers массово деактивирует участников команды.
+func (s *pullRequestServer) DeactivateTeamMembers(ctx context.Context, req *pb.DeactivateTeamMembersRequest) (*pb.DeactivateTeamMembersResponse, error) {
+	teamName := strings.TrimSpace(req.GetTeamName())
+	if teamName == "" {
+		return nil, missingParam("team_name is required")
+	}
+	userIDs := make([]string, 0, len(req.GetUserIds()))
+	for _, raw := range req.GetUserIds() {
+		if id := strings.TrimSpace(raw); id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, missingParam("at least one user_id is required")
+	}
+
+	res, err := s.svc.BulkDeactivateTeamMembers(ctx, teamName, userIDs)
+	if err != nil {
+		return nil, domainError(ctx, "DeactivateTeamMembers", err)
+	}
+	return toPBDeactivateResult(res), nil
+}
+
+// GetAssignmentStats возвращает статистику назначений по всем срезам.
+func (s *pullRequestServer) GetAssignmentStats(ctx context.Context, req *pb.GetAssignmentStatsRequest) (*pb.GetAssignmentStatsResponse, error) {
+	filter, err := fromPBFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.svc.AssignmentStats(ctx, filter)
+	if err != nil {
+		return nil, domainError(ctx, "GetAssignmentStats", err)
+	}
+	out := &pb.GetAssignmentStatsResponse{}
+	if stats == nil {
+		return out, nil
+	}
+	for _, u := range stats.ByUser {
+		out.ByUser = append(out.ByUser, toPBUserStat(u))
+	}
+	for _, pr := range stats.ByPullRequest {
+		out.ByPullRequest = append(out.ByPullRequest, toPBPullRequestStat(pr))
+	}
+	out.ByTeam = toPBTeamStats(stats.ByTeam)
+	return out, nil
+}
+
+// ListTeamAssignmentStats возвращает статистику назначений по командам.
+func (s *pullRequestServer) ListTeamAssignmentStats(ctx context.Context, req *pb.ListTeamAssignmentStatsRequest) (*pb.ListTeamAssignmentStatsResponse, error) {
+	filter, err := fromPBFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := s.svc.TeamAssignmentStats(ctx, filter)
+	if err != nil {
+		return nil, domainError(ctx, "ListTeamAssignmentStats", err)
+	}
+	return &pb.ListTeamAssignmentStatsResponse{Teams: toPBTeamStats(teams)}, nil
+}
+
+// StreamUserAssignments отдаёт статистику по ревьюверам потоком.
+func (s *pullRequestServer) StreamUserAssignments(req *pb.StreamUserAssignmentsRequest, stream grpc.ServerStreamingServer[pb.StreamUserAssignmentsResponse]) error {
+	filter, err := fromPBFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	err = s.svc.ExportUserAssignments(ctx, filter, func(stat models.UserAssignmentStat) error {
+		return stream.Send(&pb.StreamUserAssignmentsResponse{Stat: toPBUserStat(stat)})
+	})
+	if err != nil {
+		return domainError(ctx, "StreamUserAssignments", err)
+	}
+	return nil
+}
+
+// StreamPullRequestAssignments отдаёт статистику по PR потоком.
+func (s *pullRequestServer) StreamPullRequestAssignments(req *pb.StreamPullRequestAssignmentsRequest, stream grpc.ServerStreamingServer[pb.StreamPullRequestAssignmentsResponse]) error {
+	filter, err := fromPBFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	err = s.svc.ExportPullRequestAssignments(ctx, filter, func(stat models.PullRequestAssignmentStat) error {
+		return stream.Send(&pb.StreamPullRequestAssignmentsResponse{Stat: toPBPullRequestStat(stat)})
+	})
+	if err != nil {
+		return domainError(ctx, "StreamPullRequestAssignments", err)
+	}
+	return nil
+}
+
+// GetTurnaroundStats возвращает перцентили времени до слияния PR.
+func (s *pullRequestServer) GetTurnaroundStats(ctx context.Context, req *pb.GetTurnaroundStatsRequest) (*pb.GetTurnaroundStatsResponse, error) {
+	stats, err := s.svc.TurnaroundStats(ctx, models.TurnaroundFilter{
+		From: fromPBTime(req.GetFrom()),
+		To:   fromPBTime(req.GetTo()),
+	})
+	if err != nil {
+		return nil, domainError(ctx, "GetTurnaroundStats", err)
+	}
+	return toPBTurnaround(stats), nil
+}
@@ -0,0 +1,171 @@
+// Package grpcserver отдаёт операции PullRequestService и UserTeamService по gRPC
+// поверх того же сервисного слоя, что и HTTP API.
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/logging"
+	pb "github.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1"
+)
+
+// requestIDMetadataKey — ключ метаданных с request ID; совпадает с HTTP-заголовком X-Request-ID.
+const requestIDMetadataKey = "x-request-id"
+
+type Server struct {
+	Address string
+	server  *grpc.Server
+	health  *health.Server
+
+	organizations Authenticator
+	adminToken    string
+}
+
+// Option настраивает необязательные возможности сервера.
+type Option func(*Server)
+
+// WithOrganizations требует токен организации в метаданных authorization у всех вызовов pr-manager;
+// adminToken действует в организации default.
+func WithOrganizations(auth Authenticator, adminToken string) Option {
+	return func(s *Server) {
+		s.organizations = auth
+		s.adminToken = adminToken
+	}
+}
+
+// New конструирует gRPC-сервер и регистрирует сервисы pr-manager, health и reflection.
+func New(cfg conf.GRPCServConf, pr PullRequestService, user UserTeamService, opts ...Option) *Server {
+	srv := &Server{
+		Address: cfg.GetAddress(),
+		health:  health.NewServer(),
+	}
+	for _, opt := range opts {
+		opt(srv)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{unaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{streamInterceptor}
+	if srv.organizations != nil {
+		unary = append(unary, srv.authUnary)
+		stream = append(stream, srv.authStream)
+	}
+	srv.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+
+	pb.RegisterPullRequestServiceServer(srv.server, &pullRequestServer{svc: pr})
+	pb.RegisterUserTeamServiceServer(srv.server, &userTeamServer{svc: user})
+	healthpb.RegisterHealthServer(srv.server, srv.health)
+	reflection.Register(srv.server)
+
+	return srv
+}
+
+// Start слушает Address и блокирует поток до остановки.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.Address)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", s.Address, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve обслуживает соединения готового listener до остановки сервера.
+func (s *Server) Serve(lis net.Listener) error {
+	slog.Info("grpc server starting", "address", lis.Addr().String())
+	return s.server.Serve(lis)
+}
+
+// Shutdown дожидается завершения активных вызовов, а по истечении таймаута обрывает их.
+func (s *Server) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	s.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// ---------- перехватчики ----------
+
+// withRequestID берёт корректный x-request-id клиента или генерирует новый и возвращает его в заголовке ответа.
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	id = logging.NormalizeRequestID(id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+	return logging.WithRequestID(ctx, id)
+}
+
+// recoverPanic превращает панику обработчика в ошибку Internal.
+func recoverPanic(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		slog.ErrorContext(ctx, "rpc panic", "method", method, "panic", r, "stack", string(debug.Stack()))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// logCall пишет одну строку журнала на вызов.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	slog.InfoContext(ctx, "grpc request",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+}
+
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	ctx = withRequestID(ctx)
+	start := time.Now()
+	defer func() { logCall(ctx, info.FullMethod, start, err) }()
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := withRequestID(ss.Context())
+	start := time.Now()
+	defer func() { logCall(ctx, info.FullMethod, start, err) }()
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream подменяет контекст потока, чтобы обработчик видел request ID.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,271 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+	pb "github.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1"
+)
+
+type testClients struct {
+	pr     pb.PullRequestServiceClient
+	team   pb.UserTeamServiceClient
+	health healthpb.HealthClient
+}
+
+// newTestClients поднимает сервер поверх in-memory хранилища и настоящих сервисов на bufconn.
+func newTestClients(t *testing.T) testClients {
+	t.Helper()
+	return newTestClientsOn(t, memory.NewStorage())
+}
+
+// newTestClientsOn поднимает сервер поверх переданного хранилища с опциями opts.
+func newTestClientsOn(t *testing.T, storage *memory.Storage, opts ...Option) testClients {
+	t.Helper()
+
+	users := service.NewUserManager(storage)
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	srv := New(conf.GRPCServConf{Host: "127.0.0.1", Port: "0"}, prs, users, opts...)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return testClients{
+		pr:     pb.NewPullRequestServiceClient(conn),
+		team:   pb.NewUserTeamServiceClient(conn),
+		health: healthpb.NewHealthClient(conn),
+	}
+}
+
+// requireStatus проверяет код gRPC и reason из ErrorInfo.
+func requireStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	require.NotNil(t, info, "ErrorInfo detail is missing")
+	require.Equal(t, reason, info.GetReason())
+	require.Equal(t, errorDomain, info.GetDomain())
+}
+
+func addBackend(t *testing.T, c testClients) {
+	t.Helper()
+	_, err := c.team.AddTeam(context.Background(), &pb.AddTeamRequest{Team: &pb.Team{
+		TeamName: "backend",
+		Members: []*pb.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: true},
+			{UserId: "u2", Username: "Bob", IsActive: true},
+			{UserId: "u3", Username: "Carol", IsActive: true},
+		},
+	}})
+	require.NoError(t, err)
+}
+
+func TestPullRequestLifecycle(t *testing.T) {
+	c := newTestClients(t)
+	ctx := context.Background()
+	addBackend(t, c)
+
+	team, err := c.team.GetTeam(ctx, &pb.GetTeamRequest{TeamName: "backend"})
+	require.NoError(t, err)
+	require.Len(t, team.GetTeam().GetMembers(), 3)
+
+	created, err := c.pr.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{
+		PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, created.GetPr().GetStatus())
+	require.NotEmpty(t, created.GetPr().GetAssignedReviewers())
+	require.NotNil(t, created.GetPr().GetCreatedAt())
+
+	reviewer := created.GetPr().GetAssignedReviewers()[0]
+	stream, err := c.pr.StreamReviewerPullRequests(ctx, &pb.StreamReviewerPullRequestsRequest{UserId: reviewer})
+	require.NoError(t, err)
+	var streamed []string
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		streamed = append(streamed, msg.GetPullRequest().GetPullRequestId())
+	}
+	require.Equal(t, []string{"pr-1"}, streamed)
+
+	merged, err := c.pr.MergePullRequest(ctx, &pb.MergePullRequestRequest{PullRequestId: "pr-1"})
+	require.NoError(t, err)
+	require.Equal(t, pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED, merged.GetPr().GetStatus())
+	require.NotNil(t, merged.GetPr().GetMergedAt())
+
+	_, err = c.pr.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{PullRequestId: "pr-1", OldUserId: reviewer})
+	requireStatus(t, err, codes.FailedPrecondition, "PR_MERGED")
+
+	stats, err := c.pr.ListTeamAssignmentStats(ctx, &pb.ListTeamAssignmentStatsRequest{})
+	require.NoError(t, err)
+	require.Len(t, stats.GetTeams(), 1)
+	require.EqualValues(t, 1, stats.GetTeams()[0].GetMergedCount())
+}
+
+func TestErrorsCarryErrorInfo(t *testing.T) {
+	c := newTestClients(t)
+	ctx := context.Background()
+	addBackend(t, c)
+
+	_, err := c.team.GetTeam(ctx, &pb.GetTeamRequest{TeamName: "ghost"})
+	requireStatus(t, err, codes.NotFound, "NOT_FOUND")
+
+	_, err = c.team.AddTeam(ctx, &pb.AddTeamRequest{Team: &pb.Team{TeamName: "backend"}})
+	requireStatus(t, err, codes.AlreadyExists, "TEAM_EXISTS")
+
+	_, err = c.pr.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{PullRequestId: "pr-1"})
+	requireStatus(t, err, codes.InvalidArgument, "MISSING_PARAM")
+
+	_, err = c.pr.GetAssignmentStats(ctx, &pb.GetAssignmentStatsRequest{Filter: &pb.AssignmentStatsFilter{Limit: -1}})
+	requireStatus(t, err, codes.InvalidArgument, "INVALID_PARAM")
+
+	stream, err := c.pr.StreamReviewerPullRequests(ctx, &pb.StreamReviewerPullRequestsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	requireStatus(t, err, codes.InvalidArgument, "MISSING_PARAM")
+}
+
+func TestImportUsersReportsFailedRows(t *testing.T) {
+	c := newTestClients(t)
+	ctx := context.Background()
+
+	active := false
+	resp, err := c.team.ImportUsers(ctx, &pb.ImportUsersRequest{Rows: []*pb.ImportUserRow{
+		{UserId: "u1", Username: "Alice", TeamName: "backend"},
+		{UserId: "u2", Username: "Bob", TeamName: "backend", IsActive: &active},
+	}})
+	require.NoError(t, err)
+	require.True(t, resp.GetReport().GetApplied())
+	require.EqualValues(t, 2, resp.GetReport().GetCreated())
+
+	team, err := c.team.GetTeam(ctx, &pb.GetTeamRequest{TeamName: "backend"})
+	require.NoError(t, err)
+	activity := map[string]bool{}
+	for _, m := range team.GetTeam().GetMembers() {
+		activity[m.GetUserId()] = m.GetIsActive()
+	}
+	require.Equal(t, map[string]bool{"u1": true, "u2": false}, activity)
+
+	resp, err = c.team.ImportUsers(ctx, &pb.ImportUsersRequest{Rows: []*pb.ImportUserRow{
+		{UserId: "u3", TeamName: "backend"},
+	}})
+	require.NoError(t, err)
+	require.False(t, resp.GetReport().GetApplied())
+	require.EqualValues(t, 1, resp.GetReport().GetFailed())
+}
+
+func TestRequestIDAndHealth(t *testing.T) {
+	c := newTestClients(t)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDMetadataKey, "req-42")
+	_, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"req-42"}, header.Get(requestIDMetadataKey))
+
+	_, err = c.health.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Len(t, header.Get(requestIDMetadataKey), 1)
+	require.NotEmpty(t, header.Get(requestIDMetadataKey)[0])
+}
+
+func TestOrganizationTokensIsolateData(t *testing.T) {
+	storage := memory.NewStorage()
+	orgs := service.NewOrganizationManager(storage)
+	c := newTestClientsOn(t, storage, WithOrganizations(orgs, "admin-secret"))
+
+	_, token, err := orgs.CreateOrganization(context.Background(), models.PostOrganizationCreateJSONBody{OrganizationId: "acme"})
+	require.NoError(t, err)
+	admin := metadata.AppendToOutgoingContext(context.Background(), authorizationMetadataKey, "Bearer admin-secret")
+	acme := metadata.AppendToOutgoingContext(context.Background(), authorizationMetadataKey, "Bearer "+token)
+
+	_, err = c.team.GetTeam(context.Background(), &pb.GetTeamRequest{TeamName: "backend"})
+	requireStatus(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+	_, err = c.team.GetTeam(metadata.AppendToOutgoingContext(context.Background(), authorizationMetadataKey, "Bearer nope"),
+		&pb.GetTeamRequest{TeamName: "backend"})
+	requireStatus(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+
+	_, err = c.health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err, "health must stay public")
+
+	_, err = c.team.AddTeam(admin, &pb.AddTeamRequest{Team: &pb.Team{
+		TeamName: "backend",
+		Members:  []*pb.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+	}})
+	require.NoError(t, err)
+
+	_, err = c.team.GetTeam(acme, &pb.GetTeamRequest{TeamName: "backend"})
+	requireStatus(t, err, codes.NotFound, "NOT_FOUND")
+
+	stream, err := c.pr.StreamReviewerPullRequests(acme, &pb.StreamReviewerPullRequestsRequest{UserId: "u1"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = c.team.GetTeam(admin, &pb.GetTeamRequest{TeamName: "backend"})
+	require.NoError(t, err)
+}
+
+func TestMapDomainError(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{domain.ErrTeamExists, codes.AlreadyExists, "TEAM_EXISTS"},
+		{domain.ErrPRExists, codes.AlreadyExists, "PR_EXISTS"},
+		{domain.ErrPRMerged, codes.FailedPrecondition, "PR_MERGED"},
+		{domain.ErrNotAssigned, codes.FailedPrecondition, "NOT_ASSIGNED"},
+		{domain.ErrNoCandidate, codes.FailedPrecondition, "NO_CANDIDATE"},
+		{domain.NewNotFoundError("team"), codes.NotFound, "NOT_FOUND"},
+		{domain.ErrUnauthorized, codes.Unauthenticated, "UNAUTHORIZED"},
+		{domain.ErrForbidden, codes.PermissionDenied, "FORBIDDEN"},
+		{domain.ErrInvalidParam, codes.InvalidArgument, "INVALID_PARAM"},
+		{domain.ErrNotEmpty, codes.FailedPrecondition, "NOT_EMPTY"},
+		{domain.ErrOrgExists, codes.AlreadyExists, "ORG_EXISTS"},
+		{context.DeadlineExceeded, codes.DeadlineExceeded, "INTERNAL_ERROR"},
+		{errors.New("boom"), codes.Internal, "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		code, reason := mapDomainError(tt.err)
+		require.Equal(t, tt.code, code, tt.err.Error())
+		require.Equal(t, tt.reason, reason, tt.err.Error())
+	}
+}
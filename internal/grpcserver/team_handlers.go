@@ -0,0 +1,66 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	pb "github.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1"
+)
+
+// userTeamServer реализует pb.UserTeamServiceServer поверх UserTeamService.
+type userTeamServer struct {
+	pb.UnimplementedUserTeamServiceServer
+	svc UserTeamService
+}
+
+// AddTeam создаёт команду и сохраняет участников.
+func (s *userTeamServer) AddTeam(ctx context.Context, req *pb.AddTeamRequest) (*pb.AddTeamResponse, error) {
+	if req.GetTeam().GetTeamName() == "" {
+		return nil, missingParam("team.team_name is required")
+	}
+
+	team := fromPBTeam(req.GetTeam())
+	if err := s.svc.AddTeam(ctx, team); err != nil {
+		return nil, domainError(ctx, "AddTeam", err)
+	}
+	return &pb.AddTeamResponse{Team: toPBTeam(&team)}, nil
+}
+
+// GetTeam возвращает команду по имени.
+func (s *userTeamServer) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, missingParam("team_name is required")
+	}
+
+	team, err := s.svc.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, domainError(ctx, "GetTeam", err)
+	}
+	return &pb.GetTeamResponse{Team: toPBTeam(team)}, nil
+}
+
+// SetUserActive меняет признак активности пользователя.
+func (s *userTeamServer) SetUserActive(ctx context.Context, req *pb.SetUserActiveRequest) (*pb.SetUserActiveResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, missingParam("user_id is required")
+	}
+
+	user, err := s.svc.SetUserActivity(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, domainError(ctx, "SetUserActive", err)
+	}
+	return &pb.SetUserActiveResponse{User: toPBUser(user)}, nil
+}
+
+// ImportUsers импортирует пользователей и команды. Ошибочные строки не дают статус ошибки:
+// отчёт возвращается с failed > 0 и applied = false, как ответ 422 HTTP API.
+func (s *userTeamServer) ImportUsers(ctx context.Context, req *pb.ImportUsersRequest) (*pb.ImportUsersResponse, error) {
+	report, err := s.svc.ImportUsers(ctx, fromPBImportRows(req.GetRows()), models.ImportOptions{
+		DryRun:     req.GetDryRun(),
+		OnConflict: models.ImportConflictPolicy(req.GetOnConflict()),
+	})
+	if err != nil {
+		return nil, domainError(ctx, "ImportUsers", err)
+	}
+	return &pb.ImportUsersResponse{Report: toPBImportReport(report)}, nil
+}
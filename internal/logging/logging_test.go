@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+)
+
+func TestNewAddsRequestIDAndRespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(conf.LogConf{Format: conf.LogFormatJSON, Level: "warn"}, &buf)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "dropped")
+	logger.WarnContext(ctx, "kept", "code", "NOT_FOUND")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+	require.Equal(t, "kept", entry["msg"])
+	require.Equal(t, "req-1", entry["request_id"])
+	require.Equal(t, "NOT_FOUND", entry["code"])
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	_, err := New(conf.LogConf{Format: "xml"}, &bytes.Buffer{})
+	require.Error(t, err)
+	_, err = New(conf.LogConf{Level: "loud"}, &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	h := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	t.Run("keeps valid client id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "client-42")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, "client-42", seen)
+		require.Equal(t, "client-42", rr.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("replaces missing or unsafe id", func(t *testing.T) {
+		for _, incoming := range []string{"", "bad id\nwith newline"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, incoming)
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			require.Len(t, seen, 32)
+			require.NotEqual(t, incoming, seen)
+			require.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+		}
+	})
+}
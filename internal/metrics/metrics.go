@@ -0,0 +1,250 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате Prometheus.
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "prmanager"
+
+// collectTimeout ограничивает запросы к хранилищу во время одного scrape.
+const collectTimeout = 5 * time.Second
+
+// unmatchedRoute подставляется вместо шаблона маршрута для запросов, не попавших ни в один маршрут.
+const unmatchedRoute = "unmatched"
+
+// OpenReviewsFunc возвращает число открытых ревью на каждого пользователя каждой организации:
+// организация → пользователь → число.
+type OpenReviewsFunc func(ctx context.Context) (map[string]map[string]int, error)
+
+// Metrics хранит собственный реестр и все метрики сервиса.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	prsCreated    prometheus.Counter
+	prsMerged     prometheus.Counter
+	reassignments prometheus.Counter
+	bulkSwaps     prometheus.Counter
+	noCandidate   *prometheus.CounterVec
+}
+
+// New создаёт реестр с метриками HTTP, бизнес-счётчиками и стандартными метриками процесса.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		prsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Pull requests created.",
+		}),
+		prsMerged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Pull requests merged.",
+		}),
+		reassignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Successful single reviewer reassignments.",
+		}),
+		bulkSwaps: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bulk_reviewer_swaps_total",
+			Help:      "Reviewer swaps applied by bulk team deactivation.",
+		}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_total",
+			Help:      "NO_CANDIDATE outcomes by operation.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.prsCreated,
+		m.prsMerged,
+		m.reassignments,
+		m.bulkSwaps,
+		m.noCandidate,
+	)
+	return m
+}
+
+// Handler отдаёт метрики реестра; ошибка одного коллектора не ломает весь ответ.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Middleware считает запросы и их длительность по шаблону маршрута chi, а не по сырому пути.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+		m.httpRequests.With(labels).Inc()
+		m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// PullRequestCreated учитывает созданный PR.
+func (m *Metrics) PullRequestCreated() { m.prsCreated.Inc() }
+
+// PullRequestMerged учитывает слитый PR.
+func (m *Metrics) PullRequestMerged() { m.prsMerged.Inc() }
+
+// ReviewerReassigned учитывает успешную замену ревьюера.
+func (m *Metrics) ReviewerReassigned() { m.reassignments.Inc() }
+
+// BulkReviewerSwaps учитывает замены, применённые массовой деактивацией.
+func (m *Metrics) BulkReviewerSwaps(n int) { m.bulkSwaps.Add(float64(n)) }
+
+// NoCandidate учитывает операцию, завершившуюся NO_CANDIDATE.
+func (m *Metrics) NoCandidate(operation string) { m.noCandidate.WithLabelValues(operation).Inc() }
+
+// RegisterOpenReviews добавляет gauge открытых ревью на пользователя, вычисляемый при каждом scrape.
+func (m *Metrics) RegisterOpenReviews(fn OpenReviewsFunc) {
+	m.registry.MustRegister(&openReviewsCollector{
+		fn: fn,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_reviews"),
+			"Open pull requests assigned to the reviewer.",
+			[]string{"organization", "user_id"}, nil,
+		),
+	})
+}
+
+// RegisterCacheSize добавляет gauge размера кэша пользователей.
+func (m *Metrics) RegisterCacheSize(fn func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "user_cache_size",
+		Help:      "Users held in the in-memory cache.",
+	}, func() float64 { return float64(fn()) }))
+}
+
+// RegisterDBPool добавляет метрики пула соединений из pgxpool.Stat.
+func (m *Metrics) RegisterDBPool(fn func() *pgxpool.Stat) {
+	m.registry.MustRegister(newDBPoolCollector(fn))
+}
+
+// openReviewsCollector запрашивает нагрузку ревьюеров у сервиса во время scrape.
+type openReviewsCollector struct {
+	fn   OpenReviewsFunc
+	desc *prometheus.Desc
+}
+
+func (c *openReviewsCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c *openReviewsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	load, err := c.fn(ctx)
+	if err != nil {
+		slog.Warn("collect open reviews", "err", err.Error())
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for organizationID, users := range load {
+		for userID, n := range users {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), organizationID, userID)
+		}
+	}
+}
+
+// dbPoolCollector переводит снимок pgxpool.Stat в метрики.
+type dbPoolCollector struct {
+	fn func() *pgxpool.Stat
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+func newDBPoolCollector(fn func() *pgxpool.Stat) *dbPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &dbPoolCollector{
+		fn:              fn,
+		acquiredConns:   desc("acquired_conns", "Connections currently acquired."),
+		idleConns:       desc("idle_conns", "Idle connections."),
+		totalConns:      desc("total_conns", "Total connections in the pool."),
+		maxConns:        desc("max_conns", "Maximum pool size."),
+		acquireCount:    desc("acquire_total", "Successful connection acquires."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent waiting for a connection."),
+		emptyAcquire:    desc("empty_acquire_total", "Acquires that had to wait for a connection."),
+		canceledAcquire: desc("canceled_acquire_total", "Acquires canceled by context."),
+	}
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.fn()
+	if stat == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
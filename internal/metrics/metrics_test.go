@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMiddlewareLabelsByRoutePattern(t *testing.T) {
+	m := New()
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	for _, path := range []string{"/team/a", "/team/b", "/ok", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/team/{name}", "418")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/ok", "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")))
+	require.Equal(t, 3, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestBusinessCounters(t *testing.T) {
+	m := New()
+	m.PullRequestCreated()
+	m.PullRequestCreated()
+	m.PullRequestMerged()
+	m.ReviewerReassigned()
+	m.BulkReviewerSwaps(3)
+	m.NoCandidate("reassign")
+
+	body := scrape(t, m)
+	require.Contains(t, body, "prmanager_pull_requests_created_total 2")
+	require.Contains(t, body, "prmanager_pull_requests_merged_total 1")
+	require.Contains(t, body, "prmanager_reviewer_reassignments_total 1")
+	require.Contains(t, body, "prmanager_bulk_reviewer_swaps_total 3")
+	require.Contains(t, body, `prmanager_no_candidate_total{operation="reassign"} 1`)
+}
+
+func TestRegisteredGauges(t *testing.T) {
+	m := New()
+	m.RegisterOpenReviews(func(context.Context) (map[string]map[string]int, error) {
+		return map[string]map[string]int{"default": {"u1": 2, "u2": 0}, "payments": {"u1": 5}}, nil
+	})
+	m.RegisterCacheSize(func() int { return 7 })
+	m.RegisterDBPool(func() *pgxpool.Stat { return nil })
+
+	body := scrape(t, m)
+	require.Contains(t, body, `prmanager_open_reviews{organization="default",user_id="u1"} 2`)
+	require.Contains(t, body, `prmanager_open_reviews{organization="default",user_id="u2"} 0`)
+	require.Contains(t, body, `prmanager_open_reviews{organization="payments",user_id="u1"} 5`, "the same user id is counted per organization")
+	require.Contains(t, body, "prmanager_user_cache_size 7")
+	require.NotContains(t, body, "prmanager_db_pool", "nil stat must be skipped")
+}
+
+func TestOpenReviewsErrorDoesNotBreakScrape(t *testing.T) {
+	m := New()
+	m.RegisterOpenReviews(func(context.Context) (map[string]map[string]int, error) {
+		return nil, errors.New("db down")
+	})
+	m.PullRequestCreated()
+
+	body := scrape(t, m)
+	require.Contains(t, body, "prmanager_pull_requests_created_total 1")
+	require.NotContains(t, body, "prmanager_open_reviews{")
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/migrations"
+)
+
+func TestLoadSortsAndPairsScripts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_index.up.sql":   {Data: []byte("CREATE INDEX i ON t(c);")},
+		"000002_add_index.down.sql": {Data: []byte("DROP INDEX i;")},
+		"000001_init.up.sql":        {Data: []byte("CREATE TABLE t (c TEXT);")},
+		"000001_init.down.sql":      {Data: []byte("DROP TABLE t;")},
+		"README.md":                 {Data: []byte("ignored")},
+	}
+
+	ms, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	require.Equal(t, int64(1), ms[0].Version)
+	require.Equal(t, "init", ms[0].Name)
+	require.Equal(t, "DROP TABLE t;", ms[0].Down)
+	require.Equal(t, int64(2), ms[1].Version)
+	require.Equal(t, "CREATE INDEX i ON t(c);", ms[1].Up)
+}
+
+func TestLoadRejectsInvalidSets(t *testing.T) {
+	t.Run("missing up", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{"000001_init.down.sql": {Data: []byte("DROP TABLE t;")}})
+		require.ErrorContains(t, err, "no up script")
+	})
+
+	t.Run("conflicting names", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{
+			"000001_init.up.sql":  {Data: []byte("SELECT 1;")},
+			"000001_other.up.sql": {Data: []byte("SELECT 2;")},
+		})
+		require.ErrorContains(t, err, "conflicting names")
+	})
+}
+
+func TestPendingAndStatus(t *testing.T) {
+	ms := []Migration{{Version: 1, Name: "a"}, {Version: 2, Name: "b"}, {Version: 3, Name: "c"}}
+	appliedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	applied := map[int64]time.Time{1: appliedAt}
+
+	pending := Pending(ms, applied)
+	require.Equal(t, []Migration{{Version: 2, Name: "b"}, {Version: 3, Name: "c"}}, pending)
+
+	status := BuildStatus(ms, applied)
+	require.Len(t, status, 3)
+	require.True(t, status[0].Applied)
+	require.Equal(t, appliedAt, *status[0].AppliedAt)
+	require.False(t, status[1].Applied)
+	require.Nil(t, status[1].AppliedAt)
+
+	m, ok := Find(ms, 2)
+	require.True(t, ok)
+	require.Equal(t, "b", m.Name)
+	_, ok = Find(ms, 42)
+	require.False(t, ok)
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	ms, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, ms)
+	require.Equal(t, int64(1), ms[0].Version)
+	require.NotEmpty(t, ms[0].Down)
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Absence — период отсутствия пользователя (отпуск, болезнь); на это время он не получает ревью.
+type Absence struct {
+	AbsenceId int64     `json:"absence_id"`
+	UserId    string    `json:"user_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	// ReassignedAt — когда открытые ревью пользователя были переданы коллегам; nil, пока период не начался.
+	ReassignedAt *time.Time `json:"reassigned_at,omitempty"`
+}
+
+// PostUsersAddAbsenceJSONBody описывает тело запроса на регистрацию отсутствия.
+type PostUsersAddAbsenceJSONBody struct {
+	UserId   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+// Covers сообщает, приходится ли момент at на период отсутствия [StartsAt, EndsAt).
+func (a Absence) Covers(at time.Time) bool {
+	return !at.Before(a.StartsAt) && at.Before(a.EndsAt)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AffinityRuleKind — вид правила подбора ревьюеров.
+type AffinityRuleKind string
+
+// Виды правил подбора ревьюеров.
+const (
+	// AffinityRuleNEVERPAIR — user_id и пользователи with_user_ids не ревьюят PR друг друга.
+	AffinityRuleNEVERPAIR AffinityRuleKind = "NEVER_PAIR"
+	// AffinityRuleALWAYSINCLUDE — среди ревьюеров PR автора user_id всегда есть кто-то из with_user_ids.
+	AffinityRuleALWAYSINCLUDE AffinityRuleKind = "ALWAYS_INCLUDE"
+	// AffinityRuleREQUIREPAIR — user_id ревьюит только вместе с кем-то из with_user_ids.
+	AffinityRuleREQUIREPAIR AffinityRuleKind = "REQUIRE_PAIR"
+)
+
+// Valid сообщает, известен ли вид правила.
+func (k AffinityRuleKind) Valid() bool {
+	switch k {
+	case AffinityRuleNEVERPAIR, AffinityRuleALWAYSINCLUDE, AffinityRuleREQUIREPAIR:
+		return true
+	}
+	return false
+}
+
+// AffinityRule — правило подбора ревьюеров команды; действует, когда ревьюеры выбираются из team_name.
+type AffinityRule struct {
+	RuleId      int64            `json:"rule_id"`
+	TeamName    string           `json:"team_name"`
+	Kind        AffinityRuleKind `json:"kind"`
+	UserId      string           `json:"user_id"`
+	WithUserIds []string         `json:"with_user_ids"`
+	Description string           `json:"description"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// PostTeamAddRuleJSONBody описывает тело запроса на создание правила подбора ревьюеров.
+type PostTeamAddRuleJSONBody struct {
+	TeamName    string           `json:"team_name"`
+	Kind        AffinityRuleKind `json:"kind"`
+	UserId      string           `json:"user_id"`
+	WithUserIds []string         `json:"with_user_ids"`
+	Description string           `json:"description"`
+}
+
+// Pairs сообщает, связывает ли правило пользователей a и b в любом порядке.
+func (r AffinityRule) Pairs(a, b string) bool {
+	return (r.UserId == a && slices.Contains(r.WithUserIds, b)) || (r.UserId == b && slices.Contains(r.WithUserIds, a))
+}
+
+// String описывает правило для объяснений отказа кандидатам.
+func (r AffinityRule) String() string {
+	with := strings.Join(r.WithUserIds, ", ")
+	switch r.Kind {
+	case AffinityRuleNEVERPAIR:
+		return fmt.Sprintf("rule %d: %s and [%s] never review each other's PRs", r.RuleId, r.UserId, with)
+	case AffinityRuleALWAYSINCLUDE:
+		return fmt.Sprintf("rule %d: PRs of %s need a reviewer from [%s]", r.RuleId, r.UserId, with)
+	case AffinityRuleREQUIREPAIR:
+		return fmt.Sprintf("rule %d: %s reviews only together with one of [%s]", r.RuleId, r.UserId, with)
+	}
+	return fmt.Sprintf("rule %d", r.RuleId)
+}
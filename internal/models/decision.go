@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// DecisionOperation — операция, в ходе которой выбирались ревьюеры.
+type DecisionOperation string
+
+// Операции, после которых сохраняется решение о назначении.
+const (
+	// DecisionOperationCREATE — назначение ревьюеров новому PR.
+	DecisionOperationCREATE DecisionOperation = "CREATE"
+	// DecisionOperationQUEUE — назначение недостающих ревьюеров PR из очереди.
+	DecisionOperationQUEUE DecisionOperation = "QUEUE"
+	// DecisionOperationREASSIGN — замена одного ревьюера, в том числе планировщиком зависших ревью.
+	DecisionOperationREASSIGN DecisionOperation = "REASSIGN"
+	// DecisionOperationDEACTIVATE — замена ревьюеров при массовой деактивации участников команды.
+	DecisionOperationDEACTIVATE DecisionOperation = "DEACTIVATE"
+	// DecisionOperationABSENCE — передача ревью ушедшего в отсутствие пользователя.
+	DecisionOperationABSENCE DecisionOperation = "ABSENCE"
+)
+
+// Valid сообщает, известна ли операция.
+func (o DecisionOperation) Valid() bool {
+	switch o {
+	case DecisionOperationCREATE, DecisionOperationQUEUE, DecisionOperationREASSIGN,
+		DecisionOperationDEACTIVATE, DecisionOperationABSENCE:
+		return true
+	}
+	return false
+}
+
+// AssignmentStrategy — порядок, в котором выбирались кандидаты.
+type AssignmentStrategy string
+
+const (
+	// AssignmentStrategyBALANCED — сначала участники в рабочее время, затем наименее загруженные, в пределах лимитов.
+	AssignmentStrategyBALANCED AssignmentStrategy = "BALANCED"
+	// AssignmentStrategyURGENT — наименее загруженные без учёта рабочего времени, при нехватке и сверх лимита.
+	AssignmentStrategyURGENT AssignmentStrategy = "URGENT"
+)
+
+// CandidateOutcome — чем для кандидата закончился подбор.
+type CandidateOutcome string
+
+const (
+	// CandidateOutcomeSELECTED — кандидат назначен ревьюером.
+	CandidateOutcomeSELECTED CandidateOutcome = "SELECTED"
+	// CandidateOutcomeEXCLUDED — кандидат не мог быть назначен: неактивен, автор, отсутствует, на лимите или отвергнут правилом.
+	CandidateOutcomeEXCLUDED CandidateOutcome = "EXCLUDED"
+	// CandidateOutcomeNOTSELECTED — кандидат подходил, но выбраны другие.
+	CandidateOutcomeNOTSELECTED CandidateOutcome = "NOT_SELECTED"
+)
+
+// CandidateLoad — нагрузка кандидата на момент подбора.
+type CandidateLoad struct {
+	OpenReviews    int  `json:"open_reviews"`
+	WeightedLoad   int  `json:"weighted_load"`
+	MaxOpenReviews int  `json:"max_open_reviews"`
+	OffHours       bool `json:"off_hours"`
+}
+
+// DecisionCandidate — участник команды ревьюеров и причина, по которой его выбрали или нет.
+type DecisionCandidate struct {
+	UserId  string           `json:"user_id"`
+	Outcome CandidateOutcome `json:"outcome"`
+	Reason  string           `json:"reason,omitempty"`
+	// Load заполнен для тех, кто попал в число кандидатов.
+	Load *CandidateLoad `json:"load,omitempty"`
+}
+
+// AssignmentDecision — запись о том, как были выбраны ревьюеры PR.
+type AssignmentDecision struct {
+	DecisionId    int64              `json:"decision_id"`
+	PullRequestId string             `json:"pull_request_id"`
+	Operation     DecisionOperation  `json:"operation"`
+	Strategy      AssignmentStrategy `json:"strategy"`
+	TeamName      string             `json:"team_name"`
+	Selected      []string           `json:"selected"`
+	// Replaced — ревьюеры, вместо которых выбраны Selected.
+	Replaced []string `json:"replaced,omitempty"`
+	// Pending — сколько мест осталось незанятыми из-за лимитов.
+	Pending int `json:"pending"`
+	// Rules — правила подбора команды, действовавшие при выборе.
+	Rules      []string            `json:"rules,omitempty"`
+	Candidates []DecisionCandidate `json:"candidates"`
+	DecidedAt  time.Time           `json:"decided_at"`
+}
+
+// PullRequestExplanation — решения о назначении ревьюеров PR, старые первыми.
+type PullRequestExplanation struct {
+	PullRequestId string               `json:"pull_request_id"`
+	Decisions     []AssignmentDecision `json:"decisions"`
+}
@@ -0,0 +1,76 @@
+package models
+
+// ImportConflictPolicy определяет, что делать со строкой импорта, если пользователь уже существует.
+type ImportConflictPolicy string
+
+const (
+	// ImportConflictSkip оставляет существующего пользователя без изменений.
+	ImportConflictSkip ImportConflictPolicy = "skip"
+	// ImportConflictUpdate перезаписывает имя, команду и активность существующего пользователя.
+	ImportConflictUpdate ImportConflictPolicy = "update"
+	// ImportConflictFail отменяет весь импорт при первом же конфликте.
+	ImportConflictFail ImportConflictPolicy = "fail"
+)
+
+// Valid сообщает, поддерживается ли политика.
+func (p ImportConflictPolicy) Valid() bool {
+	switch p {
+	case ImportConflictSkip, ImportConflictUpdate, ImportConflictFail:
+		return true
+	default:
+		return false
+	}
+}
+
+// Форматы файла импорта пользователей.
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// ImportUserRow — строка файла импорта; Row считается с единицы без учёта заголовка CSV.
+type ImportUserRow struct {
+	Row      int
+	UserId   string
+	Username string
+	TeamName string
+	IsActive bool
+	// ParseError заполняется, если строку не удалось разобрать.
+	ParseError string
+}
+
+// ImportOptions задаёт режим импорта.
+type ImportOptions struct {
+	DryRun     bool
+	OnConflict ImportConflictPolicy
+}
+
+// Действия над строкой импорта.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionSkip      = "skip"
+	ImportActionUnchanged = "unchanged"
+	ImportActionError     = "error"
+)
+
+// ImportRowResult описывает итог обработки одной строки.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	UserId string `json:"user_id"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport — отчёт об импорте: что сделано (или было бы сделано при dry_run) по каждой строке.
+type ImportReport struct {
+	DryRun       bool              `json:"dry_run"`
+	Applied      bool              `json:"applied"`
+	OnConflict   string            `json:"on_conflict"`
+	TeamsCreated []string          `json:"teams_created"`
+	Created      int               `json:"created"`
+	Updated      int               `json:"updated"`
+	Skipped      int               `json:"skipped"`
+	Failed       int               `json:"failed"`
+	Rows         []ImportRowResult `json:"rows"`
+}
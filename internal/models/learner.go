@@ -0,0 +1,20 @@
+package models
+
+// Learner — стажёр команды, которого назначают теневым ревьюером.
+type Learner struct {
+	UserId string `json:"user_id"`
+	// OpenShadowReviews — открытые PR, где стажёр назначен теневым ревьюером.
+	OpenShadowReviews int `json:"open_shadow_reviews"`
+}
+
+// TeamLearners — стажёры команды.
+type TeamLearners struct {
+	TeamName string    `json:"team_name"`
+	Learners []Learner `json:"learners"`
+}
+
+// PostTeamSetLearnersJSONBody задаёт список стажёров команды целиком; пустой список отключает теневые ревью.
+type PostTeamSetLearnersJSONBody struct {
+	TeamName string   `json:"team_name"`
+	UserIds  []string `json:"user_ids"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DefaultOrganization — организация, которой принадлежат данные, созданные до появления организаций,
+// и все запросы, пока токены организаций не включены.
+const DefaultOrganization = "default"
+
+// Organization — арендатор сервиса: отдел со своими командами, пользователями и PR, невидимыми другим организациям.
+type Organization struct {
+	OrganizationId string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PostOrganizationCreateJSONBody описывает тело запроса на создание организации.
+type PostOrganizationCreateJSONBody struct {
+	OrganizationId string `json:"organization_id"`
+	Name           string `json:"name"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // PullRequest описывает модель pull request.
 type PullRequest struct {
@@ -12,6 +15,68 @@ type PullRequest struct {
 	PullRequestId     string            `json:"pull_request_id"`
 	PullRequestName   string            `json:"pull_request_name"`
 	Status            PullRequestStatus `json:"status"`
+
+	// LinesAdded, LinesDeleted и FilesChanged — размер изменений; 0 — не указан.
+	LinesAdded   int                 `json:"lines_added,omitempty"`
+	LinesDeleted int                 `json:"lines_deleted,omitempty"`
+	FilesChanged int                 `json:"files_changed,omitempty"`
+	Priority     PullRequestPriority `json:"priority,omitempty"`
+	Labels       []string            `json:"labels,omitempty"`
+
+	// Repository — репозиторий PR; Number — номер PR в нём, 0 — без номера.
+	Repository string `json:"repository,omitempty"`
+	Number     int    `json:"number,omitempty"`
+
+	// ShadowReviewers — теневые ревьюеры-стажёры: не входят в AssignedReviewers и ни на что не влияют.
+	ShadowReviewers []string `json:"shadow_reviewers,omitempty"`
+}
+
+// ReviewerRole — роль пользователя в ревью PR.
+type ReviewerRole string
+
+// Возможные значения ReviewerRole.
+const (
+	ReviewerRoleREVIEWER ReviewerRole = "REVIEWER"
+	ReviewerRoleSHADOW   ReviewerRole = "SHADOW"
+)
+
+// RoleOf возвращает роль пользователя в ревью PR; пустая строка — пользователь не назначен.
+func (pr *PullRequest) RoleOf(userID string) ReviewerRole {
+	if slices.Contains(pr.AssignedReviewers, userID) {
+		return ReviewerRoleREVIEWER
+	}
+	if slices.Contains(pr.ShadowReviewers, userID) {
+		return ReviewerRoleSHADOW
+	}
+	return ""
+}
+
+// ReviewerAssignment — назначение пользователя на ревью PR с его ролью.
+type ReviewerAssignment struct {
+	UserId string
+	Role   ReviewerRole
+}
+
+// Assignments возвращает назначения PR без пустых id и повторов: сначала ревьюеры, затем теневые ревьюеры.
+// Пользователь из обоих списков остаётся ревьюером.
+func (pr *PullRequest) Assignments() []ReviewerAssignment {
+	result := make([]ReviewerAssignment, 0, len(pr.AssignedReviewers)+len(pr.ShadowReviewers))
+	seen := make(map[string]struct{}, cap(result))
+	add := func(ids []string, role ReviewerRole) {
+		for _, id := range ids {
+			if id == "" {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			result = append(result, ReviewerAssignment{UserId: id, Role: role})
+		}
+	}
+	add(pr.AssignedReviewers, ReviewerRoleREVIEWER)
+	add(pr.ShadowReviewers, ReviewerRoleSHADOW)
+	return result
 }
 
 // PostPullRequestCreateJSONBody описывает тело запроса создания PR.
@@ -19,6 +84,70 @@ type PostPullRequestCreateJSONBody struct {
 	AuthorId        string `json:"author_id"`
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
+
+	LinesAdded   int                 `json:"lines_added,omitempty"`
+	LinesDeleted int                 `json:"lines_deleted,omitempty"`
+	FilesChanged int                 `json:"files_changed,omitempty"`
+	Priority     PullRequestPriority `json:"priority,omitempty"`
+	Labels       []string            `json:"labels,omitempty"`
+
+	// Repository — репозиторий PR, по умолчанию default. Если задан Number,
+	// pull_request_id выводится как <repository>#<number>.
+	Repository string `json:"repository,omitempty"`
+	Number     int    `json:"number,omitempty"`
+}
+
+// PullRequestPriority — срочность PR; пустое значение равносильно NORMAL.
+type PullRequestPriority string
+
+// Возможные значения PullRequestPriority.
+const (
+	PullRequestPriorityLOW    PullRequestPriority = "LOW"
+	PullRequestPriorityNORMAL PullRequestPriority = "NORMAL"
+	PullRequestPriorityHIGH   PullRequestPriority = "HIGH"
+	PullRequestPriorityURGENT PullRequestPriority = "URGENT"
+)
+
+// Rank возвращает порядковый номер срочности: чем больше, тем срочнее; для неизвестного значения — -1.
+func (p PullRequestPriority) Rank() int {
+	switch p {
+	case PullRequestPriorityLOW:
+		return 0
+	case PullRequestPriorityNORMAL, "":
+		return 1
+	case PullRequestPriorityHIGH:
+		return 2
+	case PullRequestPriorityURGENT:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// Пороги размера PR: каждый превышенный порог добавляет единицу к весу ревью.
+var (
+	reviewWeightLineSteps = []int{100, 400, 1000, 2000}
+	reviewWeightFileSteps = []int{10, 25, 50, 100}
+)
+
+// ReviewWeight оценивает объём ревью PR: 1 для небольшого PR или PR без размера, до 5 для самых крупных.
+// Считается по большему из двух показателей — числу изменённых строк и числу файлов.
+func (pr *PullRequest) ReviewWeight() int {
+	return 1 + max(
+		stepsExceeded(pr.LinesAdded+pr.LinesDeleted, reviewWeightLineSteps),
+		stepsExceeded(pr.FilesChanged, reviewWeightFileSteps),
+	)
+}
+
+// stepsExceeded считает пороги, которые value превышает.
+func stepsExceeded(value int, steps []int) int {
+	n := 0
+	for _, step := range steps {
+		if value > step {
+			n++
+		}
+	}
+	return n
 }
 
 // PostPullRequestMergeJSONBody описывает параметры запроса на Merge.
@@ -40,6 +169,9 @@ type PullRequestShort struct {
 	PullRequestId   string                 `json:"pull_request_id"`
 	PullRequestName string                 `json:"pull_request_name"`
 	Status          PullRequestShortStatus `json:"status"`
+	Repository      string                 `json:"repository,omitempty"`
+	// Role — роль пользователя, для которого собран список: REVIEWER или SHADOW.
+	Role ReviewerRole `json:"role,omitempty"`
 }
 
 // PullRequestShortStatus описывает возможные статусы укороченного PR.
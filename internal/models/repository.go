@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultRepository — репозиторий, в который попадают PR без явного репозитория, в том числе созданные до его появления.
+const DefaultRepository = "default"
+
+// Repository — репозиторий с PR, командой-владельцем и собственными правилами ревью.
+type Repository struct {
+	RepositoryName string `json:"repository_name"`
+	// OwnerTeam — команда, из которой назначаются ревьюеры PR репозитория; пустое значение — команда автора.
+	OwnerTeam string `json:"owner_team,omitempty"`
+	// RequiredReviewers — сколько ревьюеров назначать на PR (1..2); 0 — по умолчанию, два.
+	RequiredReviewers int `json:"required_reviewers"`
+}
+
+// PullRequestKey собирает идентификатор PR из репозитория и номера: номера PR уникальны только внутри репозитория.
+func PullRequestKey(repository string, number int) string {
+	if repository == "" {
+		repository = DefaultRepository
+	}
+	return repository + "#" + strconv.Itoa(number)
+}
+
+// RepositoryOrDefault возвращает имя репозитория или DefaultRepository для пустого имени.
+func RepositoryOrDefault(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return DefaultRepository
+	}
+	return name
+}
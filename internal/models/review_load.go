@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ReviewerLoad — нагрузка ревьювера: число открытых ревью, их суммарный вес и лимит.
+type ReviewerLoad struct {
+	UserId      string `json:"user_id"`
+	OpenReviews int    `json:"open_reviews"`
+	// WeightedLoad — сумма весов открытых ревью (см. PullRequest.ReviewWeight).
+	WeightedLoad int `json:"weighted_load"`
+	// MaxOpenReviews — действующий лимит открытых ревью; 0 — без лимита.
+	MaxOpenReviews int `json:"max_open_reviews"`
+}
+
+// AtCapacity сообщает, исчерпан ли лимит ревьювера.
+func (l ReviewerLoad) AtCapacity() bool {
+	return l.MaxOpenReviews > 0 && l.OpenReviews >= l.MaxOpenReviews
+}
+
+// PostUsersSetCapacityJSONBody описывает тело запроса на установку лимита; 0 сбрасывает личный лимит.
+type PostUsersSetCapacityJSONBody struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+// QueuedReview — PR в очереди на ревьюеров: при создании им не хватило ревьюеров из-за лимитов.
+type QueuedReview struct {
+	PullRequestId string `json:"pull_request_id"`
+	// Missing — сколько ревьюеров ещё нужно назначить.
+	Missing  int       `json:"missing"`
+	QueuedAt time.Time `json:"queued_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// SnapshotVersion — версия формата архива состояния; увеличивается только при несовместимых изменениях.
+// Новые разделы необязательны (omitempty) и версию не меняют: архив без них загружается как пустой раздел.
+const SnapshotVersion = 1
+
+// Snapshot — полный архив состояния сервиса для переноса между окружениями.
+type Snapshot struct {
+	Version      int            `json:"version"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Teams        []SnapshotTeam `json:"teams"`
+	Users        []User         `json:"users"`
+	PullRequests []*PullRequest `json:"pull_requests"`
+	// Repositories — репозитории с владельцами и правилами ревью; в архивах до их появления отсутствует.
+	Repositories []Repository `json:"repositories,omitempty"`
+	// ReviewRotations — история автоматических замен ревьюверов в порядке записи.
+	ReviewRotations []ReviewRotation `json:"review_rotations,omitempty"`
+	// Absences — периоды отсутствия пользователей; при загрузке они получают новые absence_id.
+	Absences []Absence `json:"absences,omitempty"`
+	// WorkingHours — часовые пояса и рабочее время пользователей.
+	WorkingHours []WorkingHours `json:"working_hours,omitempty"`
+	// ReviewCapacities — личные лимиты открытых ревью.
+	ReviewCapacities []SnapshotCapacity `json:"review_capacities,omitempty"`
+	// ReviewQueue — PR, которые ждут недостающих ревьюверов.
+	ReviewQueue []QueuedReview `json:"review_queue,omitempty"`
+	// AffinityRules — правила подбора ревьюверов команд; при загрузке они получают новые rule_id.
+	AffinityRules []AffinityRule `json:"affinity_rules,omitempty"`
+	// TeamLearners — стажёры команд, из которых назначаются теневые ревьюеры.
+	TeamLearners []SnapshotLearner `json:"team_learners,omitempty"`
+	// AssignmentDecisions — решения о назначении ревьюеров, старые первыми; при загрузке они получают новые decision_id.
+	AssignmentDecisions []AssignmentDecision `json:"assignment_decisions,omitempty"`
+}
+
+// SnapshotTeam — команда в архиве; участники хранятся в Users по team_name.
+type SnapshotTeam struct {
+	TeamName string `json:"team_name"`
+}
+
+// SnapshotCapacity — личный лимит открытых ревью пользователя в архиве.
+type SnapshotCapacity struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+// SnapshotLearner — стажёр команды в архиве.
+type SnapshotLearner struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// SnapshotCounts — число восстановленных записей по видам.
+type SnapshotCounts struct {
+	Teams        int `json:"teams"`
+	Users        int `json:"users"`
+	PullRequests int `json:"pull_requests"`
+	Reviewers    int `json:"reviewers"`
+}
+
+// Counts возвращает число записей каждого вида в архиве.
+func (s *Snapshot) Counts() SnapshotCounts {
+	counts := SnapshotCounts{Teams: len(s.Teams), Users: len(s.Users), PullRequests: len(s.PullRequests)}
+	for _, pr := range s.PullRequests {
+		counts.Reviewers += len(pr.AssignedReviewers)
+	}
+	return counts
+}
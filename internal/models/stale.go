@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ReviewAssignment — назначение ревьюера на открытый PR вместе с отметкой последней активности.
+type ReviewAssignment struct {
+	PullRequestId string
+	ReviewerId    string
+	// TeamName — команда PR, то есть команда автора; по ней выбирается SLA.
+	TeamName       string
+	LastActivityAt time.Time
+	// Rotations — сколько раз планировщик уже менял ревьюеров этого PR.
+	Rotations int
+}
+
+// ReviewActivity — отметка активности ревьюера по PR.
+type ReviewActivity struct {
+	PullRequestId  string    `json:"pull_request_id"`
+	UserId         string    `json:"user_id"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+}
+
+// ReviewRotation — запись истории автоматической замены неактивного ревьюера.
+type ReviewRotation struct {
+	PullRequestId string    `json:"pull_request_id"`
+	OldUserId     string    `json:"old_user_id"`
+	NewUserId     string    `json:"new_user_id"`
+	TeamName      string    `json:"team_name"`
+	IdleSeconds   int64     `json:"idle_seconds"`
+	RotatedAt     time.Time `json:"rotated_at"`
+}
+
+// ReviewRotationFilter ограничивает выборку истории замен; пустые поля не фильтруют.
+type ReviewRotationFilter struct {
+	PullRequestId string
+	Limit         int
+}
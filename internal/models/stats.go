@@ -1,9 +1,60 @@
 package models
 
+import (
+	"math"
+	"time"
+)
+
 // AssignmentStats содержит счётчики назначений по пользователям и PR.
 type AssignmentStats struct {
 	ByUser        []UserAssignmentStat        `json:"by_user"`
 	ByPullRequest []PullRequestAssignmentStat `json:"by_pull_request"`
+	ByTeam        []TeamAssignmentStat        `json:"by_team"`
+}
+
+// AssignmentStatsFilter ограничивает выборку PR для статистики назначений.
+// Пустые поля не фильтруют; окно [From, To) применяется к времени создания PR,
+// команда PR — команда автора. Limit ограничивает списки ByUser и ByPullRequest.
+type AssignmentStatsFilter struct {
+	TeamName   string
+	Repository string
+	From       time.Time
+	To         time.Time
+	Status     PullRequestStatus
+	Limit      int
+}
+
+// TeamAssignmentStat агрегирует PR и нагрузку ревьюеров одной команды.
+type TeamAssignmentStat struct {
+	TeamName     string  `json:"team_name"`
+	OpenCount    int     `json:"open_count"`
+	MergedCount  int     `json:"merged_count"`
+	AvgReviewers float64 `json:"avg_reviewers"`
+	// LoadImbalance — коэффициент вариации числа назначений среди активных участников команды:
+	// 0 при равномерной нагрузке, растёт по мере перекоса.
+	LoadImbalance float64 `json:"load_imbalance"`
+}
+
+// LoadImbalance считает коэффициент вариации (σ/μ) нагрузки; для пустой или нулевой нагрузки — 0.
+func LoadImbalance(loads []int) float64 {
+	if len(loads) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, l := range loads {
+		sum += float64(l)
+	}
+	mean := sum / float64(len(loads))
+	if mean == 0 {
+		return 0
+	}
+	var variance float64
+	for _, l := range loads {
+		d := float64(l) - mean
+		variance += d * d
+	}
+	variance /= float64(len(loads))
+	return math.Sqrt(variance) / mean
 }
 
 // UserAssignmentStat показывает, сколько активных назначений у конкретного пользователя.
@@ -11,6 +62,10 @@ type UserAssignmentStat struct {
 	UserId      string `json:"user_id"`
 	Username    string `json:"username"`
 	Assignments int    `json:"assignments"`
+	// WeightedLoad — сумма весов ревью тех же PR: крупный PR весит больше мелкого.
+	WeightedLoad int `json:"weighted_load"`
+	// ShadowAssignments — PR, где пользователь был теневым ревьюером; в Assignments не входят.
+	ShadowAssignments int `json:"shadow_assignments"`
 }
 
 // PullRequestAssignmentStat показывает, сколько ревьюеров закреплено за PR.
@@ -18,4 +73,65 @@ type PullRequestAssignmentStat struct {
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	ReviewerCount   int    `json:"reviewer_count"`
+	// ShadowCount — число теневых ревьюеров PR; в ReviewerCount не входят.
+	ShadowCount int `json:"shadow_count"`
+}
+
+// TurnaroundFilter задаёт окно [From, To) по времени слияния PR; непустой Repository оставляет PR одного репозитория.
+type TurnaroundFilter struct {
+	From       time.Time
+	To         time.Time
+	Repository string
+}
+
+// TurnaroundPercentiles — перцентили времени от создания до слияния PR в секундах (nearest-rank).
+type TurnaroundPercentiles struct {
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+// TeamTurnaround — перцентили по команде автора PR.
+type TeamTurnaround struct {
+	TeamName string `json:"team_name"`
+	TurnaroundPercentiles
+}
+
+// UserTurnaround — перцентили по автору или ревьюеру.
+type UserTurnaround struct {
+	UserId string `json:"user_id"`
+	TurnaroundPercentiles
+}
+
+// TurnaroundStats содержит время до слияния в разрезе команд, авторов и ревьюеров.
+type TurnaroundStats struct {
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Overall    TurnaroundPercentiles `json:"overall"`
+	ByTeam     []TeamTurnaround      `json:"by_team"`
+	ByAuthor   []UserTurnaround      `json:"by_author"`
+	ByReviewer []UserTurnaround      `json:"by_reviewer"`
+}
+
+// Разрезы, в которых репозитории возвращают строки статистики времени до слияния.
+const (
+	TurnaroundDimOverall  = "overall"
+	TurnaroundDimTeam     = "team"
+	TurnaroundDimAuthor   = "author"
+	TurnaroundDimReviewer = "reviewer"
+)
+
+// AddGroup раскладывает строку статистики по разрезу dim в соответствующий срез.
+func (s *TurnaroundStats) AddGroup(dim, key string, p TurnaroundPercentiles) {
+	switch dim {
+	case TurnaroundDimOverall:
+		s.Overall = p
+	case TurnaroundDimTeam:
+		s.ByTeam = append(s.ByTeam, TeamTurnaround{TeamName: key, TurnaroundPercentiles: p})
+	case TurnaroundDimAuthor:
+		s.ByAuthor = append(s.ByAuthor, UserTurnaround{UserId: key, TurnaroundPercentiles: p})
+	case TurnaroundDimReviewer:
+		s.ByReviewer = append(s.ByReviewer, UserTurnaround{UserId: key, TurnaroundPercentiles: p})
+	}
 }
@@ -0,0 +1,11 @@
+package models
+
+// WorkingHours — часовой пояс и рабочее время пользователя; рабочие дни — с понедельника по пятницу.
+type WorkingHours struct {
+	UserId string `json:"user_id"`
+	// TimeZone — имя пояса из базы IANA, например Europe/Berlin.
+	TimeZone string `json:"time_zone"`
+	// Start и End — начало и конец рабочего дня по местному времени в формате HH:MM; End позже Start.
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
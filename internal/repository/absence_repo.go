@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const selectAbsencesSQL = `
+SELECT absence_id, user_id, starts_at, ends_at, reason, created_at, reassigned_at
+FROM user_absences
+WHERE organization_id = $1
+`
+
+// CreateAbsence сохраняет период отсутствия и заполняет AbsenceId.
+func (s *Storage) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	if absence == nil {
+		return fmt.Errorf("absence is nil")
+	}
+	const q = `
+INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at, organization_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING absence_id
+`
+	rows, err := s.pool.Query(ctx, q, absence.UserId, absence.StartsAt, absence.EndsAt, absence.Reason, absence.CreatedAt,
+		tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("insert absence: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("insert absence: %w", err)
+		}
+		return fmt.Errorf("insert absence: no id returned")
+	}
+	if err := rows.Scan(&absence.AbsenceId); err != nil {
+		return fmt.Errorf("scan absence id: %w", err)
+	}
+	return nil
+}
+
+// GetAbsence возвращает период отсутствия по идентификатору.
+func (s *Storage) GetAbsence(ctx context.Context, absenceID int64) (*models.Absence, error) {
+	absences, err := s.queryAbsences(ctx, selectAbsencesSQL+`AND absence_id = $2`, tenant.Organization(ctx), absenceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(absences) == 0 {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	return &absences[0], nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя в порядке начала.
+func (s *Storage) ListAbsences(ctx context.Context, userID string) ([]models.Absence, error) {
+	return s.queryAbsences(ctx, selectAbsencesSQL+`AND user_id = $2 ORDER BY starts_at, absence_id`, tenant.Organization(ctx), userID)
+}
+
+// DeleteAbsence удаляет период отсутствия; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM user_absences WHERE absence_id = $1 AND organization_id = $2`, absenceID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("delete absence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	return nil
+}
+
+// FindAbsentUsers возвращает пользователей, чей период отсутствия покрывает момент at.
+func (s *Storage) FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error) {
+	const q = `
+SELECT DISTINCT user_id
+FROM user_absences
+WHERE organization_id = $2 AND starts_at <= $1 AND ends_at > $1
+ORDER BY user_id
+`
+	rows, err := s.pool.Query(ctx, q, at, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query absent users: %w", err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan absent users: %w", err)
+		}
+		result = append(result, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows absent users: %w", err)
+	}
+	return result, nil
+}
+
+// FindAbsencesToReassign возвращает идущие в момент at периоды, ревью по которым ещё не переданы.
+func (s *Storage) FindAbsencesToReassign(ctx context.Context, at time.Time) ([]models.Absence, error) {
+	const where = `AND reassigned_at IS NULL AND starts_at <= $2 AND ends_at > $2 ORDER BY starts_at, absence_id`
+	return s.queryAbsences(ctx, selectAbsencesSQL+where, tenant.Organization(ctx), at)
+}
+
+// MarkAbsenceReassigned отмечает, что ревью отсутствующего переданы коллегам.
+func (s *Storage) MarkAbsenceReassigned(ctx context.Context, absenceID int64, at time.Time) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE user_absences SET reassigned_at = $2 WHERE absence_id = $1 AND organization_id = $3`,
+		absenceID, at, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("mark absence reassigned: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	return nil
+}
+
+// queryAbsences выполняет выборку из user_absences.
+func (s *Storage) queryAbsences(ctx context.Context, q string, args ...any) ([]models.Absence, error) {
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query absences: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Absence, 0)
+	for rows.Next() {
+		var a models.Absence
+		if err := rows.Scan(&a.AbsenceId, &a.UserId, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.ReassignedAt); err != nil {
+			return nil, fmt.Errorf("scan absences: %w", err)
+		}
+		result = append(result, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows absences: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectAffinityRulesSQL = `
+SELECT rule_id, team_name, kind, user_id, with_user_ids, description, created_at
+FROM affinity_rules
+WHERE organization_id = $1
+`
+
+// CreateAffinityRule сохраняет правило подбора ревьюеров и заполняет RuleId.
+func (s *Storage) CreateAffinityRule(ctx context.Context, rule *models.AffinityRule) error {
+	if rule == nil {
+		return fmt.Errorf("affinity rule is nil")
+	}
+	const q = `
+INSERT INTO affinity_rules (organization_id, team_name, kind, user_id, with_user_ids, description, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING rule_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx), rule.TeamName, string(rule.Kind), rule.UserId,
+		rule.WithUserIds, rule.Description, rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert affinity rule: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("insert affinity rule: %w", err)
+		}
+		return fmt.Errorf("insert affinity rule: no id returned")
+	}
+	if err := rows.Scan(&rule.RuleId); err != nil {
+		return fmt.Errorf("scan affinity rule id: %w", err)
+	}
+	return nil
+}
+
+// DeleteAffinityRule удаляет правило; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAffinityRule(ctx context.Context, ruleID int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM affinity_rules WHERE rule_id = $1 AND organization_id = $2`, ruleID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("delete affinity rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotFoundError(fmt.Sprintf("affinity rule %d", ruleID))
+	}
+	return nil
+}
+
+// FindAffinityRules возвращает правила команды в порядке создания.
+func (s *Storage) FindAffinityRules(ctx context.Context, teamName string) ([]models.AffinityRule, error) {
+	rows, err := s.pool.Query(ctx, selectAffinityRulesSQL+`AND team_name = $2 ORDER BY rule_id`, tenant.Organization(ctx), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query affinity rules: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.AffinityRule, 0)
+	for rows.Next() {
+		r, err := scanAffinityRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan affinity rules: %w", err)
+		}
+		result = append(result, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows affinity rules: %w", err)
+	}
+	return result, nil
+}
+
+// scanAffinityRule читает текущую строку selectAffinityRulesSQL.
+func scanAffinityRule(rows pgx.Rows) (*models.AffinityRule, error) {
+	var (
+		r    models.AffinityRule
+		kind string
+	)
+	if err := rows.Scan(&r.RuleId, &r.TeamName, &kind, &r.UserId, &r.WithUserIds, &r.Description, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	r.Kind = models.AffinityRuleKind(kind)
+	return &r, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/migrate"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/repotest"
+	"github.com/AlekseyZapadovnikov/pr-manager/migrations"
+)
+
+// testDSNEnv задаёт строку подключения к отдельной тестовой базе PostgreSQL; без неё контрактные тесты пропускаются.
+const testDSNEnv = "PR_MANAGER_TEST_DSN"
+
+func TestStorageContract(t *testing.T) {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	s, err := newStorageFromDSN(testCtx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(s.Close)
+
+	ms, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := s.MigrateUp(testCtx, ms); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repotest.RunContract(t, func(t *testing.T) repotest.Backend {
+		const truncate = `TRUNCATE assignment_decisions, team_learners, affinity_rules, review_queue, user_review_capacity, user_working_hours, user_absences, review_rotations, scheduler_leases, pull_request_reviewers, pull_requests, repositories, users, teams, organization_tokens, organizations CASCADE`
+		if _, err := s.pool.Exec(testCtx, truncate); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		// Организацию default и её репозиторий default создают миграции, а не приложение.
+		if _, err := s.pool.Exec(testCtx, `INSERT INTO organizations (organization_id, name) VALUES ('default', 'Default')`); err != nil {
+			t.Fatalf("seed default organization: %v", err)
+		}
+		if _, err := s.pool.Exec(testCtx, `INSERT INTO repositories (repository_name, organization_id) VALUES ('default', 'default')`); err != nil {
+			t.Fatalf("seed default repository: %v", err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// SaveAssignmentDecision сохраняет решение о назначении ревьюеров и заполняет DecisionId.
+func (s *Storage) SaveAssignmentDecision(ctx context.Context, decision *models.AssignmentDecision) error {
+	if decision == nil {
+		return fmt.Errorf("assignment decision is nil")
+	}
+	const q = `
+INSERT INTO assignment_decisions (organization_id, pull_request_id, operation, strategy, team_name,
+                                  selected, replaced, pending, rules, candidates, decided_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING decision_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx), decision.PullRequestId, string(decision.Operation),
+		string(decision.Strategy), decision.TeamName, decision.Selected, decision.Replaced, decision.Pending,
+		decision.Rules, decision.Candidates, decision.DecidedAt)
+	if err != nil {
+		return fmt.Errorf("insert assignment decision: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("insert assignment decision: %w", err)
+		}
+		return fmt.Errorf("insert assignment decision: no id returned")
+	}
+	if err := rows.Scan(&decision.DecisionId); err != nil {
+		return fmt.Errorf("scan assignment decision id: %w", err)
+	}
+	return nil
+}
+
+// selectDecisionsSQL выбирает решения о назначении организации; дальнейшие условия дописываются через AND.
+const selectDecisionsSQL = `
+SELECT decision_id, pull_request_id, operation, strategy, team_name, selected, replaced, pending, rules, candidates, decided_at
+FROM assignment_decisions
+WHERE organization_id = $1
+`
+
+// FindAssignmentDecisions возвращает решения о назначении ревьюеров PR, старые первыми.
+func (s *Storage) FindAssignmentDecisions(ctx context.Context, prID string) ([]models.AssignmentDecision, error) {
+	rows, err := s.pool.Query(ctx, selectDecisionsSQL+`AND pull_request_id = $2 ORDER BY decision_id`, tenant.Organization(ctx), prID)
+	if err != nil {
+		return nil, fmt.Errorf("query assignment decisions: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.AssignmentDecision, 0)
+	for rows.Next() {
+		d, err := scanDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan assignment decisions: %w", err)
+		}
+		result = append(result, *d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows assignment decisions: %w", err)
+	}
+	return result, nil
+}
+
+// scanDecision читает строку, выбранную selectDecisionsSQL.
+func scanDecision(rows pgx.Rows) (*models.AssignmentDecision, error) {
+	var (
+		d                   models.AssignmentDecision
+		operation, strategy string
+	)
+	if err := rows.Scan(&d.DecisionId, &d.PullRequestId, &operation, &strategy, &d.TeamName, &d.Selected,
+		&d.Replaced, &d.Pending, &d.Rules, &d.Candidates, &d.DecidedAt); err != nil {
+		return nil, err
+	}
+	d.Operation = models.DecisionOperation(operation)
+	d.Strategy = models.AssignmentStrategy(strategy)
+	return &d, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// SetTeamLearners заменяет список стажёров команды в одной транзакции.
+func (s *Storage) SetTeamLearners(ctx context.Context, teamName string, userIDs []string) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	organizationID := tenant.Organization(ctx)
+	if _, err := tx.Exec(ctx, `DELETE FROM team_learners WHERE organization_id = $1 AND team_name = $2`, organizationID, teamName); err != nil {
+		return fmt.Errorf("delete team learners: %w", err)
+	}
+	const insertLearner = `
+	INSERT INTO team_learners (organization_id, team_name, user_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+	`
+	for _, id := range userIDs {
+		if _, err := tx.Exec(ctx, insertLearner, organizationID, teamName, id); err != nil {
+			return fmt.Errorf("insert team learner (%s): %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// FindTeamLearners возвращает стажёров команды по user_id с числом их открытых теневых ревью.
+func (s *Storage) FindTeamLearners(ctx context.Context, teamName string) ([]models.Learner, error) {
+	const q = `
+SELECT l.user_id, COUNT(p.pull_request_id)
+FROM team_learners l
+LEFT JOIN pull_request_reviewers r
+    ON r.organization_id = l.organization_id AND r.user_id = l.user_id AND r.role = 'SHADOW'
+LEFT JOIN pull_requests p
+    ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id AND p.status = 'OPEN'
+WHERE l.organization_id = $1 AND l.team_name = $2
+GROUP BY l.user_id
+ORDER BY l.user_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query team learners: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Learner, 0)
+	for rows.Next() {
+		var (
+			learner models.Learner
+			open    int64
+		)
+		if err := rows.Scan(&learner.UserId, &open); err != nil {
+			return nil, fmt.Errorf("scan team learners: %w", err)
+		}
+		learner.OpenShadowReviews = int(open)
+		result = append(result, learner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows team learners: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,1727 @@
+// Package memory реализует репозитории сервиса в памяти процесса — для разработки и тестов без базы данных.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// maxReviewers повторяет ограничение SavePullRequest в PostgreSQL-хранилище.
+const maxReviewers = 2
+
+// Storage хранит данные организаций в памяти и повторяет семантику PostgreSQL-хранилища.
+type Storage struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenantData
+
+	organizations map[string]models.Organization
+	tokens        map[string]string // хеш токена → организация
+
+	leases        map[string]lease
+	lastAbsenceID int64
+	lastRuleID    int64
+
+	lastDecisionID int64
+}
+
+// tenantData — строки одной организации: команды, пользователи, PR и всё, что на них ссылается.
+type tenantData struct {
+	teams map[string]struct{}
+	users map[string]models.User
+	prs   map[string]*pullRequestRecord
+
+	rotations []models.ReviewRotation
+
+	absences map[int64]models.Absence
+
+	workingHours map[string]models.WorkingHours
+
+	capacities  map[string]int
+	reviewQueue map[string]models.QueuedReview
+
+	repositories map[string]models.Repository
+
+	affinityRules map[int64]models.AffinityRule
+
+	learners map[string]map[string]struct{} // команда → стажёры
+
+	decisions []models.AssignmentDecision
+}
+
+// newTenantData создаёт пустые данные организации; как и в SQL-хранилищах, в них сразу есть репозиторий default.
+func newTenantData() *tenantData {
+	return &tenantData{
+		repositories: defaultRepositories(),
+		teams:        make(map[string]struct{}),
+		users:        make(map[string]models.User),
+		prs:          make(map[string]*pullRequestRecord),
+		absences:     make(map[int64]models.Absence),
+		workingHours: make(map[string]models.WorkingHours),
+		capacities:   make(map[string]int),
+		reviewQueue:  make(map[string]models.QueuedReview),
+
+		affinityRules: make(map[int64]models.AffinityRule),
+		learners:      make(map[string]map[string]struct{}),
+	}
+}
+
+// lease — строка scheduler_leases.
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// pullRequestRecord — строка pull_requests вместе со строками pull_request_reviewers
+// (ревьюер → время последней активности); строки с ролью SHADOW хранятся отдельно в shadows.
+type pullRequestRecord struct {
+	id        string
+	name      string
+	authorID  string
+	status    models.PullRequestStatus
+	createdAt *time.Time
+	mergedAt  *time.Time
+	reviewers map[string]time.Time
+	shadows   map[string]time.Time
+
+	linesAdded, linesDeleted, filesChanged int
+	priority                               models.PullRequestPriority
+	labels                                 []string
+	repository                             string
+	number                                 int
+}
+
+// newPullRequestRecord копирует поля PR в запись; ревьюеры и теневые ревьюеры передаются отдельно.
+func newPullRequestRecord(pr *models.PullRequest, reviewers, shadows map[string]time.Time) *pullRequestRecord {
+	return &pullRequestRecord{
+		id:           pr.PullRequestId,
+		name:         pr.PullRequestName,
+		authorID:     pr.AuthorId,
+		status:       pr.Status,
+		createdAt:    cloneTime(pr.CreatedAt),
+		mergedAt:     cloneTime(pr.MergedAt),
+		reviewers:    reviewers,
+		shadows:      shadows,
+		linesAdded:   pr.LinesAdded,
+		linesDeleted: pr.LinesDeleted,
+		filesChanged: pr.FilesChanged,
+		priority:     pr.Priority,
+		labels:       slices.Clone(pr.Labels),
+		repository:   models.RepositoryOrDefault(pr.Repository),
+		number:       pr.Number,
+	}
+}
+
+// reviewWeight повторяет колонку review_weight SQL-хранилищ.
+func (r *pullRequestRecord) reviewWeight() int {
+	pr := models.PullRequest{LinesAdded: r.linesAdded, LinesDeleted: r.linesDeleted, FilesChanged: r.filesChanged}
+	return pr.ReviewWeight()
+}
+
+// NewStorage создаёт пустое хранилище в памяти; как и миграция SQL-хранилищ, оно сразу содержит
+// организацию default с репозиторием default.
+func NewStorage() *Storage {
+	return &Storage{
+		tenants: map[string]*tenantData{models.DefaultOrganization: newTenantData()},
+		organizations: map[string]models.Organization{
+			models.DefaultOrganization: {OrganizationId: models.DefaultOrganization, Name: "Default", CreatedAt: time.Now()},
+		},
+		tokens: make(map[string]string),
+		leases: make(map[string]lease),
+	}
+}
+
+// tenant возвращает данные организации из контекста; вызывается под блокировкой.
+// У неизвестной организации данных нет: она видит пустое хранилище, а записанное в него теряется.
+func (s *Storage) tenant(ctx context.Context) *tenantData {
+	if t, ok := s.tenants[tenant.Organization(ctx)]; ok {
+		return t
+	}
+	return newTenantData()
+}
+
+// Close ничего не делает и нужен для совместимости с PostgreSQL-хранилищем.
+func (s *Storage) Close() {}
+
+// ---------- пользователи и команды ----------
+
+// SaveUser выполняет upsert пользователя; команда пользователя должна существовать.
+func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return fmt.Errorf("user is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if err := t.checkTeamRef(user.TeamName); err != nil {
+		return fmt.Errorf("upsert user: %w", err)
+	}
+	t.users[user.UserId] = *user
+	return nil
+}
+
+// GetUser возвращает пользователя по идентификатору.
+func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	user, ok := t.users[userID]
+	if !ok {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("user %s", userID))
+	}
+	return &user, nil
+}
+
+// GetAllUsersInTeam возвращает участников команды, отсортированных по имени.
+func (s *Storage) GetAllUsersInTeam(ctx context.Context, teamID string) ([]*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+	return t.usersInTeam(teamID), nil
+}
+
+// SaveTeam создаёт команду или возвращает ошибку TEAM_EXISTS.
+func (s *Storage) SaveTeam(ctx context.Context, team *models.Team) error {
+	if team == nil {
+		return fmt.Errorf("team is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, exists := t.teams[team.TeamName]; exists {
+		return domain.NewTeamExistsError(team.TeamName)
+	}
+	t.teams[team.TeamName] = struct{}{}
+	return nil
+}
+
+// CreateTeamWithMembers атомарно создаёт команду и выполняет upsert её участников.
+func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team, users []models.User) error {
+	if team == nil {
+		return fmt.Errorf("team is nil")
+	}
+	return s.CreateTeamsWithMembers(ctx, []string{team.TeamName}, users)
+}
+
+// CreateTeamsWithMembers атомарно создаёт новые команды и выполняет upsert пользователей.
+func (s *Storage) CreateTeamsWithMembers(ctx context.Context, teamNames []string, users []models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	created := make(map[string]struct{}, len(teamNames))
+	for _, teamName := range teamNames {
+		if _, exists := t.teams[teamName]; exists {
+			return domain.NewTeamExistsError(teamName)
+		}
+		if _, dup := created[teamName]; dup {
+			return domain.NewTeamExistsError(teamName)
+		}
+		created[teamName] = struct{}{}
+	}
+	// Проверяем ссылки до изменений, чтобы ошибка не оставила половину данных.
+	for _, user := range users {
+		if _, ok := created[user.TeamName]; ok {
+			continue
+		}
+		if err := t.checkTeamRef(user.TeamName); err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.UserId, err)
+		}
+	}
+
+	for teamName := range created {
+		t.teams[teamName] = struct{}{}
+	}
+	for _, user := range users {
+		t.users[user.UserId] = user
+	}
+	return nil
+}
+
+// GetTeam возвращает команду вместе с участниками.
+func (s *Storage) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	if _, exists := t.teams[teamID]; !exists {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("team %s", teamID))
+	}
+
+	users := t.usersInTeam(teamID)
+	members := make([]models.TeamMember, 0, len(users))
+	for _, u := range users {
+		members = append(members, models.ConvertUserToTeamMember(*u))
+	}
+	return &models.Team{TeamName: teamID, Members: members}, nil
+}
+
+// ---------- pull requests ----------
+
+// SavePullRequest атомарно сохраняет PR и полностью заменяет список его ревьюеров.
+func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	if pr == nil {
+		return fmt.Errorf("pr is nil")
+	}
+	if len(pr.AssignedReviewers) > maxReviewers {
+		return fmt.Errorf("assigned reviewers count must be <= 2")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.users[pr.AuthorId]; !ok {
+		return fmt.Errorf("upsert pull_requests: author %s does not exist", pr.AuthorId)
+	}
+	if err := checkPullRequestRefs(pr, t.repositories, t.prs); err != nil {
+		return fmt.Errorf("upsert pull_requests: %w", err)
+	}
+	// Оставшиеся ревьюеры сохраняют отметку активности, даже если сменили роль; новые получают текущее время.
+	var previous *pullRequestRecord
+	if rec, ok := t.prs[pr.PullRequestId]; ok {
+		previous = rec
+	}
+	now := time.Now()
+	reviewers := make(map[string]time.Time, len(pr.AssignedReviewers))
+	shadows := make(map[string]time.Time, len(pr.ShadowReviewers))
+	for _, a := range pr.Assignments() {
+		if _, ok := t.users[a.UserId]; !ok {
+			return fmt.Errorf("insert pull_request_reviewer (%s): user does not exist", a.UserId)
+		}
+		at := now
+		if previous != nil {
+			if last, ok := previous.activity(a.UserId); ok {
+				at = last
+			}
+		}
+		if a.Role == models.ReviewerRoleSHADOW {
+			shadows[a.UserId] = at
+		} else {
+			reviewers[a.UserId] = at
+		}
+	}
+
+	t.prs[pr.PullRequestId] = newPullRequestRecord(pr, reviewers, shadows)
+	return nil
+}
+
+// GetPullRequest возвращает PR по идентификатору.
+func (s *Storage) GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	rec, ok := t.prs[prID]
+	if !ok {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("pull request %s", prID))
+	}
+	return rec.toModel(), nil
+}
+
+// FindPullRequestsByReviewer возвращает PR ревьюера, новые — первыми.
+func (s *Storage) FindPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	var result []*models.PullRequest
+	for _, rec := range t.prs {
+		if _, ok := rec.activity(reviewerID); ok {
+			result = append(result, rec.toModel())
+		}
+	}
+	sortByCreatedDesc(result)
+	return result, nil
+}
+
+// GetAssignmentStats считает назначения по пользователям, PR и командам для PR, попавших в фильтр.
+func (s *Storage) GetAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	stats := &models.AssignmentStats{}
+	counts := make(map[string]int)
+	weights := make(map[string]int)
+	shadowCounts := make(map[string]int)
+	teams := make(map[string]*models.TeamAssignmentStat)
+	reviewerSums := make(map[string]int)
+	for _, rec := range t.prs {
+		team := t.users[rec.authorID].TeamName
+		if !matchAssignmentFilter(rec, team, filter) {
+			continue
+		}
+		for r := range rec.reviewers {
+			counts[r]++
+			weights[r] += rec.reviewWeight()
+		}
+		for r := range rec.shadows {
+			shadowCounts[r]++
+		}
+		stats.ByPullRequest = append(stats.ByPullRequest, models.PullRequestAssignmentStat{
+			PullRequestId:   rec.id,
+			PullRequestName: rec.name,
+			ReviewerCount:   len(rec.reviewers),
+			ShadowCount:     len(rec.shadows),
+		})
+
+		if team == "" {
+			continue
+		}
+		ts, ok := teams[team]
+		if !ok {
+			ts = &models.TeamAssignmentStat{TeamName: team}
+			teams[team] = ts
+		}
+		switch rec.status {
+		case models.PullRequestStatusOPEN:
+			ts.OpenCount++
+		case models.PullRequestStatusMERGED:
+			ts.MergedCount++
+		}
+		reviewerSums[team] += len(rec.reviewers)
+	}
+	// Пользователь только с теневыми ревью тоже попадает в ByUser — с нулём основных назначений.
+	for userID := range shadowCounts {
+		if _, ok := counts[userID]; !ok {
+			counts[userID] = 0
+		}
+	}
+	for userID, count := range counts {
+		stats.ByUser = append(stats.ByUser, models.UserAssignmentStat{
+			UserId:            userID,
+			Username:          t.users[userID].Username,
+			Assignments:       count,
+			WeightedLoad:      weights[userID],
+			ShadowAssignments: shadowCounts[userID],
+		})
+	}
+
+	// Нагрузка считается по всем активным участникам, включая тех, у кого нет назначений.
+	loads := make(map[string][]int)
+	for _, user := range t.users {
+		if user.IsActive && user.TeamName != "" {
+			loads[user.TeamName] = append(loads[user.TeamName], counts[user.UserId])
+		}
+	}
+	for team, ts := range teams {
+		total := ts.OpenCount + ts.MergedCount
+		ts.AvgReviewers = float64(reviewerSums[team]) / float64(total)
+		ts.LoadImbalance = models.LoadImbalance(loads[team])
+		stats.ByTeam = append(stats.ByTeam, *ts)
+	}
+
+	sort.Slice(stats.ByUser, func(i, j int) bool {
+		if stats.ByUser[i].Assignments != stats.ByUser[j].Assignments {
+			return stats.ByUser[i].Assignments > stats.ByUser[j].Assignments
+		}
+		return stats.ByUser[i].UserId < stats.ByUser[j].UserId
+	})
+	sort.Slice(stats.ByPullRequest, func(i, j int) bool {
+		if stats.ByPullRequest[i].ReviewerCount != stats.ByPullRequest[j].ReviewerCount {
+			return stats.ByPullRequest[i].ReviewerCount > stats.ByPullRequest[j].ReviewerCount
+		}
+		return stats.ByPullRequest[i].PullRequestId < stats.ByPullRequest[j].PullRequestId
+	})
+	sort.Slice(stats.ByTeam, func(i, j int) bool {
+		return stats.ByTeam[i].TeamName < stats.ByTeam[j].TeamName
+	})
+	if filter.Limit > 0 {
+		stats.ByUser = stats.ByUser[:min(filter.Limit, len(stats.ByUser))]
+		stats.ByPullRequest = stats.ByPullRequest[:min(filter.Limit, len(stats.ByPullRequest))]
+	}
+	return stats, nil
+}
+
+// matchAssignmentFilter повторяет условия WHERE статистики назначений PostgreSQL-хранилища.
+func matchAssignmentFilter(rec *pullRequestRecord, team string, filter models.AssignmentStatsFilter) bool {
+	if filter.TeamName != "" && team != filter.TeamName {
+		return false
+	}
+	if filter.Repository != "" && rec.repository != filter.Repository {
+		return false
+	}
+	if filter.Status != "" && rec.status != filter.Status {
+		return false
+	}
+	if !filter.From.IsZero() && (rec.createdAt == nil || rec.createdAt.Before(filter.From)) {
+		return false
+	}
+	if !filter.To.IsZero() && (rec.createdAt == nil || !rec.createdAt.Before(filter.To)) {
+		return false
+	}
+	return true
+}
+
+// StreamPullRequestsByReviewer передаёт в fn PR ревьюера; снимок берётся под блокировкой, fn вызывается без неё.
+func (s *Storage) StreamPullRequestsByReviewer(ctx context.Context, reviewerID string, fn func(*models.PullRequest) error) error {
+	prs, err := s.FindPullRequestsByReviewer(ctx, reviewerID)
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs {
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamUserAssignments передаёт в fn строки ByUser статистики назначений.
+func (s *Storage) StreamUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+	stats, err := s.GetAssignmentStats(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, stat := range stats.ByUser {
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamPullRequestAssignments передаёт в fn строки ByPullRequest статистики назначений.
+func (s *Storage) StreamPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+	stats, err := s.GetAssignmentStats(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, stat := range stats.ByPullRequest {
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTeamAssignmentStats возвращает агрегаты по командам из статистики назначений.
+func (s *Storage) GetTeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+	stats, err := s.GetAssignmentStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return stats.ByTeam, nil
+}
+
+// GetTurnaroundStats считает перцентили времени до слияния PR, слитых в [From, To),
+// тем же методом ближайшего ранга, что percentile_disc в PostgreSQL.
+func (s *Storage) GetTurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	type groupKey struct{ dim, key string }
+	groups := make(map[groupKey][]float64)
+	add := func(dim, key string, secs float64) {
+		k := groupKey{dim, key}
+		groups[k] = append(groups[k], secs)
+	}
+
+	for _, rec := range t.prs {
+		if rec.status != models.PullRequestStatusMERGED || rec.createdAt == nil || rec.mergedAt == nil {
+			continue
+		}
+		if rec.mergedAt.Before(filter.From) || !rec.mergedAt.Before(filter.To) {
+			continue
+		}
+		if filter.Repository != "" && rec.repository != filter.Repository {
+			continue
+		}
+		secs := rec.mergedAt.Sub(*rec.createdAt).Seconds()
+		add(models.TurnaroundDimOverall, "", secs)
+		add(models.TurnaroundDimAuthor, rec.authorID, secs)
+		if author, ok := t.users[rec.authorID]; ok && author.TeamName != "" {
+			add(models.TurnaroundDimTeam, author.TeamName, secs)
+		}
+		for r := range rec.reviewers {
+			add(models.TurnaroundDimReviewer, r, secs)
+		}
+	}
+
+	keys := make([]groupKey, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].dim != keys[j].dim {
+			return keys[i].dim < keys[j].dim
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	stats := &models.TurnaroundStats{From: filter.From, To: filter.To}
+	for _, k := range keys {
+		values := groups[k]
+		sort.Float64s(values)
+		stats.AddGroup(k.dim, k.key, models.TurnaroundPercentiles{
+			Count:      len(values),
+			P50Seconds: nearestRank(values, 0.5),
+			P90Seconds: nearestRank(values, 0.9),
+			P99Seconds: nearestRank(values, 0.99),
+		})
+	}
+	return stats, nil
+}
+
+// FindOpenPullRequestsByReviewers ищет открытые PR, где назначен хотя бы один из ревьюеров.
+func (s *Storage) FindOpenPullRequestsByReviewers(ctx context.Context, reviewerIDs []string) ([]*models.PullRequest, error) {
+	targets := make(map[string]struct{}, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		if id != "" {
+			targets[id] = struct{}{}
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	var result []*models.PullRequest
+	for _, rec := range t.prs {
+		if rec.status != models.PullRequestStatusOPEN {
+			continue
+		}
+		for r := range rec.reviewers {
+			if _, ok := targets[r]; ok {
+				result = append(result, rec.toModel())
+				break
+			}
+		}
+	}
+	sortByCreatedDesc(result)
+	return result, nil
+}
+
+// ApplyBulkTeamReviewerSwaps атомарно заменяет ревьюеров и деактивирует пользователей: при ошибке состояние не меняется.
+func (s *Storage) ApplyBulkTeamReviewerSwaps(ctx context.Context, swaps []models.ReviewerSwap, usersToDeactivate []string) error {
+	if len(swaps) == 0 && len(usersToDeactivate) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	// Применяем замены к копиям множеств ревьюеров и подменяем их только после успешной проверки всех замен.
+	now := time.Now()
+	staged := make(map[string]map[string]time.Time)
+	for _, swap := range swaps {
+		if swap.PullRequestId == "" || swap.OldUserId == "" || swap.NewUserId == "" {
+			return fmt.Errorf("invalid reviewer swap payload: %+v", swap)
+		}
+		reviewers, ok := staged[swap.PullRequestId]
+		if !ok {
+			rec, exists := t.prs[swap.PullRequestId]
+			if !exists {
+				return fmt.Errorf("insert reviewer %s for pr %s: pull request does not exist", swap.NewUserId, swap.PullRequestId)
+			}
+			reviewers = make(map[string]time.Time, len(rec.reviewers))
+			for r, at := range rec.reviewers {
+				reviewers[r] = at
+			}
+			staged[swap.PullRequestId] = reviewers
+		}
+		if _, ok := t.users[swap.NewUserId]; !ok {
+			return fmt.Errorf("insert reviewer %s for pr %s: user does not exist", swap.NewUserId, swap.PullRequestId)
+		}
+		delete(reviewers, swap.OldUserId)
+		if _, shadow := t.prs[swap.PullRequestId].shadows[swap.NewUserId]; shadow {
+			return fmt.Errorf("insert reviewer %s for pr %s: reviewer already assigned", swap.NewUserId, swap.PullRequestId)
+		}
+		if _, dup := reviewers[swap.NewUserId]; dup {
+			return fmt.Errorf("insert reviewer %s for pr %s: reviewer already assigned", swap.NewUserId, swap.PullRequestId)
+		}
+		reviewers[swap.NewUserId] = now
+	}
+
+	for prID, reviewers := range staged {
+		t.prs[prID].reviewers = reviewers
+	}
+	for _, id := range usersToDeactivate {
+		if user, ok := t.users[id]; ok {
+			user.IsActive = false
+			t.users[id] = user
+		}
+	}
+	return nil
+}
+
+// ---------- зависшие ревью ----------
+
+// FindOpenReviewAssignments возвращает назначения ревьюеров на открытые PR с числом уже выполненных замен;
+// теневые ревьюеры не торопят ревью и не попадают в выборку.
+func (s *Storage) FindOpenReviewAssignments(ctx context.Context) ([]models.ReviewAssignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	counts := make(map[string]int)
+	for _, r := range t.rotations {
+		counts[r.PullRequestId]++
+	}
+
+	var result []models.ReviewAssignment
+	for _, rec := range t.prs {
+		if rec.status != models.PullRequestStatusOPEN {
+			continue
+		}
+		team := ""
+		if author, ok := t.users[rec.authorID]; ok {
+			team = author.TeamName
+		}
+		for reviewer, at := range rec.reviewers {
+			result = append(result, models.ReviewAssignment{
+				PullRequestId:  rec.id,
+				ReviewerId:     reviewer,
+				TeamName:       team,
+				LastActivityAt: at,
+				Rotations:      counts[rec.id],
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if !a.LastActivityAt.Equal(b.LastActivityAt) {
+			return a.LastActivityAt.Before(b.LastActivityAt)
+		}
+		if a.PullRequestId != b.PullRequestId {
+			return a.PullRequestId < b.PullRequestId
+		}
+		return a.ReviewerId < b.ReviewerId
+	})
+	return result, nil
+}
+
+// TouchReviewActivity обновляет отметку активности ревьюера; NOT_ASSIGNED, если он не назначен на PR.
+func (s *Storage) TouchReviewActivity(ctx context.Context, prID, userID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	rec, ok := t.prs[prID]
+	if !ok {
+		return domain.NewNotAssignedError(prID)
+	}
+	if _, assigned := rec.reviewers[userID]; assigned {
+		rec.reviewers[userID] = at
+		return nil
+	}
+	if _, shadow := rec.shadows[userID]; shadow {
+		rec.shadows[userID] = at
+		return nil
+	}
+	return domain.NewNotAssignedError(prID)
+}
+
+// RecordReviewRotation добавляет запись в историю автоматических замен.
+func (s *Storage) RecordReviewRotation(ctx context.Context, rotation *models.ReviewRotation) error {
+	if rotation == nil {
+		return fmt.Errorf("rotation is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.prs[rotation.PullRequestId]; !ok {
+		return fmt.Errorf("insert review rotation: pull request %s does not exist", rotation.PullRequestId)
+	}
+	t.rotations = append(t.rotations, *rotation)
+	return nil
+}
+
+// ListReviewRotations возвращает историю замен, новые записи первыми.
+func (s *Storage) ListReviewRotations(ctx context.Context, filter models.ReviewRotationFilter) ([]models.ReviewRotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.ReviewRotation, 0)
+	// Обход с конца даёт порядок по убыванию вставки, как ORDER BY id DESC.
+	for i := len(t.rotations) - 1; i >= 0; i-- {
+		r := t.rotations[i]
+		if filter.PullRequestId != "" && r.PullRequestId != filter.PullRequestId {
+			continue
+		}
+		result = append(result, r)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].RotatedAt.After(result[j].RotatedAt) })
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// AcquireLease захватывает аренду, если она свободна, истекла или уже принадлежит holder.
+func (s *Storage) AcquireLease(_ context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if cur, ok := s.leases[name]; ok && cur.holder != holder && !cur.expiresAt.Before(now) {
+		return false, nil
+	}
+	s.leases[name] = lease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// ---------- отсутствия ----------
+
+// CreateAbsence сохраняет период отсутствия и заполняет AbsenceId.
+func (s *Storage) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	if absence == nil {
+		return fmt.Errorf("absence is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.users[absence.UserId]; !ok {
+		return fmt.Errorf("insert absence: user %s does not exist", absence.UserId)
+	}
+	if !absence.EndsAt.After(absence.StartsAt) {
+		return fmt.Errorf("insert absence: ends_at must be after starts_at")
+	}
+	s.lastAbsenceID++
+	absence.AbsenceId = s.lastAbsenceID
+	t.absences[absence.AbsenceId] = cloneAbsence(*absence)
+	return nil
+}
+
+// GetAbsence возвращает период отсутствия по идентификатору.
+func (s *Storage) GetAbsence(ctx context.Context, absenceID int64) (*models.Absence, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	a, ok := t.absences[absenceID]
+	if !ok {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	a = cloneAbsence(a)
+	return &a, nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя в порядке начала.
+func (s *Storage) ListAbsences(ctx context.Context, userID string) ([]models.Absence, error) {
+	return s.filterAbsences(ctx, func(a models.Absence) bool { return a.UserId == userID }), nil
+}
+
+// DeleteAbsence удаляет период отсутствия; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.absences[absenceID]; !ok {
+		return domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	delete(t.absences, absenceID)
+	return nil
+}
+
+// FindAbsentUsers возвращает пользователей, чей период отсутствия покрывает момент at.
+func (s *Storage) FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	seen := make(map[string]struct{})
+	var result []string
+	for _, a := range t.absences {
+		if _, dup := seen[a.UserId]; dup || !a.Covers(at) {
+			continue
+		}
+		seen[a.UserId] = struct{}{}
+		result = append(result, a.UserId)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// FindAbsencesToReassign возвращает идущие в момент at периоды, ревью по которым ещё не переданы.
+func (s *Storage) FindAbsencesToReassign(ctx context.Context, at time.Time) ([]models.Absence, error) {
+	return s.filterAbsences(ctx, func(a models.Absence) bool { return a.ReassignedAt == nil && a.Covers(at) }), nil
+}
+
+// MarkAbsenceReassigned отмечает, что ревью отсутствующего переданы коллегам.
+func (s *Storage) MarkAbsenceReassigned(ctx context.Context, absenceID int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	a, ok := t.absences[absenceID]
+	if !ok {
+		return domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	a.ReassignedAt = &at
+	t.absences[absenceID] = a
+	return nil
+}
+
+// filterAbsences возвращает копии подходящих периодов в порядке начала, как ORDER BY starts_at, absence_id.
+func (s *Storage) filterAbsences(ctx context.Context, match func(models.Absence) bool) []models.Absence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.Absence, 0)
+	for _, a := range t.absences {
+		if match(a) {
+			result = append(result, cloneAbsence(a))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartsAt.Equal(result[j].StartsAt) {
+			return result[i].StartsAt.Before(result[j].StartsAt)
+		}
+		return result[i].AbsenceId < result[j].AbsenceId
+	})
+	return result
+}
+
+// cloneAbsence копирует период вместе с указателем ReassignedAt.
+func cloneAbsence(a models.Absence) models.Absence {
+	a.ReassignedAt = cloneTime(a.ReassignedAt)
+	return a
+}
+
+// ---------- рабочее время ----------
+
+// SaveWorkingHours создаёт или заменяет рабочее время пользователя.
+func (s *Storage) SaveWorkingHours(ctx context.Context, wh *models.WorkingHours) error {
+	if wh == nil {
+		return fmt.Errorf("working hours is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.users[wh.UserId]; !ok {
+		return fmt.Errorf("save working hours: user %s does not exist", wh.UserId)
+	}
+	t.workingHours[wh.UserId] = *wh
+	return nil
+}
+
+// GetWorkingHours возвращает рабочее время пользователя; NOT_FOUND, если оно не задано.
+func (s *Storage) GetWorkingHours(ctx context.Context, userID string) (*models.WorkingHours, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	wh, ok := t.workingHours[userID]
+	if !ok {
+		return nil, domain.NewNotFoundError("working hours of " + userID)
+	}
+	return &wh, nil
+}
+
+// DeleteWorkingHours удаляет рабочее время пользователя; NOT_FOUND, если его нет.
+func (s *Storage) DeleteWorkingHours(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.workingHours[userID]; !ok {
+		return domain.NewNotFoundError("working hours of " + userID)
+	}
+	delete(t.workingHours, userID)
+	return nil
+}
+
+// FindWorkingHours возвращает рабочее время перечисленных пользователей; у кого его нет, пропускаются.
+func (s *Storage) FindWorkingHours(ctx context.Context, userIDs []string) ([]models.WorkingHours, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.WorkingHours, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		if wh, ok := t.workingHours[id]; ok {
+			result = append(result, wh)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserId < result[j].UserId })
+	return result, nil
+}
+
+// ---------- нагрузка ревьюеров ----------
+
+// SaveCapacity создаёт или заменяет личный лимит открытых ревью пользователя.
+func (s *Storage) SaveCapacity(ctx context.Context, userID string, maxOpenReviews int) error {
+	if maxOpenReviews <= 0 {
+		return fmt.Errorf("save capacity: max_open_reviews must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.users[userID]; !ok {
+		return fmt.Errorf("save capacity: user %s does not exist", userID)
+	}
+	t.capacities[userID] = maxOpenReviews
+	return nil
+}
+
+// DeleteCapacity удаляет личный лимит пользователя; отсутствие лимита ошибкой не считается.
+func (s *Storage) DeleteCapacity(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	delete(t.capacities, userID)
+	return nil
+}
+
+// FindReviewLoad возвращает число и суммарный вес открытых ревью и личный лимит (0 — не задан) перечисленных пользователей;
+// несуществующие пользователи пропускаются.
+func (s *Storage) FindReviewLoad(ctx context.Context, userIDs []string) ([]models.ReviewerLoad, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.ReviewerLoad, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		if _, ok := t.users[id]; !ok {
+			continue
+		}
+		load := models.ReviewerLoad{UserId: id, MaxOpenReviews: t.capacities[id]}
+		for _, pr := range t.prs {
+			if _, assigned := pr.reviewers[id]; assigned && pr.status == models.PullRequestStatusOPEN {
+				load.OpenReviews++
+				load.WeightedLoad += pr.reviewWeight()
+			}
+		}
+		result = append(result, load)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserId < result[j].UserId })
+	return result, nil
+}
+
+// CountOpenReviews возвращает число открытых ревью каждого, кто назначался ревьюером; без открытых ревью — 0.
+func (s *Storage) CountOpenReviews(ctx context.Context) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make(map[string]int)
+	for _, pr := range t.prs {
+		open := 0
+		if pr.status == models.PullRequestStatusOPEN {
+			open = 1
+		}
+		for id := range pr.reviewers {
+			result[id] += open
+		}
+	}
+	return result, nil
+}
+
+// EnqueueReview ставит PR в очередь на ревьюеров или обновляет число недостающих.
+func (s *Storage) EnqueueReview(ctx context.Context, item models.QueuedReview) error {
+	if item.Missing <= 0 {
+		return fmt.Errorf("enqueue review: missing must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.prs[item.PullRequestId]; !ok {
+		return fmt.Errorf("enqueue review: pull request %s does not exist", item.PullRequestId)
+	}
+	if queued, ok := t.reviewQueue[item.PullRequestId]; ok {
+		item.QueuedAt = queued.QueuedAt
+	}
+	t.reviewQueue[item.PullRequestId] = item
+	return nil
+}
+
+// ListQueuedReviews возвращает очередь в порядке постановки.
+func (s *Storage) ListQueuedReviews(ctx context.Context) ([]models.QueuedReview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	return t.sortedReviewQueue(), nil
+}
+
+// sortedReviewQueue возвращает очередь в порядке постановки; вызывается под блокировкой.
+func (t *tenantData) sortedReviewQueue() []models.QueuedReview {
+	result := make([]models.QueuedReview, 0, len(t.reviewQueue))
+	for _, item := range t.reviewQueue {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].QueuedAt.Equal(result[j].QueuedAt) {
+			return result[i].QueuedAt.Before(result[j].QueuedAt)
+		}
+		return result[i].PullRequestId < result[j].PullRequestId
+	})
+	return result
+}
+
+// DequeueReview убирает PR из очереди; если его там нет, ничего не делает.
+func (s *Storage) DequeueReview(ctx context.Context, prID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	delete(t.reviewQueue, prID)
+	return nil
+}
+
+// ---------- репозитории ----------
+
+// SaveRepository создаёт репозиторий или заменяет его владельца и правила ревью.
+func (s *Storage) SaveRepository(ctx context.Context, repo *models.Repository) error {
+	if repo == nil {
+		return fmt.Errorf("repository is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if err := t.checkTeamRef(repo.OwnerTeam); err != nil {
+		return fmt.Errorf("save repository: %w", err)
+	}
+	t.repositories[repo.RepositoryName] = *repo
+	return nil
+}
+
+// GetRepository возвращает репозиторий по имени; NOT_FOUND, если его нет.
+func (s *Storage) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	repo, ok := t.repositories[name]
+	if !ok {
+		return nil, domain.NewNotFoundError("repository " + name)
+	}
+	return &repo, nil
+}
+
+// ListRepositories возвращает все репозитории по имени.
+func (s *Storage) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+	return t.sortedRepositories(), nil
+}
+
+// sortedRepositories копирует репозитории в порядке имени; вызывается под блокировкой.
+func (t *tenantData) sortedRepositories() []models.Repository {
+	result := make([]models.Repository, 0, len(t.repositories))
+	for _, repo := range t.repositories {
+		result = append(result, repo)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RepositoryName < result[j].RepositoryName })
+	return result
+}
+
+// defaultRepositories возвращает набор репозиториев пустой базы: только default с правилами по умолчанию.
+func defaultRepositories() map[string]models.Repository {
+	return map[string]models.Repository{
+		models.DefaultRepository: {RepositoryName: models.DefaultRepository, RequiredReviewers: maxReviewers},
+	}
+}
+
+// checkPullRequestRefs повторяет внешний ключ pull_requests.repository и уникальность (repository, number).
+func checkPullRequestRefs(pr *models.PullRequest, repositories map[string]models.Repository, prs map[string]*pullRequestRecord) error {
+	repo := models.RepositoryOrDefault(pr.Repository)
+	if _, ok := repositories[repo]; !ok {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	if pr.Number <= 0 {
+		return nil
+	}
+	for id, rec := range prs {
+		if id != pr.PullRequestId && rec.repository == repo && rec.number == pr.Number {
+			return fmt.Errorf("pull request %s already has number %d in %s", id, pr.Number, repo)
+		}
+	}
+	return nil
+}
+
+// ---------- правила подбора ревьюеров ----------
+
+// CreateAffinityRule сохраняет правило подбора ревьюеров и заполняет RuleId.
+func (s *Storage) CreateAffinityRule(ctx context.Context, rule *models.AffinityRule) error {
+	if rule == nil {
+		return fmt.Errorf("affinity rule is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.teams[rule.TeamName]; !ok {
+		return fmt.Errorf("insert affinity rule: team %s does not exist", rule.TeamName)
+	}
+	if _, ok := t.users[rule.UserId]; !ok {
+		return fmt.Errorf("insert affinity rule: user %s does not exist", rule.UserId)
+	}
+	if len(rule.WithUserIds) == 0 {
+		return fmt.Errorf("insert affinity rule: with_user_ids must not be empty")
+	}
+	s.lastRuleID++
+	rule.RuleId = s.lastRuleID
+	saved := *rule
+	saved.WithUserIds = slices.Clone(rule.WithUserIds)
+	t.affinityRules[rule.RuleId] = saved
+	return nil
+}
+
+// DeleteAffinityRule удаляет правило; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAffinityRule(ctx context.Context, ruleID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.affinityRules[ruleID]; !ok {
+		return domain.NewNotFoundError(fmt.Sprintf("affinity rule %d", ruleID))
+	}
+	delete(t.affinityRules, ruleID)
+	return nil
+}
+
+// FindAffinityRules возвращает копии правил команды в порядке создания.
+func (s *Storage) FindAffinityRules(ctx context.Context, teamName string) ([]models.AffinityRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.AffinityRule, 0)
+	for _, r := range t.affinityRules {
+		if r.TeamName == teamName {
+			r.WithUserIds = slices.Clone(r.WithUserIds)
+			result = append(result, r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RuleId < result[j].RuleId })
+	return result, nil
+}
+
+// ---------- стажёры ----------
+
+// SetTeamLearners заменяет список стажёров команды.
+func (s *Storage) SetTeamLearners(ctx context.Context, teamName string, userIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.teams[teamName]; !ok {
+		return fmt.Errorf("insert team learners: team %s does not exist", teamName)
+	}
+	learners := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := t.users[id]; !ok {
+			return fmt.Errorf("insert team learners: user %s does not exist", id)
+		}
+		learners[id] = struct{}{}
+	}
+	t.learners[teamName] = learners
+	return nil
+}
+
+// FindTeamLearners возвращает стажёров команды по user_id с числом их открытых теневых ревью.
+func (s *Storage) FindTeamLearners(ctx context.Context, teamName string) ([]models.Learner, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.Learner, 0, len(t.learners[teamName]))
+	for id := range t.learners[teamName] {
+		learner := models.Learner{UserId: id}
+		for _, pr := range t.prs {
+			if _, shadow := pr.shadows[id]; shadow && pr.status == models.PullRequestStatusOPEN {
+				learner.OpenShadowReviews++
+			}
+		}
+		result = append(result, learner)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserId < result[j].UserId })
+	return result, nil
+}
+
+// ---------- решения о назначении ----------
+
+// SaveAssignmentDecision сохраняет копию решения о назначении ревьюеров и заполняет DecisionId.
+func (s *Storage) SaveAssignmentDecision(ctx context.Context, decision *models.AssignmentDecision) error {
+	if decision == nil {
+		return fmt.Errorf("assignment decision is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if _, ok := t.prs[decision.PullRequestId]; !ok {
+		return fmt.Errorf("insert assignment decision: pull request %s does not exist", decision.PullRequestId)
+	}
+	s.lastDecisionID++
+	decision.DecisionId = s.lastDecisionID
+	t.decisions = append(t.decisions, cloneDecision(*decision))
+	return nil
+}
+
+// FindAssignmentDecisions возвращает копии решений о назначении ревьюеров PR, старые первыми.
+func (s *Storage) FindAssignmentDecisions(ctx context.Context, prID string) ([]models.AssignmentDecision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	result := make([]models.AssignmentDecision, 0)
+	for _, d := range t.decisions {
+		if d.PullRequestId == prID {
+			result = append(result, cloneDecision(d))
+		}
+	}
+	return result, nil
+}
+
+// ---------- организации ----------
+
+// CreateOrganization создаёт организацию с её токеном и репозиторием default; ORG_EXISTS, если она уже есть.
+func (s *Storage) CreateOrganization(_ context.Context, org *models.Organization, tokenHash string) error {
+	if org == nil {
+		return fmt.Errorf("organization is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.organizations[org.OrganizationId]; exists {
+		return domain.NewOrgExistsError(org.OrganizationId)
+	}
+	if _, dup := s.tokens[tokenHash]; dup {
+		return fmt.Errorf("insert organization token: duplicate key")
+	}
+	s.organizations[org.OrganizationId] = *org
+	s.tokens[tokenHash] = org.OrganizationId
+	s.tenants[org.OrganizationId] = newTenantData()
+	return nil
+}
+
+// ListOrganizations возвращает все организации по идентификатору.
+func (s *Storage) ListOrganizations(_ context.Context) ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Organization, 0, len(s.organizations))
+	for _, org := range s.organizations {
+		result = append(result, org)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OrganizationId < result[j].OrganizationId })
+	return result, nil
+}
+
+// FindOrganizationByToken возвращает организацию, которой выдан токен с хешем tokenHash; NOT_FOUND, если такого нет.
+func (s *Storage) FindOrganizationByToken(_ context.Context, tokenHash string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organizationID, ok := s.tokens[tokenHash]
+	if !ok {
+		return "", domain.NewNotFoundError("organization token")
+	}
+	return organizationID, nil
+}
+
+// ---------- архив состояния ----------
+
+// ExportSnapshot возвращает копию состояния организации из контекста.
+func (s *Storage) ExportSnapshot(ctx context.Context) (*models.Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tenant(ctx)
+
+	snap := &models.Snapshot{
+		Version:      models.SnapshotVersion,
+		Teams:        make([]models.SnapshotTeam, 0, len(t.teams)),
+		Users:        make([]models.User, 0, len(t.users)),
+		PullRequests: make([]*models.PullRequest, 0, len(t.prs)),
+	}
+	for name := range t.teams {
+		snap.Teams = append(snap.Teams, models.SnapshotTeam{TeamName: name})
+	}
+	sort.Slice(snap.Teams, func(i, j int) bool { return snap.Teams[i].TeamName < snap.Teams[j].TeamName })
+	for _, user := range t.users {
+		snap.Users = append(snap.Users, user)
+	}
+	sort.Slice(snap.Users, func(i, j int) bool { return snap.Users[i].UserId < snap.Users[j].UserId })
+	snap.Repositories = t.sortedRepositories()
+	snap.ReviewRotations = slices.Clone(t.rotations)
+	for _, a := range t.absences {
+		snap.Absences = append(snap.Absences, cloneAbsence(a))
+	}
+	sort.Slice(snap.Absences, func(i, j int) bool { return snap.Absences[i].AbsenceId < snap.Absences[j].AbsenceId })
+	for _, wh := range t.workingHours {
+		snap.WorkingHours = append(snap.WorkingHours, wh)
+	}
+	sort.Slice(snap.WorkingHours, func(i, j int) bool { return snap.WorkingHours[i].UserId < snap.WorkingHours[j].UserId })
+	for userID, maxOpen := range t.capacities {
+		snap.ReviewCapacities = append(snap.ReviewCapacities, models.SnapshotCapacity{UserId: userID, MaxOpenReviews: maxOpen})
+	}
+	sort.Slice(snap.ReviewCapacities, func(i, j int) bool { return snap.ReviewCapacities[i].UserId < snap.ReviewCapacities[j].UserId })
+	if len(t.reviewQueue) > 0 {
+		snap.ReviewQueue = t.sortedReviewQueue()
+	}
+	for _, r := range t.affinityRules {
+		r.WithUserIds = slices.Clone(r.WithUserIds)
+		snap.AffinityRules = append(snap.AffinityRules, r)
+	}
+	sort.Slice(snap.AffinityRules, func(i, j int) bool { return snap.AffinityRules[i].RuleId < snap.AffinityRules[j].RuleId })
+	for teamName, learners := range t.learners {
+		for userID := range learners {
+			snap.TeamLearners = append(snap.TeamLearners, models.SnapshotLearner{TeamName: teamName, UserId: userID})
+		}
+	}
+	for _, d := range t.decisions {
+		snap.AssignmentDecisions = append(snap.AssignmentDecisions, cloneDecision(d))
+	}
+	sort.Slice(snap.TeamLearners, func(i, j int) bool {
+		a, b := snap.TeamLearners[i], snap.TeamLearners[j]
+		return a.TeamName < b.TeamName || a.TeamName == b.TeamName && a.UserId < b.UserId
+	})
+	for _, rec := range t.prs {
+		pr := rec.toModel()
+		if pr.AssignedReviewers == nil {
+			pr.AssignedReviewers = []string{}
+		}
+		snap.PullRequests = append(snap.PullRequests, pr)
+	}
+	sort.Slice(snap.PullRequests, func(i, j int) bool {
+		return snap.PullRequests[i].PullRequestId < snap.PullRequests[j].PullRequestId
+	})
+	return snap, nil
+}
+
+// RestoreSnapshot загружает архив в пустую организацию из контекста; при ошибке ссылок состояние не меняется.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *models.Snapshot) error {
+	if snap == nil {
+		return fmt.Errorf("snapshot is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(ctx)
+
+	if len(t.teams) > 0 || len(t.users) > 0 || len(t.prs) > 0 || len(t.rotations) > 0 || len(t.absences) > 0 || len(t.workingHours) > 0 ||
+		len(t.capacities) > 0 || len(t.reviewQueue) > 0 || len(t.affinityRules) > 0 || len(t.learners) > 0 || len(t.decisions) > 0 {
+		return domain.NewNotEmptyError("database")
+	}
+
+	teams := make(map[string]struct{}, len(snap.Teams))
+	for _, team := range snap.Teams {
+		if _, dup := teams[team.TeamName]; dup {
+			return fmt.Errorf("insert team %s: duplicate key", team.TeamName)
+		}
+		teams[team.TeamName] = struct{}{}
+	}
+	users := make(map[string]models.User, len(snap.Users))
+	for _, user := range snap.Users {
+		if _, dup := users[user.UserId]; dup {
+			return fmt.Errorf("insert user %s: duplicate key", user.UserId)
+		}
+		if _, ok := teams[user.TeamName]; user.TeamName != "" && !ok {
+			return fmt.Errorf("insert user %s: team %s does not exist", user.UserId, user.TeamName)
+		}
+		users[user.UserId] = user
+	}
+	repositories := defaultRepositories()
+	for _, repo := range snap.Repositories {
+		if _, ok := teams[repo.OwnerTeam]; repo.OwnerTeam != "" && !ok {
+			return fmt.Errorf("upsert repository %s: team %s does not exist", repo.RepositoryName, repo.OwnerTeam)
+		}
+		repositories[repo.RepositoryName] = repo
+	}
+	now := time.Now()
+	prs := make(map[string]*pullRequestRecord, len(snap.PullRequests))
+	for _, pr := range snap.PullRequests {
+		if _, dup := prs[pr.PullRequestId]; dup {
+			return fmt.Errorf("insert pull request %s: duplicate key", pr.PullRequestId)
+		}
+		if _, ok := users[pr.AuthorId]; !ok {
+			return fmt.Errorf("insert pull request %s: author %s does not exist", pr.PullRequestId, pr.AuthorId)
+		}
+		if err := checkPullRequestRefs(pr, repositories, prs); err != nil {
+			return fmt.Errorf("insert pull request %s: %w", pr.PullRequestId, err)
+		}
+		reviewers := make(map[string]time.Time, len(pr.AssignedReviewers))
+		shadows := make(map[string]time.Time, len(pr.ShadowReviewers))
+		for _, a := range pr.Assignments() {
+			if _, ok := users[a.UserId]; !ok {
+				return fmt.Errorf("insert reviewer %s of %s: user does not exist", a.UserId, pr.PullRequestId)
+			}
+			if a.Role == models.ReviewerRoleSHADOW {
+				shadows[a.UserId] = now
+			} else {
+				reviewers[a.UserId] = now
+			}
+		}
+		prs[pr.PullRequestId] = newPullRequestRecord(pr, reviewers, shadows)
+	}
+	for _, r := range snap.ReviewRotations {
+		if _, ok := prs[r.PullRequestId]; !ok {
+			return fmt.Errorf("insert review rotation: pull request %s does not exist", r.PullRequestId)
+		}
+	}
+	for _, a := range snap.Absences {
+		if _, ok := users[a.UserId]; !ok {
+			return fmt.Errorf("insert absence: user %s does not exist", a.UserId)
+		}
+		if !a.EndsAt.After(a.StartsAt) {
+			return fmt.Errorf("insert absence of %s: ends_at must be after starts_at", a.UserId)
+		}
+	}
+	workingHours := make(map[string]models.WorkingHours, len(snap.WorkingHours))
+	for _, wh := range snap.WorkingHours {
+		if _, ok := users[wh.UserId]; !ok {
+			return fmt.Errorf("insert working hours: user %s does not exist", wh.UserId)
+		}
+		if _, dup := workingHours[wh.UserId]; dup {
+			return fmt.Errorf("insert working hours of %s: duplicate key", wh.UserId)
+		}
+		workingHours[wh.UserId] = wh
+	}
+	capacities := make(map[string]int, len(snap.ReviewCapacities))
+	for _, c := range snap.ReviewCapacities {
+		if _, ok := users[c.UserId]; !ok {
+			return fmt.Errorf("insert review capacity: user %s does not exist", c.UserId)
+		}
+		if _, dup := capacities[c.UserId]; dup {
+			return fmt.Errorf("insert review capacity of %s: duplicate key", c.UserId)
+		}
+		if c.MaxOpenReviews <= 0 {
+			return fmt.Errorf("insert review capacity of %s: max_open_reviews must be positive", c.UserId)
+		}
+		capacities[c.UserId] = c.MaxOpenReviews
+	}
+	queue := make(map[string]models.QueuedReview, len(snap.ReviewQueue))
+	for _, item := range snap.ReviewQueue {
+		if _, ok := prs[item.PullRequestId]; !ok {
+			return fmt.Errorf("insert queued review: pull request %s does not exist", item.PullRequestId)
+		}
+		if _, dup := queue[item.PullRequestId]; dup {
+			return fmt.Errorf("insert queued review %s: duplicate key", item.PullRequestId)
+		}
+		if item.Missing <= 0 {
+			return fmt.Errorf("insert queued review %s: missing must be positive", item.PullRequestId)
+		}
+		queue[item.PullRequestId] = item
+	}
+	for _, r := range snap.AffinityRules {
+		if _, ok := teams[r.TeamName]; !ok {
+			return fmt.Errorf("insert affinity rule: team %s does not exist", r.TeamName)
+		}
+		if _, ok := users[r.UserId]; !ok {
+			return fmt.Errorf("insert affinity rule: user %s does not exist", r.UserId)
+		}
+		if len(r.WithUserIds) == 0 {
+			return fmt.Errorf("insert affinity rule: with_user_ids must not be empty")
+		}
+	}
+	learners := make(map[string]map[string]struct{})
+	for _, l := range snap.TeamLearners {
+		if _, ok := teams[l.TeamName]; !ok {
+			return fmt.Errorf("insert team learner: team %s does not exist", l.TeamName)
+		}
+		if _, ok := users[l.UserId]; !ok {
+			return fmt.Errorf("insert team learner: user %s does not exist", l.UserId)
+		}
+		if _, dup := learners[l.TeamName][l.UserId]; dup {
+			return fmt.Errorf("insert team learner %s of %s: duplicate key", l.UserId, l.TeamName)
+		}
+		if learners[l.TeamName] == nil {
+			learners[l.TeamName] = make(map[string]struct{})
+		}
+		learners[l.TeamName][l.UserId] = struct{}{}
+	}
+	for _, d := range snap.AssignmentDecisions {
+		if _, ok := prs[d.PullRequestId]; !ok {
+			return fmt.Errorf("insert assignment decision: pull request %s does not exist", d.PullRequestId)
+		}
+	}
+
+	t.teams, t.users, t.prs, t.repositories = teams, users, prs, repositories
+	t.rotations, t.workingHours = slices.Clone(snap.ReviewRotations), workingHours
+	t.capacities, t.reviewQueue, t.learners = capacities, queue, learners
+	// rule_id общий для всех организаций, поэтому правила получают новые идентификаторы.
+	for _, r := range snap.AffinityRules {
+		s.lastRuleID++
+		r.RuleId = s.lastRuleID
+		r.WithUserIds = slices.Clone(r.WithUserIds)
+		t.affinityRules[r.RuleId] = r
+	}
+	// decision_id тоже общий; решения получают новые идентификаторы в прежнем порядке.
+	for _, d := range snap.AssignmentDecisions {
+		s.lastDecisionID++
+		d.DecisionId = s.lastDecisionID
+		t.decisions = append(t.decisions, cloneDecision(d))
+	}
+	// absence_id общий для всех организаций, поэтому периоды отсутствия получают новые идентификаторы.
+	for _, a := range snap.Absences {
+		s.lastAbsenceID++
+		a.AbsenceId = s.lastAbsenceID
+		t.absences[a.AbsenceId] = cloneAbsence(a)
+	}
+	return nil
+}
+
+// ---------- вспомогательные функции ----------
+
+// checkTeamRef повторяет внешний ключ users.team_name -> teams.team_name.
+func (t *tenantData) checkTeamRef(teamName string) error {
+	if teamName == "" {
+		return nil
+	}
+	if _, ok := t.teams[teamName]; !ok {
+		return fmt.Errorf("team %s does not exist", teamName)
+	}
+	return nil
+}
+
+// usersInTeam возвращает копии участников команды в порядке username; вызывается под блокировкой.
+func (t *tenantData) usersInTeam(teamID string) []*models.User {
+	var result []*models.User
+	for _, user := range t.users {
+		if user.TeamName == teamID {
+			u := user
+			result = append(result, &u)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Username != result[j].Username {
+			return result[i].Username < result[j].Username
+		}
+		return result[i].UserId < result[j].UserId
+	})
+	return result
+}
+
+// toModel возвращает независимую копию записи в виде модели с отсортированными ревьюерами.
+func (r *pullRequestRecord) toModel() *models.PullRequest {
+	return &models.PullRequest{
+		AssignedReviewers: sortedKeys(r.reviewers),
+		ShadowReviewers:   sortedKeys(r.shadows),
+		AuthorId:          r.authorID,
+		CreatedAt:         cloneTime(r.createdAt),
+		MergedAt:          cloneTime(r.mergedAt),
+		PullRequestId:     r.id,
+		PullRequestName:   r.name,
+		Status:            r.status,
+		LinesAdded:        r.linesAdded,
+		LinesDeleted:      r.linesDeleted,
+		FilesChanged:      r.filesChanged,
+		Priority:          r.priority,
+		Labels:            slices.Clone(r.labels),
+		Repository:        r.repository,
+		Number:            r.number,
+	}
+}
+
+// activity возвращает отметку активности пользователя в любой роли; false — он не назначен на PR.
+func (r *pullRequestRecord) activity(userID string) (time.Time, bool) {
+	if at, ok := r.reviewers[userID]; ok {
+		return at, true
+	}
+	at, ok := r.shadows[userID]
+	return at, ok
+}
+
+// sortedKeys возвращает ключи множества по возрастанию; nil для пустого.
+func sortedKeys(set map[string]time.Time) []string {
+	var keys []string
+	for id := range set {
+		keys = append(keys, id)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortByCreatedDesc повторяет ORDER BY created_at DESC NULLS LAST с детерминированным порядком при равенстве.
+func sortByCreatedDesc(prs []*models.PullRequest) {
+	sort.Slice(prs, func(i, j int) bool {
+		a, b := prs[i].CreatedAt, prs[j].CreatedAt
+		switch {
+		case a == nil && b == nil:
+			return prs[i].PullRequestId < prs[j].PullRequestId
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case !a.Equal(*b):
+			return a.After(*b)
+		default:
+			return prs[i].PullRequestId < prs[j].PullRequestId
+		}
+	})
+}
+
+// cloneDecision копирует решение вместе со списками и нагрузкой кандидатов.
+func cloneDecision(d models.AssignmentDecision) models.AssignmentDecision {
+	d.Selected = slices.Clone(d.Selected)
+	d.Replaced = slices.Clone(d.Replaced)
+	d.Rules = slices.Clone(d.Rules)
+	d.Candidates = slices.Clone(d.Candidates)
+	for i, c := range d.Candidates {
+		if c.Load != nil {
+			load := *c.Load
+			d.Candidates[i].Load = &load
+		}
+	}
+	return d
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
+
+// nearestRank возвращает первое значение отсортированного среза, на котором доля строк достигает p.
+func nearestRank(sorted []float64, p float64) float64 {
+	n := len(sorted)
+	for i, v := range sorted {
+		if float64(i+1)/float64(n) >= p {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/repotest"
+)
+
+func TestStorageContract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) repotest.Backend {
+		return NewStorage()
+	})
+}
+
+func TestStorageReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	s := NewStorage()
+	require.NoError(t, s.CreateTeamWithMembers(ctx, &models.Team{TeamName: "t"}, []models.User{
+		{UserId: "a", Username: "A", IsActive: true, TeamName: "t"},
+		{UserId: "b", Username: "B", IsActive: true, TeamName: "t"},
+	}))
+	require.NoError(t, s.SavePullRequest(ctx, &models.PullRequest{
+		PullRequestId: "pr", AuthorId: "a", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"b"},
+	}))
+
+	pr, err := s.GetPullRequest(ctx, "pr")
+	require.NoError(t, err)
+	pr.AssignedReviewers[0] = "mutated"
+
+	user, err := s.GetUser(ctx, "a")
+	require.NoError(t, err)
+	user.IsActive = false
+
+	pr, err = s.GetPullRequest(ctx, "pr")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, pr.AssignedReviewers)
+	user, err = s.GetUser(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+}
+
+func TestStorageConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	s := NewStorage()
+	require.NoError(t, s.SaveTeam(ctx, &models.Team{TeamName: "t"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("u%d", i)
+			require.NoError(t, s.SaveUser(ctx, &models.User{UserId: id, Username: id, IsActive: true, TeamName: "t"}))
+			_, err := s.GetTeam(ctx, "t")
+			require.NoError(t, err)
+			_, err = s.GetAssignmentStats(ctx, models.AssignmentStatsFilter{})
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	users, err := s.GetAllUsersInTeam(ctx, "t")
+	require.NoError(t, err)
+	require.Len(t, users, 20)
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/migrate"
+)
+
+// migrationLockKey — ключ advisory-lock, под которым выполняются миграции, чтобы несколько инстансов не применяли их одновременно.
+const migrationLockKey int64 = 7_207_150_301
+
+const (
+	createMigrationsTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+	lockMigrationsSQL          = `SELECT pg_advisory_xact_lock($1)`
+	selectAppliedMigrationsSQL = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+	insertMigrationSQL         = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+	deleteMigrationSQL         = `DELETE FROM schema_migrations WHERE version = $1`
+)
+
+// MigrateUp применяет все неприменённые миграции по одной, каждую в своей транзакции, и возвращает их версии.
+func (s *Storage) MigrateUp(ctx context.Context, migrations []migrate.Migration) ([]int64, error) {
+	var applied []int64
+	for {
+		version, done, err := s.applyNextMigration(ctx, migrations)
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			return applied, nil
+		}
+		applied = append(applied, version)
+	}
+}
+
+// MigrateDown откатывает последние steps применённых миграций и возвращает их версии.
+func (s *Storage) MigrateDown(ctx context.Context, migrations []migrate.Migration, steps int) ([]int64, error) {
+	var reverted []int64
+	for i := 0; i < steps; i++ {
+		version, done, err := s.revertLastMigration(ctx, migrations)
+		if err != nil {
+			return reverted, err
+		}
+		if done {
+			break
+		}
+		reverted = append(reverted, version)
+	}
+	return reverted, nil
+}
+
+// MigrationStatus возвращает состояние каждой известной миграции.
+func (s *Storage) MigrationStatus(ctx context.Context, migrations []migrate.Migration) ([]migrate.Status, error) {
+	var status []migrate.Status
+	err := s.withMigrationLock(ctx, func(tx pgx.Tx) error {
+		applied, err := loadAppliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+		status = migrate.BuildStatus(migrations, applied)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// applyNextMigration под блокировкой применяет первую неприменённую миграцию; done=true, если применять нечего.
+func (s *Storage) applyNextMigration(ctx context.Context, migrations []migrate.Migration) (version int64, done bool, err error) {
+	err = s.withMigrationLock(ctx, func(tx pgx.Tx) error {
+		applied, err := loadAppliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+		pending := migrate.Pending(migrations, applied)
+		if len(pending) == 0 {
+			done = true
+			return nil
+		}
+
+		next := pending[0]
+		if _, err := tx.Exec(ctx, next.Up); err != nil {
+			return fmt.Errorf("apply migration %d_%s: %w", next.Version, next.Name, err)
+		}
+		if _, err := tx.Exec(ctx, insertMigrationSQL, next.Version, next.Name); err != nil {
+			return fmt.Errorf("record migration %d: %w", next.Version, err)
+		}
+		version = next.Version
+		return nil
+	})
+	return version, done, err
+}
+
+// revertLastMigration под блокировкой откатывает последнюю применённую миграцию; done=true, если откатывать нечего.
+func (s *Storage) revertLastMigration(ctx context.Context, migrations []migrate.Migration) (version int64, done bool, err error) {
+	err = s.withMigrationLock(ctx, func(tx pgx.Tx) error {
+		applied, err := loadAppliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			done = true
+			return nil
+		}
+
+		var last int64
+		for v := range applied {
+			if v > last {
+				last = v
+			}
+		}
+		m, ok := migrate.Find(migrations, last)
+		if !ok {
+			return fmt.Errorf("migration %d is applied but unknown to this binary", last)
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down script", m.Version, m.Name)
+		}
+
+		if _, err := tx.Exec(ctx, m.Down); err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec(ctx, deleteMigrationSQL, m.Version); err != nil {
+			return fmt.Errorf("unrecord migration %d: %w", m.Version, err)
+		}
+		version = m.Version
+		return nil
+	})
+	return version, done, err
+}
+
+// withMigrationLock открывает транзакцию, берёт advisory-lock миграций и гарантирует наличие schema_migrations.
+func (s *Storage) withMigrationLock(ctx context.Context, fn func(tx pgx.Tx) error) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, lockMigrationsSQL, migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if _, err := tx.Exec(ctx, createMigrationsTableSQL); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// loadAppliedMigrations читает версии из schema_migrations.
+func loadAppliedMigrations(ctx context.Context, tx pgx.Tx) (map[int64]time.Time, error) {
+	rows, err := tx.Query(ctx, selectAppliedMigrationsSQL)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schema_migrations rows: %w", err)
+	}
+	return applied, nil
+}
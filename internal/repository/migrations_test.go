@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/migrate"
+)
+
+var (
+	testMigrations = []migrate.Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE a (id TEXT)", Down: "DROP TABLE a"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE b (id TEXT)", Down: "DROP TABLE b"},
+	}
+	schemaMigrationCols = []string{"version", "applied_at"}
+)
+
+func expectMigrationLock(mock pgxmock.PgxPoolIface) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock")).
+		WithArgs(migrationLockKey).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+}
+
+func TestStorage_MigrateUp(t *testing.T) {
+	t.Run("applies pending migrations one by one", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		appliedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols).AddRow(int64(1), appliedAt))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b")).
+			WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations")).
+			WithArgs(int64(2), "second").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols).
+				AddRow(int64(1), appliedAt).
+				AddRow(int64(2), appliedAt))
+		mock.ExpectCommit()
+
+		applied, err := s.MigrateUp(testCtx, testMigrations)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(applied) != 1 || applied[0] != 2 {
+			t.Fatalf("expected only version 2 to be applied, got %v", applied)
+		}
+	})
+
+	t.Run("migration failure rolls back", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a")).
+			WillReturnError(errors.New("syntax error"))
+		mock.ExpectRollback()
+
+		applied, err := s.MigrateUp(testCtx, testMigrations)
+		if err == nil || !strings.Contains(err.Error(), "apply migration 1_init") {
+			t.Fatalf("expected apply error, got %v", err)
+		}
+		if len(applied) != 0 {
+			t.Fatalf("expected nothing applied, got %v", applied)
+		}
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock")).
+			WithArgs(migrationLockKey).
+			WillReturnError(errors.New("lock timeout"))
+		mock.ExpectRollback()
+
+		if _, err := s.MigrateUp(testCtx, testMigrations); err == nil || !strings.Contains(err.Error(), "acquire migration lock") {
+			t.Fatalf("expected lock error, got %v", err)
+		}
+	})
+}
+
+func TestStorage_MigrateDown(t *testing.T) {
+	t.Run("reverts latest migration", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		appliedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols).
+				AddRow(int64(1), appliedAt).
+				AddRow(int64(2), appliedAt))
+		mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b")).
+			WillReturnResult(pgxmock.NewResult("DROP TABLE", 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations")).
+			WithArgs(int64(2)).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+
+		reverted, err := s.MigrateDown(testCtx, testMigrations, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reverted) != 1 || reverted[0] != 2 {
+			t.Fatalf("expected version 2 reverted, got %v", reverted)
+		}
+	})
+
+	t.Run("stops when nothing applied", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols))
+		mock.ExpectCommit()
+
+		reverted, err := s.MigrateDown(testCtx, testMigrations, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reverted) != 0 {
+			t.Fatalf("expected nothing reverted, got %v", reverted)
+		}
+	})
+
+	t.Run("unknown applied version", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		expectMigrationLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(pgxmock.NewRows(schemaMigrationCols).AddRow(int64(9), time.Now()))
+		mock.ExpectRollback()
+
+		if _, err := s.MigrateDown(testCtx, testMigrations, 1); err == nil || !strings.Contains(err.Error(), "unknown to this binary") {
+			t.Fatalf("expected unknown version error, got %v", err)
+		}
+	})
+}
+
+func TestStorage_MigrationStatus(t *testing.T) {
+	s, mock := newTestStorage(t)
+	appliedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	expectMigrationLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+		WillReturnRows(pgxmock.NewRows(schemaMigrationCols).AddRow(int64(1), appliedAt))
+	mock.ExpectCommit()
+
+	status, err := s.MigrationStatus(testCtx, testMigrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status) != 2 || !status[0].Applied || status[1].Applied {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
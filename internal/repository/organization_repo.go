@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// CreateOrganization создаёт организацию с её токеном и репозиторием default в одной транзакции.
+// ORG_EXISTS, если организация с таким идентификатором уже есть.
+func (s *Storage) CreateOrganization(ctx context.Context, org *models.Organization, tokenHash string) (err error) {
+	if org == nil {
+		return fmt.Errorf("organization is nil")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	const insertOrg = `
+INSERT INTO organizations (organization_id, name, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id) DO NOTHING
+`
+	tag, err := tx.Exec(ctx, insertOrg, org.OrganizationId, org.Name, org.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert organization: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewOrgExistsError(org.OrganizationId)
+	}
+
+	const insertToken = `INSERT INTO organization_tokens (token_hash, organization_id) VALUES ($1, $2)`
+	if _, err := tx.Exec(ctx, insertToken, tokenHash, org.OrganizationId); err != nil {
+		return fmt.Errorf("insert organization token: %w", err)
+	}
+
+	const insertRepo = `INSERT INTO repositories (repository_name, organization_id) VALUES ($1, $2)`
+	if _, err := tx.Exec(ctx, insertRepo, models.DefaultRepository, org.OrganizationId); err != nil {
+		return fmt.Errorf("insert default repository: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// ListOrganizations возвращает все организации по идентификатору.
+func (s *Storage) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	const q = `SELECT organization_id, name, created_at FROM organizations ORDER BY organization_id`
+	rows, err := s.pool.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query organizations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Organization, 0)
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.OrganizationId, &org.Name, &org.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan organizations: %w", err)
+		}
+		result = append(result, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows organizations: %w", err)
+	}
+	return result, nil
+}
+
+// FindOrganizationByToken возвращает организацию, которой выдан токен с хешем tokenHash; NOT_FOUND, если такого нет.
+func (s *Storage) FindOrganizationByToken(ctx context.Context, tokenHash string) (string, error) {
+	rows, err := s.pool.Query(ctx, `SELECT organization_id FROM organization_tokens WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return "", fmt.Errorf("query organization token: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("query organization token: %w", err)
+		}
+		return "", domain.NewNotFoundError("organization token")
+	}
+	var organizationID string
+	if err := rows.Scan(&organizationID); err != nil {
+		return "", fmt.Errorf("scan organization token: %w", err)
+	}
+	return organizationID, nil
+}
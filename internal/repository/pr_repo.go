@@ -10,6 +10,7 @@ import (
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
 // SavePullRequest сохраняет или обновляет Pull Request и закреплённых ревьюеров в одной транзакции.
@@ -39,16 +40,26 @@ func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) (
 
 	const upsertPR = `
 	INSERT INTO pull_requests (
-		pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-	) VALUES ($1, $2, $3, $4, $5, $6)
-	ON CONFLICT (pull_request_id) DO UPDATE
+		pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+		lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number, organization_id
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	ON CONFLICT (organization_id, pull_request_id) DO UPDATE
 	SET pull_request_name = EXCLUDED.pull_request_name,
 		author_id = EXCLUDED.author_id,
 		status = EXCLUDED.status,
 		created_at = EXCLUDED.created_at,
-		merged_at = EXCLUDED.merged_at
+		merged_at = EXCLUDED.merged_at,
+		lines_added = EXCLUDED.lines_added,
+		lines_deleted = EXCLUDED.lines_deleted,
+		files_changed = EXCLUDED.files_changed,
+		priority = EXCLUDED.priority,
+		labels = EXCLUDED.labels,
+		review_weight = EXCLUDED.review_weight,
+		repository = EXCLUDED.repository,
+		number = EXCLUDED.number
 `
 
+	organizationID := tenant.Organization(ctx)
 	// РїРµСЂРµРґР°С‘Рј *time.Time вЂ” nil РєРѕСЂСЂРµРєС‚РЅРѕ РїСЂРµРІСЂР°С‰Р°РµС‚СЃСЏ РІ NULL
 	_, err = tx.Exec(ctx, upsertPR,
 		pr.PullRequestId,
@@ -57,30 +68,41 @@ func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) (
 		string(pr.Status),
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.LinesAdded,
+		pr.LinesDeleted,
+		pr.FilesChanged,
+		string(pr.Priority),
+		nonNilLabels(pr.Labels),
+		pr.ReviewWeight(),
+		models.RepositoryOrDefault(pr.Repository),
+		nullableNumber(pr.Number),
+		organizationID,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert pull_requests: %w", err)
 	}
 
-	// РћР±РЅРѕРІР»СЏРµРј СЂРµРІСЊСЋРµСЂРѕРІ: РїСЂРѕС‰Рµ СѓРґР°Р»РёС‚СЊ СЃС‚Р°СЂС‹Рµ Рё РІСЃС‚Р°РІРёС‚СЊ РЅРѕРІС‹Рµ
-	const deleteReviewers = `DELETE FROM pull_request_reviewers WHERE pull_request_id = $1`
-	if _, err := tx.Exec(ctx, deleteReviewers, pr.PullRequestId); err != nil {
+	// Удаляем снятых ревьюеров и добавляем новых; у оставшихся сохраняется last_activity_at, роль обновляется.
+	assignments := pr.Assignments()
+	reviewers := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		reviewers = append(reviewers, a.UserId)
+	}
+	const deleteReviewers = `
+	DELETE FROM pull_request_reviewers
+	WHERE pull_request_id = $1 AND NOT (user_id = ANY($2)) AND organization_id = $3
+	`
+	if _, err := tx.Exec(ctx, deleteReviewers, pr.PullRequestId, reviewers, organizationID); err != nil {
 		return fmt.Errorf("delete pull_request_reviewers: %w", err)
 	}
 
-	const insertReviewer = `INSERT INTO pull_request_reviewers (pull_request_id, user_id) VALUES ($1, $2)`
-	// Р”РµРґСѓРїР»РёС†РёСЂСѓРµРј РЅР° РІСЃСЏРєРёР№ СЃР»СѓС‡Р°Р№
-	seen := make(map[string]struct{}, len(pr.AssignedReviewers))
-	for _, r := range pr.AssignedReviewers {
-		if r == "" {
-			continue // РїСЂРѕРїСѓСЃРєР°РµРј РїСѓСЃС‚С‹Рµ id
-		}
-		if _, ok := seen[r]; ok {
-			continue
-		}
-		seen[r] = struct{}{}
-		if _, err := tx.Exec(ctx, insertReviewer, pr.PullRequestId, r); err != nil {
-			return fmt.Errorf("insert pull_request_reviewer (%s): %w", r, err)
+	const upsertReviewer = `
+	INSERT INTO pull_request_reviewers (pull_request_id, user_id, role, organization_id) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (organization_id, pull_request_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+	for _, a := range assignments {
+		if _, err := tx.Exec(ctx, upsertReviewer, pr.PullRequestId, a.UserId, string(a.Role), organizationID); err != nil {
+			return fmt.Errorf("insert pull_request_reviewer (%s): %w", a.UserId, err)
 		}
 	}
 
@@ -94,12 +116,14 @@ func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) (
 // GetPullRequest возвращает Pull Request по идентификатору вместе со списком ревьюеров.
 func (s *Storage) GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
 	const qPR = `
-	SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+	SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+		lines_added, lines_deleted, files_changed, priority, labels, repository, number
 	FROM pull_requests
-	WHERE pull_request_id = $1
+	WHERE pull_request_id = $1 AND organization_id = $2
 	`
 
-	rows, err := s.pool.Query(ctx, qPR, prID)
+	organizationID := tenant.Organization(ctx)
+	rows, err := s.pool.Query(ctx, qPR, prID, organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("query pull_requests: %w", err)
 	}
@@ -117,26 +141,34 @@ func (s *Storage) GetPullRequest(ctx context.Context, prID string) (*models.Pull
 		status  string
 		created *time.Time
 		merged  *time.Time
+		meta    pullRequestMeta
 	)
-	if err := rows.Scan(&id, &name, &author, &status, &created, &merged); err != nil {
+	if err := rows.Scan(&id, &name, &author, &status, &created, &merged, &meta.linesAdded, &meta.linesDeleted,
+		&meta.filesChanged, &meta.priority, &meta.labels, &meta.repository, &meta.number); err != nil {
 		return nil, fmt.Errorf("scan pull_requests: %w", err)
 	}
 
 	// РџРѕР»СѓС‡Р°РµРј СЂРµРІСЊСЋРµСЂРѕРІ (РјРѕР¶РµС‚ Р±С‹С‚СЊ 0)
-	const qReviewers = `SELECT user_id FROM pull_request_reviewers WHERE pull_request_id = $1 ORDER BY user_id`
-	rrows, err := s.pool.Query(ctx, qReviewers, prID)
+	const qReviewers = `
+	SELECT user_id, role FROM pull_request_reviewers WHERE pull_request_id = $1 AND organization_id = $2 ORDER BY user_id
+	`
+	rrows, err := s.pool.Query(ctx, qReviewers, prID, organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("query pull_request_reviewers: %w", err)
 	}
 	defer rrows.Close()
 
-	var reviewers []string
+	var reviewers, shadows []string
 	for rrows.Next() {
-		var uid string
-		if err := rrows.Scan(&uid); err != nil {
+		var uid, role string
+		if err := rrows.Scan(&uid, &role); err != nil {
 			return nil, fmt.Errorf("scan reviewer: %w", err)
 		}
-		reviewers = append(reviewers, uid)
+		if models.ReviewerRole(role) == models.ReviewerRoleSHADOW {
+			shadows = append(shadows, uid)
+		} else {
+			reviewers = append(reviewers, uid)
+		}
 	}
 
 	pr := &models.PullRequest{
@@ -147,12 +179,28 @@ func (s *Storage) GetPullRequest(ctx context.Context, prID string) (*models.Pull
 		PullRequestId:     id,
 		PullRequestName:   name,
 		Status:            models.PullRequestStatus(status),
+		ShadowReviewers:   shadows,
 	}
+	meta.apply(pr)
 	return pr, nil
 }
 
 // FindPullRequestsByReviewer находит все PR, назначенные конкретному ревьюеру.
 func (s *Storage) FindPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error) {
+	var result []*models.PullRequest
+	err := s.StreamPullRequestsByReviewer(ctx, reviewerID, func(pr *models.PullRequest) error {
+		result = append(result, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamPullRequestsByReviewer передаёт в fn PR ревьюера по мере чтения строк, не накапливая их в памяти.
+// Ошибка fn прерывает чтение и возвращается без обёртки.
+func (s *Storage) StreamPullRequestsByReviewer(ctx context.Context, reviewerID string, fn func(*models.PullRequest) error) error {
 	const q = `
 SELECT 
     p.pull_request_id,
@@ -161,21 +209,35 @@ SELECT
     p.status,
     p.created_at,
     p.merged_at,
-    COALESCE(array_agg(r.user_id ORDER BY r.user_id), ARRAY[]::text[]) AS reviewers
+    p.lines_added,
+    p.lines_deleted,
+    p.files_changed,
+    p.priority,
+    p.labels,
+    p.repository,
+    p.number,
+    COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.role = 'REVIEWER'), ARRAY[]::text[]) AS reviewers,
+    COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.role = 'SHADOW'), ARRAY[]::text[]) AS shadows
 FROM pull_requests p
-JOIN pull_request_reviewers r ON p.pull_request_id = r.pull_request_id
-WHERE r.user_id = $1
-GROUP BY p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at
+JOIN pull_request_reviewers r ON r.organization_id = p.organization_id AND r.pull_request_id = p.pull_request_id
+WHERE p.organization_id = $2
+  AND EXISTS (
+        SELECT 1
+        FROM pull_request_reviewers tr
+        WHERE tr.organization_id = p.organization_id
+          AND tr.pull_request_id = p.pull_request_id
+          AND tr.user_id = $1
+    )
+GROUP BY p.organization_id, p.pull_request_id
 ORDER BY p.created_at DESC NULLS LAST
 `
 
-	rows, err := s.pool.Query(ctx, q, reviewerID)
+	rows, err := s.pool.Query(ctx, q, reviewerID, tenant.Organization(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("query find by reviewer: %w", err)
+		return fmt.Errorf("query find by reviewer: %w", err)
 	}
 	defer rows.Close()
 
-	var result []*models.PullRequest
 	for rows.Next() {
 		var (
 			id        string
@@ -184,11 +246,14 @@ ORDER BY p.created_at DESC NULLS LAST
 			status    string
 			created   *time.Time
 			merged    *time.Time
+			meta      pullRequestMeta
 			reviewers []string
+			shadows   []string
 		)
 
-		if err := rows.Scan(&id, &name, &author, &status, &created, &merged, &reviewers); err != nil {
-			return nil, fmt.Errorf("scan find by reviewer: %w", err)
+		if err := rows.Scan(&id, &name, &author, &status, &created, &merged, &meta.linesAdded, &meta.linesDeleted,
+			&meta.filesChanged, &meta.priority, &meta.labels, &meta.repository, &meta.number, &reviewers, &shadows); err != nil {
+			return fmt.Errorf("scan find by reviewer: %w", err)
 		}
 
 		pr := &models.PullRequest{
@@ -199,71 +264,147 @@ ORDER BY p.created_at DESC NULLS LAST
 			PullRequestId:     id,
 			PullRequestName:   name,
 			Status:            models.PullRequestStatus(status),
+			ShadowReviewers:   nilIfEmpty(shadows),
+		}
+		meta.apply(pr)
+		if err := fn(pr); err != nil {
+			return err
 		}
-		result = append(result, pr)
 	}
 
 	// Р’РѕР·РјРѕР¶РЅРѕ, rows.Err() СЃРѕРґРµСЂР¶РёС‚ РѕС€РёР±РєСѓ
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
 	}
 
-	return result, nil
+	return nil
 }
 
-// GetAssignmentStats рассчитывает агрегированную статистику распределения ревью.
-func (s *Storage) GetAssignmentStats(ctx context.Context) (*models.AssignmentStats, error) {
-	const qUsers = `
+// assignmentPRsCTE отбирает PR по фильтру статистики; команда PR — команда автора.
+// Параметры: $1 команда, $2 и $3 границы created_at, $4 статус, $5 репозиторий; пустые значения и NULL не фильтруют.
+// $6 — организация: выборка и все соединения с prs ограничены ею.
+const assignmentPRsCTE = `
+WITH prs AS (
+    SELECT p.organization_id, p.pull_request_id, p.pull_request_name, p.status, p.review_weight, u.team_name
+    FROM pull_requests p
+    LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+    WHERE p.organization_id = $6
+      AND ($1::text = '' OR u.team_name = $1)
+      AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+      AND ($3::timestamptz IS NULL OR p.created_at < $3)
+      AND ($4::text = '' OR p.status = $4)
+      AND ($5::text = '' OR p.repository = $5)
+)
+`
+
+// GetAssignmentStats рассчитывает агрегированную статистику распределения ревью по PR, попавшим в фильтр.
+func (s *Storage) GetAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
+	stats := &models.AssignmentStats{}
+	err := s.StreamUserAssignments(ctx, filter, func(stat models.UserAssignmentStat) error {
+		stats.ByUser = append(stats.ByUser, stat)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.StreamPullRequestAssignments(ctx, filter, func(stat models.PullRequestAssignmentStat) error {
+		stats.ByPullRequest = append(stats.ByPullRequest, stat)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.ByTeam, err = s.GetTeamAssignmentStats(ctx, filter); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// assignmentFilterArgs раскладывает фильтр и организацию по параметрам $1..$6 assignmentPRsCTE.
+func assignmentFilterArgs(ctx context.Context, filter models.AssignmentStatsFilter) []any {
+	return []any{filter.TeamName, nullableTime(filter.From), nullableTime(filter.To), string(filter.Status), filter.Repository,
+		tenant.Organization(ctx)}
+}
+
+// assignmentLimit возвращает параметр LIMIT; NULL снимает ограничение.
+func assignmentLimit(filter models.AssignmentStatsFilter) any {
+	if filter.Limit > 0 {
+		return filter.Limit
+	}
+	return nil
+}
+
+// StreamUserAssignments передаёт в fn число назначений по ревьюерам, начиная с самых загруженных.
+func (s *Storage) StreamUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+	const qUsers = assignmentPRsCTE + `
 SELECT 
     r.user_id,
     COALESCE(u.username, ''),
-    COUNT(*) AS assignments
-FROM pull_request_reviewers r
-LEFT JOIN users u ON u.user_id = r.user_id
+    COUNT(*) FILTER (WHERE r.role = 'REVIEWER') AS assignments,
+    COALESCE(SUM(prs.review_weight) FILTER (WHERE r.role = 'REVIEWER'), 0) AS weighted_load,
+    COUNT(*) FILTER (WHERE r.role = 'SHADOW') AS shadow_assignments
+FROM prs
+JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+LEFT JOIN users u ON u.organization_id = r.organization_id AND u.user_id = r.user_id
 GROUP BY r.user_id, u.username
 ORDER BY assignments DESC, r.user_id
+LIMIT $7
 `
 
-	userRows, err := s.pool.Query(ctx, qUsers)
+	userRows, err := s.pool.Query(ctx, qUsers, append(assignmentFilterArgs(ctx, filter), assignmentLimit(filter))...)
 	if err != nil {
-		return nil, fmt.Errorf("query user assignment stats: %w", err)
+		return fmt.Errorf("query user assignment stats: %w", err)
 	}
 	defer userRows.Close()
 
-	stats := &models.AssignmentStats{}
 	for userRows.Next() {
 		var (
-			userID      string
-			username    string
-			assignments int64
+			userID       string
+			username     string
+			assignments  int64
+			weightedLoad int64
+			shadows      int64
 		)
-		if scanErr := userRows.Scan(&userID, &username, &assignments); scanErr != nil {
-			return nil, fmt.Errorf("scan user assignment stats: %w", scanErr)
+		if scanErr := userRows.Scan(&userID, &username, &assignments, &weightedLoad, &shadows); scanErr != nil {
+			return fmt.Errorf("scan user assignment stats: %w", scanErr)
 		}
-		stats.ByUser = append(stats.ByUser, models.UserAssignmentStat{
-			UserId:      userID,
-			Username:    username,
-			Assignments: int(assignments),
+		err = fn(models.UserAssignmentStat{
+			UserId:            userID,
+			Username:          username,
+			Assignments:       int(assignments),
+			WeightedLoad:      int(weightedLoad),
+			ShadowAssignments: int(shadows),
 		})
+		if err != nil {
+			return err
+		}
 	}
 	if err = userRows.Err(); err != nil {
-		return nil, fmt.Errorf("user assignment stats rows: %w", err)
+		return fmt.Errorf("user assignment stats rows: %w", err)
 	}
+	return nil
+}
 
-	const qPRs = `
+// StreamPullRequestAssignments передаёт в fn число ревьюеров по PR, начиная с PR с наибольшим числом.
+func (s *Storage) StreamPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+	const qPRs = assignmentPRsCTE + `
 SELECT 
-    p.pull_request_id,
-    p.pull_request_name,
-    COUNT(r.user_id) AS reviewer_count
-FROM pull_requests p
-LEFT JOIN pull_request_reviewers r ON r.pull_request_id = p.pull_request_id
-GROUP BY p.pull_request_id, p.pull_request_name
-ORDER BY reviewer_count DESC, p.pull_request_id
+    prs.pull_request_id,
+    prs.pull_request_name,
+    COUNT(r.user_id) FILTER (WHERE r.role = 'REVIEWER') AS reviewer_count,
+    COUNT(r.user_id) FILTER (WHERE r.role = 'SHADOW') AS shadow_count
+FROM prs
+LEFT JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+GROUP BY prs.pull_request_id, prs.pull_request_name
+ORDER BY reviewer_count DESC, prs.pull_request_id
+LIMIT $7
 `
 
-	prRows, err := s.pool.Query(ctx, qPRs)
+	prRows, err := s.pool.Query(ctx, qPRs, append(assignmentFilterArgs(ctx, filter), assignmentLimit(filter))...)
 	if err != nil {
-		return nil, fmt.Errorf("query pr assignment stats: %w", err)
+		return fmt.Errorf("query pr assignment stats: %w", err)
 	}
 	defer prRows.Close()
 
@@ -272,20 +413,238 @@ ORDER BY reviewer_count DESC, p.pull_request_id
 			prID          string
 			prName        string
 			reviewerCount int64
+			shadowCount   int64
 		)
-		if err := prRows.Scan(&prID, &prName, &reviewerCount); err != nil {
-			return nil, fmt.Errorf("scan pr assignment stats: %w", err)
+		if err := prRows.Scan(&prID, &prName, &reviewerCount, &shadowCount); err != nil {
+			return fmt.Errorf("scan pr assignment stats: %w", err)
 		}
-		stats.ByPullRequest = append(stats.ByPullRequest, models.PullRequestAssignmentStat{
+		err := fn(models.PullRequestAssignmentStat{
 			PullRequestId:   prID,
 			PullRequestName: prName,
 			ReviewerCount:   int(reviewerCount),
+			ShadowCount:     int(shadowCount),
 		})
+		if err != nil {
+			return err
+		}
 	}
 	if err := prRows.Err(); err != nil {
-		return nil, fmt.Errorf("pr assignment stats rows: %w", err)
+		return fmt.Errorf("pr assignment stats rows: %w", err)
+	}
+	return nil
+}
+
+// GetTeamAssignmentStats считает агрегаты по командам и коэффициент дисбаланса нагрузки их активных участников.
+func (s *Storage) GetTeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+	args := assignmentFilterArgs(ctx, filter)
+	const qTeams = assignmentPRsCTE + `,
+counted AS (
+    SELECT prs.pull_request_id, prs.status, prs.team_name, COUNT(r.user_id) AS reviewer_count
+    FROM prs
+    LEFT JOIN pull_request_reviewers r
+        ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id AND r.role = 'REVIEWER'
+    WHERE prs.team_name IS NOT NULL
+    GROUP BY prs.pull_request_id, prs.status, prs.team_name
+)
+SELECT
+    team_name,
+    COUNT(*) FILTER (WHERE status = 'OPEN') AS open_count,
+    COUNT(*) FILTER (WHERE status = 'MERGED') AS merged_count,
+    AVG(reviewer_count)::float8 AS avg_reviewers
+FROM counted
+GROUP BY team_name
+ORDER BY team_name
+`
+	teamRows, err := s.pool.Query(ctx, qTeams, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query team assignment stats: %w", err)
+	}
+	defer teamRows.Close()
+
+	var result []models.TeamAssignmentStat
+	index := make(map[string]int)
+	for teamRows.Next() {
+		var (
+			stat         models.TeamAssignmentStat
+			open, merged int64
+		)
+		if err := teamRows.Scan(&stat.TeamName, &open, &merged, &stat.AvgReviewers); err != nil {
+			return nil, fmt.Errorf("scan team assignment stats: %w", err)
+		}
+		stat.OpenCount, stat.MergedCount = int(open), int(merged)
+		index[stat.TeamName] = len(result)
+		result = append(result, stat)
+	}
+	if err := teamRows.Err(); err != nil {
+		return nil, fmt.Errorf("team assignment stats rows: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	// Нагрузка считается по всем активным участникам, включая тех, у кого нет назначений.
+	const qLoads = assignmentPRsCTE + `
+SELECT u.team_name, COUNT(a.user_id) AS member_load
+FROM users u
+LEFT JOIN (
+    SELECT r.user_id
+    FROM prs
+    JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+    WHERE r.role = 'REVIEWER'
+) a ON a.user_id = u.user_id
+WHERE u.organization_id = $6
+  AND u.is_active
+  AND u.team_name IS NOT NULL
+  AND ($1::text = '' OR u.team_name = $1)
+GROUP BY u.team_name, u.user_id
+`
+	loadRows, err := s.pool.Query(ctx, qLoads, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query team member loads: %w", err)
 	}
+	defer loadRows.Close()
 
+	loads := make(map[string][]int)
+	for loadRows.Next() {
+		var (
+			team string
+			load int64
+		)
+		if err := loadRows.Scan(&team, &load); err != nil {
+			return nil, fmt.Errorf("scan team member loads: %w", err)
+		}
+		loads[team] = append(loads[team], int(load))
+	}
+	if err := loadRows.Err(); err != nil {
+		return nil, fmt.Errorf("team member loads rows: %w", err)
+	}
+
+	for team, i := range index {
+		result[i].LoadImbalance = models.LoadImbalance(loads[team])
+	}
+	return result, nil
+}
+
+// pullRequestMeta — размер, срочность и метки PR в том виде, в каком они читаются из pull_requests.
+type pullRequestMeta struct {
+	linesAdded, linesDeleted, filesChanged int
+	priority                               string
+	labels                                 []string
+	repository                             string
+	number                                 *int
+}
+
+// apply переносит метаданные в модель; пустой список меток становится nil, как в модели без меток.
+func (m pullRequestMeta) apply(pr *models.PullRequest) {
+	pr.LinesAdded, pr.LinesDeleted, pr.FilesChanged = m.linesAdded, m.linesDeleted, m.filesChanged
+	pr.Priority = models.PullRequestPriority(m.priority)
+	if len(m.labels) > 0 {
+		pr.Labels = m.labels
+	}
+	pr.Repository = m.repository
+	if m.number != nil {
+		pr.Number = *m.number
+	}
+}
+
+// nilIfEmpty превращает пустой список из array_agg в nil, как в модели без значений.
+func nilIfEmpty(ids []string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// nonNilLabels заменяет nil пустым списком: колонка labels объявлена NOT NULL.
+func nonNilLabels(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// nullableNumber превращает отсутствующий номер PR в NULL: уникальность номера не распространяется на PR без него.
+func nullableNumber(n int) any {
+	if n <= 0 {
+		return nil
+	}
+	return n
+}
+
+// nullableTime превращает нулевое время в NULL, чтобы условие фильтра не применялось.
+func nullableTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// turnaroundSQL считает перцентили времени до слияния по всем разрезам одним запросом.
+// percentile_disc возвращает ближайший ранг, поэтому значения совпадают с реальными PR.
+const turnaroundSQL = `
+WITH merged AS (
+    SELECT
+        p.pull_request_id,
+        p.author_id,
+        u.team_name,
+        EXTRACT(EPOCH FROM p.merged_at - p.created_at)::float8 AS secs
+    FROM pull_requests p
+    LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+    WHERE p.organization_id = $4
+      AND p.status = 'MERGED'
+      AND p.created_at IS NOT NULL
+      AND p.merged_at >= $1
+      AND p.merged_at < $2
+      AND ($3::text = '' OR p.repository = $3)
+),
+dims AS (
+    SELECT 'overall' AS dim, '' AS key, secs FROM merged
+    UNION ALL
+    SELECT 'team', team_name, secs FROM merged WHERE team_name IS NOT NULL
+    UNION ALL
+    SELECT 'author', author_id, secs FROM merged
+    UNION ALL
+    SELECT 'reviewer', r.user_id, m.secs
+    FROM merged m
+    JOIN pull_request_reviewers r ON r.organization_id = $4 AND r.pull_request_id = m.pull_request_id AND r.role = 'REVIEWER'
+)
+SELECT
+    dim,
+    key,
+    COUNT(*),
+    percentile_disc(0.5) WITHIN GROUP (ORDER BY secs),
+    percentile_disc(0.9) WITHIN GROUP (ORDER BY secs),
+    percentile_disc(0.99) WITHIN GROUP (ORDER BY secs)
+FROM dims
+GROUP BY dim, key
+ORDER BY dim, key
+`
+
+// GetTurnaroundStats считает перцентили времени от создания до слияния PR,
+// слитых в полуинтервале [From, To), в целом и по командам, авторам и ревьюерам; Repository сужает выборку до репозитория.
+func (s *Storage) GetTurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+	rows, err := s.pool.Query(ctx, turnaroundSQL, filter.From, filter.To, filter.Repository, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query turnaround stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.TurnaroundStats{From: filter.From, To: filter.To}
+	for rows.Next() {
+		var (
+			dim, key string
+			count    int64
+			p        models.TurnaroundPercentiles
+		)
+		if err := rows.Scan(&dim, &key, &count, &p.P50Seconds, &p.P90Seconds, &p.P99Seconds); err != nil {
+			return nil, fmt.Errorf("scan turnaround stats: %w", err)
+		}
+		p.Count = int(count)
+		stats.AddGroup(dim, key, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("turnaround stats rows: %w", err)
+	}
 	return stats, nil
 }
 
@@ -319,21 +678,32 @@ SELECT
     p.status,
     p.created_at,
     p.merged_at,
-    COALESCE(array_agg(r.user_id ORDER BY r.user_id), ARRAY[]::text[]) AS reviewers
+    p.lines_added,
+    p.lines_deleted,
+    p.files_changed,
+    p.priority,
+    p.labels,
+    p.repository,
+    p.number,
+    COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.role = 'REVIEWER'), ARRAY[]::text[]) AS reviewers,
+    COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.role = 'SHADOW'), ARRAY[]::text[]) AS shadows
 FROM pull_requests p
-JOIN pull_request_reviewers r ON r.pull_request_id = p.pull_request_id
-WHERE p.status = 'OPEN'
+JOIN pull_request_reviewers r ON r.organization_id = p.organization_id AND r.pull_request_id = p.pull_request_id
+WHERE p.organization_id = $2
+  AND p.status = 'OPEN'
   AND EXISTS (
         SELECT 1
         FROM pull_request_reviewers tr
-        WHERE tr.pull_request_id = p.pull_request_id
+        WHERE tr.organization_id = p.organization_id
+          AND tr.pull_request_id = p.pull_request_id
           AND tr.user_id = ANY($1)
+          AND tr.role = 'REVIEWER'
     )
-GROUP BY p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at
+GROUP BY p.organization_id, p.pull_request_id
 ORDER BY p.created_at DESC NULLS LAST
 `
 
-	rows, err := s.pool.Query(ctx, q, ids)
+	rows, err := s.pool.Query(ctx, q, ids, tenant.Organization(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query open pull requests by reviewers: %w", err)
 	}
@@ -348,9 +718,12 @@ ORDER BY p.created_at DESC NULLS LAST
 			status    string
 			created   *time.Time
 			merged    *time.Time
+			meta      pullRequestMeta
 			reviewers []string
+			shadows   []string
 		)
-		if err := rows.Scan(&id, &name, &author, &status, &created, &merged, &reviewers); err != nil {
+		if err := rows.Scan(&id, &name, &author, &status, &created, &merged, &meta.linesAdded, &meta.linesDeleted,
+			&meta.filesChanged, &meta.priority, &meta.labels, &meta.repository, &meta.number, &reviewers, &shadows); err != nil {
 			return nil, fmt.Errorf("scan open pull requests by reviewers: %w", err)
 		}
 
@@ -362,7 +735,9 @@ ORDER BY p.created_at DESC NULLS LAST
 			CreatedAt:         created,
 			MergedAt:          merged,
 			AssignedReviewers: reviewers,
+			ShadowReviewers:   nilIfEmpty(shadows),
 		}
+		meta.apply(pr)
 		prs = append(prs, pr)
 	}
 
@@ -415,18 +790,19 @@ func (s *Storage) ApplyBulkTeamReviewerSwaps(ctx context.Context, swaps []models
 // applyReviewerSwapsTx заменяет ревьюеров в рамках переданной транзакции.
 func (s *Storage) applyReviewerSwapsTx(ctx context.Context, tx pgx.Tx, swaps []models.ReviewerSwap) error {
 	const (
-		deleteSQL = `DELETE FROM pull_request_reviewers WHERE pull_request_id = $1 AND user_id = $2`
-		insertSQL = `INSERT INTO pull_request_reviewers (pull_request_id, user_id) VALUES ($1, $2)`
+		deleteSQL = `DELETE FROM pull_request_reviewers WHERE pull_request_id = $1 AND user_id = $2 AND organization_id = $3 AND role = 'REVIEWER'`
+		insertSQL = `INSERT INTO pull_request_reviewers (pull_request_id, user_id, organization_id) VALUES ($1, $2, $3)`
 	)
+	organizationID := tenant.Organization(ctx)
 
 	for _, swap := range swaps {
 		if swap.PullRequestId == "" || swap.OldUserId == "" || swap.NewUserId == "" {
 			return fmt.Errorf("invalid reviewer swap payload: %+v", swap)
 		}
-		if _, err := tx.Exec(ctx, deleteSQL, swap.PullRequestId, swap.OldUserId); err != nil {
+		if _, err := tx.Exec(ctx, deleteSQL, swap.PullRequestId, swap.OldUserId, organizationID); err != nil {
 			return fmt.Errorf("delete reviewer %s for pr %s: %w", swap.OldUserId, swap.PullRequestId, err)
 		}
-		if _, err := tx.Exec(ctx, insertSQL, swap.PullRequestId, swap.NewUserId); err != nil {
+		if _, err := tx.Exec(ctx, insertSQL, swap.PullRequestId, swap.NewUserId, organizationID); err != nil {
 			return fmt.Errorf("insert reviewer %s for pr %s: %w", swap.NewUserId, swap.PullRequestId, err)
 		}
 	}
@@ -449,8 +825,8 @@ func (s *Storage) deactivateUsersTx(ctx context.Context, tx pgx.Tx, users []stri
 	for id := range uniq {
 		ids = append(ids, id)
 	}
-	const updateSQL = `UPDATE users SET is_active = false WHERE user_id = ANY($1)`
-	if _, err := tx.Exec(ctx, updateSQL, ids); err != nil {
+	const updateSQL = `UPDATE users SET is_active = false WHERE user_id = ANY($1) AND organization_id = $2`
+	if _, err := tx.Exec(ctx, updateSQL, ids, tenant.Organization(ctx)); err != nil {
 		return fmt.Errorf("bulk deactivate users: %w", err)
 	}
 	return nil
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const selectRepositoriesSQL = `
+SELECT repository_name, COALESCE(owner_team, ''), required_reviewers
+FROM repositories
+WHERE organization_id = $1
+`
+
+const upsertRepositorySQL = `
+INSERT INTO repositories (repository_name, owner_team, required_reviewers, organization_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (organization_id, repository_name) DO UPDATE
+SET owner_team = EXCLUDED.owner_team, required_reviewers = EXCLUDED.required_reviewers
+`
+
+// SaveRepository создаёт репозиторий или заменяет его владельца и правила ревью.
+func (s *Storage) SaveRepository(ctx context.Context, repo *models.Repository) error {
+	if repo == nil {
+		return fmt.Errorf("repository is nil")
+	}
+	if _, err := s.pool.Exec(ctx, upsertRepositorySQL, repositoryArgs(tenant.Organization(ctx), repo)...); err != nil {
+		return fmt.Errorf("save repository: %w", err)
+	}
+	return nil
+}
+
+// GetRepository возвращает репозиторий по имени; NOT_FOUND, если его нет.
+func (s *Storage) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	repos, err := s.queryRepositories(ctx, selectRepositoriesSQL+`AND repository_name = $2`, tenant.Organization(ctx), name)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		return nil, domain.NewNotFoundError("repository " + name)
+	}
+	return &repos[0], nil
+}
+
+// ListRepositories возвращает все репозитории по имени.
+func (s *Storage) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	return s.queryRepositories(ctx, selectRepositoriesSQL+`ORDER BY repository_name`, tenant.Organization(ctx))
+}
+
+// queryRepositories выполняет выборку из repositories.
+func (s *Storage) queryRepositories(ctx context.Context, q string, args ...any) ([]models.Repository, error) {
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query repositories: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Repository, 0)
+	for rows.Next() {
+		repo, err := scanRepository(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan repository: %w", err)
+		}
+		result = append(result, *repo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows repositories: %w", err)
+	}
+	return result, nil
+}
+
+// scanRepository читает строку selectRepositoriesSQL.
+func scanRepository(row interface{ Scan(dest ...any) error }) (*models.Repository, error) {
+	var repo models.Repository
+	if err := row.Scan(&repo.RepositoryName, &repo.OwnerTeam, &repo.RequiredReviewers); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// repositoryArgs раскладывает репозиторий по параметрам upsertRepositorySQL; пустой владелец хранится как NULL.
+func repositoryArgs(organizationID string, repo *models.Repository) []any {
+	var owner *string
+	if repo.OwnerTeam != "" {
+		owner = &repo.OwnerTeam
+	}
+	return []any{repo.RepositoryName, owner, repo.RequiredReviewers, organizationID}
+}
@@ -0,0 +1,1515 @@
+// Package repotest содержит общий контрактный набор тестов для всех реализаций репозиториев.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// Backend объединяет интерфейсы репозиториев, которые должна реализовать каждая подсистема хранения.
+type Backend interface {
+	service.PullRequestRepository
+	service.UserTeamRepository
+	service.SnapshotRepository
+	service.StaleReviewRepository
+	service.AbsenceRepository
+	service.WorkingHoursRepository
+	service.CapacityRepository
+	service.ReviewQueueRepository
+	service.RepositoryStore
+	service.OrganizationRepository
+	service.AffinityRuleRepository
+	service.LearnerRepository
+	service.DecisionRepository
+}
+
+// Factory возвращает пустое хранилище для очередного теста.
+type Factory func(t *testing.T) Backend
+
+// RunContract прогоняет контрактные тесты против хранилища, созданного factory.
+func RunContract(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("teams", func(t *testing.T) { testTeams(t, factory(t)) })
+	t.Run("users", func(t *testing.T) { testUsers(t, factory(t)) })
+	t.Run("bulk import", func(t *testing.T) { testBulkImport(t, factory(t)) })
+	t.Run("pull requests", func(t *testing.T) { testPullRequests(t, factory(t)) })
+	t.Run("reviewer queries", func(t *testing.T) { testReviewerQueries(t, factory(t)) })
+	t.Run("reviewer queries keep co-reviewers", func(t *testing.T) { testReviewerQueriesKeepCoReviewers(t, factory(t)) })
+	t.Run("assignment stats", func(t *testing.T) { testAssignmentStats(t, factory(t)) })
+	t.Run("turnaround stats", func(t *testing.T) { testTurnaroundStats(t, factory(t)) })
+	t.Run("streams", func(t *testing.T) { testStreams(t, factory(t)) })
+	t.Run("bulk swaps", func(t *testing.T) { testBulkSwaps(t, factory(t)) })
+	t.Run("bulk swaps are atomic", func(t *testing.T) { testBulkSwapsAtomic(t, factory(t)) })
+	t.Run("snapshot", func(t *testing.T) { testSnapshot(t, factory) })
+	t.Run("review activity", func(t *testing.T) { testReviewActivity(t, factory(t)) })
+	t.Run("review rotations", func(t *testing.T) { testReviewRotations(t, factory(t)) })
+	t.Run("leases", func(t *testing.T) { testLeases(t, factory(t)) })
+	t.Run("absences", func(t *testing.T) { testAbsences(t, factory(t)) })
+	t.Run("working hours", func(t *testing.T) { testWorkingHours(t, factory(t)) })
+	t.Run("review load", func(t *testing.T) { testReviewLoad(t, factory(t)) })
+	t.Run("review queue", func(t *testing.T) { testReviewQueue(t, factory(t)) })
+	t.Run("repositories", func(t *testing.T) { testRepositories(t, factory(t)) })
+	t.Run("organizations", func(t *testing.T) { testOrganizations(t, factory(t)) })
+	t.Run("tenant isolation", func(t *testing.T) { testTenantIsolation(t, factory(t)) })
+	t.Run("affinity rules", func(t *testing.T) { testAffinityRules(t, factory(t)) })
+	t.Run("shadow reviewers", func(t *testing.T) { testShadowReviewers(t, factory(t)) })
+	t.Run("assignment decisions", func(t *testing.T) { testAssignmentDecisions(t, factory(t)) })
+}
+
+// ---------- сценарии ----------
+
+func testTeams(t *testing.T, repo Backend) {
+	ctx := context.Background()
+
+	_, err := repo.GetTeam(ctx, "backend")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "u2", Username: "Bob", IsActive: true},
+		models.User{UserId: "u1", Username: "Alice", IsActive: false},
+	)
+
+	team, err := repo.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, "backend", team.TeamName)
+	require.Equal(t, []models.TeamMember{
+		{UserId: "u1", Username: "Alice", IsActive: false},
+		{UserId: "u2", Username: "Bob", IsActive: true},
+	}, team.Members)
+
+	err = repo.CreateTeamWithMembers(ctx, &models.Team{TeamName: "backend"}, []models.User{
+		{UserId: "u1", Username: "Renamed", IsActive: true, TeamName: "backend"},
+	})
+	require.ErrorIs(t, err, domain.ErrTeamExists)
+
+	user, err := repo.GetUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user.Username, "failed team creation must not touch users")
+
+	require.NoError(t, repo.SaveTeam(ctx, &models.Team{TeamName: "frontend"}))
+	require.ErrorIs(t, repo.SaveTeam(ctx, &models.Team{TeamName: "frontend"}), domain.ErrTeamExists)
+
+	empty, err := repo.GetTeam(ctx, "frontend")
+	require.NoError(t, err)
+	require.Empty(t, empty.Members)
+}
+
+func testUsers(t *testing.T, repo Backend) {
+	ctx := context.Background()
+
+	_, err := repo.GetUser(ctx, "ghost")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	seedTeam(t, repo, "backend", models.User{UserId: "u1", Username: "Alice", IsActive: true})
+
+	require.NoError(t, repo.SaveUser(ctx, &models.User{UserId: "u1", Username: "Alice", IsActive: false, TeamName: "backend"}))
+	require.NoError(t, repo.SaveUser(ctx, &models.User{UserId: "u2", Username: "Zed", IsActive: true, TeamName: "backend"}))
+	require.NoError(t, repo.SaveUser(ctx, &models.User{UserId: "u3", Username: "Loner", IsActive: true}))
+	require.Error(t, repo.SaveUser(ctx, &models.User{UserId: "u4", Username: "Lost", TeamName: "missing"}))
+
+	user, err := repo.GetUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, models.User{UserId: "u1", Username: "Alice", IsActive: false, TeamName: "backend"}, *user)
+
+	loner, err := repo.GetUser(ctx, "u3")
+	require.NoError(t, err)
+	require.Empty(t, loner.TeamName)
+
+	members, err := repo.GetAllUsersInTeam(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, "u1", members[0].UserId)
+	require.Equal(t, "u2", members[1].UserId)
+}
+
+func testBulkImport(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", models.User{UserId: "u1", Username: "Alice", IsActive: true})
+
+	require.NoError(t, repo.CreateTeamsWithMembers(ctx, []string{"frontend", "ops"}, []models.User{
+		{UserId: "u1", Username: "Alice", IsActive: false, TeamName: "frontend"},
+		{UserId: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+		{UserId: "u3", Username: "Carol", IsActive: true, TeamName: "ops"},
+	}))
+
+	moved, err := repo.GetUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, models.User{UserId: "u1", Username: "Alice", IsActive: false, TeamName: "frontend"}, *moved)
+	backend, err := repo.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []models.TeamMember{{UserId: "u2", Username: "Bob", IsActive: true}}, backend.Members)
+	ops, err := repo.GetTeam(ctx, "ops")
+	require.NoError(t, err)
+	require.Len(t, ops.Members, 1)
+
+	// Существующая команда в списке новых отменяет весь импорт.
+	err = repo.CreateTeamsWithMembers(ctx, []string{"qa", "backend"}, []models.User{
+		{UserId: "u4", Username: "Dan", IsActive: true, TeamName: "qa"},
+	})
+	require.ErrorIs(t, err, domain.ErrTeamExists)
+	_, err = repo.GetTeam(ctx, "qa")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetUser(ctx, "u4")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Ссылка на несозданную команду тоже ничего не сохраняет.
+	require.Error(t, repo.CreateTeamsWithMembers(ctx, []string{"qa"}, []models.User{
+		{UserId: "u4", Username: "Dan", IsActive: true, TeamName: "qa"},
+		{UserId: "u5", Username: "Eve", IsActive: true, TeamName: "missing"},
+	}))
+	_, err = repo.GetTeam(ctx, "qa")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testPullRequests(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+		models.User{UserId: "r3", Username: "R3", IsActive: true},
+	)
+
+	_, err := repo.GetPullRequest(ctx, "pr-1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	created := testTime(0)
+	pr := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add docs",
+		AuthorId:          "author",
+		Status:            models.PullRequestStatusOPEN,
+		CreatedAt:         &created,
+		AssignedReviewers: []string{"r2", "r1"},
+		LinesAdded:        120,
+		LinesDeleted:      30,
+		FilesChanged:      4,
+		Priority:          models.PullRequestPriorityHIGH,
+		Labels:            []string{"docs", "backend"},
+	}
+	require.NoError(t, repo.SavePullRequest(ctx, pr))
+
+	got, err := repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, "Add docs", got.PullRequestName)
+	require.Equal(t, models.PullRequestStatusOPEN, got.Status)
+	require.Equal(t, []string{"r1", "r2"}, got.AssignedReviewers)
+	requireSameTime(t, &created, got.CreatedAt)
+	require.Nil(t, got.MergedAt)
+	require.Equal(t, [3]int{120, 30, 4}, [3]int{got.LinesAdded, got.LinesDeleted, got.FilesChanged})
+	require.Equal(t, models.PullRequestPriorityHIGH, got.Priority)
+	require.Equal(t, []string{"docs", "backend"}, got.Labels, "labels keep their order")
+
+	merged := testTime(time.Hour)
+	pr.Status = models.PullRequestStatusMERGED
+	pr.MergedAt = &merged
+	pr.AssignedReviewers = []string{"r3"}
+	require.NoError(t, repo.SavePullRequest(ctx, pr))
+
+	got, err = repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, models.PullRequestStatusMERGED, got.Status)
+	require.Equal(t, []string{"r3"}, got.AssignedReviewers)
+	requireSameTime(t, &merged, got.MergedAt)
+	require.Equal(t, []string{"docs", "backend"}, got.Labels)
+
+	pr.Labels = nil
+	require.NoError(t, repo.SavePullRequest(ctx, pr))
+	got, err = repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Nil(t, got.Labels, "no labels read back as nil")
+
+	tooMany := *pr
+	tooMany.AssignedReviewers = []string{"r1", "r2", "r3"}
+	require.Error(t, repo.SavePullRequest(ctx, &tooMany))
+
+	unknownAuthor := *pr
+	unknownAuthor.PullRequestId = "pr-2"
+	unknownAuthor.AuthorId = "ghost"
+	require.Error(t, repo.SavePullRequest(ctx, &unknownAuthor))
+	_, err = repo.GetPullRequest(ctx, "pr-2")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	unknownReviewer := *pr
+	unknownReviewer.AssignedReviewers = []string{"ghost"}
+	require.Error(t, repo.SavePullRequest(ctx, &unknownReviewer))
+	got, err = repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"r3"}, got.AssignedReviewers, "failed save must not change reviewers")
+}
+
+func testReviewerQueries(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+	)
+	seedPR(t, repo, "pr-old", models.PullRequestStatusOPEN, 0, "r1")
+	seedPR(t, repo, "pr-new", models.PullRequestStatusOPEN, time.Hour, "r1", "r2")
+	seedPR(t, repo, "pr-merged", models.PullRequestStatusMERGED, 2*time.Hour, "r2")
+	updatePR(t, repo, "pr-new", func(pr *models.PullRequest) {
+		pr.Priority = models.PullRequestPriorityURGENT
+		pr.Labels = []string{"hotfix"}
+	})
+
+	byReviewer, err := repo.FindPullRequestsByReviewer(ctx, "r1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-new", "pr-old"}, prIDs(byReviewer))
+	require.Equal(t, []string{"r1", "r2"}, byReviewer[0].AssignedReviewers, "all reviewers of the PR are returned")
+	require.Equal(t, models.PullRequestPriorityURGENT, byReviewer[0].Priority)
+	require.Equal(t, []string{"hotfix"}, byReviewer[0].Labels)
+
+	none, err := repo.FindPullRequestsByReviewer(ctx, "author")
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	open, err := repo.FindOpenPullRequestsByReviewers(ctx, []string{"r2", "", "r2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-new"}, prIDs(open))
+	require.Equal(t, models.PullRequestPriorityURGENT, open[0].Priority)
+
+	empty, err := repo.FindOpenPullRequestsByReviewers(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+func testAssignmentStats(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+	)
+	seedPR(t, repo, "pr-a", models.PullRequestStatusOPEN, 0, "r1", "r2")
+	seedPR(t, repo, "pr-b", models.PullRequestStatusMERGED, time.Hour, "r2")
+	seedPR(t, repo, "pr-c", models.PullRequestStatusOPEN, 2*time.Hour)
+	// 500 строк — вес 3.
+	updatePR(t, repo, "pr-b", func(pr *models.PullRequest) { pr.LinesAdded = 500 })
+
+	stats, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []models.UserAssignmentStat{
+		{UserId: "r2", Username: "R2", Assignments: 2, WeightedLoad: 4},
+		{UserId: "r1", Username: "R1", Assignments: 1, WeightedLoad: 1},
+	}, stats.ByUser)
+	require.Equal(t, []models.PullRequestAssignmentStat{
+		{PullRequestId: "pr-a", PullRequestName: "pr-a", ReviewerCount: 2},
+		{PullRequestId: "pr-b", PullRequestName: "pr-b", ReviewerCount: 1},
+		{PullRequestId: "pr-c", PullRequestName: "pr-c", ReviewerCount: 0},
+	}, stats.ByPullRequest)
+	require.Len(t, stats.ByTeam, 1)
+	team := stats.ByTeam[0]
+	require.Equal(t, "backend", team.TeamName)
+	require.Equal(t, 2, team.OpenCount)
+	require.Equal(t, 1, team.MergedCount)
+	require.InDelta(t, 1.0, team.AvgReviewers, 0.001)
+	// Нагрузка активных участников 0, 1 и 2: σ/μ = sqrt(2/3).
+	require.InDelta(t, 0.8165, team.LoadImbalance, 0.001)
+
+	merged, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{Status: models.PullRequestStatusMERGED})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-b"}, statPRIDs(merged))
+	require.Equal(t, []models.UserAssignmentStat{{UserId: "r2", Username: "R2", Assignments: 1, WeightedLoad: 3}}, merged.ByUser)
+	require.Len(t, merged.ByTeam, 1)
+	require.Equal(t, 0, merged.ByTeam[0].OpenCount)
+	require.Equal(t, 1, merged.ByTeam[0].MergedCount)
+
+	windowed, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{From: testTime(30 * time.Minute), To: testTime(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-b"}, statPRIDs(windowed))
+
+	limited, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{TeamName: "backend", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-a"}, statPRIDs(limited))
+	require.Len(t, limited.ByUser, 1)
+	require.Equal(t, "r2", limited.ByUser[0].UserId)
+	require.Len(t, limited.ByTeam, 1, "limit must not truncate team aggregates")
+
+	other, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{TeamName: "frontend"})
+	require.NoError(t, err)
+	require.Empty(t, other.ByUser)
+	require.Empty(t, other.ByPullRequest)
+	require.Empty(t, other.ByTeam)
+}
+
+// testReviewerQueriesKeepCoReviewers — регрессия: фильтр по ревьюеру не должен обрезать
+// списки ревьюеров найденного PR до одного этого ревьюера.
+func testReviewerQueriesKeepCoReviewers(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+		models.User{UserId: "mentee", Username: "Mentee", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1", "r2")
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.ShadowReviewers = []string{"mentee"} })
+
+	for _, reviewer := range []string{"r1", "r2", "mentee"} {
+		prs, err := repo.FindPullRequestsByReviewer(ctx, reviewer)
+		require.NoError(t, err)
+		require.Len(t, prs, 1, "PR of %s is returned once", reviewer)
+		require.Equal(t, []string{"r1", "r2"}, prs[0].AssignedReviewers, "reviewers of PR found by %s", reviewer)
+		require.Equal(t, []string{"mentee"}, prs[0].ShadowReviewers, "shadows of PR found by %s", reviewer)
+
+		var streamed []*models.PullRequest
+		require.NoError(t, repo.StreamPullRequestsByReviewer(ctx, reviewer, func(pr *models.PullRequest) error {
+			streamed = append(streamed, pr)
+			return nil
+		}))
+		require.Equal(t, prs, streamed, "stream and find agree for %s", reviewer)
+	}
+}
+
+func testStreams(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Hour, "r1")
+
+	var streamed []string
+	require.NoError(t, repo.StreamPullRequestsByReviewer(ctx, "r1", func(pr *models.PullRequest) error {
+		streamed = append(streamed, pr.PullRequestId)
+		return nil
+	}))
+	require.Equal(t, []string{"pr-2", "pr-1"}, streamed)
+
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.StreamPullRequestAssignments(ctx, models.AssignmentStatsFilter{}, func(models.PullRequestAssignmentStat) error {
+		calls++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 1, calls, "callback error must stop the stream")
+
+	var users []models.UserAssignmentStat
+	require.NoError(t, repo.StreamUserAssignments(ctx, models.AssignmentStatsFilter{}, func(stat models.UserAssignmentStat) error {
+		users = append(users, stat)
+		return nil
+	}))
+	require.Equal(t, []models.UserAssignmentStat{{UserId: "r1", Username: "R1", Assignments: 2, WeightedLoad: 2}}, users)
+
+	teams, err := repo.GetTeamAssignmentStats(ctx, models.AssignmentStatsFilter{})
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	require.Equal(t, 2, teams[0].OpenCount)
+}
+
+func testTurnaroundStats(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+	)
+	seedMerged := func(id string, offset, took time.Duration, reviewers ...string) {
+		t.Helper()
+		created := testTime(offset)
+		merged := created.Add(took)
+		require.NoError(t, repo.SavePullRequest(ctx, &models.PullRequest{
+			PullRequestId:     id,
+			PullRequestName:   id,
+			AuthorId:          "author",
+			Status:            models.PullRequestStatusMERGED,
+			CreatedAt:         &created,
+			MergedAt:          &merged,
+			AssignedReviewers: reviewers,
+		}))
+	}
+	seedMerged("pr-1", 0, time.Minute, "r1")
+	seedMerged("pr-2", time.Hour, 2*time.Minute, "r1", "r2")
+	seedMerged("pr-3", 2*time.Hour, 10*time.Minute+500*time.Millisecond, "r2")
+	seedMerged("pr-late", 48*time.Hour, time.Minute, "r1")
+	seedPR(t, repo, "pr-open", models.PullRequestStatusOPEN, 0, "r1")
+
+	filter := models.TurnaroundFilter{From: testTime(-time.Hour), To: testTime(24 * time.Hour)}
+	stats, err := repo.GetTurnaroundStats(ctx, filter)
+	require.NoError(t, err)
+	require.True(t, filter.From.Equal(stats.From))
+	require.True(t, filter.To.Equal(stats.To))
+
+	requirePercentiles := func(want, got models.TurnaroundPercentiles) {
+		t.Helper()
+		require.Equal(t, want.Count, got.Count)
+		require.InDelta(t, want.P50Seconds, got.P50Seconds, 0.001)
+		require.InDelta(t, want.P90Seconds, got.P90Seconds, 0.001)
+		require.InDelta(t, want.P99Seconds, got.P99Seconds, 0.001)
+	}
+
+	requirePercentiles(models.TurnaroundPercentiles{Count: 3, P50Seconds: 120, P90Seconds: 600.5, P99Seconds: 600.5}, stats.Overall)
+	require.Len(t, stats.ByTeam, 1)
+	require.Equal(t, "backend", stats.ByTeam[0].TeamName)
+	requirePercentiles(stats.Overall, stats.ByTeam[0].TurnaroundPercentiles)
+	require.Len(t, stats.ByAuthor, 1)
+	require.Equal(t, "author", stats.ByAuthor[0].UserId)
+
+	require.Len(t, stats.ByReviewer, 2)
+	require.Equal(t, "r1", stats.ByReviewer[0].UserId)
+	requirePercentiles(models.TurnaroundPercentiles{Count: 2, P50Seconds: 60, P90Seconds: 120, P99Seconds: 120}, stats.ByReviewer[0].TurnaroundPercentiles)
+	require.Equal(t, "r2", stats.ByReviewer[1].UserId)
+	requirePercentiles(models.TurnaroundPercentiles{Count: 2, P50Seconds: 120, P90Seconds: 600.5, P99Seconds: 600.5}, stats.ByReviewer[1].TurnaroundPercentiles)
+
+	empty, err := repo.GetTurnaroundStats(ctx, models.TurnaroundFilter{From: testTime(-48 * time.Hour), To: testTime(-24 * time.Hour)})
+	require.NoError(t, err)
+	require.Zero(t, empty.Overall.Count)
+	require.Empty(t, empty.ByTeam)
+	require.Empty(t, empty.ByReviewer)
+}
+
+func testBulkSwaps(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+		models.User{UserId: "r3", Username: "R3", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1", "r2")
+
+	require.NoError(t, repo.ApplyBulkTeamReviewerSwaps(ctx, nil, nil))
+
+	err := repo.ApplyBulkTeamReviewerSwaps(ctx,
+		[]models.ReviewerSwap{{PullRequestId: "pr-1", OldUserId: "r1", NewUserId: "r3"}},
+		[]string{"r1", "r3"},
+	)
+	require.NoError(t, err)
+
+	pr, err := repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"r2", "r3"}, pr.AssignedReviewers)
+
+	for id, active := range map[string]bool{"r1": false, "r2": true, "r3": false} {
+		user, err := repo.GetUser(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, active, user.IsActive, "user %s", id)
+	}
+
+	err = repo.ApplyBulkTeamReviewerSwaps(ctx, []models.ReviewerSwap{{PullRequestId: "pr-1", OldUserId: "r2"}}, nil)
+	require.Error(t, err)
+}
+
+func testBulkSwapsAtomic(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+		models.User{UserId: "r3", Username: "R3", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Hour, "r2")
+
+	// Вторая замена ссылается на несуществующего пользователя, поэтому откатиться должна и первая.
+	err := repo.ApplyBulkTeamReviewerSwaps(ctx, []models.ReviewerSwap{
+		{PullRequestId: "pr-1", OldUserId: "r1", NewUserId: "r3"},
+		{PullRequestId: "pr-2", OldUserId: "r2", NewUserId: "ghost"},
+	}, []string{"r1"})
+	require.Error(t, err)
+
+	pr, err := repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"r1"}, pr.AssignedReviewers)
+
+	user, err := repo.GetUser(ctx, "r1")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+}
+
+func testReviewActivity(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+		models.User{UserId: "r3", Username: "R3", IsActive: true},
+	)
+	seedPR(t, repo, "pr-open", models.PullRequestStatusOPEN, 0, "r1", "r2")
+	seedPR(t, repo, "pr-merged", models.PullRequestStatusMERGED, time.Hour, "r1")
+
+	assignments, err := repo.FindOpenReviewAssignments(ctx)
+	require.NoError(t, err)
+	require.Len(t, assignments, 2, "merged PRs are not tracked")
+	for _, a := range assignments {
+		require.Equal(t, "pr-open", a.PullRequestId)
+		require.Equal(t, "backend", a.TeamName)
+		require.Zero(t, a.Rotations)
+		require.False(t, a.LastActivityAt.IsZero())
+	}
+
+	touched := testTime(48 * time.Hour)
+	require.NoError(t, repo.TouchReviewActivity(ctx, "pr-open", "r1", touched))
+	require.ErrorIs(t, repo.TouchReviewActivity(ctx, "pr-open", "r3", touched), domain.ErrNotAssigned)
+	require.ErrorIs(t, repo.TouchReviewActivity(ctx, "ghost", "r1", touched), domain.ErrNotAssigned)
+
+	// Замена второго ревьюера не сбрасывает активность оставшегося.
+	pr, err := repo.GetPullRequest(ctx, "pr-open")
+	require.NoError(t, err)
+	pr.AssignedReviewers = []string{"r1", "r3"}
+	require.NoError(t, repo.SavePullRequest(ctx, pr))
+
+	assignments, err = repo.FindOpenReviewAssignments(ctx)
+	require.NoError(t, err)
+	activity := make(map[string]time.Time, len(assignments))
+	for _, a := range assignments {
+		activity[a.ReviewerId] = a.LastActivityAt
+	}
+	require.Len(t, activity, 2)
+	r1 := activity["r1"]
+	requireSameTime(t, &touched, &r1)
+	require.Contains(t, activity, "r3")
+}
+
+func testReviewRotations(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r2")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Hour, "r1")
+
+	empty, err := repo.ListReviewRotations(ctx, models.ReviewRotationFilter{})
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	first := models.ReviewRotation{
+		PullRequestId: "pr-1", OldUserId: "r1", NewUserId: "r2", TeamName: "backend",
+		IdleSeconds: 3600, RotatedAt: testTime(time.Hour),
+	}
+	second := models.ReviewRotation{
+		PullRequestId: "pr-2", OldUserId: "r2", NewUserId: "r1", TeamName: "backend",
+		IdleSeconds: 7200, RotatedAt: testTime(2 * time.Hour),
+	}
+	require.NoError(t, repo.RecordReviewRotation(ctx, &first))
+	require.NoError(t, repo.RecordReviewRotation(ctx, &second))
+	require.Error(t, repo.RecordReviewRotation(ctx, &models.ReviewRotation{
+		PullRequestId: "ghost", OldUserId: "r1", NewUserId: "r2", RotatedAt: testTime(0),
+	}))
+
+	all, err := repo.ListReviewRotations(ctx, models.ReviewRotationFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "pr-2", all[0].PullRequestId, "newest first")
+	require.Equal(t, int64(7200), all[0].IdleSeconds)
+	requireSameTime(t, &second.RotatedAt, &all[0].RotatedAt)
+
+	byPR, err := repo.ListReviewRotations(ctx, models.ReviewRotationFilter{PullRequestId: "pr-1"})
+	require.NoError(t, err)
+	require.Len(t, byPR, 1)
+	require.Equal(t, "r1", byPR[0].OldUserId)
+	require.Equal(t, "r2", byPR[0].NewUserId)
+
+	limited, err := repo.ListReviewRotations(ctx, models.ReviewRotationFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+
+	assignments, err := repo.FindOpenReviewAssignments(ctx)
+	require.NoError(t, err)
+	for _, a := range assignments {
+		require.Equal(t, 1, a.Rotations, "pr %s", a.PullRequestId)
+	}
+}
+
+func testLeases(t *testing.T, repo Backend) {
+	ctx := context.Background()
+
+	ok, err := repo.AcquireLease(ctx, "job", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.AcquireLease(ctx, "job", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok, "lease is held by another instance")
+
+	ok, err = repo.AcquireLease(ctx, "job", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok, "holder renews its lease")
+
+	ok, err = repo.AcquireLease(ctx, "other", "b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok, "leases are independent")
+
+	// Отрицательный срок создаёт уже истёкшую аренду.
+	ok, err = repo.AcquireLease(ctx, "expired", "a", -time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.AcquireLease(ctx, "expired", "b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok, "expired lease can be taken over")
+}
+
+func testAbsences(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "u1", Username: "Alice", IsActive: true},
+		models.User{UserId: "u2", Username: "Bob", IsActive: true},
+	)
+
+	vacation := &models.Absence{
+		UserId: "u1", StartsAt: testTime(0), EndsAt: testTime(72 * time.Hour),
+		Reason: "vacation", CreatedAt: testTime(-time.Hour),
+	}
+	require.NoError(t, repo.CreateAbsence(ctx, vacation))
+	require.NotZero(t, vacation.AbsenceId)
+	sick := &models.Absence{UserId: "u1", StartsAt: testTime(-48 * time.Hour), EndsAt: testTime(-24 * time.Hour), CreatedAt: testTime(-49 * time.Hour)}
+	require.NoError(t, repo.CreateAbsence(ctx, sick))
+	require.NotEqual(t, vacation.AbsenceId, sick.AbsenceId)
+	require.Error(t, repo.CreateAbsence(ctx, &models.Absence{
+		UserId: "ghost", StartsAt: testTime(0), EndsAt: testTime(time.Hour), CreatedAt: testTime(0),
+	}), "absence must reference an existing user")
+
+	got, err := repo.GetAbsence(ctx, vacation.AbsenceId)
+	require.NoError(t, err)
+	require.Equal(t, "u1", got.UserId)
+	require.Equal(t, "vacation", got.Reason)
+	requireSameTime(t, &vacation.StartsAt, &got.StartsAt)
+	requireSameTime(t, &vacation.EndsAt, &got.EndsAt)
+	requireSameTime(t, &vacation.CreatedAt, &got.CreatedAt)
+	require.Nil(t, got.ReassignedAt)
+	_, err = repo.GetAbsence(ctx, 9999)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	list, err := repo.ListAbsences(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, sick.AbsenceId, list[0].AbsenceId, "absences are ordered by start")
+	list, err = repo.ListAbsences(ctx, "u2")
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	absent, err := repo.FindAbsentUsers(ctx, testTime(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"u1"}, absent)
+	absent, err = repo.FindAbsentUsers(ctx, testTime(72*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, absent, "the period end is exclusive")
+
+	pending, err := repo.FindAbsencesToReassign(ctx, testTime(0))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, vacation.AbsenceId, pending[0].AbsenceId)
+
+	require.NoError(t, repo.MarkAbsenceReassigned(ctx, vacation.AbsenceId, testTime(time.Minute)))
+	pending, err = repo.FindAbsencesToReassign(ctx, testTime(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	got, err = repo.GetAbsence(ctx, vacation.AbsenceId)
+	require.NoError(t, err)
+	reassignedAt := testTime(time.Minute)
+	requireSameTime(t, &reassignedAt, got.ReassignedAt)
+	require.ErrorIs(t, repo.MarkAbsenceReassigned(ctx, 9999, testTime(0)), domain.ErrNotFound)
+
+	require.NoError(t, repo.DeleteAbsence(ctx, vacation.AbsenceId))
+	require.ErrorIs(t, repo.DeleteAbsence(ctx, vacation.AbsenceId), domain.ErrNotFound)
+	absent, err = repo.FindAbsentUsers(ctx, testTime(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, absent)
+}
+
+func testWorkingHours(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "u1", Username: "Alice", IsActive: true},
+		models.User{UserId: "u2", Username: "Bob", IsActive: true},
+		models.User{UserId: "u3", Username: "Carol", IsActive: true},
+	)
+
+	berlin := models.WorkingHours{UserId: "u1", TimeZone: "Europe/Berlin", Start: "09:00", End: "17:00"}
+	require.NoError(t, repo.SaveWorkingHours(ctx, &berlin))
+	tokyo := models.WorkingHours{UserId: "u2", TimeZone: "Asia/Tokyo", Start: "10:00", End: "19:00"}
+	require.NoError(t, repo.SaveWorkingHours(ctx, &tokyo))
+	require.Error(t, repo.SaveWorkingHours(ctx, &models.WorkingHours{
+		UserId: "ghost", TimeZone: "UTC", Start: "09:00", End: "17:00",
+	}), "working hours must reference an existing user")
+
+	got, err := repo.GetWorkingHours(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, berlin, *got)
+	_, err = repo.GetWorkingHours(ctx, "u3")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	berlin.Start, berlin.End = "08:00", "16:00"
+	require.NoError(t, repo.SaveWorkingHours(ctx, &berlin), "saving again replaces the schedule")
+	got, err = repo.GetWorkingHours(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "08:00", got.Start)
+
+	found, err := repo.FindWorkingHours(ctx, []string{"u2", "u3", "u1", "u2"})
+	require.NoError(t, err)
+	require.Equal(t, []models.WorkingHours{berlin, tokyo}, found, "users without a schedule are skipped")
+	found, err = repo.FindWorkingHours(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	require.NoError(t, repo.DeleteWorkingHours(ctx, "u1"))
+	require.ErrorIs(t, repo.DeleteWorkingHours(ctx, "u1"), domain.ErrNotFound)
+	found, err = repo.FindWorkingHours(ctx, []string{"u1"})
+	require.NoError(t, err)
+	require.Empty(t, found)
+}
+
+func testReviewLoad(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "u1", Username: "Alice", IsActive: true},
+		models.User{UserId: "u2", Username: "Bob", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "u1", "u2")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Minute, "u1")
+	seedPR(t, repo, "pr-3", models.PullRequestStatusMERGED, 2*time.Minute, "u1", "u2")
+	// 30 файлов — вес 3.
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.FilesChanged = 30 })
+
+	require.NoError(t, repo.SaveCapacity(ctx, "u1", 3))
+	require.NoError(t, repo.SaveCapacity(ctx, "u1", 2), "saving again replaces the capacity")
+	require.Error(t, repo.SaveCapacity(ctx, "ghost", 1), "capacity must reference an existing user")
+
+	loads, err := repo.FindReviewLoad(ctx, []string{"u2", "ghost", "u1", "u2"})
+	require.NoError(t, err)
+	require.Equal(t, []models.ReviewerLoad{
+		{UserId: "u1", OpenReviews: 2, WeightedLoad: 4, MaxOpenReviews: 2},
+		{UserId: "u2", OpenReviews: 1, WeightedLoad: 3},
+	}, loads, "merged PRs are not counted, unknown users are skipped")
+	loads, err = repo.FindReviewLoad(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, loads)
+
+	open, err := repo.CountOpenReviews(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"u1": 2, "u2": 1}, open)
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.Status = models.PullRequestStatusMERGED })
+	open, err = repo.CountOpenReviews(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"u1": 1, "u2": 0}, open, "reviewers without open reviews are reported with 0")
+	other, err := repo.CountOpenReviews(tenant.WithOrganization(ctx, "acme"))
+	require.NoError(t, err)
+	require.Empty(t, other, "open reviews are counted per organization")
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.Status = models.PullRequestStatusOPEN })
+
+	require.NoError(t, repo.DeleteCapacity(ctx, "u1"))
+	require.NoError(t, repo.DeleteCapacity(ctx, "u1"), "deleting a missing capacity is a no-op")
+	loads, err = repo.FindReviewLoad(ctx, []string{"u1"})
+	require.NoError(t, err)
+	require.Equal(t, []models.ReviewerLoad{{UserId: "u1", OpenReviews: 2, WeightedLoad: 4}}, loads)
+}
+
+func testReviewQueue(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", models.User{UserId: "author", Username: "Author", IsActive: true})
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0)
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Minute)
+
+	require.NoError(t, repo.EnqueueReview(ctx, models.QueuedReview{PullRequestId: "pr-2", Missing: 2, QueuedAt: testTime(0)}))
+	require.NoError(t, repo.EnqueueReview(ctx, models.QueuedReview{PullRequestId: "pr-1", Missing: 1, QueuedAt: testTime(time.Minute)}))
+	require.NoError(t, repo.EnqueueReview(ctx, models.QueuedReview{PullRequestId: "pr-2", Missing: 1, QueuedAt: testTime(time.Hour)}),
+		"enqueueing again updates the missing count")
+	require.Error(t, repo.EnqueueReview(ctx, models.QueuedReview{PullRequestId: "ghost", Missing: 1, QueuedAt: testTime(0)}),
+		"queued review must reference an existing pull request")
+
+	queued, err := repo.ListQueuedReviews(ctx)
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	require.Equal(t, "pr-2", queued[0].PullRequestId, "re-enqueueing keeps the place in the queue")
+	require.Equal(t, 1, queued[0].Missing)
+	require.True(t, testTime(0).Equal(queued[0].QueuedAt))
+	require.Equal(t, "pr-1", queued[1].PullRequestId)
+
+	require.NoError(t, repo.DequeueReview(ctx, "pr-2"))
+	require.NoError(t, repo.DequeueReview(ctx, "pr-2"), "dequeueing a missing item is a no-op")
+	queued, err = repo.ListQueuedReviews(ctx)
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, "pr-1", queued[0].PullRequestId)
+}
+
+func testRepositories(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", models.User{UserId: "author", Username: "Author", IsActive: true})
+
+	repos, err := repo.ListRepositories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.Repository{{RepositoryName: models.DefaultRepository, RequiredReviewers: 2}}, repos,
+		"the default repository always exists")
+
+	require.NoError(t, repo.SaveRepository(ctx, &models.Repository{RepositoryName: "search-api", RequiredReviewers: 2}))
+	require.NoError(t, repo.SaveRepository(ctx, &models.Repository{RepositoryName: "search-api", OwnerTeam: "backend", RequiredReviewers: 1}),
+		"saving again replaces the owner and rules")
+	require.Error(t, repo.SaveRepository(ctx, &models.Repository{RepositoryName: "web", OwnerTeam: "ghost", RequiredReviewers: 1}),
+		"owner must reference an existing team")
+	got, err := repo.GetRepository(ctx, "search-api")
+	require.NoError(t, err)
+	require.Equal(t, models.Repository{RepositoryName: "search-api", OwnerTeam: "backend", RequiredReviewers: 1}, *got)
+	_, err = repo.GetRepository(ctx, "web")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Номера PR уникальны только внутри репозитория.
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0)
+	seedPR(t, repo, "search-api#1", models.PullRequestStatusOPEN, time.Minute)
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.Number = 1 })
+	updatePR(t, repo, "search-api#1", func(pr *models.PullRequest) { pr.Repository, pr.Number = "search-api", 1 })
+
+	pr, err := repo.GetPullRequest(ctx, "search-api#1")
+	require.NoError(t, err)
+	require.Equal(t, "search-api", pr.Repository)
+	require.Equal(t, 1, pr.Number)
+	pr, err = repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, models.DefaultRepository, pr.Repository, "PRs without a repository land in default")
+
+	created := testTime(time.Hour)
+	require.Error(t, repo.SavePullRequest(ctx, &models.PullRequest{
+		PullRequestId: "dup", PullRequestName: "dup", AuthorId: "author", Status: models.PullRequestStatusOPEN,
+		CreatedAt: &created, Repository: "search-api", Number: 1,
+	}), "number must be unique within the repository")
+	require.Error(t, repo.SavePullRequest(ctx, &models.PullRequest{
+		PullRequestId: "ghost#1", PullRequestName: "ghost", AuthorId: "author", Status: models.PullRequestStatusOPEN,
+		CreatedAt: &created, Repository: "ghost", Number: 1,
+	}), "pull request must reference an existing repository")
+
+	stats, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{Repository: "search-api"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"search-api#1"}, statPRIDs(stats))
+}
+
+// testOrganizations проверяет создание организаций, поиск по токену и собственный репозиторий default.
+func testOrganizations(t *testing.T, repo Backend) {
+	ctx := context.Background()
+
+	orgs, err := repo.ListOrganizations(ctx)
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+	require.Equal(t, models.DefaultOrganization, orgs[0].OrganizationId)
+
+	acme := &models.Organization{OrganizationId: "acme", Name: "Acme", CreatedAt: testTime(0)}
+	require.NoError(t, repo.CreateOrganization(ctx, acme, "hash-acme"))
+	require.ErrorIs(t, repo.CreateOrganization(ctx, acme, "hash-other"), domain.ErrOrgExists)
+
+	orgs, err = repo.ListOrganizations(ctx)
+	require.NoError(t, err)
+	require.Len(t, orgs, 2)
+	require.Equal(t, "acme", orgs[0].OrganizationId)
+	require.Equal(t, "Acme", orgs[0].Name)
+	requireSameTime(t, &acme.CreatedAt, &orgs[0].CreatedAt)
+
+	id, err := repo.FindOrganizationByToken(ctx, "hash-acme")
+	require.NoError(t, err)
+	require.Equal(t, "acme", id)
+	_, err = repo.FindOrganizationByToken(ctx, "hash-other")
+	require.ErrorIs(t, err, domain.ErrNotFound, "a failed creation must not store its token")
+
+	repos, err := repo.ListRepositories(tenant.WithOrganization(ctx, "acme"))
+	require.NoError(t, err)
+	require.Len(t, repos, 1, "a new organization gets its own default repository")
+	require.Equal(t, models.DefaultRepository, repos[0].RepositoryName)
+}
+
+// testTenantIsolation заводит в двух организациях одни и те же идентификаторы
+// и проверяет, что чтения одной организации не видят данных другой.
+func testTenantIsolation(t *testing.T, repo Backend) {
+	base := context.Background()
+	require.NoError(t, repo.CreateOrganization(base, &models.Organization{OrganizationId: "acme", Name: "Acme", CreatedAt: testTime(0)}, "hash-acme"))
+	acme := tenant.WithOrganization(base, "acme")
+	def := tenant.WithOrganization(base, models.DefaultOrganization)
+
+	seed := func(ctx context.Context, username string, reviewers ...string) {
+		t.Helper()
+		require.NoError(t, repo.CreateTeamWithMembers(ctx, &models.Team{TeamName: "backend"}, []models.User{
+			{UserId: "author", Username: username, IsActive: true, TeamName: "backend"},
+			{UserId: "r1", Username: username, IsActive: true, TeamName: "backend"},
+			{UserId: "r2", Username: username, IsActive: true, TeamName: "backend"},
+		}))
+		created := testTime(0)
+		merged := created.Add(time.Minute)
+		require.NoError(t, repo.SavePullRequest(ctx, &models.PullRequest{
+			PullRequestId: "pr-1", PullRequestName: username, AuthorId: "author",
+			Status: models.PullRequestStatusMERGED, CreatedAt: &created, MergedAt: &merged,
+			AssignedReviewers: reviewers,
+		}))
+	}
+	seed(def, "Default", "r1")
+	seed(acme, "Acme", "r2")
+
+	// Организация без данных не видит ничего из обеих.
+	_, err := repo.GetTeam(tenant.WithOrganization(base, "empty"), "backend")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	for _, tc := range []struct {
+		ctx      context.Context
+		username string
+		reviewer string
+		other    string
+	}{
+		{def, "Default", "r1", "r2"},
+		{acme, "Acme", "r2", "r1"},
+	} {
+		team, err := repo.GetTeam(tc.ctx, "backend")
+		require.NoError(t, err)
+		require.Len(t, team.Members, 3)
+		for _, m := range team.Members {
+			require.Equal(t, tc.username, m.Username)
+		}
+
+		prs, err := repo.FindPullRequestsByReviewer(tc.ctx, tc.reviewer)
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		require.Equal(t, tc.username, prs[0].PullRequestName)
+		require.Equal(t, []string{tc.reviewer}, prs[0].AssignedReviewers)
+
+		foreign, err := repo.FindPullRequestsByReviewer(tc.ctx, tc.other)
+		require.NoError(t, err)
+		require.Empty(t, foreign)
+
+		stats, err := repo.GetAssignmentStats(tc.ctx, models.AssignmentStatsFilter{})
+		require.NoError(t, err)
+		require.Equal(t, []models.UserAssignmentStat{
+			{UserId: tc.reviewer, Username: tc.username, Assignments: 1, WeightedLoad: 1},
+		}, stats.ByUser)
+		require.Len(t, stats.ByPullRequest, 1)
+		require.Len(t, stats.ByTeam, 1)
+		require.Equal(t, 1, stats.ByTeam[0].MergedCount)
+
+		turnaround, err := repo.GetTurnaroundStats(tc.ctx, models.TurnaroundFilter{From: testTime(-time.Hour), To: testTime(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, 1, turnaround.Overall.Count)
+		require.Len(t, turnaround.ByReviewer, 1)
+		require.Equal(t, tc.reviewer, turnaround.ByReviewer[0].UserId)
+	}
+
+	// Запись в одной организации не трогает одноимённые строки другой.
+	require.NoError(t, repo.SaveUser(acme, &models.User{UserId: "r1", Username: "Acme", IsActive: false, TeamName: "backend"}))
+	user, err := repo.GetUser(def, "r1")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+}
+
+// testAffinityRules проверяет хранение правил подбора ревьюеров, их порядок и удаление.
+func testAffinityRules(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "lead", Username: "Lead", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+	)
+	require.NoError(t, repo.SaveTeam(ctx, &models.Team{TeamName: "frontend"}))
+
+	include := &models.AffinityRule{
+		TeamName: "backend", Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author",
+		WithUserIds: []string{"lead", "r1"}, Description: "security review", CreatedAt: testTime(0),
+	}
+	require.NoError(t, repo.CreateAffinityRule(ctx, include))
+	require.Positive(t, include.RuleId)
+	never := &models.AffinityRule{
+		TeamName: "backend", Kind: models.AffinityRuleNEVERPAIR, UserId: "r1",
+		WithUserIds: []string{"author"}, CreatedAt: testTime(time.Minute),
+	}
+	require.NoError(t, repo.CreateAffinityRule(ctx, never))
+	require.Greater(t, never.RuleId, include.RuleId)
+	require.NoError(t, repo.CreateAffinityRule(ctx, &models.AffinityRule{
+		TeamName: "frontend", Kind: models.AffinityRuleREQUIREPAIR, UserId: "r1",
+		WithUserIds: []string{"lead"}, CreatedAt: testTime(0),
+	}))
+	require.Error(t, repo.CreateAffinityRule(ctx, &models.AffinityRule{
+		TeamName: "ghost", Kind: models.AffinityRuleNEVERPAIR, UserId: "r1",
+		WithUserIds: []string{"lead"}, CreatedAt: testTime(0),
+	}), "rule must reference an existing team")
+
+	rules, err := repo.FindAffinityRules(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, include.RuleId, rules[0].RuleId)
+	require.Equal(t, models.AffinityRuleALWAYSINCLUDE, rules[0].Kind)
+	require.Equal(t, "author", rules[0].UserId)
+	require.Equal(t, []string{"lead", "r1"}, rules[0].WithUserIds)
+	require.Equal(t, "security review", rules[0].Description)
+	requireSameTime(t, &include.CreatedAt, &rules[0].CreatedAt)
+	require.Equal(t, never.RuleId, rules[1].RuleId)
+
+	rules, err = repo.FindAffinityRules(ctx, "nobody")
+	require.NoError(t, err)
+	require.Empty(t, rules)
+	rules, err = repo.FindAffinityRules(tenant.WithOrganization(ctx, "acme"), "backend")
+	require.NoError(t, err)
+	require.Empty(t, rules, "rules are scoped to the organization")
+
+	require.NoError(t, repo.DeleteAffinityRule(ctx, include.RuleId))
+	require.ErrorIs(t, repo.DeleteAffinityRule(ctx, include.RuleId), domain.ErrNotFound)
+	rules, err = repo.FindAffinityRules(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, never.RuleId, rules[0].RuleId)
+}
+
+func testShadowReviewers(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "junior", Username: "Junior", IsActive: true},
+		models.User{UserId: "mentee", Username: "Mentee", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+	)
+
+	require.NoError(t, repo.SetTeamLearners(ctx, "backend", []string{"mentee", "junior"}))
+	require.Error(t, repo.SetTeamLearners(ctx, "backend", []string{"junior", "ghost"}), "learners must reference existing users")
+	learners, err := repo.FindTeamLearners(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []models.Learner{{UserId: "junior"}, {UserId: "mentee"}}, learners, "failed update must keep the old list")
+
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusMERGED, time.Hour, "r1")
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) { pr.ShadowReviewers = []string{"mentee"} })
+	updatePR(t, repo, "pr-2", func(pr *models.PullRequest) { pr.ShadowReviewers = []string{"junior"} })
+
+	pr, err := repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"r1"}, pr.AssignedReviewers)
+	require.Equal(t, []string{"mentee"}, pr.ShadowReviewers)
+
+	prs, err := repo.FindPullRequestsByReviewer(ctx, "mentee")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr-1"}, prIDs(prs), "shadow reviews are listed for the learner")
+	require.Equal(t, []string{"mentee"}, prs[0].ShadowReviewers)
+
+	learners, err = repo.FindTeamLearners(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []models.Learner{{UserId: "junior"}, {UserId: "mentee", OpenShadowReviews: 1}}, learners, "merged PRs are not counted")
+
+	loads, err := repo.FindReviewLoad(ctx, []string{"mentee", "r1"})
+	require.NoError(t, err)
+	require.Equal(t, []models.ReviewerLoad{{UserId: "mentee"}, {UserId: "r1", OpenReviews: 1, WeightedLoad: 1}}, loads,
+		"shadow reviews do not add to the load")
+
+	stats, err := repo.GetAssignmentStats(ctx, models.AssignmentStatsFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []models.UserAssignmentStat{
+		{UserId: "r1", Username: "R1", Assignments: 2, WeightedLoad: 2},
+		{UserId: "junior", Username: "Junior", ShadowAssignments: 1},
+		{UserId: "mentee", Username: "Mentee", ShadowAssignments: 1},
+	}, stats.ByUser)
+	require.Equal(t, []models.PullRequestAssignmentStat{
+		{PullRequestId: "pr-1", PullRequestName: "pr-1", ReviewerCount: 1, ShadowCount: 1},
+		{PullRequestId: "pr-2", PullRequestName: "pr-2", ReviewerCount: 1, ShadowCount: 1},
+	}, stats.ByPullRequest)
+	require.Len(t, stats.ByTeam, 1)
+	require.InDelta(t, 1.0, stats.ByTeam[0].AvgReviewers, 0.001, "shadows do not count as reviewers")
+
+	// Перевод стажёра в обычные ревьюеры меняет роль, а не добавляет вторую запись.
+	updatePR(t, repo, "pr-1", func(pr *models.PullRequest) {
+		pr.AssignedReviewers = []string{"r1", "mentee"}
+		pr.ShadowReviewers = nil
+	})
+	pr, err = repo.GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mentee", "r1"}, pr.AssignedReviewers)
+	require.Empty(t, pr.ShadowReviewers)
+
+	require.NoError(t, repo.SetTeamLearners(ctx, "backend", []string{"junior"}))
+	learners, err = repo.FindTeamLearners(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []models.Learner{{UserId: "junior"}}, learners)
+	learners, err = repo.FindTeamLearners(tenant.WithOrganization(ctx, "acme"), "backend")
+	require.NoError(t, err)
+	require.Empty(t, learners, "learners are scoped to the organization")
+
+	require.NoError(t, repo.SetTeamLearners(ctx, "backend", nil))
+	learners, err = repo.FindTeamLearners(ctx, "backend")
+	require.NoError(t, err)
+	require.Empty(t, learners)
+}
+
+// ---------- вспомогательные функции ----------
+
+// testSnapshot выгружает состояние и восстанавливает его в новое хранилище той же фабрики.
+func testSnapshot(t *testing.T, factory Factory) {
+	ctx := context.Background()
+	src := factory(t)
+
+	empty, err := src.ExportSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.SnapshotVersion, empty.Version)
+	require.Empty(t, empty.Teams)
+	require.Empty(t, empty.PullRequests)
+	require.Equal(t, []models.Repository{{RepositoryName: models.DefaultRepository, RequiredReviewers: 2}}, empty.Repositories)
+
+	seedTeam(t, src, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: false},
+	)
+	require.NoError(t, src.SaveTeam(ctx, &models.Team{TeamName: "empty"}))
+	require.NoError(t, src.SaveUser(ctx, &models.User{UserId: "loner", Username: "Loner", IsActive: true}))
+	seedPR(t, src, "pr-open", models.PullRequestStatusOPEN, 0, "r2", "r1")
+	seedPR(t, src, "pr-merged", models.PullRequestStatusMERGED, time.Hour, "r1")
+	seedPR(t, src, "pr-bare", models.PullRequestStatusOPEN, 2*time.Hour)
+	require.NoError(t, src.SaveRepository(ctx, &models.Repository{RepositoryName: "search-api", OwnerTeam: "backend", RequiredReviewers: 1}))
+	updatePR(t, src, "pr-bare", func(pr *models.PullRequest) { pr.Repository, pr.Number = "search-api", 7 })
+	updatePR(t, src, "pr-open", func(pr *models.PullRequest) {
+		pr.LinesAdded, pr.LinesDeleted, pr.FilesChanged = 10, 5, 2
+		pr.Priority = models.PullRequestPriorityLOW
+		pr.Labels = []string{"chore"}
+	})
+	updatePR(t, src, "pr-merged", func(pr *models.PullRequest) { pr.ShadowReviewers = []string{"r2"} })
+	rotations := []models.ReviewRotation{
+		{PullRequestId: "pr-open", OldUserId: "r2", NewUserId: "r1", TeamName: "backend", IdleSeconds: 7200, RotatedAt: testTime(3 * time.Hour)},
+		{PullRequestId: "pr-merged", OldUserId: "r2", NewUserId: "r1", TeamName: "backend", IdleSeconds: 60, RotatedAt: testTime(time.Hour)},
+	}
+	for i := range rotations {
+		require.NoError(t, src.RecordReviewRotation(ctx, &rotations[i]))
+	}
+	absences := []models.Absence{
+		{UserId: "r1", StartsAt: testTime(0), EndsAt: testTime(72 * time.Hour), Reason: "vacation", CreatedAt: testTime(-time.Hour)},
+		{UserId: "loner", StartsAt: testTime(24 * time.Hour), EndsAt: testTime(48 * time.Hour), CreatedAt: testTime(-time.Hour)},
+	}
+	for i := range absences {
+		require.NoError(t, src.CreateAbsence(ctx, &absences[i]))
+	}
+	reassignedAt := testTime(time.Minute)
+	require.NoError(t, src.MarkAbsenceReassigned(ctx, absences[0].AbsenceId, reassignedAt))
+	absences[0].ReassignedAt = &reassignedAt
+	workingHours := []models.WorkingHours{
+		{UserId: "r1", TimeZone: "Europe/Berlin", Start: "09:00", End: "18:00"},
+		{UserId: "r2", TimeZone: "Asia/Tokyo", Start: "10:00", End: "19:00"},
+	}
+	for i := range workingHours {
+		require.NoError(t, src.SaveWorkingHours(ctx, &workingHours[i]))
+	}
+	require.NoError(t, src.SaveCapacity(ctx, "r1", 3))
+	queue := []models.QueuedReview{
+		{PullRequestId: "pr-open", Missing: 1, QueuedAt: testTime(time.Minute)},
+		{PullRequestId: "pr-bare", Missing: 2, QueuedAt: testTime(2 * time.Minute)},
+	}
+	for _, item := range queue {
+		require.NoError(t, src.EnqueueReview(ctx, item))
+	}
+	rule := models.AffinityRule{
+		TeamName: "backend", Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author", WithUserIds: []string{"r1", "r2"},
+		Description: "mentor", CreatedAt: testTime(-time.Hour),
+	}
+	require.NoError(t, src.CreateAffinityRule(ctx, &rule))
+	require.NoError(t, src.SetTeamLearners(ctx, "backend", []string{"r2", "r1"}))
+	decisions := []models.AssignmentDecision{
+		{
+			PullRequestId: "pr-open", Operation: models.DecisionOperationCREATE, Strategy: models.AssignmentStrategyBALANCED,
+			TeamName: "backend", Selected: []string{"r1"}, Pending: 1, Rules: []string{"rule 1"},
+			Candidates: []models.DecisionCandidate{
+				{UserId: "r1", Outcome: models.CandidateOutcomeSELECTED, Load: &models.CandidateLoad{OpenReviews: 1, WeightedLoad: 1}},
+				{UserId: "author", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is the author"},
+			},
+			DecidedAt: testTime(0),
+		},
+		{
+			PullRequestId: "pr-open", Operation: models.DecisionOperationREASSIGN, Strategy: models.AssignmentStrategyURGENT,
+			TeamName: "backend", Selected: []string{"r2"}, Replaced: []string{"r1"},
+			Candidates: []models.DecisionCandidate{{UserId: "r2", Outcome: models.CandidateOutcomeSELECTED}},
+			DecidedAt:  testTime(time.Hour),
+		},
+	}
+	for i := range decisions {
+		require.NoError(t, src.SaveAssignmentDecision(ctx, &decisions[i]))
+	}
+
+	snap, err := src.ExportSnapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, snap.ReviewRotations, len(rotations))
+	for i, want := range rotations {
+		requireSameRotation(t, want, snap.ReviewRotations[i])
+	}
+	require.Len(t, snap.Absences, len(absences))
+	for i, want := range absences {
+		requireSameAbsence(t, want, snap.Absences[i])
+	}
+	require.Equal(t, workingHours, snap.WorkingHours)
+	require.Equal(t, []models.SnapshotCapacity{{UserId: "r1", MaxOpenReviews: 3}}, snap.ReviewCapacities)
+	requireSameQueue(t, queue, snap.ReviewQueue)
+	require.Len(t, snap.AffinityRules, 1)
+	requireSameRule(t, rule, snap.AffinityRules[0])
+	require.Equal(t, []models.SnapshotLearner{{TeamName: "backend", UserId: "r1"}, {TeamName: "backend", UserId: "r2"}}, snap.TeamLearners)
+	require.Len(t, snap.AssignmentDecisions, len(decisions))
+	for i, want := range decisions {
+		requireSameDecision(t, want, snap.AssignmentDecisions[i])
+	}
+	require.Equal(t, []models.SnapshotTeam{{TeamName: "backend"}, {TeamName: "empty"}}, snap.Teams)
+	require.Equal(t, []models.User{
+		{UserId: "author", Username: "Author", IsActive: true, TeamName: "backend"},
+		{UserId: "loner", Username: "Loner", IsActive: true},
+		{UserId: "r1", Username: "R1", IsActive: true, TeamName: "backend"},
+		{UserId: "r2", Username: "R2", IsActive: false, TeamName: "backend"},
+	}, snap.Users)
+	require.Equal(t, []string{"pr-bare", "pr-merged", "pr-open"}, prIDs(snap.PullRequests))
+	require.Equal(t, []string{}, snap.PullRequests[0].AssignedReviewers)
+	require.Equal(t, []string{"r1", "r2"}, snap.PullRequests[2].AssignedReviewers)
+	require.Equal(t, []string{"r2"}, snap.PullRequests[1].ShadowReviewers)
+	require.Equal(t, models.SnapshotCounts{Teams: 2, Users: 4, PullRequests: 3, Reviewers: 3}, snap.Counts())
+
+	dst := factory(t)
+	require.NoError(t, dst.RestoreSnapshot(ctx, snap))
+	require.ErrorIs(t, dst.RestoreSnapshot(ctx, snap), domain.ErrNotEmpty)
+
+	restored, err := dst.ExportSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, snap.Teams, restored.Teams)
+	require.Equal(t, snap.Users, restored.Users)
+	require.Equal(t, snap.Repositories, restored.Repositories)
+	require.Len(t, snap.Repositories, 2)
+	require.Len(t, restored.PullRequests, len(snap.PullRequests))
+	for i, want := range snap.PullRequests {
+		got := restored.PullRequests[i]
+		require.Equal(t, want.PullRequestId, got.PullRequestId)
+		require.Equal(t, want.PullRequestName, got.PullRequestName)
+		require.Equal(t, want.AuthorId, got.AuthorId)
+		require.Equal(t, want.Status, got.Status)
+		require.Equal(t, want.AssignedReviewers, got.AssignedReviewers)
+		require.Equal(t, want.ShadowReviewers, got.ShadowReviewers)
+		require.Equal(t, [3]int{want.LinesAdded, want.LinesDeleted, want.FilesChanged}, [3]int{got.LinesAdded, got.LinesDeleted, got.FilesChanged})
+		require.Equal(t, want.Priority, got.Priority)
+		require.Equal(t, want.Labels, got.Labels)
+		require.Equal(t, want.Repository, got.Repository)
+		require.Equal(t, want.Number, got.Number)
+		requireSameTime(t, want.CreatedAt, got.CreatedAt)
+		if want.MergedAt == nil {
+			require.Nil(t, got.MergedAt)
+		} else {
+			requireSameTime(t, want.MergedAt, got.MergedAt)
+		}
+	}
+	require.Len(t, restored.ReviewRotations, len(rotations))
+	for i, want := range rotations {
+		requireSameRotation(t, want, restored.ReviewRotations[i])
+	}
+	history, err := dst.ListReviewRotations(ctx, models.ReviewRotationFilter{PullRequestId: "pr-open"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Len(t, restored.Absences, len(absences))
+	for i, want := range absences {
+		requireSameAbsence(t, want, restored.Absences[i])
+	}
+	absent, err := dst.FindAbsentUsers(ctx, testTime(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"r1"}, absent)
+	pending, err := dst.FindAbsencesToReassign(ctx, testTime(30*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "reassigned absences stay reassigned after restore")
+	require.Equal(t, "loner", pending[0].UserId)
+	require.Equal(t, workingHours, restored.WorkingHours)
+	hours, err := dst.FindWorkingHours(ctx, []string{"r1", "r2", "author"})
+	require.NoError(t, err)
+	require.Equal(t, workingHours, hours)
+	require.Equal(t, snap.ReviewCapacities, restored.ReviewCapacities)
+	loads, err := dst.FindReviewLoad(ctx, []string{"r1", "r2"})
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 0}, []int{loads[0].MaxOpenReviews, loads[1].MaxOpenReviews})
+	restoredQueue, err := dst.ListQueuedReviews(ctx)
+	require.NoError(t, err)
+	requireSameQueue(t, queue, restoredQueue)
+	rules, err := dst.FindAffinityRules(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	requireSameRule(t, rule, rules[0])
+	require.Equal(t, snap.TeamLearners, restored.TeamLearners)
+	learners, err := dst.FindTeamLearners(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []string{"r1", "r2"}, []string{learners[0].UserId, learners[1].UserId})
+	explained, err := dst.FindAssignmentDecisions(ctx, "pr-open")
+	require.NoError(t, err)
+	require.Len(t, explained, len(decisions), "decisions keep explaining restored pull requests")
+	for i, want := range decisions {
+		requireSameDecision(t, want, explained[i])
+	}
+
+	// Нарушение ссылок откатывает всю загрузку.
+	broken := factory(t)
+	err = broken.RestoreSnapshot(ctx, &models.Snapshot{
+		Version: models.SnapshotVersion,
+		Teams:   []models.SnapshotTeam{{TeamName: "backend"}},
+		Users:   []models.User{{UserId: "u1", Username: "U1", TeamName: "backend"}},
+		PullRequests: []*models.PullRequest{
+			{PullRequestId: "pr", PullRequestName: "pr", AuthorId: "ghost", Status: models.PullRequestStatusOPEN},
+		},
+	})
+	require.Error(t, err)
+	_, err = broken.GetTeam(ctx, "backend")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// testTime возвращает детерминированное время с точностью до микросекунд, как хранит PostgreSQL.
+func testTime(offset time.Duration) time.Time {
+	return time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC).Add(offset)
+}
+
+func seedTeam(t *testing.T, repo Backend, teamName string, users ...models.User) {
+	t.Helper()
+	for i := range users {
+		users[i].TeamName = teamName
+	}
+	require.NoError(t, repo.CreateTeamWithMembers(context.Background(), &models.Team{TeamName: teamName}, users))
+}
+
+func seedPR(t *testing.T, repo Backend, id string, status models.PullRequestStatus, offset time.Duration, reviewers ...string) {
+	t.Helper()
+	created := testTime(offset)
+	pr := &models.PullRequest{
+		PullRequestId:     id,
+		PullRequestName:   id,
+		AuthorId:          "author",
+		Status:            status,
+		CreatedAt:         &created,
+		AssignedReviewers: reviewers,
+	}
+	if status == models.PullRequestStatusMERGED {
+		merged := created.Add(time.Minute)
+		pr.MergedAt = &merged
+	}
+	require.NoError(t, repo.SavePullRequest(context.Background(), pr))
+}
+
+// updatePR перечитывает PR, применяет update и сохраняет его.
+func updatePR(t *testing.T, repo Backend, id string, update func(pr *models.PullRequest)) {
+	t.Helper()
+	pr, err := repo.GetPullRequest(context.Background(), id)
+	require.NoError(t, err)
+	update(pr)
+	require.NoError(t, repo.SavePullRequest(context.Background(), pr))
+}
+
+func prIDs(prs []*models.PullRequest) []string {
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.PullRequestId)
+	}
+	return ids
+}
+
+func statPRIDs(stats *models.AssignmentStats) []string {
+	ids := make([]string, 0, len(stats.ByPullRequest))
+	for _, stat := range stats.ByPullRequest {
+		ids = append(ids, stat.PullRequestId)
+	}
+	return ids
+}
+
+func testAssignmentDecisions(t *testing.T, repo Backend) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend",
+		models.User{UserId: "author", Username: "Author", IsActive: true},
+		models.User{UserId: "r1", Username: "R1", IsActive: true},
+		models.User{UserId: "r2", Username: "R2", IsActive: true},
+	)
+	seedPR(t, repo, "pr-1", models.PullRequestStatusOPEN, 0, "r1")
+	seedPR(t, repo, "pr-2", models.PullRequestStatusOPEN, time.Hour, "r2")
+
+	empty, err := repo.FindAssignmentDecisions(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	created := models.AssignmentDecision{
+		PullRequestId: "pr-1", Operation: models.DecisionOperationCREATE, Strategy: models.AssignmentStrategyBALANCED,
+		TeamName: "backend", Selected: []string{"r1"}, Pending: 1,
+		Rules: []string{"rule 1: author and [r2] never review each other's PRs"},
+		Candidates: []models.DecisionCandidate{
+			{UserId: "r1", Outcome: models.CandidateOutcomeSELECTED, Load: &models.CandidateLoad{OpenReviews: 1, WeightedLoad: 2, MaxOpenReviews: 3}},
+			{UserId: "author", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is the author"},
+		},
+		DecidedAt: testTime(0),
+	}
+	reassigned := models.AssignmentDecision{
+		PullRequestId: "pr-1", Operation: models.DecisionOperationREASSIGN, Strategy: models.AssignmentStrategyURGENT,
+		TeamName: "backend", Selected: []string{"r2"}, Replaced: []string{"r1"},
+		Candidates: []models.DecisionCandidate{{UserId: "r2", Outcome: models.CandidateOutcomeSELECTED, Load: &models.CandidateLoad{OffHours: true}}},
+		DecidedAt:  testTime(time.Hour),
+	}
+	require.NoError(t, repo.SaveAssignmentDecision(ctx, &created))
+	require.NoError(t, repo.SaveAssignmentDecision(ctx, &reassigned))
+	require.NoError(t, repo.SaveAssignmentDecision(ctx, &models.AssignmentDecision{
+		PullRequestId: "pr-2", Operation: models.DecisionOperationQUEUE, Strategy: models.AssignmentStrategyBALANCED,
+		TeamName: "backend", Selected: []string{"r2"}, Candidates: []models.DecisionCandidate{}, DecidedAt: testTime(0),
+	}))
+	require.Error(t, repo.SaveAssignmentDecision(ctx, &models.AssignmentDecision{
+		PullRequestId: "ghost", Operation: models.DecisionOperationCREATE, Strategy: models.AssignmentStrategyBALANCED,
+		TeamName: "backend", Selected: []string{}, Candidates: []models.DecisionCandidate{}, DecidedAt: testTime(0),
+	}), "decisions must reference an existing pull request")
+	require.NotZero(t, created.DecisionId)
+	require.Greater(t, reassigned.DecisionId, created.DecisionId)
+
+	got, err := repo.FindAssignmentDecisions(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, got, 2, "oldest first, other pull requests are not listed")
+	for i, want := range []models.AssignmentDecision{created, reassigned} {
+		requireSameTime(t, &want.DecidedAt, &got[i].DecidedAt)
+		got[i].DecidedAt = want.DecidedAt
+		require.Equal(t, want, got[i])
+	}
+
+	other, err := repo.FindAssignmentDecisions(tenant.WithOrganization(ctx, "acme"), "pr-1")
+	require.NoError(t, err)
+	require.Empty(t, other, "decisions are scoped to the organization")
+}
+
+func requireSameTime(t *testing.T, want, got *time.Time) {
+	t.Helper()
+	require.NotNil(t, got)
+	require.True(t, want.Equal(*got), "want %s, got %s", want, got)
+}
+
+// requireSameRotation сравнивает записи истории замен; время сравнивается как момент, без учёта зоны.
+func requireSameRotation(t *testing.T, want, got models.ReviewRotation) {
+	t.Helper()
+	requireSameTime(t, &want.RotatedAt, &got.RotatedAt)
+	want.RotatedAt, got.RotatedAt = time.Time{}, time.Time{}
+	require.Equal(t, want, got)
+}
+
+// requireSameAbsence сравнивает периоды отсутствия без absence_id, который при загрузке архива выдаётся заново.
+func requireSameAbsence(t *testing.T, want, got models.Absence) {
+	t.Helper()
+	requireSameTime(t, &want.StartsAt, &got.StartsAt)
+	requireSameTime(t, &want.EndsAt, &got.EndsAt)
+	requireSameTime(t, &want.CreatedAt, &got.CreatedAt)
+	if want.ReassignedAt == nil {
+		require.Nil(t, got.ReassignedAt)
+	} else {
+		requireSameTime(t, want.ReassignedAt, got.ReassignedAt)
+	}
+	require.Equal(t, [2]string{want.UserId, want.Reason}, [2]string{got.UserId, got.Reason})
+}
+
+// requireSameQueue сравнивает очереди на ревьюверов; время постановки сравнивается как момент.
+func requireSameQueue(t *testing.T, want, got []models.QueuedReview) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i := range want {
+		requireSameTime(t, &want[i].QueuedAt, &got[i].QueuedAt)
+		require.Equal(t, [2]any{want[i].PullRequestId, want[i].Missing}, [2]any{got[i].PullRequestId, got[i].Missing})
+	}
+}
+
+// requireSameRule сравнивает правила подбора без rule_id, который при загрузке архива выдаётся заново.
+func requireSameRule(t *testing.T, want, got models.AffinityRule) {
+	t.Helper()
+	requireSameTime(t, &want.CreatedAt, &got.CreatedAt)
+	want.RuleId, want.CreatedAt, got.RuleId, got.CreatedAt = 0, time.Time{}, 0, time.Time{}
+	require.Equal(t, want, got)
+}
+
+// requireSameDecision сравнивает решения о назначении без decision_id, который при загрузке архива выдаётся заново.
+func requireSameDecision(t *testing.T, want, got models.AssignmentDecision) {
+	t.Helper()
+	requireSameTime(t, &want.DecidedAt, &got.DecidedAt)
+	want.DecisionId, want.DecidedAt, got.DecisionId, got.DecidedAt = 0, time.Time{}, 0, time.Time{}
+	require.Equal(t, want, got)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// SaveCapacity создаёт или заменяет личный лимит открытых ревью пользователя.
+func (s *Storage) SaveCapacity(ctx context.Context, userID string, maxOpenReviews int) error {
+	const q = `
+INSERT INTO user_review_capacity (user_id, max_open_reviews, organization_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO UPDATE SET max_open_reviews = EXCLUDED.max_open_reviews
+`
+	if _, err := s.pool.Exec(ctx, q, userID, maxOpenReviews, tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("save capacity: %w", err)
+	}
+	return nil
+}
+
+// DeleteCapacity удаляет личный лимит пользователя; отсутствие лимита ошибкой не считается.
+func (s *Storage) DeleteCapacity(ctx context.Context, userID string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM user_review_capacity WHERE user_id = $1 AND organization_id = $2`,
+		userID, tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("delete capacity: %w", err)
+	}
+	return nil
+}
+
+// FindReviewLoad возвращает число и суммарный вес открытых ревью и личный лимит (0 — не задан) перечисленных пользователей;
+// несуществующие пользователи пропускаются.
+func (s *Storage) FindReviewLoad(ctx context.Context, userIDs []string) ([]models.ReviewerLoad, error) {
+	if len(userIDs) == 0 {
+		return []models.ReviewerLoad{}, nil
+	}
+	const q = `
+SELECT u.user_id,
+    COALESCE(o.open_reviews, 0),
+    COALESCE(o.weighted_load, 0),
+    COALESCE(c.max_open_reviews, 0)
+FROM users u
+LEFT JOIN (
+    SELECT r.user_id, COUNT(*) AS open_reviews, SUM(p.review_weight) AS weighted_load
+    FROM pull_request_reviewers r
+    JOIN pull_requests p ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id
+    WHERE r.organization_id = $2 AND p.status = 'OPEN' AND r.role = 'REVIEWER'
+    GROUP BY r.user_id
+) o ON o.user_id = u.user_id
+LEFT JOIN user_review_capacity c ON c.organization_id = u.organization_id AND c.user_id = u.user_id
+WHERE u.organization_id = $2 AND u.user_id = ANY($1)
+ORDER BY u.user_id
+`
+	rows, err := s.pool.Query(ctx, q, userIDs, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query review load: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.ReviewerLoad, 0, len(userIDs))
+	for rows.Next() {
+		var load models.ReviewerLoad
+		if err := rows.Scan(&load.UserId, &load.OpenReviews, &load.WeightedLoad, &load.MaxOpenReviews); err != nil {
+			return nil, fmt.Errorf("scan review load: %w", err)
+		}
+		result = append(result, load)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review load: %w", err)
+	}
+	return result, nil
+}
+
+// CountOpenReviews возвращает число открытых ревью каждого, кто назначался ревьюером; без открытых ревью — 0.
+func (s *Storage) CountOpenReviews(ctx context.Context) (map[string]int, error) {
+	const q = `
+SELECT r.user_id, COUNT(*) FILTER (WHERE p.status = 'OPEN')
+FROM pull_request_reviewers r
+JOIN pull_requests p ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id
+WHERE r.organization_id = $1 AND r.role = 'REVIEWER'
+GROUP BY r.user_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query open reviews: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]int)
+	for rows.Next() {
+		var (
+			userID string
+			open   int64
+		)
+		if err := rows.Scan(&userID, &open); err != nil {
+			return nil, fmt.Errorf("scan open reviews: %w", err)
+		}
+		result[userID] = int(open)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows open reviews: %w", err)
+	}
+	return result, nil
+}
+
+// EnqueueReview ставит PR в очередь на ревьюеров или обновляет число недостающих.
+func (s *Storage) EnqueueReview(ctx context.Context, item models.QueuedReview) error {
+	const q = `
+INSERT INTO review_queue (pull_request_id, missing, queued_at, organization_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (organization_id, pull_request_id) DO UPDATE SET missing = EXCLUDED.missing
+`
+	if _, err := s.pool.Exec(ctx, q, item.PullRequestId, item.Missing, item.QueuedAt, tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("enqueue review: %w", err)
+	}
+	return nil
+}
+
+// ListQueuedReviews возвращает очередь в порядке постановки.
+func (s *Storage) ListQueuedReviews(ctx context.Context) ([]models.QueuedReview, error) {
+	const q = `
+SELECT pull_request_id, missing, queued_at
+FROM review_queue
+WHERE organization_id = $1
+ORDER BY queued_at, pull_request_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query review queue: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.QueuedReview, 0)
+	for rows.Next() {
+		var item models.QueuedReview
+		if err := rows.Scan(&item.PullRequestId, &item.Missing, &item.QueuedAt); err != nil {
+			return nil, fmt.Errorf("scan review queue: %w", err)
+		}
+		result = append(result, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review queue: %w", err)
+	}
+	return result, nil
+}
+
+// DequeueReview убирает PR из очереди; если его там нет, ничего не делает.
+func (s *Storage) DequeueReview(ctx context.Context, prID string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM review_queue WHERE pull_request_id = $1 AND organization_id = $2`,
+		prID, tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("dequeue review: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportSnapshot читает состояние организации из контекста в одном снимке транзакции REPEATABLE READ.
+func (s *Storage) ExportSnapshot(ctx context.Context) (_ *models.Snapshot, err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`); err != nil {
+		return nil, fmt.Errorf("set snapshot isolation: %w", err)
+	}
+
+	organizationID := tenant.Organization(ctx)
+	snap := &models.Snapshot{
+		Version:      models.SnapshotVersion,
+		Teams:        []models.SnapshotTeam{},
+		Users:        []models.User{},
+		PullRequests: []*models.PullRequest{},
+	}
+
+	if err := queryEach(ctx, tx, `SELECT team_name FROM teams WHERE organization_id = $1 ORDER BY team_name`, func(rows pgx.Rows) error {
+		var team models.SnapshotTeam
+		if err := rows.Scan(&team.TeamName); err != nil {
+			return err
+		}
+		snap.Teams = append(snap.Teams, team)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export teams: %w", err)
+	}
+
+	const qUsers = `
+	SELECT user_id, username, is_active, COALESCE(team_name, '') FROM users WHERE organization_id = $1 ORDER BY user_id
+	`
+	if err := queryEach(ctx, tx, qUsers, func(rows pgx.Rows) error {
+		var user models.User
+		if err := rows.Scan(&user.UserId, &user.Username, &user.IsActive, &user.TeamName); err != nil {
+			return err
+		}
+		snap.Users = append(snap.Users, user)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export users: %w", err)
+	}
+
+	if err := queryEach(ctx, tx, selectRepositoriesSQL+`ORDER BY repository_name`, func(rows pgx.Rows) error {
+		repo, err := scanRepository(rows)
+		if err != nil {
+			return err
+		}
+		snap.Repositories = append(snap.Repositories, *repo)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export repositories: %w", err)
+	}
+
+	const qPRs = `
+	SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+		lines_added, lines_deleted, files_changed, priority, labels, repository, number
+	FROM pull_requests
+	WHERE organization_id = $1
+	ORDER BY pull_request_id
+	`
+	byID := make(map[string]*models.PullRequest)
+	if err := queryEach(ctx, tx, qPRs, func(rows pgx.Rows) error {
+		var (
+			pr      models.PullRequest
+			status  string
+			created *time.Time
+			merged  *time.Time
+			meta    pullRequestMeta
+		)
+		if err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &status, &created, &merged,
+			&meta.linesAdded, &meta.linesDeleted, &meta.filesChanged, &meta.priority, &meta.labels, &meta.repository, &meta.number); err != nil {
+			return err
+		}
+		meta.apply(&pr)
+		pr.Status = models.PullRequestStatus(status)
+		pr.CreatedAt, pr.MergedAt = created, merged
+		pr.AssignedReviewers = []string{}
+		snap.PullRequests = append(snap.PullRequests, &pr)
+		byID[pr.PullRequestId] = &pr
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export pull requests: %w", err)
+	}
+
+	const qReviewers = `
+	SELECT pull_request_id, user_id, role FROM pull_request_reviewers WHERE organization_id = $1 ORDER BY pull_request_id, user_id
+	`
+	if err := queryEach(ctx, tx, qReviewers, func(rows pgx.Rows) error {
+		var prID, userID, role string
+		if err := rows.Scan(&prID, &userID, &role); err != nil {
+			return err
+		}
+		pr, ok := byID[prID]
+		switch {
+		case !ok:
+		case models.ReviewerRole(role) == models.ReviewerRoleSHADOW:
+			pr.ShadowReviewers = append(pr.ShadowReviewers, userID)
+		default:
+			pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+		}
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export reviewers: %w", err)
+	}
+
+	const qRotations = `
+	SELECT pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at
+	FROM review_rotations WHERE organization_id = $1 ORDER BY id
+	`
+	if err := queryEach(ctx, tx, qRotations, func(rows pgx.Rows) error {
+		var r models.ReviewRotation
+		if err := rows.Scan(&r.PullRequestId, &r.OldUserId, &r.NewUserId, &r.TeamName, &r.IdleSeconds, &r.RotatedAt); err != nil {
+			return err
+		}
+		snap.ReviewRotations = append(snap.ReviewRotations, r)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export review rotations: %w", err)
+	}
+
+	if err := queryEach(ctx, tx, selectAbsencesSQL+`ORDER BY absence_id`, func(rows pgx.Rows) error {
+		var a models.Absence
+		if err := rows.Scan(&a.AbsenceId, &a.UserId, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.ReassignedAt); err != nil {
+			return err
+		}
+		snap.Absences = append(snap.Absences, a)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export absences: %w", err)
+	}
+
+	if err := queryEach(ctx, tx, selectWorkingHoursSQL+`ORDER BY user_id`, func(rows pgx.Rows) error {
+		var wh models.WorkingHours
+		if err := rows.Scan(&wh.UserId, &wh.TimeZone, &wh.Start, &wh.End); err != nil {
+			return err
+		}
+		snap.WorkingHours = append(snap.WorkingHours, wh)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export working hours: %w", err)
+	}
+
+	const qCapacities = `
+	SELECT user_id, max_open_reviews FROM user_review_capacity WHERE organization_id = $1 ORDER BY user_id
+	`
+	if err := queryEach(ctx, tx, qCapacities, func(rows pgx.Rows) error {
+		var c models.SnapshotCapacity
+		if err := rows.Scan(&c.UserId, &c.MaxOpenReviews); err != nil {
+			return err
+		}
+		snap.ReviewCapacities = append(snap.ReviewCapacities, c)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export review capacities: %w", err)
+	}
+
+	const qQueue = `
+	SELECT pull_request_id, missing, queued_at FROM review_queue WHERE organization_id = $1 ORDER BY queued_at, pull_request_id
+	`
+	if err := queryEach(ctx, tx, qQueue, func(rows pgx.Rows) error {
+		var item models.QueuedReview
+		if err := rows.Scan(&item.PullRequestId, &item.Missing, &item.QueuedAt); err != nil {
+			return err
+		}
+		snap.ReviewQueue = append(snap.ReviewQueue, item)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export review queue: %w", err)
+	}
+
+	if err := queryEach(ctx, tx, selectAffinityRulesSQL+`ORDER BY rule_id`, func(rows pgx.Rows) error {
+		rule, err := scanAffinityRule(rows)
+		if err != nil {
+			return err
+		}
+		snap.AffinityRules = append(snap.AffinityRules, *rule)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export affinity rules: %w", err)
+	}
+
+	const qLearners = `
+	SELECT team_name, user_id FROM team_learners WHERE organization_id = $1 ORDER BY team_name, user_id
+	`
+	if err := queryEach(ctx, tx, qLearners, func(rows pgx.Rows) error {
+		var l models.SnapshotLearner
+		if err := rows.Scan(&l.TeamName, &l.UserId); err != nil {
+			return err
+		}
+		snap.TeamLearners = append(snap.TeamLearners, l)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export team learners: %w", err)
+	}
+
+	if err := queryEach(ctx, tx, selectDecisionsSQL+`ORDER BY decision_id`, func(rows pgx.Rows) error {
+		d, err := scanDecision(rows)
+		if err != nil {
+			return err
+		}
+		snap.AssignmentDecisions = append(snap.AssignmentDecisions, *d)
+		return nil
+	}, organizationID); err != nil {
+		return nil, fmt.Errorf("export assignment decisions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return snap, nil
+}
+
+// RestoreSnapshot загружает архив в пустую организацию из контекста одной транзакцией.
+// Таблицы блокируются на запись, чтобы параллельные запросы не вклинились между проверкой и загрузкой.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *models.Snapshot) (err error) {
+	if snap == nil {
+		return fmt.Errorf("snapshot is nil")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE teams, users, repositories, pull_requests, pull_request_reviewers, review_rotations, user_absences, user_working_hours,
+		user_review_capacity, review_queue, affinity_rules, team_learners, assignment_decisions IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("lock tables: %w", err)
+	}
+
+	const qNotEmpty = `
+	SELECT EXISTS (SELECT 1 FROM teams WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM users WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM pull_requests WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM review_rotations WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM user_absences WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM user_working_hours WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM user_review_capacity WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM review_queue WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM affinity_rules WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM team_learners WHERE organization_id = $1)
+		OR EXISTS (SELECT 1 FROM assignment_decisions WHERE organization_id = $1)
+	`
+	organizationID := tenant.Organization(ctx)
+	var notEmpty bool
+	if err := tx.QueryRow(ctx, qNotEmpty, organizationID).Scan(&notEmpty); err != nil {
+		return fmt.Errorf("check empty database: %w", err)
+	}
+	if notEmpty {
+		return domain.NewNotEmptyError("database")
+	}
+
+	teams := make([][]any, 0, len(snap.Teams))
+	for _, team := range snap.Teams {
+		teams = append(teams, []any{team.TeamName, organizationID})
+	}
+	users := make([][]any, 0, len(snap.Users))
+	for _, user := range snap.Users {
+		var teamName *string
+		if user.TeamName != "" {
+			teamName = &user.TeamName
+		}
+		users = append(users, []any{user.UserId, user.Username, user.IsActive, teamName, organizationID})
+	}
+	prs := make([][]any, 0, len(snap.PullRequests))
+	var reviewers [][]any
+	for _, pr := range snap.PullRequests {
+		prs = append(prs, []any{
+			pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt,
+			pr.LinesAdded, pr.LinesDeleted, pr.FilesChanged, string(pr.Priority), nonNilLabels(pr.Labels), pr.ReviewWeight(),
+			models.RepositoryOrDefault(pr.Repository), nullableNumber(pr.Number), organizationID,
+		})
+		for _, a := range pr.Assignments() {
+			reviewers = append(reviewers, []any{pr.PullRequestId, a.UserId, string(a.Role), organizationID})
+		}
+	}
+	rotations := make([][]any, 0, len(snap.ReviewRotations))
+	for _, r := range snap.ReviewRotations {
+		rotations = append(rotations, []any{r.PullRequestId, r.OldUserId, r.NewUserId, r.TeamName, r.IdleSeconds, r.RotatedAt, organizationID})
+	}
+	// absence_id общий для всех организаций, поэтому периоды отсутствия получают новые идентификаторы.
+	absences := make([][]any, 0, len(snap.Absences))
+	for _, a := range snap.Absences {
+		absences = append(absences, []any{a.UserId, a.StartsAt, a.EndsAt, a.Reason, a.CreatedAt, a.ReassignedAt, organizationID})
+	}
+	workingHours := make([][]any, 0, len(snap.WorkingHours))
+	for _, wh := range snap.WorkingHours {
+		workingHours = append(workingHours, []any{wh.UserId, wh.TimeZone, wh.Start, wh.End, organizationID})
+	}
+	capacities := make([][]any, 0, len(snap.ReviewCapacities))
+	for _, c := range snap.ReviewCapacities {
+		capacities = append(capacities, []any{c.UserId, c.MaxOpenReviews, organizationID})
+	}
+	queue := make([][]any, 0, len(snap.ReviewQueue))
+	for _, item := range snap.ReviewQueue {
+		queue = append(queue, []any{item.PullRequestId, item.Missing, item.QueuedAt, organizationID})
+	}
+	// rule_id общий для всех организаций, поэтому правила получают новые идентификаторы.
+	rules := make([][]any, 0, len(snap.AffinityRules))
+	for _, r := range snap.AffinityRules {
+		rules = append(rules, []any{organizationID, r.TeamName, string(r.Kind), r.UserId, r.WithUserIds, r.Description, r.CreatedAt})
+	}
+	learners := make([][]any, 0, len(snap.TeamLearners))
+	for _, l := range snap.TeamLearners {
+		learners = append(learners, []any{organizationID, l.TeamName, l.UserId})
+	}
+	// decision_id тоже общий; строки копируются по порядку, поэтому решения сохраняют очерёдность.
+	decisions := make([][]any, 0, len(snap.AssignmentDecisions))
+	for _, d := range snap.AssignmentDecisions {
+		decisions = append(decisions, []any{
+			organizationID, d.PullRequestId, string(d.Operation), string(d.Strategy), d.TeamName,
+			d.Selected, d.Replaced, d.Pending, d.Rules, d.Candidates, d.DecidedAt,
+		})
+	}
+
+	// Репозитории ссылаются на команды, а PR — на репозитории. Репозиторий default создаётся вместе с организацией,
+	// поэтому репозитории не копируются, а обновляются.
+	copyBatch := func(table string, columns []string, rows [][]any) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows)); err != nil {
+			return fmt.Errorf("copy %s: %w", table, err)
+		}
+		return nil
+	}
+	if err := copyBatch("teams", []string{"team_name", "organization_id"}, teams); err != nil {
+		return err
+	}
+	for _, repo := range snap.Repositories {
+		if _, err := tx.Exec(ctx, upsertRepositorySQL, repositoryArgs(organizationID, &repo)...); err != nil {
+			return fmt.Errorf("upsert repository %s: %w", repo.RepositoryName, err)
+		}
+	}
+
+	// Порядок таблиц следует внешним ключам.
+	for _, batch := range []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"users", []string{"user_id", "username", "is_active", "team_name", "organization_id"}, users},
+		{"pull_requests", []string{
+			"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at",
+			"lines_added", "lines_deleted", "files_changed", "priority", "labels", "review_weight", "repository", "number",
+			"organization_id",
+		}, prs},
+		{"pull_request_reviewers", []string{"pull_request_id", "user_id", "role", "organization_id"}, reviewers},
+		{"review_rotations", []string{
+			"pull_request_id", "old_user_id", "new_user_id", "team_name", "idle_seconds", "rotated_at", "organization_id",
+		}, rotations},
+		{"user_absences", []string{
+			"user_id", "starts_at", "ends_at", "reason", "created_at", "reassigned_at", "organization_id",
+		}, absences},
+		{"user_working_hours", []string{"user_id", "time_zone", "work_start", "work_end", "organization_id"}, workingHours},
+		{"user_review_capacity", []string{"user_id", "max_open_reviews", "organization_id"}, capacities},
+		{"review_queue", []string{"pull_request_id", "missing", "queued_at", "organization_id"}, queue},
+		{"affinity_rules", []string{
+			"organization_id", "team_name", "kind", "user_id", "with_user_ids", "description", "created_at",
+		}, rules},
+		{"team_learners", []string{"organization_id", "team_name", "user_id"}, learners},
+		{"assignment_decisions", []string{
+			"organization_id", "pull_request_id", "operation", "strategy", "team_name",
+			"selected", "replaced", "pending", "rules", "candidates", "decided_at",
+		}, decisions},
+	} {
+		if err := copyBatch(batch.table, batch.columns, batch.rows); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// queryEach выполняет запрос с аргументами args в транзакции и вызывает scan для каждой строки.
+func queryEach(ctx context.Context, tx pgx.Tx, query string, scan func(pgx.Rows) error, args ...any) error {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
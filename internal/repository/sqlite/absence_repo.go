@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const selectAbsencesSQL = `
+SELECT absence_id, user_id, starts_at, ends_at, reason, created_at, reassigned_at
+FROM user_absences
+WHERE organization_id = ?1
+`
+
+// CreateAbsence сохраняет период отсутствия и заполняет AbsenceId.
+func (s *Storage) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	if absence == nil {
+		return fmt.Errorf("absence is nil")
+	}
+	const q = `
+INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	res, err := s.db.ExecContext(ctx, q,
+		absence.UserId,
+		formatTime(&absence.StartsAt),
+		formatTime(&absence.EndsAt),
+		absence.Reason,
+		formatTime(&absence.CreatedAt),
+		tenant.Organization(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("insert absence: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert absence: %w", err)
+	}
+	absence.AbsenceId = id
+	return nil
+}
+
+// GetAbsence возвращает период отсутствия по идентификатору.
+func (s *Storage) GetAbsence(ctx context.Context, absenceID int64) (*models.Absence, error) {
+	absences, err := s.queryAbsences(ctx, selectAbsencesSQL+`AND absence_id = ?`, tenant.Organization(ctx), absenceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(absences) == 0 {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	return &absences[0], nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя в порядке начала.
+func (s *Storage) ListAbsences(ctx context.Context, userID string) ([]models.Absence, error) {
+	return s.queryAbsences(ctx, selectAbsencesSQL+`AND user_id = ? ORDER BY starts_at, absence_id`,
+		tenant.Organization(ctx), userID)
+}
+
+// DeleteAbsence удаляет период отсутствия; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM user_absences WHERE absence_id = ? AND organization_id = ?`,
+		absenceID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("delete absence: %w", err)
+	}
+	return requireAbsenceRow(res, absenceID)
+}
+
+// FindAbsentUsers возвращает пользователей, чей период отсутствия покрывает момент at.
+func (s *Storage) FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error) {
+	const q = `
+SELECT DISTINCT user_id
+FROM user_absences
+WHERE organization_id = ?2 AND starts_at <= ?1 AND ends_at > ?1
+ORDER BY user_id
+`
+	rows, err := s.db.QueryContext(ctx, q, formatTime(&at), tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query absent users: %w", err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan absent users: %w", err)
+		}
+		result = append(result, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows absent users: %w", err)
+	}
+	return result, nil
+}
+
+// FindAbsencesToReassign возвращает идущие в момент at периоды, ревью по которым ещё не переданы.
+func (s *Storage) FindAbsencesToReassign(ctx context.Context, at time.Time) ([]models.Absence, error) {
+	const where = `AND reassigned_at IS NULL AND starts_at <= ?2 AND ends_at > ?2 ORDER BY starts_at, absence_id`
+	return s.queryAbsences(ctx, selectAbsencesSQL+where, tenant.Organization(ctx), formatTime(&at))
+}
+
+// MarkAbsenceReassigned отмечает, что ревью отсутствующего переданы коллегам.
+func (s *Storage) MarkAbsenceReassigned(ctx context.Context, absenceID int64, at time.Time) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE user_absences SET reassigned_at = ? WHERE absence_id = ? AND organization_id = ?`,
+		formatTime(&at), absenceID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("mark absence reassigned: %w", err)
+	}
+	return requireAbsenceRow(res, absenceID)
+}
+
+// requireAbsenceRow возвращает NOT_FOUND, если запрос не затронул ни одной строки.
+func requireAbsenceRow(res sql.Result, absenceID int64) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("absence %d: %w", absenceID, err)
+	}
+	if n == 0 {
+		return domain.NewNotFoundError(fmt.Sprintf("absence %d", absenceID))
+	}
+	return nil
+}
+
+// queryAbsences выполняет выборку из user_absences.
+func (s *Storage) queryAbsences(ctx context.Context, q string, args ...any) ([]models.Absence, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query absences: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Absence, 0)
+	for rows.Next() {
+		a, err := scanAbsence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan absences: %w", err)
+		}
+		result = append(result, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows absences: %w", err)
+	}
+	return result, nil
+}
+
+// scanAbsence читает текущую строку selectAbsencesSQL.
+func scanAbsence(rows *sql.Rows) (*models.Absence, error) {
+	var (
+		a                           models.Absence
+		starts, ends, created, done sql.NullString
+	)
+	if err := rows.Scan(&a.AbsenceId, &a.UserId, &starts, &ends, &a.Reason, &created, &done); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if a.StartsAt, err = parseRequiredTime(starts); err != nil {
+		return nil, err
+	}
+	if a.EndsAt, err = parseRequiredTime(ends); err != nil {
+		return nil, err
+	}
+	if a.CreatedAt, err = parseRequiredTime(created); err != nil {
+		return nil, err
+	}
+	if a.ReassignedAt, err = parseTime(done); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// parseRequiredTime разбирает колонку времени с ограничением NOT NULL.
+func parseRequiredTime(v sql.NullString) (time.Time, error) {
+	t, err := parseTime(v)
+	if err != nil || t == nil {
+		return time.Time{}, err
+	}
+	return *t, nil
+}
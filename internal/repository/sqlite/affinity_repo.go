@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const selectAffinityRulesSQL = `
+SELECT rule_id, team_name, kind, user_id, with_user_ids, description, created_at
+FROM affinity_rules
+WHERE organization_id = ?
+`
+
+// CreateAffinityRule сохраняет правило подбора ревьюеров и заполняет RuleId; with_user_ids хранится JSON-массивом.
+func (s *Storage) CreateAffinityRule(ctx context.Context, rule *models.AffinityRule) error {
+	if rule == nil {
+		return fmt.Errorf("affinity rule is nil")
+	}
+	with, err := json.Marshal(rule.WithUserIds)
+	if err != nil {
+		return fmt.Errorf("encode with_user_ids: %w", err)
+	}
+	const q = `
+INSERT INTO affinity_rules (organization_id, team_name, kind, user_id, with_user_ids, description, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+	res, err := s.db.ExecContext(ctx, q, tenant.Organization(ctx), rule.TeamName, string(rule.Kind), rule.UserId,
+		string(with), rule.Description, formatTime(&rule.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("insert affinity rule: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert affinity rule: %w", err)
+	}
+	rule.RuleId = id
+	return nil
+}
+
+// DeleteAffinityRule удаляет правило; NOT_FOUND, если его нет.
+func (s *Storage) DeleteAffinityRule(ctx context.Context, ruleID int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM affinity_rules WHERE rule_id = ? AND organization_id = ?`,
+		ruleID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("delete affinity rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("affinity rule %d: %w", ruleID, err)
+	}
+	if n == 0 {
+		return domain.NewNotFoundError(fmt.Sprintf("affinity rule %d", ruleID))
+	}
+	return nil
+}
+
+// FindAffinityRules возвращает правила команды в порядке создания.
+func (s *Storage) FindAffinityRules(ctx context.Context, teamName string) ([]models.AffinityRule, error) {
+	rows, err := s.db.QueryContext(ctx, selectAffinityRulesSQL+`AND team_name = ? ORDER BY rule_id`, tenant.Organization(ctx), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query affinity rules: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.AffinityRule, 0)
+	for rows.Next() {
+		r, err := scanAffinityRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan affinity rules: %w", err)
+		}
+		result = append(result, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows affinity rules: %w", err)
+	}
+	return result, nil
+}
+
+// scanAffinityRule читает текущую строку selectAffinityRulesSQL.
+func scanAffinityRule(rows *sql.Rows) (*models.AffinityRule, error) {
+	var (
+		r         models.AffinityRule
+		kind      string
+		with      string
+		createdAt sql.NullString
+	)
+	if err := rows.Scan(&r.RuleId, &r.TeamName, &kind, &r.UserId, &with, &r.Description, &createdAt); err != nil {
+		return nil, err
+	}
+	r.Kind = models.AffinityRuleKind(kind)
+	if err := json.Unmarshal([]byte(with), &r.WithUserIds); err != nil {
+		return nil, fmt.Errorf("decode with_user_ids: %w", err)
+	}
+	var err error
+	if r.CreatedAt, err = parseRequiredTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
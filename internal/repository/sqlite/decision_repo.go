@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// insertDecisionSQL вставляет решение о назначении; списки и кандидаты передаются JSON-массивами.
+const insertDecisionSQL = `
+INSERT INTO assignment_decisions (organization_id, pull_request_id, operation, strategy, team_name,
+                                  selected, replaced, pending, rules, candidates, decided_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// SaveAssignmentDecision сохраняет решение о назначении ревьюеров и заполняет DecisionId;
+// списки и кандидаты хранятся JSON-массивами.
+func (s *Storage) SaveAssignmentDecision(ctx context.Context, decision *models.AssignmentDecision) error {
+	if decision == nil {
+		return fmt.Errorf("assignment decision is nil")
+	}
+	var encoded [4][]byte
+	for i, v := range []any{decision.Selected, decision.Replaced, decision.Rules, decision.Candidates} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode assignment decision: %w", err)
+		}
+		encoded[i] = data
+	}
+	res, err := s.db.ExecContext(ctx, insertDecisionSQL, tenant.Organization(ctx), decision.PullRequestId, string(decision.Operation),
+		string(decision.Strategy), decision.TeamName, string(encoded[0]), string(encoded[1]), decision.Pending,
+		string(encoded[2]), string(encoded[3]), formatTime(&decision.DecidedAt))
+	if err != nil {
+		return fmt.Errorf("insert assignment decision: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert assignment decision: %w", err)
+	}
+	decision.DecisionId = id
+	return nil
+}
+
+// selectDecisionsSQL выбирает решения о назначении организации; дальнейшие условия дописываются через AND.
+const selectDecisionsSQL = `
+SELECT decision_id, pull_request_id, operation, strategy, team_name, selected, replaced, pending, rules, candidates, decided_at
+FROM assignment_decisions
+WHERE organization_id = ?
+`
+
+// FindAssignmentDecisions возвращает решения о назначении ревьюеров PR, старые первыми.
+func (s *Storage) FindAssignmentDecisions(ctx context.Context, prID string) ([]models.AssignmentDecision, error) {
+	rows, err := s.db.QueryContext(ctx, selectDecisionsSQL+`AND pull_request_id = ? ORDER BY decision_id`, tenant.Organization(ctx), prID)
+	if err != nil {
+		return nil, fmt.Errorf("query assignment decisions: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.AssignmentDecision, 0)
+	for rows.Next() {
+		d, err := scanDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan assignment decisions: %w", err)
+		}
+		result = append(result, *d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows assignment decisions: %w", err)
+	}
+	return result, nil
+}
+
+// scanDecision читает строку, выбранную selectDecisionsSQL.
+func scanDecision(rows *sql.Rows) (*models.AssignmentDecision, error) {
+	var (
+		d                                     models.AssignmentDecision
+		operation, strategy                   string
+		selected, replaced, rules, candidates string
+		decidedAt                             sql.NullString
+	)
+	if err := rows.Scan(&d.DecisionId, &d.PullRequestId, &operation, &strategy, &d.TeamName, &selected,
+		&replaced, &d.Pending, &rules, &candidates, &decidedAt); err != nil {
+		return nil, err
+	}
+	d.Operation = models.DecisionOperation(operation)
+	d.Strategy = models.AssignmentStrategy(strategy)
+	for _, field := range []struct {
+		raw string
+		dst any
+	}{{selected, &d.Selected}, {replaced, &d.Replaced}, {rules, &d.Rules}, {candidates, &d.Candidates}} {
+		if err := json.Unmarshal([]byte(field.raw), field.dst); err != nil {
+			return nil, fmt.Errorf("decode assignment decision: %w", err)
+		}
+	}
+	at, err := parseTime(decidedAt)
+	if err != nil {
+		return nil, err
+	}
+	if at != nil {
+		d.DecidedAt = *at
+	}
+	return &d, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// SetTeamLearners заменяет список стажёров команды в одной транзакции.
+func (s *Storage) SetTeamLearners(ctx context.Context, teamName string, userIDs []string) error {
+	organizationID := tenant.Organization(ctx)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_learners WHERE organization_id = ? AND team_name = ?`,
+			organizationID, teamName); err != nil {
+			return fmt.Errorf("delete team learners: %w", err)
+		}
+		const insertLearner = `
+INSERT INTO team_learners (organization_id, team_name, user_id) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`
+		for _, id := range userIDs {
+			if _, err := tx.ExecContext(ctx, insertLearner, organizationID, teamName, id); err != nil {
+				return fmt.Errorf("insert team learner (%s): %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindTeamLearners возвращает стажёров команды по user_id с числом их открытых теневых ревью.
+func (s *Storage) FindTeamLearners(ctx context.Context, teamName string) ([]models.Learner, error) {
+	const q = `
+SELECT l.user_id, COUNT(p.pull_request_id)
+FROM team_learners l
+LEFT JOIN pull_request_reviewers r
+    ON r.organization_id = l.organization_id AND r.user_id = l.user_id AND r.role = 'SHADOW'
+LEFT JOIN pull_requests p
+    ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id AND p.status = 'OPEN'
+WHERE l.organization_id = ? AND l.team_name = ?
+GROUP BY l.user_id
+ORDER BY l.user_id
+`
+	rows, err := s.db.QueryContext(ctx, q, tenant.Organization(ctx), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query team learners: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Learner, 0)
+	for rows.Next() {
+		var learner models.Learner
+		if err := rows.Scan(&learner.UserId, &learner.OpenShadowReviews); err != nil {
+			return nil, fmt.Errorf("scan team learners: %w", err)
+		}
+		result = append(result, learner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows team learners: %w", err)
+	}
+	return result, nil
+}
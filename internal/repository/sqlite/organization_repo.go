@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// CreateOrganization создаёт организацию с её токеном и репозиторием default в одной транзакции.
+// ORG_EXISTS, если организация с таким идентификатором уже есть.
+func (s *Storage) CreateOrganization(ctx context.Context, org *models.Organization, tokenHash string) error {
+	if org == nil {
+		return fmt.Errorf("organization is nil")
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		const insertOrg = `
+INSERT INTO organizations (organization_id, name, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT (organization_id) DO NOTHING
+`
+		res, err := tx.ExecContext(ctx, insertOrg, org.OrganizationId, org.Name, formatTime(&org.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("insert organization: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("insert organization: %w", err)
+		}
+		if n == 0 {
+			return domain.NewOrgExistsError(org.OrganizationId)
+		}
+
+		const insertToken = `INSERT INTO organization_tokens (token_hash, organization_id, created_at) VALUES (?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, insertToken, tokenHash, org.OrganizationId, formatTime(&org.CreatedAt)); err != nil {
+			return fmt.Errorf("insert organization token: %w", err)
+		}
+
+		const insertRepo = `INSERT INTO repositories (repository_name, organization_id) VALUES (?, ?)`
+		if _, err := tx.ExecContext(ctx, insertRepo, models.DefaultRepository, org.OrganizationId); err != nil {
+			return fmt.Errorf("insert default repository: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListOrganizations возвращает все организации по идентификатору.
+func (s *Storage) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	const q = `SELECT organization_id, name, created_at FROM organizations ORDER BY organization_id`
+	rows, err := s.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query organizations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.Organization, 0)
+	for rows.Next() {
+		var (
+			org       models.Organization
+			createdAt sql.NullString
+		)
+		if err := rows.Scan(&org.OrganizationId, &org.Name, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan organizations: %w", err)
+		}
+		at, err := parseTime(createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan organizations: %w", err)
+		}
+		if at != nil {
+			org.CreatedAt = *at
+		}
+		result = append(result, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows organizations: %w", err)
+	}
+	return result, nil
+}
+
+// FindOrganizationByToken возвращает организацию, которой выдан токен с хешем tokenHash; NOT_FOUND, если такого нет.
+func (s *Storage) FindOrganizationByToken(ctx context.Context, tokenHash string) (string, error) {
+	var organizationID string
+	err := s.db.QueryRowContext(ctx, `SELECT organization_id FROM organization_tokens WHERE token_hash = ?`, tokenHash).
+		Scan(&organizationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.NewNotFoundError("organization token")
+	}
+	if err != nil {
+		return "", fmt.Errorf("query organization token: %w", err)
+	}
+	return organizationID, nil
+}
@@ -0,0 +1,642 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// selectPullRequestsSQL выбирает PR организации (первый параметр) вместе с ревьюерами и теневыми ревьюерами;
+// group_concat заменяет array_agg.
+const selectPullRequestsSQL = `
+SELECT
+    p.pull_request_id,
+    p.pull_request_name,
+    p.author_id,
+    p.status,
+    p.created_at,
+    p.merged_at,
+    p.lines_added,
+    p.lines_deleted,
+    p.files_changed,
+    p.priority,
+    p.labels,
+    p.repository,
+    p.number,
+    (SELECT group_concat(r.user_id, char(31))
+       FROM pull_request_reviewers r
+      WHERE r.organization_id = p.organization_id AND r.pull_request_id = p.pull_request_id
+        AND r.role = 'REVIEWER') AS reviewers,
+    (SELECT group_concat(r.user_id, char(31))
+       FROM pull_request_reviewers r
+      WHERE r.organization_id = p.organization_id AND r.pull_request_id = p.pull_request_id
+        AND r.role = 'SHADOW') AS shadows
+FROM pull_requests p
+WHERE p.organization_id = ?
+`
+
+// SavePullRequest сохраняет или обновляет Pull Request и закреплённых ревьюеров в одной транзакции.
+func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	if pr == nil {
+		return fmt.Errorf("pr is nil")
+	}
+	if len(pr.AssignedReviewers) > 2 {
+		return fmt.Errorf("assigned reviewers count must be <= 2")
+	}
+
+	const upsertPR = `
+INSERT INTO pull_requests (
+    pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number, organization_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (organization_id, pull_request_id) DO UPDATE
+SET pull_request_name = excluded.pull_request_name,
+    author_id = excluded.author_id,
+    status = excluded.status,
+    created_at = excluded.created_at,
+    merged_at = excluded.merged_at,
+    lines_added = excluded.lines_added,
+    lines_deleted = excluded.lines_deleted,
+    files_changed = excluded.files_changed,
+    priority = excluded.priority,
+    labels = excluded.labels,
+    review_weight = excluded.review_weight,
+    repository = excluded.repository,
+    number = excluded.number
+`
+	labels, err := encodeLabels(pr.Labels)
+	if err != nil {
+		return err
+	}
+
+	organizationID := tenant.Organization(ctx)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, upsertPR,
+			pr.PullRequestId,
+			pr.PullRequestName,
+			pr.AuthorId,
+			string(pr.Status),
+			formatTime(pr.CreatedAt),
+			formatTime(pr.MergedAt),
+			pr.LinesAdded,
+			pr.LinesDeleted,
+			pr.FilesChanged,
+			string(pr.Priority),
+			labels,
+			pr.ReviewWeight(),
+			models.RepositoryOrDefault(pr.Repository),
+			nullableNumber(pr.Number),
+			organizationID,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert pull_requests: %w", err)
+		}
+
+		// Удаляем снятых ревьюеров и добавляем новых; у оставшихся сохраняется last_activity_at, роль обновляется.
+		assignments := pr.Assignments()
+		reviewers := make([]string, 0, len(assignments))
+		for _, a := range assignments {
+			reviewers = append(reviewers, a.UserId)
+		}
+		deleteSQL := `DELETE FROM pull_request_reviewers WHERE organization_id = ? AND pull_request_id = ?`
+		deleteArgs := []any{organizationID, pr.PullRequestId}
+		if len(reviewers) > 0 {
+			placeholders, args := inClause(reviewers)
+			deleteSQL += ` AND user_id NOT IN (` + placeholders + `)`
+			deleteArgs = append(deleteArgs, args...)
+		}
+		if _, err := tx.ExecContext(ctx, deleteSQL, deleteArgs...); err != nil {
+			return fmt.Errorf("delete pull_request_reviewers: %w", err)
+		}
+
+		const insertReviewer = `
+INSERT INTO pull_request_reviewers (pull_request_id, user_id, role, last_activity_at, organization_id) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (organization_id, pull_request_id, user_id) DO UPDATE SET role = excluded.role`
+		now := time.Now()
+		for _, a := range assignments {
+			if _, err := tx.ExecContext(ctx, insertReviewer, pr.PullRequestId, a.UserId, string(a.Role), formatTime(&now), organizationID); err != nil {
+				return fmt.Errorf("insert pull_request_reviewer (%s): %w", a.UserId, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetPullRequest возвращает Pull Request по идентификатору вместе со списком ревьюеров.
+func (s *Storage) GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	rows, err := s.db.QueryContext(ctx, selectPullRequestsSQL+`AND p.pull_request_id = ?`, tenant.Organization(ctx), prID)
+	if err != nil {
+		return nil, fmt.Errorf("query pull_requests: %w", err)
+	}
+	prs, err := scanPullRequests(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan pull_requests: %w", err)
+	}
+	if len(prs) == 0 {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("pull request %s", prID))
+	}
+	return prs[0], nil
+}
+
+// FindPullRequestsByReviewer находит все PR, назначенные конкретному ревьюеру.
+func (s *Storage) FindPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error) {
+	var result []*models.PullRequest
+	err := s.StreamPullRequestsByReviewer(ctx, reviewerID, func(pr *models.PullRequest) error {
+		result = append(result, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamPullRequestsByReviewer передаёт в fn PR ревьюера по мере чтения строк, не накапливая их в памяти.
+// Ошибка fn прерывает чтение и возвращается без обёртки.
+func (s *Storage) StreamPullRequestsByReviewer(ctx context.Context, reviewerID string, fn func(*models.PullRequest) error) error {
+	const where = `
+AND EXISTS (
+        SELECT 1
+        FROM pull_request_reviewers tr
+        WHERE tr.organization_id = p.organization_id
+          AND tr.pull_request_id = p.pull_request_id
+          AND tr.user_id = ?
+    )
+ORDER BY p.created_at DESC NULLS LAST, p.pull_request_id
+`
+	rows, err := s.db.QueryContext(ctx, selectPullRequestsSQL+where, tenant.Organization(ctx), reviewerID)
+	if err != nil {
+		return fmt.Errorf("query find by reviewer: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return fmt.Errorf("scan find by reviewer: %w", err)
+		}
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("scan find by reviewer: %w", err)
+	}
+	return nil
+}
+
+// assignmentPRsCTE отбирает PR по фильтру статистики; команда PR — команда автора.
+// Параметры: ?1 команда, ?2 и ?3 границы created_at, ?4 статус, ?5 репозиторий; пустые значения и NULL не фильтруют.
+// ?6 — организация: выборка и все соединения с prs ограничены ею.
+const assignmentPRsCTE = `
+WITH prs AS (
+    SELECT p.organization_id, p.pull_request_id, p.pull_request_name, p.status, p.review_weight, u.team_name
+    FROM pull_requests p
+    LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+    WHERE p.organization_id = ?6
+      AND (?1 = '' OR u.team_name = ?1)
+      AND (?2 IS NULL OR p.created_at >= ?2)
+      AND (?3 IS NULL OR p.created_at < ?3)
+      AND (?4 = '' OR p.status = ?4)
+      AND (?5 = '' OR p.repository = ?5)
+)
+`
+
+// GetAssignmentStats рассчитывает агрегированную статистику распределения ревью по PR, попавшим в фильтр.
+func (s *Storage) GetAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
+	stats := &models.AssignmentStats{}
+	err := s.StreamUserAssignments(ctx, filter, func(stat models.UserAssignmentStat) error {
+		stats.ByUser = append(stats.ByUser, stat)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.StreamPullRequestAssignments(ctx, filter, func(stat models.PullRequestAssignmentStat) error {
+		stats.ByPullRequest = append(stats.ByPullRequest, stat)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.ByTeam, err = s.GetTeamAssignmentStats(ctx, filter); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// assignmentFilterArgs раскладывает фильтр и организацию по параметрам ?1..?6 assignmentPRsCTE.
+func assignmentFilterArgs(ctx context.Context, filter models.AssignmentStatsFilter) []any {
+	return []any{filter.TeamName, nullableTime(filter.From), nullableTime(filter.To), string(filter.Status), filter.Repository,
+		tenant.Organization(ctx)}
+}
+
+// assignmentLimit возвращает параметр LIMIT; в SQLite отрицательный LIMIT снимает ограничение.
+func assignmentLimit(filter models.AssignmentStatsFilter) int {
+	if filter.Limit > 0 {
+		return filter.Limit
+	}
+	return -1
+}
+
+// StreamUserAssignments передаёт в fn число назначений по ревьюерам, начиная с самых загруженных.
+func (s *Storage) StreamUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+	const qUsers = assignmentPRsCTE + `
+SELECT
+    r.user_id,
+    COALESCE(u.username, ''),
+    SUM(r.role = 'REVIEWER') AS assignments,
+    SUM(CASE WHEN r.role = 'REVIEWER' THEN prs.review_weight ELSE 0 END) AS weighted_load,
+    SUM(r.role = 'SHADOW') AS shadow_assignments
+FROM prs
+JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+LEFT JOIN users u ON u.organization_id = r.organization_id AND u.user_id = r.user_id
+GROUP BY r.user_id, u.username
+ORDER BY assignments DESC, r.user_id
+LIMIT ?7
+`
+	userRows, err := s.db.QueryContext(ctx, qUsers, append(assignmentFilterArgs(ctx, filter), assignmentLimit(filter))...)
+	if err != nil {
+		return fmt.Errorf("query user assignment stats: %w", err)
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var stat models.UserAssignmentStat
+		if err := userRows.Scan(&stat.UserId, &stat.Username, &stat.Assignments, &stat.WeightedLoad, &stat.ShadowAssignments); err != nil {
+			return fmt.Errorf("scan user assignment stats: %w", err)
+		}
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	if err := userRows.Err(); err != nil {
+		return fmt.Errorf("user assignment stats rows: %w", err)
+	}
+	return nil
+}
+
+// StreamPullRequestAssignments передаёт в fn число ревьюеров по PR, начиная с PR с наибольшим числом.
+func (s *Storage) StreamPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+	const qPRs = assignmentPRsCTE + `
+SELECT
+    prs.pull_request_id,
+    prs.pull_request_name,
+    COUNT(CASE WHEN r.role = 'REVIEWER' THEN r.user_id END) AS reviewer_count,
+    COUNT(CASE WHEN r.role = 'SHADOW' THEN r.user_id END) AS shadow_count
+FROM prs
+LEFT JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+GROUP BY prs.pull_request_id, prs.pull_request_name
+ORDER BY reviewer_count DESC, prs.pull_request_id
+LIMIT ?7
+`
+	prRows, err := s.db.QueryContext(ctx, qPRs, append(assignmentFilterArgs(ctx, filter), assignmentLimit(filter))...)
+	if err != nil {
+		return fmt.Errorf("query pr assignment stats: %w", err)
+	}
+	defer prRows.Close()
+
+	for prRows.Next() {
+		var stat models.PullRequestAssignmentStat
+		if err := prRows.Scan(&stat.PullRequestId, &stat.PullRequestName, &stat.ReviewerCount, &stat.ShadowCount); err != nil {
+			return fmt.Errorf("scan pr assignment stats: %w", err)
+		}
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	if err := prRows.Err(); err != nil {
+		return fmt.Errorf("pr assignment stats rows: %w", err)
+	}
+	return nil
+}
+
+// GetTeamAssignmentStats считает агрегаты по командам и коэффициент дисбаланса нагрузки их активных участников.
+func (s *Storage) GetTeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+	args := assignmentFilterArgs(ctx, filter)
+	const qTeams = assignmentPRsCTE + `,
+counted AS (
+    SELECT prs.pull_request_id, prs.status, prs.team_name, COUNT(r.user_id) AS reviewer_count
+    FROM prs
+    LEFT JOIN pull_request_reviewers r
+        ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id AND r.role = 'REVIEWER'
+    WHERE prs.team_name IS NOT NULL
+    GROUP BY prs.pull_request_id, prs.status, prs.team_name
+)
+SELECT
+    team_name,
+    SUM(status = 'OPEN') AS open_count,
+    SUM(status = 'MERGED') AS merged_count,
+    AVG(reviewer_count) AS avg_reviewers
+FROM counted
+GROUP BY team_name
+ORDER BY team_name
+`
+	teamRows, err := s.db.QueryContext(ctx, qTeams, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query team assignment stats: %w", err)
+	}
+	defer teamRows.Close()
+
+	var result []models.TeamAssignmentStat
+	index := make(map[string]int)
+	for teamRows.Next() {
+		var stat models.TeamAssignmentStat
+		if err := teamRows.Scan(&stat.TeamName, &stat.OpenCount, &stat.MergedCount, &stat.AvgReviewers); err != nil {
+			return nil, fmt.Errorf("scan team assignment stats: %w", err)
+		}
+		index[stat.TeamName] = len(result)
+		result = append(result, stat)
+	}
+	if err := teamRows.Err(); err != nil {
+		return nil, fmt.Errorf("team assignment stats rows: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	// Нагрузка считается по всем активным участникам, включая тех, у кого нет назначений.
+	const qLoads = assignmentPRsCTE + `
+SELECT u.team_name, COUNT(a.user_id) AS member_load
+FROM users u
+LEFT JOIN (
+    SELECT r.user_id
+    FROM prs
+    JOIN pull_request_reviewers r ON r.organization_id = prs.organization_id AND r.pull_request_id = prs.pull_request_id
+    WHERE r.role = 'REVIEWER'
+) a ON a.user_id = u.user_id
+WHERE u.organization_id = ?6
+  AND u.is_active
+  AND u.team_name IS NOT NULL
+  AND (?1 = '' OR u.team_name = ?1)
+GROUP BY u.team_name, u.user_id
+`
+	loadRows, err := s.db.QueryContext(ctx, qLoads, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query team member loads: %w", err)
+	}
+	defer loadRows.Close()
+
+	loads := make(map[string][]int)
+	for loadRows.Next() {
+		var (
+			team string
+			load int
+		)
+		if err := loadRows.Scan(&team, &load); err != nil {
+			return nil, fmt.Errorf("scan team member loads: %w", err)
+		}
+		loads[team] = append(loads[team], load)
+	}
+	if err := loadRows.Err(); err != nil {
+		return nil, fmt.Errorf("team member loads rows: %w", err)
+	}
+
+	for team, i := range index {
+		result[i].LoadImbalance = models.LoadImbalance(loads[team])
+	}
+	return result, nil
+}
+
+// turnaroundSQL повторяет percentile_disc из PostgreSQL: перцентиль p — первое значение,
+// для которого доля строк с меньшим или равным рангом достигает p.
+const turnaroundSQL = `
+WITH merged AS (
+    SELECT
+        p.pull_request_id,
+        p.author_id,
+        u.team_name,
+        unixepoch(p.merged_at, 'subsec') - unixepoch(p.created_at, 'subsec') AS secs
+    FROM pull_requests p
+    LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+    WHERE p.organization_id = ?4
+      AND p.status = 'MERGED'
+      AND p.created_at IS NOT NULL
+      AND p.merged_at >= ?1
+      AND p.merged_at < ?2
+      AND (?3 = '' OR p.repository = ?3)
+),
+dims AS (
+    SELECT 'overall' AS dim, '' AS key, secs FROM merged
+    UNION ALL
+    SELECT 'team', team_name, secs FROM merged WHERE team_name IS NOT NULL
+    UNION ALL
+    SELECT 'author', author_id, secs FROM merged
+    UNION ALL
+    SELECT 'reviewer', r.user_id, m.secs
+    FROM merged m
+    JOIN pull_request_reviewers r ON r.organization_id = ?4 AND r.pull_request_id = m.pull_request_id AND r.role = 'REVIEWER'
+),
+ranked AS (
+    SELECT
+        dim,
+        key,
+        secs,
+        ROW_NUMBER() OVER (PARTITION BY dim, key ORDER BY secs) AS rn,
+        COUNT(*) OVER (PARTITION BY dim, key) AS cnt
+    FROM dims
+)
+SELECT
+    dim,
+    key,
+    MAX(cnt),
+    MIN(CASE WHEN rn * 1.0 / cnt >= 0.5 THEN secs END),
+    MIN(CASE WHEN rn * 1.0 / cnt >= 0.9 THEN secs END),
+    MIN(CASE WHEN rn * 1.0 / cnt >= 0.99 THEN secs END)
+FROM ranked
+GROUP BY dim, key
+ORDER BY dim, key
+`
+
+// GetTurnaroundStats считает перцентили времени от создания до слияния PR,
+// слитых в полуинтервале [From, To), в целом и по командам, авторам и ревьюерам.
+func (s *Storage) GetTurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+	rows, err := s.db.QueryContext(ctx, turnaroundSQL, formatTime(&filter.From), formatTime(&filter.To), filter.Repository,
+		tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query turnaround stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.TurnaroundStats{From: filter.From, To: filter.To}
+	for rows.Next() {
+		var (
+			dim, key string
+			p        models.TurnaroundPercentiles
+		)
+		if err := rows.Scan(&dim, &key, &p.Count, &p.P50Seconds, &p.P90Seconds, &p.P99Seconds); err != nil {
+			return nil, fmt.Errorf("scan turnaround stats: %w", err)
+		}
+		stats.AddGroup(dim, key, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("turnaround stats rows: %w", err)
+	}
+	return stats, nil
+}
+
+// FindOpenPullRequestsByReviewers ищет открытые PR с участием любых указанных ревьюеров.
+func (s *Storage) FindOpenPullRequestsByReviewers(ctx context.Context, reviewerIDs []string) ([]*models.PullRequest, error) {
+	ids := uniqueIDs(reviewerIDs)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := inClause(ids)
+	args = append([]any{tenant.Organization(ctx)}, args...)
+	where := `
+AND p.status = 'OPEN'
+  AND EXISTS (
+        SELECT 1
+        FROM pull_request_reviewers tr
+        WHERE tr.organization_id = p.organization_id
+          AND tr.pull_request_id = p.pull_request_id
+          AND tr.user_id IN (` + placeholders + `)
+          AND tr.role = 'REVIEWER'
+    )
+ORDER BY p.created_at DESC NULLS LAST, p.pull_request_id
+`
+	rows, err := s.db.QueryContext(ctx, selectPullRequestsSQL+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query open pull requests by reviewers: %w", err)
+	}
+	prs, err := scanPullRequests(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan open pull requests by reviewers: %w", err)
+	}
+	return prs, nil
+}
+
+// ApplyBulkTeamReviewerSwaps в одной транзакции заменяет ревьюеров и деактивирует пользователей.
+func (s *Storage) ApplyBulkTeamReviewerSwaps(ctx context.Context, swaps []models.ReviewerSwap, usersToDeactivate []string) error {
+	if len(swaps) == 0 && len(usersToDeactivate) == 0 {
+		return nil
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		const (
+			deleteSQL = `DELETE FROM pull_request_reviewers WHERE pull_request_id = ? AND user_id = ? AND organization_id = ? AND role = 'REVIEWER'`
+			insertSQL = `
+INSERT INTO pull_request_reviewers (pull_request_id, user_id, last_activity_at, organization_id) VALUES (?, ?, ?, ?)`
+		)
+		organizationID := tenant.Organization(ctx)
+		now := time.Now()
+		for _, swap := range swaps {
+			if swap.PullRequestId == "" || swap.OldUserId == "" || swap.NewUserId == "" {
+				return fmt.Errorf("invalid reviewer swap payload: %+v", swap)
+			}
+			if _, err := tx.ExecContext(ctx, deleteSQL, swap.PullRequestId, swap.OldUserId, organizationID); err != nil {
+				return fmt.Errorf("delete reviewer %s for pr %s: %w", swap.OldUserId, swap.PullRequestId, err)
+			}
+			if _, err := tx.ExecContext(ctx, insertSQL, swap.PullRequestId, swap.NewUserId, formatTime(&now), organizationID); err != nil {
+				return fmt.Errorf("insert reviewer %s for pr %s: %w", swap.NewUserId, swap.PullRequestId, err)
+			}
+		}
+
+		ids := uniqueIDs(usersToDeactivate)
+		if len(ids) == 0 {
+			return nil
+		}
+		placeholders, args := inClause(ids)
+		args = append([]any{organizationID}, args...)
+		const updateSQL = `UPDATE users SET is_active = 0 WHERE organization_id = ? AND user_id IN (`
+		if _, err := tx.ExecContext(ctx, updateSQL+placeholders+`)`, args...); err != nil {
+			return fmt.Errorf("bulk deactivate users: %w", err)
+		}
+		return nil
+	})
+}
+
+// scanPullRequests читает строки selectPullRequestsSQL и закрывает rows.
+func scanPullRequests(rows *sql.Rows) ([]*models.PullRequest, error) {
+	defer rows.Close()
+
+	var result []*models.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanPullRequest читает текущую строку selectPullRequestsSQL.
+func scanPullRequest(rows *sql.Rows) (*models.PullRequest, error) {
+	var (
+		pr        models.PullRequest
+		status    string
+		created   sql.NullString
+		merged    sql.NullString
+		priority  string
+		labels    string
+		number    sql.NullInt64
+		reviewers sql.NullString
+		shadows   sql.NullString
+	)
+	if err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &status, &created, &merged,
+		&pr.LinesAdded, &pr.LinesDeleted, &pr.FilesChanged, &priority, &labels, &pr.Repository, &number, &reviewers, &shadows); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if pr.CreatedAt, err = parseTime(created); err != nil {
+		return nil, err
+	}
+	if pr.MergedAt, err = parseTime(merged); err != nil {
+		return nil, err
+	}
+	if pr.Labels, err = decodeLabels(labels); err != nil {
+		return nil, err
+	}
+	pr.Status = models.PullRequestStatus(status)
+	pr.Priority = models.PullRequestPriority(priority)
+	pr.Number = int(number.Int64)
+	pr.AssignedReviewers = splitReviewers(reviewers)
+	pr.ShadowReviewers = splitReviewers(shadows)
+	return &pr, nil
+}
+
+// nullableNumber превращает отсутствующий номер PR в NULL: уникальность номера не распространяется на PR без него.
+func nullableNumber(n int) any {
+	if n <= 0 {
+		return nil
+	}
+	return n
+}
+
+// encodeLabels хранит метки JSON-массивом: в SQLite нет типа массива.
+func encodeLabels(labels []string) (string, error) {
+	if len(labels) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return "", fmt.Errorf("encode labels: %w", err)
+	}
+	return string(data), nil
+}
+
+// decodeLabels разбирает JSON-массив меток; пустой массив становится nil, как в модели без меток.
+func decodeLabels(raw string) ([]string, error) {
+	var labels []string
+	if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+		return nil, fmt.Errorf("decode labels: %w", err)
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels, nil
+}
@@ -0,0 +1,378 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// ExportSnapshot читает состояние организации из контекста внутри одной транзакции.
+func (s *Storage) ExportSnapshot(ctx context.Context) (*models.Snapshot, error) {
+	organizationID := tenant.Organization(ctx)
+	snap := &models.Snapshot{
+		Version:      models.SnapshotVersion,
+		Teams:        []models.SnapshotTeam{},
+		Users:        []models.User{},
+		PullRequests: []*models.PullRequest{},
+	}
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := queryEach(ctx, tx, `SELECT team_name FROM teams WHERE organization_id = ? ORDER BY team_name`, func(rows *sql.Rows) error {
+			var team models.SnapshotTeam
+			if err := rows.Scan(&team.TeamName); err != nil {
+				return err
+			}
+			snap.Teams = append(snap.Teams, team)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export teams: %w", err)
+		}
+
+		const qUsers = `SELECT user_id, username, is_active, COALESCE(team_name, '') FROM users WHERE organization_id = ? ORDER BY user_id`
+		if err := queryEach(ctx, tx, qUsers, func(rows *sql.Rows) error {
+			var user models.User
+			if err := rows.Scan(&user.UserId, &user.Username, &user.IsActive, &user.TeamName); err != nil {
+				return err
+			}
+			snap.Users = append(snap.Users, user)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export users: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectRepositoriesSQL+`ORDER BY repository_name`, func(rows *sql.Rows) error {
+			repo, err := scanRepository(rows)
+			if err != nil {
+				return err
+			}
+			snap.Repositories = append(snap.Repositories, *repo)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export repositories: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectPullRequestsSQL+`ORDER BY p.pull_request_id`, func(rows *sql.Rows) error {
+			pr, err := scanPullRequest(rows)
+			if err != nil {
+				return err
+			}
+			if pr.AssignedReviewers == nil {
+				pr.AssignedReviewers = []string{}
+			}
+			snap.PullRequests = append(snap.PullRequests, pr)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export pull requests: %w", err)
+		}
+
+		const qRotations = `
+SELECT pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at
+FROM review_rotations WHERE organization_id = ? ORDER BY id
+`
+		if err := queryEach(ctx, tx, qRotations, func(rows *sql.Rows) error {
+			var (
+				r         models.ReviewRotation
+				rotatedAt sql.NullString
+			)
+			if err := rows.Scan(&r.PullRequestId, &r.OldUserId, &r.NewUserId, &r.TeamName, &r.IdleSeconds, &rotatedAt); err != nil {
+				return err
+			}
+			at, err := parseTime(rotatedAt)
+			if err != nil {
+				return err
+			}
+			if at != nil {
+				r.RotatedAt = *at
+			}
+			snap.ReviewRotations = append(snap.ReviewRotations, r)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export review rotations: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectAbsencesSQL+`ORDER BY absence_id`, func(rows *sql.Rows) error {
+			a, err := scanAbsence(rows)
+			if err != nil {
+				return err
+			}
+			snap.Absences = append(snap.Absences, *a)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export absences: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectWorkingHoursSQL+`ORDER BY user_id`, func(rows *sql.Rows) error {
+			var wh models.WorkingHours
+			if err := rows.Scan(&wh.UserId, &wh.TimeZone, &wh.Start, &wh.End); err != nil {
+				return err
+			}
+			snap.WorkingHours = append(snap.WorkingHours, wh)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export working hours: %w", err)
+		}
+
+		const qCapacities = `SELECT user_id, max_open_reviews FROM user_review_capacity WHERE organization_id = ? ORDER BY user_id`
+		if err := queryEach(ctx, tx, qCapacities, func(rows *sql.Rows) error {
+			var c models.SnapshotCapacity
+			if err := rows.Scan(&c.UserId, &c.MaxOpenReviews); err != nil {
+				return err
+			}
+			snap.ReviewCapacities = append(snap.ReviewCapacities, c)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export review capacities: %w", err)
+		}
+
+		const qQueue = `
+SELECT pull_request_id, missing, queued_at FROM review_queue WHERE organization_id = ? ORDER BY queued_at, pull_request_id
+`
+		if err := queryEach(ctx, tx, qQueue, func(rows *sql.Rows) error {
+			var (
+				item     models.QueuedReview
+				queuedAt sql.NullString
+			)
+			if err := rows.Scan(&item.PullRequestId, &item.Missing, &queuedAt); err != nil {
+				return err
+			}
+			at, err := parseRequiredTime(queuedAt)
+			if err != nil {
+				return err
+			}
+			item.QueuedAt = at
+			snap.ReviewQueue = append(snap.ReviewQueue, item)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export review queue: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectAffinityRulesSQL+`ORDER BY rule_id`, func(rows *sql.Rows) error {
+			rule, err := scanAffinityRule(rows)
+			if err != nil {
+				return err
+			}
+			snap.AffinityRules = append(snap.AffinityRules, *rule)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export affinity rules: %w", err)
+		}
+
+		const qLearners = `SELECT team_name, user_id FROM team_learners WHERE organization_id = ? ORDER BY team_name, user_id`
+		if err := queryEach(ctx, tx, qLearners, func(rows *sql.Rows) error {
+			var l models.SnapshotLearner
+			if err := rows.Scan(&l.TeamName, &l.UserId); err != nil {
+				return err
+			}
+			snap.TeamLearners = append(snap.TeamLearners, l)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export team learners: %w", err)
+		}
+
+		if err := queryEach(ctx, tx, selectDecisionsSQL+`ORDER BY decision_id`, func(rows *sql.Rows) error {
+			d, err := scanDecision(rows)
+			if err != nil {
+				return err
+			}
+			snap.AssignmentDecisions = append(snap.AssignmentDecisions, *d)
+			return nil
+		}, organizationID); err != nil {
+			return fmt.Errorf("export assignment decisions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// RestoreSnapshot загружает архив в пустую организацию из контекста одной транзакцией.
+func (s *Storage) RestoreSnapshot(ctx context.Context, snap *models.Snapshot) error {
+	if snap == nil {
+		return fmt.Errorf("snapshot is nil")
+	}
+
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		const qNotEmpty = `
+SELECT EXISTS (SELECT 1 FROM teams WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM users WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM pull_requests WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM review_rotations WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM user_absences WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM user_working_hours WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM user_review_capacity WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM review_queue WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM affinity_rules WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM team_learners WHERE organization_id = ?1)
+    OR EXISTS (SELECT 1 FROM assignment_decisions WHERE organization_id = ?1)
+`
+		organizationID := tenant.Organization(ctx)
+		var notEmpty bool
+		if err := tx.QueryRowContext(ctx, qNotEmpty, organizationID).Scan(&notEmpty); err != nil {
+			return fmt.Errorf("check empty database: %w", err)
+		}
+		if notEmpty {
+			return domain.NewNotEmptyError("database")
+		}
+
+		for _, team := range snap.Teams {
+			if _, err := tx.ExecContext(ctx, insertTeamSQL, team.TeamName, organizationID); err != nil {
+				return fmt.Errorf("insert team %s: %w", team.TeamName, err)
+			}
+		}
+		for _, user := range snap.Users {
+			if _, err := tx.ExecContext(ctx, upsertUserSQL, user.UserId, user.Username, user.IsActive, user.TeamName, organizationID); err != nil {
+				return fmt.Errorf("insert user %s: %w", user.UserId, err)
+			}
+		}
+		// Репозиторий default создаётся вместе с организацией, поэтому репозитории обновляются, а не вставляются.
+		for _, repo := range snap.Repositories {
+			if _, err := tx.ExecContext(ctx, upsertRepositorySQL, repositoryArgs(organizationID, &repo)...); err != nil {
+				return fmt.Errorf("upsert repository %s: %w", repo.RepositoryName, err)
+			}
+		}
+
+		const insertPR = `
+INSERT INTO pull_requests (
+    pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number, organization_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+		const insertReviewer = `
+INSERT INTO pull_request_reviewers (pull_request_id, user_id, role, last_activity_at, organization_id) VALUES (?, ?, ?, ?, ?)
+`
+		// Таймеры SLA восстановленных назначений отсчитываются от момента загрузки, как DEFAULT now() в PostgreSQL.
+		now := time.Now()
+		for _, pr := range snap.PullRequests {
+			labels, err := encodeLabels(pr.Labels)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, insertPR,
+				pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status),
+				formatTime(pr.CreatedAt), formatTime(pr.MergedAt),
+				pr.LinesAdded, pr.LinesDeleted, pr.FilesChanged, string(pr.Priority), labels, pr.ReviewWeight(),
+				models.RepositoryOrDefault(pr.Repository), nullableNumber(pr.Number), organizationID,
+			); err != nil {
+				return fmt.Errorf("insert pull request %s: %w", pr.PullRequestId, err)
+			}
+			for _, a := range pr.Assignments() {
+				if _, err := tx.ExecContext(ctx, insertReviewer, pr.PullRequestId, a.UserId, string(a.Role), formatTime(&now), organizationID); err != nil {
+					return fmt.Errorf("insert reviewer %s of %s: %w", a.UserId, pr.PullRequestId, err)
+				}
+			}
+		}
+
+		const insertRotation = `
+INSERT INTO review_rotations (pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+		for _, r := range snap.ReviewRotations {
+			if _, err := tx.ExecContext(ctx, insertRotation,
+				r.PullRequestId, r.OldUserId, r.NewUserId, r.TeamName, r.IdleSeconds, formatTime(&r.RotatedAt), organizationID,
+			); err != nil {
+				return fmt.Errorf("insert review rotation of %s: %w", r.PullRequestId, err)
+			}
+		}
+
+		// absence_id общий для всех организаций, поэтому периоды отсутствия получают новые идентификаторы.
+		const insertAbsence = `
+INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at, reassigned_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+		for _, a := range snap.Absences {
+			if _, err := tx.ExecContext(ctx, insertAbsence,
+				a.UserId, formatTime(&a.StartsAt), formatTime(&a.EndsAt), a.Reason, formatTime(&a.CreatedAt), formatTime(a.ReassignedAt),
+				organizationID,
+			); err != nil {
+				return fmt.Errorf("insert absence of %s: %w", a.UserId, err)
+			}
+		}
+
+		const insertWorkingHours = `
+INSERT INTO user_working_hours (user_id, time_zone, work_start, work_end, organization_id) VALUES (?, ?, ?, ?, ?)
+`
+		for _, wh := range snap.WorkingHours {
+			if _, err := tx.ExecContext(ctx, insertWorkingHours, wh.UserId, wh.TimeZone, wh.Start, wh.End, organizationID); err != nil {
+				return fmt.Errorf("insert working hours of %s: %w", wh.UserId, err)
+			}
+		}
+
+		const insertCapacity = `INSERT INTO user_review_capacity (user_id, max_open_reviews, organization_id) VALUES (?, ?, ?)`
+		for _, c := range snap.ReviewCapacities {
+			if _, err := tx.ExecContext(ctx, insertCapacity, c.UserId, c.MaxOpenReviews, organizationID); err != nil {
+				return fmt.Errorf("insert review capacity of %s: %w", c.UserId, err)
+			}
+		}
+		const insertQueued = `INSERT INTO review_queue (pull_request_id, missing, queued_at, organization_id) VALUES (?, ?, ?, ?)`
+		for _, item := range snap.ReviewQueue {
+			if _, err := tx.ExecContext(ctx, insertQueued, item.PullRequestId, item.Missing, formatTime(&item.QueuedAt), organizationID); err != nil {
+				return fmt.Errorf("insert queued review %s: %w", item.PullRequestId, err)
+			}
+		}
+
+		// rule_id общий для всех организаций, поэтому правила получают новые идентификаторы.
+		const insertRule = `
+INSERT INTO affinity_rules (organization_id, team_name, kind, user_id, with_user_ids, description, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+		for _, r := range snap.AffinityRules {
+			with, err := json.Marshal(r.WithUserIds)
+			if err != nil {
+				return fmt.Errorf("encode with_user_ids: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, insertRule, organizationID, r.TeamName, string(r.Kind), r.UserId,
+				string(with), r.Description, formatTime(&r.CreatedAt)); err != nil {
+				return fmt.Errorf("insert affinity rule of %s: %w", r.TeamName, err)
+			}
+		}
+
+		const insertLearner = `INSERT INTO team_learners (organization_id, team_name, user_id) VALUES (?, ?, ?)`
+		for _, l := range snap.TeamLearners {
+			if _, err := tx.ExecContext(ctx, insertLearner, organizationID, l.TeamName, l.UserId); err != nil {
+				return fmt.Errorf("insert team learner %s of %s: %w", l.UserId, l.TeamName, err)
+			}
+		}
+
+		// decision_id тоже общий; решения вставляются по порядку и сохраняют очерёдность.
+		for _, d := range snap.AssignmentDecisions {
+			var encoded [4][]byte
+			for i, v := range []any{d.Selected, d.Replaced, d.Rules, d.Candidates} {
+				data, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("encode assignment decision: %w", err)
+				}
+				encoded[i] = data
+			}
+			if _, err := tx.ExecContext(ctx, insertDecisionSQL, organizationID, d.PullRequestId, string(d.Operation),
+				string(d.Strategy), d.TeamName, string(encoded[0]), string(encoded[1]), d.Pending,
+				string(encoded[2]), string(encoded[3]), formatTime(&d.DecidedAt)); err != nil {
+				return fmt.Errorf("insert assignment decision of %s: %w", d.PullRequestId, err)
+			}
+		}
+		return nil
+	})
+}
+
+// queryEach выполняет запрос в транзакции и вызывает scan для каждой строки.
+func queryEach(ctx context.Context, tx *sql.Tx, query string, scan func(*sql.Rows) error, args ...any) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
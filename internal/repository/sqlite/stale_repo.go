@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// FindOpenReviewAssignments возвращает назначения ревьюеров на открытые PR с числом уже выполненных замен;
+// теневые ревьюеры не торопят ревью и не попадают в выборку. Назначения без отметки активности (до миграции) отсчитываются от создания PR.
+func (s *Storage) FindOpenReviewAssignments(ctx context.Context) ([]models.ReviewAssignment, error) {
+	const q = `
+SELECT
+    r.pull_request_id,
+    r.user_id,
+    COALESCE(u.team_name, ''),
+    COALESCE(r.last_activity_at, p.created_at) AS last_activity_at,
+    (SELECT COUNT(*) FROM review_rotations rr
+        WHERE rr.organization_id = p.organization_id AND rr.pull_request_id = p.pull_request_id) AS rotations
+FROM pull_request_reviewers r
+JOIN pull_requests p ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id
+LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+WHERE r.organization_id = ? AND p.status = 'OPEN' AND r.role = 'REVIEWER'
+ORDER BY last_activity_at, r.pull_request_id, r.user_id
+`
+	rows, err := s.db.QueryContext(ctx, q, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query open review assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.ReviewAssignment
+	for rows.Next() {
+		var (
+			a        models.ReviewAssignment
+			activity sql.NullString
+		)
+		if err := rows.Scan(&a.PullRequestId, &a.ReviewerId, &a.TeamName, &activity, &a.Rotations); err != nil {
+			return nil, fmt.Errorf("scan open review assignments: %w", err)
+		}
+		at, err := parseTime(activity)
+		if err != nil {
+			return nil, fmt.Errorf("scan open review assignments: %w", err)
+		}
+		if at != nil {
+			a.LastActivityAt = *at
+		}
+		result = append(result, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows open review assignments: %w", err)
+	}
+	return result, nil
+}
+
+// TouchReviewActivity обновляет last_activity_at ревьюера; NOT_ASSIGNED, если строки назначения нет.
+func (s *Storage) TouchReviewActivity(ctx context.Context, prID, userID string, at time.Time) error {
+	const q = `
+UPDATE pull_request_reviewers SET last_activity_at = ?
+WHERE pull_request_id = ? AND user_id = ? AND organization_id = ?
+`
+	res, err := s.db.ExecContext(ctx, q, formatTime(&at), prID, userID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("update review activity: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update review activity: %w", err)
+	}
+	if n == 0 {
+		return domain.NewNotAssignedError(prID)
+	}
+	return nil
+}
+
+// RecordReviewRotation добавляет запись в историю автоматических замен.
+func (s *Storage) RecordReviewRotation(ctx context.Context, rotation *models.ReviewRotation) error {
+	if rotation == nil {
+		return fmt.Errorf("rotation is nil")
+	}
+	const q = `
+INSERT INTO review_rotations (pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+	_, err := s.db.ExecContext(ctx, q,
+		rotation.PullRequestId,
+		rotation.OldUserId,
+		rotation.NewUserId,
+		rotation.TeamName,
+		rotation.IdleSeconds,
+		formatTime(&rotation.RotatedAt),
+		tenant.Organization(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("insert review rotation: %w", err)
+	}
+	return nil
+}
+
+// ListReviewRotations возвращает историю замен, новые записи первыми.
+func (s *Storage) ListReviewRotations(ctx context.Context, filter models.ReviewRotationFilter) ([]models.ReviewRotation, error) {
+	const q = `
+SELECT pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at
+FROM review_rotations
+WHERE organization_id = ?3 AND (?1 = '' OR pull_request_id = ?1)
+ORDER BY rotated_at DESC, id DESC
+LIMIT ?2
+`
+	limit := -1
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	rows, err := s.db.QueryContext(ctx, q, filter.PullRequestId, limit, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query review rotations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.ReviewRotation, 0)
+	for rows.Next() {
+		var (
+			r         models.ReviewRotation
+			rotatedAt sql.NullString
+		)
+		if err := rows.Scan(&r.PullRequestId, &r.OldUserId, &r.NewUserId, &r.TeamName, &r.IdleSeconds, &rotatedAt); err != nil {
+			return nil, fmt.Errorf("scan review rotations: %w", err)
+		}
+		at, err := parseTime(rotatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan review rotations: %w", err)
+		}
+		if at != nil {
+			r.RotatedAt = *at
+		}
+		result = append(result, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review rotations: %w", err)
+	}
+	return result, nil
+}
+
+// AcquireLease захватывает аренду, если она свободна, истекла или уже принадлежит holder.
+// Аренды общие для всех организаций: лидер обслуживает их все.
+func (s *Storage) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	const q = `
+INSERT INTO scheduler_leases (name, holder, expires_at)
+VALUES (?1, ?2, ?3)
+ON CONFLICT (name) DO UPDATE
+SET holder = excluded.holder,
+    expires_at = excluded.expires_at
+WHERE scheduler_leases.holder = excluded.holder
+   OR scheduler_leases.expires_at < ?4
+`
+	now := time.Now()
+	expires := now.Add(ttl)
+	res, err := s.db.ExecContext(ctx, q, name, holder, formatTime(&expires), formatTime(&now))
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,157 @@
+// Package sqlite реализует репозитории сервиса поверх SQLite — для запуска одним бинарником без PostgreSQL.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	// Регистрирует драйвер "sqlite" (чистый Go, без cgo).
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// timeLayout фиксированной ширины: строки в UTC сортируются так же, как моменты времени.
+	timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+	// reviewerSeparator разделяет идентификаторы в group_concat вместо array_agg.
+	reviewerSeparator = "\x1f"
+)
+
+// Storage инкапсулирует подключение к файлу SQLite.
+type Storage struct {
+	db *sql.DB
+}
+
+// NewStorage открывает (или создаёт) файл базы SQLite и проверяет соединение.
+func NewStorage(ctx context.Context, path string) (*Storage, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is empty")
+	}
+
+	// foreign_keys включается на каждом соединении, _txlock=immediate сразу берёт блокировку записи при BEGIN.
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+	dsn := "file:" + path + "?" + params.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if path == ":memory:" {
+		// Каждое соединение к :memory: — отдельная база, поэтому держим ровно одно.
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping sqlite: %w", err)
+	}
+	return &Storage{db: db}, nil
+}
+
+// Close закрывает подключение к базе.
+func (s *Storage) Close() {
+	if s.db != nil {
+		_ = s.db.Close()
+	}
+}
+
+// withTx выполняет fn в транзакции и откатывает её при ошибке.
+func (s *Storage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback tx: %w", rollbackErr))
+			}
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// formatTime переводит время в строку для колонок created_at/merged_at (аналог TIMESTAMPTZ).
+func formatTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+// nullableTime превращает нулевое время в NULL, чтобы условие фильтра не применялось.
+func nullableTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return formatTime(&t)
+}
+
+// parseTime разбирает значение колонки времени; NULL превращается в nil.
+func parseTime(v sql.NullString) (*time.Time, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(timeLayout, v.String)
+	if err != nil {
+		return nil, fmt.Errorf("parse time %q: %w", v.String, err)
+	}
+	return &t, nil
+}
+
+// splitReviewers разбирает результат group_concat в отсортированный список.
+func splitReviewers(v sql.NullString) []string {
+	if !v.Valid || v.String == "" {
+		return nil
+	}
+	reviewers := strings.Split(v.String, reviewerSeparator)
+	sort.Strings(reviewers)
+	return reviewers
+}
+
+// inClause строит список плейсхолдеров для IN (...) вместо = ANY($1).
+func inClause(ids []string) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// uniqueIDs удаляет пустые и повторяющиеся идентификаторы.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/migrate"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/repotest"
+	"github.com/AlekseyZapadovnikov/pr-manager/migrations"
+)
+
+// newMigratedStorage создаёт базу во временном файле и применяет встроенные миграции.
+func newMigratedStorage(t *testing.T) *Storage {
+	t.Helper()
+	ctx := context.Background()
+
+	s, err := NewStorage(ctx, filepath.Join(t.TempDir(), "pr-manager.db"))
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	ms, err := migrate.Load(migrations.SQLite())
+	require.NoError(t, err)
+	_, err = s.MigrateUp(ctx, ms)
+	require.NoError(t, err)
+	return s
+}
+
+func TestStorageContract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) repotest.Backend {
+		return newMigratedStorage(t)
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewStorage(ctx, filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	ms, err := migrate.Load(migrations.SQLite())
+	require.NoError(t, err)
+	require.NotEmpty(t, ms)
+
+	applied, err := s.MigrateUp(ctx, ms)
+	require.NoError(t, err)
+	require.Len(t, applied, len(ms))
+
+	applied, err = s.MigrateUp(ctx, ms)
+	require.NoError(t, err)
+	require.Empty(t, applied, "second run must be a no-op")
+
+	status, err := s.MigrationStatus(ctx, ms)
+	require.NoError(t, err)
+	for _, st := range status {
+		require.True(t, st.Applied, "migration %d", st.Version)
+		require.NotNil(t, st.AppliedAt)
+	}
+
+	reverted, err := s.MigrateDown(ctx, ms, len(ms)+1)
+	require.NoError(t, err)
+	require.Len(t, reverted, len(ms))
+
+	_, err = s.GetTeam(ctx, "any")
+	require.Error(t, err, "tables must be dropped after full rollback")
+}
+
+func TestForeignKeysEnforced(t *testing.T) {
+	s := newMigratedStorage(t)
+	err := s.SavePullRequest(context.Background(), &models.PullRequest{
+		PullRequestId: "pr", AuthorId: "ghost", Status: models.PullRequestStatusOPEN,
+	})
+	require.Error(t, err)
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newMigratedStorage(t)
+	require.NoError(t, s.SaveTeam(ctx, &models.Team{TeamName: "t"}))
+
+	errs := make(chan error, 20)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) {
+			errs <- s.SaveUser(ctx, &models.User{UserId: fmt.Sprintf("u%d", i), Username: "U", IsActive: true, TeamName: "t"})
+		}(i)
+	}
+	for i := 0; i < cap(errs); i++ {
+		require.NoError(t, <-errs)
+	}
+
+	users, err := s.GetAllUsersInTeam(ctx, "t")
+	require.NoError(t, err)
+	require.Len(t, users, cap(errs))
+}
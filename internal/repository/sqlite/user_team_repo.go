@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const upsertUserSQL = `
+INSERT INTO users (user_id, username, is_active, team_name, organization_id)
+VALUES (?, ?, ?, NULLIF(?, ''), ?)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET username = excluded.username,
+    is_active = excluded.is_active,
+    team_name = excluded.team_name
+`
+
+const insertTeamSQL = `INSERT INTO teams (team_name, organization_id) VALUES (?, ?) ON CONFLICT (organization_id, team_name) DO NOTHING`
+
+// SaveUser выполняет upsert пользователя в таблицу users.
+func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return fmt.Errorf("user is nil")
+	}
+	if _, err := s.db.ExecContext(ctx, upsertUserSQL, user.UserId, user.Username, user.IsActive, user.TeamName,
+		tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("upsert user: %w", err)
+	}
+	return nil
+}
+
+// GetUser возвращает пользователя по идентификатору.
+func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	const q = `SELECT user_id, username, is_active, team_name FROM users WHERE user_id = ? AND organization_id = ?`
+
+	var (
+		user     models.User
+		teamName sql.NullString
+	)
+	err := s.db.QueryRowContext(ctx, q, userID, tenant.Organization(ctx)).Scan(&user.UserId, &user.Username, &user.IsActive, &teamName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("user %s", userID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query GetUser: %w", err)
+	}
+	user.TeamName = teamName.String
+	return &user, nil
+}
+
+// GetAllUsersInTeam достаёт всех пользователей указанной команды.
+func (s *Storage) GetAllUsersInTeam(ctx context.Context, teamID string) ([]*models.User, error) {
+	const q = `
+SELECT user_id, username, is_active, team_name
+FROM users
+WHERE team_name = ? AND organization_id = ?
+ORDER BY username, user_id
+`
+	rows, err := s.db.QueryContext(ctx, q, teamID, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query getAllUsersInTeam: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.User
+	for rows.Next() {
+		var (
+			user     models.User
+			teamName sql.NullString
+		)
+		if err := rows.Scan(&user.UserId, &user.Username, &user.IsActive, &teamName); err != nil {
+			return nil, fmt.Errorf("scan getAllUsersInTeam: %w", err)
+		}
+		user.TeamName = teamName.String
+		result = append(result, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error getAllUsersInTeam: %w", err)
+	}
+	return result, nil
+}
+
+// SaveTeam создаёт команду, если записи ещё нет.
+func (s *Storage) SaveTeam(ctx context.Context, team *models.Team) error {
+	if team == nil {
+		return fmt.Errorf("team is nil")
+	}
+	res, err := s.db.ExecContext(ctx, insertTeamSQL, team.TeamName, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("upsert team: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.NewTeamExistsError(team.TeamName)
+	}
+	return nil
+}
+
+// CreateTeamWithMembers сохраняет команду и всех участников в одной транзакции.
+func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team, users []models.User) error {
+	if team == nil {
+		return fmt.Errorf("team is nil")
+	}
+	return s.CreateTeamsWithMembers(ctx, []string{team.TeamName}, users)
+}
+
+// CreateTeamsWithMembers создаёт новые команды и выполняет upsert пользователей в одной транзакции.
+// Если хотя бы одна из команд уже существует, ничего не сохраняется и возвращается TEAM_EXISTS.
+func (s *Storage) CreateTeamsWithMembers(ctx context.Context, teamNames []string, users []models.User) error {
+	organizationID := tenant.Organization(ctx)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, teamName := range teamNames {
+			res, err := tx.ExecContext(ctx, insertTeamSQL, teamName, organizationID)
+			if err != nil {
+				return fmt.Errorf("insert team: %w", err)
+			}
+			if n, err := res.RowsAffected(); err == nil && n == 0 {
+				return domain.NewTeamExistsError(teamName)
+			}
+		}
+
+		for _, user := range users {
+			if _, err := tx.ExecContext(ctx, upsertUserSQL, user.UserId, user.Username, user.IsActive, user.TeamName, organizationID); err != nil {
+				return fmt.Errorf("upsert user %s: %w", user.UserId, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetTeam возвращает команду вместе с участниками.
+func (s *Storage) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
+	var tn string
+	err := s.db.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name = ? AND organization_id = ?`,
+		teamID, tenant.Organization(ctx)).Scan(&tn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("team %s", teamID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query GetTeam: %w", err)
+	}
+
+	users, err := s.GetAllUsersInTeam(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get members for team %s: %w", teamID, err)
+	}
+
+	members := make([]models.TeamMember, 0, len(users))
+	for _, u := range users {
+		members = append(members, models.ConvertUserToTeamMember(*u))
+	}
+	return &models.Team{TeamName: tn, Members: members}, nil
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+const selectWorkingHoursSQL = `
+SELECT user_id, time_zone, work_start, work_end
+FROM user_working_hours
+WHERE organization_id = ?
+`
+
+// SaveWorkingHours создаёт или заменяет рабочее время пользователя.
+func (s *Storage) SaveWorkingHours(ctx context.Context, wh *models.WorkingHours) error {
+	if wh == nil {
+		return fmt.Errorf("working hours is nil")
+	}
+	const q = `
+INSERT INTO user_working_hours (user_id, time_zone, work_start, work_end, organization_id)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET time_zone = excluded.time_zone, work_start = excluded.work_start, work_end = excluded.work_end
+`
+	if _, err := s.db.ExecContext(ctx, q, wh.UserId, wh.TimeZone, wh.Start, wh.End, tenant.Organization(ctx)); err != nil {
+		return fmt.Errorf("save working hours: %w", err)
+	}
+	return nil
+}
+
+// GetWorkingHours возвращает рабочее время пользователя; NOT_FOUND, если оно не задано.
+func (s *Storage) GetWorkingHours(ctx context.Context, userID string) (*models.WorkingHours, error) {
+	hours, err := s.queryWorkingHours(ctx, selectWorkingHoursSQL+`AND user_id = ?`, tenant.Organization(ctx), userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(hours) == 0 {
+		return nil, domain.NewNotFoundError("working hours of " + userID)
+	}
+	return &hours[0], nil
+}
+
+// DeleteWorkingHours удаляет рабочее время пользователя; NOT_FOUND, если его нет.
+func (s *Storage) DeleteWorkingHours(ctx context.Context, userID string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM user_working_hours WHERE user_id = ? AND organization_id = ?`,
+		userID, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("delete working hours: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete working hours: %w", err)
+	}
+	if n == 0 {
+		return domain.NewNotFoundError("working hours of " + userID)
+	}
+	return nil
+}
+
+// FindWorkingHours возвращает рабочее время перечисленных пользователей; у кого его нет, пропускаются.
+func (s *Storage) FindWorkingHours(ctx context.Context, userIDs []string) ([]models.WorkingHours, error) {
+	ids := uniqueIDs(userIDs)
+	if len(ids) == 0 {
+		return []models.WorkingHours{}, nil
+	}
+	placeholders, args := inClause(ids)
+	q := selectWorkingHoursSQL + `AND user_id IN (` + placeholders + `) ORDER BY user_id`
+	return s.queryWorkingHours(ctx, q, append([]any{tenant.Organization(ctx)}, args...)...)
+}
+
+// queryWorkingHours выполняет выборку из user_working_hours.
+func (s *Storage) queryWorkingHours(ctx context.Context, q string, args ...any) ([]models.WorkingHours, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query working hours: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.WorkingHours, 0)
+	for rows.Next() {
+		var wh models.WorkingHours
+		if err := rows.Scan(&wh.UserId, &wh.TimeZone, &wh.Start, &wh.End); err != nil {
+			return nil, fmt.Errorf("scan working hours: %w", err)
+		}
+		result = append(result, wh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows working hours: %w", err)
+	}
+	return result, nil
+}
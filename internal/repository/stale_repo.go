@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// FindOpenReviewAssignments возвращает назначения ревьюеров на открытые PR с числом уже выполненных замен;
+// теневые ревьюеры не торопят ревью и не попадают в выборку.
+func (s *Storage) FindOpenReviewAssignments(ctx context.Context) ([]models.ReviewAssignment, error) {
+	const q = `
+SELECT
+    r.pull_request_id,
+    r.user_id,
+    COALESCE(u.team_name, ''),
+    r.last_activity_at,
+    (SELECT COUNT(*) FROM review_rotations rr
+     WHERE rr.organization_id = p.organization_id AND rr.pull_request_id = p.pull_request_id) AS rotations
+FROM pull_request_reviewers r
+JOIN pull_requests p ON p.organization_id = r.organization_id AND p.pull_request_id = r.pull_request_id
+LEFT JOIN users u ON u.organization_id = p.organization_id AND u.user_id = p.author_id
+WHERE r.organization_id = $1
+  AND p.status = 'OPEN'
+  AND r.role = 'REVIEWER'
+ORDER BY r.last_activity_at, r.pull_request_id, r.user_id
+`
+	rows, err := s.pool.Query(ctx, q, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query open review assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.ReviewAssignment
+	for rows.Next() {
+		var a models.ReviewAssignment
+		if err := rows.Scan(&a.PullRequestId, &a.ReviewerId, &a.TeamName, &a.LastActivityAt, &a.Rotations); err != nil {
+			return nil, fmt.Errorf("scan open review assignments: %w", err)
+		}
+		result = append(result, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows open review assignments: %w", err)
+	}
+	return result, nil
+}
+
+// TouchReviewActivity обновляет last_activity_at ревьюера; NOT_ASSIGNED, если строки назначения нет.
+func (s *Storage) TouchReviewActivity(ctx context.Context, prID, userID string, at time.Time) error {
+	const q = `
+UPDATE pull_request_reviewers SET last_activity_at = $3
+WHERE pull_request_id = $1 AND user_id = $2 AND organization_id = $4
+`
+	tag, err := s.pool.Exec(ctx, q, prID, userID, at, tenant.Organization(ctx))
+	if err != nil {
+		return fmt.Errorf("update review activity: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewNotAssignedError(prID)
+	}
+	return nil
+}
+
+// RecordReviewRotation добавляет запись в историю автоматических замен.
+func (s *Storage) RecordReviewRotation(ctx context.Context, rotation *models.ReviewRotation) error {
+	if rotation == nil {
+		return fmt.Errorf("rotation is nil")
+	}
+	const q = `
+INSERT INTO review_rotations (pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at, organization_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+	_, err := s.pool.Exec(ctx, q,
+		rotation.PullRequestId,
+		rotation.OldUserId,
+		rotation.NewUserId,
+		rotation.TeamName,
+		rotation.IdleSeconds,
+		rotation.RotatedAt,
+		tenant.Organization(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("insert review rotation: %w", err)
+	}
+	return nil
+}
+
+// ListReviewRotations возвращает историю замен, новые записи первыми.
+func (s *Storage) ListReviewRotations(ctx context.Context, filter models.ReviewRotationFilter) ([]models.ReviewRotation, error) {
+	const q = `
+SELECT pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at
+FROM review_rotations
+WHERE organization_id = $3
+  AND ($1::text = '' OR pull_request_id = $1)
+ORDER BY rotated_at DESC, id DESC
+LIMIT $2
+`
+	var limit any
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	rows, err := s.pool.Query(ctx, q, filter.PullRequestId, limit, tenant.Organization(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query review rotations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.ReviewRotation, 0)
+	for rows.Next() {
+		var r models.ReviewRotation
+		if err := rows.Scan(&r.PullRequestId, &r.OldUserId, &r.NewUserId, &r.TeamName, &r.IdleSeconds, &r.RotatedAt); err != nil {
+			return nil, fmt.Errorf("scan review rotations: %w", err)
+		}
+		result = append(result, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows review rotations: %w", err)
+	}
+	return result, nil
+}
+
+// AcquireLease захватывает аренду, если она свободна, истекла или уже принадлежит holder.
+// Сроки считаются по часам базы данных, поэтому расхождение часов инстансов не влияет на выбор лидера.
+// Аренды общие для всех организаций: лидер обслуживает их все.
+func (s *Storage) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	const q = `
+INSERT INTO scheduler_leases (name, holder, expires_at)
+VALUES ($1, $2, now() + make_interval(secs => $3))
+ON CONFLICT (name) DO UPDATE
+SET holder = EXCLUDED.holder,
+    expires_at = EXCLUDED.expires_at
+WHERE scheduler_leases.holder = EXCLUDED.holder
+   OR scheduler_leases.expires_at < now()
+`
+	tag, err := s.pool.Exec(ctx, q, name, holder, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -34,8 +34,18 @@ func NewStorage(ctx context.Context, cfg *conf.DbConf) (*Storage, error) {
 		cfg.Port,
 		cfg.Name,
 	)
+	return newStorageFromDSN(ctx, connStr)
+}
+
+// newStorageFromDSN подключается к PostgreSQL по готовой строке подключения.
+func newStorageFromDSN(ctx context.Context, connStr string) (*Storage, error) {
+	poolCfg, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection string: %w", err)
+	}
+	poolCfg.ConnConfig.Tracer = newQueryTracer()
 
-	pool, err := pgxpool.New(ctx, connStr)
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -49,6 +59,14 @@ func NewStorage(ctx context.Context, cfg *conf.DbConf) (*Storage, error) {
 	return &Storage{pool: pool}, nil
 }
 
+// PoolStat возвращает статистику пула соединений; nil, если пул её не предоставляет (например, в тестах).
+func (s *Storage) PoolStat() *pgxpool.Stat {
+	if statPool, ok := s.pool.(interface{ Stat() *pgxpool.Stat }); ok {
+		return statPool.Stat()
+	}
+	return nil
+}
+
 // Close закрывает пул подключений, когда он больше не нужен.
 func (s *Storage) Close() {
 	if s.pool != nil {
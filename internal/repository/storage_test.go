@@ -3,22 +3,35 @@ package repository
 import (
 	"context"
 	"errors"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v2"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
 var (
 	testCtx            = context.Background()
-	pullRequestRowCols = []string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at"}
-	teamRowCols        = []string{"team_name"}
-	teamMemberRowCols  = []string{"user_id", "username", "is_active", "team_name"}
+	pullRequestRowCols = []string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at",
+		"lines_added", "lines_deleted", "files_changed", "priority", "labels", "repository", "number"}
+	teamRowCols           = []string{"team_name"}
+	repositoryRowCols     = []string{"repository_name", "owner_team", "required_reviewers"}
+	teamMemberRowCols     = []string{"user_id", "username", "is_active", "team_name"}
+	reviewRotationRowCols = []string{"pull_request_id", "old_user_id", "new_user_id", "team_name", "idle_seconds", "rotated_at"}
+	absenceRowCols        = []string{"absence_id", "user_id", "starts_at", "ends_at", "reason", "created_at", "reassigned_at"}
+	workingHoursRowCols   = []string{"user_id", "time_zone", "work_start", "work_end"}
+	decisionRowCols       = []string{
+		"decision_id", "pull_request_id", "operation", "strategy", "team_name", "selected", "replaced", "pending", "rules", "candidates", "decided_at",
+	}
+	affinityRuleRowCols = []string{"rule_id", "team_name", "kind", "user_id", "with_user_ids", "description", "created_at"}
 )
 
 const (
@@ -87,7 +100,7 @@ func TestStorage_SavePullRequest(t *testing.T) {
 		pr := testPullRequest()
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnError(errors.New("fail insert"))
 		mock.ExpectRollback()
 
@@ -101,10 +114,10 @@ func TestStorage_SavePullRequest(t *testing.T) {
 		pr := testPullRequest()
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs(pr.PullRequestId).
+			WithArgs(pr.PullRequestId, pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnError(errors.New("delete failed"))
 		mock.ExpectRollback()
 
@@ -119,13 +132,13 @@ func TestStorage_SavePullRequest(t *testing.T) {
 		pr.AssignedReviewers = []string{"reviewer-1"}
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs(pr.PullRequestId).
+			WithArgs(pr.PullRequestId, pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs(pr.PullRequestId, "reviewer-1").
+			WithArgs(pr.PullRequestId, "reviewer-1", "REVIEWER", models.DefaultOrganization).
 			WillReturnError(errors.New("insert reviewer failed"))
 		mock.ExpectRollback()
 
@@ -140,13 +153,13 @@ func TestStorage_SavePullRequest(t *testing.T) {
 		pr.AssignedReviewers = []string{"one"}
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs(pr.PullRequestId).
+			WithArgs(pr.PullRequestId, pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs(pr.PullRequestId, "one").
+			WithArgs(pr.PullRequestId, "one", "REVIEWER", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectCommit().WillReturnError(errors.New("commit fail"))
 		mock.ExpectRollback()
@@ -163,16 +176,16 @@ func TestStorage_SavePullRequest(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs(pr.PullRequestId).
+			WithArgs(pr.PullRequestId, pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 0))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs(pr.PullRequestId, "first").
+			WithArgs(pr.PullRequestId, "first", "REVIEWER", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs(pr.PullRequestId, "second").
+			WithArgs(pr.PullRequestId, "second", "REVIEWER", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectCommit()
 
@@ -188,13 +201,13 @@ func TestStorage_SavePullRequest(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_requests")).
-			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt).
+			WithArgs(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), pr.CreatedAt, pr.MergedAt, 0, 0, 0, "", []string{}, 1, models.DefaultRepository, nil, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs(pr.PullRequestId).
+			WithArgs(pr.PullRequestId, pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 0))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs(pr.PullRequestId, "first").
+			WithArgs(pr.PullRequestId, "first", "REVIEWER", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectCommit()
 
@@ -207,7 +220,7 @@ func TestStorage_SavePullRequest(t *testing.T) {
 func TestStorage_GetPullRequestQueryError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnError(errors.New("query fail"))
 
 	if _, err := s.GetPullRequest(testCtx, testPullRequestID); err == nil || !regexp.MustCompile("query pull_requests").MatchString(err.Error()) {
@@ -218,7 +231,7 @@ func TestStorage_GetPullRequestQueryError(t *testing.T) {
 func TestStorage_GetPullRequestNotFound(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(pullRequestRowCols))
 
 	_, err := s.GetPullRequest(testCtx, testPullRequestID)
@@ -230,10 +243,10 @@ func TestStorage_GetPullRequestNotFound(t *testing.T) {
 func TestStorage_GetPullRequestScanError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	rows := pgxmock.NewRows(pullRequestRowCols).
-		AddRow(testPullRequestID, 123, "author", "OPEN", nil, nil).
+		AddRow(testPullRequestID, 123, "author", "OPEN", nil, nil, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil).
 		RowError(0, errors.New("scan fail"))
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnRows(rows)
 
 	if _, err := s.GetPullRequest(testCtx, testPullRequestID); err == nil || !regexp.MustCompile("scan pull_requests").MatchString(err.Error()) {
@@ -245,11 +258,11 @@ func TestStorage_GetPullRequestReviewersQueryError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	now := time.Now().UTC()
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(pullRequestRowCols).
-			AddRow(testPullRequestID, "name", "author", "OPEN", &now, nil))
-	mock.ExpectQuery("SELECT\\s+user_id\\s+FROM\\s+pull_request_reviewers").
-		WithArgs(testPullRequestID).
+			AddRow(testPullRequestID, "name", "author", "OPEN", &now, nil, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil))
+	mock.ExpectQuery("SELECT\\s+user_id, role\\s+FROM\\s+pull_request_reviewers").
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnError(errors.New("reviewer query"))
 
 	if _, err := s.GetPullRequest(testCtx, testPullRequestID); err == nil || !regexp.MustCompile("query pull_request_reviewers").MatchString(err.Error()) {
@@ -261,13 +274,13 @@ func TestStorage_GetPullRequestReviewersScanError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	now := time.Now().UTC()
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(pullRequestRowCols).
-			AddRow(testPullRequestID, "name", "author", "OPEN", &now, nil))
-	mock.ExpectQuery("SELECT\\s+user_id\\s+FROM\\s+pull_request_reviewers").
-		WithArgs(testPullRequestID).
-		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).
-			AddRow("user-1").
+			AddRow(testPullRequestID, "name", "author", "OPEN", &now, nil, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil))
+	mock.ExpectQuery("SELECT\\s+user_id, role\\s+FROM\\s+pull_request_reviewers").
+		WithArgs(testPullRequestID, models.DefaultOrganization).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "role"}).
+			AddRow("user-1", "REVIEWER").
 			RowError(0, errors.New("scan reviewer")))
 
 	if _, err := s.GetPullRequest(testCtx, testPullRequestID); err == nil || !regexp.MustCompile("scan reviewer").MatchString(err.Error()) {
@@ -279,15 +292,17 @@ func TestStorage_GetPullRequestSuccess(t *testing.T) {
 	s, mock := newTestStorage(t)
 	created := time.Now().UTC()
 	merged := created.Add(time.Hour)
+	number := 42
 	mock.ExpectQuery("SELECT\\s+pull_request_id").
-		WithArgs(testPullRequestID).
+		WithArgs(testPullRequestID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(pullRequestRowCols).
-			AddRow(testPullRequestID, "name", "author", "OPEN", &created, &merged))
-	mock.ExpectQuery("SELECT\\s+user_id\\s+FROM\\s+pull_request_reviewers").
-		WithArgs(testPullRequestID).
-		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).
-			AddRow("a").
-			AddRow("b"))
+			AddRow(testPullRequestID, "name", "author", "OPEN", &created, &merged, 120, 30, 4, "HIGH", []string{"backend"}, "billing", &number))
+	mock.ExpectQuery("SELECT\\s+user_id, role\\s+FROM\\s+pull_request_reviewers").
+		WithArgs(testPullRequestID, models.DefaultOrganization).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "role"}).
+			AddRow("a", "REVIEWER").
+			AddRow("mentee", "SHADOW").
+			AddRow("b", "REVIEWER"))
 
 	pr, err := s.GetPullRequest(testCtx, testPullRequestID)
 	if err != nil {
@@ -299,19 +314,26 @@ func TestStorage_GetPullRequestSuccess(t *testing.T) {
 	if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] != "a" || pr.AssignedReviewers[1] != "b" {
 		t.Fatalf("unexpected reviewers: %+v", pr.AssignedReviewers)
 	}
+	if !reflect.DeepEqual(pr.ShadowReviewers, []string{"mentee"}) {
+		t.Fatalf("unexpected shadow reviewers: %+v", pr.ShadowReviewers)
+	}
 	if pr.CreatedAt == nil || pr.MergedAt == nil {
 		t.Fatal("expected timestamps to be set")
 	}
+	if pr.LinesAdded != 120 || pr.LinesDeleted != 30 || pr.FilesChanged != 4 || pr.Priority != models.PullRequestPriorityHIGH ||
+		!reflect.DeepEqual(pr.Labels, []string{"backend"}) || pr.Repository != "billing" || pr.Number != 42 {
+		t.Fatalf("unexpected metadata: %+v", pr)
+	}
 }
 
 func TestStorage_FindPullRequestsByReviewer(t *testing.T) {
 	const reviewerID = "rev-1"
-	columns := []string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "reviewers"}
+	columns := append(slices.Clone(pullRequestRowCols), "reviewers", "shadows")
 
 	t.Run("query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").
-			WithArgs(reviewerID).
+			WithArgs(reviewerID, models.DefaultOrganization).
 			WillReturnError(errors.New("query fail"))
 
 		if _, err := s.FindPullRequestsByReviewer(testCtx, reviewerID); err == nil || !regexp.MustCompile("query find by reviewer").MatchString(err.Error()) {
@@ -324,10 +346,10 @@ func TestStorage_FindPullRequestsByReviewer(t *testing.T) {
 		created := time.Now().UTC()
 		var merged *time.Time
 		rows := pgxmock.NewRows(columns).
-			AddRow("pr", "name", "author", "OPEN", &created, merged, []string{}).
+			AddRow("pr", "name", "author", "OPEN", &created, merged, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil, []string{}, []string{}).
 			RowError(0, errors.New("scan fail"))
 		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").
-			WithArgs(reviewerID).
+			WithArgs(reviewerID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		if _, err := s.FindPullRequestsByReviewer(testCtx, reviewerID); err == nil || !regexp.MustCompile("scan find by reviewer").MatchString(err.Error()) {
@@ -340,10 +362,10 @@ func TestStorage_FindPullRequestsByReviewer(t *testing.T) {
 		created := time.Now().UTC()
 		var merged *time.Time
 		rows := pgxmock.NewRows(columns).
-			AddRow("pr", "name", "author", "OPEN", &created, merged, []string{"a"}).
+			AddRow("pr", "name", "author", "OPEN", &created, merged, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil, []string{"a"}, []string{}).
 			RowError(1, errors.New("rows err"))
 		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").
-			WithArgs(reviewerID).
+			WithArgs(reviewerID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		if _, err := s.FindPullRequestsByReviewer(testCtx, reviewerID); err == nil || !regexp.MustCompile("rows error").MatchString(err.Error()) {
@@ -351,14 +373,35 @@ func TestStorage_FindPullRequestsByReviewer(t *testing.T) {
 		}
 	})
 
+	t.Run("reviewer filter does not trim reviewer lists", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		created := time.Now().UTC()
+		var merged *time.Time
+		rows := pgxmock.NewRows(columns).
+			AddRow("pr", "name", "author", "OPEN", &created, merged, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil, []string{reviewerID, "rev-2"}, []string{"mentee"})
+		// Ревьюер ищется подзапросом EXISTS: условие в WHERE по присоединённой строке
+		// оставило бы в array_agg только его самого.
+		mock.ExpectQuery(`AND EXISTS \(\s+SELECT 1\s+FROM pull_request_reviewers tr`).
+			WithArgs(reviewerID, models.DefaultOrganization).
+			WillReturnRows(rows)
+
+		list, err := s.FindPullRequestsByReviewer(testCtx, reviewerID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || len(list[0].AssignedReviewers) != 2 || len(list[0].ShadowReviewers) != 1 {
+			t.Fatalf("unexpected pr data: %+v", list)
+		}
+	})
+
 	t.Run("success", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		created := time.Now().UTC()
 		var merged *time.Time
 		rows := pgxmock.NewRows(columns).
-			AddRow("pr", "name", "author", "OPEN", &created, merged, []string{"x", "y"})
+			AddRow("pr", "name", "author", "OPEN", &created, merged, 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil, []string{"x", "y"}, []string{})
 		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").
-			WithArgs(reviewerID).
+			WithArgs(reviewerID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		list, err := s.FindPullRequestsByReviewer(testCtx, reviewerID)
@@ -386,7 +429,7 @@ func TestStorage_SaveUser(t *testing.T) {
 		s, mock := newTestStorage(t)
 		user := &models.User{UserId: "u", Username: "name", IsActive: true}
 		mock.ExpectExec("INSERT\\s+INTO\\s+users").
-			WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName).
+			WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName, models.DefaultOrganization).
 			WillReturnError(errors.New("exec fail"))
 
 		if err := s.SaveUser(testCtx, user); err == nil || !regexp.MustCompile("upsert user").MatchString(err.Error()) {
@@ -398,7 +441,7 @@ func TestStorage_SaveUser(t *testing.T) {
 		s, mock := newTestStorage(t)
 		user := &models.User{UserId: "u", Username: "name", IsActive: true, TeamName: "team"}
 		mock.ExpectExec("INSERT\\s+INTO\\s+users").
-			WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName).
+			WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		if err := s.SaveUser(testCtx, user); err != nil {
@@ -414,7 +457,7 @@ func TestStorage_GetUser(t *testing.T) {
 	t.Run("query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(userID).
+			WithArgs(userID, models.DefaultOrganization).
 			WillReturnError(errors.New("query fail"))
 
 		if _, err := s.GetUser(testCtx, userID); err == nil || !regexp.MustCompile("query GetUser").MatchString(err.Error()) {
@@ -425,7 +468,7 @@ func TestStorage_GetUser(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(userID).
+			WithArgs(userID, models.DefaultOrganization).
 			WillReturnRows(pgxmock.NewRows(columns))
 
 		_, err := s.GetUser(testCtx, userID)
@@ -440,7 +483,7 @@ func TestStorage_GetUser(t *testing.T) {
 			AddRow(userID, "name", true, "team").
 			RowError(0, errors.New("scan fail"))
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(userID).
+			WithArgs(userID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		if _, err := s.GetUser(testCtx, userID); err == nil || !regexp.MustCompile("scan GetUser").MatchString(err.Error()) {
@@ -454,7 +497,7 @@ func TestStorage_GetUser(t *testing.T) {
 		rows := pgxmock.NewRows(columns).
 			AddRow(userID, "name", true, teamName)
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(userID).
+			WithArgs(userID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		user, err := s.GetUser(testCtx, userID)
@@ -477,7 +520,7 @@ func TestStorage_GetAllUsersInTeam(t *testing.T) {
 	t.Run("query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(teamID).
+			WithArgs(teamID, models.DefaultOrganization).
 			WillReturnError(errors.New("query fail"))
 
 		if _, err := s.GetAllUsersInTeam(testCtx, teamID); err == nil || !regexp.MustCompile("query getAllUsersInTeam").MatchString(err.Error()) {
@@ -492,7 +535,7 @@ func TestStorage_GetAllUsersInTeam(t *testing.T) {
 			AddRow("user", "name", true, &teamName).
 			RowError(0, errors.New("scan fail"))
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(teamID).
+			WithArgs(teamID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		if _, err := s.GetAllUsersInTeam(testCtx, teamID); err == nil || !regexp.MustCompile("scan getAllUsersInTeam").MatchString(err.Error()) {
@@ -507,7 +550,7 @@ func TestStorage_GetAllUsersInTeam(t *testing.T) {
 			AddRow("user", "name", true, &teamName).
 			RowError(1, errors.New("rows fail"))
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(teamID).
+			WithArgs(teamID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		if _, err := s.GetAllUsersInTeam(testCtx, teamID); err == nil || !regexp.MustCompile("rows error getAllUsersInTeam").MatchString(err.Error()) {
@@ -522,7 +565,7 @@ func TestStorage_GetAllUsersInTeam(t *testing.T) {
 			AddRow("user", "name", true, &teamName).
 			AddRow("user2", "name2", false, nil)
 		mock.ExpectQuery("SELECT\\s+user_id").
-			WithArgs(teamID).
+			WithArgs(teamID, models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		users, err := s.GetAllUsersInTeam(testCtx, teamID)
@@ -547,7 +590,7 @@ func TestStorage_SaveTeam(t *testing.T) {
 		s, mock := newTestStorage(t)
 		team := &models.Team{TeamName: "devs"}
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnError(errors.New("exec fail"))
 
 		if err := s.SaveTeam(testCtx, team); err == nil || !regexp.MustCompile("upsert team").MatchString(err.Error()) {
@@ -559,7 +602,7 @@ func TestStorage_SaveTeam(t *testing.T) {
 		s, mock := newTestStorage(t)
 		team := &models.Team{TeamName: "devs"}
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 0))
 
 		if err := s.SaveTeam(testCtx, team); err == nil || !errors.Is(err, domain.ErrTeamExists) {
@@ -571,7 +614,7 @@ func TestStorage_SaveTeam(t *testing.T) {
 		s, mock := newTestStorage(t)
 		team := &models.Team{TeamName: "devs"}
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		if err := s.SaveTeam(testCtx, team); err != nil {
@@ -607,7 +650,7 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnError(errors.New("insert fail"))
 		mock.ExpectRollback()
 
@@ -620,7 +663,7 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 0))
 		mock.ExpectRollback()
 
@@ -633,10 +676,10 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec("INSERT\\s+INTO\\s+users").
-			WithArgs(users[0].UserId, users[0].Username, users[0].IsActive, users[0].TeamName).
+			WithArgs(users[0].UserId, users[0].Username, users[0].IsActive, users[0].TeamName, models.DefaultOrganization).
 			WillReturnError(errors.New("user fail"))
 		mock.ExpectRollback()
 
@@ -649,11 +692,11 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		for _, user := range users {
 			mock.ExpectExec("INSERT\\s+INTO\\s+users").
-				WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName).
+				WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName, models.DefaultOrganization).
 				WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		}
 		mock.ExpectCommit().WillReturnError(errors.New("commit fail"))
@@ -668,11 +711,11 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
-			WithArgs(team.TeamName).
+			WithArgs(team.TeamName, models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		for _, user := range users {
 			mock.ExpectExec("INSERT\\s+INTO\\s+users").
-				WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName).
+				WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName, models.DefaultOrganization).
 				WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		}
 		mock.ExpectCommit()
@@ -683,10 +726,51 @@ func TestStorage_CreateTeamWithMembers(t *testing.T) {
 	})
 }
 
+func TestStorage_CreateTeamsWithMembers(t *testing.T) {
+	users := []models.User{
+		{UserId: "u1", Username: "name1", IsActive: true, TeamName: "frontend"},
+		{UserId: "u2", Username: "name2", IsActive: true, TeamName: "backend"},
+	}
+
+	t.Run("second team exists", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
+			WithArgs("frontend", models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT\\s+INTO\\s+teams").
+			WithArgs("ops", models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		if err := s.CreateTeamsWithMembers(testCtx, []string{"frontend", "ops"}, users); !errors.Is(err, domain.ErrTeamExists) {
+			t.Fatalf("expected team exists error, got %v", err)
+		}
+	})
+
+	t.Run("only existing teams", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		for _, user := range users {
+			mock.ExpectExec("INSERT\\s+INTO\\s+users").
+				WithArgs(user.UserId, user.Username, user.IsActive, user.TeamName, models.DefaultOrganization).
+				WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		}
+		mock.ExpectCommit()
+
+		if err := s.CreateTeamsWithMembers(testCtx, nil, users); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unmet expectations: %v", err)
+		}
+	})
+}
+
 func TestStorage_GetTeamQueryError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+team_name").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnError(errors.New("query fail"))
 
 	if _, err := s.GetTeam(testCtx, testTeamID); err == nil || !regexp.MustCompile("query GetTeam").MatchString(err.Error()) {
@@ -697,7 +781,7 @@ func TestStorage_GetTeamQueryError(t *testing.T) {
 func TestStorage_GetTeamNotFound(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+team_name").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(teamRowCols))
 
 	_, err := s.GetTeam(testCtx, testTeamID)
@@ -712,7 +796,7 @@ func TestStorage_GetTeamScanError(t *testing.T) {
 		AddRow(testTeamID).
 		RowError(0, errors.New("scan fail"))
 	mock.ExpectQuery("SELECT\\s+team_name").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnRows(rows)
 
 	if _, err := s.GetTeam(testCtx, testTeamID); err == nil || !regexp.MustCompile("scan GetTeam").MatchString(err.Error()) {
@@ -723,10 +807,10 @@ func TestStorage_GetTeamScanError(t *testing.T) {
 func TestStorage_GetTeamMembersQueryError(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+team_name").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(teamRowCols).AddRow(testTeamID))
 	mock.ExpectQuery("SELECT\\s+user_id").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnError(errors.New("user query fail"))
 
 	if _, err := s.GetTeam(testCtx, testTeamID); err == nil || !regexp.MustCompile("get members for team").MatchString(err.Error()) {
@@ -737,12 +821,12 @@ func TestStorage_GetTeamMembersQueryError(t *testing.T) {
 func TestStorage_GetTeamSuccess(t *testing.T) {
 	s, mock := newTestStorage(t)
 	mock.ExpectQuery("SELECT\\s+team_name").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(teamRowCols).AddRow(testTeamID))
 	teamName1 := testTeamID
 	teamName2 := testTeamID
 	mock.ExpectQuery("SELECT\\s+user_id").
-		WithArgs(testTeamID).
+		WithArgs(testTeamID, models.DefaultOrganization).
 		WillReturnRows(pgxmock.NewRows(teamMemberRowCols).
 			AddRow("u1", "name1", true, &teamName1).
 			AddRow("u2", "name2", false, &teamName2))
@@ -763,14 +847,17 @@ func TestStorage_GetTeamSuccess(t *testing.T) {
 }
 
 func TestStorage_GetAssignmentStats(t *testing.T) {
-	userCols := []string{"user_id", "username", "assignments"}
-	prCols := []string{"pull_request_id", "pull_request_name", "reviewer_count"}
+	userCols := []string{"user_id", "username", "assignments", "weighted_load", "shadow_assignments"}
+	prCols := []string{"pull_request_id", "pull_request_name", "reviewer_count", "shadow_count"}
+	teamCols := []string{"team_name", "open_count", "merged_count", "avg_reviewers"}
+	loadCols := []string{"team_name", "member_load"}
+	noFilter := []interface{}{"", nil, nil, "", "", models.DefaultOrganization}
 
 	t.Run("user query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
-		mock.ExpectQuery("SELECT\\s+r\\.user_id").WillReturnError(errors.New("boom"))
+		mock.ExpectQuery("AS assignments").WithArgs(append(noFilter, nil)...).WillReturnError(errors.New("boom"))
 
-		if _, err := s.GetAssignmentStats(testCtx); err == nil || !regexp.MustCompile("query user assignment stats").MatchString(err.Error()) {
+		if _, err := s.GetAssignmentStats(testCtx, models.AssignmentStatsFilter{}); err == nil || !regexp.MustCompile("query user assignment stats").MatchString(err.Error()) {
 			t.Fatalf("expected user query error, got %v", err)
 		}
 	})
@@ -778,10 +865,10 @@ func TestStorage_GetAssignmentStats(t *testing.T) {
 	t.Run("user scan error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		rows := pgxmock.NewRows(userCols).
-			AddRow("user-1", 123, int64(5))
-		mock.ExpectQuery("SELECT\\s+r\\.user_id").WillReturnRows(rows)
+			AddRow("user-1", 123, int64(5), int64(5), int64(0))
+		mock.ExpectQuery("AS assignments").WithArgs(append(noFilter, nil)...).WillReturnRows(rows)
 
-		if _, err := s.GetAssignmentStats(testCtx); err == nil || !regexp.MustCompile("scan user assignment stats").MatchString(err.Error()) {
+		if _, err := s.GetAssignmentStats(testCtx, models.AssignmentStatsFilter{}); err == nil || !regexp.MustCompile("scan user assignment stats").MatchString(err.Error()) {
 			t.Fatalf("expected scan error, got %v", err)
 		}
 	})
@@ -789,37 +876,142 @@ func TestStorage_GetAssignmentStats(t *testing.T) {
 	t.Run("pr query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		userRows := pgxmock.NewRows(userCols).
-			AddRow("user-1", "Alice", int64(2))
-		mock.ExpectQuery("SELECT\\s+r\\.user_id").WillReturnRows(userRows)
-		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").WillReturnError(errors.New("boom"))
+			AddRow("user-1", "Alice", int64(2), int64(3), int64(0))
+		mock.ExpectQuery("AS assignments").WithArgs(append(noFilter, nil)...).WillReturnRows(userRows)
+		mock.ExpectQuery("AS shadow_count\\s+FROM prs").WithArgs(append(noFilter, nil)...).WillReturnError(errors.New("boom"))
 
-		if _, err := s.GetAssignmentStats(testCtx); err == nil || !regexp.MustCompile("query pr assignment stats").MatchString(err.Error()) {
+		if _, err := s.GetAssignmentStats(testCtx, models.AssignmentStatsFilter{}); err == nil || !regexp.MustCompile("query pr assignment stats").MatchString(err.Error()) {
 			t.Fatalf("expected pr query error, got %v", err)
 		}
 	})
 
+	t.Run("team query error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectQuery("AS assignments").WithArgs(append(noFilter, nil)...).WillReturnRows(pgxmock.NewRows(userCols))
+		mock.ExpectQuery("AS shadow_count\\s+FROM prs").WithArgs(append(noFilter, nil)...).WillReturnRows(pgxmock.NewRows(prCols))
+		mock.ExpectQuery("AS merged_count").WithArgs(noFilter...).WillReturnError(errors.New("boom"))
+
+		if _, err := s.GetAssignmentStats(testCtx, models.AssignmentStatsFilter{}); err == nil || !regexp.MustCompile("query team assignment stats").MatchString(err.Error()) {
+			t.Fatalf("expected team query error, got %v", err)
+		}
+	})
+
 	t.Run("success", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		userRows := pgxmock.NewRows(userCols).
-			AddRow("user-1", "Alice", int64(2)).
-			AddRow("user-2", "Bob", int64(1))
-		mock.ExpectQuery("SELECT\\s+r\\.user_id").WillReturnRows(userRows)
+			AddRow("user-1", "Alice", int64(2), int64(3), int64(1)).
+			AddRow("user-2", "Bob", int64(1), int64(1), int64(0))
+		mock.ExpectQuery("AS assignments").WithArgs(append(noFilter, nil)...).WillReturnRows(userRows)
 
 		prRows := pgxmock.NewRows(prCols).
-			AddRow("pr-1", "Docs", int64(2)).
-			AddRow("pr-2", "API", int64(1))
-		mock.ExpectQuery("SELECT\\s+p\\.pull_request_id").WillReturnRows(prRows)
+			AddRow("pr-1", "Docs", int64(2), int64(1)).
+			AddRow("pr-2", "API", int64(1), int64(0))
+		mock.ExpectQuery("AS shadow_count\\s+FROM prs").WithArgs(append(noFilter, nil)...).WillReturnRows(prRows)
+
+		teamRows := pgxmock.NewRows(teamCols).AddRow("backend", int64(1), int64(1), 1.5)
+		mock.ExpectQuery("AS merged_count").WithArgs(noFilter...).WillReturnRows(teamRows)
+
+		loadRows := pgxmock.NewRows(loadCols).
+			AddRow("backend", int64(2)).
+			AddRow("backend", int64(1)).
+			AddRow("backend", int64(0))
+		mock.ExpectQuery("AS member_load").WithArgs(noFilter...).WillReturnRows(loadRows)
 
-		stats, err := s.GetAssignmentStats(testCtx)
+		stats, err := s.GetAssignmentStats(testCtx, models.AssignmentStatsFilter{})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if len(stats.ByUser) != 2 || stats.ByUser[0].UserId != "user-1" || stats.ByUser[0].Assignments != 2 {
+		if len(stats.ByUser) != 2 || stats.ByUser[0].UserId != "user-1" || stats.ByUser[0].Assignments != 2 || stats.ByUser[0].WeightedLoad != 3 ||
+			stats.ByUser[0].ShadowAssignments != 1 {
 			t.Fatalf("unexpected user stats: %+v", stats.ByUser)
 		}
-		if len(stats.ByPullRequest) != 2 || stats.ByPullRequest[1].PullRequestName != "API" || stats.ByPullRequest[1].ReviewerCount != 1 {
+		if len(stats.ByPullRequest) != 2 || stats.ByPullRequest[1].PullRequestName != "API" || stats.ByPullRequest[1].ReviewerCount != 1 ||
+			stats.ByPullRequest[0].ShadowCount != 1 {
 			t.Fatalf("unexpected pr stats: %+v", stats.ByPullRequest)
 		}
+		if len(stats.ByTeam) != 1 || stats.ByTeam[0].OpenCount != 1 || stats.ByTeam[0].AvgReviewers != 1.5 {
+			t.Fatalf("unexpected team stats: %+v", stats.ByTeam)
+		}
+		if got := stats.ByTeam[0].LoadImbalance; got < 0.81 || got > 0.82 {
+			t.Fatalf("unexpected load imbalance: %v", got)
+		}
+	})
+
+	t.Run("filter arguments", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+		filter := models.AssignmentStatsFilter{TeamName: "backend", Repository: "billing", From: from, Status: models.PullRequestStatusOPEN, Limit: 5}
+		args := []interface{}{"backend", from, nil, "OPEN", "billing", models.DefaultOrganization}
+
+		mock.ExpectQuery("AS assignments").WithArgs(append(args, 5)...).WillReturnRows(pgxmock.NewRows(userCols))
+		mock.ExpectQuery("AS shadow_count\\s+FROM prs").WithArgs(append(args, 5)...).WillReturnRows(pgxmock.NewRows(prCols))
+		mock.ExpectQuery("AS merged_count").WithArgs(args...).WillReturnRows(pgxmock.NewRows(teamCols))
+
+		stats, err := s.GetAssignmentStats(testCtx, filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.ByTeam != nil {
+			t.Fatalf("expected no team stats, got %+v", stats.ByTeam)
+		}
+	})
+}
+
+func TestStorage_GetTurnaroundStats(t *testing.T) {
+	cols := []string{"dim", "key", "count", "p50", "p90", "p99"}
+	filter := models.TurnaroundFilter{
+		From:       time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		Repository: "billing",
+	}
+
+	t.Run("query error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectQuery("WITH merged AS").WithArgs(filter.From, filter.To, filter.Repository, models.DefaultOrganization).WillReturnError(errors.New("boom"))
+
+		if _, err := s.GetTurnaroundStats(testCtx, filter); err == nil || !regexp.MustCompile("query turnaround stats").MatchString(err.Error()) {
+			t.Fatalf("expected query error, got %v", err)
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		rows := pgxmock.NewRows(cols).AddRow("overall", "", "many", 1.0, 2.0, 3.0)
+		mock.ExpectQuery("WITH merged AS").WithArgs(filter.From, filter.To, filter.Repository, models.DefaultOrganization).WillReturnRows(rows)
+
+		if _, err := s.GetTurnaroundStats(testCtx, filter); err == nil || !regexp.MustCompile("scan turnaround stats").MatchString(err.Error()) {
+			t.Fatalf("expected scan error, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		rows := pgxmock.NewRows(cols).
+			AddRow("author", "u1", int64(2), 60.0, 120.0, 120.0).
+			AddRow("overall", "", int64(2), 60.0, 120.0, 120.0).
+			AddRow("reviewer", "u2", int64(1), 120.0, 120.0, 120.0).
+			AddRow("team", "backend", int64(2), 60.0, 120.0, 120.0)
+		mock.ExpectQuery("WITH merged AS").WithArgs(filter.From, filter.To, filter.Repository, models.DefaultOrganization).WillReturnRows(rows)
+
+		stats, err := s.GetTurnaroundStats(testCtx, filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.Overall.Count != 2 || stats.Overall.P50Seconds != 60 || stats.Overall.P90Seconds != 120 {
+			t.Fatalf("unexpected overall: %+v", stats.Overall)
+		}
+		if len(stats.ByTeam) != 1 || stats.ByTeam[0].TeamName != "backend" {
+			t.Fatalf("unexpected team stats: %+v", stats.ByTeam)
+		}
+		if len(stats.ByAuthor) != 1 || stats.ByAuthor[0].UserId != "u1" {
+			t.Fatalf("unexpected author stats: %+v", stats.ByAuthor)
+		}
+		if len(stats.ByReviewer) != 1 || stats.ByReviewer[0].UserId != "u2" || stats.ByReviewer[0].P99Seconds != 120 {
+			t.Fatalf("unexpected reviewer stats: %+v", stats.ByReviewer)
+		}
+		if !stats.From.Equal(filter.From) || !stats.To.Equal(filter.To) {
+			t.Fatalf("unexpected window: %s..%s", stats.From, stats.To)
+		}
 	})
 }
 
@@ -838,7 +1030,7 @@ func TestStorage_FindOpenPullRequestsByReviewers(t *testing.T) {
 	t.Run("query error", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectQuery(regexp.QuoteMeta("SELECT ")).
-			WithArgs(pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnError(errors.New("boom"))
 
 		_, err := s.FindOpenPullRequestsByReviewers(testCtx, []string{"u1"})
@@ -850,10 +1042,10 @@ func TestStorage_FindOpenPullRequestsByReviewers(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		s, mock := newTestStorage(t)
 		now := time.Now()
-		rows := pgxmock.NewRows([]string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "reviewers"}).
-			AddRow("pr-1", "add feature", "author-1", "OPEN", &now, nil, []string{"u1", "u2"})
+		rows := pgxmock.NewRows(append(slices.Clone(pullRequestRowCols), "reviewers", "shadows")).
+			AddRow("pr-1", "add feature", "author-1", "OPEN", &now, nil, 0, 0, 0, "URGENT", []string{}, models.DefaultRepository, nil, []string{"u1", "u2"}, []string{})
 		mock.ExpectQuery(regexp.QuoteMeta("SELECT ")).
-			WithArgs(pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnRows(rows)
 
 		prs, err := s.FindOpenPullRequestsByReviewers(testCtx, []string{"u1", "u2"})
@@ -878,7 +1070,7 @@ func TestStorage_ApplyBulkTeamReviewerSwaps(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs("pr-1", "old").
+			WithArgs("pr-1", "old", models.DefaultOrganization).
 			WillReturnError(errors.New("boom"))
 		mock.ExpectRollback()
 
@@ -894,13 +1086,13 @@ func TestStorage_ApplyBulkTeamReviewerSwaps(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs("pr-1", "old").
+			WithArgs("pr-1", "old", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs("pr-1", "new").
+			WithArgs("pr-1", "new", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET is_active = false")).
-			WithArgs(pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnError(errors.New("boom"))
 		mock.ExpectRollback()
 
@@ -916,13 +1108,13 @@ func TestStorage_ApplyBulkTeamReviewerSwaps(t *testing.T) {
 		s, mock := newTestStorage(t)
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM pull_request_reviewers")).
-			WithArgs("pr-1", "old").
+			WithArgs("pr-1", "old", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pull_request_reviewers")).
-			WithArgs("pr-1", "new").
+			WithArgs("pr-1", "new", models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 		mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET is_active = false")).
-			WithArgs(pgxmock.AnyArg()).
+			WithArgs(pgxmock.AnyArg(), models.DefaultOrganization).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
 		mock.ExpectCommit()
 
@@ -952,3 +1144,480 @@ func TestStorage_Close(t *testing.T) {
 	s := &Storage{}
 	s.Close() // should not panic without pool
 }
+
+func TestStorage_ExportSnapshot(t *testing.T) {
+	t.Run("assembles reviewers", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		created := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec("SET\\s+TRANSACTION\\s+ISOLATION\\s+LEVEL\\s+REPEATABLE\\s+READ").
+			WillReturnResult(pgxmock.NewResult("SET", 0))
+		mock.ExpectQuery("SELECT\\s+team_name\\s+FROM\\s+teams").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(teamRowCols).AddRow("backend"))
+		mock.ExpectQuery("FROM\\s+users\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+user_id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(teamMemberRowCols).
+				AddRow("u1", "Alice", true, "backend").
+				AddRow("u2", "Bob", false, ""))
+		mock.ExpectQuery("FROM\\s+repositories\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+repository_name").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(repositoryRowCols).
+				AddRow(models.DefaultRepository, "", 2).
+				AddRow("billing", "backend", 1))
+		mock.ExpectQuery("FROM\\s+pull_requests\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+pull_request_id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(pullRequestRowCols).
+				AddRow("pr-1", "Feature", "u1", "OPEN", &created, (*time.Time)(nil), 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil).
+				AddRow("pr-2", "Fix", "u1", "OPEN", &created, (*time.Time)(nil), 0, 0, 0, "NORMAL", []string{}, models.DefaultRepository, nil))
+		mock.ExpectQuery("FROM\\s+pull_request_reviewers\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "user_id", "role"}).AddRow("pr-1", "u2", "REVIEWER"))
+		mock.ExpectQuery("FROM\\s+review_rotations\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(reviewRotationRowCols).AddRow("pr-1", "u1", "u2", "backend", int64(3600), created))
+		mock.ExpectQuery("FROM\\s+user_absences\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+absence_id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(absenceRowCols).AddRow(int64(7), "u2", created, created.Add(time.Hour), "", created, (*time.Time)(nil)))
+		mock.ExpectQuery("FROM\\s+user_working_hours\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+user_id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(workingHoursRowCols).AddRow("u1", "Europe/Berlin", "09:00", "18:00"))
+		mock.ExpectQuery("FROM\\s+user_review_capacity\\s+WHERE\\s+organization_id\\s+=\\s+\\$1").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "max_open_reviews"}).AddRow("u2", 3))
+		mock.ExpectQuery("FROM\\s+review_queue\\s+WHERE\\s+organization_id\\s+=\\s+\\$1").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows([]string{"pull_request_id", "missing", "queued_at"}).AddRow("pr-2", 2, created))
+		mock.ExpectQuery("FROM\\s+affinity_rules\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+rule_id").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(affinityRuleRowCols).AddRow(int64(4), "backend", "NEVER_PAIR", "u1", []string{"u2"}, "", created))
+		mock.ExpectQuery("FROM\\s+team_learners\\s+WHERE\\s+organization_id\\s+=\\s+\\$1").WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows([]string{"team_name", "user_id"}).AddRow("backend", "u2"))
+		mock.ExpectQuery("FROM\\s+assignment_decisions\\s+WHERE\\s+organization_id\\s+=\\s+\\$1\\s+ORDER\\s+BY\\s+decision_id").
+			WithArgs(models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows(decisionRowCols).AddRow(int64(9), "pr-1", "CREATE", "BALANCED", "backend",
+				[]string{"u2"}, []string(nil), 0, []string(nil), []models.DecisionCandidate{{UserId: "u2", Outcome: models.CandidateOutcomeSELECTED}}, created))
+		mock.ExpectCommit()
+
+		snap, err := s.ExportSnapshot(testCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(snap.Teams) != 1 || len(snap.Users) != 2 || snap.Users[1].TeamName != "" {
+			t.Fatalf("unexpected teams or users: %+v", snap)
+		}
+		if len(snap.PullRequests) != 2 || len(snap.PullRequests[0].AssignedReviewers) != 1 || len(snap.PullRequests[1].AssignedReviewers) != 0 {
+			t.Fatalf("unexpected pull requests: %+v", snap.PullRequests)
+		}
+		if len(snap.Repositories) != 2 || snap.Repositories[1] != (models.Repository{RepositoryName: "billing", OwnerTeam: "backend", RequiredReviewers: 1}) {
+			t.Fatalf("unexpected repositories: %+v", snap.Repositories)
+		}
+		if len(snap.ReviewRotations) != 1 || snap.ReviewRotations[0].NewUserId != "u2" || !snap.ReviewRotations[0].RotatedAt.Equal(created) {
+			t.Fatalf("unexpected review rotations: %+v", snap.ReviewRotations)
+		}
+		if len(snap.Absences) != 1 || snap.Absences[0].AbsenceId != 7 || snap.Absences[0].UserId != "u2" {
+			t.Fatalf("unexpected absences: %+v", snap.Absences)
+		}
+		if len(snap.WorkingHours) != 1 || snap.WorkingHours[0] != (models.WorkingHours{UserId: "u1", TimeZone: "Europe/Berlin", Start: "09:00", End: "18:00"}) {
+			t.Fatalf("unexpected working hours: %+v", snap.WorkingHours)
+		}
+		if len(snap.ReviewCapacities) != 1 || snap.ReviewCapacities[0] != (models.SnapshotCapacity{UserId: "u2", MaxOpenReviews: 3}) {
+			t.Fatalf("unexpected review capacities: %+v", snap.ReviewCapacities)
+		}
+		if len(snap.ReviewQueue) != 1 || snap.ReviewQueue[0].PullRequestId != "pr-2" || snap.ReviewQueue[0].Missing != 2 {
+			t.Fatalf("unexpected review queue: %+v", snap.ReviewQueue)
+		}
+		if len(snap.AffinityRules) != 1 || snap.AffinityRules[0].Kind != models.AffinityRuleNEVERPAIR || snap.AffinityRules[0].WithUserIds[0] != "u2" {
+			t.Fatalf("unexpected affinity rules: %+v", snap.AffinityRules)
+		}
+		if len(snap.TeamLearners) != 1 || snap.TeamLearners[0] != (models.SnapshotLearner{TeamName: "backend", UserId: "u2"}) {
+			t.Fatalf("unexpected team learners: %+v", snap.TeamLearners)
+		}
+		if len(snap.AssignmentDecisions) != 1 || snap.AssignmentDecisions[0].Operation != models.DecisionOperationCREATE ||
+			len(snap.AssignmentDecisions[0].Candidates) != 1 {
+			t.Fatalf("unexpected assignment decisions: %+v", snap.AssignmentDecisions)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SET\\s+TRANSACTION").WillReturnResult(pgxmock.NewResult("SET", 0))
+		mock.ExpectQuery("SELECT\\s+team_name\\s+FROM\\s+teams").WithArgs(models.DefaultOrganization).WillReturnError(errors.New("boom"))
+		mock.ExpectRollback()
+
+		if _, err := s.ExportSnapshot(testCtx); err == nil || !regexp.MustCompile("export teams").MatchString(err.Error()) {
+			t.Fatalf("expected export teams error, got %v", err)
+		}
+	})
+}
+
+func TestStorage_RestoreSnapshot(t *testing.T) {
+	created := time.Date(2025, time.January, 1, 10, 0, 0, 0, time.UTC)
+	snap := &models.Snapshot{
+		Version: models.SnapshotVersion,
+		Teams:   []models.SnapshotTeam{{TeamName: "backend"}},
+		Users: []models.User{
+			{UserId: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+			{UserId: "u2", Username: "Bob", IsActive: true},
+		},
+		PullRequests: []*models.PullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "Feature", AuthorId: "u1", Status: models.PullRequestStatusOPEN, CreatedAt: &created, AssignedReviewers: []string{"u2"}},
+		},
+		Repositories:     []models.Repository{{RepositoryName: "billing", OwnerTeam: "backend", RequiredReviewers: 1}},
+		ReviewRotations:  []models.ReviewRotation{{PullRequestId: "pr-1", OldUserId: "u1", NewUserId: "u2", TeamName: "backend", IdleSeconds: 3600, RotatedAt: created}},
+		Absences:         []models.Absence{{AbsenceId: 7, UserId: "u2", StartsAt: created, EndsAt: created.Add(time.Hour), CreatedAt: created}},
+		WorkingHours:     []models.WorkingHours{{UserId: "u1", TimeZone: "Europe/Berlin", Start: "09:00", End: "18:00"}},
+		ReviewCapacities: []models.SnapshotCapacity{{UserId: "u2", MaxOpenReviews: 3}},
+		ReviewQueue:      []models.QueuedReview{{PullRequestId: "pr-1", Missing: 1, QueuedAt: created}},
+		AffinityRules: []models.AffinityRule{
+			{RuleId: 4, TeamName: "backend", Kind: models.AffinityRuleNEVERPAIR, UserId: "u1", WithUserIds: []string{"u2"}, CreatedAt: created},
+		},
+		TeamLearners: []models.SnapshotLearner{{TeamName: "backend", UserId: "u2"}},
+		AssignmentDecisions: []models.AssignmentDecision{{
+			DecisionId: 9, PullRequestId: "pr-1", Operation: models.DecisionOperationCREATE, Strategy: models.AssignmentStrategyBALANCED,
+			TeamName: "backend", Selected: []string{"u2"}, Candidates: []models.DecisionCandidate{}, DecidedAt: created,
+		}},
+	}
+
+	t.Run("database not empty", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("LOCK\\s+TABLE").WillReturnResult(pgxmock.NewResult("LOCK", 0))
+		mock.ExpectQuery("SELECT\\s+EXISTS").WithArgs(models.DefaultOrganization).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		if err := s.RestoreSnapshot(testCtx, snap); !errors.Is(err, domain.ErrNotEmpty) {
+			t.Fatalf("expected not empty error, got %v", err)
+		}
+	})
+
+	t.Run("copy error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("LOCK\\s+TABLE").WillReturnResult(pgxmock.NewResult("LOCK", 0))
+		mock.ExpectQuery("SELECT\\s+EXISTS").WithArgs(models.DefaultOrganization).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectCopyFrom(pgx.Identifier{"teams"}, []string{"team_name", "organization_id"}).WillReturnResult(1)
+		mock.ExpectExec("INSERT\\s+INTO\\s+repositories").
+			WithArgs("billing", pgxmock.AnyArg(), 1, models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCopyFrom(pgx.Identifier{"users"}, []string{"user_id", "username", "is_active", "team_name", "organization_id"}).
+			WillReturnError(errors.New("fk violation"))
+		mock.ExpectRollback()
+
+		if err := s.RestoreSnapshot(testCtx, snap); err == nil || !regexp.MustCompile("copy users").MatchString(err.Error()) {
+			t.Fatalf("expected copy error, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("LOCK\\s+TABLE").WillReturnResult(pgxmock.NewResult("LOCK", 0))
+		mock.ExpectQuery("SELECT\\s+EXISTS").WithArgs(models.DefaultOrganization).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectCopyFrom(pgx.Identifier{"teams"}, []string{"team_name", "organization_id"}).WillReturnResult(1)
+		mock.ExpectExec("INSERT\\s+INTO\\s+repositories").
+			WithArgs("billing", pgxmock.AnyArg(), 1, models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCopyFrom(pgx.Identifier{"users"}, []string{"user_id", "username", "is_active", "team_name", "organization_id"}).WillReturnResult(2)
+		mock.ExpectCopyFrom(pgx.Identifier{"pull_requests"}, []string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at",
+			"lines_added", "lines_deleted", "files_changed", "priority", "labels", "review_weight", "repository", "number", "organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"pull_request_reviewers"}, []string{"pull_request_id", "user_id", "role", "organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"review_rotations"}, []string{"pull_request_id", "old_user_id", "new_user_id", "team_name", "idle_seconds", "rotated_at",
+			"organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"user_absences"}, []string{"user_id", "starts_at", "ends_at", "reason", "created_at", "reassigned_at",
+			"organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"user_working_hours"}, []string{"user_id", "time_zone", "work_start", "work_end", "organization_id"}).
+			WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"user_review_capacity"}, []string{"user_id", "max_open_reviews", "organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"review_queue"}, []string{"pull_request_id", "missing", "queued_at", "organization_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"affinity_rules"}, []string{"organization_id", "team_name", "kind", "user_id", "with_user_ids",
+			"description", "created_at"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"team_learners"}, []string{"organization_id", "team_name", "user_id"}).WillReturnResult(1)
+		mock.ExpectCopyFrom(pgx.Identifier{"assignment_decisions"}, []string{"organization_id", "pull_request_id", "operation",
+			"strategy", "team_name", "selected", "replaced", "pending", "rules", "candidates", "decided_at"}).WillReturnResult(1)
+		mock.ExpectCommit()
+
+		if err := s.RestoreSnapshot(testCtx, snap); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStorage_TouchReviewActivity(t *testing.T) {
+	at := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+
+	t.Run("not assigned", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pull_request_reviewers SET last_activity_at")).
+			WithArgs("pr-1", "u1", at, models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := s.TouchReviewActivity(testCtx, "pr-1", "u1", at)
+		if !errors.Is(err, domain.ErrNotAssigned) {
+			t.Fatalf("expected NOT_ASSIGNED, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pull_request_reviewers SET last_activity_at")).
+			WithArgs("pr-1", "u1", at, models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		if err := s.TouchReviewActivity(testCtx, "pr-1", "u1", at); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStorage_AcquireLease(t *testing.T) {
+	t.Run("held by another instance", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO scheduler_leases")).
+			WithArgs("job", "b", float64(60)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		ok, err := s.AcquireLease(testCtx, "job", "b", time.Minute)
+		if err != nil || ok {
+			t.Fatalf("expected lease to be refused, got ok=%v err=%v", ok, err)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO scheduler_leases")).
+			WithArgs("job", "a", float64(60)).
+			WillReturnError(errors.New("boom"))
+
+		if _, err := s.AcquireLease(testCtx, "job", "a", time.Minute); err == nil || !strings.Contains(err.Error(), "acquire lease job") {
+			t.Fatalf("expected lease error, got %v", err)
+		}
+	})
+}
+
+func TestStorage_CreateAbsence(t *testing.T) {
+	s, mock := newTestStorage(t)
+	start := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	absence := &models.Absence{UserId: "u1", StartsAt: start, EndsAt: start.Add(24 * time.Hour), Reason: "vacation", CreatedAt: start}
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO user_absences")).
+		WithArgs("u1", absence.StartsAt, absence.EndsAt, "vacation", absence.CreatedAt, models.DefaultOrganization).
+		WillReturnRows(pgxmock.NewRows([]string{"absence_id"}).AddRow(int64(7)))
+
+	if err := s.CreateAbsence(testCtx, absence); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if absence.AbsenceId != 7 {
+		t.Fatalf("expected absence id 7, got %d", absence.AbsenceId)
+	}
+}
+
+func TestStorage_DeleteAbsence(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_absences")).
+			WithArgs(int64(7), models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		if err := s.DeleteAbsence(testCtx, 7); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected NOT_FOUND, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_absences")).
+			WithArgs(int64(7), models.DefaultOrganization).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		if err := s.DeleteAbsence(testCtx, 7); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStorage_FindAbsentUsers(t *testing.T) {
+	s, mock := newTestStorage(t)
+	at := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT user_id")).
+		WithArgs(at, models.DefaultOrganization).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow("u1").AddRow("u3"))
+
+	ids, err := s.FindAbsentUsers(testCtx, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ids, ",") != "u1,u3" {
+		t.Fatalf("unexpected absent users: %v", ids)
+	}
+}
+
+func TestStorage_SaveWorkingHours(t *testing.T) {
+	s, mock := newTestStorage(t)
+	wh := &models.WorkingHours{UserId: "u1", TimeZone: "Europe/Berlin", Start: "09:00", End: "17:00"}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_working_hours")).
+		WithArgs("u1", "Europe/Berlin", "09:00", "17:00", models.DefaultOrganization).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	if err := s.SaveWorkingHours(testCtx, wh); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStorage_FindWorkingHours(t *testing.T) {
+	t.Run("no users", func(t *testing.T) {
+		s, _ := newTestStorage(t)
+		hours, err := s.FindWorkingHours(testCtx, nil)
+		if err != nil || len(hours) != 0 {
+			t.Fatalf("expected empty result without a query, got %v (err=%v)", hours, err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectQuery(regexp.QuoteMeta("FROM user_working_hours")).
+			WithArgs(models.DefaultOrganization, []string{"u1", "u2"}).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "time_zone", "work_start", "work_end"}).
+				AddRow("u1", "Europe/Berlin", "09:00", "17:00"))
+
+		hours, err := s.FindWorkingHours(testCtx, []string{"u1", "u2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(hours) != 1 || hours[0].TimeZone != "Europe/Berlin" {
+			t.Fatalf("unexpected working hours: %+v", hours)
+		}
+	})
+}
+
+func TestStorage_DeleteWorkingHoursNotFound(t *testing.T) {
+	s, mock := newTestStorage(t)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_working_hours")).
+		WithArgs("u1", models.DefaultOrganization).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := s.DeleteWorkingHours(testCtx, "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
+
+func TestStorage_SaveCapacity(t *testing.T) {
+	s, mock := newTestStorage(t)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_review_capacity")).
+		WithArgs("u1", 3, models.DefaultOrganization).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	if err := s.SaveCapacity(testCtx, "u1", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStorage_CountOpenReviews(t *testing.T) {
+	s, mock := newTestStorage(t)
+	mock.ExpectQuery(regexp.QuoteMeta("COUNT(*) FILTER (WHERE p.status = 'OPEN')")).
+		WithArgs(models.DefaultOrganization).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "open_reviews"}).AddRow("u1", int64(2)).AddRow("u2", int64(0)))
+
+	open, err := s.CountOpenReviews(testCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(open) != 2 || open["u1"] != 2 || open["u2"] != 0 {
+		t.Fatalf("unexpected open reviews: %v", open)
+	}
+}
+
+func TestStorage_FindReviewLoad(t *testing.T) {
+	t.Run("no users", func(t *testing.T) {
+		s, _ := newTestStorage(t)
+		loads, err := s.FindReviewLoad(testCtx, nil)
+		if err != nil || len(loads) != 0 {
+			t.Fatalf("expected empty result without a query, got %v (err=%v)", loads, err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectQuery(regexp.QuoteMeta("LEFT JOIN user_review_capacity")).
+			WithArgs([]string{"u1", "u2"}, models.DefaultOrganization).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "open_reviews", "weighted_load", "max_open_reviews"}).
+				AddRow("u1", 2, 5, 3).
+				AddRow("u2", 0, 0, 0))
+
+		loads, err := s.FindReviewLoad(testCtx, []string{"u1", "u2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []models.ReviewerLoad{{UserId: "u1", OpenReviews: 2, WeightedLoad: 5, MaxOpenReviews: 3}, {UserId: "u2"}}
+		if !reflect.DeepEqual(loads, want) {
+			t.Fatalf("expected %+v, got %+v", want, loads)
+		}
+	})
+}
+
+func TestStorage_EnqueueReview(t *testing.T) {
+	s, mock := newTestStorage(t)
+	queuedAt := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_queue")).
+		WithArgs("pr-1", 1, queuedAt, models.DefaultOrganization).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	if err := s.EnqueueReview(testCtx, models.QueuedReview{PullRequestId: "pr-1", Missing: 1, QueuedAt: queuedAt}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStorage_GetUserUsesContextOrganization(t *testing.T) {
+	s, mock := newTestStorage(t)
+	mock.ExpectQuery("SELECT\\s+user_id").
+		WithArgs("u1", "acme").
+		WillReturnRows(pgxmock.NewRows(teamMemberRowCols))
+
+	if _, err := s.GetUser(tenant.WithOrganization(testCtx, "acme"), "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestStorage_CreateOrganization(t *testing.T) {
+	created := time.Date(2025, time.April, 1, 9, 0, 0, 0, time.UTC)
+	org := &models.Organization{OrganizationId: "acme", Name: "Acme", CreatedAt: created}
+
+	t.Run("organization exists", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO organizations")).
+			WithArgs("acme", "Acme", created).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		if err := s.CreateOrganization(testCtx, org, "hash"); !errors.Is(err, domain.ErrOrgExists) {
+			t.Fatalf("expected org exists error, got %v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		s, mock := newTestStorage(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO organizations")).
+			WithArgs("acme", "Acme", created).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO organization_tokens")).
+			WithArgs("hash", "acme").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO repositories")).
+			WithArgs(models.DefaultRepository, "acme").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		if err := s.CreateOrganization(testCtx, org, "hash"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStorage_FindOrganizationByToken(t *testing.T) {
+	s, mock := newTestStorage(t)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM organization_tokens")).
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM organization_tokens")).
+		WithArgs("hash").
+		WillReturnRows(pgxmock.NewRows([]string{"organization_id"}).AddRow("acme"))
+
+	if _, err := s.FindOrganizationByToken(testCtx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	id, err := s.FindOrganizationByToken(testCtx, "hash")
+	if err != nil || id != "acme" {
+		t.Fatalf("expected acme, got %q and %v", id, err)
+	}
+}
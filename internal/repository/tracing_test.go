@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryTracerRecordsStatements(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	qt := &queryTracer{tracer: tp.Tracer("test")}
+
+	ctx := qt.TraceQueryStart(testCtx, nil, pgx.TraceQueryStartData{SQL: "\n\tdelete FROM pull_request_reviewers WHERE pull_request_id = $1"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("DELETE 2")})
+
+	ctx = qt.TraceQueryStart(testCtx, nil, pgx.TraceQueryStartData{SQL: "INSERT INTO teams (team_name) VALUES ($1)"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("duplicate key")})
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "db DELETE" {
+		t.Fatalf("unexpected span name %q", spans[0].Name())
+	}
+	var rows int64 = -1
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == "db.rows_affected" {
+			rows = attr.Value.AsInt64()
+		}
+	}
+	if rows != 2 {
+		t.Fatalf("expected rows_affected=2, got %d", rows)
+	}
+	if spans[1].Name() != "db INSERT" || spans[1].Status().Code != codes.Error {
+		t.Fatalf("expected failed INSERT span, got %q with status %v", spans[1].Name(), spans[1].Status())
+	}
+}
+
+func TestSQLOperation(t *testing.T) {
+	cases := map[string]string{
+		"  select 1":             "SELECT",
+		"\nWITH x AS (SELECT 1)": "WITH",
+		"":                       "QUERY",
+	}
+	for in, want := range cases {
+		if got := sqlOperation(in); got != want {
+			t.Fatalf("sqlOperation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,24 +7,34 @@ import (
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
+// upsertUserSQL общий для SaveUser и CreateTeamsWithMembers, чтобы одиночное и пакетное сохранение не расходились.
+const upsertUserSQL = `
+	INSERT INTO users (user_id, username, is_active, team_name, organization_id)
+	VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+	ON CONFLICT (organization_id, user_id) DO UPDATE
+	SET username = EXCLUDED.username,
+		is_active = EXCLUDED.is_active,
+		team_name = EXCLUDED.team_name
+	`
+
+// insertTeamSQL не трогает существующую команду; RowsAffected() == 0 означает TEAM_EXISTS.
+const insertTeamSQL = `
+		INSERT INTO teams (team_name, organization_id)
+		VALUES ($1, $2)
+		ON CONFLICT (organization_id, team_name) DO NOTHING
+	`
+
 // SaveUser выполняет upsert пользователя в таблицу users.
 func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
 	if user == nil {
 		return fmt.Errorf("user is nil")
 	}
 
-	const upsertUser = `
-	INSERT INTO users (user_id, username, is_active, team_name)
-	VALUES ($1, $2, $3, NULLIF($4, ''))
-	ON CONFLICT (user_id) DO UPDATE
-	SET username = EXCLUDED.username,
-		is_active = EXCLUDED.is_active,
-		team_name = EXCLUDED.team_name
-	`
 	// Р•СЃР»Рё user.TeamName == "" - РїРµСЂРµРґР°С‘Рј РїСѓСЃС‚СѓСЋ СЃС‚СЂРѕРє, Р° РІ Р·Р°РїСЂРѕСЃРµ NULLIF('', '') -> NULL
-	_, err := s.pool.Exec(ctx, upsertUser, user.UserId, user.Username, user.IsActive, user.TeamName)
+	_, err := s.pool.Exec(ctx, upsertUserSQL, user.UserId, user.Username, user.IsActive, user.TeamName, tenant.Organization(ctx))
 	if err != nil {
 		return fmt.Errorf("upsert user: %w", err)
 	}
@@ -36,9 +46,9 @@ func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, err
 	const q = `
 	SELECT user_id, username, is_active, team_name
 	FROM users
-	WHERE user_id = $1
+	WHERE user_id = $1 AND organization_id = $2
 	`
-	rows, err := s.pool.Query(ctx, q, userID)
+	rows, err := s.pool.Query(ctx, q, userID, tenant.Organization(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query GetUser: %w", err)
 	}
@@ -77,10 +87,10 @@ func (s *Storage) GetAllUsersInTeam(ctx context.Context, teamId string) ([]*mode
 	const q = `
 SELECT user_id, username, is_active, team_name
 FROM users
-WHERE team_name = $1
+WHERE team_name = $1 AND organization_id = $2
 ORDER BY username
 `
-	rows, err := s.pool.Query(ctx, q, teamId)
+	rows, err := s.pool.Query(ctx, q, teamId, tenant.Organization(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query getAllUsersInTeam: %w", err)
 	}
@@ -122,12 +132,7 @@ func (s *Storage) SaveTeam(ctx context.Context, team *models.Team) error {
 	if team == nil {
 		return fmt.Errorf("team is nil")
 	}
-	const upsertTeam = `
-		INSERT INTO teams (team_name)
-		VALUES ($1)
-		ON CONFLICT (team_name) DO NOTHING
-	`
-	tag, err := s.pool.Exec(ctx, upsertTeam, team.TeamName)
+	tag, err := s.pool.Exec(ctx, insertTeamSQL, team.TeamName, tenant.Organization(ctx))
 	if err != nil {
 		return fmt.Errorf("upsert team: %w", err)
 	}
@@ -138,11 +143,19 @@ func (s *Storage) SaveTeam(ctx context.Context, team *models.Team) error {
 }
 
 // CreateTeamWithMembers сохраняет команду и всех участников в одной транзакции.
-func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team, users []models.User) (err error) {
+// Это частный случай CreateTeamsWithMembers с одной командой.
+func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team, users []models.User) error {
 	if team == nil {
 		return fmt.Errorf("team is nil")
 	}
+	return s.CreateTeamsWithMembers(ctx, []string{team.TeamName}, users)
+}
 
+// CreateTeamsWithMembers создаёт новые команды и выполняет upsert пользователей в одной транзакции.
+// Если хотя бы одна из команд уже существует, ничего не сохраняется и возвращается TEAM_EXISTS.
+// Импорт создаёт несколько команд атомарно, а сервис не держит транзакцию между вызовами
+// репозитория, поэтому пакетный вариант живёт здесь, а CreateTeamWithMembers делегирует ему.
+func (s *Storage) CreateTeamsWithMembers(ctx context.Context, teamNames []string, users []models.User) (err error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -157,31 +170,20 @@ func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team,
 		}
 	}()
 
-	const insertTeam = `
-		INSERT INTO teams (team_name)
-		VALUES ($1)
-		ON CONFLICT (team_name) DO NOTHING
-	`
-	tag, err := tx.Exec(ctx, insertTeam, team.TeamName)
-	if err != nil {
-		return fmt.Errorf("insert team: %w", err)
-	}
-	if tag.RowsAffected() == 0 {
-		return domain.NewTeamExistsError(team.TeamName)
+	organizationID := tenant.Organization(ctx)
+	for _, teamName := range teamNames {
+		tag, err := tx.Exec(ctx, insertTeamSQL, teamName, organizationID)
+		if err != nil {
+			return fmt.Errorf("insert team: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.NewTeamExistsError(teamName)
+		}
 	}
 
-	const upsertUser = `
-	INSERT INTO users (user_id, username, is_active, team_name)
-	VALUES ($1, $2, $3, NULLIF($4, ''))
-	ON CONFLICT (user_id) DO UPDATE
-	SET username = EXCLUDED.username,
-		is_active = EXCLUDED.is_active,
-		team_name = EXCLUDED.team_name
-	`
 	for _, user := range users {
-		userCopy := user
-		if _, err := tx.Exec(ctx, upsertUser, userCopy.UserId, userCopy.Username, userCopy.IsActive, userCopy.TeamName); err != nil {
-			return fmt.Errorf("upsert user %s: %w", userCopy.UserId, err)
+		if _, err := tx.Exec(ctx, upsertUserSQL, user.UserId, user.Username, user.IsActive, user.TeamName, organizationID); err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.UserId, err)
 		}
 	}
 
@@ -195,8 +197,8 @@ func (s *Storage) CreateTeamWithMembers(ctx context.Context, team *models.Team,
 // GetTeam возвращает команду вместе с участниками.
 func (s *Storage) GetTeam(ctx context.Context, teamID string) (*models.Team, error) {
 	// РЎРЅР°С‡Р°Р»Р° РїСЂРѕРІРµСЂРёРј, С‡С‚Рѕ РєРѕРјР°РЅРґР° СЃСѓС‰РµСЃС‚РІСѓРµС‚
-	const qTeam = `SELECT team_name FROM teams WHERE team_name = $1`
-	rows, err := s.pool.Query(ctx, qTeam, teamID)
+	const qTeam = `SELECT team_name FROM teams WHERE team_name = $1 AND organization_id = $2`
+	rows, err := s.pool.Query(ctx, qTeam, teamID, tenant.Organization(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("query GetTeam: %w", err)
 	}
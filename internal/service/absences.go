@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// Значения по умолчанию для планировщика отсутствий.
+const (
+	defaultAbsenceInterval = time.Minute
+	// absenceLease — имя аренды, под которой передаются ревью отсутствующих.
+	absenceLease = "absences"
+)
+
+// AbsenceRepository хранит периоды отсутствия пользователей.
+type AbsenceRepository interface {
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	// CreateAbsence сохраняет период и заполняет AbsenceId.
+	CreateAbsence(ctx context.Context, absence *models.Absence) error
+	GetAbsence(ctx context.Context, absenceID int64) (*models.Absence, error)
+	// ListAbsences возвращает периоды пользователя в порядке начала.
+	ListAbsences(ctx context.Context, userID string) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, absenceID int64) error
+	// FindAbsentUsers возвращает пользователей, отсутствующих в момент at.
+	FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error)
+	// FindAbsencesToReassign возвращает идущие в момент at периоды, ревью по которым ещё не переданы.
+	FindAbsencesToReassign(ctx context.Context, at time.Time) ([]models.Absence, error)
+	MarkAbsenceReassigned(ctx context.Context, absenceID int64, at time.Time) error
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+}
+
+// ReviewReleaser передаёт открытые ревью пользователя коллегам; реализуется PullRequestManager.
+type ReviewReleaser interface {
+	ReleaseReviews(ctx context.Context, userID string) ([]models.TeamPRReassignment, error)
+}
+
+// AbsenceConfig настраивает планировщик отсутствий; нулевые значения заменяются умолчаниями.
+type AbsenceConfig struct {
+	// Interval — период проверки начавшихся отсутствий.
+	Interval time.Duration
+	// LeaseTTL — срок аренды лидера; по умолчанию два интервала.
+	LeaseTTL time.Duration
+	// Holder идентифицирует инстанс в аренде; по умолчанию имя хоста и PID.
+	Holder string
+}
+
+// withDefaults подставляет значения по умолчанию.
+func (c AbsenceConfig) withDefaults() AbsenceConfig {
+	if c.Interval <= 0 {
+		c.Interval = defaultAbsenceInterval
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = 2 * c.Interval
+	}
+	if c.Holder == "" {
+		c.Holder = defaultLeaseHolder()
+	}
+	return c
+}
+
+// AbsenceManager регистрирует отсутствия и в момент их начала передаёт открытые ревью коллегам.
+// Флаг is_active при этом не меняется: после окончания периода пользователь снова получает ревью.
+type AbsenceManager struct {
+	repo     AbsenceRepository
+	releaser ReviewReleaser
+	cfg      AbsenceConfig
+	now      func() time.Time
+
+	organizations OrganizationLister
+}
+
+// NewAbsenceManager создаёт менеджер отсутствий.
+func NewAbsenceManager(repo AbsenceRepository, releaser ReviewReleaser, cfg AbsenceConfig) *AbsenceManager {
+	return &AbsenceManager{
+		repo:     repo,
+		releaser: releaser,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+	}
+}
+
+// SetOrganizations подключает список организаций: планировщик обходит их все.
+// Без него проверяется только организация default.
+func (am *AbsenceManager) SetOrganizations(lister OrganizationLister) {
+	am.organizations = lister
+}
+
+// AddAbsence регистрирует период отсутствия. Если период уже идёт, ревью передаются сразу;
+// при неудаче их передаст планировщик на следующем проходе.
+func (am *AbsenceManager) AddAbsence(ctx context.Context, req models.PostUsersAddAbsenceJSONBody) (_ *models.Absence, err error) {
+	ctx, span := tracer.Start(ctx, "AbsenceManager.AddAbsence")
+	defer func() { endSpan(span, err) }()
+
+	now := am.now()
+	switch {
+	case req.StartsAt.IsZero():
+		return nil, domain.NewInvalidParamError("starts_at", "is required")
+	case !req.EndsAt.After(req.StartsAt):
+		return nil, domain.NewInvalidParamError("ends_at", "must be after starts_at")
+	case !req.EndsAt.After(now):
+		return nil, domain.NewInvalidParamError("ends_at", "must be in the future")
+	}
+	if err := am.ensureUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	absence := &models.Absence{
+		UserId:    req.UserId,
+		StartsAt:  req.StartsAt.UTC(),
+		EndsAt:    req.EndsAt.UTC(),
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedAt: now.UTC(),
+	}
+	if err := am.repo.CreateAbsence(ctx, absence); err != nil {
+		return nil, fmt.Errorf("failed to create absence: %w", err)
+	}
+
+	if absence.Covers(now) {
+		if err := am.reassign(ctx, absence); err != nil {
+			slog.WarnContext(ctx, "absent reviewer's reviews not reassigned yet",
+				"absence_id", absence.AbsenceId, "user_id", absence.UserId, "err", err.Error())
+		}
+	}
+	return absence, nil
+}
+
+// Absences возвращает периоды отсутствия пользователя в порядке начала.
+func (am *AbsenceManager) Absences(ctx context.Context, userID string) (_ []models.Absence, err error) {
+	ctx, span := tracer.Start(ctx, "AbsenceManager.Absences")
+	defer func() { endSpan(span, err) }()
+
+	if err := am.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	absences, err := am.repo.ListAbsences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list absences: %w", err)
+	}
+	return absences, nil
+}
+
+// CancelAbsence удаляет период отсутствия и возвращает его; переданные ревью остаются у новых ревьюеров.
+func (am *AbsenceManager) CancelAbsence(ctx context.Context, absenceID int64) (_ *models.Absence, err error) {
+	ctx, span := tracer.Start(ctx, "AbsenceManager.CancelAbsence")
+	defer func() { endSpan(span, err) }()
+
+	absence, err := am.repo.GetAbsence(ctx, absenceID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("absence")
+		}
+		return nil, fmt.Errorf("failed to get absence: %w", err)
+	}
+	if err := am.repo.DeleteAbsence(ctx, absenceID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("absence")
+		}
+		return nil, fmt.Errorf("failed to delete absence: %w", err)
+	}
+	return absence, nil
+}
+
+// Run передаёт ревью начавшихся отсутствий каждые Interval, пока не отменён ctx.
+// Проверку выполняет только инстанс, удерживающий аренду в базе данных.
+func (am *AbsenceManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(am.cfg.Interval)
+	defer ticker.Stop()
+
+	slog.Info("absence scheduler started", "interval", am.cfg.Interval, "holder", am.cfg.Holder)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			am.tick(ctx)
+		}
+	}
+}
+
+// tick продлевает аренду и, если инстанс — лидер, обрабатывает начавшиеся отсутствия во всех организациях.
+func (am *AbsenceManager) tick(ctx context.Context) {
+	leader, err := am.repo.AcquireLease(ctx, absenceLease, am.cfg.Holder, am.cfg.LeaseTTL)
+	if err != nil {
+		slog.ErrorContext(ctx, "absence lease failed", "err", err.Error())
+		return
+	}
+	if !leader {
+		slog.DebugContext(ctx, "absence scheduler is not the leader", "holder", am.cfg.Holder)
+		return
+	}
+	err = forEachOrganization(ctx, am.organizations, func(ctx context.Context) error {
+		_, err := am.ReassignStarted(ctx)
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "absence reassignment failed", "err", err.Error())
+	}
+}
+
+// ReassignStarted передаёт ревью всех идущих отсутствий, которые ещё не обработаны, и возвращает их число.
+// Отсутствие, для которого не нашлось замены, журналируется и повторяется на следующем проходе.
+func (am *AbsenceManager) ReassignStarted(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "AbsenceManager.ReassignStarted")
+	defer func() { endSpan(span, err) }()
+
+	absences, err := am.repo.FindAbsencesToReassign(ctx, am.now())
+	if err != nil {
+		return 0, fmt.Errorf("find started absences: %w", err)
+	}
+
+	done := 0
+	for i := range absences {
+		absence := &absences[i]
+		if err := am.reassign(ctx, absence); err != nil {
+			if errors.Is(err, domain.ErrNoCandidate) || errors.Is(err, domain.ErrNotFound) {
+				slog.WarnContext(ctx, "absent reviewer's reviews not reassigned",
+					"absence_id", absence.AbsenceId, "user_id", absence.UserId, "err", err.Error())
+				continue
+			}
+			return done, fmt.Errorf("reassign reviews of %s: %w", absence.UserId, err)
+		}
+		done++
+	}
+	return done, nil
+}
+
+// reassign передаёт открытые ревью отсутствующего и отмечает период обработанным.
+func (am *AbsenceManager) reassign(ctx context.Context, absence *models.Absence) error {
+	reassignments, err := am.releaser.ReleaseReviews(ctx, absence.UserId)
+	if err != nil {
+		return err
+	}
+	at := am.now().UTC()
+	if err := am.repo.MarkAbsenceReassigned(ctx, absence.AbsenceId, at); err != nil {
+		return fmt.Errorf("mark absence reassigned: %w", err)
+	}
+	absence.ReassignedAt = &at
+	slog.InfoContext(ctx, "absent reviewer's reviews reassigned",
+		"absence_id", absence.AbsenceId, "user_id", absence.UserId, "pull_requests", len(reassignments))
+	return nil
+}
+
+// ensureUser возвращает NOT_FOUND, если пользователя нет.
+func (am *AbsenceManager) ensureUser(ctx context.Context, userID string) error {
+	if _, err := am.repo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("user")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
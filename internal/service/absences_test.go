@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type mockAbsenceRepository struct {
+	users    map[string]bool
+	absences map[int64]*models.Absence
+	nextID   int64
+	leader   bool
+}
+
+func newMockAbsenceRepository(users ...string) *mockAbsenceRepository {
+	m := &mockAbsenceRepository{users: map[string]bool{}, absences: map[int64]*models.Absence{}}
+	for _, id := range users {
+		m.users[id] = true
+	}
+	return m
+}
+
+func (m *mockAbsenceRepository) GetUser(_ context.Context, userID string) (*models.User, error) {
+	if !m.users[userID] {
+		return nil, domain.NewNotFoundError("user " + userID)
+	}
+	return &models.User{UserId: userID}, nil
+}
+
+func (m *mockAbsenceRepository) CreateAbsence(_ context.Context, absence *models.Absence) error {
+	m.nextID++
+	absence.AbsenceId = m.nextID
+	stored := *absence
+	m.absences[absence.AbsenceId] = &stored
+	return nil
+}
+
+func (m *mockAbsenceRepository) GetAbsence(_ context.Context, id int64) (*models.Absence, error) {
+	a, ok := m.absences[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("absence")
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *mockAbsenceRepository) ListAbsences(_ context.Context, userID string) ([]models.Absence, error) {
+	var result []models.Absence
+	for _, a := range m.absences {
+		if a.UserId == userID {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAbsenceRepository) DeleteAbsence(_ context.Context, id int64) error {
+	if _, ok := m.absences[id]; !ok {
+		return domain.NewNotFoundError("absence")
+	}
+	delete(m.absences, id)
+	return nil
+}
+
+func (m *mockAbsenceRepository) FindAbsentUsers(_ context.Context, at time.Time) ([]string, error) {
+	var result []string
+	for _, a := range m.absences {
+		if a.Covers(at) {
+			result = append(result, a.UserId)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAbsenceRepository) FindAbsencesToReassign(_ context.Context, at time.Time) ([]models.Absence, error) {
+	var result []models.Absence
+	for _, a := range m.absences {
+		if a.ReassignedAt == nil && a.Covers(at) {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAbsenceRepository) MarkAbsenceReassigned(_ context.Context, id int64, at time.Time) error {
+	a, ok := m.absences[id]
+	if !ok {
+		return domain.NewNotFoundError("absence")
+	}
+	a.ReassignedAt = &at
+	return nil
+}
+
+func (m *mockAbsenceRepository) AcquireLease(context.Context, string, string, time.Duration) (bool, error) {
+	return m.leader, nil
+}
+
+// fakeReleaser запоминает пользователей, чьи ревью передавались, или возвращает ошибку из errs.
+type fakeReleaser struct {
+	errs     map[string]error
+	released []string
+}
+
+func (f *fakeReleaser) ReleaseReviews(_ context.Context, userID string) ([]models.TeamPRReassignment, error) {
+	if err := f.errs[userID]; err != nil {
+		return nil, err
+	}
+	f.released = append(f.released, userID)
+	return nil, nil
+}
+
+func newTestAbsenceManager(repo AbsenceRepository, releaser ReviewReleaser, now time.Time) *AbsenceManager {
+	am := NewAbsenceManager(repo, releaser, AbsenceConfig{})
+	am.now = func() time.Time { return now }
+	return am
+}
+
+func TestAddAbsenceValidates(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	am := newTestAbsenceManager(newMockAbsenceRepository("u1"), &fakeReleaser{}, now)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		req  models.PostUsersAddAbsenceJSONBody
+		want error
+	}{
+		{"missing start", models.PostUsersAddAbsenceJSONBody{UserId: "u1", EndsAt: now.Add(time.Hour)}, domain.ErrInvalidParam},
+		{"end before start", models.PostUsersAddAbsenceJSONBody{UserId: "u1", StartsAt: now, EndsAt: now}, domain.ErrInvalidParam},
+		{"already over", models.PostUsersAddAbsenceJSONBody{UserId: "u1", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}, domain.ErrInvalidParam},
+		{"unknown user", models.PostUsersAddAbsenceJSONBody{UserId: "ghost", StartsAt: now, EndsAt: now.Add(time.Hour)}, domain.ErrNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := am.AddAbsence(ctx, tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestAddAbsenceReassignsWhenAlreadyStarted(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := newMockAbsenceRepository("u1", "u2")
+	releaser := &fakeReleaser{}
+	am := newTestAbsenceManager(repo, releaser, now)
+	ctx := context.Background()
+
+	current, err := am.AddAbsence(ctx, models.PostUsersAddAbsenceJSONBody{
+		UserId: "u1", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(48 * time.Hour), Reason: " sick ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.ReassignedAt == nil || current.Reason != "sick" {
+		t.Fatalf("expected a reassigned absence with trimmed reason, got %+v", current)
+	}
+
+	future, err := am.AddAbsence(ctx, models.PostUsersAddAbsenceJSONBody{
+		UserId: "u2", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if future.ReassignedAt != nil {
+		t.Fatalf("future absence must wait for the scheduler, got %+v", future)
+	}
+	if len(releaser.released) != 1 || releaser.released[0] != "u1" {
+		t.Fatalf("expected only u1's reviews to be released, got %v", releaser.released)
+	}
+}
+
+func TestReassignStartedRetriesWithoutCandidate(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := newMockAbsenceRepository("u1", "u2")
+	for _, userID := range []string{"u1", "u2"} {
+		_ = repo.CreateAbsence(context.Background(), &models.Absence{UserId: userID, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+	}
+	releaser := &fakeReleaser{errs: map[string]error{"u1": domain.NewNoCandidateError("pr-1")}}
+	am := newTestAbsenceManager(repo, releaser, now)
+
+	done, err := am.ReassignStarted(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done != 1 {
+		t.Fatalf("expected one processed absence, got %d", done)
+	}
+
+	pending, _ := repo.FindAbsencesToReassign(context.Background(), now)
+	if len(pending) != 1 || pending[0].UserId != "u1" {
+		t.Fatalf("u1's absence must stay pending for the next pass, got %+v", pending)
+	}
+
+	releaser.errs["u1"] = errors.New("db down")
+	if _, err := am.ReassignStarted(context.Background()); err == nil {
+		t.Fatal("expected unexpected errors to abort the pass")
+	}
+}
+
+func TestAbsenceTickRunsOnlyOnLeader(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := newMockAbsenceRepository("u1")
+	_ = repo.CreateAbsence(context.Background(), &models.Absence{UserId: "u1", StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+	releaser := &fakeReleaser{}
+	am := newTestAbsenceManager(repo, releaser, now)
+
+	am.tick(context.Background())
+	if len(releaser.released) != 0 {
+		t.Fatalf("follower must not reassign reviews, got %v", releaser.released)
+	}
+
+	repo.leader = true
+	am.tick(context.Background())
+	if len(releaser.released) != 1 {
+		t.Fatalf("leader must reassign reviews once, got %v", releaser.released)
+	}
+}
+
+func TestCancelAbsence(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := newMockAbsenceRepository("u1")
+	am := newTestAbsenceManager(repo, &fakeReleaser{}, now)
+	ctx := context.Background()
+
+	absence, err := am.AddAbsence(ctx, models.PostUsersAddAbsenceJSONBody{UserId: "u1", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancelled, err := am.CancelAbsence(ctx, absence.AbsenceId)
+	if err != nil || cancelled.AbsenceId != absence.AbsenceId {
+		t.Fatalf("unexpected cancel result: %+v, %v", cancelled, err)
+	}
+	if _, err := am.CancelAbsence(ctx, absence.AbsenceId); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// AffinityRuleLookup возвращает правила подбора ревьюеров команды.
+type AffinityRuleLookup interface {
+	// FindAffinityRules возвращает правила команды в порядке создания.
+	FindAffinityRules(ctx context.Context, teamName string) ([]models.AffinityRule, error)
+}
+
+// AffinityRuleRepository хранит правила подбора ревьюеров.
+type AffinityRuleRepository interface {
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	// CreateAffinityRule сохраняет правило и заполняет RuleId.
+	CreateAffinityRule(ctx context.Context, rule *models.AffinityRule) error
+	DeleteAffinityRule(ctx context.Context, ruleID int64) error
+	AffinityRuleLookup
+}
+
+// AffinityRuleManager управляет правилами подбора ревьюеров команд.
+type AffinityRuleManager struct {
+	repo AffinityRuleRepository
+	now  func() time.Time
+}
+
+// NewAffinityRuleManager создаёт менеджер правил подбора ревьюеров.
+func NewAffinityRuleManager(repo AffinityRuleRepository) *AffinityRuleManager {
+	return &AffinityRuleManager{repo: repo, now: time.Now}
+}
+
+// AddRule проверяет и сохраняет правило команды. Пользователи правила должны существовать,
+// но не обязаны состоять в команде: автор PR может прийти из другой команды через владельца репозитория.
+func (am *AffinityRuleManager) AddRule(ctx context.Context, req models.PostTeamAddRuleJSONBody) (_ *models.AffinityRule, err error) {
+	ctx, span := tracer.Start(ctx, "AffinityRuleManager.AddRule")
+	defer func() { endSpan(span, err) }()
+
+	rule := &models.AffinityRule{
+		TeamName:    strings.TrimSpace(req.TeamName),
+		Kind:        req.Kind,
+		UserId:      strings.TrimSpace(req.UserId),
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   am.now().UTC(),
+	}
+	if !rule.Kind.Valid() {
+		return nil, domain.NewInvalidParamError("kind", "must be NEVER_PAIR, ALWAYS_INCLUDE or REQUIRE_PAIR")
+	}
+	for _, id := range req.WithUserIds {
+		id = strings.TrimSpace(id)
+		if id == "" || slices.Contains(rule.WithUserIds, id) {
+			continue
+		}
+		if id == rule.UserId {
+			return nil, domain.NewInvalidParamError("with_user_ids", "must not contain user_id")
+		}
+		rule.WithUserIds = append(rule.WithUserIds, id)
+	}
+	if len(rule.WithUserIds) == 0 {
+		return nil, domain.NewInvalidParamError("with_user_ids", "must not be empty")
+	}
+
+	if _, err := am.repo.GetTeam(ctx, rule.TeamName); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("team")
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	for _, id := range append([]string{rule.UserId}, rule.WithUserIds...) {
+		if _, err := am.repo.GetUser(ctx, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.NewNotFoundError(fmt.Sprintf("user %s", id))
+			}
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	if err := am.repo.CreateAffinityRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create affinity rule: %w", err)
+	}
+	return rule, nil
+}
+
+// Rules возвращает правила команды в порядке создания.
+func (am *AffinityRuleManager) Rules(ctx context.Context, teamName string) (_ []models.AffinityRule, err error) {
+	ctx, span := tracer.Start(ctx, "AffinityRuleManager.Rules")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := am.repo.GetTeam(ctx, teamName); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("team")
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	rules, err := am.repo.FindAffinityRules(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list affinity rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteRule удаляет правило; NOT_FOUND, если его нет.
+func (am *AffinityRuleManager) DeleteRule(ctx context.Context, ruleID int64) (err error) {
+	ctx, span := tracer.Start(ctx, "AffinityRuleManager.DeleteRule")
+	defer func() { endSpan(span, err) }()
+
+	if err := am.repo.DeleteAffinityRule(ctx, ruleID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete affinity rule: %w", err)
+	}
+	return nil
+}
+
+// findAffinityRules возвращает правила команды или nil, если источник правил не подключён.
+func findAffinityRules(ctx context.Context, lookup AffinityRuleLookup, teamName string) ([]models.AffinityRule, error) {
+	if lookup == nil {
+		return nil, nil
+	}
+	rules, err := lookup.FindAffinityRules(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("find affinity rules: %w", err)
+	}
+	return rules, nil
+}
+
+// affinityConstraints применяет правила команды к подбору ревьюеров одного PR.
+type affinityConstraints struct {
+	rules  []models.AffinityRule
+	author string
+	// chosen — ревьюеры PR, которые остаются на нём, и выбранные по ходу подбора.
+	chosen []string
+	// rejected — причины, по которым правила отвергли кандидатов, в порядке отказа.
+	rejected   []string
+	rejectedBy map[string]string
+	// unavailable объясняет, почему пользователь не попал в число кандидатов; задаёт вызывающий.
+	unavailable func(userID string) string
+}
+
+// newAffinityConstraints готовит проверку правил для PR автора author с ревьюерами reviewers.
+func newAffinityConstraints(rules []models.AffinityRule, author string, reviewers []string, unavailable func(string) string) *affinityConstraints {
+	if unavailable == nil {
+		unavailable = func(string) string { return "is not a candidate" }
+	}
+	return &affinityConstraints{
+		rules:       rules,
+		author:      author,
+		chosen:      slices.Clone(reviewers),
+		rejectedBy:  make(map[string]string),
+		unavailable: unavailable,
+	}
+}
+
+// reject запоминает первую причину отказа кандидату.
+func (c *affinityConstraints) reject(userID, reason string) {
+	if _, seen := c.rejectedBy[userID]; seen {
+		return
+	}
+	c.rejectedBy[userID] = reason
+	c.rejected = append(c.rejected, userID+": "+reason)
+}
+
+// neverPaired возвращает правило NEVER_PAIR, запрещающее candidate ревьюить PR автора.
+func (c *affinityConstraints) neverPaired(candidate string) (models.AffinityRule, bool) {
+	for _, r := range c.rules {
+		if r.Kind == models.AffinityRuleNEVERPAIR && r.Pairs(c.author, candidate) {
+			return r, true
+		}
+	}
+	return models.AffinityRule{}, false
+}
+
+// unmet возвращает правила ALWAYS_INCLUDE и REQUIRE_PAIR, которым пока не удовлетворяет chosen.
+func (c *affinityConstraints) unmet() []models.AffinityRule {
+	var result []models.AffinityRule
+	for _, r := range c.rules {
+		switch {
+		case r.Kind == models.AffinityRuleALWAYSINCLUDE && r.UserId == c.author,
+			r.Kind == models.AffinityRuleREQUIREPAIR && slices.Contains(c.chosen, r.UserId):
+			if !c.includesAny(r.WithUserIds) {
+				result = append(result, r)
+			}
+		}
+	}
+	return result
+}
+
+// partnerRule возвращает неудовлетворённое правило REQUIRE_PAIR кандидата.
+func (c *affinityConstraints) partnerRule(candidate string) (models.AffinityRule, bool) {
+	for _, r := range c.rules {
+		if r.Kind == models.AffinityRuleREQUIREPAIR && r.UserId == candidate && !c.includesAny(r.WithUserIds) {
+			return r, true
+		}
+	}
+	return models.AffinityRule{}, false
+}
+
+func (c *affinityConstraints) includesAny(userIDs []string) bool {
+	for _, id := range userIDs {
+		if slices.Contains(c.chosen, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// pick выбирает из пула до count ревьюеров вне exclude с соблюдением правил. Сначала закрываются
+// правила ALWAYS_INCLUDE и REQUIRE_PAIR; кандидат с REQUIRE_PAIR берётся, только если для его пары
+// останется место и свободный кандидат. Выбранные добавляются в chosen и в exclude.
+func (c *affinityConstraints) pick(pool *reviewerPool, exclude map[string]struct{}, count, weight int) []models.ReviewerLoad {
+	picked := make([]models.ReviewerLoad, 0, count)
+	for len(picked) < count {
+		slots := count - len(picked)
+		unmet := c.unmet()
+		allow := func(candidate ReviewCandidate) bool {
+			id := candidate.UserId
+			if rule, ok := c.neverPaired(id); ok {
+				c.reject(id, rule.String())
+				return false
+			}
+			if len(unmet) > 0 && !slices.Contains(unmet[0].WithUserIds, id) {
+				return false
+			}
+			if rule, ok := c.partnerRule(id); ok {
+				partner := func(p ReviewCandidate) bool {
+					_, forbidden := c.neverPaired(p.UserId)
+					return p.UserId != id && slices.Contains(rule.WithUserIds, p.UserId) && !forbidden
+				}
+				if slots == 1 || !pool.available(exclude, partner) {
+					c.reject(id, rule.String()+", and no such co-reviewer is available")
+					return false
+				}
+			}
+			return true
+		}
+
+		load, ok := pool.takeIf(exclude, weight, allow)
+		if !ok {
+			break
+		}
+		picked = append(picked, load)
+		c.chosen = append(c.chosen, load.UserId)
+		exclude[load.UserId] = struct{}{}
+	}
+	return picked
+}
+
+// blockedByCapacity сообщает, что правило можно было бы выполнить, если бы у кого-то из его кандидатов был запас лимита.
+func (c *affinityConstraints) blockedByCapacity(pool *reviewerPool, rule models.AffinityRule) bool {
+	if pool.urgent {
+		return false
+	}
+	for _, candidate := range pool.candidates {
+		if candidate.AtCapacity() && slices.Contains(rule.WithUserIds, candidate.UserId) {
+			if _, rejected := c.rejectedBy[candidate.UserId]; !rejected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// explain объясняет, почему правило rule не выполнено: по строке на правило и на каждого его кандидата.
+func (c *affinityConstraints) explain(pool *reviewerPool, rule models.AffinityRule) []string {
+	reasons := []string{rule.String()}
+	for _, id := range rule.WithUserIds {
+		reasons = append(reasons, id+": "+c.whyNot(pool, id))
+	}
+	return reasons
+}
+
+// whyNot объясняет, почему пользователь не назначен ревьюером.
+func (c *affinityConstraints) whyNot(pool *reviewerPool, userID string) string {
+	if reason, ok := c.rejectedBy[userID]; ok {
+		return reason
+	}
+	if userID == c.author {
+		return "is the author"
+	}
+	for _, candidate := range pool.candidates {
+		if candidate.UserId == userID && candidate.AtCapacity() {
+			return "is at review capacity"
+		}
+	}
+	return c.unavailable(userID)
+}
+
+// failure возвращает ошибку с объяснениями, если правила не дали выполнить назначение,
+// или nil, если правила здесь ни при чём.
+func (c *affinityConstraints) failure(pool *reviewerPool, prID string) error {
+	if unmet := c.unmet(); len(unmet) > 0 {
+		return domain.NewRejectedCandidatesError(prID, c.explain(pool, unmet[0]))
+	}
+	if len(c.rejected) > 0 {
+		return domain.NewRejectedCandidatesError(prID, c.rejected)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// affinityLookupFunc адаптирует функцию к AffinityRuleLookup.
+type affinityLookupFunc func(ctx context.Context, teamName string) ([]models.AffinityRule, error)
+
+func (f affinityLookupFunc) FindAffinityRules(ctx context.Context, teamName string) ([]models.AffinityRule, error) {
+	return f(ctx, teamName)
+}
+
+// staticRules возвращает lookup, отдающий правила только команде alpha.
+func staticRules(rules ...models.AffinityRule) AffinityRuleLookup {
+	return affinityLookupFunc(func(_ context.Context, teamName string) ([]models.AffinityRule, error) {
+		if teamName != "alpha" {
+			return nil, nil
+		}
+		return rules, nil
+	})
+}
+
+// newAffinityUserManager заводит команду alpha: у lead больше всех ревью, u1 свободнее остальных.
+func newAffinityUserManager(rules ...models.AffinityRule) *UserManager {
+	manager := NewUserManager(nil)
+	for _, id := range []string{"author", "lead", "u1", "u2", "u3"} {
+		defaultCache(manager)[id] = &models.User{UserId: id, TeamName: "alpha", IsActive: true}
+	}
+	manager.SetReviewLoad(staticLoad(map[string]models.ReviewerLoad{
+		"lead": {OpenReviews: 4},
+		"u2":   {OpenReviews: 1},
+		"u3":   {OpenReviews: 2},
+	}), 0)
+	manager.SetAffinityRules(staticRules(rules...))
+	return manager
+}
+
+func authorDemand(count int, reviewers ...string) ReviewDemand {
+	return ReviewDemand{PullRequestId: "pr-1", Author: "author", Reviewers: reviewers, Count: count, Weight: 1}
+}
+
+func TestUserManager_AssignRewiersNeverPair(t *testing.T) {
+	manager := newAffinityUserManager(models.AffinityRule{
+		RuleId: 1, Kind: models.AffinityRuleNEVERPAIR, UserId: "u1", WithUserIds: []string{"author"},
+	})
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"u2", "u3"}) {
+		t.Fatalf("u1 must never review the author's PRs, got %v", ids)
+	}
+}
+
+func TestUserManager_AssignRewiersAlwaysInclude(t *testing.T) {
+	manager := newAffinityUserManager(models.AffinityRule{
+		RuleId: 1, Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author", WithUserIds: []string{"lead"},
+	})
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"lead", "u1"}) {
+		t.Fatalf("expected the lead despite the load, got %v", ids)
+	}
+
+	// Правило уже выполнено оставшимся ревьюером: замена выбирается как обычно.
+	repl, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"author", "lead", "u2"}, authorDemand(1, "lead"))
+	if err != nil || replacedBy(repl) != "u1" {
+		t.Fatalf("expected u1 replacement, got %s (err=%v)", replacedBy(repl), err)
+	}
+}
+
+func TestUserManager_AssignRewiersRequirePair(t *testing.T) {
+	manager := newAffinityUserManager(models.AffinityRule{
+		RuleId: 1, Kind: models.AffinityRuleREQUIREPAIR, UserId: "u1", WithUserIds: []string{"u3"},
+	})
+
+	// Одного ревьюера u1 взять нельзя: для его пары нет места.
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"u2"}) {
+		t.Fatalf("expected u2 alone, got %v", ids)
+	}
+
+	selection, err = manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"u1", "u3"}) {
+		t.Fatalf("u1 must come with u3, got %v", ids)
+	}
+
+	// Замена к оставшемуся u3 может быть u1: пара уже на PR.
+	repl, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"author", "u2", "u3"}, authorDemand(1, "u3"))
+	if err != nil || replacedBy(repl) != "u1" {
+		t.Fatalf("expected u1 replacement, got %s (err=%v)", replacedBy(repl), err)
+	}
+}
+
+func TestUserManager_AssignRewiersExplainsRejections(t *testing.T) {
+	manager := newAffinityUserManager(models.AffinityRule{
+		RuleId: 7, Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author", WithUserIds: []string{"lead"},
+	})
+	manager.SetAbsences(absenceLookupFunc(func(context.Context, time.Time) ([]string, error) {
+		return []string{"lead"}, nil
+	}))
+
+	_, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(2))
+	if !errors.Is(err, domain.ErrNoCandidate) {
+		t.Fatalf("expected no candidate error, got %v", err)
+	}
+	var rejected *domain.RejectedCandidatesError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("expected rejected candidates error, got %T", err)
+	}
+	want := []string{"rule 7: PRs of author need a reviewer from [lead]", "lead: is absent"}
+	if rejected.PullRequestID != "pr-1" || !reflect.DeepEqual(rejected.Reasons, want) {
+		t.Fatalf("expected %v for pr-1, got %+v", want, rejected)
+	}
+	if !strings.Contains(err.Error(), "lead: is absent") {
+		t.Fatalf("error text must carry the reasons, got %q", err.Error())
+	}
+}
+
+func TestUserManager_AssignRewiersQueuesWhenRuleBlockedByCapacity(t *testing.T) {
+	manager := newAffinityUserManager(models.AffinityRule{
+		RuleId: 1, Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author", WithUserIds: []string{"lead"},
+	})
+	manager.SetReviewLoad(staticLoad(map[string]models.ReviewerLoad{
+		"lead": {OpenReviews: 2, MaxOpenReviews: 2},
+	}), 0)
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selection.Reviewers) != 0 || selection.Pending != 1 {
+		t.Fatalf("expected the PR to wait for the lead, got %+v", selection)
+	}
+}
+
+// mockAffinityRuleRepository хранит правила в срезе; команды и пользователи задаются множествами.
+type mockAffinityRuleRepository struct {
+	teams []string
+	users []string
+	rules []models.AffinityRule
+}
+
+func (m *mockAffinityRuleRepository) GetTeam(_ context.Context, teamName string) (*models.Team, error) {
+	if !slices.Contains(m.teams, teamName) {
+		return nil, domain.NewNotFoundError("team")
+	}
+	return &models.Team{TeamName: teamName}, nil
+}
+
+func (m *mockAffinityRuleRepository) GetUser(_ context.Context, userID string) (*models.User, error) {
+	if !slices.Contains(m.users, userID) {
+		return nil, domain.NewNotFoundError("user")
+	}
+	return &models.User{UserId: userID}, nil
+}
+
+func (m *mockAffinityRuleRepository) CreateAffinityRule(_ context.Context, rule *models.AffinityRule) error {
+	rule.RuleId = int64(len(m.rules) + 1)
+	m.rules = append(m.rules, *rule)
+	return nil
+}
+
+func (m *mockAffinityRuleRepository) DeleteAffinityRule(_ context.Context, ruleID int64) error {
+	for i, r := range m.rules {
+		if r.RuleId == ruleID {
+			m.rules = slices.Delete(m.rules, i, i+1)
+			return nil
+		}
+	}
+	return domain.NewNotFoundError("affinity rule")
+}
+
+func (m *mockAffinityRuleRepository) FindAffinityRules(_ context.Context, teamName string) ([]models.AffinityRule, error) {
+	var result []models.AffinityRule
+	for _, r := range m.rules {
+		if r.TeamName == teamName {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func TestAffinityRuleManager_AddRule(t *testing.T) {
+	repo := &mockAffinityRuleRepository{teams: []string{"alpha"}, users: []string{"author", "lead", "u1"}}
+	manager := NewAffinityRuleManager(repo)
+	ctx := context.Background()
+
+	rule, err := manager.AddRule(ctx, models.PostTeamAddRuleJSONBody{
+		TeamName: "alpha", Kind: models.AffinityRuleALWAYSINCLUDE, UserId: " author ",
+		WithUserIds: []string{"lead", "", "lead", "u1"}, Description: "security",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.RuleId != 1 || rule.UserId != "author" || !reflect.DeepEqual(rule.WithUserIds, []string{"lead", "u1"}) || rule.CreatedAt.IsZero() {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+
+	for _, tc := range []struct {
+		name string
+		req  models.PostTeamAddRuleJSONBody
+		want error
+	}{
+		{"unknown kind", models.PostTeamAddRuleJSONBody{TeamName: "alpha", Kind: "SOMETIMES", UserId: "author", WithUserIds: []string{"lead"}}, domain.ErrInvalidParam},
+		{"empty with", models.PostTeamAddRuleJSONBody{TeamName: "alpha", Kind: models.AffinityRuleNEVERPAIR, UserId: "author", WithUserIds: []string{" "}}, domain.ErrInvalidParam},
+		{"self pair", models.PostTeamAddRuleJSONBody{TeamName: "alpha", Kind: models.AffinityRuleNEVERPAIR, UserId: "author", WithUserIds: []string{"author"}}, domain.ErrInvalidParam},
+		{"unknown team", models.PostTeamAddRuleJSONBody{TeamName: "beta", Kind: models.AffinityRuleNEVERPAIR, UserId: "author", WithUserIds: []string{"lead"}}, domain.ErrNotFound},
+		{"unknown user", models.PostTeamAddRuleJSONBody{TeamName: "alpha", Kind: models.AffinityRuleNEVERPAIR, UserId: "author", WithUserIds: []string{"ghost"}}, domain.ErrNotFound},
+	} {
+		if _, err := manager.AddRule(ctx, tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if len(repo.rules) != 1 {
+		t.Fatalf("invalid rules must not be stored, got %+v", repo.rules)
+	}
+
+	if err := manager.DeleteRule(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.DeleteRule(ctx, 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := manager.Rules(ctx, "beta"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected team not found, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// DecisionRecorder сохраняет решения о назначении ревьюеров.
+type DecisionRecorder interface {
+	// SaveAssignmentDecision сохраняет решение и заполняет его DecisionId.
+	SaveAssignmentDecision(ctx context.Context, decision *models.AssignmentDecision) error
+}
+
+// DecisionRepository хранит решения о назначении ревьюеров вместе с PR.
+type DecisionRepository interface {
+	GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error)
+	DecisionRecorder
+	// FindAssignmentDecisions возвращает решения по PR, старые первыми.
+	FindAssignmentDecisions(ctx context.Context, prID string) ([]models.AssignmentDecision, error)
+}
+
+// DecisionManager объясняет, почему PR достались его ревьюеры.
+type DecisionManager struct {
+	repo DecisionRepository
+}
+
+// NewDecisionManager создаёт менеджер решений о назначении.
+func NewDecisionManager(repo DecisionRepository) *DecisionManager {
+	return &DecisionManager{repo: repo}
+}
+
+// Explain возвращает все решения о назначении ревьюеров PR.
+func (dm *DecisionManager) Explain(ctx context.Context, prID string) (_ *models.PullRequestExplanation, err error) {
+	ctx, span := tracer.Start(ctx, "DecisionManager.Explain")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := dm.repo.GetPullRequest(ctx, prID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("pull request")
+		}
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	decisions, err := dm.repo.FindAssignmentDecisions(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("find assignment decisions: %w", err)
+	}
+	return &models.PullRequestExplanation{PullRequestId: prID, Decisions: decisions}, nil
+}
+
+// recordDecision сохраняет решение о назначении после того, как назначение сохранено.
+// Ошибка только журналируется: ревьюеры уже назначены, а объяснение для этого не нужно.
+func (prm *PullRequestManager) recordDecision(ctx context.Context, operation models.DecisionOperation, decision *models.AssignmentDecision) {
+	if prm.decisions == nil || decision == nil {
+		return
+	}
+	decision.Operation = operation
+	decision.DecidedAt = time.Now().UTC()
+	if err := prm.decisions.SaveAssignmentDecision(ctx, decision); err != nil {
+		slog.WarnContext(ctx, "assignment decision not saved",
+			"pull_request_id", decision.PullRequestId, "operation", operation, "err", err.Error())
+	}
+}
+
+// explainSelection описывает подбор ревьюеров из команды teamName: members — все её участники,
+// considered — кандидаты пула до подбора, excluded — кандидаты пула, которых нельзя было выбрать для этого PR,
+// pool и constraints — их состояние после того, как выбраны picked.
+func explainSelection(
+	prID, teamName string,
+	members []string,
+	considered []ReviewCandidate,
+	excluded map[string]struct{},
+	pool *reviewerPool,
+	constraints *affinityConstraints,
+	picked []models.ReviewerLoad,
+) *models.AssignmentDecision {
+	decision := &models.AssignmentDecision{
+		PullRequestId: prID,
+		Strategy:      models.AssignmentStrategyBALANCED,
+		TeamName:      teamName,
+		Selected:      make([]string, 0, len(picked)),
+		Candidates:    make([]models.DecisionCandidate, 0, len(members)),
+	}
+	if pool.urgent {
+		decision.Strategy = models.AssignmentStrategyURGENT
+	}
+	for _, rule := range constraints.rules {
+		decision.Rules = append(decision.Rules, rule.String())
+	}
+
+	byID := make(map[string]ReviewCandidate, len(considered))
+	for _, c := range considered {
+		byID[c.UserId] = c
+	}
+	winners := make([]ReviewCandidate, 0, len(picked))
+	for _, p := range picked {
+		winner := byID[p.UserId]
+		winners = append(winners, winner)
+		decision.Selected = append(decision.Selected, p.UserId)
+		decision.Candidates = append(decision.Candidates, models.DecisionCandidate{
+			UserId:  p.UserId,
+			Outcome: models.CandidateOutcomeSELECTED,
+			Load:    candidateLoad(winner),
+		})
+	}
+
+	others := slices.Sorted(slices.Values(members))
+	for _, id := range slices.Compact(others) {
+		if slices.Contains(decision.Selected, id) {
+			continue
+		}
+		candidate, inPool := byID[id]
+		_, skipped := excluded[id]
+		entry := models.DecisionCandidate{UserId: id, Outcome: models.CandidateOutcomeEXCLUDED}
+		switch reason, rejected := constraints.rejectedBy[id]; {
+		case skipped && id == constraints.author:
+			entry.Reason = "is the author"
+		case skipped:
+			entry.Reason = constraints.unavailable(id)
+		case !inPool:
+			entry.Reason = constraints.whyNot(pool, id)
+		case rejected:
+			entry.Reason, entry.Load = reason, candidateLoad(candidate)
+		case candidate.AtCapacity() && !pool.urgent:
+			entry.Reason, entry.Load = "is at review capacity", candidateLoad(candidate)
+		default:
+			entry.Outcome = models.CandidateOutcomeNOTSELECTED
+			entry.Reason, entry.Load = passedOver(pool, candidate, winners), candidateLoad(candidate)
+		}
+		decision.Candidates = append(decision.Candidates, entry)
+	}
+	return decision
+}
+
+// passedOver объясняет, почему подходящий кандидат уступил выбранным ревьюерам winners.
+func passedOver(pool *reviewerPool, candidate ReviewCandidate, winners []ReviewCandidate) string {
+	if candidate.AtCapacity() {
+		return "is at review capacity"
+	}
+	// Кандидат шёл в очереди раньше кого-то из выбранных — его обошли ради правила подбора.
+	if len(winners) == 0 || slices.ContainsFunc(winners, func(w ReviewCandidate) bool { return pool.less(candidate, w) }) {
+		return "gave way to a reviewer required by team rules"
+	}
+	last := winners[len(winners)-1]
+	switch {
+	case !pool.urgent && candidate.OffHours && !last.OffHours:
+		return "is outside working hours"
+	case candidate.WeightedLoad != last.WeightedLoad || candidate.OpenReviews != last.OpenReviews:
+		return "has a higher review load"
+	}
+	return "lost the tie-break by user_id"
+}
+
+func candidateLoad(c ReviewCandidate) *models.CandidateLoad {
+	return &models.CandidateLoad{
+		OpenReviews:    c.OpenReviews,
+		WeightedLoad:   c.WeightedLoad,
+		MaxOpenReviews: c.MaxOpenReviews,
+		OffHours:       c.OffHours,
+	}
+}
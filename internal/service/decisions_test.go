@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// candidateReasons собирает исход и причину по каждому кандидату решения.
+func candidateReasons(decision *models.AssignmentDecision) map[string]string {
+	reasons := make(map[string]string, len(decision.Candidates))
+	for _, c := range decision.Candidates {
+		reasons[c.UserId] = string(c.Outcome) + " " + c.Reason
+	}
+	return reasons
+}
+
+func TestUserManager_AssignRewiersExplainsDecision(t *testing.T) {
+	manager := newLoadedUserManager(0)
+	defaultCache(manager)["u5"] = &models.User{UserId: "u5", TeamName: "alpha"}
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &models.AssignmentDecision{
+		PullRequestId: "pr-1",
+		Strategy:      models.AssignmentStrategyBALANCED,
+		TeamName:      "alpha",
+		Selected:      []string{"u2"},
+		Candidates: []models.DecisionCandidate{
+			{UserId: "u2", Outcome: models.CandidateOutcomeSELECTED, Load: &models.CandidateLoad{OpenReviews: 1}},
+			{UserId: "author", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is the author"},
+			{UserId: "u1", Outcome: models.CandidateOutcomeNOTSELECTED, Reason: "has a higher review load", Load: &models.CandidateLoad{OpenReviews: 3}},
+			{UserId: "u3", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is at review capacity", Load: &models.CandidateLoad{OpenReviews: 1, MaxOpenReviews: 1}},
+			{UserId: "u4", Outcome: models.CandidateOutcomeNOTSELECTED, Reason: "lost the tie-break by user_id", Load: &models.CandidateLoad{OpenReviews: 1}},
+			{UserId: "u5", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is inactive"},
+		},
+	}
+	if !reflect.DeepEqual(selection.Decision, want) {
+		t.Fatalf("expected %+v, got %+v", want, selection.Decision)
+	}
+
+	// Срочному PR годятся и ревьюеры на лимите, но только если свободных нет.
+	urgent := authorDemand(1)
+	urgent.Urgent = true
+	selection, err = manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, urgent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if selection.Decision.Strategy != models.AssignmentStrategyURGENT {
+		t.Fatalf("expected urgent strategy, got %s", selection.Decision.Strategy)
+	}
+	if got := candidateReasons(selection.Decision)["u3"]; got != "NOT_SELECTED is at review capacity" {
+		t.Fatalf("unexpected reason for u3: %q", got)
+	}
+}
+
+func TestUserManager_AssignRewiersExplainsRules(t *testing.T) {
+	rule := models.AffinityRule{RuleId: 1, Kind: models.AffinityRuleALWAYSINCLUDE, UserId: "author", WithUserIds: []string{"lead"}}
+	manager := newAffinityUserManager(rule, models.AffinityRule{
+		RuleId: 2, Kind: models.AffinityRuleNEVERPAIR, UserId: "u2", WithUserIds: []string{"author"},
+	})
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, authorDemand(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selection.Decision.Rules) != 2 || selection.Decision.Rules[0] != rule.String() {
+		t.Fatalf("expected team rules in the decision, got %v", selection.Decision.Rules)
+	}
+	want := map[string]string{
+		"lead":   "SELECTED ",
+		"author": "EXCLUDED is the author",
+		"u1":     "NOT_SELECTED gave way to a reviewer required by team rules",
+		"u2":     "EXCLUDED rule 2: u2 and [author] never review each other's PRs",
+		"u3":     "NOT_SELECTED gave way to a reviewer required by team rules",
+	}
+	if got := candidateReasons(selection.Decision); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestUserManager_FindReplacementReviewerExplainsDecision(t *testing.T) {
+	manager := newLoadedUserManager(0)
+
+	selection, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"author", "u2"}, authorDemand(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(selection.Decision.Selected, []string{"u4"}) {
+		t.Fatalf("expected u4, got %v", selection.Decision.Selected)
+	}
+	if got := candidateReasons(selection.Decision)["u2"]; got != "EXCLUDED is already assigned or being replaced" {
+		t.Fatalf("unexpected reason for u2: %q", got)
+	}
+}
+
+// recordingDecisions запоминает сохранённые решения; err возвращается из SaveAssignmentDecision.
+type recordingDecisions struct {
+	saved []models.AssignmentDecision
+	err   error
+}
+
+func (r *recordingDecisions) SaveAssignmentDecision(_ context.Context, decision *models.AssignmentDecision) error {
+	if r.err != nil {
+		return r.err
+	}
+	decision.DecisionId = int64(len(r.saved) + 1)
+	r.saved = append(r.saved, *decision)
+	return nil
+}
+
+func TestPullRequestManager_RecordsDecisions(t *testing.T) {
+	ctx := context.Background()
+	stored := map[string]*models.PullRequest{}
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			stored[pr.PullRequestId] = pr
+			return nil
+		},
+		getPullRequestFn: func(_ context.Context, id string) (*models.PullRequest, error) {
+			pr, ok := stored[id]
+			if !ok {
+				return nil, domain.NewNotFoundError("pull request")
+			}
+			return pr, nil
+		},
+	}
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(_ string, _ []string, demand ReviewDemand) (*ReviewerSelection, error) {
+			selection := selectionOf("rev-1", "rev-2")
+			selection.Decision = &models.AssignmentDecision{PullRequestId: demand.PullRequestId, Selected: selection.ReviewerIDs()}
+			return selection, nil
+		},
+		findReplacementReviewerFn: func(string, []string) (string, error) { return "rev-3", nil },
+	}
+	decisions := &recordingDecisions{}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetDecisions(decisions)
+
+	if _, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", AuthorId: "author"}); err != nil {
+		t.Fatalf("CreatePullRequest returned error: %v", err)
+	}
+	if _, err := manager.Reassign(ctx, "rev-1", "pr-1"); err != nil {
+		t.Fatalf("Reassign returned error: %v", err)
+	}
+	if len(decisions.saved) != 2 {
+		t.Fatalf("expected two decisions, got %+v", decisions.saved)
+	}
+	created, reassigned := decisions.saved[0], decisions.saved[1]
+	if created.Operation != models.DecisionOperationCREATE || created.PullRequestId != "pr-1" || created.DecidedAt.IsZero() {
+		t.Fatalf("unexpected create decision: %+v", created)
+	}
+	if reassigned.Operation != models.DecisionOperationREASSIGN || !reflect.DeepEqual(reassigned.Replaced, []string{"rev-1"}) ||
+		!reflect.DeepEqual(reassigned.Selected, []string{"rev-3"}) {
+		t.Fatalf("unexpected reassign decision: %+v", reassigned)
+	}
+
+	// Ошибка сохранения решения не отменяет назначение.
+	decisions.err = errors.New("boom")
+	if _, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-2", AuthorId: "author"}); err != nil {
+		t.Fatalf("decision failure must not fail the pull request: %v", err)
+	}
+}
+
+func TestPullRequestManager_ReleaseReviewsRecordsDecisions(t *testing.T) {
+	repo := &mockPullRequestRepository{
+		findOpenPullRequestsByReviewerFn: func(context.Context, []string) ([]*models.PullRequest, error) {
+			return []*models.PullRequest{
+				{PullRequestId: "pr-1", AuthorId: "u4", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u1"}},
+			}, nil
+		},
+		applyBulkTeamReviewerSwapsFn: func(context.Context, []models.ReviewerSwap, []string) error { return nil },
+	}
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return "backend", nil },
+		getTeamFn: func(context.Context, string) (*models.Team, error) {
+			return &models.Team{TeamName: "backend", Members: []models.TeamMember{
+				{UserId: "u1", IsActive: true},
+				{UserId: "u2", IsActive: true},
+				{UserId: "u3", IsActive: true},
+				{UserId: "u4", IsActive: true},
+				{UserId: "u5"},
+			}}, nil
+		},
+		absentUsersFn: func(time.Time) (map[string]struct{}, error) {
+			return map[string]struct{}{"u1": {}, "u2": {}}, nil
+		},
+	}
+	decisions := &recordingDecisions{}
+	prm := &PullRequestManager{repo: repo, UserService: userSvc}
+	prm.SetDecisions(decisions)
+
+	if _, err := prm.ReleaseReviews(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decisions.saved) != 1 {
+		t.Fatalf("expected one decision, got %+v", decisions.saved)
+	}
+	decision := decisions.saved[0]
+	if decision.Operation != models.DecisionOperationABSENCE || !reflect.DeepEqual(decision.Replaced, []string{"u1"}) ||
+		!reflect.DeepEqual(decision.Selected, []string{"u3"}) {
+		t.Fatalf("unexpected decision: %+v", decision)
+	}
+	want := map[string]string{
+		"u1": "EXCLUDED is being replaced",
+		"u2": "EXCLUDED is absent",
+		"u3": "SELECTED ",
+		"u4": "EXCLUDED is the author",
+		"u5": "EXCLUDED is inactive",
+	}
+	if got := candidateReasons(&decision); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// mockDecisionRepository хранит PR и решения по ним.
+type mockDecisionRepository struct {
+	prs       map[string]bool
+	decisions map[string][]models.AssignmentDecision
+}
+
+func (m *mockDecisionRepository) GetPullRequest(_ context.Context, prID string) (*models.PullRequest, error) {
+	if !m.prs[prID] {
+		return nil, domain.ErrNotFound
+	}
+	return &models.PullRequest{PullRequestId: prID}, nil
+}
+
+func (m *mockDecisionRepository) SaveAssignmentDecision(_ context.Context, decision *models.AssignmentDecision) error {
+	m.decisions[decision.PullRequestId] = append(m.decisions[decision.PullRequestId], *decision)
+	return nil
+}
+
+func (m *mockDecisionRepository) FindAssignmentDecisions(_ context.Context, prID string) ([]models.AssignmentDecision, error) {
+	return append([]models.AssignmentDecision{}, m.decisions[prID]...), nil
+}
+
+func TestDecisionManager_Explain(t *testing.T) {
+	repo := &mockDecisionRepository{
+		prs:       map[string]bool{"pr-1": true, "pr-2": true},
+		decisions: map[string][]models.AssignmentDecision{"pr-1": {{DecisionId: 1, PullRequestId: "pr-1", Selected: []string{"u1"}}}},
+	}
+	manager := NewDecisionManager(repo)
+	ctx := context.Background()
+
+	got, err := manager.Explain(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.PullRequestId != "pr-1" || len(got.Decisions) != 1 || got.Decisions[0].DecisionId != 1 {
+		t.Fatalf("unexpected explanation: %+v", got)
+	}
+	if got, err := manager.Explain(ctx, "pr-2"); err != nil || len(got.Decisions) != 0 {
+		t.Fatalf("expected an empty explanation, got %+v (err=%v)", got, err)
+	}
+	if _, err := manager.Explain(ctx, "ghost"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
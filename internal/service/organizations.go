@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// organizationTokenBytes — длина токена организации до кодирования в hex.
+const organizationTokenBytes = 32
+
+// OrganizationLister перечисляет организации; нужен фоновым задачам, которые обходят их все.
+type OrganizationLister interface {
+	ListOrganizations(ctx context.Context) ([]models.Organization, error)
+}
+
+// OrganizationRepository хранит организации и хеши их токенов.
+type OrganizationRepository interface {
+	// CreateOrganization создаёт организацию с токеном и репозиторием default; ORG_EXISTS, если она уже есть.
+	CreateOrganization(ctx context.Context, org *models.Organization, tokenHash string) error
+	// FindOrganizationByToken возвращает организацию по хешу токена; ErrNotFound, если такого нет.
+	FindOrganizationByToken(ctx context.Context, tokenHash string) (string, error)
+	OrganizationLister
+}
+
+// OrganizationManager создаёт организации и определяет организацию по токену доступа.
+type OrganizationManager struct {
+	repo OrganizationRepository
+	now  func() time.Time
+}
+
+// NewOrganizationManager создаёт менеджер организаций.
+func NewOrganizationManager(repo OrganizationRepository) *OrganizationManager {
+	return &OrganizationManager{repo: repo, now: time.Now}
+}
+
+// CreateOrganization создаёт организацию и возвращает её вместе с токеном доступа.
+// Токен показывается один раз: хранится только его SHA-256.
+func (om *OrganizationManager) CreateOrganization(ctx context.Context, req models.PostOrganizationCreateJSONBody) (_ *models.Organization, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "OrganizationManager.CreateOrganization")
+	defer func() { endSpan(span, err) }()
+
+	id := strings.TrimSpace(req.OrganizationId)
+	if id == "" {
+		return nil, "", domain.NewInvalidParamError("organization_id", "is required")
+	}
+	if strings.ContainsAny(id, "# \t") {
+		return nil, "", domain.NewInvalidParamError("organization_id", "must not contain '#' or spaces")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = id
+	}
+
+	token, err := newOrganizationToken()
+	if err != nil {
+		return nil, "", err
+	}
+	org := &models.Organization{OrganizationId: id, Name: name, CreatedAt: om.now().UTC()}
+	if err := om.repo.CreateOrganization(ctx, org, hashToken(token)); err != nil {
+		if errors.Is(err, domain.ErrOrgExists) {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("failed to create organization: %w", err)
+	}
+	return org, token, nil
+}
+
+// Organizations возвращает все организации по идентификатору.
+func (om *OrganizationManager) Organizations(ctx context.Context) (_ []models.Organization, err error) {
+	ctx, span := tracer.Start(ctx, "OrganizationManager.Organizations")
+	defer func() { endSpan(span, err) }()
+
+	orgs, err := om.repo.ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// Authenticate возвращает организацию, которой выдан token; UNAUTHORIZED, если токен неизвестен.
+func (om *OrganizationManager) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", domain.NewUnauthorizedError("access the API without a token")
+	}
+	organizationID, err := om.repo.FindOrganizationByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", domain.NewUnauthorizedError("access the API with this token")
+		}
+		return "", fmt.Errorf("failed to find organization token: %w", err)
+	}
+	return organizationID, nil
+}
+
+// newOrganizationToken генерирует случайный токен в hex.
+func newOrganizationToken() (string, error) {
+	buf := make([]byte, organizationTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate organization token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken возвращает SHA-256 токена в hex — в таком виде токены хранятся в базе.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// forEachOrganization вызывает fn в контексте каждой организации; без lister — только для организации default.
+// Ошибка одной организации не прерывает обход: все ошибки возвращаются вместе.
+func forEachOrganization(ctx context.Context, lister OrganizationLister, fn func(ctx context.Context) error) error {
+	ids := []string{models.DefaultOrganization}
+	if lister != nil {
+		orgs, err := lister.ListOrganizations(ctx)
+		if err != nil {
+			return fmt.Errorf("list organizations: %w", err)
+		}
+		ids = ids[:0]
+		for _, org := range orgs {
+			ids = append(ids, org.OrganizationId)
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := fn(tenant.WithOrganization(ctx, id)); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+type mockOrganizationRepository struct {
+	orgs   []models.Organization
+	tokens map[string]string
+}
+
+func newMockOrganizationRepository() *mockOrganizationRepository {
+	return &mockOrganizationRepository{
+		orgs:   []models.Organization{{OrganizationId: models.DefaultOrganization, Name: "Default"}},
+		tokens: map[string]string{},
+	}
+}
+
+func (m *mockOrganizationRepository) CreateOrganization(_ context.Context, org *models.Organization, tokenHash string) error {
+	for _, existing := range m.orgs {
+		if existing.OrganizationId == org.OrganizationId {
+			return domain.NewOrgExistsError(org.OrganizationId)
+		}
+	}
+	m.orgs = append(m.orgs, *org)
+	m.tokens[tokenHash] = org.OrganizationId
+	return nil
+}
+
+func (m *mockOrganizationRepository) FindOrganizationByToken(_ context.Context, tokenHash string) (string, error) {
+	id, ok := m.tokens[tokenHash]
+	if !ok {
+		return "", domain.NewNotFoundError("organization token")
+	}
+	return id, nil
+}
+
+func (m *mockOrganizationRepository) ListOrganizations(context.Context) ([]models.Organization, error) {
+	return m.orgs, nil
+}
+
+func TestCreateOrganizationIssuesToken(t *testing.T) {
+	repo := newMockOrganizationRepository()
+	om := NewOrganizationManager(repo)
+	ctx := context.Background()
+
+	org, token, err := om.CreateOrganization(ctx, models.PostOrganizationCreateJSONBody{OrganizationId: " payments "})
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	if org.OrganizationId != "payments" || org.Name != "payments" {
+		t.Fatalf("unexpected organization %+v", org)
+	}
+	if token == "" {
+		t.Fatal("expected a token")
+	}
+	if _, stored := repo.tokens[token]; stored {
+		t.Fatal("token must be stored hashed")
+	}
+
+	got, err := om.Authenticate(ctx, token)
+	if err != nil || got != "payments" {
+		t.Fatalf("Authenticate = %q, %v; want payments", got, err)
+	}
+
+	_, _, err = om.CreateOrganization(ctx, models.PostOrganizationCreateJSONBody{OrganizationId: "payments"})
+	if !errors.Is(err, domain.ErrOrgExists) {
+		t.Fatalf("expected ORG_EXISTS, got %v", err)
+	}
+}
+
+func TestCreateOrganizationValidatesID(t *testing.T) {
+	om := NewOrganizationManager(newMockOrganizationRepository())
+	for _, id := range []string{"", "  ", "a b", "a#b"} {
+		_, _, err := om.CreateOrganization(context.Background(), models.PostOrganizationCreateJSONBody{OrganizationId: id})
+		if !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("id %q: expected INVALID_PARAM, got %v", id, err)
+		}
+	}
+}
+
+func TestAuthenticateRejectsUnknownToken(t *testing.T) {
+	om := NewOrganizationManager(newMockOrganizationRepository())
+	for _, token := range []string{"", "nope"} {
+		if _, err := om.Authenticate(context.Background(), token); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Fatalf("token %q: expected UNAUTHORIZED, got %v", token, err)
+		}
+	}
+}
+
+func TestForEachOrganization(t *testing.T) {
+	var visited []string
+	visit := func(ctx context.Context) error {
+		visited = append(visited, tenant.Organization(ctx))
+		if tenant.Organization(ctx) == "a" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	if err := forEachOrganization(context.Background(), nil, visit); err != nil {
+		t.Fatalf("forEachOrganization: %v", err)
+	}
+	if len(visited) != 1 || visited[0] != models.DefaultOrganization {
+		t.Fatalf("without lister only default is visited, got %v", visited)
+	}
+
+	repo := newMockOrganizationRepository()
+	repo.orgs = append(repo.orgs, models.Organization{OrganizationId: "a"}, models.Organization{OrganizationId: "b"})
+	visited = nil
+	err := forEachOrganization(context.Background(), repo, visit)
+	if err == nil || err.Error() != "organization a: boom" {
+		t.Fatalf("expected the error of organization a, got %v", err)
+	}
+	if len(visited) != 3 {
+		t.Fatalf("an error must not stop the pass, visited %v", visited)
+	}
+}
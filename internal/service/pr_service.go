@@ -4,34 +4,85 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
 type PullRequestRepository interface {
 	SavePullRequest(ctx context.Context, pr *models.PullRequest) error
 	GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error)
 	FindPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error)
-	GetAssignmentStats(ctx context.Context) (*models.AssignmentStats, error)
+	GetAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error)
+	GetTeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error)
+	StreamUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error
+	StreamPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error
+	StreamPullRequestsByReviewer(ctx context.Context, reviewerID string, fn func(*models.PullRequest) error) error
+	GetTurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error)
 	FindOpenPullRequestsByReviewers(ctx context.Context, reviewerIDs []string) ([]*models.PullRequest, error)
+	// CountOpenReviews считает открытые ревью каждого, кто назначался ревьюером, одним запросом.
+	CountOpenReviews(ctx context.Context) (map[string]int, error)
 	ApplyBulkTeamReviewerSwaps(ctx context.Context, swaps []models.ReviewerSwap, usersToDeactivate []string) error
 }
 
 type UserService interface {
-	AssignRewiers(teamId string) []string // тут мб надо возвращать ошибку
-	SetActivity(rew []string, status bool) error
-	GetUserTeam(userID string) (string, error)                                        // Получить команду пользователя
-	FindReplacementReviewer(teamName string, excludeUserIDs []string) (string, error) // Найти заменяющего ревьювера
+	AssignRewiers(ctx context.Context, teamId string, exclude []string, demand ReviewDemand) (*ReviewerSelection, error)
+	GetUserTeam(ctx context.Context, userID string) (string, error)                                                                         // Получить команду пользователя
+	FindReplacementReviewer(ctx context.Context, teamName string, excludeUserIDs []string, demand ReviewDemand) (*ReviewerSelection, error) // Найти заменяющего ревьювера
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
-	SyncUsersActivity(userIDs []string, status bool)
+	SyncUsersActivity(ctx context.Context, userIDs []string, status bool)
+	AbsentUsers(ctx context.Context, at time.Time) (map[string]struct{}, error)                    // Отсутствующие в момент at
+	RankCandidates(ctx context.Context, userIDs []string, at time.Time) ([]ReviewCandidate, error) // Кандидаты в порядке предпочтения
 }
 
+// MetricsRecorder получает бизнес-события сервиса для экспорта метрик.
+type MetricsRecorder interface {
+	PullRequestCreated()
+	PullRequestMerged()
+	ReviewerReassigned()
+	BulkReviewerSwaps(n int)
+	NoCandidate(operation string)
+}
+
+// reviewersPerPullRequest — сколько ревьюеров назначается на PR.
+const reviewersPerPullRequest = 2
+
+// Операции, для которых учитывается NO_CANDIDATE.
+const (
+	OperationReassign       = "reassign"
+	OperationBulkDeactivate = "bulk_deactivate"
+	OperationAbsence        = "absence"
+)
+
 type PullRequestManager struct {
 	repo        PullRequestRepository
 	UserService UserService
+	metrics     MetricsRecorder
+	queue       ReviewQueueRepository
+	// repositories задают команду ревьюеров и их число по репозиторию PR; без них действуют правила по умолчанию.
+	repositories RepositoryLookup
+	// affinity — правила подбора ревьюеров команд для массовых замен.
+	affinity AffinityRuleLookup
+	// learners — стажёры команд; без них теневые ревьюеры не назначаются.
+	learners LearnerLookup
+	// decisions сохраняет объяснения назначений; без него они не запоминаются.
+	decisions DecisionRecorder
+	// organizations перечисляет организации для метрик; без него учитывается только default.
+	organizations OrganizationLister
+	// queueMu не даёт двум разборам очереди одновременно назначить одних и тех же ревьюеров.
+	queueMu sync.Mutex
+
+	statsCacheTTL    time.Duration
+	turnaroundWindow time.Duration
+	turnaround       turnaroundCache
 }
 
 // NewPullRequestService связывает менеджер с репозиторием PR и пользователями.
@@ -42,26 +93,101 @@ func (prm *PullRequestManager) NewPullRequestService(repo PullRequestRepository,
 	}
 }
 
+// SetMetrics подключает получателя бизнес-метрик; без него события не учитываются.
+func (prm *PullRequestManager) SetMetrics(m MetricsRecorder) {
+	prm.metrics = m
+}
+
+// SetReviewQueue подключает очередь PR, ждущих ревьюеров; без неё нехватка ревьюеров из-за лимитов не запоминается.
+func (prm *PullRequestManager) SetReviewQueue(queue ReviewQueueRepository) {
+	prm.queue = queue
+}
+
+// SetRepositories подключает репозитории PR с владельцами и правилами ревью.
+func (prm *PullRequestManager) SetRepositories(repos RepositoryLookup) {
+	prm.repositories = repos
+}
+
+// SetAffinityRules подключает правила подбора ревьюеров команд для массовых замен ревьюеров.
+func (prm *PullRequestManager) SetAffinityRules(lookup AffinityRuleLookup) {
+	prm.affinity = lookup
+}
+
+// SetShadowReviews включает назначение теневых ревьюеров из стажёров команды ревьюеров.
+func (prm *PullRequestManager) SetShadowReviews(learners LearnerLookup) {
+	prm.learners = learners
+}
+
+// SetOrganizations подключает список организаций: OpenReviewsByOrganization обходит их все.
+// Без него учитывается только организация default.
+func (prm *PullRequestManager) SetOrganizations(lister OrganizationLister) {
+	prm.organizations = lister
+}
+
+// SetDecisions включает сохранение решений о назначении ревьюеров.
+func (prm *PullRequestManager) SetDecisions(decisions DecisionRecorder) {
+	prm.decisions = decisions
+}
+
+// recorder возвращает подключённый MetricsRecorder или заглушку.
+func (prm *PullRequestManager) recorder() MetricsRecorder {
+	if prm.metrics == nil {
+		return noopMetrics{}
+	}
+	return prm.metrics
+}
+
 // CreatePullRequest формирует запись PR, назначает ревьюеров и сохраняет её.
-func (prm *PullRequestManager) CreatePullRequest(ctx context.Context, reqData models.PostPullRequestCreateJSONBody) (*models.PullRequest, error) {
+// Если ревьюеров не хватило из-за лимитов, PR встаёт в очередь и получит их, когда у коллег освободится лимит.
+func (prm *PullRequestManager) CreatePullRequest(ctx context.Context, reqData models.PostPullRequestCreateJSONBody) (_ *domain.CreateResponse, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.CreatePullRequest")
+	defer func() { endSpan(span, err) }()
+
+	if err := validatePullRequestMeta(reqData); err != nil {
+		return nil, err
+	}
 	pr := convertReqToModel(reqData)
-	teamID, err := prm.UserService.GetUserTeam(pr.AuthorId)
+	teamID, err := prm.UserService.GetUserTeam(ctx, pr.AuthorId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get author team: %w", err)
 	}
+	rules, err := prm.reviewRules(ctx, pr.Repository, teamID)
+	if err != nil {
+		return nil, err
+	}
 
-	curAssignedReviewers := prm.UserService.AssignRewiers(teamID)
-	pr.AssignedReviewers = curAssignedReviewers
-	go prm.UserService.SetActivity(curAssignedReviewers, false)
+	selection, err := prm.UserService.AssignRewiers(ctx, rules.team, []string{pr.AuthorId}, demandFor(pr, rules.reviewers))
+	if err != nil {
+		if errors.Is(err, domain.ErrNoCandidate) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to assign reviewers: %w", err)
+	}
+	pr.AssignedReviewers = selection.ReviewerIDs()
+	pr.ShadowReviewers = prm.pickShadowReviewers(ctx, rules.team, pr)
 
 	if err := prm.repo.SavePullRequest(ctx, pr); err != nil {
 		return nil, fmt.Errorf("couldn`t add pr to DB")
 	}
-	return pr, nil
+	prm.recorder().PullRequestCreated()
+	prm.recordDecision(ctx, models.DecisionOperationCREATE, selection.Decision)
+
+	resp := &domain.CreateResponse{PR: pr, ReviewerLoad: selection.Reviewers}
+	if selection.Pending > 0 && prm.queue != nil {
+		item := models.QueuedReview{PullRequestId: pr.PullRequestId, Missing: selection.Pending, QueuedAt: *pr.CreatedAt}
+		if err := prm.queue.EnqueueReview(ctx, item); err != nil {
+			return nil, fmt.Errorf("failed to queue pull request: %w", err)
+		}
+		resp.PendingReviewers = selection.Pending
+	}
+	return resp, nil
 }
 
 // Merge помечает PR как слитый и возвращает актуальное состояние.
-func (prm *PullRequestManager) Merge(ctx context.Context, payload models.PostPullRequestMergeJSONBody) (*models.PullRequest, error) {
+func (prm *PullRequestManager) Merge(ctx context.Context, payload models.PostPullRequestMergeJSONBody) (_ *models.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.Merge")
+	defer func() { endSpan(span, err) }()
+
 	// Получаем текущий PR.
 	pr, err := prm.repo.GetPullRequest(ctx, payload.PullRequestId)
 	if err != nil {
@@ -85,17 +211,19 @@ func (prm *PullRequestManager) Merge(ctx context.Context, payload models.PostPul
 	if err := prm.repo.SavePullRequest(ctx, pr); err != nil {
 		return nil, fmt.Errorf("failed to save merged pull request: %w", err)
 	}
+	prm.recorder().PullRequestMerged()
 
-	// Возвращаем активность ревьюерам PR.
-	if len(pr.AssignedReviewers) > 0 {
-		go prm.UserService.SetActivity(pr.AssignedReviewers, true)
-	}
+	// У ревьюеров PR освободилось место — отдаём его PR из очереди.
+	prm.drainQueueAfterRelease(ctx)
 
 	return pr, nil
 }
 
 // Reassign заменяет ревьюера PR и возвращает информацию о перестановке.
-func (prm *PullRequestManager) Reassign(ctx context.Context, oldUserId, prId string) (*domain.ReassignResponse, error) {
+func (prm *PullRequestManager) Reassign(ctx context.Context, oldUserId, prId string) (_ *domain.ReassignResponse, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.Reassign")
+	defer func() { endSpan(span, err) }()
+
 	// Формируем payload в формате внутренних структур.
 	payload := models.PostPullRequestReassignJSONBody{
 		PullRequestId: prId,
@@ -128,32 +256,43 @@ func (prm *PullRequestManager) Reassign(ctx context.Context, oldUserId, prId str
 	}
 
 	// Узнаём команду старого ревьюера.
-	teamName, err := prm.UserService.GetUserTeam(payload.OldUserId)
+	teamName, err := prm.UserService.GetUserTeam(ctx, payload.OldUserId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user team: %w", err)
 	}
 
 	// Ищем замену в этой же команде.
-	// Исключаем текущих ревьюеров и ушедшего участника.
-	excludeUserIDs := make([]string, 0, len(pr.AssignedReviewers)+1)
+	// Исключаем автора, текущих и теневых ревьюеров и ушедшего участника.
+	excludeUserIDs := make([]string, 0, len(pr.AssignedReviewers)+len(pr.ShadowReviewers)+2)
 	excludeUserIDs = append(excludeUserIDs, pr.AssignedReviewers...)
-	excludeUserIDs = append(excludeUserIDs, payload.OldUserId)
+	excludeUserIDs = append(excludeUserIDs, pr.ShadowReviewers...)
+	excludeUserIDs = append(excludeUserIDs, payload.OldUserId, pr.AuthorId)
+
+	// Правила подбора проверяются по ревьюерам, которые останутся на PR.
+	newAssignedReviewers := make([]string, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID != payload.OldUserId {
+			newAssignedReviewers = append(newAssignedReviewers, reviewerID)
+		}
+	}
+	demand := demandFor(pr, 1)
+	demand.Reviewers = newAssignedReviewers
 
-	newReviewerID, err := prm.UserService.FindReplacementReviewer(teamName, excludeUserIDs)
+	replacement, err := prm.UserService.FindReplacementReviewer(ctx, teamName, excludeUserIDs, demand)
 	if err != nil {
 		if errors.Is(err, domain.ErrNoCandidate) {
+			prm.recorder().NoCandidate(OperationReassign)
+			var rejected *domain.RejectedCandidatesError
+			if errors.As(err, &rejected) {
+				return nil, err
+			}
 			return nil, domain.NewNoCandidateError(payload.PullRequestId)
 		}
 		return nil, fmt.Errorf("failed to find replacement reviewer: %w", err)
 	}
 
 	// Заменяем ревьюера в списке назначенных.
-	newAssignedReviewers := make([]string, 0, len(pr.AssignedReviewers))
-	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID != payload.OldUserId {
-			newAssignedReviewers = append(newAssignedReviewers, reviewerID)
-		}
-	}
+	newReviewerID := replacement.Reviewers[0].UserId
 	newAssignedReviewers = append(newAssignedReviewers, newReviewerID)
 
 	pr.AssignedReviewers = newAssignedReviewers
@@ -162,10 +301,14 @@ func (prm *PullRequestManager) Reassign(ctx context.Context, oldUserId, prId str
 	if err := prm.repo.SavePullRequest(ctx, pr); err != nil {
 		return nil, fmt.Errorf("failed to save reassigned pull request: %w", err)
 	}
+	prm.recorder().ReviewerReassigned()
+	if replacement.Decision != nil {
+		replacement.Decision.Replaced = []string{payload.OldUserId}
+	}
+	prm.recordDecision(ctx, models.DecisionOperationREASSIGN, replacement.Decision)
 
-	// Обновляем активности: старого ревьюера включаем, нового выключаем.
-	go prm.UserService.SetActivity([]string{payload.OldUserId}, true)
-	go prm.UserService.SetActivity([]string{newReviewerID}, false)
+	// У старого ревьюера освободилось место.
+	prm.drainQueueAfterRelease(ctx)
 
 	// Формируем ответ.
 	response := &domain.ReassignResponse{
@@ -177,7 +320,10 @@ func (prm *PullRequestManager) Reassign(ctx context.Context, oldUserId, prId str
 }
 
 // BulkDeactivateTeamMembers деактивирует целевых пользователей и планирует замену ревьюеров.
-func (prm *PullRequestManager) BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.TeamBulkDeactivateResult, error) {
+func (prm *PullRequestManager) BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (_ *models.TeamBulkDeactivateResult, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.BulkDeactivateTeamMembers")
+	defer func() { endSpan(span, err) }()
+
 	teamName = strings.TrimSpace(teamName)
 	if teamName == "" {
 		return nil, fmt.Errorf("team name is required")
@@ -200,30 +346,95 @@ func (prm *PullRequestManager) BulkDeactivateTeamMembers(ctx context.Context, te
 		return nil, err
 	}
 
-	candidateIDs := collectReplacementCandidates(team.Members, targetSet)
-	pool := newReviewerPool(candidateIDs)
+	reassignments, err := prm.swapOutReviewers(ctx, team, targets, targetSet, true, OperationBulkDeactivate)
+	if err != nil {
+		return nil, err
+	}
 
-	swaps, reassignments, replacementIDs, err := prm.planBulkReviewerSwaps(ctx, targets, targetSet, pool)
+	result := &models.TeamBulkDeactivateResult{
+		TeamName:      teamName,
+		Deactivated:   targets,
+		Reassignments: reassignments,
+	}
+	return result, nil
+}
+
+// ReleaseReviews передаёт открытые ревью пользователя другим участникам его команды, не меняя его активность.
+// Используется в начале периода отсутствия.
+func (prm *PullRequestManager) ReleaseReviews(ctx context.Context, userID string) (_ []models.TeamPRReassignment, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.ReleaseReviews")
+	defer func() { endSpan(span, err) }()
+
+	teamName, err := prm.UserService.GetUserTeam(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user team: %w", err)
+	}
+	team, err := prm.UserService.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []string{userID}
+	targetSet := map[string]struct{}{userID: {}}
+	return prm.swapOutReviewers(ctx, team, targets, targetSet, false, OperationAbsence)
+}
+
+// swapOutReviewers заменяет targets во всех их открытых PR активными и присутствующими участниками команды
+// в порядке RankCandidates с учётом лимитов и правил подбора команды и применяет замены одной транзакцией.
+// При deactivateTargets сами targets деактивируются в той же транзакции.
+func (prm *PullRequestManager) swapOutReviewers(
+	ctx context.Context,
+	team *models.Team,
+	targets []string,
+	targetSet map[string]struct{},
+	deactivateTargets bool,
+	operation string,
+) ([]models.TeamPRReassignment, error) {
+	now := time.Now()
+	absent, err := prm.UserService.AbsentUsers(ctx, now)
 	if err != nil {
+		return nil, fmt.Errorf("find absent users: %w", err)
+	}
+	candidates, err := prm.UserService.RankCandidates(ctx, collectReplacementCandidates(team.Members, targetSet, absent), now)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := findAffinityRules(ctx, prm.affinity, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	plan := bulkSwapPlan{
+		team:        team,
+		pool:        newReviewerPool(candidates),
+		rules:       rules,
+		unavailable: replacementUnavailable(team, targetSet, absent),
+	}
+
+	swaps, reassignments, decisions, err := prm.planBulkReviewerSwaps(ctx, targets, targetSet, plan)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoCandidate) {
+			prm.recorder().NoCandidate(operation)
+		}
 		return nil, err
 	}
 
-	usersToDeactivate := append(append(make([]string, 0, len(targets)+len(replacementIDs)), targets...), replacementIDs...)
+	var usersToDeactivate []string
+	if deactivateTargets {
+		usersToDeactivate = targets
+	}
 
 	if err = prm.repo.ApplyBulkTeamReviewerSwaps(ctx, swaps, usersToDeactivate); err != nil {
 		return nil, fmt.Errorf("bulk reviewer swap: %w", err)
 	}
-
-	if len(usersToDeactivate) > 0 {
-		prm.UserService.SyncUsersActivity(usersToDeactivate, false)
+	prm.recorder().BulkReviewerSwaps(len(swaps))
+	for _, decision := range decisions {
+		prm.recordDecision(ctx, bulkDecisionOperations[operation], decision)
 	}
 
-	result := &models.TeamBulkDeactivateResult{
-		TeamName:      teamName,
-		Deactivated:   targets,
-		Reassignments: reassignments,
+	if len(usersToDeactivate) > 0 {
+		prm.UserService.SyncUsersActivity(ctx, usersToDeactivate, false)
 	}
-	return result, nil
+	return reassignments, nil
 }
 
 // normalizeTargetUserIDs удаляет дубли и пустые значения из списка пользователей.
@@ -261,13 +472,16 @@ func ensureTargetsInTeam(targets []string, members []models.TeamMember) error {
 	return nil
 }
 
-// collectReplacementCandidates собирает активных участников команды вне списка деактивации.
-func collectReplacementCandidates(members []models.TeamMember, targetSet map[string]struct{}) []string {
+// collectReplacementCandidates собирает активных и присутствующих участников команды вне списка деактивации.
+func collectReplacementCandidates(members []models.TeamMember, targetSet, absent map[string]struct{}) []string {
 	candidateIDs := make([]string, 0, len(members))
 	for _, member := range members {
 		if _, targeted := targetSet[member.UserId]; targeted {
 			continue
 		}
+		if _, away := absent[member.UserId]; away {
+			continue
+		}
 		if member.IsActive {
 			candidateIDs = append(candidateIDs, member.UserId)
 		}
@@ -275,46 +489,108 @@ func collectReplacementCandidates(members []models.TeamMember, targetSet map[str
 	return candidateIDs
 }
 
-// planBulkReviewerSwaps строит список замен ревьюеров и пользователей для деактивации.
+// replacementUnavailable объясняет, почему участник команды не рассматривается как замена.
+func replacementUnavailable(team *models.Team, targetSet, absent map[string]struct{}) func(string) string {
+	return func(userID string) string {
+		if _, targeted := targetSet[userID]; targeted {
+			return "is being replaced"
+		}
+		if _, away := absent[userID]; away {
+			return "is absent"
+		}
+		for _, member := range team.Members {
+			if member.UserId == userID && !member.IsActive {
+				return "is inactive"
+			}
+			if member.UserId == userID {
+				return "already reviews the pull request"
+			}
+		}
+		return "is not a member of team " + team.TeamName
+	}
+}
+
+// bulkDecisionOperations сопоставляет операции массовой замены с операциями решений о назначении.
+var bulkDecisionOperations = map[string]models.DecisionOperation{
+	OperationBulkDeactivate: models.DecisionOperationDEACTIVATE,
+	OperationAbsence:        models.DecisionOperationABSENCE,
+}
+
+// bulkSwapPlan — команда, кандидаты и правила подбора для массовой замены ревьюеров.
+type bulkSwapPlan struct {
+	team        *models.Team
+	pool        *reviewerPool
+	rules       []models.AffinityRule
+	unavailable func(userID string) string
+}
+
+// planBulkReviewerSwaps строит список замен ревьюеров. Замены для всех targets одного PR подбираются вместе,
+// чтобы правила REQUIRE_PAIR и ALWAYS_INCLUDE проверялись по итоговому составу ревьюеров.
+// Для каждого PR с заменами возвращается решение о назначении.
 func (prm *PullRequestManager) planBulkReviewerSwaps(
 	ctx context.Context,
 	targets []string,
 	targetSet map[string]struct{},
-	pool *reviewerPool,
-) ([]models.ReviewerSwap, []models.TeamPRReassignment, []string, error) {
+	plan bulkSwapPlan,
+) (_ []models.ReviewerSwap, _ []models.TeamPRReassignment, _ []*models.AssignmentDecision, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.planBulkReviewerSwaps")
+	defer func() { endSpan(span, err) }()
+
 	openPRs, err := prm.repo.FindOpenPullRequestsByReviewers(ctx, targets)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("find open pull requests: %w", err)
 	}
+	members := make([]string, 0, len(plan.team.Members))
+	for _, member := range plan.team.Members {
+		members = append(members, member.UserId)
+	}
 
-	var (
-		swaps          []models.ReviewerSwap
-		reassignments  []models.TeamPRReassignment
-		replacementIDs []string
-	)
+	var swaps []models.ReviewerSwap
+	var decisions []*models.AssignmentDecision
+	// Пустой список, а не null: reassignments обязателен в ответе API.
+	reassignments := make([]models.TeamPRReassignment, 0)
 
 	for _, pr := range openPRs {
 		assigned := make(map[string]struct{}, len(pr.AssignedReviewers))
+		var replaced, staying []string
 		for _, reviewer := range pr.AssignedReviewers {
 			assigned[reviewer] = struct{}{}
+			if _, targeted := targetSet[reviewer]; targeted {
+				replaced = append(replaced, reviewer)
+			} else {
+				staying = append(staying, reviewer)
+			}
+		}
+		if len(replaced) == 0 {
+			continue
+		}
+		// Автор и теневые ревьюеры тоже не могут стать ревьюерами PR.
+		assigned[pr.AuthorId] = struct{}{}
+		for _, shadow := range pr.ShadowReviewers {
+			assigned[shadow] = struct{}{}
 		}
 
-		replacementsForPR := make([]models.ReviewerReplacement, 0, len(pr.AssignedReviewers))
-		for _, reviewer := range pr.AssignedReviewers {
-			if _, targeted := targetSet[reviewer]; !targeted {
-				continue
+		constraints := newAffinityConstraints(plan.rules, pr.AuthorId, staying, plan.unavailable)
+		considered, excluded := slices.Clone(plan.pool.candidates), maps.Clone(assigned)
+		picked := constraints.pick(plan.pool, assigned, len(replaced), pr.ReviewWeight())
+		if len(picked) < len(replaced) || len(constraints.unmet()) > 0 {
+			if err := constraints.failure(plan.pool, pr.PullRequestId); err != nil {
+				return nil, nil, nil, err
 			}
-			newReviewer, ok := pool.take(assigned)
-			if !ok {
-				return nil, nil, nil, domain.NewNoCandidateError(pr.PullRequestId)
-			}
-			assigned[newReviewer] = struct{}{}
+			return nil, nil, nil, domain.NewNoCandidateError(pr.PullRequestId)
+		}
+		decision := explainSelection(pr.PullRequestId, plan.team.TeamName, members, considered, excluded, plan.pool, constraints, picked)
+		decision.Replaced = replaced
+		decisions = append(decisions, decision)
+
+		replacementsForPR := make([]models.ReviewerReplacement, 0, len(replaced))
+		for i, reviewer := range replaced {
+			newReviewer := picked[i].UserId
 			swaps = append(swaps, models.ReviewerSwap{
 				PullRequestId: pr.PullRequestId,
 				OldUserId:     reviewer,
 				NewUserId:     newReviewer,
 			})
-			replacementIDs = append(replacementIDs, newReviewer)
 			replacementsForPR = append(replacementsForPR, models.ReviewerReplacement{
 				OldUserId: reviewer,
 				NewUserId: newReviewer,
@@ -329,11 +605,142 @@ func (prm *PullRequestManager) planBulkReviewerSwaps(
 		}
 	}
 
-	return swaps, reassignments, replacementIDs, nil
+	return swaps, reassignments, decisions, nil
+}
+
+// DrainReviewQueue назначает недостающих ревьюеров PR из очереди — сначала более приоритетным, при равном приоритете
+// в порядке постановки, — пока у кандидатов есть запас лимита, и возвращает число назначенных ревьюеров.
+// Слитые и удалённые PR убираются из очереди.
+func (prm *PullRequestManager) DrainReviewQueue(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.DrainReviewQueue")
+	defer func() { endSpan(span, err) }()
+
+	if prm.queue == nil {
+		return 0, nil
+	}
+	prm.queueMu.Lock()
+	defer prm.queueMu.Unlock()
+
+	items, err := prm.queue.ListQueuedReviews(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list review queue: %w", err)
+	}
+	queued := make([]queuedPullRequest, 0, len(items))
+	for _, item := range items {
+		pr, err := prm.repo.GetPullRequest(ctx, item.PullRequestId)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return 0, fmt.Errorf("get queued pull request %s: %w", item.PullRequestId, err)
+			}
+			if err := prm.queue.DequeueReview(ctx, item.PullRequestId); err != nil {
+				return 0, fmt.Errorf("dequeue pull request %s: %w", item.PullRequestId, err)
+			}
+			continue
+		}
+		queued = append(queued, queuedPullRequest{item: item, pr: pr})
+	}
+	// Очередь уже упорядочена по времени постановки, стабильная сортировка его сохраняет.
+	sort.SliceStable(queued, func(i, j int) bool {
+		return queued[i].pr.Priority.Rank() > queued[j].pr.Priority.Rank()
+	})
+
+	assigned := 0
+	for _, q := range queued {
+		n, err := prm.fillQueuedReview(ctx, q.item, q.pr)
+		if err != nil {
+			return assigned, fmt.Errorf("fill queued pull request %s: %w", q.item.PullRequestId, err)
+		}
+		assigned += n
+	}
+	return assigned, nil
+}
+
+// queuedPullRequest — элемент очереди вместе с PR.
+type queuedPullRequest struct {
+	item models.QueuedReview
+	pr   *models.PullRequest
 }
 
-// ListForReviewer возвращает короткие карточки PR, где пользователь назначен ревьюером.
-func (prm *PullRequestManager) ListForReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+// fillQueuedReview назначает PR из очереди сколько получится недостающих ревьюеров и обновляет очередь.
+func (prm *PullRequestManager) fillQueuedReview(ctx context.Context, item models.QueuedReview, pr *models.PullRequest) (int, error) {
+	if pr.Status == models.PullRequestStatusMERGED {
+		return 0, prm.queue.DequeueReview(ctx, item.PullRequestId)
+	}
+
+	teamID, err := prm.UserService.GetUserTeam(ctx, pr.AuthorId)
+	if err != nil {
+		return 0, fmt.Errorf("get author team: %w", err)
+	}
+	rules, err := prm.reviewRules(ctx, pr.Repository, teamID)
+	if err != nil {
+		return 0, err
+	}
+	missing := min(item.Missing, rules.reviewers-len(pr.AssignedReviewers))
+	if missing <= 0 {
+		return 0, prm.queue.DequeueReview(ctx, item.PullRequestId)
+	}
+	exclude := append([]string{pr.AuthorId}, pr.AssignedReviewers...)
+	exclude = append(exclude, pr.ShadowReviewers...)
+	selection, err := prm.UserService.AssignRewiers(ctx, rules.team, exclude, demandFor(pr, missing))
+	if err != nil {
+		// Правила подбора сейчас не выполнить — PR остаётся в очереди до следующего разбора.
+		if errors.Is(err, domain.ErrNoCandidate) {
+			slog.WarnContext(ctx, "queued pull request still has no valid reviewers",
+				"pull_request_id", pr.PullRequestId, "err", err.Error())
+			return 0, nil
+		}
+		return 0, fmt.Errorf("assign reviewers: %w", err)
+	}
+	if len(selection.Reviewers) == 0 {
+		return 0, nil
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, selection.ReviewerIDs()...)
+	if err := prm.repo.SavePullRequest(ctx, pr); err != nil {
+		return 0, fmt.Errorf("save pull request: %w", err)
+	}
+	prm.recordDecision(ctx, models.DecisionOperationQUEUE, selection.Decision)
+	if left := missing - len(selection.Reviewers); left > 0 {
+		item.Missing = left
+		err = prm.queue.EnqueueReview(ctx, item)
+	} else {
+		err = prm.queue.DequeueReview(ctx, item.PullRequestId)
+	}
+	return len(selection.Reviewers), err
+}
+
+// reviewRules возвращает правила ревью репозитория PR; authorTeam — команда автора.
+func (prm *PullRequestManager) reviewRules(ctx context.Context, repository, authorTeam string) (reviewRules, error) {
+	if prm.repositories == nil {
+		return reviewRulesFor(nil, authorTeam), nil
+	}
+	repo, err := prm.repositories.GetRepository(ctx, models.RepositoryOrDefault(repository))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return reviewRules{}, domain.NewNotFoundError("repository")
+		}
+		return reviewRules{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+	return reviewRulesFor(repo, authorTeam), nil
+}
+
+// drainQueueAfterRelease разбирает очередь после того, как у ревьюера освободилось место.
+// Ошибка только журналируется: операция, освободившая место, уже выполнена.
+func (prm *PullRequestManager) drainQueueAfterRelease(ctx context.Context) {
+	if prm.queue == nil {
+		return
+	}
+	if _, err := prm.DrainReviewQueue(ctx); err != nil {
+		slog.WarnContext(ctx, "review queue drain failed", "err", err.Error())
+	}
+}
+
+// ListForReviewer возвращает короткие карточки PR, где пользователь назначен ревьюером или теневым ревьюером;
+// непустой repository оставляет PR одного репозитория.
+func (prm *PullRequestManager) ListForReviewer(ctx context.Context, userID, repository string) (_ []models.PullRequestShort, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.ListForReviewer")
+	defer func() { endSpan(span, err) }()
+
 	// Получаем все PR, где пользователь числится ревьюером.
 	prs, err := prm.repo.FindPullRequestsByReviewer(ctx, userID)
 	if err != nil {
@@ -343,88 +750,247 @@ func (prm *PullRequestManager) ListForReviewer(ctx context.Context, userID strin
 	// Конвертируем записи в укороченный формат.
 	result := make([]models.PullRequestShort, 0, len(prs))
 	for _, pr := range prs {
-		shortPR := models.PullRequestShort{
-			AuthorId:        pr.AuthorId,
-			PullRequestId:   pr.PullRequestId,
-			PullRequestName: pr.PullRequestName,
-			Status:          models.PullRequestShortStatus(pr.Status),
+		if inRepository(pr, repository) {
+			result = append(result, toShortPullRequest(pr, userID))
 		}
-		result = append(result, shortPR)
 	}
 
 	return result, nil
 }
 
-// AssignmentStats возвращает агрегированную статистику назначений ревьюеров.
-func (prm *PullRequestManager) AssignmentStats(ctx context.Context) (*models.AssignmentStats, error) {
-	stats, err := prm.repo.GetAssignmentStats(ctx)
+// ExportReviewerPullRequests передаёт в fn PR ревьюера в укороченном формате по мере чтения из хранилища;
+// непустой repository оставляет PR одного репозитория.
+func (prm *PullRequestManager) ExportReviewerPullRequests(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) (err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.ExportReviewerPullRequests")
+	defer func() { endSpan(span, err) }()
+
+	err = prm.repo.StreamPullRequestsByReviewer(ctx, userID, func(pr *models.PullRequest) error {
+		if !inRepository(pr, repository) {
+			return nil
+		}
+		return fn(toShortPullRequest(pr, userID))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream pull requests for reviewer %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ExportUserAssignments передаёт в fn строки статистики назначений по ревьюерам по мере чтения из хранилища.
+func (prm *PullRequestManager) ExportUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) (err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.ExportUserAssignments")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateAssignmentFilter(filter); err != nil {
+		return err
+	}
+	if err := prm.repo.StreamUserAssignments(ctx, filter, fn); err != nil {
+		return fmt.Errorf("failed to stream user assignment stats: %w", err)
+	}
+	return nil
+}
+
+// ExportPullRequestAssignments передаёт в fn строки статистики назначений по PR по мере чтения из хранилища.
+func (prm *PullRequestManager) ExportPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) (err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.ExportPullRequestAssignments")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateAssignmentFilter(filter); err != nil {
+		return err
+	}
+	if err := prm.repo.StreamPullRequestAssignments(ctx, filter, fn); err != nil {
+		return fmt.Errorf("failed to stream pull request assignment stats: %w", err)
+	}
+	return nil
+}
+
+// TeamAssignmentStats возвращает только агрегаты по командам, не читая списки пользователей и PR.
+func (prm *PullRequestManager) TeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (_ []models.TeamAssignmentStat, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.TeamAssignmentStats")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateAssignmentFilter(filter); err != nil {
+		return nil, err
+	}
+	teams, err := prm.repo.GetTeamAssignmentStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team assignment stats: %w", err)
+	}
+	return teams, nil
+}
+
+// validateAssignmentFilter проверяет параметры фильтра статистики назначений.
+func validateAssignmentFilter(filter models.AssignmentStatsFilter) error {
+	switch filter.Status {
+	case "", models.PullRequestStatusOPEN, models.PullRequestStatusMERGED:
+	default:
+		return domain.NewInvalidParamError("status", "must be OPEN or MERGED")
+	}
+	if filter.Limit < 0 {
+		return domain.NewInvalidParamError("limit", "must not be negative")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return domain.NewInvalidParamError("from", "must be before to")
+	}
+	return nil
+}
+
+// toShortPullRequest переводит PR в укороченный формат ответа с ролью пользователя userID в его ревью.
+func toShortPullRequest(pr *models.PullRequest, userID string) models.PullRequestShort {
+	return models.PullRequestShort{
+		AuthorId:        pr.AuthorId,
+		PullRequestId:   pr.PullRequestId,
+		PullRequestName: pr.PullRequestName,
+		Status:          models.PullRequestShortStatus(pr.Status),
+		Repository:      pr.Repository,
+		Role:            pr.RoleOf(userID),
+	}
+}
+
+// inRepository сообщает, относится ли PR к репозиторию; пустой repository подходит любому PR.
+func inRepository(pr *models.PullRequest, repository string) bool {
+	return repository == "" || models.RepositoryOrDefault(pr.Repository) == repository
+}
+
+// AssignmentStats возвращает агрегированную статистику назначений ревьюеров по PR, попавшим в фильтр.
+func (prm *PullRequestManager) AssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (_ *models.AssignmentStats, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.AssignmentStats")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateAssignmentFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := prm.repo.GetAssignmentStats(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get assignment stats: %w", err)
 	}
 	return stats, nil
 }
 
-// convertReqToModel преобразует входной payload в модель PullRequest.
-func convertReqToModel(reqData models.PostPullRequestCreateJSONBody) *models.PullRequest {
-	var createdAt = time.Now()
-	return &models.PullRequest{
-		AuthorId:        reqData.AuthorId,
-		PullRequestId:   reqData.PullRequestId,
-		PullRequestName: reqData.PullRequestName,
-		Status:          models.PullRequestStatusOPEN,
-		CreatedAt:       &createdAt,
+// OpenReviewsByUser считает открытые PR на каждого ревьюера, когда-либо получавшего назначения.
+// Вызывается при каждом scrape метрик, поэтому обходится одним агрегирующим запросом.
+func (prm *PullRequestManager) OpenReviewsByUser(ctx context.Context) (_ map[string]int, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.OpenReviewsByUser")
+	defer func() { endSpan(span, err) }()
+
+	load, err := prm.repo.CountOpenReviews(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
 	}
+	return load, nil
 }
 
-type reviewerPool struct {
-	queue     []string
-	available map[string]struct{}
+// OpenReviewsByOrganization считает открытые ревью на пользователя в каждой организации: организация → пользователь → число.
+func (prm *PullRequestManager) OpenReviewsByOrganization(ctx context.Context) (_ map[string]map[string]int, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.OpenReviewsByOrganization")
+	defer func() { endSpan(span, err) }()
+
+	result := make(map[string]map[string]int)
+	err = forEachOrganization(ctx, prm.organizations, func(ctx context.Context) error {
+		load, err := prm.OpenReviewsByUser(ctx)
+		if err != nil {
+			return err
+		}
+		result[tenant.Organization(ctx)] = load
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// newReviewerPool создаёт пул доступных ревьюеров без дублей.
-func newReviewerPool(ids []string) *reviewerPool {
-	available := make(map[string]struct{}, len(ids))
-	queue := make([]string, 0, len(ids))
-	for _, id := range ids {
-		if id == "" {
-			continue
+// validatePullRequestMeta проверяет номер, размер и приоритет PR из запроса на создание.
+// PR репозитория определяется парой (repository, number) и получает идентификатор <repository>#<number>.
+// Только в репозитории default PR можно создать без номера, по произвольному pull_request_id без '#',
+// чтобы он не занял идентификатор будущего PR с номером.
+func validatePullRequestMeta(reqData models.PostPullRequestCreateJSONBody) error {
+	if reqData.Number < 0 {
+		return domain.NewInvalidParamError("number", "must not be negative")
+	}
+	if reqData.Number == 0 {
+		if repository := models.RepositoryOrDefault(strings.TrimSpace(reqData.Repository)); repository != models.DefaultRepository {
+			return domain.NewInvalidParamError("number", "is required for pull requests of repository "+repository)
 		}
-		if _, exists := available[id]; exists {
-			continue
+		if reqData.PullRequestId == "" {
+			return domain.NewInvalidParamError("pull_request_id", "is required without number")
+		}
+		if strings.Contains(reqData.PullRequestId, "#") {
+			return domain.NewInvalidParamError("pull_request_id", "must not contain # without number")
+		}
+	}
+	if reqData.Number > 0 && reqData.PullRequestId != "" {
+		if key := models.PullRequestKey(strings.TrimSpace(reqData.Repository), reqData.Number); reqData.PullRequestId != key {
+			return domain.NewInvalidParamError("pull_request_id", "must be empty or "+key)
 		}
-		available[id] = struct{}{}
-		queue = append(queue, id)
 	}
-	return &reviewerPool{
-		queue:     queue,
-		available: available,
+	if reqData.LinesAdded < 0 {
+		return domain.NewInvalidParamError("lines_added", "must not be negative")
 	}
+	if reqData.LinesDeleted < 0 {
+		return domain.NewInvalidParamError("lines_deleted", "must not be negative")
+	}
+	if reqData.FilesChanged < 0 {
+		return domain.NewInvalidParamError("files_changed", "must not be negative")
+	}
+	if reqData.Priority.Rank() < 0 {
+		return domain.NewInvalidParamError("priority", "must be one of LOW, NORMAL, HIGH, URGENT")
+	}
+	return nil
 }
 
-// take выбирает следующего доступного ревьюера, избегая исключений.
-func (p *reviewerPool) take(exclude map[string]struct{}) (string, bool) {
-	if p == nil || len(p.available) == 0 {
-		return "", false
-	}
-	attempts := len(p.queue)
-	for attempts > 0 {
-		attempts--
-		if len(p.queue) == 0 {
-			break
-		}
-		candidate := p.queue[0]
-		p.queue = p.queue[1:]
-		if _, ok := p.available[candidate]; !ok {
+// normalizeLabels обрезает пробелы и убирает пустые и повторяющиеся метки, сохраняя порядок.
+func normalizeLabels(labels []string) []string {
+	var result []string
+	seen := make(map[string]struct{}, len(labels))
+	for _, raw := range labels {
+		label := strings.TrimSpace(raw)
+		if label == "" {
 			continue
 		}
-		if exclude != nil {
-			if _, conflict := exclude[candidate]; conflict {
-				p.queue = append(p.queue, candidate)
-				continue
-			}
+		if _, exists := seen[label]; exists {
+			continue
 		}
-		delete(p.available, candidate)
-		return candidate, true
+		seen[label] = struct{}{}
+		result = append(result, label)
+	}
+	return result
+}
+
+// convertReqToModel преобразует входной payload в модель PullRequest.
+func convertReqToModel(reqData models.PostPullRequestCreateJSONBody) *models.PullRequest {
+	var createdAt = time.Now()
+	priority := reqData.Priority
+	if priority == "" {
+		priority = models.PullRequestPriorityNORMAL
+	}
+	repository := models.RepositoryOrDefault(strings.TrimSpace(reqData.Repository))
+	prID := reqData.PullRequestId
+	if reqData.Number > 0 {
+		prID = models.PullRequestKey(repository, reqData.Number)
+	}
+	return &models.PullRequest{
+		AuthorId:        reqData.AuthorId,
+		PullRequestId:   prID,
+		PullRequestName: reqData.PullRequestName,
+		Status:          models.PullRequestStatusOPEN,
+		CreatedAt:       &createdAt,
+		LinesAdded:      reqData.LinesAdded,
+		LinesDeleted:    reqData.LinesDeleted,
+		FilesChanged:    reqData.FilesChanged,
+		Priority:        priority,
+		Labels:          normalizeLabels(reqData.Labels),
+		Repository:      repository,
+		Number:          reqData.Number,
 	}
-	return "", false
 }
+
+// noopMetrics — заглушка MetricsRecorder для запуска без метрик.
+type noopMetrics struct{}
+
+func (noopMetrics) PullRequestCreated()   {}
+func (noopMetrics) PullRequestMerged()    {}
+func (noopMetrics) ReviewerReassigned()   {}
+func (noopMetrics) BulkReviewerSwaps(int) {}
+func (noopMetrics) NoCandidate(string)    {}
@@ -6,11 +6,17 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
 const testTeamName = "team-1"
@@ -19,8 +25,10 @@ type mockPullRequestRepository struct {
 	savePullRequestFn                func(context.Context, *models.PullRequest) error
 	getPullRequestFn                 func(context.Context, string) (*models.PullRequest, error)
 	findPullRequestsByReviewerFn     func(context.Context, string) ([]*models.PullRequest, error)
-	getAssignmentStatsFn             func(context.Context) (*models.AssignmentStats, error)
+	getAssignmentStatsFn             func(context.Context, models.AssignmentStatsFilter) (*models.AssignmentStats, error)
+	getTurnaroundStatsFn             func(context.Context, models.TurnaroundFilter) (*models.TurnaroundStats, error)
 	findOpenPullRequestsByReviewerFn func(context.Context, []string) ([]*models.PullRequest, error)
+	countOpenReviewsFn               func(context.Context) (map[string]int, error)
 	applyBulkTeamReviewerSwapsFn     func(context.Context, []models.ReviewerSwap, []string) error
 }
 
@@ -45,11 +53,66 @@ func (m *mockPullRequestRepository) FindPullRequestsByReviewer(ctx context.Conte
 	return m.findPullRequestsByReviewerFn(ctx, reviewerID)
 }
 
-func (m *mockPullRequestRepository) GetAssignmentStats(ctx context.Context) (*models.AssignmentStats, error) {
+func (m *mockPullRequestRepository) GetAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 	if m == nil || m.getAssignmentStatsFn == nil {
 		return nil, nil
 	}
-	return m.getAssignmentStatsFn(ctx)
+	return m.getAssignmentStatsFn(ctx, filter)
+}
+
+func (m *mockPullRequestRepository) GetTeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+	stats, err := m.GetAssignmentStats(ctx, filter)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+	return stats.ByTeam, nil
+}
+
+// Потоковые методы мока отдают те же данные, что и соответствующие списочные.
+func (m *mockPullRequestRepository) StreamUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+	stats, err := m.GetAssignmentStats(ctx, filter)
+	if err != nil || stats == nil {
+		return err
+	}
+	for _, stat := range stats.ByUser {
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockPullRequestRepository) StreamPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+	stats, err := m.GetAssignmentStats(ctx, filter)
+	if err != nil || stats == nil {
+		return err
+	}
+	for _, stat := range stats.ByPullRequest {
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockPullRequestRepository) StreamPullRequestsByReviewer(ctx context.Context, reviewerID string, fn func(*models.PullRequest) error) error {
+	prs, err := m.FindPullRequestsByReviewer(ctx, reviewerID)
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs {
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockPullRequestRepository) GetTurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+	if m == nil || m.getTurnaroundStatsFn == nil {
+		return &models.TurnaroundStats{From: filter.From, To: filter.To}, nil
+	}
+	return m.getTurnaroundStatsFn(ctx, filter)
 }
 
 func (m *mockPullRequestRepository) FindOpenPullRequestsByReviewers(ctx context.Context, ids []string) ([]*models.PullRequest, error) {
@@ -59,6 +122,13 @@ func (m *mockPullRequestRepository) FindOpenPullRequestsByReviewers(ctx context.
 	return m.findOpenPullRequestsByReviewerFn(ctx, ids)
 }
 
+func (m *mockPullRequestRepository) CountOpenReviews(ctx context.Context) (map[string]int, error) {
+	if m == nil || m.countOpenReviewsFn == nil {
+		return map[string]int{}, nil
+	}
+	return m.countOpenReviewsFn(ctx)
+}
+
 func (m *mockPullRequestRepository) ApplyBulkTeamReviewerSwaps(ctx context.Context, swaps []models.ReviewerSwap, users []string) error {
 	if m == nil || m.applyBulkTeamReviewerSwapsFn == nil {
 		return nil
@@ -67,40 +137,39 @@ func (m *mockPullRequestRepository) ApplyBulkTeamReviewerSwaps(ctx context.Conte
 }
 
 type mockUserService struct {
-	assignReviewersFn         func(string) []string
-	setActivityFn             func([]string, bool) error
+	assignReviewersFn         func(teamID string, exclude []string, demand ReviewDemand) (*ReviewerSelection, error)
 	getUserTeamFn             func(string) (string, error)
 	findReplacementReviewerFn func(string, []string) (string, error)
 	getTeamFn                 func(context.Context, string) (*models.Team, error)
 	syncUsersActivityFn       func([]string, bool)
+	absentUsersFn             func(time.Time) (map[string]struct{}, error)
 }
 
-func (m *mockUserService) AssignRewiers(teamID string) []string {
+func (m *mockUserService) AssignRewiers(_ context.Context, teamID string, exclude []string, demand ReviewDemand) (*ReviewerSelection, error) {
 	if m == nil || m.assignReviewersFn == nil {
-		return nil
-	}
-	return m.assignReviewersFn(teamID)
-}
-
-func (m *mockUserService) SetActivity(ids []string, status bool) error {
-	if m == nil || m.setActivityFn == nil {
-		return nil
+		return &ReviewerSelection{}, nil
 	}
-	return m.setActivityFn(ids, status)
+	return m.assignReviewersFn(teamID, exclude, demand)
 }
 
-func (m *mockUserService) GetUserTeam(userID string) (string, error) {
+func (m *mockUserService) GetUserTeam(_ context.Context, userID string) (string, error) {
 	if m == nil || m.getUserTeamFn == nil {
 		return "", domain.NewNotFoundError("user")
 	}
 	return m.getUserTeamFn(userID)
 }
 
-func (m *mockUserService) FindReplacementReviewer(teamName string, excludeUserIDs []string) (string, error) {
+func (m *mockUserService) FindReplacementReviewer(_ context.Context, teamName string, excludeUserIDs []string, demand ReviewDemand) (*ReviewerSelection, error) {
 	if m == nil || m.findReplacementReviewerFn == nil {
-		return "", domain.ErrNoCandidate
+		return nil, domain.ErrNoCandidate
+	}
+	id, err := m.findReplacementReviewerFn(teamName, excludeUserIDs)
+	if err != nil {
+		return nil, err
 	}
-	return m.findReplacementReviewerFn(teamName, excludeUserIDs)
+	selection := selectionOf(id)
+	selection.Decision = &models.AssignmentDecision{PullRequestId: demand.PullRequestId, TeamName: teamName, Selected: []string{id}}
+	return selection, nil
 }
 
 func (m *mockUserService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
@@ -110,13 +179,38 @@ func (m *mockUserService) GetTeam(ctx context.Context, teamName string) (*models
 	return m.getTeamFn(ctx, teamName)
 }
 
-func (m *mockUserService) SyncUsersActivity(ids []string, status bool) {
+func (m *mockUserService) SyncUsersActivity(_ context.Context, ids []string, status bool) {
 	if m == nil || m.syncUsersActivityFn == nil {
 		return
 	}
 	m.syncUsersActivityFn(ids, status)
 }
 
+// RankCandidates мока сохраняет порядок и считает всех незагруженными.
+func (m *mockUserService) RankCandidates(_ context.Context, userIDs []string, _ time.Time) ([]ReviewCandidate, error) {
+	candidates := make([]ReviewCandidate, 0, len(userIDs))
+	for _, id := range userIDs {
+		candidates = append(candidates, ReviewCandidate{ReviewerLoad: models.ReviewerLoad{UserId: id}})
+	}
+	return candidates, nil
+}
+
+// selectionOf собирает ReviewerSelection из ID с нулевой нагрузкой до назначения.
+func selectionOf(ids ...string) *ReviewerSelection {
+	selection := &ReviewerSelection{}
+	for _, id := range ids {
+		selection.Reviewers = append(selection.Reviewers, models.ReviewerLoad{UserId: id, OpenReviews: 1})
+	}
+	return selection
+}
+
+func (m *mockUserService) AbsentUsers(_ context.Context, at time.Time) (map[string]struct{}, error) {
+	if m == nil || m.absentUsersFn == nil {
+		return map[string]struct{}{}, nil
+	}
+	return m.absentUsersFn(at)
+}
+
 func TestPullRequestManager_CreatePullRequestSuccess(t *testing.T) {
 	ctx := context.Background()
 	var persisted *models.PullRequest
@@ -126,8 +220,6 @@ func TestPullRequestManager_CreatePullRequestSuccess(t *testing.T) {
 			return nil
 		},
 	}
-	var wg sync.WaitGroup
-	wg.Add(1)
 	userSvc := &mockUserService{
 		getUserTeamFn: func(userID string) (string, error) {
 			if userID != "author-1" {
@@ -135,21 +227,14 @@ func TestPullRequestManager_CreatePullRequestSuccess(t *testing.T) {
 			}
 			return testTeamName, nil
 		},
-		assignReviewersFn: func(teamID string) []string {
+		assignReviewersFn: func(teamID string, exclude []string, demand ReviewDemand) (*ReviewerSelection, error) {
 			if teamID != testTeamName {
 				t.Fatalf("AssignRewiers called with wrong team %s", teamID)
 			}
-			return []string{"rev-1", "rev-2"}
-		},
-		setActivityFn: func(ids []string, status bool) error {
-			defer wg.Done()
-			if status {
-				t.Fatalf(" reviewers should be marked inactive")
-			}
-			if len(ids) != 2 {
-				t.Fatalf("expected two reviewers, got %v", ids)
+			if !reflect.DeepEqual(exclude, []string{"author-1"}) || demand.Count != 2 || demand.Weight != 1 || demand.Author != "author-1" || demand.PullRequestId != "pr-1" {
+				t.Fatalf("expected author excluded and two reviewers requested, got %v/%+v", exclude, demand)
 			}
-			return nil
+			return selectionOf("rev-1", "rev-2"), nil
 		},
 	}
 
@@ -159,10 +244,11 @@ func TestPullRequestManager_CreatePullRequestSuccess(t *testing.T) {
 		PullRequestId:   "pr-1",
 		PullRequestName: "My PR",
 	}
-	pr, err := manager.CreatePullRequest(ctx, req)
+	resp, err := manager.CreatePullRequest(ctx, req)
 	if err != nil {
 		t.Fatalf("CreatePullRequest returned unexpected error: %v", err)
 	}
+	pr := resp.PR
 	if pr.Status != models.PullRequestStatusOPEN {
 		t.Fatalf("expected PR created with OPEN status")
 	}
@@ -172,12 +258,56 @@ func TestPullRequestManager_CreatePullRequestSuccess(t *testing.T) {
 	if len(pr.AssignedReviewers) != 2 {
 		t.Fatalf("expected assigned reviewers propagated, got %v", pr.AssignedReviewers)
 	}
-	wg.Wait()
+	if len(resp.ReviewerLoad) != 2 || resp.ReviewerLoad[0].OpenReviews != 1 || resp.PendingReviewers != 0 {
+		t.Fatalf("expected reviewer load in response, got %+v", resp)
+	}
 	if persisted != pr {
 		t.Fatalf("CreatePullRequest did not save resulting PR")
 	}
 }
 
+func TestPullRequestManager_CreatePullRequestMetadata(t *testing.T) {
+	var demand ReviewDemand
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(_ string, _ []string, d ReviewDemand) (*ReviewerSelection, error) {
+			demand = d
+			return selectionOf("rev-1"), nil
+		},
+	}
+	manager := &PullRequestManager{repo: &mockPullRequestRepository{}, UserService: userSvc}
+
+	resp, err := manager.CreatePullRequest(context.Background(), models.PostPullRequestCreateJSONBody{
+		AuthorId:      "author-1",
+		PullRequestId: "pr-1",
+		LinesAdded:    1500,
+		LinesDeleted:  600,
+		FilesChanged:  12,
+		Priority:      models.PullRequestPriorityURGENT,
+		Labels:        []string{" backend ", "", "hotfix", "backend"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, demand.Count)
+	require.Equal(t, 5, demand.Weight)
+	require.True(t, demand.Urgent)
+	require.Equal(t, []string{"backend", "hotfix"}, resp.PR.Labels)
+	require.Equal(t, models.PullRequestPriorityURGENT, resp.PR.Priority)
+
+	resp, err = manager.CreatePullRequest(context.Background(), models.PostPullRequestCreateJSONBody{AuthorId: "author-1", PullRequestId: "pr-2"})
+	require.NoError(t, err)
+	require.Equal(t, models.PullRequestPriorityNORMAL, resp.PR.Priority)
+	require.Nil(t, resp.PR.Labels)
+
+	for _, req := range []models.PostPullRequestCreateJSONBody{
+		{AuthorId: "author-1", LinesAdded: -1},
+		{AuthorId: "author-1", FilesChanged: -3},
+		{AuthorId: "author-1", Priority: "BLOCKER"},
+	} {
+		_, err := manager.CreatePullRequest(context.Background(), req)
+		require.ErrorIs(t, err, domain.ErrInvalidParam, "request %+v", req)
+	}
+}
+
 func TestPullRequestManager_CreatePullRequestErrors(t *testing.T) {
 	t.Run("user service failure", func(t *testing.T) {
 		userSvc := &mockUserService{
@@ -202,11 +332,8 @@ func TestPullRequestManager_CreatePullRequestErrors(t *testing.T) {
 			getUserTeamFn: func(string) (string, error) {
 				return testTeamName, nil
 			},
-			assignReviewersFn: func(string) []string {
-				return []string{"r1"}
-			},
-			setActivityFn: func([]string, bool) error {
-				return nil
+			assignReviewersFn: func(string, []string, ReviewDemand) (*ReviewerSelection, error) {
+				return selectionOf("r1"), nil
 			},
 		}
 		manager := &PullRequestManager{repo: repo, UserService: userSvc}
@@ -232,22 +359,7 @@ func TestPullRequestManager_MergeSuccess(t *testing.T) {
 			return nil
 		},
 	}
-	var wg sync.WaitGroup
-	wg.Add(1)
-	userSvc := &mockUserService{
-		setActivityFn: func(ids []string, status bool) error {
-			defer wg.Done()
-			if !status {
-				t.Fatalf("reviewers should be marked active on merge")
-			}
-			if len(ids) != 1 || ids[0] != "rev-1" {
-				t.Fatalf("unexpected reviewer ids %v", ids)
-			}
-			return nil
-		},
-	}
-
-	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
 	payload := models.PostPullRequestMergeJSONBody{PullRequestId: "pr-1"}
 	pr, err := manager.Merge(ctx, payload)
 	if err != nil {
@@ -256,7 +368,6 @@ func TestPullRequestManager_MergeSuccess(t *testing.T) {
 	if pr.Status != models.PullRequestStatusMERGED || pr.MergedAt == nil {
 		t.Fatalf("Merge should mark PR as merged")
 	}
-	wg.Wait()
 	if !saved {
 		t.Fatalf("Merge should save merged PR")
 	}
@@ -306,6 +417,7 @@ func TestPullRequestManager_ReassignSuccess(t *testing.T) {
 		getPullRequestFn: func(context.Context, string) (*models.PullRequest, error) {
 			return &models.PullRequest{
 				PullRequestId:     "pr-1",
+				AuthorId:          "author",
 				Status:            models.PullRequestStatusOPEN,
 				AssignedReviewers: []string{"old", "keep"},
 			}, nil
@@ -314,10 +426,6 @@ func TestPullRequestManager_ReassignSuccess(t *testing.T) {
 			return nil
 		},
 	}
-	var wg sync.WaitGroup
-	wg.Add(2)
-	statusCalls := make(map[string]bool)
-	var mu sync.Mutex
 	userSvc := &mockUserService{
 		getUserTeamFn: func(userID string) (string, error) {
 			if userID != "old" {
@@ -337,22 +445,11 @@ func TestPullRequestManager_ReassignSuccess(t *testing.T) {
 				}
 				return false
 			}
-			if !contains("old") || !contains("keep") {
-				t.Fatalf("older reviewers should be excluded")
+			if !contains("old") || !contains("keep") || !contains("author") {
+				t.Fatalf("author and older reviewers should be excluded")
 			}
 			return "new", nil
 		},
-		setActivityFn: func(ids []string, status bool) error {
-			defer wg.Done()
-			if len(ids) != 1 {
-				t.Fatalf("expected per-user activity update, got %v", ids)
-			}
-			mu.Lock()
-			statusCalls[ids[0]] = status
-			mu.Unlock()
-			return nil
-		},
-		assignReviewersFn: func(string) []string { return nil },
 	}
 
 	manager := &PullRequestManager{repo: repo, UserService: userSvc}
@@ -363,11 +460,8 @@ func TestPullRequestManager_ReassignSuccess(t *testing.T) {
 	if resp.ReplacedBy != "new" {
 		t.Fatalf("expected new reviewer, got %s", resp.ReplacedBy)
 	}
-	wg.Wait()
-	mu.Lock()
-	defer mu.Unlock()
-	if !statusCalls["old"] || statusCalls["new"] {
-		t.Fatalf("expected old reviewer reactivated and new reviewer deactivated, got %v", statusCalls)
+	if !reflect.DeepEqual(resp.PR.AssignedReviewers, []string{"keep", "new"}) {
+		t.Fatalf("expected old reviewer replaced, got %v", resp.PR.AssignedReviewers)
 	}
 }
 
@@ -442,12 +536,12 @@ func TestPullRequestManager_AssignmentStats(t *testing.T) {
 		},
 	}
 	repo := &mockPullRequestRepository{
-		getAssignmentStatsFn: func(context.Context) (*models.AssignmentStats, error) {
+		getAssignmentStatsFn: func(context.Context, models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 			return want, nil
 		},
 	}
 	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
-	got, err := manager.AssignmentStats(context.Background())
+	got, err := manager.AssignmentStats(context.Background(), models.AssignmentStatsFilter{})
 	if err != nil {
 		t.Fatalf("AssignmentStats returned error: %v", err)
 	}
@@ -458,16 +552,82 @@ func TestPullRequestManager_AssignmentStats(t *testing.T) {
 
 func TestPullRequestManager_AssignmentStatsError(t *testing.T) {
 	repo := &mockPullRequestRepository{
-		getAssignmentStatsFn: func(context.Context) (*models.AssignmentStats, error) {
+		getAssignmentStatsFn: func(context.Context, models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 			return nil, errors.New("boom")
 		},
 	}
 	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
-	if _, err := manager.AssignmentStats(context.Background()); err == nil || err.Error() != "failed to get assignment stats: boom" {
+	if _, err := manager.AssignmentStats(context.Background(), models.AssignmentStatsFilter{}); err == nil || err.Error() != "failed to get assignment stats: boom" {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestPullRequestManager_TurnaroundStatsCachesByWindow(t *testing.T) {
+	calls := 0
+	var got models.TurnaroundFilter
+	repo := &mockPullRequestRepository{
+		getTurnaroundStatsFn: func(_ context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+			calls++
+			got = filter
+			return &models.TurnaroundStats{From: filter.From, To: filter.To}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
+	manager.ConfigureStats(time.Hour, 7*24*time.Hour)
+
+	to := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if _, err := manager.TurnaroundStats(context.Background(), models.TurnaroundFilter{To: to}); err != nil {
+			t.Fatalf("TurnaroundStats returned error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected cached result, repository called %d times", calls)
+	}
+	if !got.From.Equal(to.Add(-7 * 24 * time.Hour)) {
+		t.Fatalf("expected default window, got from %s", got.From)
+	}
+
+	if _, err := manager.TurnaroundStats(context.Background(), models.TurnaroundFilter{From: to.Add(-time.Hour), To: to}); err != nil {
+		t.Fatalf("TurnaroundStats returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a new window to miss the cache, repository called %d times", calls)
+	}
+}
+
+func TestPullRequestManager_TurnaroundStatsErrors(t *testing.T) {
+	manager := &PullRequestManager{repo: &mockPullRequestRepository{}, UserService: &mockUserService{}}
+	to := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := manager.TurnaroundStats(context.Background(), models.TurnaroundFilter{From: to, To: to}); !errors.Is(err, domain.ErrInvalidParam) {
+		t.Fatalf("expected ErrInvalidParam, got %v", err)
+	}
+
+	manager.repo = &mockPullRequestRepository{
+		getTurnaroundStatsFn: func(context.Context, models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	if _, err := manager.TurnaroundStats(context.Background(), models.TurnaroundFilter{}); err == nil || err.Error() != "failed to get turnaround stats: boom" {
 		t.Fatalf("expected wrapped error, got %v", err)
 	}
 }
 
+func TestPullRequestManager_AssignmentStatsValidatesFilter(t *testing.T) {
+	from := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	filters := map[string]models.AssignmentStatsFilter{
+		"status":   {Status: "CLOSED"},
+		"limit":    {Limit: -1},
+		"from>=to": {From: from, To: from},
+	}
+	for name, filter := range filters {
+		manager := &PullRequestManager{repo: &mockPullRequestRepository{}, UserService: &mockUserService{}}
+		if _, err := manager.AssignmentStats(context.Background(), filter); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("%s: expected ErrInvalidParam, got %v", name, err)
+		}
+	}
+}
+
 func TestPullRequestManager_ListForReviewer(t *testing.T) {
 	repo := &mockPullRequestRepository{
 		findPullRequestsByReviewerFn: func(context.Context, string) ([]*models.PullRequest, error) {
@@ -488,7 +648,7 @@ func TestPullRequestManager_ListForReviewer(t *testing.T) {
 		},
 	}
 	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
-	res, err := manager.ListForReviewer(context.Background(), "rev")
+	res, err := manager.ListForReviewer(context.Background(), "rev", "")
 	if err != nil {
 		t.Fatalf("ListForReviewer returned error: %v", err)
 	}
@@ -517,7 +677,7 @@ func TestPullRequestManager_BulkDeactivateTeamMembers(t *testing.T) {
 			},
 			applyBulkTeamReviewerSwapsFn: func(ctx context.Context, swaps []models.ReviewerSwap, users []string) error {
 				require.Equal(t, []models.ReviewerSwap{{PullRequestId: "pr-1", OldUserId: "u1", NewUserId: "u2"}}, swaps)
-				require.ElementsMatch(t, []string{"u1", "u2"}, users)
+				require.Equal(t, []string{"u1"}, users, "only targets are deactivated")
 				return nil
 			},
 		}
@@ -550,8 +710,60 @@ func TestPullRequestManager_BulkDeactivateTeamMembers(t *testing.T) {
 		require.Len(t, result.Reassignments, 1)
 		require.Equal(t, "pr-1", result.Reassignments[0].PullRequestId)
 		require.Equal(t, "u2", result.Reassignments[0].Replacements[0].NewUserId)
-		require.Len(t, synced, 1)
-		require.ElementsMatch(t, []string{"u1", "u2"}, synced[0])
+		require.Equal(t, [][]string{{"u1"}}, synced)
+	})
+
+	t.Run("affinity rules", func(t *testing.T) {
+		var applied []models.ReviewerSwap
+		repo := &mockPullRequestRepository{
+			findOpenPullRequestsByReviewerFn: func(context.Context, []string) ([]*models.PullRequest, error) {
+				return []*models.PullRequest{
+					{PullRequestId: "pr-1", AuthorId: "author", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u1"}},
+				}, nil
+			},
+			applyBulkTeamReviewerSwapsFn: func(_ context.Context, swaps []models.ReviewerSwap, _ []string) error {
+				applied = swaps
+				return nil
+			},
+		}
+		userSvc := &mockUserService{
+			getTeamFn: func(context.Context, string) (*models.Team, error) {
+				return &models.Team{TeamName: "backend", Members: []models.TeamMember{
+					{UserId: "author", IsActive: true},
+					{UserId: "u1", IsActive: true},
+					{UserId: "u2", IsActive: true},
+					{UserId: "u3", IsActive: true},
+				}}, nil
+			},
+		}
+		rules := []models.AffinityRule{
+			{RuleId: 1, Kind: models.AffinityRuleNEVERPAIR, UserId: "author", WithUserIds: []string{"u2"}},
+		}
+		prm := &PullRequestManager{repo: repo, UserService: userSvc}
+		prm.SetAffinityRules(affinityLookupFunc(func(context.Context, string) ([]models.AffinityRule, error) {
+			return rules, nil
+		}))
+
+		if _, err := prm.BulkDeactivateTeamMembers(ctx, "backend", []string{"u1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		require.Equal(t, []models.ReviewerSwap{{PullRequestId: "pr-1", OldUserId: "u1", NewUserId: "u3"}}, applied,
+			"u2 never reviews the author's PRs")
+
+		// u3 ревьюит только вместе с u2, а u2 автору запрещён: замены нет, и ответ объясняет почему.
+		rules = append(rules, models.AffinityRule{RuleId: 2, Kind: models.AffinityRuleREQUIREPAIR, UserId: "u3", WithUserIds: []string{"u2"}})
+		applied = nil
+		_, err := prm.BulkDeactivateTeamMembers(ctx, "backend", []string{"u1"})
+		var rejected *domain.RejectedCandidatesError
+		if !errors.As(err, &rejected) {
+			t.Fatalf("expected rejected candidates error, got %v", err)
+		}
+		require.Equal(t, "pr-1", rejected.PullRequestID)
+		require.Equal(t, []string{
+			"u2: rule 1: author and [u2] never review each other's PRs",
+			"u3: rule 2: u3 reviews only together with one of [u2], and no such co-reviewer is available",
+		}, rejected.Reasons)
+		require.Nil(t, applied)
 	})
 
 	t.Run("deactivate without open prs", func(t *testing.T) {
@@ -626,3 +838,197 @@ func TestPullRequestManager_BulkDeactivateTeamMembers(t *testing.T) {
 		}
 	})
 }
+
+func TestPullRequestManager_ReleaseReviews(t *testing.T) {
+	repo := &mockPullRequestRepository{
+		findOpenPullRequestsByReviewerFn: func(ctx context.Context, ids []string) ([]*models.PullRequest, error) {
+			require.Equal(t, []string{"u1"}, ids)
+			return []*models.PullRequest{
+				{PullRequestId: "pr-1", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u1"}},
+			}, nil
+		},
+		applyBulkTeamReviewerSwapsFn: func(ctx context.Context, swaps []models.ReviewerSwap, users []string) error {
+			require.Equal(t, []models.ReviewerSwap{{PullRequestId: "pr-1", OldUserId: "u1", NewUserId: "u3"}}, swaps)
+			require.Empty(t, users, "absence keeps everyone active")
+			return nil
+		},
+	}
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return "backend", nil },
+		getTeamFn: func(ctx context.Context, teamName string) (*models.Team, error) {
+			return &models.Team{
+				TeamName: "backend",
+				Members: []models.TeamMember{
+					{UserId: "u1", IsActive: true},
+					{UserId: "u2", IsActive: true},
+					{UserId: "u3", IsActive: true},
+				},
+			}, nil
+		},
+		absentUsersFn: func(time.Time) (map[string]struct{}, error) {
+			return map[string]struct{}{"u1": {}, "u2": {}}, nil
+		},
+		syncUsersActivityFn: func(ids []string, status bool) {
+			t.Fatalf("absence must not change activity, got %v=%v", ids, status)
+		},
+	}
+
+	prm := &PullRequestManager{repo: repo, UserService: userSvc}
+	reassignments, err := prm.ReleaseReviews(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	require.Len(t, reassignments, 1)
+	require.Equal(t, "u3", reassignments[0].Replacements[0].NewUserId)
+}
+
+type recordingMetrics struct {
+	mu          sync.Mutex
+	created     int
+	merged      int
+	reassigned  int
+	swaps       int
+	noCandidate []string
+}
+
+func (m *recordingMetrics) PullRequestCreated() { m.mu.Lock(); m.created++; m.mu.Unlock() }
+func (m *recordingMetrics) PullRequestMerged()  { m.mu.Lock(); m.merged++; m.mu.Unlock() }
+func (m *recordingMetrics) ReviewerReassigned() { m.mu.Lock(); m.reassigned++; m.mu.Unlock() }
+func (m *recordingMetrics) BulkReviewerSwaps(n int) {
+	m.mu.Lock()
+	m.swaps += n
+	m.mu.Unlock()
+}
+func (m *recordingMetrics) NoCandidate(op string) {
+	m.mu.Lock()
+	m.noCandidate = append(m.noCandidate, op)
+	m.mu.Unlock()
+}
+
+func TestPullRequestManager_RecordsMetrics(t *testing.T) {
+	ctx := context.Background()
+	stored := map[string]*models.PullRequest{}
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			stored[pr.PullRequestId] = pr
+			return nil
+		},
+		getPullRequestFn: func(_ context.Context, id string) (*models.PullRequest, error) {
+			pr, ok := stored[id]
+			if !ok {
+				return nil, domain.NewNotFoundError("pull request")
+			}
+			return pr, nil
+		},
+	}
+	replacement := "rev-3"
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(string, []string, ReviewDemand) (*ReviewerSelection, error) {
+			return selectionOf("rev-1", "rev-2"), nil
+		},
+		findReplacementReviewerFn: func(string, []string) (string, error) {
+			if replacement == "" {
+				return "", domain.ErrNoCandidate
+			}
+			return replacement, nil
+		},
+	}
+	rec := &recordingMetrics{}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetMetrics(rec)
+
+	_, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", AuthorId: "author"})
+	require.NoError(t, err)
+	_, err = manager.Reassign(ctx, "rev-1", "pr-1")
+	require.NoError(t, err)
+
+	replacement = ""
+	_, err = manager.Reassign(ctx, "rev-2", "pr-1")
+	require.ErrorIs(t, err, domain.ErrNoCandidate)
+
+	_, err = manager.Merge(ctx, models.PostPullRequestMergeJSONBody{PullRequestId: "pr-1"})
+	require.NoError(t, err)
+	_, err = manager.Merge(ctx, models.PostPullRequestMergeJSONBody{PullRequestId: "pr-1"})
+	require.NoError(t, err)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	require.Equal(t, 1, rec.created)
+	require.Equal(t, 1, rec.reassigned)
+	require.Equal(t, 1, rec.merged, "idempotent merge must not be counted twice")
+	require.Equal(t, []string{OperationReassign}, rec.noCandidate)
+}
+
+func TestPullRequestManager_OpenReviewsByUser(t *testing.T) {
+	repo := &mockPullRequestRepository{
+		getAssignmentStatsFn: func(context.Context, models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
+			t.Fatal("open reviews must not compute full assignment stats")
+			return nil, nil
+		},
+		countOpenReviewsFn: func(context.Context) (map[string]int, error) {
+			return map[string]int{"u1": 2, "u2": 1, "u3": 0}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
+
+	load, err := manager.OpenReviewsByUser(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"u1": 2, "u2": 1, "u3": 0}, load)
+
+	repo.countOpenReviewsFn = func(context.Context) (map[string]int, error) { return nil, errors.New("db down") }
+	_, err = manager.OpenReviewsByUser(context.Background())
+	require.ErrorContains(t, err, "db down")
+}
+
+func TestPullRequestManager_OpenReviewsByOrganization(t *testing.T) {
+	repo := &mockPullRequestRepository{
+		countOpenReviewsFn: func(ctx context.Context) (map[string]int, error) {
+			switch tenant.Organization(ctx) {
+			case "broken":
+				return nil, errors.New("db down")
+			case "payments":
+				return map[string]int{"u1": 1}, nil
+			}
+			return map[string]int{"u1": 0}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
+
+	load, err := manager.OpenReviewsByOrganization(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{models.DefaultOrganization: {"u1": 0}}, load, "without a lister only default is counted")
+
+	orgs := newMockOrganizationRepository()
+	orgs.orgs = append(orgs.orgs, models.Organization{OrganizationId: "payments"})
+	manager.SetOrganizations(orgs)
+	load, err = manager.OpenReviewsByOrganization(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{models.DefaultOrganization: {"u1": 0}, "payments": {"u1": 1}}, load)
+
+	orgs.orgs = append(orgs.orgs, models.Organization{OrganizationId: "broken"})
+	_, err = manager.OpenReviewsByOrganization(context.Background())
+	require.ErrorContains(t, err, "organization broken")
+}
+
+func TestPullRequestManager_SpansRecordErrors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	repo := &mockPullRequestRepository{
+		getPullRequestFn: func(context.Context, string) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestId: "pr", Status: models.PullRequestStatusMERGED}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
+
+	_, err := manager.Reassign(context.Background(), "u1", "pr")
+	require.ErrorIs(t, err, domain.ErrPRMerged)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "PullRequestManager.Reassign", spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// RepositoryStore хранит репозитории PR.
+type RepositoryStore interface {
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	// SaveRepository создаёт репозиторий или заменяет его владельца и правила ревью.
+	SaveRepository(ctx context.Context, repo *models.Repository) error
+	ListRepositories(ctx context.Context) ([]models.Repository, error)
+	RepositoryLookup
+}
+
+// RepositoryLookup возвращает репозиторий по имени; ErrNotFound, если его нет.
+type RepositoryLookup interface {
+	GetRepository(ctx context.Context, name string) (*models.Repository, error)
+}
+
+// RepositoryManager настраивает репозитории: команду-владельца и число ревьюеров.
+type RepositoryManager struct {
+	repo RepositoryStore
+}
+
+// NewRepositoryManager создаёт менеджер репозиториев.
+func NewRepositoryManager(repo RepositoryStore) *RepositoryManager {
+	return &RepositoryManager{repo: repo}
+}
+
+// SetRepository проверяет и сохраняет репозиторий; 0 ревьюеров означает значение по умолчанию.
+func (rm *RepositoryManager) SetRepository(ctx context.Context, req models.Repository) (_ *models.Repository, err error) {
+	ctx, span := tracer.Start(ctx, "RepositoryManager.SetRepository")
+	defer func() { endSpan(span, err) }()
+
+	req.RepositoryName = strings.TrimSpace(req.RepositoryName)
+	req.OwnerTeam = strings.TrimSpace(req.OwnerTeam)
+	if req.RepositoryName == "" {
+		return nil, domain.NewInvalidParamError("repository_name", "is required")
+	}
+	if strings.ContainsAny(req.RepositoryName, "# \t") {
+		return nil, domain.NewInvalidParamError("repository_name", "must not contain '#' or spaces")
+	}
+	if req.RequiredReviewers < 0 || req.RequiredReviewers > reviewersPerPullRequest {
+		return nil, domain.NewInvalidParamError("required_reviewers", fmt.Sprintf("must be between 1 and %d", reviewersPerPullRequest))
+	}
+	if req.RequiredReviewers == 0 {
+		req.RequiredReviewers = reviewersPerPullRequest
+	}
+	if req.OwnerTeam != "" {
+		if _, err := rm.repo.GetTeam(ctx, req.OwnerTeam); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.NewNotFoundError("team")
+			}
+			return nil, fmt.Errorf("failed to get team: %w", err)
+		}
+	}
+
+	if err := rm.repo.SaveRepository(ctx, &req); err != nil {
+		return nil, fmt.Errorf("failed to save repository: %w", err)
+	}
+	return &req, nil
+}
+
+// Repository возвращает репозиторий по имени; NOT_FOUND, если его нет.
+func (rm *RepositoryManager) Repository(ctx context.Context, name string) (_ *models.Repository, err error) {
+	ctx, span := tracer.Start(ctx, "RepositoryManager.Repository")
+	defer func() { endSpan(span, err) }()
+
+	repo, err := rm.repo.GetRepository(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("repository")
+		}
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	return repo, nil
+}
+
+// Repositories возвращает все репозитории по имени.
+func (rm *RepositoryManager) Repositories(ctx context.Context) (_ []models.Repository, err error) {
+	ctx, span := tracer.Start(ctx, "RepositoryManager.Repositories")
+	defer func() { endSpan(span, err) }()
+
+	repos, err := rm.repo.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	return repos, nil
+}
+
+// reviewRules — откуда и сколько ревьюеров назначать на PR.
+type reviewRules struct {
+	team      string
+	reviewers int
+}
+
+// reviewRulesFor применяет правила репозитория: ревьюеры берутся из команды-владельца,
+// а без владельца — из команды автора authorTeam.
+func reviewRulesFor(repo *models.Repository, authorTeam string) reviewRules {
+	rules := reviewRules{team: authorTeam, reviewers: reviewersPerPullRequest}
+	if repo == nil {
+		return rules
+	}
+	if repo.OwnerTeam != "" {
+		rules.team = repo.OwnerTeam
+	}
+	if repo.RequiredReviewers > 0 {
+		rules.reviewers = min(repo.RequiredReviewers, reviewersPerPullRequest)
+	}
+	return rules
+}
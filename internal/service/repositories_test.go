@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type mockRepositoryStore struct {
+	teams map[string]bool
+	repos map[string]models.Repository
+}
+
+func newMockRepositoryStore(teams ...string) *mockRepositoryStore {
+	m := &mockRepositoryStore{teams: map[string]bool{}, repos: map[string]models.Repository{
+		models.DefaultRepository: {RepositoryName: models.DefaultRepository, RequiredReviewers: reviewersPerPullRequest},
+	}}
+	for _, name := range teams {
+		m.teams[name] = true
+	}
+	return m
+}
+
+func (m *mockRepositoryStore) GetTeam(_ context.Context, teamName string) (*models.Team, error) {
+	if !m.teams[teamName] {
+		return nil, domain.NewNotFoundError("team " + teamName)
+	}
+	return &models.Team{TeamName: teamName}, nil
+}
+
+func (m *mockRepositoryStore) SaveRepository(_ context.Context, repo *models.Repository) error {
+	m.repos[repo.RepositoryName] = *repo
+	return nil
+}
+
+func (m *mockRepositoryStore) GetRepository(_ context.Context, name string) (*models.Repository, error) {
+	repo, ok := m.repos[name]
+	if !ok {
+		return nil, domain.NewNotFoundError("repository " + name)
+	}
+	return &repo, nil
+}
+
+func (m *mockRepositoryStore) ListRepositories(context.Context) ([]models.Repository, error) {
+	result := make([]models.Repository, 0, len(m.repos))
+	for _, repo := range m.repos {
+		result = append(result, repo)
+	}
+	slices.SortFunc(result, func(a, b models.Repository) int { return strings.Compare(a.RepositoryName, b.RepositoryName) })
+	return result, nil
+}
+
+func TestRepositoryManager_SetRepository(t *testing.T) {
+	ctx := context.Background()
+	manager := NewRepositoryManager(newMockRepositoryStore("backend"))
+
+	repo, err := manager.SetRepository(ctx, models.Repository{RepositoryName: " search-api ", OwnerTeam: "backend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.Repository{RepositoryName: "search-api", OwnerTeam: "backend", RequiredReviewers: reviewersPerPullRequest}
+	if *repo != want {
+		t.Fatalf("expected %+v, got %+v", want, *repo)
+	}
+
+	repos, err := manager.Repositories(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 2 || repos[1] != want {
+		t.Fatalf("expected default and search-api, got %+v", repos)
+	}
+
+	for _, req := range []models.Repository{
+		{},
+		{RepositoryName: "search api"},
+		{RepositoryName: "search#api"},
+		{RepositoryName: "search-api", RequiredReviewers: 3},
+		{RepositoryName: "search-api", RequiredReviewers: -1},
+	} {
+		if _, err := manager.SetRepository(ctx, req); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("expected invalid param for %+v, got %v", req, err)
+		}
+	}
+	if _, err := manager.SetRepository(ctx, models.Repository{RepositoryName: "web", OwnerTeam: "ghost"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for missing owner team, got %v", err)
+	}
+	if _, err := manager.Repository(ctx, "web"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for missing repository, got %v", err)
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestUsesRepositoryRules(t *testing.T) {
+	ctx := context.Background()
+	store := newMockRepositoryStore("platform")
+	store.repos["search-api"] = models.Repository{RepositoryName: "search-api", OwnerTeam: "platform", RequiredReviewers: 1}
+
+	var team string
+	var demand ReviewDemand
+	var saved *models.PullRequest
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(teamName string, _ []string, d ReviewDemand) (*ReviewerSelection, error) {
+			team, demand = teamName, d
+			return selectionOf("rev-1"), nil
+		},
+	}
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			saved = pr
+			return nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetRepositories(store)
+
+	resp, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{
+		AuthorId: "author-1", Repository: "search-api", Number: 12, PullRequestName: "Add search",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if team != "platform" || demand.Count != 1 {
+		t.Fatalf("expected one reviewer from the owner team, got team %q and %+v", team, demand)
+	}
+	if resp.PR.PullRequestId != "search-api#12" || saved.Repository != "search-api" || saved.Number != 12 {
+		t.Fatalf("expected PR search-api#12, got %+v", saved)
+	}
+
+	// Без владельца ревьюеры берутся из команды автора.
+	if _, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{AuthorId: "author-1", PullRequestId: "pr-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if team != testTeamName || demand.Count != reviewersPerPullRequest || saved.Repository != models.DefaultRepository {
+		t.Fatalf("expected default rules, got team %q, %+v and repository %q", team, demand, saved.Repository)
+	}
+
+	for _, req := range []models.PostPullRequestCreateJSONBody{
+		{AuthorId: "author-1"},
+		{AuthorId: "author-1", Number: -1},
+		{AuthorId: "author-1", Repository: "search-api", Number: 12, PullRequestId: "pr-12"},
+		// PR репозитория без номера не создаётся: его идентичность — пара (repository, number).
+		{AuthorId: "author-1", Repository: "search-api", PullRequestId: "pr-13"},
+		// Произвольный идентификатор не может занять ключ PR с номером.
+		{AuthorId: "author-1", PullRequestId: "search-api#13"},
+	} {
+		if _, err := manager.CreatePullRequest(ctx, req); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("expected invalid param for %+v, got %v", req, err)
+		}
+	}
+
+	// Одинаковые номера в разных репозиториях дают разные PR.
+	store.repos["billing"] = models.Repository{RepositoryName: "billing", RequiredReviewers: 1}
+	resp, err = manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{AuthorId: "author-1", Repository: "billing", Number: 12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PR.PullRequestId != "billing#12" || saved.Repository != "billing" || saved.Number != 12 {
+		t.Fatalf("expected PR billing#12, got %+v", saved)
+	}
+
+	_, err = manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{AuthorId: "author-1", Repository: "ghost", Number: 1})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for missing repository, got %v", err)
+	}
+}
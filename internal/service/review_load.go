@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// ReviewLoadLookup возвращает число и суммарный вес открытых ревью и личный лимит (0 — не задан) перечисленных пользователей.
+type ReviewLoadLookup interface {
+	FindReviewLoad(ctx context.Context, userIDs []string) ([]models.ReviewerLoad, error)
+}
+
+// CapacityRepository хранит личные лимиты открытых ревью.
+type CapacityRepository interface {
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	// SaveCapacity создаёт или заменяет личный лимит пользователя.
+	SaveCapacity(ctx context.Context, userID string, maxOpenReviews int) error
+	// DeleteCapacity удаляет личный лимит; отсутствие лимита ошибкой не считается.
+	DeleteCapacity(ctx context.Context, userID string) error
+	ReviewLoadLookup
+}
+
+// ReviewQueueRepository хранит PR, которым не хватило ревьюеров из-за лимитов.
+type ReviewQueueRepository interface {
+	// EnqueueReview ставит PR в очередь или обновляет число недостающих ревьюеров, сохраняя место в очереди.
+	EnqueueReview(ctx context.Context, item models.QueuedReview) error
+	ListQueuedReviews(ctx context.Context) ([]models.QueuedReview, error)
+	// DequeueReview убирает PR из очереди; если его там нет, ничего не делает.
+	DequeueReview(ctx context.Context, prID string) error
+}
+
+// ReviewQueueDrainer назначает ревьюеров PR из очереди, когда у коллег освобождается лимит.
+type ReviewQueueDrainer interface {
+	DrainReviewQueue(ctx context.Context) (int, error)
+}
+
+// ReviewCandidate — кандидат в ревьюеры с текущей нагрузкой.
+type ReviewCandidate struct {
+	models.ReviewerLoad
+	// OffHours — у кандидата сейчас нерабочее время.
+	OffHours bool
+}
+
+// before задаёт порядок предпочтения: сначала те, у кого рабочее время, затем наименее загруженные.
+func (c ReviewCandidate) before(other ReviewCandidate) bool {
+	if c.OffHours != other.OffHours {
+		return !c.OffHours
+	}
+	return c.lessLoaded(other)
+}
+
+// lessLoaded сравнивает по весу открытых ревью, затем по их числу,
+// при равенстве — по user_id, чтобы выбор был детерминированным.
+func (c ReviewCandidate) lessLoaded(other ReviewCandidate) bool {
+	if c.WeightedLoad != other.WeightedLoad {
+		return c.WeightedLoad < other.WeightedLoad
+	}
+	if c.OpenReviews != other.OpenReviews {
+		return c.OpenReviews < other.OpenReviews
+	}
+	return c.UserId < other.UserId
+}
+
+// ReviewDemand описывает, сколько ревьюеров нужно PR и насколько он тяжёл.
+type ReviewDemand struct {
+	// PullRequestId, Author и Reviewers нужны правилам подбора: Reviewers — ревьюеры, которые остаются на PR.
+	PullRequestId string
+	Author        string
+	Reviewers     []string
+
+	Count int
+	// Weight — вес PR в нагрузке ревьюера, см. models.PullRequest.ReviewWeight.
+	Weight int
+	// Urgent — срочный PR: рабочее время не учитывается, а вместо очереди берутся наименее загруженные
+	// ревьюеры даже сверх лимита.
+	Urgent bool
+}
+
+// demandFor строит потребность PR в count ревьюерах.
+func demandFor(pr *models.PullRequest, count int) ReviewDemand {
+	return ReviewDemand{
+		PullRequestId: pr.PullRequestId,
+		Author:        pr.AuthorId,
+		Reviewers:     pr.AssignedReviewers,
+		Count:         count,
+		Weight:        pr.ReviewWeight(),
+		Urgent:        pr.Priority == models.PullRequestPriorityURGENT,
+	}
+}
+
+// ReviewerSelection — результат подбора ревьюеров.
+type ReviewerSelection struct {
+	// Reviewers — выбранные ревьюеры в порядке предпочтения с нагрузкой, учитывающей новое назначение.
+	Reviewers []models.ReviewerLoad
+	// Pending — сколько ревьюеров не набралось из-за того, что у подходящих кандидатов исчерпан лимит.
+	Pending int
+	// Decision объясняет выбор: кого рассматривали и почему остальные не выбраны.
+	Decision *models.AssignmentDecision
+}
+
+// ReviewerIDs возвращает идентификаторы выбранных ревьюеров.
+func (s *ReviewerSelection) ReviewerIDs() []string {
+	ids := make([]string, 0, len(s.Reviewers))
+	for _, r := range s.Reviewers {
+		ids = append(ids, r.UserId)
+	}
+	return ids
+}
+
+// effectiveLoad подставляет лимит по умолчанию пользователям без личного лимита.
+func effectiveLoad(load models.ReviewerLoad, defaultMax int) models.ReviewerLoad {
+	if load.MaxOpenReviews == 0 {
+		load.MaxOpenReviews = defaultMax
+	}
+	return load
+}
+
+// reviewerPool раздаёт ревьюеров с учётом лимитов: каждый раз выбирается лучший по before кандидат
+// с запасом лимита, и его нагрузка сразу увеличивается.
+type reviewerPool struct {
+	candidates []ReviewCandidate
+	// urgent — выбирать наименее загруженных без учёта рабочего времени и, если свободных нет, сверх лимита.
+	urgent bool
+}
+
+// newReviewerPool создаёт пул из кандидатов без дублей и пустых ID.
+func newReviewerPool(candidates []ReviewCandidate) *reviewerPool {
+	seen := make(map[string]struct{}, len(candidates))
+	pool := &reviewerPool{candidates: make([]ReviewCandidate, 0, len(candidates))}
+	for _, c := range candidates {
+		if c.UserId == "" {
+			continue
+		}
+		if _, exists := seen[c.UserId]; exists {
+			continue
+		}
+		seen[c.UserId] = struct{}{}
+		pool.candidates = append(pool.candidates, c)
+	}
+	return pool
+}
+
+// take выбирает следующего ревьюера вне exclude и возвращает его нагрузку с учётом нового назначения весом weight.
+func (p *reviewerPool) take(exclude map[string]struct{}, weight int) (models.ReviewerLoad, bool) {
+	return p.takeIf(exclude, weight, nil)
+}
+
+// takeIf работает как take, но рассматривает только кандидатов, для которых allow вернул true; nil — всех.
+func (p *reviewerPool) takeIf(exclude map[string]struct{}, weight int, allow func(ReviewCandidate) bool) (models.ReviewerLoad, bool) {
+	if p == nil {
+		return models.ReviewerLoad{}, false
+	}
+	best := p.best(exclude, false, allow)
+	if best < 0 && p.urgent {
+		best = p.best(exclude, true, allow)
+	}
+	if best < 0 {
+		return models.ReviewerLoad{}, false
+	}
+	p.candidates[best].OpenReviews++
+	p.candidates[best].WeightedLoad += weight
+	return p.candidates[best].ReviewerLoad, true
+}
+
+// available сообщает, есть ли в пуле кандидат вне exclude, которого можно назначить и для которого match вернул true.
+func (p *reviewerPool) available(exclude map[string]struct{}, match func(ReviewCandidate) bool) bool {
+	if p == nil {
+		return false
+	}
+	return p.best(exclude, p.urgent, match) >= 0
+}
+
+// best возвращает индекс лучшего кандидата вне exclude, прошедшего allow, или -1;
+// overCapacity разрешает кандидатов на лимите.
+func (p *reviewerPool) best(exclude map[string]struct{}, overCapacity bool, allow func(ReviewCandidate) bool) int {
+	best := -1
+	for i, c := range p.candidates {
+		if c.AtCapacity() && !overCapacity {
+			continue
+		}
+		if _, conflict := exclude[c.UserId]; conflict {
+			continue
+		}
+		if allow != nil && !allow(c) {
+			continue
+		}
+		if best < 0 || p.less(c, p.candidates[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// less задаёт порядок выбора в пуле.
+func (p *reviewerPool) less(a, b ReviewCandidate) bool {
+	if p.urgent {
+		return a.lessLoaded(b)
+	}
+	return a.before(b)
+}
+
+// saturated возвращает число кандидатов с исчерпанным лимитом.
+func (p *reviewerPool) saturated() int {
+	n := 0
+	for _, c := range p.candidates {
+		if c.AtCapacity() {
+			n++
+		}
+	}
+	return n
+}
+
+// sortCandidates упорядочивает кандидатов по before.
+func sortCandidates(candidates []ReviewCandidate) {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].before(candidates[j]) })
+}
+
+// CapacityManager настраивает лимиты открытых ревью и показывает нагрузку ревьюеров.
+type CapacityManager struct {
+	repo       CapacityRepository
+	queue      ReviewQueueDrainer
+	defaultMax int
+}
+
+// NewCapacityManager создаёт менеджер лимитов; defaultMax действует для пользователей без личного лимита (0 — без лимита).
+// После изменения лимита queue получает шанс назначить ревьюеров ждущим PR; queue может быть nil.
+func NewCapacityManager(repo CapacityRepository, queue ReviewQueueDrainer, defaultMax int) *CapacityManager {
+	return &CapacityManager{repo: repo, queue: queue, defaultMax: defaultMax}
+}
+
+// SetCapacity задаёт личный лимит пользователя; 0 сбрасывает его к лимиту по умолчанию.
+func (cm *CapacityManager) SetCapacity(ctx context.Context, req models.PostUsersSetCapacityJSONBody) (_ *models.ReviewerLoad, err error) {
+	ctx, span := tracer.Start(ctx, "CapacityManager.SetCapacity")
+	defer func() { endSpan(span, err) }()
+
+	if req.MaxOpenReviews < 0 {
+		return nil, domain.NewInvalidParamError("max_open_reviews", "must not be negative")
+	}
+	if err := cm.ensureUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if req.MaxOpenReviews == 0 {
+		err = cm.repo.DeleteCapacity(ctx, req.UserId)
+	} else {
+		err = cm.repo.SaveCapacity(ctx, req.UserId, req.MaxOpenReviews)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save capacity: %w", err)
+	}
+
+	// Лимит уже сохранён: ошибка разбора очереди не отменяет изменение, очередь разберётся при следующем освобождении.
+	if cm.queue != nil {
+		if _, err := cm.queue.DrainReviewQueue(ctx); err != nil {
+			slog.WarnContext(ctx, "review queue drain failed", "user_id", req.UserId, "err", err.Error())
+		}
+	}
+	return cm.load(ctx, req.UserId)
+}
+
+// ReviewerLoad возвращает число открытых ревью пользователя и действующий лимит.
+func (cm *CapacityManager) ReviewerLoad(ctx context.Context, userID string) (_ *models.ReviewerLoad, err error) {
+	ctx, span := tracer.Start(ctx, "CapacityManager.ReviewerLoad")
+	defer func() { endSpan(span, err) }()
+
+	if err := cm.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return cm.load(ctx, userID)
+}
+
+// load читает нагрузку пользователя и подставляет лимит по умолчанию.
+func (cm *CapacityManager) load(ctx context.Context, userID string) (*models.ReviewerLoad, error) {
+	loads, err := cm.repo.FindReviewLoad(ctx, []string{userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review load: %w", err)
+	}
+	if len(loads) == 0 {
+		return nil, domain.NewNotFoundError("user")
+	}
+	load := effectiveLoad(loads[0], cm.defaultMax)
+	return &load, nil
+}
+
+// ensureUser возвращает NOT_FOUND, если пользователя нет.
+func (cm *CapacityManager) ensureUser(ctx context.Context, userID string) error {
+	if _, err := cm.repo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("user")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// reviewLoadLookupFunc адаптирует функцию к ReviewLoadLookup.
+type reviewLoadLookupFunc func(ctx context.Context, userIDs []string) ([]models.ReviewerLoad, error)
+
+func (f reviewLoadLookupFunc) FindReviewLoad(ctx context.Context, userIDs []string) ([]models.ReviewerLoad, error) {
+	return f(ctx, userIDs)
+}
+
+// staticLoad возвращает lookup, отдающий нагрузку из карты; пользователи без записи считаются незагруженными.
+func staticLoad(loads map[string]models.ReviewerLoad) ReviewLoadLookup {
+	return reviewLoadLookupFunc(func(_ context.Context, ids []string) ([]models.ReviewerLoad, error) {
+		result := make([]models.ReviewerLoad, 0, len(ids))
+		for _, id := range ids {
+			load := loads[id]
+			load.UserId = id
+			result = append(result, load)
+		}
+		return result, nil
+	})
+}
+
+func newLoadedUserManager(defaultMax int) *UserManager {
+	manager := NewUserManager(nil)
+	for _, id := range []string{"author", "u1", "u2", "u3", "u4"} {
+		defaultCache(manager)[id] = &models.User{UserId: id, TeamName: "alpha", IsActive: true}
+	}
+	manager.SetReviewLoad(staticLoad(map[string]models.ReviewerLoad{
+		"u1": {OpenReviews: 3},
+		"u2": {OpenReviews: 1},
+		"u3": {OpenReviews: 1, MaxOpenReviews: 1},
+		"u4": {OpenReviews: 1},
+	}), defaultMax)
+	return manager
+}
+
+func TestUserManager_AssignRewiersPrefersLeastLoaded(t *testing.T) {
+	manager := newLoadedUserManager(0)
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author"}, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// u3 на лимите, у u2 и u4 нагрузка равна — при равенстве решает user_id.
+	want := []models.ReviewerLoad{
+		{UserId: "u2", OpenReviews: 2, WeightedLoad: 1},
+		{UserId: "u4", OpenReviews: 2, WeightedLoad: 1},
+	}
+	if !reflect.DeepEqual(selection.Reviewers, want) || selection.Pending != 0 {
+		t.Fatalf("expected %v without pending, got %+v", want, selection)
+	}
+}
+
+func TestUserManager_AssignRewiersReportsPendingOverCapacity(t *testing.T) {
+	// По умолчанию не больше двух открытых ревью: u1 и u3 заняты.
+	manager := newLoadedUserManager(2)
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author", "u2"}, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"u4"}) || selection.Pending != 1 {
+		t.Fatalf("expected u4 and one pending reviewer, got %+v", selection)
+	}
+	if selection.Reviewers[0].MaxOpenReviews != 2 {
+		t.Fatalf("expected default capacity in reviewer load, got %+v", selection.Reviewers[0])
+	}
+
+	// Не хватает людей, а не лимита: в очередь ставить нечего.
+	defaultCache(manager)["u1"].IsActive = false
+	defaultCache(manager)["u3"].IsActive = false
+	selection, err = manager.AssignRewiers(context.Background(), "alpha", []string{"author", "u2"}, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil || selection.Pending != 0 {
+		t.Fatalf("expected no pending reviewers for a small team, got %+v (err=%v)", selection, err)
+	}
+}
+
+func TestUserManager_AssignRewiersWeighsPullRequestSize(t *testing.T) {
+	manager := NewUserManager(nil)
+	for _, id := range []string{"small", "large"} {
+		defaultCache(manager)[id] = &models.User{UserId: id, TeamName: "alpha", IsActive: true}
+	}
+	// Одно большое ревью весит больше трёх маленьких.
+	manager.SetReviewLoad(staticLoad(map[string]models.ReviewerLoad{
+		"small": {OpenReviews: 3, WeightedLoad: 3},
+		"large": {OpenReviews: 1, WeightedLoad: 5},
+	}), 0)
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", nil, ReviewDemand{Count: 1, Weight: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []models.ReviewerLoad{{UserId: "small", OpenReviews: 4, WeightedLoad: 7}}
+	if !reflect.DeepEqual(selection.Reviewers, want) {
+		t.Fatalf("expected %v, got %+v", want, selection.Reviewers)
+	}
+}
+
+func TestUserManager_AssignRewiersRoutesUrgentOverCapacity(t *testing.T) {
+	manager := newLoadedUserManager(1)
+
+	// Свободен только u3 с личным лимитом в одно ревью — он уже занят; срочный PR не ждёт очереди.
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", []string{"author", "u4"}, ReviewDemand{Count: 2, Weight: 1, Urgent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := selection.ReviewerIDs(); !reflect.DeepEqual(ids, []string{"u2", "u3"}) || selection.Pending != 0 {
+		t.Fatalf("expected least loaded u2 and u3 without pending, got %+v", selection)
+	}
+
+	selection, err = manager.AssignRewiers(context.Background(), "alpha", []string{"author", "u4"}, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selection.Reviewers) != 0 || selection.Pending != 2 {
+		t.Fatalf("expected a regular PR to wait for capacity, got %+v", selection)
+	}
+}
+
+func TestUserManager_FindReplacementSkipsFullReviewers(t *testing.T) {
+	manager := newLoadedUserManager(2)
+
+	if repl, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"author"}, ReviewDemand{Count: 1, Weight: 1}); err != nil || replacedBy(repl) != "u2" {
+		t.Fatalf("expected u2, got %s (err=%v)", replacedBy(repl), err)
+	}
+	if _, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"author", "u2", "u4"}, ReviewDemand{Count: 1, Weight: 1}); !errors.Is(err, domain.ErrNoCandidate) {
+		t.Fatalf("reviewers at capacity must not be picked, got %v", err)
+	}
+}
+
+func TestReviewerPoolRespectsCapacity(t *testing.T) {
+	pool := newReviewerPool([]ReviewCandidate{
+		{ReviewerLoad: models.ReviewerLoad{UserId: "b", MaxOpenReviews: 1}},
+		{ReviewerLoad: models.ReviewerLoad{UserId: "a", MaxOpenReviews: 2}},
+		{ReviewerLoad: models.ReviewerLoad{UserId: "a", MaxOpenReviews: 2}},
+	})
+
+	var taken []string
+	for {
+		load, ok := pool.take(nil, 1)
+		if !ok {
+			break
+		}
+		taken = append(taken, load.UserId)
+	}
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(taken, want) {
+		t.Fatalf("expected %v, got %v", want, taken)
+	}
+}
+
+type mockReviewQueue struct {
+	items map[string]models.QueuedReview
+}
+
+func (m *mockReviewQueue) EnqueueReview(_ context.Context, item models.QueuedReview) error {
+	m.items[item.PullRequestId] = item
+	return nil
+}
+
+func (m *mockReviewQueue) ListQueuedReviews(context.Context) ([]models.QueuedReview, error) {
+	result := make([]models.QueuedReview, 0, len(m.items))
+	for _, item := range m.items {
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (m *mockReviewQueue) DequeueReview(_ context.Context, prID string) error {
+	delete(m.items, prID)
+	return nil
+}
+
+func TestPullRequestManager_QueuesAndDrainsReviews(t *testing.T) {
+	ctx := context.Background()
+	stored := map[string]*models.PullRequest{
+		"pr-0": {PullRequestId: "pr-0", AuthorId: "u9", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u2"}},
+	}
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			stored[pr.PullRequestId] = pr
+			return nil
+		},
+		getPullRequestFn: func(_ context.Context, id string) (*models.PullRequest, error) {
+			pr, ok := stored[id]
+			if !ok {
+				return nil, domain.NewNotFoundError("pull request")
+			}
+			return pr, nil
+		},
+	}
+	free := []string{"u1"}
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(_ string, exclude []string, demand ReviewDemand) (*ReviewerSelection, error) {
+			selection := &ReviewerSelection{}
+			for _, id := range free {
+				if len(selection.Reviewers) < demand.Count && !slices.Contains(exclude, id) {
+					selection.Reviewers = append(selection.Reviewers, models.ReviewerLoad{UserId: id, OpenReviews: 1, MaxOpenReviews: 1})
+				}
+			}
+			selection.Pending = demand.Count - len(selection.Reviewers)
+			return selection, nil
+		},
+	}
+	queue := &mockReviewQueue{items: map[string]models.QueuedReview{}}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetReviewQueue(queue)
+
+	resp, err := manager.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", AuthorId: "author"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PendingReviewers != 1 || queue.items["pr-1"].Missing != 1 {
+		t.Fatalf("expected one reviewer queued, got %+v / %+v", resp, queue.items)
+	}
+
+	// Слияние pr-0 освобождает u2, и он достаётся PR из очереди.
+	free = []string{"u1", "u2"}
+	if _, err := manager.Merge(ctx, models.PostPullRequestMergeJSONBody{PullRequestId: "pr-0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := stored["pr-1"].AssignedReviewers; !reflect.DeepEqual(got, []string{"u1", "u2"}) {
+		t.Fatalf("expected queued PR to receive u2, got %v", got)
+	}
+	if len(queue.items) != 0 {
+		t.Fatalf("expected queue drained, got %+v", queue.items)
+	}
+}
+
+func TestPullRequestManager_DrainReviewQueueByPriority(t *testing.T) {
+	ctx := context.Background()
+	stored := map[string]*models.PullRequest{
+		"pr-low":    {PullRequestId: "pr-low", AuthorId: "author", Status: models.PullRequestStatusOPEN, Priority: models.PullRequestPriorityLOW},
+		"pr-normal": {PullRequestId: "pr-normal", AuthorId: "author", Status: models.PullRequestStatusOPEN},
+		"pr-high":   {PullRequestId: "pr-high", AuthorId: "author", Status: models.PullRequestStatusOPEN, Priority: models.PullRequestPriorityHIGH, LinesAdded: 500},
+	}
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			stored[pr.PullRequestId] = pr
+			return nil
+		},
+		getPullRequestFn: func(_ context.Context, id string) (*models.PullRequest, error) {
+			return stored[id], nil
+		},
+	}
+	// Свободно одно место: его получает самый приоритетный PR, хотя в очередь он встал последним.
+	free := 1
+	var weights []int
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(_ string, _ []string, demand ReviewDemand) (*ReviewerSelection, error) {
+			if free == 0 {
+				return &ReviewerSelection{Pending: demand.Count}, nil
+			}
+			free--
+			weights = append(weights, demand.Weight)
+			return selectionOf("u1"), nil
+		},
+	}
+	queue := &orderedReviewQueue{}
+	for _, id := range []string{"pr-low", "pr-normal", "pr-high"} {
+		_ = queue.EnqueueReview(ctx, models.QueuedReview{PullRequestId: id, Missing: 1})
+	}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetReviewQueue(queue)
+
+	if n, err := manager.DrainReviewQueue(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one reviewer assigned, got %d (err=%v)", n, err)
+	}
+	if got := stored["pr-high"].AssignedReviewers; !reflect.DeepEqual(got, []string{"u1"}) {
+		t.Fatalf("expected the HIGH priority PR to be served first, got %v", got)
+	}
+	if !reflect.DeepEqual(weights, []int{3}) {
+		t.Fatalf("expected PR weight passed to assignment, got %v", weights)
+	}
+	if ids := queue.ids(); !reflect.DeepEqual(ids, []string{"pr-low", "pr-normal"}) {
+		t.Fatalf("expected remaining queue order preserved, got %v", ids)
+	}
+}
+
+// orderedReviewQueue хранит очередь в порядке постановки, как настоящие хранилища.
+type orderedReviewQueue struct {
+	items []models.QueuedReview
+}
+
+func (q *orderedReviewQueue) EnqueueReview(_ context.Context, item models.QueuedReview) error {
+	for i := range q.items {
+		if q.items[i].PullRequestId == item.PullRequestId {
+			q.items[i].Missing = item.Missing
+			return nil
+		}
+	}
+	q.items = append(q.items, item)
+	return nil
+}
+
+func (q *orderedReviewQueue) ListQueuedReviews(context.Context) ([]models.QueuedReview, error) {
+	return slices.Clone(q.items), nil
+}
+
+func (q *orderedReviewQueue) DequeueReview(_ context.Context, prID string) error {
+	q.items = slices.DeleteFunc(q.items, func(item models.QueuedReview) bool { return item.PullRequestId == prID })
+	return nil
+}
+
+func (q *orderedReviewQueue) ids() []string {
+	ids := make([]string, 0, len(q.items))
+	for _, item := range q.items {
+		ids = append(ids, item.PullRequestId)
+	}
+	return ids
+}
+
+type mockCapacityRepository struct {
+	users      map[string]bool
+	capacities map[string]int
+}
+
+func (m *mockCapacityRepository) GetUser(_ context.Context, userID string) (*models.User, error) {
+	if !m.users[userID] {
+		return nil, domain.NewNotFoundError("user " + userID)
+	}
+	return &models.User{UserId: userID}, nil
+}
+
+func (m *mockCapacityRepository) SaveCapacity(_ context.Context, userID string, maxOpenReviews int) error {
+	m.capacities[userID] = maxOpenReviews
+	return nil
+}
+
+func (m *mockCapacityRepository) DeleteCapacity(_ context.Context, userID string) error {
+	delete(m.capacities, userID)
+	return nil
+}
+
+func (m *mockCapacityRepository) FindReviewLoad(_ context.Context, userIDs []string) ([]models.ReviewerLoad, error) {
+	var result []models.ReviewerLoad
+	for _, id := range userIDs {
+		if m.users[id] {
+			result = append(result, models.ReviewerLoad{UserId: id, OpenReviews: 2, MaxOpenReviews: m.capacities[id]})
+		}
+	}
+	return result, nil
+}
+
+type countingDrainer struct{ calls int }
+
+func (d *countingDrainer) DrainReviewQueue(context.Context) (int, error) {
+	d.calls++
+	return 0, nil
+}
+
+func TestCapacityManager_SetCapacity(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockCapacityRepository{users: map[string]bool{"u1": true}, capacities: map[string]int{}}
+	drainer := &countingDrainer{}
+	cm := NewCapacityManager(repo, drainer, 5)
+
+	if _, err := cm.SetCapacity(ctx, models.PostUsersSetCapacityJSONBody{UserId: "u1", MaxOpenReviews: -1}); !errors.Is(err, domain.ErrInvalidParam) {
+		t.Fatalf("expected INVALID_PARAM, got %v", err)
+	}
+	if _, err := cm.SetCapacity(ctx, models.PostUsersSetCapacityJSONBody{UserId: "ghost", MaxOpenReviews: 1}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+
+	load, err := cm.SetCapacity(ctx, models.PostUsersSetCapacityJSONBody{UserId: "u1", MaxOpenReviews: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *load != (models.ReviewerLoad{UserId: "u1", OpenReviews: 2, MaxOpenReviews: 3}) || drainer.calls != 1 {
+		t.Fatalf("expected personal capacity and a queue drain, got %+v (drains=%d)", load, drainer.calls)
+	}
+
+	load, err = cm.SetCapacity(ctx, models.PostUsersSetCapacityJSONBody{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if load.MaxOpenReviews != 5 || len(repo.capacities) != 0 {
+		t.Fatalf("expected reset to the default capacity, got %+v", load)
+	}
+}
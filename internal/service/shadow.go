@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// LearnerLookup возвращает стажёров команды, из которых назначаются теневые ревьюеры.
+type LearnerLookup interface {
+	// FindTeamLearners возвращает стажёров команды по user_id с числом их открытых теневых ревью.
+	FindTeamLearners(ctx context.Context, teamName string) ([]models.Learner, error)
+}
+
+// LearnerRepository хранит стажёров команд.
+type LearnerRepository interface {
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	// SetTeamLearners заменяет список стажёров команды.
+	SetTeamLearners(ctx context.Context, teamName string, userIDs []string) error
+	LearnerLookup
+}
+
+// LearnerManager управляет стажёрами команд.
+type LearnerManager struct {
+	repo LearnerRepository
+}
+
+// NewLearnerManager создаёт менеджер стажёров.
+func NewLearnerManager(repo LearnerRepository) *LearnerManager {
+	return &LearnerManager{repo: repo}
+}
+
+// SetLearners заменяет список стажёров команды; стажёры должны состоять в ней.
+func (lm *LearnerManager) SetLearners(ctx context.Context, req models.PostTeamSetLearnersJSONBody) (_ *models.TeamLearners, err error) {
+	ctx, span := tracer.Start(ctx, "LearnerManager.SetLearners")
+	defer func() { endSpan(span, err) }()
+
+	teamName := strings.TrimSpace(req.TeamName)
+	team, err := lm.team(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []string
+	for _, id := range req.UserIds {
+		id = strings.TrimSpace(id)
+		if id == "" || slices.Contains(userIDs, id) {
+			continue
+		}
+		if !slices.ContainsFunc(team.Members, func(m models.TeamMember) bool { return m.UserId == id }) {
+			return nil, domain.NewInvalidParamError("user_ids", fmt.Sprintf("user %s is not a member of team %s", id, teamName))
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	if err := lm.repo.SetTeamLearners(ctx, teamName, userIDs); err != nil {
+		return nil, fmt.Errorf("failed to set team learners: %w", err)
+	}
+	return lm.learners(ctx, teamName)
+}
+
+// Learners возвращает стажёров команды с числом их открытых теневых ревью.
+func (lm *LearnerManager) Learners(ctx context.Context, teamName string) (_ *models.TeamLearners, err error) {
+	ctx, span := tracer.Start(ctx, "LearnerManager.Learners")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := lm.team(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return lm.learners(ctx, teamName)
+}
+
+// team возвращает команду; NOT_FOUND, если её нет.
+func (lm *LearnerManager) team(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := lm.repo.GetTeam(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("team")
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	return team, nil
+}
+
+func (lm *LearnerManager) learners(ctx context.Context, teamName string) (*models.TeamLearners, error) {
+	learners, err := lm.repo.FindTeamLearners(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team learners: %w", err)
+	}
+	return &models.TeamLearners{TeamName: teamName, Learners: learners}, nil
+}
+
+// pickShadowReviewers возвращает теневого ревьюера для нового PR или nil. Теневой ревьюер необязателен,
+// поэтому ошибка подбора только журналируется и не мешает создать PR.
+func (prm *PullRequestManager) pickShadowReviewers(ctx context.Context, teamName string, pr *models.PullRequest) []string {
+	if prm.learners == nil {
+		return nil
+	}
+	shadow, err := prm.findShadowReviewer(ctx, teamName, pr)
+	if err != nil {
+		slog.WarnContext(ctx, "shadow reviewer not assigned", "pull_request_id", pr.PullRequestId, "err", err.Error())
+		return nil
+	}
+	if shadow == "" {
+		return nil
+	}
+	return []string{shadow}
+}
+
+// findShadowReviewer выбирает среди стажёров команды активного и присутствующего участника, который не автор
+// и не ревьюер PR, с наименьшим числом открытых теневых ревью; при равенстве — с меньшим user_id.
+func (prm *PullRequestManager) findShadowReviewer(ctx context.Context, teamName string, pr *models.PullRequest) (string, error) {
+	learners, err := prm.learners.FindTeamLearners(ctx, teamName)
+	if err != nil {
+		return "", fmt.Errorf("find team learners: %w", err)
+	}
+	if len(learners) == 0 {
+		return "", nil
+	}
+	team, err := prm.UserService.GetTeam(ctx, teamName)
+	if err != nil {
+		return "", fmt.Errorf("get team: %w", err)
+	}
+	absent, err := prm.UserService.AbsentUsers(ctx, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("find absent users: %w", err)
+	}
+	active := make(map[string]struct{}, len(team.Members))
+	for _, member := range team.Members {
+		if member.IsActive {
+			active[member.UserId] = struct{}{}
+		}
+	}
+
+	var best *models.Learner
+	for i, learner := range learners {
+		if _, ok := active[learner.UserId]; !ok {
+			continue
+		}
+		if _, away := absent[learner.UserId]; away {
+			continue
+		}
+		if learner.UserId == pr.AuthorId || slices.Contains(pr.AssignedReviewers, learner.UserId) {
+			continue
+		}
+		if best == nil || learner.OpenShadowReviews < best.OpenShadowReviews ||
+			learner.OpenShadowReviews == best.OpenShadowReviews && learner.UserId < best.UserId {
+			best = &learners[i]
+		}
+	}
+	if best == nil {
+		return "", nil
+	}
+	return best.UserId, nil
+}
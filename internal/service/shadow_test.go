@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// learnerLookupFunc адаптирует функцию к LearnerLookup.
+type learnerLookupFunc func(ctx context.Context, teamName string) ([]models.Learner, error)
+
+func (f learnerLookupFunc) FindTeamLearners(ctx context.Context, teamName string) ([]models.Learner, error) {
+	return f(ctx, teamName)
+}
+
+// newShadowManager собирает менеджер PR, у которого автор author-1 и ревьюер rev-1 из команды testTeamName.
+func newShadowManager(learners LearnerLookup) (*PullRequestManager, *models.PullRequest) {
+	var persisted models.PullRequest
+	repo := &mockPullRequestRepository{
+		savePullRequestFn: func(_ context.Context, pr *models.PullRequest) error {
+			persisted = *pr
+			return nil
+		},
+	}
+	members := []models.TeamMember{{UserId: "author-1", IsActive: true}, {UserId: "rev-1", IsActive: true}, {UserId: "inactive"}}
+	for _, id := range []string{"absent", "busy", "mentee", "zed"} {
+		members = append(members, models.TeamMember{UserId: id, IsActive: true})
+	}
+	userSvc := &mockUserService{
+		getUserTeamFn: func(string) (string, error) { return testTeamName, nil },
+		assignReviewersFn: func(string, []string, ReviewDemand) (*ReviewerSelection, error) {
+			return selectionOf("rev-1"), nil
+		},
+		getTeamFn: func(_ context.Context, teamName string) (*models.Team, error) {
+			return &models.Team{TeamName: teamName, Members: members}, nil
+		},
+		absentUsersFn: func(time.Time) (map[string]struct{}, error) {
+			return map[string]struct{}{"absent": {}}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: userSvc}
+	manager.SetShadowReviews(learners)
+	return manager, &persisted
+}
+
+func createShadowPR(t *testing.T, manager *PullRequestManager) *models.PullRequest {
+	t.Helper()
+	resp, err := manager.CreatePullRequest(context.Background(), models.PostPullRequestCreateJSONBody{
+		AuthorId: "author-1", PullRequestId: "pr-1", PullRequestName: "My PR",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest returned unexpected error: %v", err)
+	}
+	return resp.PR
+}
+
+func TestPullRequestManager_CreatePullRequestAssignsShadow(t *testing.T) {
+	manager, persisted := newShadowManager(learnerLookupFunc(func(_ context.Context, teamName string) ([]models.Learner, error) {
+		if teamName != testTeamName {
+			t.Fatalf("unexpected team %s", teamName)
+		}
+		return []models.Learner{
+			{UserId: "absent"},
+			{UserId: "author-1"},
+			{UserId: "busy", OpenShadowReviews: 3},
+			{UserId: "inactive"},
+			{UserId: "rev-1"},
+			{UserId: "zed", OpenShadowReviews: 1},
+			{UserId: "mentee", OpenShadowReviews: 1},
+		}, nil
+	}))
+
+	pr := createShadowPR(t, manager)
+	if !reflect.DeepEqual(pr.AssignedReviewers, []string{"rev-1"}) {
+		t.Fatalf("shadow must not take a reviewer slot, got %v", pr.AssignedReviewers)
+	}
+	if !reflect.DeepEqual(pr.ShadowReviewers, []string{"mentee"}) {
+		t.Fatalf("expected the least busy learner, got %v", pr.ShadowReviewers)
+	}
+	if !reflect.DeepEqual(persisted.ShadowReviewers, []string{"mentee"}) {
+		t.Fatalf("shadow reviewer must be saved, got %v", persisted.ShadowReviewers)
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestWithoutShadow(t *testing.T) {
+	for name, learners := range map[string]LearnerLookup{
+		"disabled": nil,
+		"no candidates": learnerLookupFunc(func(context.Context, string) ([]models.Learner, error) {
+			return []models.Learner{{UserId: "author-1"}, {UserId: "absent"}}, nil
+		}),
+		"lookup error": learnerLookupFunc(func(context.Context, string) ([]models.Learner, error) {
+			return nil, errors.New("boom")
+		}),
+	} {
+		manager, _ := newShadowManager(learners)
+		if pr := createShadowPR(t, manager); len(pr.ShadowReviewers) != 0 {
+			t.Fatalf("%s: expected no shadow reviewer, got %v", name, pr.ShadowReviewers)
+		}
+	}
+}
+
+func TestPullRequestManager_ListForReviewerRole(t *testing.T) {
+	repo := &mockPullRequestRepository{
+		findPullRequestsByReviewerFn: func(context.Context, string) ([]*models.PullRequest, error) {
+			return []*models.PullRequest{
+				{PullRequestId: "pr-1", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"rev"}},
+				{PullRequestId: "pr-2", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"other"}, ShadowReviewers: []string{"rev"}},
+			}, nil
+		},
+	}
+	manager := &PullRequestManager{repo: repo, UserService: &mockUserService{}}
+	res, err := manager.ListForReviewer(context.Background(), "rev", "")
+	if err != nil {
+		t.Fatalf("ListForReviewer returned error: %v", err)
+	}
+	if len(res) != 2 || res[0].Role != models.ReviewerRoleREVIEWER || res[1].Role != models.ReviewerRoleSHADOW {
+		t.Fatalf("unexpected roles: %+v", res)
+	}
+}
+
+// mockLearnerRepository хранит стажёров по командам; open задаёт число открытых теневых ревью.
+type mockLearnerRepository struct {
+	teams    map[string][]string
+	learners map[string][]string
+	open     map[string]int
+}
+
+func (m *mockLearnerRepository) GetTeam(_ context.Context, teamName string) (*models.Team, error) {
+	ids, ok := m.teams[teamName]
+	if !ok {
+		return nil, domain.NewNotFoundError("team")
+	}
+	team := &models.Team{TeamName: teamName}
+	for _, id := range ids {
+		team.Members = append(team.Members, models.TeamMember{UserId: id, IsActive: true})
+	}
+	return team, nil
+}
+
+func (m *mockLearnerRepository) SetTeamLearners(_ context.Context, teamName string, userIDs []string) error {
+	m.learners[teamName] = userIDs
+	return nil
+}
+
+func (m *mockLearnerRepository) FindTeamLearners(_ context.Context, teamName string) ([]models.Learner, error) {
+	ids := slices.Sorted(slices.Values(m.learners[teamName]))
+	result := make([]models.Learner, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, models.Learner{UserId: id, OpenShadowReviews: m.open[id]})
+	}
+	return result, nil
+}
+
+func TestLearnerManager_SetLearners(t *testing.T) {
+	repo := &mockLearnerRepository{
+		teams:    map[string][]string{"alpha": {"u1", "u2", "u3"}},
+		learners: map[string][]string{},
+		open:     map[string]int{"u2": 2},
+	}
+	manager := NewLearnerManager(repo)
+	ctx := context.Background()
+
+	got, err := manager.SetLearners(ctx, models.PostTeamSetLearnersJSONBody{TeamName: " alpha ", UserIds: []string{"u2", " u1 ", "", "u2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &models.TeamLearners{TeamName: "alpha", Learners: []models.Learner{{UserId: "u1"}, {UserId: "u2", OpenShadowReviews: 2}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := manager.SetLearners(ctx, models.PostTeamSetLearnersJSONBody{TeamName: "alpha", UserIds: []string{"u3", "ghost"}}); !errors.Is(err, domain.ErrInvalidParam) {
+		t.Fatalf("expected invalid param for a stranger, got %v", err)
+	}
+	if !reflect.DeepEqual(repo.learners["alpha"], []string{"u2", "u1"}) {
+		t.Fatalf("invalid list must not be stored, got %v", repo.learners["alpha"])
+	}
+	if _, err := manager.SetLearners(ctx, models.PostTeamSetLearnersJSONBody{TeamName: "beta"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected team not found, got %v", err)
+	}
+
+	if _, err := manager.SetLearners(ctx, models.PostTeamSetLearnersJSONBody{TeamName: "alpha"}); err != nil {
+		t.Fatalf("clearing learners failed: %v", err)
+	}
+	got, err = manager.Learners(ctx, "alpha")
+	if err != nil || len(got.Learners) != 0 {
+		t.Fatalf("expected no learners, got %+v (err=%v)", got, err)
+	}
+	if _, err := manager.Learners(ctx, "beta"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected team not found, got %v", err)
+	}
+}
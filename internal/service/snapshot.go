@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+const (
+	// maxSnapshotProblems ограничивает число нарушений целостности в тексте ошибки.
+	maxSnapshotProblems = 20
+	// maxAssignedReviewers повторяет ограничение хранилищ на число ревьюверов PR.
+	maxAssignedReviewers = 2
+)
+
+// SnapshotRepository выгружает и восстанавливает полное состояние хранилища.
+type SnapshotRepository interface {
+	ExportSnapshot(ctx context.Context) (*models.Snapshot, error)
+	// RestoreSnapshot загружает архив одной транзакцией и возвращает NOT_EMPTY, если в хранилище уже есть данные.
+	RestoreSnapshot(ctx context.Context, snap *models.Snapshot) error
+}
+
+// SnapshotManager создаёт архивы состояния сервиса и восстанавливает их с проверкой целостности.
+type SnapshotManager struct {
+	repo  SnapshotRepository
+	users *UserManager
+}
+
+// NewSnapshotManager создаёт менеджер архивов; users (может быть nil) получает восстановленных пользователей в кэш.
+func NewSnapshotManager(repo SnapshotRepository, users *UserManager) *SnapshotManager {
+	return &SnapshotManager{repo: repo, users: users}
+}
+
+// Export возвращает архив текущего состояния с отметкой времени создания.
+func (sm *SnapshotManager) Export(ctx context.Context) (_ *models.Snapshot, err error) {
+	ctx, span := tracer.Start(ctx, "SnapshotManager.Export")
+	defer func() { endSpan(span, err) }()
+
+	snap, err := sm.repo.ExportSnapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export snapshot: %w", err)
+	}
+	snap.CreatedAt = time.Now().UTC()
+	return snap, nil
+}
+
+// Restore проверяет архив и загружает его в пустое хранилище.
+func (sm *SnapshotManager) Restore(ctx context.Context, snap *models.Snapshot) (_ models.SnapshotCounts, err error) {
+	ctx, span := tracer.Start(ctx, "SnapshotManager.Restore")
+	defer func() { endSpan(span, err) }()
+
+	if snap == nil {
+		return models.SnapshotCounts{}, domain.NewInvalidParamError("snapshot", "is empty")
+	}
+	if err := ValidateSnapshot(snap); err != nil {
+		return models.SnapshotCounts{}, err
+	}
+
+	if err := sm.repo.RestoreSnapshot(ctx, snap); err != nil {
+		return models.SnapshotCounts{}, fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	if sm.users != nil {
+		sm.users.mu.Lock()
+		cache := sm.users.userCache(ctx)
+		for _, user := range snap.Users {
+			userCopy := user
+			cache[userCopy.UserId] = &userCopy
+		}
+		sm.users.mu.Unlock()
+	}
+	return snap.Counts(), nil
+}
+
+// ValidateSnapshot проверяет версию архива и ссылочную целостность: уникальность ключей,
+// существование команд, авторов и ревьюверов, статусы PR, лимит ревьюверов, репозитории и номера PR в них, PR истории замен, периоды отсутствия, рабочее время,
+// личные лимиты, очередь на ревьюверов, правила подбора, стажёров команд и решения о назначении.
+func ValidateSnapshot(snap *models.Snapshot) error {
+	if snap.Version != models.SnapshotVersion {
+		return domain.NewInvalidParamError("snapshot", fmt.Sprintf("version %d is not supported, expected %d", snap.Version, models.SnapshotVersion))
+	}
+
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	teams := make(map[string]struct{}, len(snap.Teams))
+	for i, team := range snap.Teams {
+		switch _, dup := teams[team.TeamName]; {
+		case team.TeamName == "":
+			problem("teams[%d]: team_name is empty", i)
+		case dup:
+			problem("teams[%d]: duplicate team %s", i, team.TeamName)
+		}
+		teams[team.TeamName] = struct{}{}
+	}
+
+	users := make(map[string]struct{}, len(snap.Users))
+	for i, user := range snap.Users {
+		switch _, dup := users[user.UserId]; {
+		case user.UserId == "":
+			problem("users[%d]: user_id is empty", i)
+		case dup:
+			problem("users[%d]: duplicate user %s", i, user.UserId)
+		}
+		users[user.UserId] = struct{}{}
+		if _, ok := teams[user.TeamName]; user.TeamName != "" && !ok {
+			problem("users[%d]: unknown team %s", i, user.TeamName)
+		}
+	}
+
+	repositories := make(map[string]struct{}, len(snap.Repositories))
+	for i, repo := range snap.Repositories {
+		switch _, dup := repositories[repo.RepositoryName]; {
+		case repo.RepositoryName == "":
+			problem("repositories[%d]: repository_name is empty", i)
+		case dup:
+			problem("repositories[%d]: duplicate repository %s", i, repo.RepositoryName)
+		}
+		repositories[repo.RepositoryName] = struct{}{}
+		if _, ok := teams[repo.OwnerTeam]; repo.OwnerTeam != "" && !ok {
+			problem("repositories[%d]: unknown owner team %s", i, repo.OwnerTeam)
+		}
+		if repo.RequiredReviewers < 1 || repo.RequiredReviewers > reviewersPerPullRequest {
+			problem("repositories[%d]: required_reviewers must be between 1 and %d", i, reviewersPerPullRequest)
+		}
+	}
+
+	prs := make(map[string]struct{}, len(snap.PullRequests))
+	numbers := make(map[string]struct{}, len(snap.PullRequests))
+	for i, pr := range snap.PullRequests {
+		if pr == nil {
+			problem("pull_requests[%d]: is null", i)
+			continue
+		}
+		switch _, dup := prs[pr.PullRequestId]; {
+		case pr.PullRequestId == "":
+			problem("pull_requests[%d]: pull_request_id is empty", i)
+		case dup:
+			problem("pull_requests[%d]: duplicate pull request %s", i, pr.PullRequestId)
+		}
+		prs[pr.PullRequestId] = struct{}{}
+
+		// Репозиторий default создаётся миграцией и в архиве может отсутствовать.
+		if name := models.RepositoryOrDefault(pr.Repository); name != models.DefaultRepository {
+			if _, ok := repositories[name]; !ok {
+				problem("pull_requests[%d]: unknown repository %s", i, name)
+			}
+		}
+		if pr.Number > 0 {
+			key := models.PullRequestKey(pr.Repository, pr.Number)
+			if _, dup := numbers[key]; dup {
+				problem("pull_requests[%d]: duplicate number %s", i, key)
+			}
+			numbers[key] = struct{}{}
+		}
+		if _, ok := users[pr.AuthorId]; !ok {
+			problem("pull_requests[%d]: unknown author %s", i, pr.AuthorId)
+		}
+		if pr.Status != models.PullRequestStatusOPEN && pr.Status != models.PullRequestStatusMERGED {
+			problem("pull_requests[%d]: unknown status %q", i, pr.Status)
+		}
+		if len(pr.AssignedReviewers) > maxAssignedReviewers {
+			problem("pull_requests[%d]: %d reviewers, at most %d allowed", i, len(pr.AssignedReviewers), maxAssignedReviewers)
+		}
+		reviewers := make(map[string]struct{}, len(pr.AssignedReviewers))
+		for _, r := range pr.AssignedReviewers {
+			if _, ok := users[r]; !ok {
+				problem("pull_requests[%d]: unknown reviewer %s", i, r)
+			}
+			if _, dup := reviewers[r]; dup {
+				problem("pull_requests[%d]: duplicate reviewer %s", i, r)
+			}
+			reviewers[r] = struct{}{}
+		}
+		for _, r := range pr.ShadowReviewers {
+			if _, ok := users[r]; !ok {
+				problem("pull_requests[%d]: unknown shadow reviewer %s", i, r)
+			}
+			if _, dup := reviewers[r]; dup {
+				problem("pull_requests[%d]: duplicate reviewer %s", i, r)
+			}
+			reviewers[r] = struct{}{}
+		}
+	}
+
+	for i, r := range snap.ReviewRotations {
+		if _, ok := prs[r.PullRequestId]; !ok {
+			problem("review_rotations[%d]: unknown pull request %s", i, r.PullRequestId)
+		}
+	}
+	for i, a := range snap.Absences {
+		if _, ok := users[a.UserId]; !ok {
+			problem("absences[%d]: unknown user %s", i, a.UserId)
+		}
+		if !a.EndsAt.After(a.StartsAt) {
+			problem("absences[%d]: ends_at must be after starts_at", i)
+		}
+	}
+	withHours := make(map[string]struct{}, len(snap.WorkingHours))
+	for i, wh := range snap.WorkingHours {
+		if _, dup := withHours[wh.UserId]; dup {
+			problem("working_hours[%d]: duplicate user %s", i, wh.UserId)
+		}
+		withHours[wh.UserId] = struct{}{}
+		if _, ok := users[wh.UserId]; !ok {
+			problem("working_hours[%d]: unknown user %s", i, wh.UserId)
+		}
+		if _, err := parseSchedule(wh); err != nil {
+			problem("working_hours[%d]: %s", i, strings.TrimPrefix(err.Error(), domain.ErrInvalidParam.Error()+": "))
+		}
+	}
+	withCapacity := make(map[string]struct{}, len(snap.ReviewCapacities))
+	for i, c := range snap.ReviewCapacities {
+		if _, dup := withCapacity[c.UserId]; dup {
+			problem("review_capacities[%d]: duplicate user %s", i, c.UserId)
+		}
+		withCapacity[c.UserId] = struct{}{}
+		if _, ok := users[c.UserId]; !ok {
+			problem("review_capacities[%d]: unknown user %s", i, c.UserId)
+		}
+		if c.MaxOpenReviews <= 0 {
+			problem("review_capacities[%d]: max_open_reviews must be positive", i)
+		}
+	}
+	queued := make(map[string]struct{}, len(snap.ReviewQueue))
+	for i, item := range snap.ReviewQueue {
+		if _, dup := queued[item.PullRequestId]; dup {
+			problem("review_queue[%d]: duplicate pull request %s", i, item.PullRequestId)
+		}
+		queued[item.PullRequestId] = struct{}{}
+		if _, ok := prs[item.PullRequestId]; !ok {
+			problem("review_queue[%d]: unknown pull request %s", i, item.PullRequestId)
+		}
+		if item.Missing <= 0 {
+			problem("review_queue[%d]: missing must be positive", i)
+		}
+	}
+	for i, r := range snap.AffinityRules {
+		if !r.Kind.Valid() {
+			problem("affinity_rules[%d]: unknown kind %q", i, r.Kind)
+		}
+		if _, ok := teams[r.TeamName]; !ok {
+			problem("affinity_rules[%d]: unknown team %s", i, r.TeamName)
+		}
+		if len(r.WithUserIds) == 0 {
+			problem("affinity_rules[%d]: with_user_ids is empty", i)
+		}
+		for _, id := range append([]string{r.UserId}, r.WithUserIds...) {
+			if _, ok := users[id]; !ok {
+				problem("affinity_rules[%d]: unknown user %s", i, id)
+			}
+		}
+	}
+	learners := make(map[models.SnapshotLearner]struct{}, len(snap.TeamLearners))
+	for i, l := range snap.TeamLearners {
+		if _, dup := learners[l]; dup {
+			problem("team_learners[%d]: duplicate learner %s of team %s", i, l.UserId, l.TeamName)
+		}
+		learners[l] = struct{}{}
+		if _, ok := teams[l.TeamName]; !ok {
+			problem("team_learners[%d]: unknown team %s", i, l.TeamName)
+		}
+		if _, ok := users[l.UserId]; !ok {
+			problem("team_learners[%d]: unknown user %s", i, l.UserId)
+		}
+	}
+	for i, d := range snap.AssignmentDecisions {
+		if _, ok := prs[d.PullRequestId]; !ok {
+			problem("assignment_decisions[%d]: unknown pull request %s", i, d.PullRequestId)
+		}
+		if !d.Operation.Valid() {
+			problem("assignment_decisions[%d]: unknown operation %q", i, d.Operation)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxSnapshotProblems {
+		problems = append(problems[:maxSnapshotProblems], fmt.Sprintf("and %d more", len(problems)-maxSnapshotProblems))
+	}
+	return domain.NewInvalidParamError("snapshot", "failed integrity checks: "+strings.Join(problems, "; "))
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type mockSnapshotRepository struct {
+	exportFn  func(context.Context) (*models.Snapshot, error)
+	restoreFn func(context.Context, *models.Snapshot) error
+}
+
+func (m *mockSnapshotRepository) ExportSnapshot(ctx context.Context) (*models.Snapshot, error) {
+	if m.exportFn == nil {
+		return &models.Snapshot{Version: models.SnapshotVersion}, nil
+	}
+	return m.exportFn(ctx)
+}
+
+func (m *mockSnapshotRepository) RestoreSnapshot(ctx context.Context, snap *models.Snapshot) error {
+	if m.restoreFn == nil {
+		return nil
+	}
+	return m.restoreFn(ctx, snap)
+}
+
+func validSnapshot() *models.Snapshot {
+	return &models.Snapshot{
+		Version: models.SnapshotVersion,
+		Teams:   []models.SnapshotTeam{{TeamName: "backend"}},
+		Users: []models.User{
+			{UserId: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+			{UserId: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+		},
+		PullRequests: []*models.PullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "Feature", AuthorId: "u1", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u2"}},
+		},
+	}
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	if err := ValidateSnapshot(validSnapshot()); err != nil {
+		t.Fatalf("valid snapshot rejected: %v", err)
+	}
+
+	future := validSnapshot()
+	future.Version = models.SnapshotVersion + 1
+	if err := ValidateSnapshot(future); !errors.Is(err, domain.ErrInvalidParam) || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("expected unsupported version error, got %v", err)
+	}
+
+	// Архив первой сборки без необязательных разделов остаётся совместимым.
+	var early models.Snapshot
+	if err := json.Unmarshal([]byte(`{"version":1,"teams":[{"team_name":"backend"}],`+
+		`"users":[{"user_id":"u1","username":"Alice","team_name":"backend","is_active":true}],"pull_requests":[]}`), &early); err != nil {
+		t.Fatalf("decode archive: %v", err)
+	}
+	if err := ValidateSnapshot(&early); err != nil {
+		t.Fatalf("archive without optional sections rejected: %v", err)
+	}
+
+	broken := validSnapshot()
+	broken.Teams = append(broken.Teams, models.SnapshotTeam{TeamName: "backend"})
+	broken.Users = append(broken.Users, models.User{UserId: "u3", Username: "Carol", TeamName: "ghosts"})
+	broken.PullRequests = append(broken.PullRequests,
+		&models.PullRequest{PullRequestId: "pr-1", AuthorId: "nobody", Status: "CLOSED", AssignedReviewers: []string{"u1", "u1", "u9"}},
+	)
+	broken.ReviewRotations = []models.ReviewRotation{{PullRequestId: "pr-ghost", OldUserId: "u1", NewUserId: "u2"}}
+	broken.Absences = []models.Absence{{UserId: "u1"}, {UserId: "u7", StartsAt: time.Unix(0, 0), EndsAt: time.Unix(60, 0)}}
+	broken.WorkingHours = []models.WorkingHours{
+		{UserId: "u1", TimeZone: "Europe/Berlin", Start: "09:00", End: "18:00"},
+		{UserId: "u1", TimeZone: "Mars/Olympus", Start: "09:00", End: "18:00"},
+		{UserId: "u8", TimeZone: "UTC", Start: "18:00", End: "09:00"},
+	}
+	broken.ReviewCapacities = []models.SnapshotCapacity{{UserId: "u9", MaxOpenReviews: 0}}
+	broken.ReviewQueue = []models.QueuedReview{{PullRequestId: "pr-ghost", Missing: 1}, {PullRequestId: "pr-1"}}
+	err := ValidateSnapshot(broken)
+	if !errors.Is(err, domain.ErrInvalidParam) {
+		t.Fatalf("expected invalid param, got %v", err)
+	}
+	for _, want := range []string{
+		"teams[1]: duplicate team backend",
+		"users[2]: unknown team ghosts",
+		"pull_requests[1]: duplicate pull request pr-1",
+		"unknown author nobody",
+		`unknown status "CLOSED"`,
+		"3 reviewers, at most 2 allowed",
+		"duplicate reviewer u1",
+		"unknown reviewer u9",
+		"review_rotations[0]: unknown pull request pr-ghost",
+		"absences[0]: ends_at must be after starts_at",
+		"absences[1]: unknown user u7",
+		"working_hours[1]: duplicate user u1",
+		"working_hours[1]: time_zone must be an IANA time zone name",
+		"working_hours[2]: unknown user u8",
+		"working_hours[2]: end must be after start",
+		"review_capacities[0]: unknown user u9",
+		"review_capacities[0]: max_open_reviews must be positive",
+		"review_queue[0]: unknown pull request pr-ghost",
+		"review_queue[1]: missing must be positive",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+
+	rules := validSnapshot()
+	rules.AffinityRules = []models.AffinityRule{
+		{TeamName: "frontend", Kind: "ALWAYS", UserId: "u1", WithUserIds: []string{"u10"}},
+		{TeamName: "backend", Kind: models.AffinityRuleNEVERPAIR, UserId: "u1"},
+	}
+	err = ValidateSnapshot(rules)
+	for _, want := range []string{
+		`affinity_rules[0]: unknown kind "ALWAYS"`,
+		"affinity_rules[0]: unknown team frontend",
+		"affinity_rules[0]: unknown user u10",
+		"affinity_rules[1]: with_user_ids is empty",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %v does not mention %q", err, want)
+		}
+	}
+
+	decisions := validSnapshot()
+	decisions.AssignmentDecisions = []models.AssignmentDecision{
+		{PullRequestId: "pr-1", Operation: models.DecisionOperationCREATE},
+		{PullRequestId: "pr-ghost", Operation: "MERGE"},
+	}
+	err = ValidateSnapshot(decisions)
+	for _, want := range []string{
+		"assignment_decisions[1]: unknown pull request pr-ghost",
+		`assignment_decisions[1]: unknown operation "MERGE"`,
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %v does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "assignment_decisions[0]") {
+		t.Fatalf("valid decision rejected: %v", err)
+	}
+
+	learners := validSnapshot()
+	learners.TeamLearners = []models.SnapshotLearner{
+		{TeamName: "backend", UserId: "u2"},
+		{TeamName: "backend", UserId: "u2"},
+		{TeamName: "frontend", UserId: "u11"},
+	}
+	err = ValidateSnapshot(learners)
+	for _, want := range []string{
+		"team_learners[1]: duplicate learner u2 of team backend",
+		"team_learners[2]: unknown team frontend",
+		"team_learners[2]: unknown user u11",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %v does not mention %q", err, want)
+		}
+	}
+
+	repos := validSnapshot()
+	repos.Repositories = []models.Repository{
+		{RepositoryName: "billing", OwnerTeam: "backend", RequiredReviewers: 1},
+		{RepositoryName: "billing", RequiredReviewers: 2},
+		{RepositoryName: "search", OwnerTeam: "frontend", RequiredReviewers: 3},
+		{RepositoryName: "", RequiredReviewers: 0},
+	}
+	repos.PullRequests = append(repos.PullRequests,
+		&models.PullRequest{PullRequestId: "billing#1", AuthorId: "u1", Status: models.PullRequestStatusOPEN, Repository: "billing", Number: 1},
+		&models.PullRequest{PullRequestId: "billing-1", AuthorId: "u1", Status: models.PullRequestStatusOPEN, Repository: "billing", Number: 1},
+		&models.PullRequest{PullRequestId: "ghost#1", AuthorId: "u1", Status: models.PullRequestStatusOPEN, Repository: "ghost", Number: 1},
+	)
+	err = ValidateSnapshot(repos)
+	for _, want := range []string{
+		"repositories[1]: duplicate repository billing",
+		"repositories[2]: unknown owner team frontend",
+		"repositories[2]: required_reviewers must be between 1 and 2",
+		"repositories[3]: repository_name is empty",
+		"repositories[3]: required_reviewers must be between 1 and 2",
+		"pull_requests[2]: duplicate number billing#1",
+		"pull_requests[3]: unknown repository ghost",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %v does not mention %q", err, want)
+		}
+	}
+	for _, unwanted := range []string{"repositories[0]", "pull_requests[0]", "pull_requests[1]"} {
+		if strings.Contains(err.Error(), unwanted) {
+			t.Fatalf("valid entry rejected: %v", err)
+		}
+	}
+}
+
+func TestSnapshotManager_Restore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restores and caches users", func(t *testing.T) {
+		var restored *models.Snapshot
+		users := NewUserManager(&mockUserTeamRepository{})
+		manager := NewSnapshotManager(&mockSnapshotRepository{
+			restoreFn: func(_ context.Context, snap *models.Snapshot) error {
+				restored = snap
+				return nil
+			},
+		}, users)
+
+		counts, err := manager.Restore(ctx, validSnapshot())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if restored == nil || counts != (models.SnapshotCounts{Teams: 1, Users: 2, PullRequests: 1, Reviewers: 1}) {
+			t.Fatalf("unexpected counts %+v", counts)
+		}
+		if users.CacheSize() != 2 {
+			t.Fatalf("expected restored users in cache, got %d", users.CacheSize())
+		}
+	})
+
+	t.Run("invalid snapshot is not restored", func(t *testing.T) {
+		manager := NewSnapshotManager(&mockSnapshotRepository{
+			restoreFn: func(context.Context, *models.Snapshot) error {
+				t.Fatal("repository must not be called")
+				return nil
+			},
+		}, nil)
+		snap := validSnapshot()
+		snap.PullRequests[0].AuthorId = "ghost"
+		if _, err := manager.Restore(ctx, snap); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("expected invalid param, got %v", err)
+		}
+	})
+
+	t.Run("not empty storage", func(t *testing.T) {
+		manager := NewSnapshotManager(&mockSnapshotRepository{
+			restoreFn: func(context.Context, *models.Snapshot) error {
+				return domain.NewNotEmptyError("database")
+			},
+		}, nil)
+		if _, err := manager.Restore(ctx, validSnapshot()); !errors.Is(err, domain.ErrNotEmpty) {
+			t.Fatalf("expected not empty error, got %v", err)
+		}
+	})
+}
+
+func TestSnapshotManager_ExportStampsTime(t *testing.T) {
+	manager := NewSnapshotManager(&mockSnapshotRepository{}, nil)
+	snap, err := manager.Export(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snap.CreatedAt.IsZero() || snap.CreatedAt.Location().String() != "UTC" {
+		t.Fatalf("expected UTC creation time, got %v", snap.CreatedAt)
+	}
+}
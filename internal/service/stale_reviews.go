@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// Значения по умолчанию для планировщика зависших ревью.
+const (
+	defaultStaleInterval     = 5 * time.Minute
+	defaultStaleSLA          = 48 * time.Hour
+	defaultStaleMaxRotations = 2
+	// staleReviewLease — имя аренды, под которой работает единственный планировщик.
+	staleReviewLease = "stale-reviews"
+)
+
+// StaleReviewRepository хранит активность ревьюеров, историю замен и аренды фоновых задач.
+type StaleReviewRepository interface {
+	GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error)
+	// FindOpenReviewAssignments возвращает назначения ревьюеров на все открытые PR.
+	FindOpenReviewAssignments(ctx context.Context) ([]models.ReviewAssignment, error)
+	// TouchReviewActivity обновляет отметку активности; NOT_ASSIGNED, если ревьюер не назначен на PR.
+	TouchReviewActivity(ctx context.Context, prID, userID string, at time.Time) error
+	RecordReviewRotation(ctx context.Context, rotation *models.ReviewRotation) error
+	ListReviewRotations(ctx context.Context, filter models.ReviewRotationFilter) ([]models.ReviewRotation, error)
+	// AcquireLease захватывает или продлевает аренду name на ttl; false, если ею владеет другой держатель.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+}
+
+// Reassigner заменяет ревьюера PR; реализуется PullRequestManager.
+type Reassigner interface {
+	Reassign(ctx context.Context, oldUserId, prId string) (*domain.ReassignResponse, error)
+}
+
+// StaleReviewConfig настраивает планировщик; нулевые значения заменяются умолчаниями.
+type StaleReviewConfig struct {
+	// Interval — период проверки открытых PR.
+	Interval time.Duration
+	// SLA — допустимое время без активности ревьюера; TeamSLA переопределяет его для отдельных команд.
+	SLA     time.Duration
+	TeamSLA map[string]time.Duration
+	// MaxRotations — сколько раз планировщик может менять ревьюеров одного PR.
+	MaxRotations int
+	// LeaseTTL — срок аренды лидера; по умолчанию два интервала, чтобы лидер успевал её продлить.
+	LeaseTTL time.Duration
+	// Holder идентифицирует инстанс в аренде; по умолчанию имя хоста и PID.
+	Holder string
+}
+
+// withDefaults подставляет значения по умолчанию.
+func (c StaleReviewConfig) withDefaults() StaleReviewConfig {
+	if c.Interval <= 0 {
+		c.Interval = defaultStaleInterval
+	}
+	if c.SLA <= 0 {
+		c.SLA = defaultStaleSLA
+	}
+	if c.MaxRotations <= 0 {
+		c.MaxRotations = defaultStaleMaxRotations
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = 2 * c.Interval
+	}
+	if c.Holder == "" {
+		c.Holder = defaultLeaseHolder()
+	}
+	return c
+}
+
+// defaultLeaseHolder идентифицирует инстанс в арендах фоновых задач: имя хоста и PID.
+func defaultLeaseHolder() string {
+	host, _ := os.Hostname()
+	return host + ":" + strconv.Itoa(os.Getpid())
+}
+
+// slaFor возвращает SLA команды PR.
+func (c StaleReviewConfig) slaFor(teamName string) time.Duration {
+	if sla, ok := c.TeamSLA[teamName]; ok && sla > 0 {
+		return sla
+	}
+	return c.SLA
+}
+
+// StaleReviewManager находит ревьюеров, которые дольше SLA не проявляли активности по открытому PR,
+// и заменяет их логикой Reassign.
+type StaleReviewManager struct {
+	repo          StaleReviewRepository
+	reassign      Reassigner
+	workingHours  WorkingHoursLookup
+	organizations OrganizationLister
+	cfg           StaleReviewConfig
+	now           func() time.Time
+}
+
+// NewStaleReviewManager создаёт менеджер зависших ревью.
+func NewStaleReviewManager(repo StaleReviewRepository, reassign Reassigner, cfg StaleReviewConfig) *StaleReviewManager {
+	return &StaleReviewManager{
+		repo:     repo,
+		reassign: reassign,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+	}
+}
+
+// SetWorkingHours подключает источник рабочего времени: SLA ревьюера с расписанием отсчитывается
+// только в его рабочие часы. Без источника время простоя считается календарным.
+func (sm *StaleReviewManager) SetWorkingHours(lookup WorkingHoursLookup) {
+	sm.workingHours = lookup
+}
+
+// SetOrganizations подключает список организаций: планировщик обходит их все.
+// Без него проверяется только организация default.
+func (sm *StaleReviewManager) SetOrganizations(lister OrganizationLister) {
+	sm.organizations = lister
+}
+
+// RecordActivity отмечает активность ревьюера по открытому PR и сбрасывает его таймер SLA.
+func (sm *StaleReviewManager) RecordActivity(ctx context.Context, prID, userID string) (_ *models.ReviewActivity, err error) {
+	ctx, span := tracer.Start(ctx, "StaleReviewManager.RecordActivity")
+	defer func() { endSpan(span, err) }()
+
+	pr, err := sm.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("pull request")
+		}
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if pr.Status == models.PullRequestStatusMERGED {
+		return nil, domain.NewPRMergedError(prID)
+	}
+
+	at := sm.now().UTC()
+	if err := sm.repo.TouchReviewActivity(ctx, prID, userID, at); err != nil {
+		if errors.Is(err, domain.ErrNotAssigned) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record review activity: %w", err)
+	}
+	return &models.ReviewActivity{PullRequestId: prID, UserId: userID, LastActivityAt: at}, nil
+}
+
+// Rotations возвращает историю автоматических замен, новые записи первыми.
+func (sm *StaleReviewManager) Rotations(ctx context.Context, filter models.ReviewRotationFilter) (_ []models.ReviewRotation, err error) {
+	ctx, span := tracer.Start(ctx, "StaleReviewManager.Rotations")
+	defer func() { endSpan(span, err) }()
+
+	if filter.Limit < 0 {
+		return nil, domain.NewInvalidParamError("limit", "must not be negative")
+	}
+	rotations, err := sm.repo.ListReviewRotations(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review rotations: %w", err)
+	}
+	return rotations, nil
+}
+
+// Run проверяет открытые PR каждые Interval, пока не отменён ctx.
+// Проверку выполняет только инстанс, удерживающий аренду в базе данных.
+func (sm *StaleReviewManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(sm.cfg.Interval)
+	defer ticker.Stop()
+
+	slog.Info("stale review scheduler started",
+		"interval", sm.cfg.Interval, "sla", sm.cfg.SLA, "max_rotations", sm.cfg.MaxRotations, "holder", sm.cfg.Holder)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sm.tick(ctx)
+		}
+	}
+}
+
+// tick продлевает аренду и, если инстанс — лидер, заменяет неактивных ревьюеров во всех организациях.
+func (sm *StaleReviewManager) tick(ctx context.Context) {
+	leader, err := sm.repo.AcquireLease(ctx, staleReviewLease, sm.cfg.Holder, sm.cfg.LeaseTTL)
+	if err != nil {
+		slog.ErrorContext(ctx, "stale review lease failed", "err", err.Error())
+		return
+	}
+	if !leader {
+		slog.DebugContext(ctx, "stale review scheduler is not the leader", "holder", sm.cfg.Holder)
+		return
+	}
+	err = forEachOrganization(ctx, sm.organizations, func(ctx context.Context) error {
+		_, err := sm.RotateStale(ctx)
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "stale review rotation failed", "err", err.Error())
+	}
+}
+
+// RotateStale заменяет ревьюеров, у которых истёк SLA команды, пока у PR не исчерпан лимит замен.
+// Для ревьюеров с расписанием простой считается только в их рабочее время.
+// Ошибки отдельных замен (нет кандидата, PR уже слит) журналируются и не прерывают обход.
+func (sm *StaleReviewManager) RotateStale(ctx context.Context) (_ []models.ReviewRotation, err error) {
+	ctx, span := tracer.Start(ctx, "StaleReviewManager.RotateStale")
+	defer func() { endSpan(span, err) }()
+
+	assignments, err := sm.repo.FindOpenReviewAssignments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find open review assignments: %w", err)
+	}
+	// Самые давние назначения обрабатываются первыми.
+	sort.SliceStable(assignments, func(i, j int) bool {
+		return assignments[i].LastActivityAt.Before(assignments[j].LastActivityAt)
+	})
+
+	reviewerIDs := make([]string, 0, len(assignments))
+	seenReviewers := make(map[string]struct{}, len(assignments))
+	for _, a := range assignments {
+		if _, ok := seenReviewers[a.ReviewerId]; !ok {
+			seenReviewers[a.ReviewerId] = struct{}{}
+			reviewerIDs = append(reviewerIDs, a.ReviewerId)
+		}
+	}
+	schedules, err := loadSchedules(ctx, sm.workingHours, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find working hours: %w", err)
+	}
+
+	now := sm.now()
+	rotations := make(map[string]int)
+	done := make([]models.ReviewRotation, 0)
+	for _, a := range assignments {
+		idle := now.Sub(a.LastActivityAt)
+		if sched, ok := schedules[a.ReviewerId]; ok {
+			idle = sched.workingTime(a.LastActivityAt, now)
+		}
+		if idle <= sm.cfg.slaFor(a.TeamName) {
+			continue
+		}
+		count, seen := rotations[a.PullRequestId]
+		if !seen {
+			count = a.Rotations
+		}
+		if count >= sm.cfg.MaxRotations {
+			slog.DebugContext(ctx, "stale review rotation limit reached",
+				"pull_request_id", a.PullRequestId, "reviewer_id", a.ReviewerId, "rotations", count)
+			continue
+		}
+
+		res, err := sm.reassign.Reassign(ctx, a.ReviewerId, a.PullRequestId)
+		if err != nil {
+			if isRotationSkippable(err) {
+				slog.WarnContext(ctx, "stale reviewer not rotated",
+					"pull_request_id", a.PullRequestId, "reviewer_id", a.ReviewerId, "err", err.Error())
+				continue
+			}
+			return done, fmt.Errorf("reassign %s on %s: %w", a.ReviewerId, a.PullRequestId, err)
+		}
+
+		rotation := models.ReviewRotation{
+			PullRequestId: a.PullRequestId,
+			OldUserId:     a.ReviewerId,
+			NewUserId:     res.ReplacedBy,
+			TeamName:      a.TeamName,
+			IdleSeconds:   int64(idle / time.Second),
+			RotatedAt:     now.UTC(),
+		}
+		if err := sm.repo.RecordReviewRotation(ctx, &rotation); err != nil {
+			return done, fmt.Errorf("record review rotation: %w", err)
+		}
+		rotations[a.PullRequestId] = count + 1
+		done = append(done, rotation)
+		slog.InfoContext(ctx, "stale reviewer rotated",
+			"pull_request_id", rotation.PullRequestId,
+			"old_user_id", rotation.OldUserId,
+			"new_user_id", rotation.NewUserId,
+			"idle", idle.Round(time.Second),
+		)
+	}
+	return done, nil
+}
+
+// isRotationSkippable сообщает, что замену нельзя выполнить сейчас, но обход можно продолжить.
+func isRotationSkippable(err error) bool {
+	return errors.Is(err, domain.ErrNoCandidate) ||
+		errors.Is(err, domain.ErrPRMerged) ||
+		errors.Is(err, domain.ErrNotAssigned) ||
+		errors.Is(err, domain.ErrNotFound)
+}
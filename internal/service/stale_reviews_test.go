@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+)
+
+type mockStaleRepository struct {
+	pr          *models.PullRequest
+	assignments []models.ReviewAssignment
+	touched     []string
+	recorded    []models.ReviewRotation
+	leader      bool
+	findCalls   int
+}
+
+func (m *mockStaleRepository) GetPullRequest(_ context.Context, prID string) (*models.PullRequest, error) {
+	if m.pr == nil || m.pr.PullRequestId != prID {
+		return nil, domain.NewNotFoundError("pull request " + prID)
+	}
+	return m.pr, nil
+}
+
+func (m *mockStaleRepository) FindOpenReviewAssignments(context.Context) ([]models.ReviewAssignment, error) {
+	m.findCalls++
+	return m.assignments, nil
+}
+
+func (m *mockStaleRepository) TouchReviewActivity(_ context.Context, prID, userID string, _ time.Time) error {
+	for _, r := range m.pr.AssignedReviewers {
+		if r == userID {
+			m.touched = append(m.touched, userID)
+			return nil
+		}
+	}
+	return domain.NewNotAssignedError(prID)
+}
+
+func (m *mockStaleRepository) RecordReviewRotation(_ context.Context, rotation *models.ReviewRotation) error {
+	m.recorded = append(m.recorded, *rotation)
+	return nil
+}
+
+func (m *mockStaleRepository) ListReviewRotations(context.Context, models.ReviewRotationFilter) ([]models.ReviewRotation, error) {
+	return m.recorded, nil
+}
+
+func (m *mockStaleRepository) AcquireLease(context.Context, string, string, time.Duration) (bool, error) {
+	return m.leader, nil
+}
+
+// fakeReassigner заменяет ревьюера на "<old>-next" или возвращает ошибку из errs.
+type fakeReassigner struct {
+	errs  map[string]error
+	calls []string
+}
+
+func (f *fakeReassigner) Reassign(_ context.Context, oldUserId, prId string) (*domain.ReassignResponse, error) {
+	f.calls = append(f.calls, prId+"/"+oldUserId)
+	if err := f.errs[prId+"/"+oldUserId]; err != nil {
+		return nil, err
+	}
+	return &domain.ReassignResponse{ReplacedBy: oldUserId + "-next"}, nil
+}
+
+func newTestStaleManager(repo StaleReviewRepository, reassign Reassigner, cfg StaleReviewConfig, now time.Time) *StaleReviewManager {
+	sm := NewStaleReviewManager(repo, reassign, cfg)
+	sm.now = func() time.Time { return now }
+	return sm
+}
+
+func TestRotateStaleRespectsTeamSLA(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := &mockStaleRepository{assignments: []models.ReviewAssignment{
+		{PullRequestId: "pr-fresh", ReviewerId: "u1", TeamName: "backend", LastActivityAt: now.Add(-time.Hour)},
+		{PullRequestId: "pr-backend", ReviewerId: "u2", TeamName: "backend", LastActivityAt: now.Add(-30 * time.Hour)},
+		{PullRequestId: "pr-mobile", ReviewerId: "u3", TeamName: "mobile", LastActivityAt: now.Add(-30 * time.Hour)},
+	}}
+	reassign := &fakeReassigner{}
+	sm := newTestStaleManager(repo, reassign, StaleReviewConfig{
+		SLA:     24 * time.Hour,
+		TeamSLA: map[string]time.Duration{"mobile": 72 * time.Hour},
+	}, now)
+
+	rotations, err := sm.RotateStale(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotations) != 1 || rotations[0].PullRequestId != "pr-backend" {
+		t.Fatalf("expected only pr-backend to rotate, got %+v", rotations)
+	}
+	got := rotations[0]
+	if got.OldUserId != "u2" || got.NewUserId != "u2-next" || got.IdleSeconds != 30*3600 || !got.RotatedAt.Equal(now) {
+		t.Fatalf("unexpected rotation record: %+v", got)
+	}
+	if len(repo.recorded) != 1 {
+		t.Fatalf("expected rotation to be recorded, got %d", len(repo.recorded))
+	}
+}
+
+func TestRotateStaleStopsAtMaxRotations(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	stale := now.Add(-100 * time.Hour)
+	repo := &mockStaleRepository{assignments: []models.ReviewAssignment{
+		{PullRequestId: "pr-1", ReviewerId: "u1", LastActivityAt: stale, Rotations: 1},
+		{PullRequestId: "pr-1", ReviewerId: "u2", LastActivityAt: stale, Rotations: 1},
+		{PullRequestId: "pr-2", ReviewerId: "u3", LastActivityAt: stale, Rotations: 2},
+	}}
+	reassign := &fakeReassigner{}
+	sm := newTestStaleManager(repo, reassign, StaleReviewConfig{SLA: time.Hour, MaxRotations: 2}, now)
+
+	rotations, err := sm.RotateStale(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotations) != 1 || rotations[0].OldUserId != "u1" {
+		t.Fatalf("expected a single rotation of u1, got %+v", rotations)
+	}
+	if len(reassign.calls) != 1 {
+		t.Fatalf("expected one Reassign call, got %v", reassign.calls)
+	}
+}
+
+func TestRotateStaleSkipsDomainFailures(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	stale := now.Add(-100 * time.Hour)
+	repo := &mockStaleRepository{assignments: []models.ReviewAssignment{
+		{PullRequestId: "pr-1", ReviewerId: "u1", LastActivityAt: stale},
+		{PullRequestId: "pr-2", ReviewerId: "u2", LastActivityAt: stale.Add(time.Minute)},
+	}}
+	reassign := &fakeReassigner{errs: map[string]error{"pr-1/u1": domain.NewNoCandidateError("pr-1")}}
+	sm := newTestStaleManager(repo, reassign, StaleReviewConfig{SLA: time.Hour}, now)
+
+	rotations, err := sm.RotateStale(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotations) != 1 || rotations[0].PullRequestId != "pr-2" {
+		t.Fatalf("expected pr-2 to rotate after NO_CANDIDATE on pr-1, got %+v", rotations)
+	}
+
+	reassign.errs["pr-2/u2"] = errors.New("db down")
+	repo.recorded = nil
+	if _, err := sm.RotateStale(context.Background()); err == nil {
+		t.Fatal("expected unexpected Reassign errors to abort the pass")
+	}
+}
+
+func TestStaleTickRunsOnlyOnLeader(t *testing.T) {
+	repo := &mockStaleRepository{}
+	sm := NewStaleReviewManager(repo, &fakeReassigner{}, StaleReviewConfig{})
+
+	sm.tick(context.Background())
+	if repo.findCalls != 0 {
+		t.Fatalf("follower must not scan pull requests, got %d calls", repo.findCalls)
+	}
+
+	repo.leader = true
+	sm.tick(context.Background())
+	if repo.findCalls != 1 {
+		t.Fatalf("leader must scan pull requests once, got %d calls", repo.findCalls)
+	}
+}
+
+func TestRecordActivity(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := &mockStaleRepository{pr: &models.PullRequest{
+		PullRequestId: "pr-1", Status: models.PullRequestStatusOPEN, AssignedReviewers: []string{"u1"},
+	}}
+	sm := newTestStaleManager(repo, &fakeReassigner{}, StaleReviewConfig{}, now)
+
+	activity, err := sm.RecordActivity(context.Background(), "pr-1", "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !activity.LastActivityAt.Equal(now) || len(repo.touched) != 1 {
+		t.Fatalf("unexpected activity: %+v", activity)
+	}
+
+	if _, err := sm.RecordActivity(context.Background(), "pr-1", "u2"); !errors.Is(err, domain.ErrNotAssigned) {
+		t.Fatalf("expected NOT_ASSIGNED, got %v", err)
+	}
+	if _, err := sm.RecordActivity(context.Background(), "ghost", "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+
+	repo.pr.Status = models.PullRequestStatusMERGED
+	if _, err := sm.RecordActivity(context.Background(), "pr-1", "u1"); !errors.Is(err, domain.ErrPRMerged) {
+		t.Fatalf("expected PR_MERGED, got %v", err)
+	}
+}
+
+// TestRotateStaleWithColdUserCache воспроизводит лидера аренды, который не обслуживал запросы команды:
+// кэш его UserManager пуст, и кандидаты на замену должны читаться из хранилища.
+func TestRotateStaleWithColdUserCache(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	warm := NewUserManager(storage)
+	if err := warm.AddTeam(ctx, models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "Alice", IsActive: true},
+		{UserId: "u2", Username: "Bob", IsActive: true},
+		{UserId: "u3", Username: "Carol", IsActive: true},
+		{UserId: "u4", Username: "Dave", IsActive: true},
+	}}); err != nil {
+		t.Fatalf("add team: %v", err)
+	}
+	created, err := (&PullRequestManager{}).NewPullRequestService(storage, warm).CreatePullRequest(ctx,
+		models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", PullRequestName: "Fix", AuthorId: "u1"})
+	if err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+
+	cold := NewUserManager(storage)
+	prs := (&PullRequestManager{}).NewPullRequestService(storage, cold)
+	sm := newTestStaleManager(storage, prs, StaleReviewConfig{SLA: time.Hour, MaxRotations: 1}, time.Now().Add(48*time.Hour))
+
+	rotations, err := sm.RotateStale(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotations) != 1 {
+		t.Fatalf("expected one rotation with a cold cache, got %+v", rotations)
+	}
+	if got := rotations[0].NewUserId; got == "" || got == "u1" || slices.Contains(created.PR.AssignedReviewers, got) {
+		t.Fatalf("unexpected replacement %q for reviewers %v", got, created.PR.AssignedReviewers)
+	}
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer создаёт спаны для методов сервисного слоя.
+var tracer = otel.Tracer("github.com/AlekseyZapadovnikov/pr-manager/internal/service")
+
+// endSpan записывает ошибку в спан (если она есть) и завершает его.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+)
+
+// Значения по умолчанию для статистики времени до слияния.
+const (
+	DefaultTurnaroundWindow = 30 * 24 * time.Hour
+	DefaultStatsCacheTTL    = time.Minute
+)
+
+// turnaroundCache хранит посчитанную статистику по организации, окну [from, to) и репозиторию до истечения TTL.
+type turnaroundCache struct {
+	mu      sync.Mutex
+	entries map[turnaroundKey]turnaroundEntry
+}
+
+type turnaroundKey struct {
+	organization string
+	from, to     int64
+	repository   string
+}
+
+type turnaroundEntry struct {
+	stats   *models.TurnaroundStats
+	expires time.Time
+}
+
+// ConfigureStats задаёт TTL кэша статистики и окно по умолчанию; нулевые значения оставляют умолчания.
+func (prm *PullRequestManager) ConfigureStats(cacheTTL, turnaroundWindow time.Duration) {
+	prm.statsCacheTTL = cacheTTL
+	prm.turnaroundWindow = turnaroundWindow
+}
+
+// TurnaroundStats возвращает перцентили времени до слияния за окно filter.
+// Пустой To означает текущую минуту, пустой From — To минус окно по умолчанию.
+// Результат кэшируется, поскольку запрос агрегирует всю историю слияний.
+func (prm *PullRequestManager) TurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (_ *models.TurnaroundStats, err error) {
+	ctx, span := tracer.Start(ctx, "PullRequestManager.TurnaroundStats")
+	defer func() { endSpan(span, err) }()
+
+	if filter.To.IsZero() {
+		// Округление до минуты даёт запросам без параметров общий ключ кэша.
+		filter.To = time.Now().UTC().Truncate(time.Minute)
+	}
+	if filter.From.IsZero() {
+		window := prm.turnaroundWindow
+		if window <= 0 {
+			window = DefaultTurnaroundWindow
+		}
+		filter.From = filter.To.Add(-window)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, domain.NewInvalidParamError("from", "must be before to")
+	}
+
+	key := turnaroundKey{
+		organization: tenant.Organization(ctx),
+		from:         filter.From.UnixNano(),
+		to:           filter.To.UnixNano(),
+		repository:   filter.Repository,
+	}
+	if stats, ok := prm.turnaround.get(key); ok {
+		return stats, nil
+	}
+
+	stats, err := prm.repo.GetTurnaroundStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get turnaround stats: %w", err)
+	}
+
+	ttl := prm.statsCacheTTL
+	if ttl <= 0 {
+		ttl = DefaultStatsCacheTTL
+	}
+	prm.turnaround.put(key, stats, ttl)
+	return stats, nil
+}
+
+func (c *turnaroundCache) get(key turnaroundKey) (*models.TurnaroundStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+// put сохраняет результат и заодно вычищает просроченные записи, чтобы кэш не рос бесконечно.
+func (c *turnaroundCache) put(key turnaroundKey, stats *models.TurnaroundStats, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.entries == nil {
+		c.entries = make(map[turnaroundKey]turnaroundEntry)
+	}
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = turnaroundEntry{stats: stats, expires: now.Add(ttl)}
+}
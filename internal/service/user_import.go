@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// ParseUserImport читает файл импорта пользователей в формате csv или json.
+// Ошибка возвращается только для нечитаемого файла; ошибки отдельных строк попадают в ParseError.
+func ParseUserImport(r io.Reader, format string) ([]models.ImportUserRow, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		return parseUserImportCSV(r)
+	case models.ImportFormatJSON:
+		return parseUserImportJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// parseUserImportCSV разбирает CSV с заголовком; колонки ищутся по имени, is_active необязательна и по умолчанию true.
+func parseUserImportCSV(r io.Reader) ([]models.ImportUserRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"user_id", "username", "team_name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header misses column %q", required)
+		}
+	}
+
+	var rows []models.ImportUserRow
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row %d: %w", n, err)
+		}
+
+		row := models.ImportUserRow{Row: n, IsActive: true}
+		if len(record) != len(header) {
+			row.ParseError = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row.UserId = field("user_id")
+		row.Username = field("username")
+		row.TeamName = field("team_name")
+		if raw := field("is_active"); raw != "" {
+			if row.IsActive, err = strconv.ParseBool(raw); err != nil {
+				row.ParseError = fmt.Sprintf("is_active must be a boolean, got %q", raw)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// importUserJSON — элемент JSON-массива импорта; отсутствующий is_active означает true.
+type importUserJSON struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// parseUserImportJSON разбирает JSON-массив объектов пользователей.
+func parseUserImportJSON(r io.Reader) ([]models.ImportUserRow, error) {
+	var items []importUserJSON
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	rows := make([]models.ImportUserRow, 0, len(items))
+	for i, item := range items {
+		row := models.ImportUserRow{
+			Row:      i + 1,
+			UserId:   strings.TrimSpace(item.UserId),
+			Username: strings.TrimSpace(item.Username),
+			TeamName: strings.TrimSpace(item.TeamName),
+			IsActive: true,
+		}
+		if item.IsActive != nil {
+			row.IsActive = *item.IsActive
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ImportUsers создаёт и обновляет команды и пользователей из строк импорта одной транзакцией.
+// Если хотя бы одна строка ошибочна, ничего не сохраняется; при DryRun отчёт строится без записи.
+func (um *UserManager) ImportUsers(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (_ *models.ImportReport, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.ImportUsers")
+	defer func() { endSpan(span, err) }()
+
+	if opts.OnConflict == "" {
+		opts.OnConflict = models.ImportConflictSkip
+	}
+	if !opts.OnConflict.Valid() {
+		return nil, domain.NewInvalidParamError("on_conflict", "must be one of skip, update, fail")
+	}
+	if len(rows) == 0 {
+		return nil, domain.NewInvalidParamError("file", "contains no rows")
+	}
+	if um.repo == nil {
+		return nil, fmt.Errorf("repository is not configured")
+	}
+
+	report := &models.ImportReport{
+		DryRun:       opts.DryRun,
+		OnConflict:   string(opts.OnConflict),
+		TeamsCreated: []string{},
+		Rows:         make([]models.ImportRowResult, 0, len(rows)),
+	}
+
+	var (
+		users    []models.User
+		seen     = make(map[string]int, len(rows))
+		teams    = make(map[string]bool) // true — команда уже есть в хранилище
+		newTeams []string
+	)
+	for _, row := range rows {
+		result := models.ImportRowResult{Row: row.Row, UserId: row.UserId}
+		action, user, rowErr := um.planImportRow(ctx, row, opts.OnConflict, seen)
+		if rowErr != nil {
+			if !errors.Is(rowErr, errImportRow) {
+				return nil, rowErr
+			}
+			result.Action = models.ImportActionError
+			result.Error = strings.TrimPrefix(rowErr.Error(), errImportRow.Error()+": ")
+			report.Failed++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+		seen[row.UserId] = row.Row
+		result.Action = action
+
+		switch action {
+		case models.ImportActionCreate, models.ImportActionUpdate:
+			if action == models.ImportActionCreate {
+				report.Created++
+			} else {
+				report.Updated++
+			}
+			users = append(users, user)
+			if _, known := teams[user.TeamName]; !known {
+				exists, err := um.teamExists(ctx, user.TeamName)
+				if err != nil {
+					return nil, err
+				}
+				teams[user.TeamName] = exists
+				if !exists {
+					newTeams = append(newTeams, user.TeamName)
+				}
+			}
+		default:
+			report.Skipped++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	sort.Strings(newTeams)
+	report.TeamsCreated = append(report.TeamsCreated, newTeams...)
+
+	if report.Failed > 0 || opts.DryRun || len(users) == 0 {
+		return report, nil
+	}
+
+	if err := um.repo.CreateTeamsWithMembers(ctx, newTeams, users); err != nil {
+		return nil, fmt.Errorf("failed to persist import: %w", err)
+	}
+	report.Applied = true
+
+	// Кэш обновляем только после успешной записи в базу.
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	cache := um.userCache(ctx)
+	for _, user := range users {
+		userCopy := user
+		cache[userCopy.UserId] = &userCopy
+	}
+	return report, nil
+}
+
+// errImportRow помечает ошибки отдельной строки, которые попадают в отчёт, а не прерывают импорт.
+var errImportRow = errors.New("import row")
+
+// planImportRow проверяет строку и решает, что с ней делать; seen хранит номера уже принятых строк по user_id.
+func (um *UserManager) planImportRow(ctx context.Context, row models.ImportUserRow, policy models.ImportConflictPolicy, seen map[string]int) (string, models.User, error) {
+	rowError := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", errImportRow, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case row.ParseError != "":
+		return "", models.User{}, rowError("%s", row.ParseError)
+	case row.UserId == "":
+		return "", models.User{}, rowError("user_id is required")
+	case row.Username == "":
+		return "", models.User{}, rowError("username is required")
+	case row.TeamName == "":
+		return "", models.User{}, rowError("team_name is required")
+	}
+	if first, dup := seen[row.UserId]; dup {
+		return "", models.User{}, rowError("duplicate user_id, first seen in row %d", first)
+	}
+
+	user := models.User{UserId: row.UserId, Username: row.Username, TeamName: row.TeamName, IsActive: row.IsActive}
+	existing, err := um.repo.GetUser(ctx, row.UserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.ImportActionCreate, user, nil
+	}
+	if err != nil {
+		return "", models.User{}, fmt.Errorf("failed to get user %s: %w", row.UserId, err)
+	}
+	if *existing == user {
+		return models.ImportActionUnchanged, user, nil
+	}
+
+	switch policy {
+	case models.ImportConflictUpdate:
+		return models.ImportActionUpdate, user, nil
+	case models.ImportConflictFail:
+		return "", models.User{}, rowError("user already exists")
+	default:
+		return models.ImportActionSkip, user, nil
+	}
+}
+
+// teamExists сообщает, сохранена ли команда в репозитории.
+func (um *UserManager) teamExists(ctx context.Context, teamName string) (bool, error) {
+	_, err := um.repo.GetTeam(ctx, teamName)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get team %s: %w", teamName, err)
+	}
+	return true, nil
+}
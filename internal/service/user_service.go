@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
 )
 
 const UserNumber = 200
@@ -27,11 +31,24 @@ type UserTeamRepository interface {
 	UserRepository
 	TeamRepository
 	CreateTeamWithMembers(ctx context.Context, team *models.Team, users []models.User) error
+	CreateTeamsWithMembers(ctx context.Context, teamNames []string, users []models.User) error
+}
+
+// AbsenceLookup сообщает, кто из пользователей отсутствует в заданный момент.
+type AbsenceLookup interface {
+	FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error)
 }
 
 type UserManager struct {
-	repo  UserTeamRepository
-	users map[string]*models.User
+	repo         UserTeamRepository
+	absences     AbsenceLookup
+	workingHours WorkingHoursLookup
+	reviewLoad   ReviewLoadLookup
+	affinity     AffinityRuleLookup
+	// defaultMaxReviews — лимит открытых ревью для пользователей без личного; 0 — без лимита.
+	defaultMaxReviews int
+	// users — кэш пользователей по организациям: организация → user_id → пользователь.
+	users map[string]map[string]*models.User
 	mu    sync.RWMutex
 }
 
@@ -39,13 +56,105 @@ type UserManager struct {
 func NewUserManager(repo UserTeamRepository) *UserManager {
 	return &UserManager{
 		repo:  repo,
-		users: make(map[string]*models.User, UserNumber),
+		users: make(map[string]map[string]*models.User),
 		mu:    sync.RWMutex{},
 	}
 }
 
+// cachedUsers возвращает кэш организации из контекста (nil, если он пуст); вызывается под блокировкой.
+func (um *UserManager) cachedUsers(ctx context.Context) map[string]*models.User {
+	return um.users[tenant.Organization(ctx)]
+}
+
+// userCache возвращает кэш организации из контекста, создавая его; вызывается под блокировкой на запись.
+func (um *UserManager) userCache(ctx context.Context) map[string]*models.User {
+	organizationID := tenant.Organization(ctx)
+	cache, ok := um.users[organizationID]
+	if !ok {
+		cache = make(map[string]*models.User, UserNumber)
+		um.users[organizationID] = cache
+	}
+	return cache
+}
+
+// SetWorkingHours подключает источник рабочего времени; без него все пользователи считаются работающими.
+func (um *UserManager) SetWorkingHours(lookup WorkingHoursLookup) {
+	um.workingHours = lookup
+}
+
+// SetReviewLoad подключает источник нагрузки ревьюеров и лимит по умолчанию (0 — без лимита);
+// без источника нагрузка и лимиты при выборе ревьюеров не учитываются.
+func (um *UserManager) SetReviewLoad(lookup ReviewLoadLookup, defaultMax int) {
+	um.reviewLoad = lookup
+	um.defaultMaxReviews = defaultMax
+}
+
+// RankCandidates возвращает пользователей с нагрузкой в порядке предпочтения: сначала те, у кого в момент at
+// рабочее время, затем наименее загруженные, при равенстве — по user_id. Пользователи с исчерпанным лимитом
+// тоже возвращаются: отбрасывать их или нет, решает вызывающий.
+func (um *UserManager) RankCandidates(ctx context.Context, userIDs []string, at time.Time) ([]ReviewCandidate, error) {
+	schedules, err := loadSchedules(ctx, um.workingHours, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find working hours: %w", err)
+	}
+	loads := make(map[string]models.ReviewerLoad, len(userIDs))
+	if um.reviewLoad != nil && len(userIDs) > 0 {
+		found, err := um.reviewLoad.FindReviewLoad(ctx, userIDs)
+		if err != nil {
+			return nil, fmt.Errorf("find review load: %w", err)
+		}
+		for _, load := range found {
+			loads[load.UserId] = effectiveLoad(load, um.defaultMaxReviews)
+		}
+	}
+
+	candidates := make([]ReviewCandidate, 0, len(userIDs))
+	for _, id := range userIDs {
+		load, ok := loads[id]
+		if !ok {
+			load = models.ReviewerLoad{UserId: id}
+		}
+		c := ReviewCandidate{ReviewerLoad: load}
+		if sched, ok := schedules[id]; ok {
+			c.OffHours = !sched.contains(at)
+		}
+		candidates = append(candidates, c)
+	}
+	sortCandidates(candidates)
+	return candidates, nil
+}
+
+// SetAffinityRules подключает правила подбора ревьюеров команд; без них действуют только активность, отсутствия и лимиты.
+func (um *UserManager) SetAffinityRules(lookup AffinityRuleLookup) {
+	um.affinity = lookup
+}
+
+// SetAbsences подключает источник периодов отсутствия; без него отсутствия при выборе ревьюеров не учитываются.
+func (um *UserManager) SetAbsences(lookup AbsenceLookup) {
+	um.absences = lookup
+}
+
+// AbsentUsers возвращает множество пользователей, отсутствующих в момент at.
+func (um *UserManager) AbsentUsers(ctx context.Context, at time.Time) (map[string]struct{}, error) {
+	absent := make(map[string]struct{})
+	if um.absences == nil {
+		return absent, nil
+	}
+	ids, err := um.absences.FindAbsentUsers(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		absent[id] = struct{}{}
+	}
+	return absent, nil
+}
+
 // PrimeCacheUser загружает пользователя из репозитория для прогрева кэша.
-func (um *UserManager) PrimeCacheUser(ctx context.Context, userID string) error {
+func (um *UserManager) PrimeCacheUser(ctx context.Context, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.PrimeCacheUser")
+	defer func() { endSpan(span, err) }()
+
 	if um.repo == nil {
 		return fmt.Errorf("repository is not configured")
 	}
@@ -57,34 +166,168 @@ func (um *UserManager) PrimeCacheUser(ctx context.Context, userID string) error
 
 	um.mu.Lock()
 	defer um.mu.Unlock()
-	um.users[user.UserId] = user
+	um.userCache(ctx)[user.UserId] = user
 	return nil
 }
 
-// AssignRewiers выбирает до двух активных ревьюеров указанной команды.
-func (um *UserManager) AssignRewiers(teamId string) []string {
-	counter := 0
-	ans := make([]string, 0, 2)
+// AssignRewiers выбирает до demand.Count активных и присутствующих ревьюеров команды вне exclude в порядке RankCandidates
+// с соблюдением правил подбора команды. Ревьюеры с исчерпанным лимитом не назначаются; сколько мест из-за них
+// осталось пустыми, сообщает Pending. Срочному PR достаются наименее загруженные ревьюеры, при нехватке — и сверх
+// лимита, поэтому Pending для него всегда 0. Если правило ALWAYS_INCLUDE или REQUIRE_PAIR выполнить нельзя,
+// возвращается domain.RejectedCandidatesError с объяснениями.
+func (um *UserManager) AssignRewiers(ctx context.Context, teamId string, exclude []string, demand ReviewDemand) (_ *ReviewerSelection, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.AssignRewiers")
+	defer func() { endSpan(span, err) }()
+
+	now := time.Now()
+	// Без списка отсутствующих назначаем как раньше: лучше лишний ревьюер, чем PR без ревью.
+	absent, err := um.AbsentUsers(ctx, now)
+	if err != nil {
+		slog.WarnContext(ctx, "absences are ignored in reviewer selection", "err", err.Error())
+	}
+	pool, constraints, err := um.candidatePool(ctx, teamId, exclude, absent, demand, now)
+	if err != nil {
+		return nil, err
+	}
+	saturated := pool.saturated()
+	considered := slices.Clone(pool.candidates)
+
+	taken := make(map[string]struct{}, demand.Count)
+	selection := &ReviewerSelection{Reviewers: constraints.pick(pool, taken, demand.Count, demand.Weight)}
+	if !demand.Urgent {
+		selection.Pending = min(demand.Count-len(selection.Reviewers), saturated)
+	}
+	if unmet := constraints.unmet(); len(unmet) > 0 {
+		// Нужный правилу ревьюер занят до лимита: PR подождёт его в очереди, а не получит ревьюера в обход правила.
+		if !constraints.blockedByCapacity(pool, unmet[0]) {
+			return nil, constraints.failure(pool, demand.PullRequestId)
+		}
+		selection.Pending = max(selection.Pending, 1)
+	}
+	selection.Decision = explainSelection(demand.PullRequestId, teamId, um.teamMemberIDs(ctx, teamId),
+		considered, nil, pool, constraints, selection.Reviewers)
+	selection.Decision.Pending = selection.Pending
+	return selection, nil
+}
+
+// candidatePool ранжирует активных и присутствующих участников команды вне exclude и готовит проверку правил
+// подбора команды для demand.
+func (um *UserManager) candidatePool(
+	ctx context.Context,
+	teamName string,
+	exclude []string,
+	absent map[string]struct{},
+	demand ReviewDemand,
+	now time.Time,
+) (*reviewerPool, *affinityConstraints, error) {
+	excludeSet := make(map[string]bool, len(exclude)+len(absent))
+	for _, id := range exclude {
+		excludeSet[id] = true
+	}
+	for id := range absent {
+		excludeSet[id] = true
+	}
+
+	if err := um.refreshTeam(ctx, teamName); err != nil {
+		return nil, nil, err
+	}
+	rules, err := findAffinityRules(ctx, um.affinity, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	ranked, err := um.RankCandidates(ctx, um.activeTeamMembers(ctx, teamName, excludeSet), now)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := newReviewerPool(ranked)
+	pool.urgent = demand.Urgent
+
+	unavailable := func(userID string) string {
+		if _, away := absent[userID]; away {
+			return "is absent"
+		}
+		if excludeSet[userID] {
+			return "is already assigned or being replaced"
+		}
+		um.mu.RLock()
+		user, ok := um.cachedUsers(ctx)[userID]
+		um.mu.RUnlock()
+		switch {
+		case !ok || user.TeamName != teamName:
+			return "is not a member of team " + teamName
+		case !user.IsActive:
+			return "is inactive"
+		}
+		return "is not a candidate"
+	}
+	return pool, newAffinityConstraints(rules, demand.Author, demand.Reviewers, unavailable), nil
+}
+
+// refreshTeam перечитывает участников команды из репозитория в кэш организации.
+// Кэш заполняют только запросы, прошедшие через этот процесс, поэтому перед подбором кандидатов
+// состав команды берётся из хранилища: иначе другой инстанс, рестарт или импорт из CLI оставляют
+// команду без кандидатов. Участники, ушедшие из команды, из кэша удаляются и подгрузятся заново по запросу.
+func (um *UserManager) refreshTeam(ctx context.Context, teamName string) error {
+	if um.repo == nil {
+		return nil
+	}
+	users, err := um.repo.GetAllUsersInTeam(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("load members of team %s: %w", teamName, err)
+	}
+
 	um.mu.Lock()
 	defer um.mu.Unlock()
-	for _, user := range um.users {
-		if user.TeamName == teamId && user.IsActive {
-			ans = append(ans, user.UserId)
-			counter++
+	cache := um.userCache(ctx)
+	for id, user := range cache {
+		if user.TeamName == teamName {
+			delete(cache, id)
 		}
-		if counter == 2 {
-			break
+	}
+	for _, user := range users {
+		cache[user.UserId] = user
+	}
+	return nil
+}
+
+// activeTeamMembers возвращает активных участников команды вне exclude.
+func (um *UserManager) activeTeamMembers(ctx context.Context, teamName string, exclude map[string]bool) []string {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	members := make([]string, 0)
+	for _, user := range um.cachedUsers(ctx) {
+		if user.TeamName == teamName && user.IsActive && !exclude[user.UserId] {
+			members = append(members, user.UserId)
+		}
+	}
+	return members
+}
+
+// teamMemberIDs возвращает всех участников команды из кэша, включая неактивных.
+func (um *UserManager) teamMemberIDs(ctx context.Context, teamName string) []string {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	members := make([]string, 0)
+	for _, user := range um.cachedUsers(ctx) {
+		if user.TeamName == teamName {
+			members = append(members, user.UserId)
 		}
 	}
-	return ans
+	return members
 }
 
 // SetActivity обновляет признак активности выбранных пользователей в кэше.
-func (um *UserManager) SetActivity(rewIds []string, status bool) error {
+func (um *UserManager) SetActivity(ctx context.Context, rewIds []string, status bool) error {
+	_, span := tracer.Start(ctx, "UserManager.SetActivity")
+	defer span.End()
+
 	um.mu.Lock()
 	defer um.mu.Unlock()
+	cache := um.cachedUsers(ctx)
 	for _, el := range rewIds {
-		if user, exists := um.users[el]; exists {
+		if user, exists := cache[el]; exists {
 			user.IsActive = status
 		}
 		// Если пользователя нет в кэше, пропускаем его.
@@ -93,7 +336,10 @@ func (um *UserManager) SetActivity(rewIds []string, status bool) error {
 }
 
 // AddTeam сохраняет новую команду и пополняет кэш её участниками.
-func (um *UserManager) AddTeam(ctx context.Context, team models.Team) error {
+func (um *UserManager) AddTeam(ctx context.Context, team models.Team) (err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.AddTeam")
+	defer func() { endSpan(span, err) }()
+
 	// Проверяем участников на пустые и дублирующиеся идентификаторы.
 	userIDs := make(map[string]bool)
 	for i, m := range team.Members {
@@ -120,26 +366,28 @@ func (um *UserManager) AddTeam(ctx context.Context, team models.Team) error {
 		}
 	} else {
 		// Если репозитория нет, ограничиваемся сообщением в лог.
-		fmt.Printf("No repository configured, only updating cache for team %s\n", team.TeamName)
+		slog.WarnContext(ctx, "no repository configured, only updating cache", "team", team.TeamName)
 	}
 
 	// Кэш обновляем только после успешной записи в базу.
 	um.mu.Lock()
 	defer um.mu.Unlock()
 
-	fmt.Printf("Adding %d members to team %s cache\n", len(team.Members), team.TeamName)
+	cache := um.userCache(ctx)
 	for _, user := range users {
-	userCopy := user // фиксируем копию, чтобы карта указывала на отдельные структуры.
-		fmt.Printf("Adding user %s (%s) to cache\n", userCopy.UserId, userCopy.Username)
-		um.users[userCopy.UserId] = &userCopy
+		userCopy := user // фиксируем копию, чтобы карта указывала на отдельные структуры.
+		cache[userCopy.UserId] = &userCopy
 	}
-	fmt.Printf("Total users in cache: %d\n", len(um.users))
+	slog.DebugContext(ctx, "team members cached", "team", team.TeamName, "members", len(users), "cache_size", len(cache))
 
 	return nil
 }
 
 // GetTeam возвращает команду, используя репозиторий либо кэш.
-func (um *UserManager) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+func (um *UserManager) GetTeam(ctx context.Context, teamName string) (_ *models.Team, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.GetTeam")
+	defer func() { endSpan(span, err) }()
+
 	// Сначала спрашиваем репозиторий — он источник истины.
 	if um.repo != nil {
 		team, err := um.repo.GetTeam(ctx, teamName)
@@ -161,7 +409,7 @@ func (um *UserManager) GetTeam(ctx context.Context, teamName string) (*models.Te
 
 	var members []models.TeamMember
 
-	for _, user := range um.users {
+	for _, user := range um.cachedUsers(ctx) {
 		if user.TeamName == teamName {
 			member := models.ConvertUserToTeamMember(*user)
 			members = append(members, member)
@@ -173,7 +421,7 @@ func (um *UserManager) GetTeam(ctx context.Context, teamName string) (*models.Te
 		return nil, domain.NewNotFoundError("team")
 	}
 
-	fmt.Printf("Built team %s from cache with %d members\n", teamName, len(members))
+	slog.DebugContext(ctx, "team built from cache", "team", teamName, "members", len(members))
 
 	team := &models.Team{
 		TeamName: teamName,
@@ -184,9 +432,12 @@ func (um *UserManager) GetTeam(ctx context.Context, teamName string) (*models.Te
 }
 
 // GetUserTeam ищет команду пользователя и при необходимости подгружает данные из репозитория.
-func (um *UserManager) GetUserTeam(userID string) (string, error) {
+func (um *UserManager) GetUserTeam(ctx context.Context, userID string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.GetUserTeam")
+	defer func() { endSpan(span, err) }()
+
 	um.mu.RLock()
-	user, exists := um.users[userID]
+	user, exists := um.cachedUsers(ctx)[userID]
 	um.mu.RUnlock()
 	if exists {
 		return user.TeamName, nil
@@ -196,8 +447,7 @@ func (um *UserManager) GetUserTeam(userID string) (string, error) {
 		return "", domain.NewNotFoundError("user")
 	}
 
-	ctx := context.Background()
-	user, err := um.repo.GetUser(ctx, userID)
+	user, err = um.repo.GetUser(ctx, userID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			return "", domain.NewNotFoundError("user")
@@ -206,43 +456,69 @@ func (um *UserManager) GetUserTeam(userID string) (string, error) {
 	}
 
 	um.mu.Lock()
-	um.users[user.UserId] = user
+	um.userCache(ctx)[user.UserId] = user
 	um.mu.Unlock()
 
 	return user.TeamName, nil
 }
 
-// FindReplacementReviewer подбирает замену активному ревьюеру, исключая заданные ID.
-func (um *UserManager) FindReplacementReviewer(teamName string, excludeUserIDs []string) (string, error) {
-	um.mu.RLock()
-	defer um.mu.RUnlock()
+// FindReplacementReviewer подбирает замену ревьюеру среди активных и присутствующих участников команды
+// вне excludeUserIDs в порядке RankCandidates с соблюдением правил подбора команды для demand;
+// участники с исчерпанным лимитом не рассматриваются. Выбранная замена — единственный ревьюер в результате.
+// Если замены нет из-за правил, возвращается domain.RejectedCandidatesError с объяснениями.
+func (um *UserManager) FindReplacementReviewer(ctx context.Context, teamName string, excludeUserIDs []string, demand ReviewDemand) (_ *ReviewerSelection, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.FindReplacementReviewer")
+	defer func() { endSpan(span, err) }()
 
-	// Создаем множество для быстрой проверки исключенных пользователей
-	excludeSet := make(map[string]bool, len(excludeUserIDs))
-	for _, id := range excludeUserIDs {
-		excludeSet[id] = true
+	now := time.Now()
+	absent, err := um.AbsentUsers(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("find absent users: %w", err)
 	}
+	pool, constraints, err := um.candidatePool(ctx, teamName, excludeUserIDs, absent, demand, now)
+	if err != nil {
+		return nil, err
+	}
+	considered := slices.Clone(pool.candidates)
 
-	// Ищем активного пользователя из команды, которого нет в исключениях
-	for _, user := range um.users {
-		if user.TeamName == teamName &&
-			user.IsActive &&
-			!excludeSet[user.UserId] {
-			return user.UserId, nil
+	picked := constraints.pick(pool, make(map[string]struct{}, 1), 1, demand.Weight)
+	if len(picked) == 0 || len(constraints.unmet()) > 0 {
+		if err := constraints.failure(pool, demand.PullRequestId); err != nil {
+			return nil, err
 		}
+		return nil, domain.ErrNoCandidate
 	}
-
-	return "", domain.ErrNoCandidate
+	return &ReviewerSelection{
+		Reviewers: picked,
+		Decision: explainSelection(demand.PullRequestId, teamName, um.teamMemberIDs(ctx, teamName),
+			considered, nil, pool, constraints, picked),
+	}, nil
 }
 
 // SetUserActivity меняет активность пользователя и синхронизирует её с хранилищем.
-func (um *UserManager) SetUserActivity(userID string, isActive bool) (*models.User, error) {
+// Пользователя, которого нет в кэше, подгружает из репозитория.
+func (um *UserManager) SetUserActivity(ctx context.Context, userID string, isActive bool) (_ *models.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserManager.SetUserActivity")
+	defer func() { endSpan(span, err) }()
+
 	um.mu.Lock()
 	defer um.mu.Unlock()
 
-	user, exists := um.users[userID]
+	user, exists := um.cachedUsers(ctx)[userID]
 	if !exists {
-		return nil, domain.NewNotFoundError("user")
+		if um.repo == nil {
+			return nil, domain.NewNotFoundError("user")
+		}
+		// Пользователь мог появиться в хранилище мимо этого процесса (импорт из CLI, другой инстанс).
+		loaded, err := um.repo.GetUser(ctx, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.NewNotFoundError("user")
+			}
+			return nil, fmt.Errorf("failed to get user from repository: %w", err)
+		}
+		user = loaded
+		um.userCache(ctx)[user.UserId] = user
 	}
 
 	// Сохраняем исходное значение для возможного отката.
@@ -251,7 +527,6 @@ func (um *UserManager) SetUserActivity(userID string, isActive bool) (*models.Us
 
 	// При наличии репозитория фиксируем изменение в базе.
 	if um.repo != nil {
-		ctx := context.Background()
 		if err := um.repo.SaveUser(ctx, user); err != nil {
 			// Откат при ошибке сохранения.
 			user.IsActive = originalStatus
@@ -262,16 +537,31 @@ func (um *UserManager) SetUserActivity(userID string, isActive bool) (*models.Us
 	return user, nil
 }
 
+// CacheSize возвращает число пользователей в кэше всех организаций.
+func (um *UserManager) CacheSize() int {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+	size := 0
+	for _, cache := range um.users {
+		size += len(cache)
+	}
+	return size
+}
+
 // SyncUsersActivity массово обновляет флаг активности пользователей только в кэше.
-func (um *UserManager) SyncUsersActivity(userIDs []string, isActive bool) {
+func (um *UserManager) SyncUsersActivity(ctx context.Context, userIDs []string, isActive bool) {
+	_, span := tracer.Start(ctx, "UserManager.SyncUsersActivity")
+	defer span.End()
+
 	if len(userIDs) == 0 {
 		return
 	}
 
 	um.mu.Lock()
 	defer um.mu.Unlock()
+	cache := um.cachedUsers(ctx)
 	for _, id := range userIDs {
-		if user, ok := um.users[id]; ok {
+		if user, ok := cache[id]; ok {
 			user.IsActive = isActive
 		}
 	}
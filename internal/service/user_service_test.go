@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
@@ -17,6 +19,7 @@ type mockUserTeamRepository struct {
 	saveTeamFn              func(context.Context, *models.Team) error
 	getTeamFn               func(context.Context, string) (*models.Team, error)
 	createTeamWithMembersFn func(context.Context, *models.Team, []models.User) error
+	createTeamsFn           func(context.Context, []string, []models.User) error
 }
 
 func (m *mockUserTeamRepository) SaveUser(ctx context.Context, user *models.User) error {
@@ -61,6 +64,18 @@ func (m *mockUserTeamRepository) CreateTeamWithMembers(ctx context.Context, team
 	return m.createTeamWithMembersFn(ctx, team, users)
 }
 
+func (m *mockUserTeamRepository) CreateTeamsWithMembers(ctx context.Context, teamNames []string, users []models.User) error {
+	if m == nil || m.createTeamsFn == nil {
+		return nil
+	}
+	return m.createTeamsFn(ctx, teamNames, users)
+}
+
+// defaultCache возвращает кэш пользователей организации default.
+func defaultCache(um *UserManager) map[string]*models.User {
+	return um.userCache(context.Background())
+}
+
 func TestUserManager_PrimeCacheUser(t *testing.T) {
 	ctx := context.Background()
 	expectedUser := &models.User{UserId: "user-1", TeamName: "alpha", Username: "alpha-1", IsActive: true}
@@ -75,7 +90,7 @@ func TestUserManager_PrimeCacheUser(t *testing.T) {
 		t.Fatalf("PrimeCacheUser returned unexpected error: %v", err)
 	}
 
-	if got := manager.users[expectedUser.UserId]; got != expectedUser {
+	if got := defaultCache(manager)[expectedUser.UserId]; got != expectedUser {
 		t.Fatalf("PrimeCacheUser did not cache repo user")
 	}
 }
@@ -121,7 +136,7 @@ func TestUserManager_AddTeamPersistsAndCaches(t *testing.T) {
 	}
 
 	for _, member := range team.Members {
-		if _, ok := manager.users[member.UserId]; !ok {
+		if _, ok := defaultCache(manager)[member.UserId]; !ok {
 			t.Fatalf("user %s was not cached", member.UserId)
 		}
 	}
@@ -169,8 +184,8 @@ func TestUserManager_GetTeamFallsBackToCache(t *testing.T) {
 	}
 
 	manager := NewUserManager(repo)
-	manager.users["u1"] = &models.User{UserId: "u1", Username: "one", TeamName: "beta", IsActive: true}
-	manager.users["u2"] = &models.User{UserId: "u2", Username: "two", TeamName: "other", IsActive: true}
+	defaultCache(manager)["u1"] = &models.User{UserId: "u1", Username: "one", TeamName: "beta", IsActive: true}
+	defaultCache(manager)["u2"] = &models.User{UserId: "u2", Username: "two", TeamName: "other", IsActive: true}
 
 	got, err := manager.GetTeam(context.Background(), "beta")
 	if err != nil {
@@ -192,9 +207,9 @@ func TestUserManager_GetTeamNotFound(t *testing.T) {
 func TestUserManager_GetUserTeamPaths(t *testing.T) {
 	t.Run("cached user", func(t *testing.T) {
 		manager := NewUserManager(nil)
-		manager.users["u1"] = &models.User{UserId: "u1", TeamName: "alpha"}
+		defaultCache(manager)["u1"] = &models.User{UserId: "u1", TeamName: "alpha"}
 
-		team, err := manager.GetUserTeam("u1")
+		team, err := manager.GetUserTeam(context.Background(), "u1")
 		if err != nil || team != "alpha" {
 			t.Fatalf("expected cached team alpha, got %s (err=%v)", team, err)
 		}
@@ -208,18 +223,18 @@ func TestUserManager_GetUserTeamPaths(t *testing.T) {
 		}
 		manager := NewUserManager(repo)
 
-		team, err := manager.GetUserTeam("u2")
+		team, err := manager.GetUserTeam(context.Background(), "u2")
 		if err != nil || team != "beta" {
 			t.Fatalf("expected repo team beta, got %s (err=%v)", team, err)
 		}
-		if _, cached := manager.users["u2"]; !cached {
+		if _, cached := defaultCache(manager)["u2"]; !cached {
 			t.Fatalf("user should be cached after repo lookup")
 		}
 	})
 
 	t.Run("repo not configured", func(t *testing.T) {
 		manager := NewUserManager(nil)
-		if _, err := manager.GetUserTeam("missing"); err == nil || !errors.Is(err, domain.ErrNotFound) {
+		if _, err := manager.GetUserTeam(context.Background(), "missing"); err == nil || !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("expected not found when repo absent, got %v", err)
 		}
 	})
@@ -231,7 +246,7 @@ func TestUserManager_GetUserTeamPaths(t *testing.T) {
 			},
 		}
 		manager := NewUserManager(repo)
-		if _, err := manager.GetUserTeam("ghost"); err == nil || !errors.Is(err, domain.ErrNotFound) {
+		if _, err := manager.GetUserTeam(context.Background(), "ghost"); err == nil || !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("expected not found, got %v", err)
 		}
 	})
@@ -254,9 +269,9 @@ func TestUserManager_SetUserActivityPersistsAndRollsBack(t *testing.T) {
 	}
 
 	manager := NewUserManager(repo)
-	manager.users[user.UserId] = user
+	defaultCache(manager)[user.UserId] = user
 
-	updated, err := manager.SetUserActivity(user.UserId, false)
+	updated, err := manager.SetUserActivity(context.Background(), user.UserId, false)
 	if err != nil {
 		t.Fatalf("SetUserActivity returned unexpected error: %v", err)
 	}
@@ -270,61 +285,163 @@ func TestUserManager_SetUserActivityPersistsAndRollsBack(t *testing.T) {
 		},
 	}
 	managerFail := NewUserManager(repoFail)
-	managerFail.users[user.UserId] = &models.User{UserId: user.UserId, TeamName: "alpha", IsActive: true}
+	defaultCache(managerFail)[user.UserId] = &models.User{UserId: user.UserId, TeamName: "alpha", IsActive: true}
 
-	if _, err := managerFail.SetUserActivity(user.UserId, false); err == nil {
+	if _, err := managerFail.SetUserActivity(context.Background(), user.UserId, false); err == nil {
 		t.Fatalf("expected error from repo failure")
 	}
-	if !managerFail.users[user.UserId].IsActive {
+	if !defaultCache(managerFail)[user.UserId].IsActive {
 		t.Fatalf("user state should roll back on repo failure")
 	}
 }
 
+func TestUserManager_SetUserActivityLoadsUncachedUser(t *testing.T) {
+	repo := &mockUserTeamRepository{
+		getUserFn: func(_ context.Context, id string) (*models.User, error) {
+			if id != "u1" {
+				return nil, domain.NewNotFoundError("user")
+			}
+			return &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}, nil
+		},
+	}
+	manager := NewUserManager(repo)
+
+	updated, err := manager.SetUserActivity(context.Background(), "u1", false)
+	if err != nil || updated.IsActive {
+		t.Fatalf("expected uncached user to be loaded and deactivated, got %+v (err=%v)", updated, err)
+	}
+	if cached := defaultCache(manager)["u1"]; cached == nil || cached.IsActive {
+		t.Fatalf("loaded user must be cached with the new status, got %+v", cached)
+	}
+	if _, err := manager.SetUserActivity(context.Background(), "ghost", false); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for unknown user, got %v", err)
+	}
+}
+
+// replacedBy возвращает выбранную замену или пустую строку.
+func replacedBy(selection *ReviewerSelection) string {
+	if selection == nil || len(selection.Reviewers) == 0 {
+		return ""
+	}
+	return selection.Reviewers[0].UserId
+}
+
 func TestUserManager_FindReplacementReviewer(t *testing.T) {
 	manager := NewUserManager(nil)
-	manager.users["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
-	manager.users["u2"] = &models.User{UserId: "u2", TeamName: "alpha", IsActive: false}
-	manager.users["u3"] = &models.User{UserId: "u3", TeamName: "beta", IsActive: true}
+	defaultCache(manager)["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u2"] = &models.User{UserId: "u2", TeamName: "alpha", IsActive: false}
+	defaultCache(manager)["u3"] = &models.User{UserId: "u3", TeamName: "beta", IsActive: true}
 
-	repl, err := manager.FindReplacementReviewer("alpha", []string{"u2"})
-	if err != nil || repl != "u1" {
-		t.Fatalf("expected u1 replacement, got %s (err=%v)", repl, err)
+	repl, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"u2"}, ReviewDemand{Count: 1, Weight: 1})
+	if err != nil || replacedBy(repl) != "u1" {
+		t.Fatalf("expected u1 replacement, got %s (err=%v)", replacedBy(repl), err)
 	}
 
-	if _, err := manager.FindReplacementReviewer("alpha", []string{"u1", "u2"}); !errors.Is(err, domain.ErrNoCandidate) {
+	if _, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"u1", "u2"}, ReviewDemand{Count: 1, Weight: 1}); !errors.Is(err, domain.ErrNoCandidate) {
 		t.Fatalf("expected no candidate error, got %v", err)
 	}
 }
 
+func TestUserManager_FindReplacementReviewerReadsTeamFromRepository(t *testing.T) {
+	teamErr := error(nil)
+	repo := &mockUserTeamRepository{
+		getAllUsersInTeamFn: func(_ context.Context, teamID string) ([]*models.User, error) {
+			if teamErr != nil {
+				return nil, teamErr
+			}
+			return []*models.User{
+				{UserId: "u1", TeamName: teamID, IsActive: true},
+				{UserId: "u2", TeamName: teamID, IsActive: true},
+			}, nil
+		},
+	}
+	manager := NewUserManager(repo)
+	// u9 ушёл из команды мимо этого процесса: в кэше осталась устаревшая запись.
+	defaultCache(manager)["u9"] = &models.User{UserId: "u9", TeamName: "alpha", IsActive: true}
+
+	repl, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"u2"}, ReviewDemand{Count: 1, Weight: 1})
+	if err != nil || replacedBy(repl) != "u1" {
+		t.Fatalf("expected u1 from repository with a cold cache, got %s (err=%v)", replacedBy(repl), err)
+	}
+	if _, ok := defaultCache(manager)["u9"]; ok {
+		t.Fatalf("stale team member must be dropped from cache")
+	}
+
+	teamErr = errors.New("db down")
+	if _, err := manager.FindReplacementReviewer(context.Background(), "alpha", nil, ReviewDemand{Count: 1, Weight: 1}); err == nil || !strings.Contains(err.Error(), "db down") {
+		t.Fatalf("expected repository error, got %v", err)
+	}
+}
+
 func TestUserManager_AssignRewiersLimitsTwo(t *testing.T) {
 	manager := NewUserManager(nil)
-	manager.users["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
-	manager.users["u2"] = &models.User{UserId: "u2", TeamName: "alpha", IsActive: true}
-	manager.users["u3"] = &models.User{UserId: "u3", TeamName: "alpha", IsActive: true}
-	manager.users["u4"] = &models.User{UserId: "u4", TeamName: "alpha", IsActive: false}
-	manager.users["u5"] = &models.User{UserId: "u5", TeamName: "beta", IsActive: true}
+	defaultCache(manager)["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u2"] = &models.User{UserId: "u2", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u3"] = &models.User{UserId: "u3", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u4"] = &models.User{UserId: "u4", TeamName: "alpha", IsActive: false}
+	defaultCache(manager)["u5"] = &models.User{UserId: "u5", TeamName: "beta", IsActive: true}
 
-	reviewers := manager.AssignRewiers("alpha")
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", nil, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reviewers := selection.ReviewerIDs()
 	if len(reviewers) != 2 {
 		t.Fatalf("expected exactly 2 reviewers, got %v", reviewers)
 	}
 	for _, id := range reviewers {
-		user := manager.users[id]
+		user := defaultCache(manager)[id]
 		if user == nil || user.TeamName != "alpha" || !user.IsActive {
 			t.Fatalf("AssignRewiers returned invalid reviewer %s", id)
 		}
 	}
 }
 
+// absenceLookupFunc адаптирует функцию к AbsenceLookup.
+type absenceLookupFunc func(ctx context.Context, at time.Time) ([]string, error)
+
+func (f absenceLookupFunc) FindAbsentUsers(ctx context.Context, at time.Time) ([]string, error) {
+	return f(ctx, at)
+}
+
+func TestUserManager_SkipsAbsentReviewers(t *testing.T) {
+	manager := NewUserManager(nil)
+	defaultCache(manager)["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u2"] = &models.User{UserId: "u2", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u3"] = &models.User{UserId: "u3", TeamName: "alpha", IsActive: true}
+	manager.SetAbsences(absenceLookupFunc(func(context.Context, time.Time) ([]string, error) {
+		return []string{"u2"}, nil
+	}))
+
+	selection, err := manager.AssignRewiers(context.Background(), "alpha", nil, ReviewDemand{Count: 2, Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reviewers := selection.ReviewerIDs(); len(reviewers) != 2 || slices.Contains(reviewers, "u2") {
+		t.Fatalf("expected u1 and u3 as reviewers, got %v", reviewers)
+	}
+
+	if _, err := manager.FindReplacementReviewer(context.Background(), "alpha", []string{"u1", "u3"}, ReviewDemand{Count: 1, Weight: 1}); !errors.Is(err, domain.ErrNoCandidate) {
+		t.Fatalf("absent user must not replace a reviewer, got %v", err)
+	}
+
+	manager.SetAbsences(absenceLookupFunc(func(context.Context, time.Time) ([]string, error) {
+		return nil, errors.New("db down")
+	}))
+	if selection, err := manager.AssignRewiers(context.Background(), "alpha", nil, ReviewDemand{Count: 2, Weight: 1}); err != nil || len(selection.Reviewers) != 2 {
+		t.Fatalf("lookup failure must not block assignment, got %v (err=%v)", selection, err)
+	}
+}
+
 func TestUserManager_SetActivityUpdatesStatuses(t *testing.T) {
 	manager := NewUserManager(nil)
-	manager.users["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
+	defaultCache(manager)["u1"] = &models.User{UserId: "u1", TeamName: "alpha", IsActive: true}
 
-	if err := manager.SetActivity([]string{"u1", "missing"}, false); err != nil {
+	if err := manager.SetActivity(context.Background(), []string{"u1", "missing"}, false); err != nil {
 		t.Fatalf("SetActivity returned unexpected error: %v", err)
 	}
 
-	if manager.users["u1"].IsActive {
+	if defaultCache(manager)["u1"].IsActive {
 		t.Fatalf("SetActivity did not update cached user")
 	}
 }
@@ -340,3 +457,145 @@ func TestUserManager_PrimeCacheRepoError(t *testing.T) {
 		t.Fatalf("expected error when repo get user fails")
 	}
 }
+
+func TestParseUserImport(t *testing.T) {
+	csvRows, err := ParseUserImport(strings.NewReader("team_name,user_id,username\nbackend,u1,Alice\nbackend,u2\n"), models.ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected csv error: %v", err)
+	}
+	if len(csvRows) != 2 || csvRows[0].UserId != "u1" || csvRows[0].TeamName != "backend" || !csvRows[0].IsActive {
+		t.Fatalf("unexpected csv rows: %+v", csvRows)
+	}
+	if csvRows[1].ParseError == "" {
+		t.Fatalf("expected parse error for short row, got %+v", csvRows[1])
+	}
+
+	jsonRows, err := ParseUserImport(strings.NewReader(`[{"user_id":"u1","username":"Alice","team_name":"backend","is_active":false}]`), models.ImportFormatJSON)
+	if err != nil {
+		t.Fatalf("unexpected json error: %v", err)
+	}
+	if len(jsonRows) != 1 || jsonRows[0].Row != 1 || jsonRows[0].IsActive {
+		t.Fatalf("unexpected json rows: %+v", jsonRows)
+	}
+
+	if _, err := ParseUserImport(strings.NewReader(`{"user_id":"u1"}`), models.ImportFormatJSON); err == nil {
+		t.Fatal("expected error for non-array json")
+	}
+}
+
+func TestUserManager_ImportUsers(t *testing.T) {
+	ctx := context.Background()
+	existing := map[string]*models.User{
+		"u1": {UserId: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		"u2": {UserId: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+	}
+	newRepo := func(applied *[]string) *mockUserTeamRepository {
+		return &mockUserTeamRepository{
+			getUserFn: func(_ context.Context, id string) (*models.User, error) {
+				if u, ok := existing[id]; ok {
+					copyUser := *u
+					return &copyUser, nil
+				}
+				return nil, domain.NewNotFoundError("user")
+			},
+			getTeamFn: func(_ context.Context, name string) (*models.Team, error) {
+				if name == "backend" {
+					return &models.Team{TeamName: name}, nil
+				}
+				return nil, domain.NewNotFoundError("team")
+			},
+			createTeamsFn: func(_ context.Context, teams []string, users []models.User) error {
+				*applied = append(*applied, teams...)
+				for _, u := range users {
+					*applied = append(*applied, u.UserId)
+				}
+				return nil
+			},
+		}
+	}
+	rows := []models.ImportUserRow{
+		{Row: 1, UserId: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{Row: 2, UserId: "u2", Username: "Bobby", TeamName: "backend", IsActive: true},
+		{Row: 3, UserId: "u3", Username: "Carol", TeamName: "frontend", IsActive: true},
+	}
+
+	t.Run("skip policy", func(t *testing.T) {
+		var applied []string
+		manager := NewUserManager(newRepo(&applied))
+		report, err := manager.ImportUsers(ctx, rows, models.ImportOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !report.Applied || report.Created != 1 || report.Skipped != 2 || report.Updated != 0 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if strings.Join(applied, ",") != "frontend,u3" {
+			t.Fatalf("unexpected applied changes: %v", applied)
+		}
+		if report.Rows[0].Action != models.ImportActionUnchanged || report.Rows[1].Action != models.ImportActionSkip {
+			t.Fatalf("unexpected row actions: %+v", report.Rows)
+		}
+		if _, ok := defaultCache(manager)["u3"]; !ok {
+			t.Fatal("imported user must be cached")
+		}
+	})
+
+	t.Run("update policy", func(t *testing.T) {
+		var applied []string
+		manager := NewUserManager(newRepo(&applied))
+		report, err := manager.ImportUsers(ctx, rows, models.ImportOptions{OnConflict: models.ImportConflictUpdate})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Updated != 1 || report.Created != 1 || strings.Join(report.TeamsCreated, ",") != "frontend" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if strings.Join(applied, ",") != "frontend,u2,u3" {
+			t.Fatalf("unexpected applied changes: %v", applied)
+		}
+	})
+
+	t.Run("fail policy rejects whole file", func(t *testing.T) {
+		var applied []string
+		manager := NewUserManager(newRepo(&applied))
+		report, err := manager.ImportUsers(ctx, rows, models.ImportOptions{OnConflict: models.ImportConflictFail})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Applied || report.Failed != 1 || report.Rows[1].Error != "user already exists" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if len(applied) != 0 {
+			t.Fatalf("nothing must be applied, got %v", applied)
+		}
+	})
+
+	t.Run("dry run and invalid rows", func(t *testing.T) {
+		var applied []string
+		manager := NewUserManager(newRepo(&applied))
+		report, err := manager.ImportUsers(ctx, []models.ImportUserRow{
+			{Row: 1, UserId: "u4", Username: "Dan", TeamName: "ops"},
+			{Row: 2, UserId: "u4", Username: "Dan", TeamName: "ops"},
+			{Row: 3, UserId: "u5", TeamName: "ops"},
+		}, models.ImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Applied || report.Created != 1 || report.Failed != 2 || len(applied) != 0 {
+			t.Fatalf("unexpected report: %+v, applied %v", report, applied)
+		}
+		if report.Rows[1].Error != "duplicate user_id, first seen in row 1" || report.Rows[2].Error != "username is required" {
+			t.Fatalf("unexpected row errors: %+v", report.Rows)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		manager := NewUserManager(&mockUserTeamRepository{})
+		if _, err := manager.ImportUsers(ctx, rows, models.ImportOptions{OnConflict: "merge"}); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("expected invalid param, got %v", err)
+		}
+		if _, err := manager.ImportUsers(ctx, nil, models.ImportOptions{}); !errors.Is(err, domain.ErrInvalidParam) {
+			t.Fatalf("expected invalid param for empty file, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	// База часовых поясов встроена в бинарник, чтобы расписания работали без zoneinfo в системе.
+	_ "time/tzdata"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+// clockLayout — формат начала и конца рабочего дня.
+const clockLayout = "15:04"
+
+// WorkingHoursRepository хранит часовые пояса и рабочее время пользователей.
+type WorkingHoursRepository interface {
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	// SaveWorkingHours создаёт или заменяет рабочее время пользователя.
+	SaveWorkingHours(ctx context.Context, wh *models.WorkingHours) error
+	GetWorkingHours(ctx context.Context, userID string) (*models.WorkingHours, error)
+	DeleteWorkingHours(ctx context.Context, userID string) error
+	WorkingHoursLookup
+}
+
+// WorkingHoursLookup возвращает рабочее время перечисленных пользователей; у кого его нет, пропускаются.
+type WorkingHoursLookup interface {
+	FindWorkingHours(ctx context.Context, userIDs []string) ([]models.WorkingHours, error)
+}
+
+// WorkingHoursManager настраивает часовые пояса и рабочее время пользователей.
+type WorkingHoursManager struct {
+	repo WorkingHoursRepository
+}
+
+// NewWorkingHoursManager создаёт менеджер рабочего времени.
+func NewWorkingHoursManager(repo WorkingHoursRepository) *WorkingHoursManager {
+	return &WorkingHoursManager{repo: repo}
+}
+
+// SetWorkingHours проверяет и сохраняет рабочее время пользователя.
+func (wm *WorkingHoursManager) SetWorkingHours(ctx context.Context, req models.WorkingHours) (_ *models.WorkingHours, err error) {
+	ctx, span := tracer.Start(ctx, "WorkingHoursManager.SetWorkingHours")
+	defer func() { endSpan(span, err) }()
+
+	req.TimeZone = strings.TrimSpace(req.TimeZone)
+	sched, err := parseSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.ensureUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	wh := sched.workingHours(req.UserId)
+	if err := wm.repo.SaveWorkingHours(ctx, &wh); err != nil {
+		return nil, fmt.Errorf("failed to save working hours: %w", err)
+	}
+	return &wh, nil
+}
+
+// WorkingHours возвращает рабочее время пользователя; NOT_FOUND, если оно не задано.
+func (wm *WorkingHoursManager) WorkingHours(ctx context.Context, userID string) (_ *models.WorkingHours, err error) {
+	ctx, span := tracer.Start(ctx, "WorkingHoursManager.WorkingHours")
+	defer func() { endSpan(span, err) }()
+
+	if err := wm.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	wh, err := wm.repo.GetWorkingHours(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("working hours")
+		}
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+	return wh, nil
+}
+
+// ClearWorkingHours удаляет рабочее время пользователя и возвращает его; дальше пользователь считается доступным всегда.
+func (wm *WorkingHoursManager) ClearWorkingHours(ctx context.Context, userID string) (_ *models.WorkingHours, err error) {
+	ctx, span := tracer.Start(ctx, "WorkingHoursManager.ClearWorkingHours")
+	defer func() { endSpan(span, err) }()
+
+	wh, err := wm.WorkingHours(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := wm.repo.DeleteWorkingHours(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("working hours")
+		}
+		return nil, fmt.Errorf("failed to delete working hours: %w", err)
+	}
+	return wh, nil
+}
+
+// ensureUser возвращает NOT_FOUND, если пользователя нет.
+func (wm *WorkingHoursManager) ensureUser(ctx context.Context, userID string) error {
+	if _, err := wm.repo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("user")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
+
+// schedule — разобранное рабочее время: пояс и границы дня в минутах от полуночи.
+type schedule struct {
+	loc        *time.Location
+	start, end int
+}
+
+// parseSchedule проверяет рабочее время и переводит его во внутреннее представление.
+func parseSchedule(wh models.WorkingHours) (schedule, error) {
+	if wh.TimeZone == "" {
+		return schedule{}, domain.NewInvalidParamError("time_zone", "is required")
+	}
+	loc, err := time.LoadLocation(wh.TimeZone)
+	if err != nil {
+		return schedule{}, domain.NewInvalidParamError("time_zone", "must be an IANA time zone name")
+	}
+	start, err := parseClock(wh.Start)
+	if err != nil {
+		return schedule{}, domain.NewInvalidParamError("start", "must be HH:MM")
+	}
+	end, err := parseClock(wh.End)
+	if err != nil {
+		return schedule{}, domain.NewInvalidParamError("end", "must be HH:MM")
+	}
+	if end <= start {
+		return schedule{}, domain.NewInvalidParamError("end", "must be after start")
+	}
+	return schedule{loc: loc, start: start, end: end}, nil
+}
+
+// parseClock переводит HH:MM в минуты от полуночи.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse(clockLayout, strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// workingHours возвращает расписание в виде модели с нормализованными границами дня.
+func (s schedule) workingHours(userID string) models.WorkingHours {
+	clock := func(minutes int) string {
+		return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+	}
+	return models.WorkingHours{UserId: userID, TimeZone: s.loc.String(), Start: clock(s.start), End: clock(s.end)}
+}
+
+// contains сообщает, приходится ли момент at на рабочее время.
+func (s schedule) contains(at time.Time) bool {
+	local := at.In(s.loc)
+	if !isWorkday(local.Weekday()) {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	return minute >= s.start && minute < s.end
+}
+
+// workingTime возвращает, сколько рабочего времени прошло в промежутке [from, to).
+// Границы дня строятся в местном времени, поэтому переходы на летнее время учитываются.
+func (s schedule) workingTime(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	var total time.Duration
+	local := from.In(s.loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !isWorkday(day.Weekday()) {
+			continue
+		}
+		open := time.Date(day.Year(), day.Month(), day.Day(), s.start/60, s.start%60, 0, 0, s.loc)
+		closing := time.Date(day.Year(), day.Month(), day.Day(), s.end/60, s.end%60, 0, 0, s.loc)
+		if open.Before(from) {
+			open = from
+		}
+		if closing.After(to) {
+			closing = to
+		}
+		if closing.After(open) {
+			total += closing.Sub(open)
+		}
+	}
+	return total
+}
+
+// isWorkday сообщает, рабочий ли день недели.
+func isWorkday(d time.Weekday) bool {
+	return d != time.Saturday && d != time.Sunday
+}
+
+// loadSchedules возвращает расписания пользователей по ID; без lookup результат пуст.
+// Некорректные записи журналируются и пропускаются: такой пользователь считается доступным всегда.
+func loadSchedules(ctx context.Context, lookup WorkingHoursLookup, userIDs []string) (map[string]schedule, error) {
+	schedules := make(map[string]schedule)
+	if lookup == nil || len(userIDs) == 0 {
+		return schedules, nil
+	}
+	hours, err := lookup.FindWorkingHours(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, wh := range hours {
+		sched, err := parseSchedule(wh)
+		if err != nil {
+			slog.WarnContext(ctx, "working hours ignored", "user_id", wh.UserId, "err", err.Error())
+			continue
+		}
+		schedules[wh.UserId] = sched
+	}
+	return schedules, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type mockWorkingHoursRepository struct {
+	users map[string]bool
+	hours map[string]models.WorkingHours
+}
+
+func newMockWorkingHoursRepository(users ...string) *mockWorkingHoursRepository {
+	m := &mockWorkingHoursRepository{users: map[string]bool{}, hours: map[string]models.WorkingHours{}}
+	for _, id := range users {
+		m.users[id] = true
+	}
+	return m
+}
+
+func (m *mockWorkingHoursRepository) GetUser(_ context.Context, userID string) (*models.User, error) {
+	if !m.users[userID] {
+		return nil, domain.NewNotFoundError("user " + userID)
+	}
+	return &models.User{UserId: userID}, nil
+}
+
+func (m *mockWorkingHoursRepository) SaveWorkingHours(_ context.Context, wh *models.WorkingHours) error {
+	m.hours[wh.UserId] = *wh
+	return nil
+}
+
+func (m *mockWorkingHoursRepository) GetWorkingHours(_ context.Context, userID string) (*models.WorkingHours, error) {
+	wh, ok := m.hours[userID]
+	if !ok {
+		return nil, domain.NewNotFoundError("working hours")
+	}
+	return &wh, nil
+}
+
+func (m *mockWorkingHoursRepository) DeleteWorkingHours(_ context.Context, userID string) error {
+	if _, ok := m.hours[userID]; !ok {
+		return domain.NewNotFoundError("working hours")
+	}
+	delete(m.hours, userID)
+	return nil
+}
+
+func (m *mockWorkingHoursRepository) FindWorkingHours(_ context.Context, userIDs []string) ([]models.WorkingHours, error) {
+	var result []models.WorkingHours
+	for _, id := range userIDs {
+		if wh, ok := m.hours[id]; ok {
+			result = append(result, wh)
+		}
+	}
+	return result, nil
+}
+
+func TestSetWorkingHoursValidates(t *testing.T) {
+	wm := NewWorkingHoursManager(newMockWorkingHoursRepository("u1"))
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		req  models.WorkingHours
+		want error
+	}{
+		{"missing zone", models.WorkingHours{UserId: "u1", Start: "09:00", End: "17:00"}, domain.ErrInvalidParam},
+		{"unknown zone", models.WorkingHours{UserId: "u1", TimeZone: "Mars/Olympus", Start: "09:00", End: "17:00"}, domain.ErrInvalidParam},
+		{"bad start", models.WorkingHours{UserId: "u1", TimeZone: "UTC", Start: "9am", End: "17:00"}, domain.ErrInvalidParam},
+		{"end before start", models.WorkingHours{UserId: "u1", TimeZone: "UTC", Start: "17:00", End: "09:00"}, domain.ErrInvalidParam},
+		{"unknown user", models.WorkingHours{UserId: "ghost", TimeZone: "UTC", Start: "09:00", End: "17:00"}, domain.ErrNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := wm.SetWorkingHours(ctx, tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestWorkingHoursLifecycle(t *testing.T) {
+	repo := newMockWorkingHoursRepository("u1")
+	wm := NewWorkingHoursManager(repo)
+	ctx := context.Background()
+
+	saved, err := wm.SetWorkingHours(ctx, models.WorkingHours{UserId: "u1", TimeZone: " Asia/Tokyo ", Start: "9:30", End: "18:00"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.WorkingHours{UserId: "u1", TimeZone: "Asia/Tokyo", Start: "09:30", End: "18:00"}
+	if *saved != want || repo.hours["u1"] != want {
+		t.Fatalf("expected normalized %+v, got %+v", want, saved)
+	}
+
+	if _, err := wm.ClearWorkingHours(ctx, "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := wm.WorkingHours(ctx, "u1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected NOT_FOUND after clear, got %v", err)
+	}
+}
+
+func TestScheduleWorkingTime(t *testing.T) {
+	berlin, err := parseSchedule(models.WorkingHours{TimeZone: "Europe/Berlin", Start: "09:00", End: "17:00"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Пятница 16:00 по Берлину — понедельник 10:00: час в пятницу и час в понедельник, выходные не в счёт.
+	from := time.Date(2025, time.March, 7, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	if got := berlin.workingTime(from, to); got != 2*time.Hour {
+		t.Fatalf("expected 2h of working time, got %v", got)
+	}
+	if got := berlin.workingTime(to, from); got != 0 {
+		t.Fatalf("expected no working time for a reversed range, got %v", got)
+	}
+
+	if !berlin.contains(to) {
+		t.Fatal("Monday 10:00 in Berlin is within working hours")
+	}
+	if berlin.contains(time.Date(2025, time.March, 8, 10, 0, 0, 0, time.UTC)) {
+		t.Fatal("Saturday is not a working day")
+	}
+	// После перехода на летнее время 09:00 по Берлину — это 07:00 UTC.
+	if !berlin.contains(time.Date(2025, time.March, 31, 7, 0, 0, 0, time.UTC)) {
+		t.Fatal("working hours must follow daylight saving time")
+	}
+}
+
+// workingHoursLookupFunc адаптирует функцию к WorkingHoursLookup.
+type workingHoursLookupFunc func(ctx context.Context, userIDs []string) ([]models.WorkingHours, error)
+
+func (f workingHoursLookupFunc) FindWorkingHours(ctx context.Context, userIDs []string) ([]models.WorkingHours, error) {
+	return f(ctx, userIDs)
+}
+
+func TestUserManager_RankCandidatesPrefersWorkingHours(t *testing.T) {
+	manager := NewUserManager(nil)
+	manager.SetWorkingHours(workingHoursLookupFunc(func(context.Context, []string) ([]models.WorkingHours, error) {
+		return []models.WorkingHours{
+			{UserId: "tokyo", TimeZone: "Asia/Tokyo", Start: "09:00", End: "18:00"},
+			{UserId: "berlin", TimeZone: "Europe/Berlin", Start: "09:00", End: "18:00"},
+			{UserId: "broken", TimeZone: "Nowhere/City", Start: "09:00", End: "18:00"},
+		}, nil
+	}))
+
+	// 19:00 в Токио, 11:00 в Берлине.
+	at := time.Date(2025, time.March, 10, 10, 0, 0, 0, time.UTC)
+	ranked, err := manager.RankCandidates(context.Background(), []string{"tokyo", "berlin", "anytime", "broken"}, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ordered := make([]string, 0, len(ranked))
+	for _, c := range ranked {
+		ordered = append(ordered, c.UserId)
+	}
+	// Внутри групп порядок — по user_id: нагрузка у всех одинаковая.
+	if want := []string{"anytime", "berlin", "broken", "tokyo"}; !slices.Equal(ordered, want) {
+		t.Fatalf("expected %v, got %v", want, ordered)
+	}
+}
+
+func TestRotateStaleCountsBusinessHours(t *testing.T) {
+	// Понедельник, 12:00 UTC: 13:00 в Берлине и 21:00 в Токио.
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	repo := &mockStaleRepository{assignments: []models.ReviewAssignment{
+		// С пятницы прошло 72 часа, но рабочих из них восемь.
+		{PullRequestId: "pr-1", ReviewerId: "berlin", TeamName: "backend", LastActivityAt: time.Date(2025, time.March, 7, 12, 0, 0, 0, time.UTC)},
+		// Семь часов по календарю, но рабочих — пять (с 14:00 до 19:00 по Токио).
+		{PullRequestId: "pr-2", ReviewerId: "tokyo", TeamName: "backend", LastActivityAt: now.Add(-7 * time.Hour)},
+		{PullRequestId: "pr-3", ReviewerId: "anytime", TeamName: "backend", LastActivityAt: now.Add(-7 * time.Hour)},
+	}}
+	reassign := &fakeReassigner{}
+	sm := newTestStaleManager(repo, reassign, StaleReviewConfig{SLA: 6 * time.Hour}, now)
+	sm.SetWorkingHours(workingHoursLookupFunc(func(context.Context, []string) ([]models.WorkingHours, error) {
+		return []models.WorkingHours{
+			{UserId: "berlin", TimeZone: "Europe/Berlin", Start: "09:00", End: "17:00"},
+			{UserId: "tokyo", TimeZone: "Asia/Tokyo", Start: "10:00", End: "19:00"},
+		}, nil
+	}))
+
+	rotations, err := sm.RotateStale(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"pr-1/berlin", "pr-3/anytime"}; !slices.Equal(reassign.calls, want) {
+		t.Fatalf("expected %v to rotate, got %v", want, reassign.calls)
+	}
+	if rotations[0].IdleSeconds != 8*3600 {
+		t.Fatalf("expected 8 business hours of idle time, got %ds", rotations[0].IdleSeconds)
+	}
+}
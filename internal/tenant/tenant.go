@@ -0,0 +1,25 @@
+// Package tenant передаёт через контекст организацию, в рамках которой выполняется запрос.
+// Хранилища читают её из контекста и видят только данные этой организации.
+package tenant
+
+import (
+	"context"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type organizationKey struct{}
+
+// WithOrganization кладёт идентификатор организации в контекст.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// Organization достаёт организацию из контекста; без неё — организация по умолчанию,
+// в которой работает сервис без токенов организаций.
+func Organization(ctx context.Context) string {
+	if id, _ := ctx.Value(organizationKey{}).(string); id != "" {
+		return id
+	}
+	return models.DefaultOrganization
+}
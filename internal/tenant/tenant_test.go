@@ -0,0 +1,17 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+func TestOrganization(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, models.DefaultOrganization, Organization(ctx))
+	require.Equal(t, "acme", Organization(WithOrganization(ctx, "acme")))
+	require.Equal(t, models.DefaultOrganization, Organization(WithOrganization(ctx, "")))
+}
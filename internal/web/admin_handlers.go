@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+)
+
+// maxImportBodyBytes ограничивает размер файла импорта.
+const maxImportBodyBytes = 10 << 20
+
+type importResponse struct {
+	Report *models.ImportReport `json:"report"`
+}
+
+// handleAdminImport загружает пользователей и команды из CSV (text/csv) или JSON (application/json).
+// Параметры: on_conflict — skip (по умолчанию), update или fail; dry_run — только отчёт без записи.
+// Если хотя бы одна строка ошибочна, ничего не сохраняется и отчёт возвращается со статусом 422.
+func (s *Server) handleAdminImport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := models.ImportOptions{OnConflict: models.ImportConflictPolicy(query.Get("on_conflict"))}
+	if raw := query.Get("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", "dry_run must be a boolean")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	format := models.ImportFormatJSON
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		switch {
+		case err != nil:
+			writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid Content-Type")
+			return
+		case mediaType == contentTypeCSV:
+			format = models.ImportFormatCSV
+		case mediaType != "application/json":
+			writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Content-Type must be text/csv or application/json")
+			return
+		}
+	}
+
+	rows, err := service.ParseUserImport(http.MaxBytesReader(w, r.Body, maxImportBodyBytes), format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "INVALID_PAYLOAD", "import file is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", err.Error())
+		return
+	}
+
+	report, err := s.userTeamService.ImportUsers(r.Context(), rows, opts)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, importResponse{Report: report})
+}
+
+// maxSnapshotBodyBytes ограничивает размер загружаемого архива состояния.
+const maxSnapshotBodyBytes = 512 << 20
+
+type snapshotRestoreResponse struct {
+	Restored models.SnapshotCounts `json:"restored"`
+}
+
+// handleAdminExport отдаёт архив всего состояния сервиса как вложение JSON.
+func (s *Server) handleAdminExport(w http.ResponseWriter, r *http.Request) {
+	snap, err := s.snapshots.Export(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	filename := "pr-manager-snapshot-" + snap.CreatedAt.Format("20060102T150405Z") + ".json"
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	writeJSON(w, http.StatusOK, snap)
+}
+
+// handleAdminImportSnapshot восстанавливает архив состояния в пустое хранилище.
+func (s *Server) handleAdminImportSnapshot(w http.ResponseWriter, r *http.Request) {
+	var snap models.Snapshot
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSnapshotBodyBytes)).Decode(&snap); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "INVALID_PAYLOAD", "snapshot is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+
+	counts, err := s.snapshots.Restore(r.Context(), &snap)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, snapshotRestoreResponse{Restored: counts})
+}
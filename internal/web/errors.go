@@ -1,14 +1,21 @@
 package web
 
-import "net/http"
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+)
 
 type errorResponse struct {
 	Error errorBody `json:"error"`
 }
 
 type errorBody struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
 }
 
 // writeError формирует стандартный JSON с кодом и сообщением об ошибке.
@@ -22,14 +29,51 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	writeJSON(w, status, resp)
 }
 
+// writeErrorDetails дополняет стандартный ответ об ошибке списком конкретных нарушений.
+func writeErrorDetails(w http.ResponseWriter, status int, code, message string, details []string) {
+	writeJSON(w, status, errorResponse{
+		Error: errorBody{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
+
+// writeDomainError переводит ошибку сервиса в HTTP-ответ и единожды логирует её вместе с кодом.
+func writeDomainError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, msg := mapDomainError(err)
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(r.Context(), level, "request failed",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", status,
+		"code", code,
+		"err", err.Error(),
+	)
+	// Отказ правил подбора объясняется списком причин, а не одной длинной строкой.
+	var rejected *domain.RejectedCandidatesError
+	if errors.As(err, &rejected) {
+		writeErrorDetails(w, status, code, rejected.Summary(), rejected.Reasons)
+		return
+	}
+	writeError(w, status, code, msg)
+}
+
 // Возможные значения кода ошибки.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	INVALIDPARAM ErrorResponseErrorCode = "INVALID_PARAM"
+	NOCANDIDATE  ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED  ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTEMPTY     ErrorResponseErrorCode = "NOT_EMPTY"
+	NOTFOUND     ErrorResponseErrorCode = "NOT_FOUND"
+	ORGEXISTS    ErrorResponseErrorCode = "ORG_EXISTS"
+	PREXISTS     ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED     ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS   ErrorResponseErrorCode = "TEAM_EXISTS"
 )
 
 // ErrorResponseErrorCode описывает код ошибки в ответе.
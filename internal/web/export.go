@@ -0,0 +1,162 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// exportFormat — формат ответа, выбранный по заголовку Accept.
+type exportFormat string
+
+const (
+	formatJSON   exportFormat = "json"
+	formatCSV    exportFormat = "csv"
+	formatNDJSON exportFormat = "ndjson"
+)
+
+// Типы содержимого потоковой выгрузки.
+const (
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// exportFlushEvery — через сколько строк буфер выгрузки отправляется клиенту.
+const exportFlushEvery = 100
+
+// negotiateFormat выбирает формат по Accept с учётом q-факторов и помечает ответ Vary: Accept.
+// Неизвестные и отсутствующие типы дают JSON, как и раньше.
+func negotiateFormat(w http.ResponseWriter, r *http.Request) exportFormat {
+	w.Header().Add("Vary", "Accept")
+	best, bestQ := formatJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+
+		var format exportFormat
+		switch mediaType {
+		case contentTypeCSV:
+			format = formatCSV
+		case contentTypeNDJSON:
+			format = formatNDJSON
+		case "application/json", "application/*", "*/*":
+			format = formatJSON
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+// rowStream пишет строки выгрузки в CSV или NDJSON.
+// Заголовки ответа отправляются с первой строкой, чтобы ошибку до начала выдачи можно было вернуть обычным JSON.
+type rowStream[T any] struct {
+	w       http.ResponseWriter
+	format  exportFormat
+	header  []string
+	record  func(T) []string
+	csv     *csv.Writer
+	enc     *json.Encoder
+	started bool
+	rows    int
+}
+
+func (s *rowStream[T]) start() error {
+	s.started = true
+	if s.format == formatCSV {
+		s.w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+		s.w.WriteHeader(http.StatusOK)
+		s.csv = csv.NewWriter(s.w)
+		return s.csv.Write(s.header)
+	}
+	s.w.Header().Set("Content-Type", contentTypeNDJSON)
+	s.w.WriteHeader(http.StatusOK)
+	s.enc = json.NewEncoder(s.w)
+	s.enc.SetEscapeHTML(false)
+	return nil
+}
+
+func (s *rowStream[T]) write(v T) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if s.format == formatCSV {
+		err = s.csv.Write(s.record(v))
+	} else {
+		err = s.enc.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%exportFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush отправляет накопленные строки клиенту.
+func (s *rowStream[T]) flush() error {
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	// Не все ResponseWriter умеют Flush; тогда данные уйдут по заполнении буфера сервера.
+	_ = http.NewResponseController(s.w).Flush()
+	return nil
+}
+
+// writeExport выгружает строки, которые produce передаёт в emit, в выбранном формате.
+// Ошибка до первой строки превращается в обычный ответ с кодом, после — обрывает поток и попадает в журнал.
+func writeExport[T any](w http.ResponseWriter, r *http.Request, format exportFormat, header []string, record func(T) []string, produce func(emit func(T) error) error) {
+	stream := &rowStream[T]{w: w, format: format, header: header, record: record}
+	if err := produce(stream.write); err != nil {
+		if !stream.started {
+			writeDomainError(w, r, err)
+			return
+		}
+		// Отдаём уже прочитанные строки: клиент увидит обрыв по отсутствию хвоста.
+		_ = stream.flush()
+		slog.ErrorContext(r.Context(), "export interrupted",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"rows", stream.rows,
+			"err", err.Error(),
+		)
+		return
+	}
+
+	if !stream.started {
+		if err := stream.start(); err != nil {
+			return
+		}
+	}
+	_ = stream.flush()
+}
+
+// formatFloat печатает число без лишних нулей.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
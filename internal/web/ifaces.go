@@ -9,18 +9,81 @@ import (
 
 // PullRequestService описывает операции над Pull Request, которые нужны HTTP-слою.
 type PullRequestService interface {
-	CreatePullRequest(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*models.PullRequest, error)
+	CreatePullRequest(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error)
 	Merge(ctx context.Context, payload models.PostPullRequestMergeJSONBody) (*models.PullRequest, error)
 	Reassign(ctx context.Context, oldUsId, prId string) (*domain.ReassignResponse, error)
-	ListForReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error)
-	AssignmentStats(ctx context.Context) (*models.AssignmentStats, error)
+	ListForReviewer(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error)
+	ExportReviewerPullRequests(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error
+	AssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error)
+	TeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error)
+	ExportUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error
+	ExportPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error
+	TurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error)
 	BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.TeamBulkDeactivateResult, error)
 }
 
 // UserTeamService объединяет операции с командами и пользователями за одним интерфейсом.
 type UserTeamService interface {
 	TeamService
-	SetUserActivity(userID string, isActive bool) (*models.User, error)
+	SetUserActivity(ctx context.Context, userID string, isActive bool) (*models.User, error)
+	ImportUsers(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error)
+}
+
+// SnapshotService выгружает и восстанавливает полное состояние сервиса.
+type SnapshotService interface {
+	Export(ctx context.Context) (*models.Snapshot, error)
+	Restore(ctx context.Context, snap *models.Snapshot) (models.SnapshotCounts, error)
+}
+
+// StaleReviewService отмечает активность ревьюеров и отдаёт историю автоматических замен.
+type StaleReviewService interface {
+	RecordActivity(ctx context.Context, prID, userID string) (*models.ReviewActivity, error)
+	Rotations(ctx context.Context, filter models.ReviewRotationFilter) ([]models.ReviewRotation, error)
+}
+
+// AbsenceService регистрирует периоды отсутствия пользователей.
+type AbsenceService interface {
+	AddAbsence(ctx context.Context, req models.PostUsersAddAbsenceJSONBody) (*models.Absence, error)
+	Absences(ctx context.Context, userID string) ([]models.Absence, error)
+	CancelAbsence(ctx context.Context, absenceID int64) (*models.Absence, error)
+}
+
+// WorkingHoursService настраивает часовые пояса и рабочее время пользователей.
+type WorkingHoursService interface {
+	SetWorkingHours(ctx context.Context, req models.WorkingHours) (*models.WorkingHours, error)
+	WorkingHours(ctx context.Context, userID string) (*models.WorkingHours, error)
+	ClearWorkingHours(ctx context.Context, userID string) (*models.WorkingHours, error)
+}
+
+// CapacityService настраивает лимиты открытых ревью и показывает нагрузку ревьюеров.
+type CapacityService interface {
+	SetCapacity(ctx context.Context, req models.PostUsersSetCapacityJSONBody) (*models.ReviewerLoad, error)
+	ReviewerLoad(ctx context.Context, userID string) (*models.ReviewerLoad, error)
+}
+
+// RepositoryService настраивает репозитории PR: команду-владельца и число ревьюеров.
+type RepositoryService interface {
+	SetRepository(ctx context.Context, req models.Repository) (*models.Repository, error)
+	Repository(ctx context.Context, name string) (*models.Repository, error)
+	Repositories(ctx context.Context) ([]models.Repository, error)
+}
+
+// AffinityRuleService управляет правилами подбора ревьюеров команд.
+type AffinityRuleService interface {
+	AddRule(ctx context.Context, req models.PostTeamAddRuleJSONBody) (*models.AffinityRule, error)
+	Rules(ctx context.Context, teamName string) ([]models.AffinityRule, error)
+	DeleteRule(ctx context.Context, ruleID int64) error
+}
+
+// LearnerService управляет стажёрами команд, из которых назначаются теневые ревьюеры.
+type LearnerService interface {
+	SetLearners(ctx context.Context, req models.PostTeamSetLearnersJSONBody) (*models.TeamLearners, error)
+	Learners(ctx context.Context, teamName string) (*models.TeamLearners, error)
+}
+
+// DecisionService объясняет, почему PR достались его ревьюеры.
+type DecisionService interface {
+	Explain(ctx context.Context, prID string) (*models.PullRequestExplanation, error)
 }
 
 // TeamService описывает базовые операции управления командами.
@@ -28,3 +91,10 @@ type TeamService interface {
 	AddTeam(ctx context.Context, team models.Team) error
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
 }
+
+// OrganizationService создаёт организации и определяет организацию по токену доступа.
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, req models.PostOrganizationCreateJSONBody) (*models.Organization, string, error)
+	Organizations(ctx context.Context) ([]models.Organization, error)
+	Authenticate(ctx context.Context, token string) (string, error)
+}
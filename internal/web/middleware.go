@@ -0,0 +1,84 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tenant"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// requestLogger пишет одну строку журнала на запрос; request_id и trace_id добавляет обработчик slog из контекста.
+func requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := ""
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+		slog.InfoContext(r.Context(), "http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route,
+			"status", status,
+			"bytes", ww.BytesWritten(),
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// adminKey помечает в контексте запросы с токеном администратора.
+type adminKey struct{}
+
+// authenticate определяет организацию запроса по заголовку Authorization: Bearer.
+// Токен администратора действует в организации default и открывает маршруты requireAdmin,
+// токен организации — только в ней. Без токена или с неизвестным токеном — 401 UNAUTHORIZED.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "bearer token is required")
+			return
+		}
+
+		ctx := r.Context()
+		if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+			ctx = context.WithValue(tenant.WithOrganization(ctx, models.DefaultOrganization), adminKey{}, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		organizationID, err := s.organizations.Authenticate(ctx, token)
+		if err != nil {
+			writeDomainError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithOrganization(ctx, organizationID)))
+	})
+}
+
+// requireAdmin пропускает только запросы с токеном администратора; токен организации получает 403 FORBIDDEN.
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if admin, _ := r.Context().Value(adminKey{}).(bool); !admin {
+			writeDomainError(w, r, domain.NewForbiddenError("manage organizations"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
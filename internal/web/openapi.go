@@ -0,0 +1,171 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/api"
+)
+
+// maxValidatedBodyBytes — тела крупнее проверяются только обработчиками (архивы, большие импорты).
+const maxValidatedBodyBytes = 1 << 20
+
+// specRouter разбирает встроенную спецификацию один раз и сопоставляет запросы с её операциями.
+var specRouter = sync.OnceValues(func() (routers.Router, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+	return router, nil
+})
+
+// validationOptions: параметры запроса проверяют сами обработчики (MISSING_PARAM, INVALID_PARAM), токены не проверяются.
+var validationOptions = &openapi3filter.Options{
+	ExcludeRequestQueryParams: true,
+	MultiError:                true,
+	AuthenticationFunc:        openapi3filter.NoopAuthenticationFunc,
+}
+
+// handleOpenAPISpec отдаёт встроенную спецификацию API.
+func handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(api.Spec)
+}
+
+// docsPage — Swagger UI, читающий спецификацию с /openapi.yml.
+const docsPage = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>PR Manager API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.yml", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// handleDocs отдаёт интерактивную документацию API.
+func handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = io.WriteString(w, docsPage)
+}
+
+// requestValidator отклоняет JSON-тела, не соответствующие спецификации, с кодом INVALID_PAYLOAD.
+// Маршруты вне спецификации и тела крупнее maxValidatedBodyBytes пропускаются без проверки.
+func requestValidator(router routers.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasJSONBody(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			route, params, err := router.FindRoute(r)
+			if err != nil || route.Operation.RequestBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBodyBytes+1))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "cannot read request body")
+				return
+			}
+			if len(body) > maxValidatedBodyBytes {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Обработчики читают тело как JSON и без заголовка Content-Type, валидатор должен делать так же.
+			vr := r
+			if r.Header.Get("Content-Type") == "" {
+				vr = r.Clone(r.Context())
+				vr.Header.Set("Content-Type", "application/json")
+			}
+			vr.Body = io.NopCloser(bytes.NewReader(body))
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    vr,
+				PathParams: params,
+				Route:      route,
+				Options:    validationOptions,
+			})
+			if err != nil {
+				details := validationDetails(err)
+				slog.WarnContext(r.Context(), "request rejected by openapi validation",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"details", details,
+				)
+				writeErrorDetails(w, http.StatusBadRequest, "INVALID_PAYLOAD", "request does not match the API specification", details)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasJSONBody сообщает, что запрос несёт (или может нести) тело в формате JSON.
+func hasJSONBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength > maxValidatedBodyBytes {
+		return false
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return r.Method == http.MethodPost
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	return err == nil && mediaType == "application/json"
+}
+
+// validationDetails раскладывает ошибку валидатора на строки «JSON-указатель: причина».
+func validationDetails(err error) []string {
+	var details []string
+	var walk func(error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, inner := range e {
+				walk(inner)
+			}
+		case *openapi3filter.RequestError:
+			if e.Err != nil {
+				walk(e.Err)
+				return
+			}
+			details = append(details, e.Reason)
+		case *openapi3.SchemaError:
+			details = append(details, "/"+strings.Join(e.JSONPointer(), "/")+": "+e.Reason)
+		default:
+			details = append(details, err.Error())
+		}
+	}
+	walk(err)
+	return details
+}
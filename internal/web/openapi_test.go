@@ -0,0 +1,472 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/api"
+	"github.com/AlekseyZapadovnikov/pr-manager/conf"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
+)
+
+func init() {
+	// Потоковые ответы NDJSON описаны в спецификации строкой.
+	openapi3filter.RegisterBodyDecoder(contentTypeNDJSON, openapi3filter.FileBodyDecoder)
+}
+
+// conformance гоняет запросы через настоящие сервисы поверх хранилища в памяти
+// и сверяет каждый запрос и ответ со спецификацией.
+type conformance struct {
+	t       *testing.T
+	srv     *Server
+	stale   *service.StaleReviewManager
+	router  routers.Router
+	covered map[string]bool
+	// token уходит в заголовке Authorization, если сервер требует токен организации.
+	token string
+}
+
+func newConformance(t *testing.T) *conformance {
+	t.Helper()
+	return newConformanceWithOrganizations(t, "")
+}
+
+// newConformanceWithOrganizations включает организации, если adminToken не пуст; запросы идут с токеном администратора.
+func newConformanceWithOrganizations(t *testing.T, adminToken string) *conformance {
+	t.Helper()
+	router, err := specRouter()
+	require.NoError(t, err)
+
+	storage := memory.NewStorage()
+	users := service.NewUserManager(storage)
+	users.SetAbsences(storage)
+	users.SetWorkingHours(storage)
+	users.SetReviewLoad(storage, 0)
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	prs.SetReviewQueue(storage)
+	prs.SetRepositories(storage)
+	users.SetAffinityRules(storage)
+	prs.SetAffinityRules(storage)
+	prs.SetShadowReviews(storage)
+	prs.SetDecisions(storage)
+	// SLA в наносекунду делает зависшим любое назначение, чтобы сценарий мог вызвать замену сразу.
+	stale := service.NewStaleReviewManager(storage, prs, service.StaleReviewConfig{SLA: time.Nanosecond})
+	opts := []Option{
+		WithSnapshots(service.NewSnapshotManager(storage, users)), WithStaleReviews(stale),
+		WithAbsences(service.NewAbsenceManager(storage, prs, service.AbsenceConfig{})),
+		WithWorkingHours(service.NewWorkingHoursManager(storage)),
+		WithCapacity(service.NewCapacityManager(storage, prs, 0)),
+		WithRepositories(service.NewRepositoryManager(storage)), WithAffinityRules(service.NewAffinityRuleManager(storage)),
+		WithLearners(service.NewLearnerManager(storage)), WithDecisions(service.NewDecisionManager(storage)),
+		WithRequestValidation(),
+	}
+	if adminToken != "" {
+		opts = append(opts, WithOrganizations(service.NewOrganizationManager(storage), adminToken))
+	}
+	srv := New(conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}, prs, users, opts...)
+
+	return &conformance{t: t, srv: srv, stale: stale, router: router, covered: make(map[string]bool), token: adminToken}
+}
+
+// do выполняет запрос и проверяет по спецификации сам запрос и полученный ответ.
+func (c *conformance) do(method, target, contentType, accept string, body any, wantStatus int) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var raw []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		raw = []byte(b)
+	default:
+		var err error
+		raw, err = json.Marshal(b)
+		require.NoError(c.t, err)
+	}
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(method, target, bytes.NewReader(raw))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		return req
+	}
+
+	req := newRequest()
+	route, params, err := c.router.FindRoute(req)
+	require.NoError(c.t, err, "%s %s is not described in openapi.yml", method, target)
+	c.covered[route.Method+" "+route.Path] = true
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: params,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+	}
+	require.NoError(c.t, openapi3filter.ValidateRequest(c.t.Context(), input), "%s %s", method, target)
+
+	rr := httptest.NewRecorder()
+	c.srv.router.ServeHTTP(rr, newRequest())
+	require.Equal(c.t, wantStatus, rr.Code, "%s %s: %s", method, target, rr.Body.String())
+
+	out := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 rr.Code,
+		Header:                 rr.Header(),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+	}
+	out.SetBodyBytes(rr.Body.Bytes())
+	require.NoError(c.t, openapi3filter.ValidateResponse(c.t.Context(), out), "%s %s: %s", method, target, rr.Body.String())
+	return rr
+}
+
+func (c *conformance) get(target string, wantStatus int) *httptest.ResponseRecorder {
+	c.t.Helper()
+	return c.do(http.MethodGet, target, "", "", nil, wantStatus)
+}
+
+func (c *conformance) post(target string, body any, wantStatus int) *httptest.ResponseRecorder {
+	c.t.Helper()
+	return c.do(http.MethodPost, target, "application/json", "", body, wantStatus)
+}
+
+func TestHandlersConformToOpenAPI(t *testing.T) {
+	c := newConformance(t)
+
+	c.get("/health", http.StatusOK)
+
+	team := models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "Alice", IsActive: true},
+		{UserId: "u2", Username: "Bob", IsActive: true},
+		{UserId: "u3", Username: "Carol", IsActive: true},
+		{UserId: "u4", Username: "Dave", IsActive: true},
+		{UserId: "u5", Username: "Eve", IsActive: true},
+	}}
+	c.post("/team/add", team, http.StatusCreated)
+	c.post("/team/add", team, http.StatusBadRequest)
+	c.get("/team/get?team_name=backend", http.StatusOK)
+	c.get("/team/get?team_name=ghost", http.StatusNotFound)
+
+	c.post("/users/setIsActive", map[string]any{"user_id": "u5", "is_active": false}, http.StatusOK)
+	c.post("/users/setIsActive", map[string]any{"user_id": "ghost", "is_active": false}, http.StatusNotFound)
+
+	rr := c.post("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	}, http.StatusCreated)
+	var created prResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	require.NotEmpty(t, created.PR.AssignedReviewers)
+	reviewer := created.PR.AssignedReviewers[0]
+
+	c.post("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-2", "pull_request_name": "Fix login", "author_id": "ghost",
+	}, http.StatusNotFound)
+
+	c.get("/users/getReview?user_id="+reviewer, http.StatusOK)
+	c.do(http.MethodGet, "/users/getReview?user_id="+reviewer, "", contentTypeCSV, nil, http.StatusOK)
+	c.do(http.MethodGet, "/users/getReview?user_id="+reviewer, "", contentTypeNDJSON, nil, http.StatusOK)
+
+	rr = c.post("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-1", "old_user_id": reviewer}, http.StatusOK)
+	var reassigned reassignResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &reassigned))
+	c.post("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-1", "old_user_id": "u5"}, http.StatusConflict)
+
+	c.post("/pullRequest/activity", map[string]string{"pull_request_id": "pr-1", "user_id": reassigned.ReplacedBy}, http.StatusOK)
+	c.post("/pullRequest/activity", map[string]string{"pull_request_id": "pr-1", "user_id": "u5"}, http.StatusConflict)
+	c.post("/pullRequest/activity", map[string]string{"pull_request_id": "ghost", "user_id": "u5"}, http.StatusNotFound)
+	_, err := c.stale.RotateStale(context.Background())
+	require.NoError(t, err)
+	c.get("/pullRequest/rotations", http.StatusOK)
+	c.get("/pullRequest/rotations?pull_request_id=pr-1&limit=1", http.StatusOK)
+	rr = c.get("/pullRequest/explain?pull_request_id=pr-1", http.StatusOK)
+	var explanation models.PullRequestExplanation
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &explanation))
+	require.GreaterOrEqual(t, len(explanation.Decisions), 2, "creation and reassignment are explained")
+	require.Equal(t, models.DecisionOperationCREATE, explanation.Decisions[0].Operation)
+	require.Equal(t, models.DecisionOperationREASSIGN, explanation.Decisions[1].Operation)
+	c.get("/pullRequest/explain?pull_request_id=ghost", http.StatusNotFound)
+
+	now := time.Now().UTC()
+	rr = c.post("/users/addAbsence", map[string]any{
+		"user_id": "u3", "starts_at": now.Add(-time.Hour), "ends_at": now.Add(24 * time.Hour), "reason": "vacation",
+	}, http.StatusCreated)
+	var added absenceResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &added))
+	c.post("/users/addAbsence", map[string]any{
+		"user_id": "u3", "starts_at": now, "ends_at": now.Add(-time.Hour),
+	}, http.StatusBadRequest)
+	c.post("/users/addAbsence", map[string]any{
+		"user_id": "ghost", "starts_at": now, "ends_at": now.Add(time.Hour),
+	}, http.StatusNotFound)
+	c.get("/users/getAbsences?user_id=u3", http.StatusOK)
+	c.get("/users/getAbsences?user_id=ghost", http.StatusNotFound)
+	c.post("/users/cancelAbsence", map[string]any{"absence_id": added.Absence.AbsenceId}, http.StatusOK)
+	c.post("/users/cancelAbsence", map[string]any{"absence_id": added.Absence.AbsenceId}, http.StatusNotFound)
+
+	hours := map[string]string{"user_id": "u4", "time_zone": "Europe/Berlin", "start": "09:00", "end": "18:00"}
+	c.post("/users/setWorkingHours", hours, http.StatusOK)
+	c.post("/users/setWorkingHours", map[string]string{"user_id": "u4", "time_zone": "Mars/Olympus", "start": "09:00", "end": "18:00"}, http.StatusBadRequest)
+	c.post("/users/setWorkingHours", map[string]string{"user_id": "ghost", "time_zone": "UTC", "start": "09:00", "end": "18:00"}, http.StatusNotFound)
+	c.get("/users/getWorkingHours?user_id=u4", http.StatusOK)
+	c.get("/users/getWorkingHours?user_id=u3", http.StatusNotFound)
+	c.post("/users/clearWorkingHours", map[string]string{"user_id": "u4"}, http.StatusOK)
+	c.post("/users/clearWorkingHours", map[string]string{"user_id": "u4"}, http.StatusNotFound)
+
+	c.post("/users/setCapacity", map[string]any{"user_id": "u4", "max_open_reviews": 3}, http.StatusOK)
+	c.post("/users/setCapacity", map[string]any{"user_id": "u4", "max_open_reviews": -1}, http.StatusBadRequest)
+	c.post("/users/setCapacity", map[string]any{"user_id": "ghost", "max_open_reviews": 3}, http.StatusNotFound)
+	c.get("/users/getLoad?user_id=u4", http.StatusOK)
+	c.get("/users/getLoad?user_id=ghost", http.StatusNotFound)
+
+	c.post("/pullRequest/merge", map[string]string{"pull_request_id": "pr-1"}, http.StatusOK)
+	c.post("/pullRequest/merge", map[string]string{"pull_request_id": "ghost"}, http.StatusNotFound)
+	c.post("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-1", "old_user_id": reassigned.ReplacedBy}, http.StatusConflict)
+
+	c.post("/repositories/set", map[string]any{"repository_name": "search-api", "owner_team": "backend", "required_reviewers": 1}, http.StatusOK)
+	c.post("/repositories/set", map[string]any{"repository_name": "search api"}, http.StatusBadRequest)
+	c.post("/repositories/set", map[string]any{"repository_name": "search-api", "owner_team": "ghost"}, http.StatusNotFound)
+	c.get("/repositories/get?repository_name=search-api", http.StatusOK)
+	c.get("/repositories/get?repository_name=ghost", http.StatusNotFound)
+	c.get("/repositories/list", http.StatusOK)
+
+	c.post("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-3", "pull_request_name": "Tune cache", "author_id": "u1",
+	}, http.StatusCreated)
+	c.post("/pullRequest/create", map[string]any{
+		"repository": "search-api", "number": 3, "pull_request_name": "Tune cache", "author_id": "u1",
+	}, http.StatusCreated)
+	c.post("/pullRequest/create", map[string]any{
+		"repository": "ghost", "number": 3, "pull_request_name": "Tune cache", "author_id": "u1",
+	}, http.StatusNotFound)
+	c.get("/users/getReview?user_id=u3&repository=search-api", http.StatusOK)
+	c.post("/team/deactivateUsers", map[string]any{"team_name": "backend", "user_ids": []string{"u2"}}, http.StatusOK)
+	c.post("/team/deactivateUsers", map[string]any{"team_name": "ghost", "user_ids": []string{"u2"}}, http.StatusNotFound)
+
+	// Правило требует неактивного u5: PR не создаётся, а причины приходят в details.
+	rr = c.post("/team/addRule", map[string]any{
+		"team_name": "backend", "kind": "ALWAYS_INCLUDE", "user_id": "u1", "with_user_ids": []string{"u5"},
+	}, http.StatusCreated)
+	var rule affinityRuleResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rule))
+	c.post("/team/addRule", map[string]any{
+		"team_name": "backend", "kind": "NEVER_PAIR", "user_id": "u1", "with_user_ids": []string{"u1"},
+	}, http.StatusBadRequest)
+	c.post("/team/addRule", map[string]any{
+		"team_name": "ghost", "kind": "NEVER_PAIR", "user_id": "u1", "with_user_ids": []string{"u3"},
+	}, http.StatusNotFound)
+	c.get("/team/getRules?team_name=backend", http.StatusOK)
+	c.get("/team/getRules?team_name=ghost", http.StatusNotFound)
+	rr = c.post("/pullRequest/create", map[string]string{
+		"pull_request_id": "pr-4", "pull_request_name": "Audit log", "author_id": "u1",
+	}, http.StatusConflict)
+	var rejected errorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rejected))
+	require.Equal(t, "NO_CANDIDATE", rejected.Error.Code)
+	require.Equal(t, []string{"rule " + strconv.FormatInt(rule.Rule.RuleId, 10) + ": PRs of u1 need a reviewer from [u5]", "u5: is inactive"},
+		rejected.Error.Details)
+	c.post("/team/deleteRule", map[string]any{"rule_id": rule.Rule.RuleId}, http.StatusOK)
+	c.post("/team/deleteRule", map[string]any{"rule_id": rule.Rule.RuleId}, http.StatusNotFound)
+
+	// Один ревьюер в search-api: второй из стажёров u2 и u4 становится теневым ревьюером.
+	c.post("/users/setIsActive", map[string]any{"user_id": "u2", "is_active": true}, http.StatusOK)
+	c.post("/team/setLearners", map[string]any{"team_name": "backend", "user_ids": []string{"u2", "u4"}}, http.StatusOK)
+	c.post("/team/setLearners", map[string]any{"team_name": "backend", "user_ids": []string{"ghost"}}, http.StatusBadRequest)
+	c.post("/team/setLearners", map[string]any{"team_name": "ghost", "user_ids": []string{"u2"}}, http.StatusNotFound)
+	rr = c.post("/pullRequest/create", map[string]any{
+		"repository": "search-api", "number": 5, "pull_request_name": "Mentored change", "author_id": "u1",
+	}, http.StatusCreated)
+	var mentored prResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &mentored))
+	require.Len(t, mentored.PR.AssignedReviewers, 1)
+	require.Len(t, mentored.PR.ShadowReviewers, 1)
+	require.NotEqual(t, mentored.PR.AssignedReviewers[0], mentored.PR.ShadowReviewers[0])
+	c.get("/users/getReview?user_id="+mentored.PR.ShadowReviewers[0], http.StatusOK)
+	c.get("/team/getLearners?team_name=backend", http.StatusOK)
+	c.get("/team/getLearners?team_name=ghost", http.StatusNotFound)
+
+	c.get("/stats/assignments", http.StatusOK)
+	c.get("/stats/assignments?team=backend&status=MERGED&limit=1", http.StatusOK)
+	c.do(http.MethodGet, "/stats/assignments?by=team", "", contentTypeCSV, nil, http.StatusOK)
+	c.do(http.MethodGet, "/stats/assignments?by=pull_request", "", contentTypeNDJSON, nil, http.StatusOK)
+	c.get("/stats/assignments?repository=search-api", http.StatusOK)
+	c.get("/stats/turnaround", http.StatusOK)
+	c.get("/stats/turnaround?repository=search-api", http.StatusOK)
+	c.get("/stats/turnaround?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", http.StatusBadRequest)
+
+	c.post("/admin/import", []map[string]any{
+		{"user_id": "u6", "username": "Frank", "team_name": "frontend"},
+	}, http.StatusOK)
+	c.do(http.MethodPost, "/admin/import?on_conflict=update", contentTypeCSV, "",
+		"user_id,username,team_name,is_active\nu6,Frank,frontend,false\n", http.StatusOK)
+	c.do(http.MethodPost, "/admin/import?dry_run=true", contentTypeCSV, "",
+		"user_id,username,team_name\nu7,,frontend\n", http.StatusUnprocessableEntity)
+
+	rr = c.get("/admin/export", http.StatusOK)
+	snapshot := rr.Body.String()
+	c.do(http.MethodPost, "/admin/import-snapshot", "application/json", "", snapshot, http.StatusConflict)
+
+	restore := newConformance(t)
+	restore.do(http.MethodPost, "/admin/import-snapshot", "application/json", "", snapshot, http.StatusCreated)
+	for op := range restore.covered {
+		c.covered[op] = true
+	}
+
+	orgs := newConformanceWithOrganizations(t, "admin-secret")
+	rr = orgs.post("/admin/organizations/create", map[string]any{"organization_id": "payments", "name": "Payments"}, http.StatusCreated)
+	var org organizationCreateResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &org))
+	orgs.post("/admin/organizations/create", map[string]any{"organization_id": "payments"}, http.StatusConflict)
+	orgs.get("/admin/organizations/list", http.StatusOK)
+	orgs.token = org.Token
+	orgs.get("/admin/organizations/list", http.StatusForbidden)
+	orgs.post("/admin/organizations/create", map[string]any{"organization_id": "search"}, http.StatusForbidden)
+	orgs.token = ""
+	orgs.get("/admin/organizations/list", http.StatusUnauthorized)
+	for op := range orgs.covered {
+		c.covered[op] = true
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	require.NoError(t, err)
+	var missing []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if op := method + " " + path; !c.covered[op] {
+				missing = append(missing, op)
+			}
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(t, missing, "operations without a conformance check")
+}
+
+func TestRequestValidationRejectsNonConformingPayload(t *testing.T) {
+	c := newConformance(t)
+
+	tests := []struct {
+		name    string
+		target  string
+		body    string
+		details []string
+	}{
+		{
+			name:    "wrong type",
+			target:  "/team/add",
+			body:    `{"team_name":5,"members":[]}`,
+			details: []string{`/team_name: value must be a string`},
+		},
+		{
+			name:    "missing fields",
+			target:  "/users/setIsActive",
+			body:    `{"user_id":"u1"}`,
+			details: []string{`/is_active: property "is_active" is missing`},
+		},
+		{
+			name:    "nested member",
+			target:  "/team/add",
+			body:    `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":"yes"}]}`,
+			details: []string{`/members/0/is_active: value must be a boolean`},
+		},
+		{
+			name:   "broken json",
+			target: "/pullRequest/merge",
+			body:   `{"pull_request_id":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			c.srv.router.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, "INVALID_PAYLOAD", resp.Error.Code)
+			require.NotEmpty(t, resp.Error.Details)
+			if tt.details != nil {
+				require.Equal(t, tt.details, resp.Error.Details)
+			}
+		})
+	}
+}
+
+func TestRequestValidationRunsAfterAuthentication(t *testing.T) {
+	c := newConformanceWithOrganizations(t, "admin-secret")
+	call := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(`{"team_name":5,"members":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		c.srv.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for _, token := range []string{"", "nope"} {
+		rr := call(token)
+		require.Equal(t, http.StatusUnauthorized, rr.Code, "token %q: %s", token, rr.Body.String())
+		require.NotContains(t, rr.Body.String(), "team_name", "schema details must not leak before authentication")
+	}
+	rr := call("admin-secret")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), "INVALID_PAYLOAD")
+}
+
+func TestRequestValidationPassesBodyToHandler(t *testing.T) {
+	c := newConformance(t)
+
+	// Без Content-Type тело всё равно проверяется и читается обработчиком как JSON.
+	req := httptest.NewRequest(http.MethodPost, "/team/add",
+		strings.NewReader(`{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`))
+	rr := httptest.NewRecorder()
+	c.srv.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(`{"members":[]}`))
+	rr = httptest.NewRecorder()
+	c.srv.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), "INVALID_PAYLOAD")
+}
+
+func TestServesOpenAPISpecAndDocs(t *testing.T) {
+	srv := New(conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}, &fakePRService{}, &fakeUserTeamService{})
+
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.yml", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/yaml", rr.Header().Get("Content-Type"))
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+	require.Equal(t, api.Spec, body)
+
+	rr = httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), `url: "openapi.yml"`)
+}
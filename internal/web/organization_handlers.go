@@ -0,0 +1,48 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type organizationCreateResp struct {
+	Organization *models.Organization `json:"organization"`
+	// Token показывается один раз: сервис хранит только его хеш.
+	Token string `json:"token"`
+}
+
+type organizationsResp struct {
+	Organizations []models.Organization `json:"organizations"`
+}
+
+// handleCreateOrganization создаёт организацию и возвращает её токен доступа.
+func (s *Server) handleCreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var p models.PostOrganizationCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.OrganizationId == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "organization_id is required")
+		return
+	}
+
+	org, token, err := s.organizations.CreateOrganization(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, organizationCreateResp{Organization: org, Token: token})
+}
+
+// handleListOrganizations возвращает все организации.
+func (s *Server) handleListOrganizations(w http.ResponseWriter, r *http.Request) {
+	orgs, err := s.organizations.Organizations(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, organizationsResp{Organizations: orgs})
+}
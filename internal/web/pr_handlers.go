@@ -3,6 +3,7 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
 )
@@ -11,6 +12,12 @@ type prResp struct {
 	PR *models.PullRequest `json:"pr"`
 }
 
+type createPRResp struct {
+	PR               *models.PullRequest   `json:"pr"`
+	ReviewerLoad     []models.ReviewerLoad `json:"reviewer_load"`
+	PendingReviewers int                   `json:"pending_reviewers"`
+}
+
 // handlePRCreate принимает JSON-запрос создания PR и проксирует его в сервис.
 func (s *Server) handlePRCreate(w http.ResponseWriter, r *http.Request) {
 	var p models.PostPullRequestCreateJSONBody
@@ -18,20 +25,24 @@ func (s *Server) handlePRCreate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
 		return
 	}
-	if p.PullRequestId == "" || p.PullRequestName == "" || p.AuthorId == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id, pull_request_name and author_id are required")
+	// Без pull_request_id идентификатор выводится из репозитория и номера PR.
+	if (p.PullRequestId == "" && p.Number == 0) || p.PullRequestName == "" || p.AuthorId == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id or number, pull_request_name and author_id are required")
 		return
 	}
 
 	ctx := r.Context()
-	pr, err := s.prService.CreatePullRequest(ctx, p)
+	resp, err := s.prService.CreatePullRequest(ctx, p)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, prResp{PR: pr})
+	writeJSON(w, http.StatusCreated, createPRResp{
+		PR:               resp.PR,
+		ReviewerLoad:     resp.ReviewerLoad,
+		PendingReviewers: resp.PendingReviewers,
+	})
 }
 
 // handlePRMerge подтверждает слияние PR и возвращает обновлённые данные.
@@ -49,8 +60,7 @@ func (s *Server) handlePRMerge(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	pr, err := s.prService.Merge(ctx, p)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -78,8 +88,7 @@ func (s *Server) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	res, err := s.prService.Reassign(ctx, oldUserId, prId)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -89,6 +98,75 @@ func (s *Server) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type activityRequest struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+}
+
+type activityResponse struct {
+	Activity *models.ReviewActivity `json:"activity"`
+}
+
+// handlePRActivity отмечает активность ревьюера и сбрасывает его таймер SLA.
+func (s *Server) handlePRActivity(w http.ResponseWriter, r *http.Request) {
+	var p activityRequest
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.PullRequestId == "" || p.UserId == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id and user_id are required")
+		return
+	}
+
+	activity, err := s.staleReviews.RecordActivity(r.Context(), p.PullRequestId, p.UserId)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, activityResponse{Activity: activity})
+}
+
+type rotationsResponse struct {
+	Rotations []models.ReviewRotation `json:"rotations"`
+}
+
+// handlePRRotations возвращает историю автоматических замен; фильтры pull_request_id и limit необязательны.
+func (s *Server) handlePRRotations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ReviewRotationFilter{PullRequestId: query.Get("pull_request_id")}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	rotations, err := s.staleReviews.Rotations(r.Context(), filter)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rotationsResponse{Rotations: rotations})
+}
+
+func (s *Server) handlePRExplain(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id is required")
+		return
+	}
+
+	explanation, err := s.decisions.Explain(r.Context(), prID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, explanation)
+}
+
 type getUserReviewsResp struct {
 	UserId       string                    `json:"user_id"`
 	PullRequests []models.PullRequestShort `json:"pull_requests"`
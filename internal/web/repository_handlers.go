@@ -0,0 +1,62 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+)
+
+type repositoryResp struct {
+	Repository *models.Repository `json:"repository"`
+}
+
+type repositoriesResp struct {
+	Repositories []models.Repository `json:"repositories"`
+}
+
+// handleSetRepository создаёт репозиторий или меняет его владельца и число ревьюеров.
+func (s *Server) handleSetRepository(w http.ResponseWriter, r *http.Request) {
+	var p models.Repository
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.RepositoryName == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "repository_name is required")
+		return
+	}
+
+	repo, err := s.repositories.SetRepository(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, repositoryResp{Repository: repo})
+}
+
+// handleGetRepository возвращает репозиторий с его правилами ревью.
+func (s *Server) handleGetRepository(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("repository_name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "repository_name is required")
+		return
+	}
+
+	repo, err := s.repositories.Repository(r.Context(), name)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, repositoryResp{Repository: repo})
+}
+
+// handleListRepositories возвращает все репозитории.
+func (s *Server) handleListRepositories(w http.ResponseWriter, r *http.Request) {
+	repos, err := s.repositories.Repositories(r.Context())
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, repositoriesResp{Repositories: repos})
+}
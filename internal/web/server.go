@@ -11,12 +11,14 @@ import (
 
 	"github.com/AlekseyZapadovnikov/pr-manager/conf"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/logging"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/metrics"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/tracing"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-
 type Server struct {
 	Address string
 	server  *http.Server
@@ -24,10 +26,125 @@ type Server struct {
 	router          *chi.Mux
 	prService       PullRequestService
 	userTeamService UserTeamService
+	snapshots       SnapshotService
+	staleReviews    StaleReviewService
+	absences        AbsenceService
+	workingHours    WorkingHoursService
+	capacity        CapacityService
+	repositories    RepositoryService
+	affinityRules   AffinityRuleService
+	learners        LearnerService
+	decisions       DecisionService
+	organizations   OrganizationService
+	adminToken      string
+	metrics         *metrics.Metrics
+	tracing         bool
+	validate        bool
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// Option настраивает необязательные возможности сервера.
+type Option func(*Server)
+
+// WithMetrics включает сбор HTTP-метрик и маршрут /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithSnapshots включает маршруты /admin/export и /admin/import-snapshot.
+func WithSnapshots(svc SnapshotService) Option {
+	return func(s *Server) {
+		s.snapshots = svc
+	}
+}
+
+// WithStaleReviews включает маршруты /pullRequest/activity и /pullRequest/rotations.
+func WithStaleReviews(svc StaleReviewService) Option {
+	return func(s *Server) {
+		s.staleReviews = svc
+	}
+}
+
+// WithAbsences включает маршруты /users/addAbsence, /users/getAbsences и /users/cancelAbsence.
+func WithAbsences(svc AbsenceService) Option {
+	return func(s *Server) {
+		s.absences = svc
+	}
+}
+
+// WithWorkingHours включает маршруты /users/setWorkingHours, /users/getWorkingHours и /users/clearWorkingHours.
+func WithWorkingHours(svc WorkingHoursService) Option {
+	return func(s *Server) {
+		s.workingHours = svc
+	}
+}
+
+// WithCapacity включает маршруты /users/setCapacity и /users/getLoad.
+func WithCapacity(svc CapacityService) Option {
+	return func(s *Server) {
+		s.capacity = svc
+	}
+}
+
+// WithRepositories включает маршруты /repositories/set, /repositories/get и /repositories/list.
+func WithRepositories(svc RepositoryService) Option {
+	return func(s *Server) {
+		s.repositories = svc
+	}
+}
+
+// WithAffinityRules включает маршруты /team/addRule, /team/getRules и /team/deleteRule.
+func WithAffinityRules(svc AffinityRuleService) Option {
+	return func(s *Server) {
+		s.affinityRules = svc
+	}
+}
+
+// WithLearners включает маршруты /team/setLearners и /team/getLearners.
+func WithLearners(svc LearnerService) Option {
+	return func(s *Server) {
+		s.learners = svc
+	}
+}
+
+// WithDecisions включает маршрут /pullRequest/explain.
+func WithDecisions(svc DecisionService) Option {
+	return func(s *Server) {
+		s.decisions = svc
+	}
+}
+
+// WithOrganizations требует токен организации на всех маршрутах API и включает маршруты
+// /admin/organizations/create и /admin/organizations/list, доступные только с adminToken.
+func WithOrganizations(svc OrganizationService, adminToken string) Option {
+	return func(s *Server) {
+		s.organizations = svc
+		s.adminToken = adminToken
+	}
+}
+
+// WithTracing открывает спан OpenTelemetry на каждый запрос с учётом входящего traceparent.
+func WithTracing() Option {
+	return func(s *Server) {
+		s.tracing = true
+	}
+}
+
+// WithRequestValidation проверяет JSON-тела запросов по встроенной спецификации OpenAPI
+// и отклоняет несоответствующие с кодом INVALID_PAYLOAD и списком нарушений.
+func WithRequestValidation() Option {
+	return func(s *Server) {
+		s.validate = true
+	}
 }
 
 // New конструирует HTTP-сервер на базе chi и регистрирует все маршруты.
-func New(cfg conf.HttpServConf, pr PullRequestService, user UserTeamService) *Server {
+func New(cfg conf.HttpServConf, pr PullRequestService, user UserTeamService, opts ...Option) *Server {
 	servAdres := cfg.GetAddress()
 	mux := chi.NewMux()
 	srv := &Server{
@@ -40,6 +157,9 @@ func New(cfg conf.HttpServConf, pr PullRequestService, user UserTeamService) *Se
 		Addr:    servAdres,
 		Handler: mux,
 	}
+	for _, opt := range opts {
+		opt(srv)
+	}
 
 	srv.setupRoutes()
 
@@ -54,8 +174,24 @@ func (s *Server) Start() error {
 
 // setupRoutes настраивает middleware, статику и HTTP-маршруты.
 func (s *Server) setupRoutes() {
-	s.router.Use(middleware.Logger)
+	s.router.Use(logging.RequestIDMiddleware)
+	if s.tracing {
+		s.router.Use(tracing.Middleware)
+	}
+	if s.metrics != nil {
+		s.router.Use(s.metrics.Middleware)
+	}
+	s.router.Use(requestLogger)
 	s.router.Use(middleware.Recoverer)
+	var validator func(http.Handler) http.Handler
+	if s.validate {
+		router, err := specRouter()
+		if err != nil {
+			// Спецификация встроена в бинарник, поэтому ошибка здесь — дефект сборки.
+			panic(err)
+		}
+		validator = requestValidator(router)
+	}
 
 	// Обслуживаем статические файлы.
 	staticDir := os.Getenv("STATIC_DIR")
@@ -71,25 +207,97 @@ func (s *Server) setupRoutes() {
 
 	// Простейший health-check.
 	s.router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
 	})
 
-	// Маршруты управления командами.
-	s.router.Post("/team/add", s.handleTeamAdd)
-	s.router.Get("/team/get", s.handleTeamGet)
-	s.router.Post("/team/deactivateUsers", s.handleTeamDeactivate)
+	// Спецификация API и документация по ней.
+	s.router.Get("/openapi.yml", handleOpenAPISpec)
+	s.router.Get("/docs", handleDocs)
 
-	// Маршруты управления пользователями.
-	s.router.Post("/users/setIsActive", s.handleSetUserActivity)
-	s.router.Get("/users/getReview", s.handleGetUserReviews)
+	// Метрики в формате Prometheus.
+	if s.metrics != nil {
+		s.router.Method(http.MethodGet, "/metrics", s.metrics.Handler())
+	}
 
-	// Маршруты для Pull Request.
-	s.router.Post("/pullRequest/create", s.handlePRCreate)
-	s.router.Post("/pullRequest/merge", s.handlePRMerge)
-	s.router.Post("/pullRequest/reassign", s.handlePRReassign)
+	// Маршруты API; с организациями запрос выполняется в организации токена.
+	// Тело проверяется по спецификации только после аутентификации, чтобы запрос без токена получал 401,
+	// а не подробности схемы.
+	s.router.Group(func(r chi.Router) {
+		if s.organizations != nil {
+			r.Use(s.authenticate)
+		}
+		if validator != nil {
+			r.Use(validator)
+		}
 
-	// Маршрут статистики.
-	s.router.Get("/stats/assignments", s.handleAssignmentStats)
+		// Маршруты управления командами.
+		r.Post("/team/add", s.handleTeamAdd)
+		r.Get("/team/get", s.handleTeamGet)
+		r.Post("/team/deactivateUsers", s.handleTeamDeactivate)
+		if s.affinityRules != nil {
+			r.Post("/team/addRule", s.handleTeamAddRule)
+			r.Get("/team/getRules", s.handleTeamGetRules)
+			r.Post("/team/deleteRule", s.handleTeamDeleteRule)
+		}
+		if s.learners != nil {
+			r.Post("/team/setLearners", s.handleTeamSetLearners)
+			r.Get("/team/getLearners", s.handleTeamGetLearners)
+		}
+
+		// Маршруты управления пользователями.
+		r.Post("/users/setIsActive", s.handleSetUserActivity)
+		r.Get("/users/getReview", s.handleGetUserReviews)
+		if s.absences != nil {
+			r.Post("/users/addAbsence", s.handleAddAbsence)
+			r.Get("/users/getAbsences", s.handleGetAbsences)
+			r.Post("/users/cancelAbsence", s.handleCancelAbsence)
+		}
+		if s.workingHours != nil {
+			r.Post("/users/setWorkingHours", s.handleSetWorkingHours)
+			r.Get("/users/getWorkingHours", s.handleGetWorkingHours)
+			r.Post("/users/clearWorkingHours", s.handleClearWorkingHours)
+		}
+		if s.capacity != nil {
+			r.Post("/users/setCapacity", s.handleSetCapacity)
+			r.Get("/users/getLoad", s.handleGetLoad)
+		}
+
+		// Маршруты репозиториев.
+		if s.repositories != nil {
+			r.Post("/repositories/set", s.handleSetRepository)
+			r.Get("/repositories/get", s.handleGetRepository)
+			r.Get("/repositories/list", s.handleListRepositories)
+		}
+
+		// Маршруты для Pull Request.
+		r.Post("/pullRequest/create", s.handlePRCreate)
+		r.Post("/pullRequest/merge", s.handlePRMerge)
+		r.Post("/pullRequest/reassign", s.handlePRReassign)
+		if s.staleReviews != nil {
+			r.Post("/pullRequest/activity", s.handlePRActivity)
+			r.Get("/pullRequest/rotations", s.handlePRRotations)
+		}
+		if s.decisions != nil {
+			r.Get("/pullRequest/explain", s.handlePRExplain)
+		}
+
+		// Маршруты статистики.
+		r.Get("/stats/assignments", s.handleAssignmentStats)
+		r.Get("/stats/turnaround", s.handleTurnaroundStats)
+
+		// Административные операции. Импорт и экспорт работают в организации токена, поэтому открыты
+		// и токенам организаций: каждая организация переносит только свои данные.
+		r.Post("/admin/import", s.handleAdminImport)
+		if s.snapshots != nil {
+			r.Get("/admin/export", s.handleAdminExport)
+			r.Post("/admin/import-snapshot", s.handleAdminImportSnapshot)
+		}
+		if s.organizations != nil {
+			// Организациями управляет только администратор.
+			r.With(requireAdmin).Post("/admin/organizations/create", s.handleCreateOrganization)
+			r.With(requireAdmin).Get("/admin/organizations/list", s.handleListOrganizations)
+		}
+	})
 }
 
 // Shutdown останавливает HTTP-сервер с таймаутом на корректное завершение.
@@ -131,8 +339,15 @@ func mapDomainError(err error) (status int, code, msg string) {
 		return http.StatusNotFound, "NOT_FOUND", err.Error()
 	case errors.Is(err, domain.ErrUnauthorized):
 		return http.StatusUnauthorized, "UNAUTHORIZED", err.Error()
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "FORBIDDEN", err.Error()
+	case errors.Is(err, domain.ErrInvalidParam):
+		return http.StatusBadRequest, "INVALID_PARAM", err.Error()
+	case errors.Is(err, domain.ErrNotEmpty):
+		return http.StatusConflict, "NOT_EMPTY", err.Error()
+	case errors.Is(err, domain.ErrOrgExists):
+		return http.StatusConflict, "ORG_EXISTS", err.Error()
 	default:
-		slog.Warn("unmapped domain error", "err", err.Error())
 		return http.StatusInternalServerError, "INTERNAL_ERROR", err.Error()
 	}
 }
@@ -2,17 +2,42 @@ package web
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
 )
 
 // handleAssignmentStats возвращает агрегированную статистику выдачи ревьюеров.
+// Необязательные параметры: team, repository, from и to (RFC 3339, по времени создания PR), status и limit.
+// При Accept text/csv или application/x-ndjson строки одного среза (параметр by) выгружаются потоком.
 func (s *Server) handleAssignmentStats(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	stats, err := s.prService.AssignmentStats(ctx)
+	query := r.URL.Query()
+	filter := models.AssignmentStatsFilter{
+		TeamName:   query.Get("team"),
+		Repository: query.Get("repository"),
+		Status:     models.PullRequestStatus(query.Get("status")),
+	}
+	if !parseTimeParams(w, r, &filter.From, &filter.To) {
+		return
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if format := negotiateFormat(w, r); format != formatJSON {
+		s.exportAssignmentStats(w, r, format, filter)
+		return
+	}
+
+	stats, err := s.prService.AssignmentStats(r.Context(), filter)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -22,3 +47,94 @@ func (s *Server) handleAssignmentStats(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, stats)
 }
+
+// exportAssignmentStats выгружает срез статистики by: user (по умолчанию), pull_request или team.
+func (s *Server) exportAssignmentStats(w http.ResponseWriter, r *http.Request, format exportFormat, filter models.AssignmentStatsFilter) {
+	ctx := r.Context()
+	switch by := r.URL.Query().Get("by"); by {
+	case "", "user":
+		writeExport(w, r, format,
+			[]string{"user_id", "username", "assignments", "weighted_load", "shadow_assignments"},
+			func(stat models.UserAssignmentStat) []string {
+				return []string{stat.UserId, stat.Username, strconv.Itoa(stat.Assignments), strconv.Itoa(stat.WeightedLoad),
+					strconv.Itoa(stat.ShadowAssignments)}
+			},
+			func(emit func(models.UserAssignmentStat) error) error {
+				return s.prService.ExportUserAssignments(ctx, filter, emit)
+			})
+	case "pull_request":
+		writeExport(w, r, format,
+			[]string{"pull_request_id", "pull_request_name", "reviewer_count", "shadow_count"},
+			func(stat models.PullRequestAssignmentStat) []string {
+				return []string{stat.PullRequestId, stat.PullRequestName, strconv.Itoa(stat.ReviewerCount), strconv.Itoa(stat.ShadowCount)}
+			},
+			func(emit func(models.PullRequestAssignmentStat) error) error {
+				return s.prService.ExportPullRequestAssignments(ctx, filter, emit)
+			})
+	case "team":
+		writeExport(w, r, format,
+			[]string{"team_name", "open_count", "merged_count", "avg_reviewers", "load_imbalance"},
+			func(stat models.TeamAssignmentStat) []string {
+				return []string{
+					stat.TeamName,
+					strconv.Itoa(stat.OpenCount),
+					strconv.Itoa(stat.MergedCount),
+					formatFloat(stat.AvgReviewers),
+					formatFloat(stat.LoadImbalance),
+				}
+			},
+			func(emit func(models.TeamAssignmentStat) error) error {
+				// Команд немного, поэтому их агрегаты читаются целиком.
+				teams, err := s.prService.TeamAssignmentStats(ctx, filter)
+				if err != nil {
+					return err
+				}
+				for _, team := range teams {
+					if err := emit(team); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "by must be one of user, pull_request, team")
+	}
+}
+
+// handleTurnaroundStats возвращает перцентили времени до слияния PR.
+// Необязательные параметры from и to задают окно в формате RFC 3339, repository — репозиторий PR.
+func (s *Server) handleTurnaroundStats(w http.ResponseWriter, r *http.Request) {
+	filter := models.TurnaroundFilter{Repository: r.URL.Query().Get("repository")}
+	if !parseTimeParams(w, r, &filter.From, &filter.To) {
+		return
+	}
+
+	stats, err := s.prService.TurnaroundStats(r.Context(), filter)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// parseTimeParams разбирает необязательные параметры from и to в формате RFC 3339.
+// При ошибке пишет ответ INVALID_PARAM и возвращает false.
+func parseTimeParams(w http.ResponseWriter, r *http.Request, from, to *time.Time) bool {
+	for _, p := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", from}, {"to", to}} {
+		raw := r.URL.Query().Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", p.name+" must be an RFC 3339 timestamp")
+			return false
+		}
+		*p.target = t
+	}
+	return true
+}
@@ -2,7 +2,6 @@ package web
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -20,7 +19,6 @@ type teamDeactivateResponse struct {
 // handleTeamAdd создаёт команду и сохраняет участников.
 func (s *Server) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 	var team models.Team
-	fmt.Println(r.Body)
 	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
 		return
@@ -29,13 +27,11 @@ func (s *Server) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	err := s.userTeamService.AddTeam(ctx, team)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
-	resp := map[string]*models.Team{"team": &team}
-	writeJSON(w, http.StatusCreated, resp)
+	writeJSON(w, http.StatusCreated, teamAddResponse{Team: &team})
 }
 
 // handleTeamGet возвращает команду по её имени.
@@ -49,8 +45,7 @@ func (s *Server) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	team, err := s.userTeamService.GetTeam(ctx, teamName)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, team)
@@ -85,10 +80,113 @@ func (s *Server) handleTeamDeactivate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	result, err := s.prService.BulkDeactivateTeamMembers(ctx, req.TeamName, filtered)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, teamDeactivateResponse{Result: result})
 }
+
+type affinityRuleResp struct {
+	Rule *models.AffinityRule `json:"rule"`
+}
+
+type affinityRulesResp struct {
+	TeamName string                `json:"team_name"`
+	Rules    []models.AffinityRule `json:"rules"`
+}
+
+type deleteRuleReq struct {
+	RuleId int64 `json:"rule_id"`
+}
+
+// handleTeamAddRule добавляет команде правило подбора ревьюеров.
+func (s *Server) handleTeamAddRule(w http.ResponseWriter, r *http.Request) {
+	var p models.PostTeamAddRuleJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.TeamName == "" || p.Kind == "" || p.UserId == "" || len(p.WithUserIds) == 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "team_name, kind, user_id and with_user_ids are required")
+		return
+	}
+
+	rule, err := s.affinityRules.AddRule(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, affinityRuleResp{Rule: rule})
+}
+
+// handleTeamGetRules возвращает правила подбора ревьюеров команды.
+func (s *Server) handleTeamGetRules(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+		return
+	}
+
+	rules, err := s.affinityRules.Rules(r.Context(), teamName)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, affinityRulesResp{TeamName: teamName, Rules: rules})
+}
+
+// handleTeamDeleteRule удаляет правило подбора ревьюеров.
+func (s *Server) handleTeamDeleteRule(w http.ResponseWriter, r *http.Request) {
+	var p deleteRuleReq
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.RuleId == 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "rule_id is required")
+		return
+	}
+
+	if err := s.affinityRules.DeleteRule(r.Context(), p.RuleId); err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deleteRuleReq{RuleId: p.RuleId})
+}
+
+// handleTeamSetLearners заменяет список стажёров команды.
+func (s *Server) handleTeamSetLearners(w http.ResponseWriter, r *http.Request) {
+	var p models.PostTeamSetLearnersJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+		return
+	}
+
+	learners, err := s.learners.SetLearners(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, learners)
+}
+
+// handleTeamGetLearners возвращает стажёров команды с числом их открытых теневых ревью.
+func (s *Server) handleTeamGetLearners(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+		return
+	}
+
+	learners, err := s.learners.Learners(r.Context(), teamName)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, learners)
+}
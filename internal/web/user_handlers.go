@@ -23,29 +23,41 @@ func (s *Server) handleSetUserActivity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.userTeamService.SetUserActivity(p.UserId, p.IsActive)
+	user, err := s.userTeamService.SetUserActivity(r.Context(), p.UserId, p.IsActive)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, setUserResp{User: user})
 }
 
-// handleGetUserReviews возвращает список PR, назначенных ревьюеру.
+// handleGetUserReviews возвращает список PR, назначенных ревьюеру; необязательный repository оставляет PR одного репозитория.
+// При Accept text/csv или application/x-ndjson список выгружается потоком.
 func (s *Server) handleGetUserReviews(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
 		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
 		return
 	}
+	repository := r.URL.Query().Get("repository")
 
 	ctx := r.Context()
-	prs, err := s.prService.ListForReviewer(ctx, userID)
+	if format := negotiateFormat(w, r); format != formatJSON {
+		writeExport(w, r, format,
+			[]string{"pull_request_id", "pull_request_name", "author_id", "status", "role"},
+			func(pr models.PullRequestShort) []string {
+				return []string{pr.PullRequestId, pr.PullRequestName, pr.AuthorId, string(pr.Status), string(pr.Role)}
+			},
+			func(emit func(models.PullRequestShort) error) error {
+				return s.prService.ExportReviewerPullRequests(ctx, userID, repository, emit)
+			})
+		return
+	}
+
+	prs, err := s.prService.ListForReviewer(ctx, userID, repository)
 	if err != nil {
-		status, code, msg := mapDomainError(err)
-		writeError(w, status, code, msg)
+		writeDomainError(w, r, err)
 		return
 	}
 
@@ -54,3 +66,176 @@ func (s *Server) handleGetUserReviews(w http.ResponseWriter, r *http.Request) {
 		PullRequests: prs,
 	})
 }
+
+type absenceResp struct {
+	Absence *models.Absence `json:"absence"`
+}
+
+type absencesResp struct {
+	UserId   string           `json:"user_id"`
+	Absences []models.Absence `json:"absences"`
+}
+
+type cancelAbsenceReq struct {
+	AbsenceId int64 `json:"absence_id"`
+}
+
+// handleAddAbsence регистрирует период отсутствия пользователя.
+func (s *Server) handleAddAbsence(w http.ResponseWriter, r *http.Request) {
+	var p models.PostUsersAddAbsenceJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.UserId == "" || p.StartsAt.IsZero() || p.EndsAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id, starts_at and ends_at are required")
+		return
+	}
+
+	absence, err := s.absences.AddAbsence(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, absenceResp{Absence: absence})
+}
+
+// handleGetAbsences возвращает периоды отсутствия пользователя.
+func (s *Server) handleGetAbsences(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
+		return
+	}
+
+	absences, err := s.absences.Absences(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, absencesResp{UserId: userID, Absences: absences})
+}
+
+// handleCancelAbsence отменяет период отсутствия.
+func (s *Server) handleCancelAbsence(w http.ResponseWriter, r *http.Request) {
+	var p cancelAbsenceReq
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.AbsenceId == 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "absence_id is required")
+		return
+	}
+
+	absence, err := s.absences.CancelAbsence(r.Context(), p.AbsenceId)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, absenceResp{Absence: absence})
+}
+
+type workingHoursResp struct {
+	WorkingHours *models.WorkingHours `json:"working_hours"`
+}
+
+type clearWorkingHoursReq struct {
+	UserId string `json:"user_id"`
+}
+
+// handleSetWorkingHours задаёт часовой пояс и рабочее время пользователя.
+func (s *Server) handleSetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var p models.WorkingHours
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.UserId == "" || p.TimeZone == "" || p.Start == "" || p.End == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id, time_zone, start and end are required")
+		return
+	}
+
+	wh, err := s.workingHours.SetWorkingHours(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workingHoursResp{WorkingHours: wh})
+}
+
+// handleGetWorkingHours возвращает рабочее время пользователя.
+func (s *Server) handleGetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
+		return
+	}
+
+	wh, err := s.workingHours.WorkingHours(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workingHoursResp{WorkingHours: wh})
+}
+
+// handleClearWorkingHours удаляет рабочее время пользователя.
+func (s *Server) handleClearWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var p clearWorkingHoursReq
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.UserId == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
+		return
+	}
+
+	wh, err := s.workingHours.ClearWorkingHours(r.Context(), p.UserId)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, workingHoursResp{WorkingHours: wh})
+}
+
+type reviewerLoadResp struct {
+	Load *models.ReviewerLoad `json:"load"`
+}
+
+// handleSetCapacity задаёт личный лимит открытых ревью пользователя; 0 сбрасывает его к лимиту по умолчанию.
+func (s *Server) handleSetCapacity(w http.ResponseWriter, r *http.Request) {
+	var p models.PostUsersSetCapacityJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+		return
+	}
+	if p.UserId == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
+		return
+	}
+
+	load, err := s.capacity.SetCapacity(r.Context(), p)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reviewerLoadResp{Load: load})
+}
+
+// handleGetLoad возвращает число открытых ревью пользователя и его лимит.
+func (s *Server) handleGetLoad(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAM", "user_id is required")
+		return
+	}
+
+	load, err := s.capacity.ReviewerLoad(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reviewerLoadResp{Load: load})
+}
@@ -5,14 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AlekseyZapadovnikov/pr-manager/conf"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/domain"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/logging"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/metrics"
 	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/repository/memory"
+	"github.com/AlekseyZapadovnikov/pr-manager/internal/service"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,6 +43,57 @@ func TestNewServerRegistersRoutes(t *testing.T) {
 	require.Equal(t, "ok", resp["status"])
 }
 
+func TestMetricsRouteIsOptional(t *testing.T) {
+	cfg := conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}
+
+	plain := New(cfg, &fakePRService{}, &fakeUserTeamService{})
+	rr := httptest.NewRecorder()
+	plain.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	srv := New(cfg, &fakePRService{}, &fakeUserTeamService{}, WithMetrics(metrics.New()))
+	srv.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	rr = httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), `prmanager_http_requests_total{method="GET",route="/health",status="200"} 1`)
+}
+
+func TestDomainErrorLoggedOnceWithCode(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(conf.LogConf{Format: conf.LogFormatJSON}, &buf)
+	require.NoError(t, err)
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	srv := New(conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}, &fakePRService{}, &fakeUserTeamService{
+		getFn: func(context.Context, string) (*models.Team, error) {
+			return nil, domain.NewNotFoundError("team")
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/team/get?team_name=ghost", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-7")
+	rr := httptest.NewRecorder()
+	srv.router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.Equal(t, "req-7", rr.Header().Get(logging.RequestIDHeader))
+
+	var failures []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		require.Equal(t, "req-7", entry["request_id"], "every line must carry the request id")
+		if entry["msg"] == "request failed" {
+			failures = append(failures, entry)
+		}
+	}
+	require.Len(t, failures, 1)
+	require.Equal(t, "NOT_FOUND", failures[0]["code"])
+	require.Equal(t, "WARN", failures[0]["level"])
+}
+
 func TestWriteJSON(t *testing.T) {
 	rr := httptest.NewRecorder()
 	payload := map[string]string{"status": "ok", "message": "<tag>"}
@@ -73,6 +131,8 @@ func TestMapDomainError(t *testing.T) {
 		{name: "no candidate", err: domain.ErrNoCandidate, status: http.StatusConflict, code: "NO_CANDIDATE"},
 		{name: "not found", err: domain.ErrNotFound, status: http.StatusNotFound, code: "NOT_FOUND"},
 		{name: "unauthorized", err: domain.ErrUnauthorized, status: http.StatusUnauthorized, code: "UNAUTHORIZED"},
+		{name: "forbidden", err: domain.ErrForbidden, status: http.StatusForbidden, code: "FORBIDDEN"},
+		{name: "org exists", err: domain.ErrOrgExists, status: http.StatusConflict, code: "ORG_EXISTS"},
 		{name: "default", err: errors.New("boom"), status: http.StatusInternalServerError, code: "INTERNAL_ERROR"},
 	}
 
@@ -353,7 +413,7 @@ func TestHandleGetUserReviews(t *testing.T) {
 
 	t.Run("domain error", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			listFn: func(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+			listFn: func(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error) {
 				require.Equal(t, "user-1", userID)
 				return nil, domain.ErrNotFound
 			},
@@ -368,12 +428,13 @@ func TestHandleGetUserReviews(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			listFn: func(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+			listFn: func(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error) {
 				require.Equal(t, "user-1", userID)
+				require.Equal(t, "search-api", repository)
 				return reviews, nil
 			},
 		}, &fakeUserTeamService{})
-		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=user-1", nil)
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=user-1&repository=search-api", nil)
 		rr := httptest.NewRecorder()
 
 		srv.handleGetUserReviews(rr, req)
@@ -386,6 +447,286 @@ func TestHandleGetUserReviews(t *testing.T) {
 	})
 }
 
+func TestHandleRepositories(t *testing.T) {
+	storage := memory.NewStorage()
+	require.NoError(t, storage.SaveTeam(context.Background(), &models.Team{TeamName: "backend"}))
+	srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+	srv.repositories = service.NewRepositoryManager(storage)
+
+	rr := httptest.NewRecorder()
+	srv.handleSetRepository(rr, httptest.NewRequest(http.MethodPost, "/repositories/set", strings.NewReader(`{"owner_team":"backend"}`)))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "repository_name is required")
+
+	rr = httptest.NewRecorder()
+	srv.handleSetRepository(rr, httptest.NewRequest(http.MethodPost, "/repositories/set",
+		strings.NewReader(`{"repository_name":"search-api","owner_team":"backend","required_reviewers":1}`)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"repository":{"repository_name":"search-api","owner_team":"backend","required_reviewers":1}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	srv.handleSetRepository(rr, httptest.NewRequest(http.MethodPost, "/repositories/set",
+		strings.NewReader(`{"repository_name":"web","owner_team":"ghost"}`)))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	srv.handleGetRepository(rr, httptest.NewRequest(http.MethodGet, "/repositories/get", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "repository_name is required")
+
+	rr = httptest.NewRecorder()
+	srv.handleGetRepository(rr, httptest.NewRequest(http.MethodGet, "/repositories/get?repository_name=web", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	srv.handleListRepositories(rr, httptest.NewRequest(http.MethodGet, "/repositories/list", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp repositoriesResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Repositories, 2)
+	require.Equal(t, models.DefaultRepository, resp.Repositories[0].RepositoryName)
+}
+
+func TestHandleTeamRules(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	require.NoError(t, storage.CreateTeamWithMembers(ctx, &models.Team{TeamName: "backend"}, []models.User{
+		{UserId: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		{UserId: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+	}))
+	srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+	srv.affinityRules = service.NewAffinityRuleManager(storage)
+
+	rr := httptest.NewRecorder()
+	srv.handleTeamAddRule(rr, httptest.NewRequest(http.MethodPost, "/team/addRule", strings.NewReader(`{"team_name":"backend","kind":"NEVER_PAIR"}`)))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "team_name, kind, user_id and with_user_ids are required")
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamAddRule(rr, httptest.NewRequest(http.MethodPost, "/team/addRule",
+		strings.NewReader(`{"team_name":"backend","kind":"SOMETIMES","user_id":"u1","with_user_ids":["u2"]}`)))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamAddRule(rr, httptest.NewRequest(http.MethodPost, "/team/addRule",
+		strings.NewReader(`{"team_name":"backend","kind":"NEVER_PAIR","user_id":"u1","with_user_ids":["u2"],"description":"pair programming"}`)))
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var added affinityRuleResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &added))
+	require.Equal(t, models.AffinityRuleNEVERPAIR, added.Rule.Kind)
+	require.Equal(t, []string{"u2"}, added.Rule.WithUserIds)
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamGetRules(rr, httptest.NewRequest(http.MethodGet, "/team/getRules", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamGetRules(rr, httptest.NewRequest(http.MethodGet, "/team/getRules?team_name=backend", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var listed affinityRulesResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listed))
+	require.Len(t, listed.Rules, 1)
+	require.Equal(t, added.Rule.RuleId, listed.Rules[0].RuleId)
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamDeleteRule(rr, httptest.NewRequest(http.MethodPost, "/team/deleteRule", strings.NewReader(`{}`)))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "rule_id is required")
+
+	body := fmt.Sprintf(`{"rule_id":%d}`, added.Rule.RuleId)
+	rr = httptest.NewRecorder()
+	srv.handleTeamDeleteRule(rr, httptest.NewRequest(http.MethodPost, "/team/deleteRule", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, body, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamDeleteRule(rr, httptest.NewRequest(http.MethodPost, "/team/deleteRule", strings.NewReader(body)))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestHandleTeamLearners(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	require.NoError(t, storage.CreateTeamWithMembers(ctx, &models.Team{TeamName: "backend"}, []models.User{
+		{UserId: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		{UserId: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+	}))
+	srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+	srv.learners = service.NewLearnerManager(storage)
+
+	rr := httptest.NewRecorder()
+	srv.handleTeamSetLearners(rr, httptest.NewRequest(http.MethodPost, "/team/setLearners", strings.NewReader(`{"user_ids":["u2"]}`)))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamSetLearners(rr, httptest.NewRequest(http.MethodPost, "/team/setLearners", strings.NewReader(`{"team_name":"backend","user_ids":["ghost"]}`)))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamSetLearners(rr, httptest.NewRequest(http.MethodPost, "/team/setLearners", strings.NewReader(`{"team_name":"backend","user_ids":["u2"]}`)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"team_name":"backend","learners":[{"user_id":"u2","open_shadow_reviews":0}]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamGetLearners(rr, httptest.NewRequest(http.MethodGet, "/team/getLearners", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "team_name is required")
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamGetLearners(rr, httptest.NewRequest(http.MethodGet, "/team/getLearners?team_name=backend", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var listed models.TeamLearners
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listed))
+	require.Equal(t, []models.Learner{{UserId: "u2"}}, listed.Learners)
+
+	rr = httptest.NewRecorder()
+	srv.handleTeamGetLearners(rr, httptest.NewRequest(http.MethodGet, "/team/getLearners?team_name=ghost", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestHandlePRExplain(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	users := service.NewUserManager(storage)
+	require.NoError(t, users.AddTeam(ctx, models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "Alice", IsActive: true},
+		{UserId: "u2", Username: "Bob", IsActive: true},
+		{UserId: "u3", Username: "Carol", IsActive: false},
+	}}))
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	prs.SetDecisions(storage)
+	_, err := prs.CreatePullRequest(ctx, models.PostPullRequestCreateJSONBody{PullRequestId: "pr-1", PullRequestName: "Fix", AuthorId: "u1"})
+	require.NoError(t, err)
+	srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+	srv.decisions = service.NewDecisionManager(storage)
+
+	rr := httptest.NewRecorder()
+	srv.handlePRExplain(rr, httptest.NewRequest(http.MethodGet, "/pullRequest/explain", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id is required")
+
+	rr = httptest.NewRecorder()
+	srv.handlePRExplain(rr, httptest.NewRequest(http.MethodGet, "/pullRequest/explain?pull_request_id=ghost", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	srv.handlePRExplain(rr, httptest.NewRequest(http.MethodGet, "/pullRequest/explain?pull_request_id=pr-1", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var explanation models.PullRequestExplanation
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &explanation))
+	require.Len(t, explanation.Decisions, 1)
+	decision := explanation.Decisions[0]
+	require.Equal(t, models.DecisionOperationCREATE, decision.Operation)
+	require.Equal(t, []string{"u2"}, decision.Selected)
+	require.Equal(t, []models.DecisionCandidate{
+		{UserId: "u2", Outcome: models.CandidateOutcomeSELECTED, Load: &models.CandidateLoad{}},
+		{UserId: "u1", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is the author"},
+		{UserId: "u3", Outcome: models.CandidateOutcomeEXCLUDED, Reason: "is inactive"},
+	}, decision.Candidates)
+}
+
+func TestOrganizationTokensIsolateData(t *testing.T) {
+	storage := memory.NewStorage()
+	users := service.NewUserManager(storage)
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	orgs := service.NewOrganizationManager(storage)
+	srv := New(conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}, prs, users, WithOrganizations(orgs, "admin-secret"))
+
+	call := func(method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		srv.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := call(http.MethodGet, "/health", "", "")
+	require.Equal(t, http.StatusOK, rr.Code, "health stays public")
+	rr = call(http.MethodGet, "/team/get?team_name=backend", "", "")
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "UNAUTHORIZED", "bearer token is required")
+	rr = call(http.MethodGet, "/team/get?team_name=backend", "nope", "")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	tokens := map[string]string{}
+	for _, id := range []string{"payments", "search"} {
+		rr = call(http.MethodPost, "/admin/organizations/create", "admin-secret", `{"organization_id":"`+id+`"}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var resp organizationCreateResp
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		tokens[id] = resp.Token
+	}
+	rr = call(http.MethodGet, "/admin/organizations/list", tokens["payments"], "")
+	assertErrorResponse(t, rr, http.StatusForbidden, "FORBIDDEN", "FORBIDDEN: not allowed to manage organizations")
+
+	team := `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`
+	rr = call(http.MethodPost, "/team/add", tokens["payments"], team)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = call(http.MethodGet, "/team/get?team_name=backend", tokens["payments"], "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	for _, token := range []string{tokens["search"], "admin-secret"} {
+		rr = call(http.MethodGet, "/team/get?team_name=backend", token, "")
+		require.Equal(t, http.StatusNotFound, rr.Code, "team of payments must be invisible to other organizations")
+	}
+
+	rr = call(http.MethodPost, "/team/add", tokens["search"], team)
+	require.Equal(t, http.StatusCreated, rr.Code, "the same team name is free in another organization")
+}
+
+func TestAdminTransferRoutesUseTokenOrganization(t *testing.T) {
+	storage := memory.NewStorage()
+	users := service.NewUserManager(storage)
+	prs := (&service.PullRequestManager{}).NewPullRequestService(storage, users)
+	srv := New(conf.HttpServConf{Host: "127.0.0.1", Port: "9999"}, prs, users,
+		WithSnapshots(service.NewSnapshotManager(storage, users)),
+		WithOrganizations(service.NewOrganizationManager(storage), "admin-secret"))
+
+	call := func(method, target, token, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rr := httptest.NewRecorder()
+		srv.router.ServeHTTP(rr, req)
+		return rr
+	}
+	tokens := map[string]string{}
+	for _, id := range []string{"payments", "search"} {
+		rr := call(http.MethodPost, "/admin/organizations/create", "admin-secret", "application/json", `{"organization_id":"`+id+`"}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var resp organizationCreateResp
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		tokens[id] = resp.Token
+	}
+
+	rr := call(http.MethodGet, "/admin/export", "", "", "")
+	require.Equal(t, http.StatusUnauthorized, rr.Code, "transfer routes still need a token")
+
+	rr = call(http.MethodPost, "/admin/import", tokens["payments"], "text/csv", "user_id,username,team_name,is_active\nu1,Alice,backend,true\n")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = call(http.MethodGet, "/admin/export", tokens["payments"], "", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	archive := rr.Body.String()
+	var snap models.Snapshot
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &snap))
+	require.Equal(t, []models.SnapshotTeam{{TeamName: "backend"}}, snap.Teams)
+
+	for _, token := range []string{tokens["search"], "admin-secret"} {
+		rr = call(http.MethodGet, "/admin/export", token, "", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var other models.Snapshot
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &other))
+		require.Empty(t, other.Teams, "export holds only the data of the token's organization")
+	}
+
+	rr = call(http.MethodPost, "/admin/import-snapshot", tokens["search"], "application/json", archive)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = call(http.MethodGet, "/team/get?team_name=backend", tokens["search"], "", "")
+	require.Equal(t, http.StatusOK, rr.Code, "the archive is restored into the token's organization")
+	rr = call(http.MethodGet, "/team/get?team_name=backend", "admin-secret", "", "")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 func TestHandlePRCreate(t *testing.T) {
 	payload := models.PostPullRequestCreateJSONBody{
 		AuthorId:        "author-1",
@@ -393,6 +734,7 @@ func TestHandlePRCreate(t *testing.T) {
 		PullRequestName: "Feature",
 	}
 	pr := &models.PullRequest{AuthorId: payload.AuthorId, PullRequestId: payload.PullRequestId, PullRequestName: payload.PullRequestName}
+	load := []models.ReviewerLoad{{UserId: "u2", OpenReviews: 3, MaxOpenReviews: 3}}
 
 	t.Run("invalid payload", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
@@ -413,12 +755,12 @@ func TestHandlePRCreate(t *testing.T) {
 
 		srv.handlePRCreate(rr, req)
 
-		assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id, pull_request_name and author_id are required")
+		assertErrorResponse(t, rr, http.StatusBadRequest, "MISSING_PARAM", "pull_request_id or number, pull_request_name and author_id are required")
 	})
 
 	t.Run("domain error", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			createFn: func(ctx context.Context, got models.PostPullRequestCreateJSONBody) (*models.PullRequest, error) {
+			createFn: func(ctx context.Context, got models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error) {
 				require.Equal(t, payload, got)
 				return nil, domain.ErrPRExists
 			},
@@ -431,11 +773,31 @@ func TestHandlePRCreate(t *testing.T) {
 		assertErrorResponse(t, rr, http.StatusConflict, "PR_EXISTS", domain.ErrPRExists.Error())
 	})
 
+	t.Run("rejected candidates", func(t *testing.T) {
+		reasons := []string{"rule 1: PRs of author-1 need a reviewer from [lead]", "lead: is absent"}
+		srv := newBareServer(&fakePRService{
+			createFn: func(ctx context.Context, got models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error) {
+				return nil, fmt.Errorf("assign reviewers: %w", domain.NewRejectedCandidatesError("pr-1", reasons))
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", mustJSONReader(t, payload))
+		rr := httptest.NewRecorder()
+
+		srv.handlePRCreate(rr, req)
+
+		require.Equal(t, http.StatusConflict, rr.Code)
+		var resp errorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		require.Equal(t, "NO_CANDIDATE", resp.Error.Code)
+		require.Equal(t, "NO_CANDIDATE: no reviewer assignment for pull request pr-1 satisfies the affinity rules", resp.Error.Message)
+		require.Equal(t, reasons, resp.Error.Details)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			createFn: func(ctx context.Context, got models.PostPullRequestCreateJSONBody) (*models.PullRequest, error) {
+			createFn: func(ctx context.Context, got models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error) {
 				require.Equal(t, payload, got)
-				return pr, nil
+				return &domain.CreateResponse{PR: pr, ReviewerLoad: load, PendingReviewers: 1}, nil
 			},
 		}, &fakeUserTeamService{})
 		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", mustJSONReader(t, payload))
@@ -444,9 +806,11 @@ func TestHandlePRCreate(t *testing.T) {
 		srv.handlePRCreate(rr, req)
 
 		require.Equal(t, http.StatusCreated, rr.Code)
-		var resp prResp
+		var resp createPRResp
 		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
 		require.Equal(t, pr, resp.PR)
+		require.Equal(t, load, resp.ReviewerLoad)
+		require.Equal(t, 1, resp.PendingReviewers)
 	})
 }
 
@@ -582,7 +946,7 @@ func TestHandleAssignmentStats(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			assignmentStatsFn: func(ctx context.Context) (*models.AssignmentStats, error) {
+			assignmentStatsFn: func(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 				return stats, nil
 			},
 		}, &fakeUserTeamService{})
@@ -600,7 +964,7 @@ func TestHandleAssignmentStats(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		srv := newBareServer(&fakePRService{
-			assignmentStatsFn: func(ctx context.Context) (*models.AssignmentStats, error) {
+			assignmentStatsFn: func(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 				return nil, errors.New("boom")
 			},
 		}, &fakeUserTeamService{})
@@ -613,18 +977,386 @@ func TestHandleAssignmentStats(t *testing.T) {
 	})
 }
 
+func TestHandleAssignmentStatsFilter(t *testing.T) {
+	t.Run("parses query parameters", func(t *testing.T) {
+		var got models.AssignmentStatsFilter
+		srv := newBareServer(&fakePRService{
+			assignmentStatsFn: func(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
+				got = filter
+				return &models.AssignmentStats{}, nil
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/stats/assignments?team=backend&from=2025-01-01T00:00:00Z&status=OPEN&limit=10", nil)
+		rr := httptest.NewRecorder()
+
+		srv.handleAssignmentStats(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "backend", got.TeamName)
+		require.Equal(t, models.PullRequestStatusOPEN, got.Status)
+		require.Equal(t, 10, got.Limit)
+		require.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), got.From.UTC())
+		require.True(t, got.To.IsZero())
+	})
+
+	for name, query := range map[string]string{
+		"invalid limit": "limit=-1",
+		"invalid from":  "from=monday",
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+			req := httptest.NewRequest(http.MethodGet, "/stats/assignments?"+query, nil)
+			rr := httptest.NewRecorder()
+
+			srv.handleAssignmentStats(rr, req)
+
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			require.Contains(t, rr.Body.String(), "INVALID_PARAM")
+		})
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	cases := map[string]exportFormat{
+		"":                                     formatJSON,
+		"*/*":                                  formatJSON,
+		"text/html":                            formatJSON,
+		"text/csv":                             formatCSV,
+		"application/x-ndjson":                 formatNDJSON,
+		"text/csv;q=0.5, application/x-ndjson": formatNDJSON,
+		"application/json;q=0.9, text/csv":     formatCSV,
+		"text/csv;q=0.1, application/json;q=1": formatJSON,
+	}
+	for accept, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		rr := httptest.NewRecorder()
+		require.Equal(t, want, negotiateFormat(rr, req), "Accept %q", accept)
+		require.Equal(t, "Accept", rr.Header().Get("Vary"))
+	}
+}
+
+func TestAssignmentStatsExport(t *testing.T) {
+	srv := newBareServer(&fakePRService{
+		exportUsersFn: func(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+			require.Equal(t, "backend", filter.TeamName)
+			for _, stat := range []models.UserAssignmentStat{
+				{UserId: "u1", Username: "Alice, Jr.", Assignments: 3, WeightedLoad: 7},
+				{UserId: "u2", Username: "Bob", Assignments: 1, WeightedLoad: 1, ShadowAssignments: 2},
+			} {
+				if err := fn(stat); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		exportPRsFn: func(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+			return fn(models.PullRequestAssignmentStat{PullRequestId: "pr1", PullRequestName: "Docs", ReviewerCount: 2, ShadowCount: 1})
+		},
+		teamStatsFn: func(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+			return []models.TeamAssignmentStat{{TeamName: "backend", OpenCount: 1, AvgReviewers: 1.5, LoadImbalance: 0.25}}, nil
+		},
+	}, &fakeUserTeamService{})
+
+	serve := func(accept, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/stats/assignments?"+query, nil)
+		req.Header.Set("Accept", accept)
+		rr := httptest.NewRecorder()
+		srv.handleAssignmentStats(rr, req)
+		return rr
+	}
+
+	t.Run("csv by user", func(t *testing.T) {
+		rr := serve("text/csv", "team=backend")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		require.Equal(t, "user_id,username,assignments,weighted_load,shadow_assignments\nu1,\"Alice, Jr.\",3,7,0\nu2,Bob,1,1,2\n", rr.Body.String())
+	})
+
+	t.Run("ndjson by pull request", func(t *testing.T) {
+		rr := serve("application/x-ndjson", "by=pull_request")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"pull_request_id":"pr1","pull_request_name":"Docs","reviewer_count":2,"shadow_count":1}`, rr.Body.String())
+	})
+
+	t.Run("csv by team", func(t *testing.T) {
+		rr := serve("text/csv", "by=team")
+		require.Equal(t, "team_name,open_count,merged_count,avg_reviewers,load_imbalance\nbackend,1,0,1.5,0.25\n", rr.Body.String())
+	})
+
+	t.Run("unknown slice", func(t *testing.T) {
+		rr := serve("text/csv", "by=repo")
+		assertErrorResponse(t, rr, http.StatusBadRequest, "INVALID_PARAM", "by must be one of user, pull_request, team")
+	})
+}
+
+func TestExportErrors(t *testing.T) {
+	t.Run("error before first row is a regular error response", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{
+			exportReviewsFn: func(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error {
+				return domain.NewNotFoundError("user")
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1", nil)
+		req.Header.Set("Accept", "text/csv")
+		rr := httptest.NewRecorder()
+
+		srv.handleGetUserReviews(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), "NOT_FOUND")
+	})
+
+	t.Run("error mid-stream truncates output", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{
+			exportReviewsFn: func(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error {
+				if err := fn(models.PullRequestShort{PullRequestId: "pr1", PullRequestName: "Docs", AuthorId: "u2", Status: "OPEN"}); err != nil {
+					return err
+				}
+				return errors.New("connection reset")
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1", nil)
+		req.Header.Set("Accept", "text/csv")
+		rr := httptest.NewRecorder()
+
+		srv.handleGetUserReviews(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.True(t, strings.HasPrefix(rr.Body.String(), "pull_request_id,pull_request_name,author_id,status,role\n"))
+		require.NotContains(t, rr.Body.String(), "INTERNAL_ERROR")
+	})
+
+	t.Run("empty export still has a csv header", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1", nil)
+		req.Header.Set("Accept", "text/csv")
+		rr := httptest.NewRecorder()
+
+		srv.handleGetUserReviews(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "pull_request_id,pull_request_name,author_id,status,role\n", rr.Body.String())
+	})
+}
+
+func TestHandleTurnaroundStats(t *testing.T) {
+	t.Run("passes window to service", func(t *testing.T) {
+		var got models.TurnaroundFilter
+		srv := newBareServer(&fakePRService{
+			turnaroundFn: func(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+				got = filter
+				return &models.TurnaroundStats{
+					From:    filter.From,
+					To:      filter.To,
+					Overall: models.TurnaroundPercentiles{Count: 1, P50Seconds: 60, P90Seconds: 60, P99Seconds: 60},
+				}, nil
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/stats/turnaround?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", nil)
+		rr := httptest.NewRecorder()
+
+		srv.handleTurnaroundStats(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), got.From.UTC())
+		require.Equal(t, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), got.To.UTC())
+		var resp models.TurnaroundStats
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		require.Equal(t, 60.0, resp.Overall.P50Seconds)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/stats/turnaround?from=yesterday", nil)
+		rr := httptest.NewRecorder()
+
+		srv.handleTurnaroundStats(rr, req)
+
+		assertErrorResponse(t, rr, http.StatusBadRequest, "INVALID_PARAM", "from must be an RFC 3339 timestamp")
+	})
+
+	t.Run("domain validation error", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{
+			turnaroundFn: func(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+				return nil, domain.NewInvalidParamError("from", "must be before to")
+			},
+		}, &fakeUserTeamService{})
+		req := httptest.NewRequest(http.MethodGet, "/stats/turnaround", nil)
+		rr := httptest.NewRecorder()
+
+		srv.handleTurnaroundStats(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "INVALID_PARAM")
+	})
+}
+
+func TestHandleAdminImport(t *testing.T) {
+	t.Run("csv with options", func(t *testing.T) {
+		var (
+			gotRows []models.ImportUserRow
+			gotOpts models.ImportOptions
+		)
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{
+			importFn: func(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error) {
+				gotRows, gotOpts = rows, opts
+				return &models.ImportReport{DryRun: opts.DryRun, Created: len(rows)}, nil
+			},
+		})
+		body := "user_id,username,team_name,is_active\nu1,Alice,backend,true\nu2,Bob,backend,false\n"
+		req := httptest.NewRequest(http.MethodPost, "/admin/import?on_conflict=update&dry_run=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		rr := httptest.NewRecorder()
+
+		srv.handleAdminImport(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, models.ImportOptions{DryRun: true, OnConflict: models.ImportConflictUpdate}, gotOpts)
+		require.Equal(t, []models.ImportUserRow{
+			{Row: 1, UserId: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+			{Row: 2, UserId: "u2", Username: "Bob", TeamName: "backend", IsActive: false},
+		}, gotRows)
+		var resp importResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		require.Equal(t, 2, resp.Report.Created)
+	})
+
+	t.Run("failed rows give 422", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{
+			importFn: func(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error) {
+				return &models.ImportReport{Failed: 1, Rows: []models.ImportRowResult{{Row: 1, Action: models.ImportActionError, Error: "user_id is required"}}}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/admin/import", strings.NewReader(`[{"username":"Alice","team_name":"backend"}]`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		srv.handleAdminImport(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		require.Contains(t, rr.Body.String(), "user_id is required")
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		cases := []struct {
+			name, target, contentType, body string
+			status                          int
+			code, msg                       string
+		}{
+			{"dry_run", "/admin/import?dry_run=maybe", "application/json", "[]", http.StatusBadRequest, "INVALID_PARAM", "dry_run must be a boolean"},
+			{"content type", "/admin/import", "application/xml", "<users/>", http.StatusBadRequest, "INVALID_PAYLOAD", "Content-Type must be text/csv or application/json"},
+			{"csv header", "/admin/import", "text/csv", "id,name\n", http.StatusBadRequest, "INVALID_PAYLOAD", `csv header misses column "user_id"`},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+				req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+				req.Header.Set("Content-Type", tc.contentType)
+				rr := httptest.NewRecorder()
+
+				srv.handleAdminImport(rr, req)
+
+				assertErrorResponse(t, rr, tc.status, tc.code, tc.msg)
+			})
+		}
+	})
+
+	t.Run("domain error", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{
+			importFn: func(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error) {
+				return nil, domain.NewInvalidParamError("on_conflict", "must be one of skip, update, fail")
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/admin/import?on_conflict=merge", strings.NewReader("[]"))
+		rr := httptest.NewRecorder()
+
+		srv.handleAdminImport(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "INVALID_PARAM")
+	})
+}
+
+func TestHandleAdminSnapshot(t *testing.T) {
+	t.Run("export as attachment", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+		srv.snapshots = &fakeSnapshotService{
+			exportFn: func(ctx context.Context) (*models.Snapshot, error) {
+				return &models.Snapshot{
+					Version:   models.SnapshotVersion,
+					CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+					Teams:     []models.SnapshotTeam{{TeamName: "backend"}},
+				}, nil
+			},
+		}
+		rr := httptest.NewRecorder()
+
+		srv.handleAdminExport(rr, httptest.NewRequest(http.MethodGet, "/admin/export", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "attachment; filename=pr-manager-snapshot-20250301T120000Z.json", rr.Header().Get("Content-Disposition"))
+		var snap models.Snapshot
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &snap))
+		require.Equal(t, []models.SnapshotTeam{{TeamName: "backend"}}, snap.Teams)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		var got *models.Snapshot
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+		srv.snapshots = &fakeSnapshotService{
+			restoreFn: func(ctx context.Context, snap *models.Snapshot) (models.SnapshotCounts, error) {
+				got = snap
+				return snap.Counts(), nil
+			},
+		}
+		body := `{"version":1,"teams":[{"team_name":"backend"}],"users":[{"user_id":"u1","username":"Alice","team_name":"backend","is_active":true}],"pull_requests":[]}`
+		rr := httptest.NewRecorder()
+
+		srv.handleAdminImportSnapshot(rr, httptest.NewRequest(http.MethodPost, "/admin/import-snapshot", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Len(t, got.Users, 1)
+		require.JSONEq(t, `{"restored":{"teams":1,"users":1,"pull_requests":0,"reviewers":0}}`, rr.Body.String())
+	})
+
+	t.Run("restore errors", func(t *testing.T) {
+		srv := newBareServer(&fakePRService{}, &fakeUserTeamService{})
+		srv.snapshots = &fakeSnapshotService{
+			restoreFn: func(ctx context.Context, snap *models.Snapshot) (models.SnapshotCounts, error) {
+				return models.SnapshotCounts{}, domain.NewNotEmptyError("database")
+			},
+		}
+
+		rr := httptest.NewRecorder()
+		srv.handleAdminImportSnapshot(rr, httptest.NewRequest(http.MethodPost, "/admin/import-snapshot", strings.NewReader("{")))
+		assertErrorResponse(t, rr, http.StatusBadRequest, "INVALID_PAYLOAD", "invalid json payload")
+
+		rr = httptest.NewRecorder()
+		srv.handleAdminImportSnapshot(rr, httptest.NewRequest(http.MethodPost, "/admin/import-snapshot", strings.NewReader(`{"version":1}`)))
+		require.Equal(t, http.StatusConflict, rr.Code)
+		require.Contains(t, rr.Body.String(), "NOT_EMPTY")
+	})
+}
+
 // --- helpers ----------------------------------------------------------------
 
 type fakePRService struct {
-	createFn          func(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*models.PullRequest, error)
+	createFn          func(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error)
 	mergeFn           func(ctx context.Context, payload models.PostPullRequestMergeJSONBody) (*models.PullRequest, error)
 	reassignFn        func(ctx context.Context, oldUserID, prID string) (*domain.ReassignResponse, error)
-	listFn            func(ctx context.Context, userID string) ([]models.PullRequestShort, error)
-	assignmentStatsFn func(ctx context.Context) (*models.AssignmentStats, error)
+	listFn            func(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error)
+	assignmentStatsFn func(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error)
+	turnaroundFn      func(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error)
+	exportUsersFn     func(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error
+	exportPRsFn       func(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error
+	teamStatsFn       func(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error)
+	exportReviewsFn   func(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error
 	bulkDeactivateFn  func(ctx context.Context, teamName string, userIDs []string) (*models.TeamBulkDeactivateResult, error)
 }
 
-func (f *fakePRService) CreatePullRequest(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*models.PullRequest, error) {
+func (f *fakePRService) CreatePullRequest(ctx context.Context, payload models.PostPullRequestCreateJSONBody) (*domain.CreateResponse, error) {
 	if f != nil && f.createFn != nil {
 		return f.createFn(ctx, payload)
 	}
@@ -645,20 +1377,55 @@ func (f *fakePRService) Reassign(ctx context.Context, oldUsId, prId string) (*do
 	return nil, nil
 }
 
-func (f *fakePRService) ListForReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+func (f *fakePRService) ListForReviewer(ctx context.Context, userID, repository string) ([]models.PullRequestShort, error) {
 	if f != nil && f.listFn != nil {
-		return f.listFn(ctx, userID)
+		return f.listFn(ctx, userID, repository)
 	}
 	return nil, nil
 }
 
-func (f *fakePRService) AssignmentStats(ctx context.Context) (*models.AssignmentStats, error) {
+func (f *fakePRService) AssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) (*models.AssignmentStats, error) {
 	if f != nil && f.assignmentStatsFn != nil {
-		return f.assignmentStatsFn(ctx)
+		return f.assignmentStatsFn(ctx, filter)
 	}
 	return nil, nil
 }
 
+func (f *fakePRService) ExportReviewerPullRequests(ctx context.Context, userID, repository string, fn func(models.PullRequestShort) error) error {
+	if f != nil && f.exportReviewsFn != nil {
+		return f.exportReviewsFn(ctx, userID, repository, fn)
+	}
+	return nil
+}
+
+func (f *fakePRService) TeamAssignmentStats(ctx context.Context, filter models.AssignmentStatsFilter) ([]models.TeamAssignmentStat, error) {
+	if f != nil && f.teamStatsFn != nil {
+		return f.teamStatsFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (f *fakePRService) ExportUserAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.UserAssignmentStat) error) error {
+	if f != nil && f.exportUsersFn != nil {
+		return f.exportUsersFn(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (f *fakePRService) ExportPullRequestAssignments(ctx context.Context, filter models.AssignmentStatsFilter, fn func(models.PullRequestAssignmentStat) error) error {
+	if f != nil && f.exportPRsFn != nil {
+		return f.exportPRsFn(ctx, filter, fn)
+	}
+	return nil
+}
+
+func (f *fakePRService) TurnaroundStats(ctx context.Context, filter models.TurnaroundFilter) (*models.TurnaroundStats, error) {
+	if f != nil && f.turnaroundFn != nil {
+		return f.turnaroundFn(ctx, filter)
+	}
+	return &models.TurnaroundStats{}, nil
+}
+
 func (f *fakePRService) BulkDeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.TeamBulkDeactivateResult, error) {
 	if f != nil && f.bulkDeactivateFn != nil {
 		return f.bulkDeactivateFn(ctx, teamName, userIDs)
@@ -667,9 +1434,10 @@ func (f *fakePRService) BulkDeactivateTeamMembers(ctx context.Context, teamName
 }
 
 type fakeUserTeamService struct {
-	addFn func(ctx context.Context, team models.Team) error
-	getFn func(ctx context.Context, teamName string) (*models.Team, error)
-	setFn func(userID string, isActive bool) (*models.User, error)
+	addFn    func(ctx context.Context, team models.Team) error
+	getFn    func(ctx context.Context, teamName string) (*models.Team, error)
+	setFn    func(userID string, isActive bool) (*models.User, error)
+	importFn func(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error)
 }
 
 func (f *fakeUserTeamService) AddTeam(ctx context.Context, team models.Team) error {
@@ -686,13 +1454,39 @@ func (f *fakeUserTeamService) GetTeam(ctx context.Context, teamName string) (*mo
 	return nil, nil
 }
 
-func (f *fakeUserTeamService) SetUserActivity(userID string, isActive bool) (*models.User, error) {
+func (f *fakeUserTeamService) SetUserActivity(_ context.Context, userID string, isActive bool) (*models.User, error) {
 	if f != nil && f.setFn != nil {
 		return f.setFn(userID, isActive)
 	}
 	return nil, nil
 }
 
+func (f *fakeUserTeamService) ImportUsers(ctx context.Context, rows []models.ImportUserRow, opts models.ImportOptions) (*models.ImportReport, error) {
+	if f != nil && f.importFn != nil {
+		return f.importFn(ctx, rows, opts)
+	}
+	return &models.ImportReport{}, nil
+}
+
+type fakeSnapshotService struct {
+	exportFn  func(ctx context.Context) (*models.Snapshot, error)
+	restoreFn func(ctx context.Context, snap *models.Snapshot) (models.SnapshotCounts, error)
+}
+
+func (f *fakeSnapshotService) Export(ctx context.Context) (*models.Snapshot, error) {
+	if f != nil && f.exportFn != nil {
+		return f.exportFn(ctx)
+	}
+	return &models.Snapshot{Version: models.SnapshotVersion}, nil
+}
+
+func (f *fakeSnapshotService) Restore(ctx context.Context, snap *models.Snapshot) (models.SnapshotCounts, error) {
+	if f != nil && f.restoreFn != nil {
+		return f.restoreFn(ctx, snap)
+	}
+	return snap.Counts(), nil
+}
+
 func newBareServer(pr PullRequestService, user UserTeamService) *Server {
 	return &Server{
 		prService:       pr,
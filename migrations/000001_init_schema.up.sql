@@ -1,10 +1,10 @@
 -- Таблица команд
-CREATE TABLE teams (
+CREATE TABLE IF NOT EXISTS teams (
     team_name TEXT PRIMARY KEY
 );
 
 -- Таблица пользователей
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     user_id   TEXT PRIMARY KEY,
     username  TEXT NOT NULL,
     is_active BOOLEAN NOT NULL DEFAULT true,
@@ -12,7 +12,7 @@ CREATE TABLE users (
 );
 
 -- Таблица Pull Request'ов
-CREATE TABLE pull_requests (
+CREATE TABLE IF NOT EXISTS pull_requests (
     pull_request_id   TEXT PRIMARY KEY,
     pull_request_name TEXT NOT NULL,
     author_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
@@ -22,8 +22,8 @@ CREATE TABLE pull_requests (
 );
 
 -- Назначенные ревьюеры (0–2 на PR)
-CREATE TABLE pull_request_reviewers (
+CREATE TABLE IF NOT EXISTS pull_request_reviewers (
     pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
     user_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
     PRIMARY KEY (pull_request_id, user_id)
-);
\ No newline at end of file
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scheduler_leases;
+DROP TABLE IF EXISTS review_rotations;
+ALTER TABLE pull_request_reviewers DROP COLUMN IF EXISTS last_activity_at;
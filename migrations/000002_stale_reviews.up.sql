@@ -0,0 +1,29 @@
+-- Последняя активность ревьюера по PR; для уже назначенных берём время создания PR
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE pull_request_reviewers r
+SET last_activity_at = p.created_at
+FROM pull_requests p
+WHERE p.pull_request_id = r.pull_request_id
+  AND p.created_at IS NOT NULL;
+
+-- История автоматических замен неактивных ревьюеров
+CREATE TABLE IF NOT EXISTS review_rotations (
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id     TEXT NOT NULL,
+    new_user_id     TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    idle_seconds    BIGINT NOT NULL,
+    rotated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (pull_request_id);
+
+-- Аренды фоновых задач: задачу выполняет только держатель неистёкшей аренды
+CREATE TABLE IF NOT EXISTS scheduler_leases (
+    name       TEXT PRIMARY KEY,
+    holder     TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
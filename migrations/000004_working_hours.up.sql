@@ -0,0 +1,8 @@
+-- Часовой пояс (IANA) и рабочее время пользователя; рабочие дни — с понедельника по пятницу
+CREATE TABLE IF NOT EXISTS user_working_hours (
+    user_id    TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    time_zone  TEXT NOT NULL,
+    work_start TEXT NOT NULL,
+    work_end   TEXT NOT NULL,
+    CHECK (work_end > work_start)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_queue;
+DROP TABLE IF EXISTS user_review_capacity;
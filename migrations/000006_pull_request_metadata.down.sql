@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS review_weight,
+    DROP COLUMN IF EXISTS labels,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS files_changed,
+    DROP COLUMN IF EXISTS lines_deleted,
+    DROP COLUMN IF EXISTS lines_added;
@@ -0,0 +1,9 @@
+-- Размер, срочность и метки PR; review_weight — вес ревью, рассчитанный приложением по размеру,
+-- хранится, чтобы нагрузку и статистику можно было суммировать в SQL. У существующих PR размер не известен.
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS lines_added   INTEGER NOT NULL DEFAULT 0 CHECK (lines_added >= 0),
+    ADD COLUMN IF NOT EXISTS lines_deleted INTEGER NOT NULL DEFAULT 0 CHECK (lines_deleted >= 0),
+    ADD COLUMN IF NOT EXISTS files_changed INTEGER NOT NULL DEFAULT 0 CHECK (files_changed >= 0),
+    ADD COLUMN IF NOT EXISTS priority      TEXT NOT NULL DEFAULT 'NORMAL',
+    ADD COLUMN IF NOT EXISTS labels        TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS review_weight INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS number,
+    DROP COLUMN IF EXISTS repository;
+
+DROP TABLE IF EXISTS repositories;
@@ -0,0 +1,15 @@
+-- Репозитории PR: номер PR уникален только внутри репозитория, ревьюеры назначаются из команды-владельца.
+-- Существующие PR попадают в репозиторий default без владельца — ревьюеров, как и раньше, даёт команда автора.
+CREATE TABLE IF NOT EXISTS repositories (
+    repository_name    TEXT PRIMARY KEY,
+    owner_team         TEXT NULL REFERENCES teams(team_name) ON DELETE SET NULL,
+    required_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (required_reviewers BETWEEN 1 AND 2)
+);
+
+INSERT INTO repositories(repository_name) VALUES ('default') ON CONFLICT DO NOTHING;
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS repository TEXT NOT NULL DEFAULT 'default' REFERENCES repositories(repository_name),
+    ADD COLUMN IF NOT EXISTS number     INTEGER NULL CHECK (number > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (repository, number);
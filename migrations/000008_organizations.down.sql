@@ -0,0 +1,87 @@
+-- Откат оставляет только данные организации default: без organization_id ключи других организаций пересеклись бы.
+DELETE FROM review_queue WHERE organization_id <> 'default';
+DELETE FROM user_review_capacity WHERE organization_id <> 'default';
+DELETE FROM user_working_hours WHERE organization_id <> 'default';
+DELETE FROM user_absences WHERE organization_id <> 'default';
+DELETE FROM review_rotations WHERE organization_id <> 'default';
+DELETE FROM pull_request_reviewers WHERE organization_id <> 'default';
+DELETE FROM pull_requests WHERE organization_id <> 'default';
+DELETE FROM repositories WHERE organization_id <> 'default';
+DELETE FROM users WHERE organization_id <> 'default';
+DELETE FROM teams WHERE organization_id <> 'default';
+
+DROP INDEX IF EXISTS review_queue_queued_at_idx;
+CREATE INDEX IF NOT EXISTS review_queue_queued_at_idx ON review_queue (queued_at);
+DROP INDEX IF EXISTS user_absences_user_idx;
+CREATE INDEX IF NOT EXISTS user_absences_user_idx ON user_absences (user_id, starts_at);
+DROP INDEX IF EXISTS review_rotations_pull_request_idx;
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (pull_request_id);
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+
+ALTER TABLE review_queue DROP CONSTRAINT IF EXISTS review_queue_pull_request_id_fkey;
+ALTER TABLE user_review_capacity DROP CONSTRAINT IF EXISTS user_review_capacity_user_id_fkey;
+ALTER TABLE user_working_hours DROP CONSTRAINT IF EXISTS user_working_hours_user_id_fkey;
+ALTER TABLE user_absences DROP CONSTRAINT IF EXISTS user_absences_user_id_fkey;
+ALTER TABLE review_rotations DROP CONSTRAINT IF EXISTS review_rotations_pull_request_id_fkey;
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT IF EXISTS pull_request_reviewers_pull_request_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_request_reviewers_user_id_fkey;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS pull_requests_author_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_requests_repository_fkey;
+ALTER TABLE repositories
+    DROP CONSTRAINT IF EXISTS repositories_owner_team_fkey,
+    DROP CONSTRAINT IF EXISTS repositories_organization_id_fkey;
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_team_name_fkey,
+    DROP CONSTRAINT IF EXISTS users_organization_id_fkey;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_organization_id_fkey;
+
+ALTER TABLE review_queue DROP CONSTRAINT review_queue_pkey, ADD PRIMARY KEY (pull_request_id);
+ALTER TABLE user_review_capacity DROP CONSTRAINT user_review_capacity_pkey, ADD PRIMARY KEY (user_id);
+ALTER TABLE user_working_hours DROP CONSTRAINT user_working_hours_pkey, ADD PRIMARY KEY (user_id);
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pkey, ADD PRIMARY KEY (pull_request_id, user_id);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (pull_request_id);
+ALTER TABLE repositories DROP CONSTRAINT repositories_pkey, ADD PRIMARY KEY (repository_name);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (user_id);
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (team_name);
+
+ALTER TABLE review_queue DROP COLUMN organization_id;
+ALTER TABLE user_review_capacity DROP COLUMN organization_id;
+ALTER TABLE user_working_hours DROP COLUMN organization_id;
+ALTER TABLE user_absences DROP COLUMN organization_id;
+ALTER TABLE review_rotations DROP COLUMN organization_id;
+ALTER TABLE pull_request_reviewers DROP COLUMN organization_id;
+ALTER TABLE pull_requests DROP COLUMN organization_id;
+ALTER TABLE repositories DROP COLUMN organization_id;
+ALTER TABLE users DROP COLUMN organization_id;
+ALTER TABLE teams DROP COLUMN organization_id;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE SET NULL;
+ALTER TABLE repositories
+    ADD CONSTRAINT repositories_owner_team_fkey FOREIGN KEY (owner_team) REFERENCES teams(team_name) ON DELETE SET NULL;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (author_id) REFERENCES users(user_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT pull_requests_repository_fkey FOREIGN KEY (repository) REFERENCES repositories(repository_name);
+ALTER TABLE pull_request_reviewers
+    ADD CONSTRAINT pull_request_reviewers_pull_request_id_fkey FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    ADD CONSTRAINT pull_request_reviewers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE RESTRICT;
+ALTER TABLE review_rotations
+    ADD CONSTRAINT review_rotations_pull_request_id_fkey FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE;
+ALTER TABLE user_absences
+    ADD CONSTRAINT user_absences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE user_working_hours
+    ADD CONSTRAINT user_working_hours_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE user_review_capacity
+    ADD CONSTRAINT user_review_capacity_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+ALTER TABLE review_queue
+    ADD CONSTRAINT review_queue_pull_request_id_fkey FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (repository, number);
+
+DROP TABLE IF EXISTS organization_tokens;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,112 @@
+-- Организации — арендаторы сервиса: у каждой свои команды, пользователи, PR и репозитории.
+-- Все данные до появления организаций принадлежат организации default.
+CREATE TABLE IF NOT EXISTS organizations (
+    organization_id TEXT PRIMARY KEY,
+    name            TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO organizations (organization_id, name) VALUES ('default', 'Default') ON CONFLICT DO NOTHING;
+
+-- Токены доступа организаций; хранится только SHA-256 токена.
+CREATE TABLE IF NOT EXISTS organization_tokens (
+    token_hash      TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Ключи становятся составными (organization_id, ...), поэтому сначала снимаем ссылки на старые ключи.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS pull_requests_author_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_requests_repository_fkey;
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT IF EXISTS pull_request_reviewers_pull_request_id_fkey,
+    DROP CONSTRAINT IF EXISTS pull_request_reviewers_user_id_fkey;
+ALTER TABLE review_rotations DROP CONSTRAINT IF EXISTS review_rotations_pull_request_id_fkey;
+ALTER TABLE user_absences DROP CONSTRAINT IF EXISTS user_absences_user_id_fkey;
+ALTER TABLE user_working_hours DROP CONSTRAINT IF EXISTS user_working_hours_user_id_fkey;
+ALTER TABLE user_review_capacity DROP CONSTRAINT IF EXISTS user_review_capacity_user_id_fkey;
+ALTER TABLE review_queue DROP CONSTRAINT IF EXISTS review_queue_pull_request_id_fkey;
+ALTER TABLE repositories DROP CONSTRAINT IF EXISTS repositories_owner_team_fkey;
+
+-- Существующие строки попадают в default; умолчание снимается, чтобы запись без организации была ошибкой.
+ALTER TABLE teams ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE users ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE repositories ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_requests ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_request_reviewers ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE review_rotations ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_absences ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_working_hours ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_review_capacity ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE review_queue ADD COLUMN organization_id TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE teams ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE repositories ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE pull_request_reviewers ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE review_rotations ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE user_absences ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE user_working_hours ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE user_review_capacity ALTER COLUMN organization_id DROP DEFAULT;
+ALTER TABLE review_queue ALTER COLUMN organization_id DROP DEFAULT;
+
+ALTER TABLE teams DROP CONSTRAINT teams_pkey, ADD PRIMARY KEY (organization_id, team_name);
+ALTER TABLE users DROP CONSTRAINT users_pkey, ADD PRIMARY KEY (organization_id, user_id);
+ALTER TABLE repositories DROP CONSTRAINT repositories_pkey, ADD PRIMARY KEY (organization_id, repository_name);
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey, ADD PRIMARY KEY (organization_id, pull_request_id);
+ALTER TABLE pull_request_reviewers DROP CONSTRAINT pull_request_reviewers_pkey,
+    ADD PRIMARY KEY (organization_id, pull_request_id, user_id);
+ALTER TABLE user_working_hours DROP CONSTRAINT user_working_hours_pkey, ADD PRIMARY KEY (organization_id, user_id);
+ALTER TABLE user_review_capacity DROP CONSTRAINT user_review_capacity_pkey, ADD PRIMARY KEY (organization_id, user_id);
+ALTER TABLE review_queue DROP CONSTRAINT review_queue_pkey, ADD PRIMARY KEY (organization_id, pull_request_id);
+
+ALTER TABLE teams
+    ADD CONSTRAINT teams_organization_id_fkey FOREIGN KEY (organization_id)
+        REFERENCES organizations(organization_id) ON DELETE CASCADE;
+ALTER TABLE users
+    ADD CONSTRAINT users_organization_id_fkey FOREIGN KEY (organization_id)
+        REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (organization_id, team_name)
+        REFERENCES teams(organization_id, team_name) ON DELETE SET NULL (team_name);
+ALTER TABLE repositories
+    ADD CONSTRAINT repositories_organization_id_fkey FOREIGN KEY (organization_id)
+        REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    ADD CONSTRAINT repositories_owner_team_fkey FOREIGN KEY (organization_id, owner_team)
+        REFERENCES teams(organization_id, team_name) ON DELETE SET NULL (owner_team);
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (organization_id, author_id)
+        REFERENCES users(organization_id, user_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT pull_requests_repository_fkey FOREIGN KEY (organization_id, repository)
+        REFERENCES repositories(organization_id, repository_name);
+ALTER TABLE pull_request_reviewers
+    ADD CONSTRAINT pull_request_reviewers_pull_request_id_fkey FOREIGN KEY (organization_id, pull_request_id)
+        REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE,
+    ADD CONSTRAINT pull_request_reviewers_user_id_fkey FOREIGN KEY (organization_id, user_id)
+        REFERENCES users(organization_id, user_id) ON DELETE RESTRICT;
+ALTER TABLE review_rotations
+    ADD CONSTRAINT review_rotations_pull_request_id_fkey FOREIGN KEY (organization_id, pull_request_id)
+        REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE;
+ALTER TABLE user_absences
+    ADD CONSTRAINT user_absences_user_id_fkey FOREIGN KEY (organization_id, user_id)
+        REFERENCES users(organization_id, user_id) ON DELETE CASCADE;
+ALTER TABLE user_working_hours
+    ADD CONSTRAINT user_working_hours_user_id_fkey FOREIGN KEY (organization_id, user_id)
+        REFERENCES users(organization_id, user_id) ON DELETE CASCADE;
+ALTER TABLE user_review_capacity
+    ADD CONSTRAINT user_review_capacity_user_id_fkey FOREIGN KEY (organization_id, user_id)
+        REFERENCES users(organization_id, user_id) ON DELETE CASCADE;
+ALTER TABLE review_queue
+    ADD CONSTRAINT review_queue_pull_request_id_fkey FOREIGN KEY (organization_id, pull_request_id)
+        REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (organization_id, repository, number);
+DROP INDEX IF EXISTS review_rotations_pull_request_idx;
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (organization_id, pull_request_id);
+DROP INDEX IF EXISTS user_absences_user_idx;
+CREATE INDEX IF NOT EXISTS user_absences_user_idx ON user_absences (organization_id, user_id, starts_at);
+DROP INDEX IF EXISTS review_queue_queued_at_idx;
+CREATE INDEX IF NOT EXISTS review_queue_queued_at_idx ON review_queue (organization_id, queued_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS affinity_rules;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_learners;
+
+DELETE FROM pull_request_reviewers WHERE role = 'SHADOW';
+ALTER TABLE pull_request_reviewers DROP COLUMN IF EXISTS role;
@@ -0,0 +1,14 @@
+-- Роль назначения: REVIEWER — основной ревьюер, SHADOW — наблюдатель-стажёр, который не входит
+-- в число ревьюеров PR и не учитывается в нагрузке. Существующие назначения — основные.
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'REVIEWER' CHECK (role IN ('REVIEWER', 'SHADOW'));
+
+-- Стажёры команды, из которых назначаются теневые ревьюеры.
+CREATE TABLE IF NOT EXISTS team_learners (
+    organization_id TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    user_id         TEXT NOT NULL,
+    PRIMARY KEY (organization_id, team_name, user_id),
+    FOREIGN KEY (organization_id, team_name) REFERENCES teams(organization_id, team_name) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,24 @@
+// Package migrations хранит SQL-миграции схемы и встраивает их в бинарник.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS содержит миграции PostgreSQL в формате NNNNNN_name.up.sql / NNNNNN_name.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite возвращает миграции SQLite в том же формате, что и FS.
+func SQLite() fs.FS {
+	sub, err := fs.Sub(sqliteFS, "sqlite")
+	if err != nil {
+		panic("sqlite migrations: " + err.Error())
+	}
+	return sub
+}
@@ -0,0 +1,29 @@
+-- Таблица команд
+CREATE TABLE IF NOT EXISTS teams (
+    team_name TEXT PRIMARY KEY
+);
+
+-- Таблица пользователей
+CREATE TABLE IF NOT EXISTS users (
+    user_id   TEXT PRIMARY KEY,
+    username  TEXT NOT NULL,
+    is_active INTEGER NOT NULL DEFAULT 1,
+    team_name TEXT REFERENCES teams(team_name) ON DELETE SET NULL
+);
+
+-- Таблица Pull Request'ов; время хранится строкой RFC 3339 в UTC
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id   TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    status            TEXT NOT NULL,
+    created_at        TEXT,
+    merged_at         TEXT
+);
+
+-- Назначенные ревьюеры (0–2 на PR)
+CREATE TABLE IF NOT EXISTS pull_request_reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    PRIMARY KEY (pull_request_id, user_id)
+);
@@ -0,0 +1,28 @@
+-- Последняя активность ревьюера по PR; ALTER TABLE в SQLite не допускает вычисляемый DEFAULT,
+-- поэтому время задаёт приложение, а для уже назначенных берём время создания PR
+ALTER TABLE pull_request_reviewers ADD COLUMN last_activity_at TEXT;
+
+UPDATE pull_request_reviewers
+SET last_activity_at = (
+    SELECT p.created_at FROM pull_requests p WHERE p.pull_request_id = pull_request_reviewers.pull_request_id
+);
+
+-- История автоматических замен неактивных ревьюеров
+CREATE TABLE IF NOT EXISTS review_rotations (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id     TEXT NOT NULL,
+    new_user_id     TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    idle_seconds    INTEGER NOT NULL,
+    rotated_at      TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (pull_request_id);
+
+-- Аренды фоновых задач: задачу выполняет только держатель неистёкшей аренды
+CREATE TABLE IF NOT EXISTS scheduler_leases (
+    name       TEXT PRIMARY KEY,
+    holder     TEXT NOT NULL,
+    expires_at TEXT NOT NULL
+);
@@ -0,0 +1,14 @@
+-- Периоды отсутствия пользователей; reassigned_at отмечает, что их ревью уже переданы коллегам
+CREATE TABLE IF NOT EXISTS user_absences (
+    absence_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at     TEXT NOT NULL,
+    ends_at       TEXT NOT NULL,
+    reason        TEXT NOT NULL DEFAULT '',
+    created_at    TEXT NOT NULL,
+    reassigned_at TEXT,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS user_absences_user_idx ON user_absences (user_id, starts_at);
+CREATE INDEX IF NOT EXISTS user_absences_period_idx ON user_absences (starts_at, ends_at);
@@ -0,0 +1,14 @@
+-- Личный лимит открытых ревью; без записи действует лимит по умолчанию из конфигурации
+CREATE TABLE IF NOT EXISTS user_review_capacity (
+    user_id          TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    max_open_reviews INTEGER NOT NULL CHECK (max_open_reviews > 0)
+);
+
+-- PR, которым при создании не хватило ревьюеров из-за лимитов; разбираются по мере освобождения ревьюеров
+CREATE TABLE IF NOT EXISTS review_queue (
+    pull_request_id TEXT PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    missing         INTEGER NOT NULL CHECK (missing > 0),
+    queued_at       TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS review_queue_queued_at_idx ON review_queue (queued_at);
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests DROP COLUMN review_weight;
+ALTER TABLE pull_requests DROP COLUMN labels;
+ALTER TABLE pull_requests DROP COLUMN priority;
+ALTER TABLE pull_requests DROP COLUMN files_changed;
+ALTER TABLE pull_requests DROP COLUMN lines_deleted;
+ALTER TABLE pull_requests DROP COLUMN lines_added;
@@ -0,0 +1,8 @@
+-- Размер, срочность и метки PR; labels — JSON-массив строк. review_weight — вес ревью, рассчитанный
+-- приложением по размеру, хранится, чтобы нагрузку и статистику можно было суммировать в SQL.
+ALTER TABLE pull_requests ADD COLUMN lines_added INTEGER NOT NULL DEFAULT 0 CHECK (lines_added >= 0);
+ALTER TABLE pull_requests ADD COLUMN lines_deleted INTEGER NOT NULL DEFAULT 0 CHECK (lines_deleted >= 0);
+ALTER TABLE pull_requests ADD COLUMN files_changed INTEGER NOT NULL DEFAULT 0 CHECK (files_changed >= 0);
+ALTER TABLE pull_requests ADD COLUMN priority TEXT NOT NULL DEFAULT 'NORMAL';
+ALTER TABLE pull_requests ADD COLUMN labels TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE pull_requests ADD COLUMN review_weight INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS pull_requests_repository_update;
+DROP TRIGGER IF EXISTS pull_requests_repository_insert;
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+
+ALTER TABLE pull_requests DROP COLUMN number;
+ALTER TABLE pull_requests DROP COLUMN repository;
+
+DROP TABLE IF EXISTS repositories;
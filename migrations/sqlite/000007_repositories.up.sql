@@ -0,0 +1,30 @@
+-- Репозитории PR: номер PR уникален только внутри репозитория, ревьюеры назначаются из команды-владельца.
+-- Существующие PR попадают в репозиторий default без владельца — ревьюеров, как и раньше, даёт команда автора.
+-- SQLite не добавляет через ALTER TABLE столбец со ссылкой и непустым умолчанием, поэтому
+-- внешний ключ pull_requests.repository заменяют триггеры.
+CREATE TABLE IF NOT EXISTS repositories (
+    repository_name    TEXT PRIMARY KEY,
+    owner_team         TEXT NULL REFERENCES teams(team_name) ON DELETE SET NULL,
+    required_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (required_reviewers BETWEEN 1 AND 2)
+);
+
+INSERT OR IGNORE INTO repositories(repository_name) VALUES ('default');
+
+ALTER TABLE pull_requests ADD COLUMN repository TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE pull_requests ADD COLUMN number INTEGER NULL CHECK (number > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (repository, number);
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_repository_insert
+BEFORE INSERT ON pull_requests
+WHEN NOT EXISTS (SELECT 1 FROM repositories WHERE repository_name = NEW.repository)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: repository does not exist');
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_repository_update
+BEFORE UPDATE OF repository ON pull_requests
+WHEN NOT EXISTS (SELECT 1 FROM repositories WHERE repository_name = NEW.repository)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: repository does not exist');
+END;
@@ -0,0 +1,167 @@
+-- Откат оставляет только данные организации default: без organization_id ключи других организаций пересеклись бы.
+DROP INDEX IF EXISTS review_queue_queued_at_idx;
+DROP INDEX IF EXISTS user_absences_period_idx;
+DROP INDEX IF EXISTS user_absences_user_idx;
+DROP INDEX IF EXISTS review_rotations_pull_request_idx;
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+
+ALTER TABLE review_queue RENAME TO review_queue_old;
+ALTER TABLE user_review_capacity RENAME TO user_review_capacity_old;
+ALTER TABLE user_working_hours RENAME TO user_working_hours_old;
+ALTER TABLE user_absences RENAME TO user_absences_old;
+ALTER TABLE review_rotations RENAME TO review_rotations_old;
+ALTER TABLE pull_request_reviewers RENAME TO pull_request_reviewers_old;
+ALTER TABLE pull_requests RENAME TO pull_requests_old;
+ALTER TABLE repositories RENAME TO repositories_old;
+ALTER TABLE users RENAME TO users_old;
+ALTER TABLE teams RENAME TO teams_old;
+
+CREATE TABLE teams (
+    team_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE users (
+    user_id   TEXT PRIMARY KEY,
+    username  TEXT NOT NULL,
+    is_active INTEGER NOT NULL DEFAULT 1,
+    team_name TEXT REFERENCES teams(team_name) ON DELETE SET NULL
+);
+
+CREATE TABLE repositories (
+    repository_name    TEXT PRIMARY KEY,
+    owner_team         TEXT NULL REFERENCES teams(team_name) ON DELETE SET NULL,
+    required_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (required_reviewers BETWEEN 1 AND 2)
+);
+
+CREATE TABLE pull_requests (
+    pull_request_id   TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    status            TEXT NOT NULL,
+    created_at        TEXT,
+    merged_at         TEXT,
+    lines_added       INTEGER NOT NULL DEFAULT 0 CHECK (lines_added >= 0),
+    lines_deleted     INTEGER NOT NULL DEFAULT 0 CHECK (lines_deleted >= 0),
+    files_changed     INTEGER NOT NULL DEFAULT 0 CHECK (files_changed >= 0),
+    priority          TEXT NOT NULL DEFAULT 'NORMAL',
+    labels            TEXT NOT NULL DEFAULT '[]',
+    review_weight     INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0),
+    repository        TEXT NOT NULL DEFAULT 'default',
+    number            INTEGER NULL CHECK (number > 0)
+);
+
+CREATE TABLE pull_request_reviewers (
+    pull_request_id  TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id          TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    last_activity_at TEXT,
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+CREATE TABLE review_rotations (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id     TEXT NOT NULL,
+    new_user_id     TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    idle_seconds    INTEGER NOT NULL,
+    rotated_at      TEXT NOT NULL
+);
+
+CREATE TABLE user_absences (
+    absence_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at     TEXT NOT NULL,
+    ends_at       TEXT NOT NULL,
+    reason        TEXT NOT NULL DEFAULT '',
+    created_at    TEXT NOT NULL,
+    reassigned_at TEXT,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE user_working_hours (
+    user_id    TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    time_zone  TEXT NOT NULL,
+    work_start TEXT NOT NULL,
+    work_end   TEXT NOT NULL,
+    CHECK (work_end > work_start)
+);
+
+CREATE TABLE user_review_capacity (
+    user_id          TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    max_open_reviews INTEGER NOT NULL CHECK (max_open_reviews > 0)
+);
+
+CREATE TABLE review_queue (
+    pull_request_id TEXT PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    missing         INTEGER NOT NULL CHECK (missing > 0),
+    queued_at       TEXT NOT NULL
+);
+
+INSERT INTO teams (team_name)
+SELECT team_name FROM teams_old WHERE organization_id = 'default';
+
+INSERT INTO users (user_id, username, is_active, team_name)
+SELECT user_id, username, is_active, team_name FROM users_old WHERE organization_id = 'default';
+
+INSERT INTO repositories (repository_name, owner_team, required_reviewers)
+SELECT repository_name, owner_team, required_reviewers FROM repositories_old WHERE organization_id = 'default';
+
+INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number)
+SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number
+FROM pull_requests_old WHERE organization_id = 'default';
+
+INSERT INTO pull_request_reviewers (pull_request_id, user_id, last_activity_at)
+SELECT pull_request_id, user_id, last_activity_at FROM pull_request_reviewers_old WHERE organization_id = 'default';
+
+INSERT INTO review_rotations (id, pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at)
+SELECT id, pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at
+FROM review_rotations_old WHERE organization_id = 'default';
+
+INSERT INTO user_absences (absence_id, user_id, starts_at, ends_at, reason, created_at, reassigned_at)
+SELECT absence_id, user_id, starts_at, ends_at, reason, created_at, reassigned_at
+FROM user_absences_old WHERE organization_id = 'default';
+
+INSERT INTO user_working_hours (user_id, time_zone, work_start, work_end)
+SELECT user_id, time_zone, work_start, work_end FROM user_working_hours_old WHERE organization_id = 'default';
+
+INSERT INTO user_review_capacity (user_id, max_open_reviews)
+SELECT user_id, max_open_reviews FROM user_review_capacity_old WHERE organization_id = 'default';
+
+INSERT INTO review_queue (pull_request_id, missing, queued_at)
+SELECT pull_request_id, missing, queued_at FROM review_queue_old WHERE organization_id = 'default';
+
+DROP TABLE review_queue_old;
+DROP TABLE user_review_capacity_old;
+DROP TABLE user_working_hours_old;
+DROP TABLE user_absences_old;
+DROP TABLE review_rotations_old;
+DROP TABLE pull_request_reviewers_old;
+DROP TABLE pull_requests_old;
+DROP TABLE repositories_old;
+DROP TABLE users_old;
+DROP TABLE teams_old;
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (repository, number);
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (pull_request_id);
+CREATE INDEX IF NOT EXISTS user_absences_user_idx ON user_absences (user_id, starts_at);
+CREATE INDEX IF NOT EXISTS user_absences_period_idx ON user_absences (starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS review_queue_queued_at_idx ON review_queue (queued_at);
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_repository_insert
+BEFORE INSERT ON pull_requests
+WHEN NOT EXISTS (SELECT 1 FROM repositories WHERE repository_name = NEW.repository)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: repository does not exist');
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_repository_update
+BEFORE UPDATE OF repository ON pull_requests
+WHEN NOT EXISTS (SELECT 1 FROM repositories WHERE repository_name = NEW.repository)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: repository does not exist');
+END;
+
+DROP TABLE IF EXISTS organization_tokens;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,200 @@
+-- Организации — арендаторы сервиса: у каждой свои команды, пользователи, PR и репозитории.
+-- Все данные до появления организаций принадлежат организации default.
+-- Ключи становятся составными (organization_id, ...), а SQLite не меняет ключи через ALTER TABLE,
+-- поэтому таблицы пересоздаются: старые переименовываются в *_old, данные копируются, старые удаляются.
+-- Составной ключ нельзя обнулить частично (ON DELETE SET NULL обнулил бы и organization_id),
+-- поэтому ссылки на команды — обычные внешние ключи: команды не удаляются.
+-- pull_requests.repository теперь настоящий внешний ключ, триггеры из 000007 больше не нужны.
+CREATE TABLE IF NOT EXISTS organizations (
+    organization_id TEXT PRIMARY KEY,
+    name            TEXT NOT NULL,
+    created_at      TEXT NOT NULL
+);
+
+INSERT OR IGNORE INTO organizations (organization_id, name, created_at)
+VALUES ('default', 'Default', strftime('%Y-%m-%dT%H:%M:%f000000Z', 'now'));
+
+-- Токены доступа организаций; хранится только SHA-256 токена.
+CREATE TABLE IF NOT EXISTS organization_tokens (
+    token_hash      TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    created_at      TEXT NOT NULL
+);
+
+DROP TRIGGER IF EXISTS pull_requests_repository_update;
+DROP TRIGGER IF EXISTS pull_requests_repository_insert;
+DROP INDEX IF EXISTS pull_requests_repository_number_idx;
+DROP INDEX IF EXISTS review_rotations_pull_request_idx;
+DROP INDEX IF EXISTS user_absences_user_idx;
+DROP INDEX IF EXISTS user_absences_period_idx;
+DROP INDEX IF EXISTS review_queue_queued_at_idx;
+
+ALTER TABLE review_queue RENAME TO review_queue_old;
+ALTER TABLE user_review_capacity RENAME TO user_review_capacity_old;
+ALTER TABLE user_working_hours RENAME TO user_working_hours_old;
+ALTER TABLE user_absences RENAME TO user_absences_old;
+ALTER TABLE review_rotations RENAME TO review_rotations_old;
+ALTER TABLE pull_request_reviewers RENAME TO pull_request_reviewers_old;
+ALTER TABLE pull_requests RENAME TO pull_requests_old;
+ALTER TABLE repositories RENAME TO repositories_old;
+ALTER TABLE users RENAME TO users_old;
+ALTER TABLE teams RENAME TO teams_old;
+
+CREATE TABLE teams (
+    organization_id TEXT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    team_name       TEXT NOT NULL,
+    PRIMARY KEY (organization_id, team_name)
+);
+
+CREATE TABLE users (
+    organization_id TEXT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL,
+    username        TEXT NOT NULL,
+    is_active       INTEGER NOT NULL DEFAULT 1,
+    team_name       TEXT,
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id, team_name) REFERENCES teams(organization_id, team_name)
+);
+
+CREATE TABLE repositories (
+    organization_id    TEXT NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    repository_name    TEXT NOT NULL,
+    owner_team         TEXT NULL,
+    required_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (required_reviewers BETWEEN 1 AND 2),
+    PRIMARY KEY (organization_id, repository_name),
+    FOREIGN KEY (organization_id, owner_team) REFERENCES teams(organization_id, team_name)
+);
+
+CREATE TABLE pull_requests (
+    organization_id   TEXT NOT NULL,
+    pull_request_id   TEXT NOT NULL,
+    pull_request_name TEXT NOT NULL,
+    author_id         TEXT NOT NULL,
+    status            TEXT NOT NULL,
+    created_at        TEXT,
+    merged_at         TEXT,
+    lines_added       INTEGER NOT NULL DEFAULT 0 CHECK (lines_added >= 0),
+    lines_deleted     INTEGER NOT NULL DEFAULT 0 CHECK (lines_deleted >= 0),
+    files_changed     INTEGER NOT NULL DEFAULT 0 CHECK (files_changed >= 0),
+    priority          TEXT NOT NULL DEFAULT 'NORMAL',
+    labels            TEXT NOT NULL DEFAULT '[]',
+    review_weight     INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0),
+    repository        TEXT NOT NULL DEFAULT 'default',
+    number            INTEGER NULL CHECK (number > 0),
+    PRIMARY KEY (organization_id, pull_request_id),
+    FOREIGN KEY (organization_id, author_id) REFERENCES users(organization_id, user_id) ON DELETE RESTRICT,
+    FOREIGN KEY (organization_id, repository) REFERENCES repositories(organization_id, repository_name)
+);
+
+CREATE TABLE pull_request_reviewers (
+    organization_id  TEXT NOT NULL,
+    pull_request_id  TEXT NOT NULL,
+    user_id          TEXT NOT NULL,
+    last_activity_at TEXT,
+    PRIMARY KEY (organization_id, pull_request_id, user_id),
+    FOREIGN KEY (organization_id, pull_request_id) REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE RESTRICT
+);
+
+CREATE TABLE review_rotations (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    old_user_id     TEXT NOT NULL,
+    new_user_id     TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    idle_seconds    INTEGER NOT NULL,
+    rotated_at      TEXT NOT NULL,
+    FOREIGN KEY (organization_id, pull_request_id) REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_absences (
+    absence_id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id TEXT NOT NULL,
+    user_id         TEXT NOT NULL,
+    starts_at       TEXT NOT NULL,
+    ends_at         TEXT NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    created_at      TEXT NOT NULL,
+    reassigned_at   TEXT,
+    CHECK (ends_at > starts_at),
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_working_hours (
+    organization_id TEXT NOT NULL,
+    user_id         TEXT NOT NULL,
+    time_zone       TEXT NOT NULL,
+    work_start      TEXT NOT NULL,
+    work_end        TEXT NOT NULL,
+    CHECK (work_end > work_start),
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_review_capacity (
+    organization_id  TEXT NOT NULL,
+    user_id          TEXT NOT NULL,
+    max_open_reviews INTEGER NOT NULL CHECK (max_open_reviews > 0),
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE review_queue (
+    organization_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    missing         INTEGER NOT NULL CHECK (missing > 0),
+    queued_at       TEXT NOT NULL,
+    PRIMARY KEY (organization_id, pull_request_id),
+    FOREIGN KEY (organization_id, pull_request_id) REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE
+);
+
+INSERT INTO teams (organization_id, team_name)
+SELECT 'default', team_name FROM teams_old;
+
+INSERT INTO users (organization_id, user_id, username, is_active, team_name)
+SELECT 'default', user_id, username, is_active, team_name FROM users_old;
+
+INSERT INTO repositories (organization_id, repository_name, owner_team, required_reviewers)
+SELECT 'default', repository_name, owner_team, required_reviewers FROM repositories_old;
+
+INSERT INTO pull_requests (organization_id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number)
+SELECT 'default', pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+    lines_added, lines_deleted, files_changed, priority, labels, review_weight, repository, number
+FROM pull_requests_old;
+
+INSERT INTO pull_request_reviewers (organization_id, pull_request_id, user_id, last_activity_at)
+SELECT 'default', pull_request_id, user_id, last_activity_at FROM pull_request_reviewers_old;
+
+INSERT INTO review_rotations (id, organization_id, pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at)
+SELECT id, 'default', pull_request_id, old_user_id, new_user_id, team_name, idle_seconds, rotated_at FROM review_rotations_old;
+
+INSERT INTO user_absences (absence_id, organization_id, user_id, starts_at, ends_at, reason, created_at, reassigned_at)
+SELECT absence_id, 'default', user_id, starts_at, ends_at, reason, created_at, reassigned_at FROM user_absences_old;
+
+INSERT INTO user_working_hours (organization_id, user_id, time_zone, work_start, work_end)
+SELECT 'default', user_id, time_zone, work_start, work_end FROM user_working_hours_old;
+
+INSERT INTO user_review_capacity (organization_id, user_id, max_open_reviews)
+SELECT 'default', user_id, max_open_reviews FROM user_review_capacity_old;
+
+INSERT INTO review_queue (organization_id, pull_request_id, missing, queued_at)
+SELECT 'default', pull_request_id, missing, queued_at FROM review_queue_old;
+
+DROP TABLE review_queue_old;
+DROP TABLE user_review_capacity_old;
+DROP TABLE user_working_hours_old;
+DROP TABLE user_absences_old;
+DROP TABLE review_rotations_old;
+DROP TABLE pull_request_reviewers_old;
+DROP TABLE pull_requests_old;
+DROP TABLE repositories_old;
+DROP TABLE users_old;
+DROP TABLE teams_old;
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_requests_repository_number_idx ON pull_requests (organization_id, repository, number);
+CREATE INDEX IF NOT EXISTS review_rotations_pull_request_idx ON review_rotations (organization_id, pull_request_id);
+CREATE INDEX IF NOT EXISTS user_absences_user_idx ON user_absences (organization_id, user_id, starts_at);
+CREATE INDEX IF NOT EXISTS user_absences_period_idx ON user_absences (starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS review_queue_queued_at_idx ON review_queue (organization_id, queued_at);
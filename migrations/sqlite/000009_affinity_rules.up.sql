@@ -0,0 +1,16 @@
+-- Правила подбора ревьюеров команды: kind — NEVER_PAIR, ALWAYS_INCLUDE или REQUIRE_PAIR,
+-- with_user_ids — вторая сторона правила, JSON-массив строк.
+CREATE TABLE IF NOT EXISTS affinity_rules (
+    rule_id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    kind            TEXT NOT NULL CHECK (kind IN ('NEVER_PAIR', 'ALWAYS_INCLUDE', 'REQUIRE_PAIR')),
+    user_id         TEXT NOT NULL,
+    with_user_ids   TEXT NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    created_at      TEXT NOT NULL,
+    FOREIGN KEY (organization_id, team_name) REFERENCES teams(organization_id, team_name) ON DELETE CASCADE,
+    FOREIGN KEY (organization_id, user_id) REFERENCES users(organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS affinity_rules_team_idx ON affinity_rules (organization_id, team_name);
@@ -0,0 +1,20 @@
+-- Решения о назначении ревьюеров: кого рассматривали и почему выбраны именно эти ревьюеры.
+-- operation — CREATE, QUEUE, REASSIGN, DEACTIVATE или ABSENCE; selected, replaced и rules — JSON-массивы строк,
+-- candidates — JSON-массив кандидатов с причинами.
+CREATE TABLE IF NOT EXISTS assignment_decisions (
+    decision_id     INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    operation       TEXT NOT NULL CHECK (operation IN ('CREATE', 'QUEUE', 'REASSIGN', 'DEACTIVATE', 'ABSENCE')),
+    strategy        TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    selected        TEXT NOT NULL,
+    replaced        TEXT NOT NULL,
+    pending         INTEGER NOT NULL DEFAULT 0,
+    rules           TEXT NOT NULL,
+    candidates      TEXT NOT NULL,
+    decided_at      TEXT NOT NULL,
+    FOREIGN KEY (organization_id, pull_request_id) REFERENCES pull_requests(organization_id, pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS assignment_decisions_pull_request_idx ON assignment_decisions (organization_id, pull_request_id);
@@ -0,0 +1,783 @@
+// Package client — типизированный Go-клиент HTTP API сервиса PR Manager.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout — таймаут HTTP-клиента по умолчанию.
+const DefaultTimeout = 30 * time.Second
+
+// Client вызывает HTTP API сервиса.
+type Client struct {
+	baseURL *url.URL
+	token   string
+	http    *http.Client
+	retry   RetryPolicy
+}
+
+// Option настраивает клиента.
+type Option func(*Client)
+
+// WithToken передаёт токен в заголовке Authorization: Bearer.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient подменяет HTTP-клиент, например чтобы задать свой таймаут или транспорт.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// New создаёт клиента для сервиса по адресу baseURL, например http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base url must start with http:// or https://, got %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL: u,
+		http:    &http.Client{Timeout: DefaultTimeout},
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// BaseURL возвращает адрес сервиса.
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
+}
+
+// request описывает один вызов API.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        any
+	rawBody     io.Reader
+	contentType string
+	// accept — значение заголовка Accept; по умолчанию application/json.
+	accept string
+	// want — коды успешного ответа; тело ответа декодируется в out.
+	want []int
+	out  any
+}
+
+// do выполняет запрос и декодирует ответ; ответ с errorResponse превращается в *APIError.
+func (c *Client) do(ctx context.Context, r request) error {
+	resp, err := c.roundTrip(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, status := range r.want {
+		if resp.StatusCode != status {
+			continue
+		}
+		if r.out == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(r.out); err != nil {
+			return fmt.Errorf("%s %s: decode response: %w", r.method, r.path, err)
+		}
+		return nil
+	}
+	return decodeAPIError(resp)
+}
+
+// roundTrip отправляет запрос, повторяя идемпотентные операции после временных сбоев.
+func (c *Client) roundTrip(ctx context.Context, r request) (*http.Response, error) {
+	var body []byte
+	if r.body != nil {
+		data, err := json.Marshal(r.body)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: encode request: %w", r.method, r.path, err)
+		}
+		body = data
+	}
+
+	attempts := 1
+	if r.rawBody == nil && isIdempotent(r.method, r.path) && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, r, body)
+		last := attempt >= attempts
+		switch {
+		case err != nil && (last || isContextError(ctx, err)):
+			return nil, err
+		case err == nil && (last || !retryableStatus(resp.StatusCode)):
+			return resp, nil
+		}
+
+		wait := c.retry.delay(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", r.method, r.path, err)
+		}
+	}
+}
+
+// stream выполняет запрос и отдаёт тело успешного ответа без декодирования.
+func (c *Client) stream(ctx context.Context, r request) (io.ReadCloser, error) {
+	resp, err := c.roundTrip(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, decodeAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// send собирает и отправляет одну попытку HTTP-запроса; body — уже закодированное JSON-тело.
+func (c *Client) send(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += r.path
+	if len(r.query) > 0 {
+		u.RawQuery = r.query.Encode()
+	}
+
+	reader, contentType := r.rawBody, r.contentType
+	if body != nil {
+		reader, contentType = bytes.NewReader(body), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", r.method, r.path, err)
+	}
+	accept := r.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", r.method, r.path, err)
+	}
+	return resp, nil
+}
+
+// ---------- служебные ----------
+
+// Health проверяет, что сервис отвечает.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: pathHealth, want: []int{http.StatusOK}})
+}
+
+// ---------- команды ----------
+
+// AddTeam создаёт команду и создаёт или обновляет её участников.
+func (c *Client) AddTeam(ctx context.Context, team Team) (*Team, error) {
+	var resp struct {
+		Team *Team `json:"team"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: pathTeamAdd, body: team, want: []int{http.StatusCreated}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Team, nil
+}
+
+// GetTeam возвращает команду с участниками.
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var team Team
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathTeamGet,
+		query:  url.Values{"team_name": {teamName}},
+		want:   []int{http.StatusOK},
+		out:    &team,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// DeactivateTeamMembers деактивирует участников команды и переназначает их открытые ревью.
+func (c *Client) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string) (*TeamBulkDeactivateResult, error) {
+	var resp struct {
+		Result *TeamBulkDeactivateResult `json:"result"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathTeamDeactivateUsers,
+		body:   teamDeactivateRequest{TeamName: teamName, UserIDs: userIDs},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// AddTeamRule добавляет команде правило подбора ревьюверов.
+func (c *Client) AddTeamRule(ctx context.Context, req AddTeamRuleRequest) (*AffinityRule, error) {
+	var resp struct {
+		Rule *AffinityRule `json:"rule"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathTeamAddRule,
+		body:   req,
+		want:   []int{http.StatusCreated},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Rule, nil
+}
+
+// TeamRules возвращает правила подбора ревьюверов команды в порядке создания.
+func (c *Client) TeamRules(ctx context.Context, teamName string) ([]AffinityRule, error) {
+	var resp struct {
+		Rules []AffinityRule `json:"rules"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathTeamGetRules,
+		query:  url.Values{"team_name": {teamName}},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Rules, nil
+}
+
+// DeleteTeamRule удаляет правило подбора ревьюверов.
+func (c *Client) DeleteTeamRule(ctx context.Context, ruleID int64) error {
+	return c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathTeamDeleteRule,
+		body:   deleteRuleRequest{RuleId: ruleID},
+		want:   []int{http.StatusOK},
+	})
+}
+
+// SetTeamLearners заменяет список стажёров команды, из которых назначаются теневые ревьюверы.
+func (c *Client) SetTeamLearners(ctx context.Context, teamName string, userIDs []string) (*TeamLearners, error) {
+	var resp TeamLearners
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathTeamSetLearners,
+		body:   setLearnersRequest{TeamName: teamName, UserIds: userIDs},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TeamLearners возвращает стажёров команды с числом их открытых теневых ревью.
+func (c *Client) TeamLearners(ctx context.Context, teamName string) (*TeamLearners, error) {
+	var resp TeamLearners
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathTeamGetLearners,
+		query:  url.Values{"team_name": {teamName}},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ---------- пользователи ----------
+
+// SetUserActive меняет признак активности пользователя.
+func (c *Client) SetUserActive(ctx context.Context, userID string, active bool) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathUsersSetIsActive,
+		body:   setIsActiveRequest{UserId: userID, IsActive: active},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// GetUserReviews возвращает PR, где пользователь назначен ревьювером.
+func (c *Client) GetUserReviews(ctx context.Context, userID string) (*UserReviews, error) {
+	return c.userReviews(ctx, url.Values{"user_id": {userID}})
+}
+
+// GetUserReviewsInRepository возвращает PR репозитория, где пользователь назначен ревьювером.
+func (c *Client) GetUserReviewsInRepository(ctx context.Context, userID, repository string) (*UserReviews, error) {
+	return c.userReviews(ctx, url.Values{"user_id": {userID}, "repository": {repository}})
+}
+
+// userReviews запрашивает PR ревьювера с фильтрами query.
+func (c *Client) userReviews(ctx context.Context, query url.Values) (*UserReviews, error) {
+	var resp UserReviews
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathUsersGetReview,
+		query:  query,
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StreamUserReviews выгружает PR ревьювера построчно в формате CSV или NDJSON.
+// Вызывающий обязан закрыть возвращённый поток.
+func (c *Client) StreamUserReviews(ctx context.Context, userID string, format StreamFormat) (io.ReadCloser, error) {
+	return c.stream(ctx, request{
+		method: http.MethodGet,
+		path:   pathUsersGetReview,
+		query:  url.Values{"user_id": {userID}},
+		accept: string(format),
+	})
+}
+
+// AddAbsence регистрирует период отсутствия пользователя.
+// Если период уже идёт, его открытые ревью сразу передаются коллегам.
+func (c *Client) AddAbsence(ctx context.Context, req AddAbsenceRequest) (*Absence, error) {
+	var resp struct {
+		Absence *Absence `json:"absence"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathUsersAddAbsence,
+		body:   req,
+		want:   []int{http.StatusCreated},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Absence, nil
+}
+
+// UserAbsences возвращает периоды отсутствия пользователя в порядке начала.
+func (c *Client) UserAbsences(ctx context.Context, userID string) ([]Absence, error) {
+	var resp struct {
+		Absences []Absence `json:"absences"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathUsersGetAbsences,
+		query:  url.Values{"user_id": {userID}},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Absences, nil
+}
+
+// CancelAbsence отменяет период отсутствия и возвращает его.
+func (c *Client) CancelAbsence(ctx context.Context, absenceID int64) (*Absence, error) {
+	var resp struct {
+		Absence *Absence `json:"absence"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathUsersCancelAbsence,
+		body:   cancelAbsenceRequest{AbsenceId: absenceID},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Absence, nil
+}
+
+// SetWorkingHours задаёт часовой пояс и рабочее время пользователя; повторный вызов заменяет их.
+func (c *Client) SetWorkingHours(ctx context.Context, hours WorkingHours) (*WorkingHours, error) {
+	return c.workingHours(ctx, request{method: http.MethodPost, path: pathUsersSetWorkingHours, body: hours})
+}
+
+// GetWorkingHours возвращает рабочее время пользователя; CodeNotFound, если оно не задано.
+func (c *Client) GetWorkingHours(ctx context.Context, userID string) (*WorkingHours, error) {
+	return c.workingHours(ctx, request{method: http.MethodGet, path: pathUsersGetWorkingHours, query: url.Values{"user_id": {userID}}})
+}
+
+// ClearWorkingHours удаляет рабочее время пользователя и возвращает его.
+func (c *Client) ClearWorkingHours(ctx context.Context, userID string) (*WorkingHours, error) {
+	return c.workingHours(ctx, request{method: http.MethodPost, path: pathUsersClearWorkingHours, body: userRequest{UserId: userID}})
+}
+
+// workingHours выполняет операцию, отвечающую объектом working_hours.
+func (c *Client) workingHours(ctx context.Context, req request) (*WorkingHours, error) {
+	var resp struct {
+		WorkingHours *WorkingHours `json:"working_hours"`
+	}
+	req.want = []int{http.StatusOK}
+	req.out = &resp
+	if err := c.do(ctx, req); err != nil {
+		return nil, err
+	}
+	return resp.WorkingHours, nil
+}
+
+// SetCapacity задаёт лимит открытых ревью пользователя; 0 сбрасывает его к лимиту по умолчанию.
+func (c *Client) SetCapacity(ctx context.Context, userID string, maxOpenReviews int) (*ReviewerLoad, error) {
+	return c.reviewerLoad(ctx, request{
+		method: http.MethodPost,
+		path:   pathUsersSetCapacity,
+		body:   setCapacityRequest{UserId: userID, MaxOpenReviews: maxOpenReviews},
+	})
+}
+
+// GetReviewerLoad возвращает число открытых ревью пользователя и его лимит.
+func (c *Client) GetReviewerLoad(ctx context.Context, userID string) (*ReviewerLoad, error) {
+	return c.reviewerLoad(ctx, request{method: http.MethodGet, path: pathUsersGetLoad, query: url.Values{"user_id": {userID}}})
+}
+
+// reviewerLoad выполняет операцию, отвечающую объектом load.
+func (c *Client) reviewerLoad(ctx context.Context, req request) (*ReviewerLoad, error) {
+	var resp struct {
+		Load *ReviewerLoad `json:"load"`
+	}
+	req.want = []int{http.StatusOK}
+	req.out = &resp
+	if err := c.do(ctx, req); err != nil {
+		return nil, err
+	}
+	return resp.Load, nil
+}
+
+// ---------- репозитории ----------
+
+// SetRepository создаёт репозиторий или меняет его команду-владельца и число ревьюверов;
+// RequiredReviewers = 0 означает значение по умолчанию.
+func (c *Client) SetRepository(ctx context.Context, repo Repository) (*Repository, error) {
+	return c.repository(ctx, request{method: http.MethodPost, path: pathRepositoriesSet, body: repo})
+}
+
+// GetRepository возвращает репозиторий и его правила ревью.
+func (c *Client) GetRepository(ctx context.Context, name string) (*Repository, error) {
+	return c.repository(ctx, request{method: http.MethodGet, path: pathRepositoriesGet, query: url.Values{"repository_name": {name}}})
+}
+
+// ListRepositories возвращает все репозитории по имени.
+func (c *Client) ListRepositories(ctx context.Context) ([]Repository, error) {
+	var resp struct {
+		Repositories []Repository `json:"repositories"`
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: pathRepositoriesList, want: []int{http.StatusOK}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Repositories, nil
+}
+
+// repository выполняет операцию, отвечающую объектом repository.
+func (c *Client) repository(ctx context.Context, req request) (*Repository, error) {
+	var resp struct {
+		Repository *Repository `json:"repository"`
+	}
+	req.want = []int{http.StatusOK}
+	req.out = &resp
+	if err := c.do(ctx, req); err != nil {
+		return nil, err
+	}
+	return resp.Repository, nil
+}
+
+// ---------- pull requests ----------
+
+// CreatePullRequest создаёт PR и назначает ревьюверов по правилам его репозитория с учётом их лимитов.
+func (c *Client) CreatePullRequest(ctx context.Context, req CreatePullRequestRequest) (*CreatePullRequestResult, error) {
+	var resp CreatePullRequestResult
+	err := c.do(ctx, request{method: http.MethodPost, path: pathPullRequestCreate, body: req, want: []int{http.StatusCreated}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MergePullRequest помечает PR слитым; повторный вызов возвращает тот же PR.
+func (c *Client) MergePullRequest(ctx context.Context, prID string) (*PullRequest, error) {
+	var resp struct {
+		PR *PullRequest `json:"pr"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathPullRequestMerge,
+		body:   mergeRequest{PullRequestId: prID},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.PR, nil
+}
+
+// ReassignReviewer заменяет ревьювера oldUserID другим участником его команды.
+func (c *Client) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*ReassignResult, error) {
+	var resp ReassignResult
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathPullRequestReassign,
+		body:   reassignRequest{PullRequestId: prID, OldUserId: oldUserID},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RecordReviewActivity отмечает активность ревьювера по открытому PR и сбрасывает его таймер SLA.
+func (c *Client) RecordReviewActivity(ctx context.Context, prID, userID string) (*ReviewActivity, error) {
+	var resp struct {
+		Activity *ReviewActivity `json:"activity"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathPullRequestActivity,
+		body:   activityRequest{PullRequestId: prID, UserId: userID},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Activity, nil
+}
+
+// ExplainPullRequest возвращает решения о назначении ревьюверов PR: кого рассматривали и почему выбраны именно они.
+func (c *Client) ExplainPullRequest(ctx context.Context, prID string) (*PullRequestExplanation, error) {
+	var resp PullRequestExplanation
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathPullRequestExplain,
+		query:  url.Values{"pull_request_id": {prID}},
+		want:   []int{http.StatusOK},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReviewRotations возвращает историю автоматических замен неактивных ревьюверов, новые записи первыми.
+func (c *Client) ReviewRotations(ctx context.Context, filter ReviewRotationFilter) ([]ReviewRotation, error) {
+	query := url.Values{}
+	setQuery(query, "pull_request_id", filter.PullRequestId)
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var resp struct {
+		Rotations []ReviewRotation `json:"rotations"`
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: pathPullRequestRotations, query: query, want: []int{http.StatusOK}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Rotations, nil
+}
+
+// ---------- статистика ----------
+
+// AssignmentStats возвращает статистику назначений с учётом фильтра.
+func (c *Client) AssignmentStats(ctx context.Context, filter AssignmentStatsFilter) (*AssignmentStats, error) {
+	var stats AssignmentStats
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   pathStatsAssignments,
+		query:  assignmentStatsQuery(filter),
+		want:   []int{http.StatusOK},
+		out:    &stats,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// StreamAssignmentStats выгружает один срез статистики назначений построчно в формате CSV или NDJSON.
+// Вызывающий обязан закрыть возвращённый поток.
+func (c *Client) StreamAssignmentStats(ctx context.Context, filter AssignmentStatsFilter, by StatsSlice, format StreamFormat) (io.ReadCloser, error) {
+	query := assignmentStatsQuery(filter)
+	setQuery(query, "by", string(by))
+	return c.stream(ctx, request{method: http.MethodGet, path: pathStatsAssignments, query: query, accept: string(format)})
+}
+
+// TurnaroundStats возвращает перцентили времени до слияния за окно [From, To).
+func (c *Client) TurnaroundStats(ctx context.Context, filter TurnaroundFilter) (*TurnaroundStats, error) {
+	query := url.Values{}
+	setQuery(query, "repository", filter.Repository)
+	setTimeQuery(query, "from", filter.From)
+	setTimeQuery(query, "to", filter.To)
+
+	var stats TurnaroundStats
+	err := c.do(ctx, request{method: http.MethodGet, path: pathStatsTurnaround, query: query, want: []int{http.StatusOK}, out: &stats})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ---------- администрирование ----------
+
+// ImportUsers загружает пользователей и команды из CSV или JSON (format — ImportFormatCSV или ImportFormatJSON).
+// Отчёт возвращается и тогда, когда импорт отклонён из-за ошибочных строк: проверяйте Failed и Applied.
+func (c *Client) ImportUsers(ctx context.Context, file io.Reader, format string, opts ImportOptions) (*ImportReport, error) {
+	contentType := "application/json"
+	if format == ImportFormatCSV {
+		contentType = "text/csv"
+	}
+	query := url.Values{}
+	setQuery(query, "on_conflict", string(opts.OnConflict))
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+
+	var resp struct {
+		Report *ImportReport `json:"report"`
+	}
+	err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        pathAdminImport,
+		query:       query,
+		rawBody:     file,
+		contentType: contentType,
+		want:        []int{http.StatusOK, http.StatusUnprocessableEntity},
+		out:         &resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Report, nil
+}
+
+// ExportSnapshot выгружает архив всего состояния сервиса.
+func (c *Client) ExportSnapshot(ctx context.Context) (*Snapshot, error) {
+	var snap Snapshot
+	err := c.do(ctx, request{method: http.MethodGet, path: pathAdminExport, want: []int{http.StatusOK}, out: &snap})
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// RestoreSnapshot загружает архив в пустую базу сервиса.
+func (c *Client) RestoreSnapshot(ctx context.Context, snap *Snapshot) (*SnapshotCounts, error) {
+	var resp struct {
+		Restored SnapshotCounts `json:"restored"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: pathAdminImportSnapshot, body: snap, want: []int{http.StatusCreated}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Restored, nil
+}
+
+// CreateOrganization создаёт организацию и возвращает её токен доступа; нужен токен администратора.
+// Токен показывается один раз — сохраните его сразу.
+func (c *Client) CreateOrganization(ctx context.Context, id, name string) (*Organization, string, error) {
+	var resp struct {
+		Organization *Organization `json:"organization"`
+		Token        string        `json:"token"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   pathAdminOrganizationsCreate,
+		body:   createOrganizationRequest{OrganizationId: id, Name: name},
+		want:   []int{http.StatusCreated},
+		out:    &resp,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Organization, resp.Token, nil
+}
+
+// ListOrganizations возвращает все организации по идентификатору; нужен токен администратора.
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	var resp struct {
+		Organizations []Organization `json:"organizations"`
+	}
+	err := c.do(ctx, request{method: http.MethodGet, path: pathAdminOrganizationsList, want: []int{http.StatusOK}, out: &resp})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Organizations, nil
+}
+
+func assignmentStatsQuery(filter AssignmentStatsFilter) url.Values {
+	query := url.Values{}
+	setQuery(query, "team", filter.TeamName)
+	setQuery(query, "repository", filter.Repository)
+	setQuery(query, "status", string(filter.Status))
+	setTimeQuery(query, "from", filter.From)
+	setTimeQuery(query, "to", filter.To)
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	return query
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setTimeQuery(query url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		query.Set(key, t.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,441 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRejectsInvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080")
+	require.Error(t, err)
+
+	c, err := New("http://localhost:8080/api/")
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8080/api", c.BaseURL())
+}
+
+func TestClientSendsTokenAndBody(t *testing.T) {
+	var gotAuth, gotPath, gotContentType string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"pr":{"pull_request_id":"pr-1","status":"OPEN"},"replaced_by":"u3"}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL+"/api", WithToken("secret"))
+	require.NoError(t, err)
+
+	res, err := c.ReassignReviewer(context.Background(), "pr-1", "u2")
+	require.NoError(t, err)
+	require.Equal(t, "pr-1", res.PR.PullRequestId)
+	require.Equal(t, StatusOpen, res.PR.Status)
+	require.Equal(t, "u3", res.ReplacedBy)
+
+	require.Equal(t, "Bearer secret", gotAuth)
+	require.Equal(t, "/api/pullRequest/reassign", gotPath)
+	require.Equal(t, "application/json", gotContentType)
+	require.Equal(t, map[string]any{"pull_request_id": "pr-1", "old_user_id": "u2"}, gotBody)
+}
+
+func TestClientAssignmentStatsQuery(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		_, _ = io.WriteString(w, `{"by_user":[],"by_pull_request":[],"by_team":[]}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.AssignmentStats(context.Background(), AssignmentStatsFilter{
+		TeamName:   "backend",
+		Repository: "search-api",
+		Status:     StatusMerged,
+		From:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "from=2025-01-01T00%3A00%3A00Z&limit=5&repository=search-api&status=MERGED&team=backend", gotQuery)
+}
+
+func TestClientReviewRotations(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		_, _ = io.WriteString(w, `{"rotations":[{"pull_request_id":"pr-1","old_user_id":"u2","new_user_id":"u3",`+
+			`"team_name":"backend","idle_seconds":180000,"rotated_at":"2025-03-10T12:00:00Z"}]}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	rotations, err := c.ReviewRotations(context.Background(), ReviewRotationFilter{PullRequestId: "pr-1", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, "/pullRequest/rotations", gotPath)
+	require.Equal(t, "limit=10&pull_request_id=pr-1", gotQuery)
+	require.Len(t, rotations, 1)
+	require.Equal(t, "u3", rotations[0].NewUserId)
+	require.Equal(t, int64(180000), rotations[0].IdleSeconds)
+}
+
+func TestClientExplainPullRequest(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		_, _ = io.WriteString(w, `{"pull_request_id":"pr-1","decisions":[{"decision_id":1,"pull_request_id":"pr-1",`+
+			`"operation":"CREATE","strategy":"BALANCED","team_name":"backend","selected":["u2"],"pending":0,`+
+			`"candidates":[{"user_id":"u2","outcome":"SELECTED","load":{"open_reviews":1,"weighted_load":2,"max_open_reviews":0,"off_hours":false}},`+
+			`{"user_id":"u3","outcome":"EXCLUDED","reason":"is absent"}],"decided_at":"2025-03-10T12:00:00Z"}]}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	explanation, err := c.ExplainPullRequest(context.Background(), "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, "/pullRequest/explain", gotPath)
+	require.Equal(t, "pull_request_id=pr-1", gotQuery)
+	require.Len(t, explanation.Decisions, 1)
+	decision := explanation.Decisions[0]
+	require.Equal(t, []string{"u2"}, decision.Selected)
+	require.Equal(t, []DecisionCandidate{
+		{UserId: "u2", Outcome: OutcomeSelected, Load: &CandidateLoad{OpenReviews: 1, WeightedLoad: 2}},
+		{UserId: "u3", Outcome: OutcomeExcluded, Reason: "is absent"},
+	}, decision.Candidates)
+}
+
+func TestClientAddAbsence(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users/addAbsence", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"absence":{"absence_id":7,"user_id":"u2","starts_at":"2025-03-10T00:00:00Z",`+
+			`"ends_at":"2025-03-17T00:00:00Z","reason":"vacation","created_at":"2025-03-01T12:00:00Z"}}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	starts := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	absence, err := c.AddAbsence(context.Background(), AddAbsenceRequest{
+		UserId: "u2", StartsAt: starts, EndsAt: starts.AddDate(0, 0, 7), Reason: "vacation",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2025-03-10T00:00:00Z", got["starts_at"])
+	require.Equal(t, int64(7), absence.AbsenceId)
+	require.Nil(t, absence.ReassignedAt)
+}
+
+func TestClientCreatePullRequestReportsLoad(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/pullRequest/create", r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"pr":{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1",`+
+			`"status":"OPEN","assigned_reviewers":["u2"]},"reviewer_load":[{"user_id":"u2","open_reviews":3,"weighted_load":5,"max_open_reviews":3}],`+
+			`"pending_reviewers":1}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	res, err := c.CreatePullRequest(context.Background(), CreatePullRequestRequest{PullRequestId: "pr-1", AuthorId: "u1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2"}, res.PR.AssignedReviewers)
+	require.Equal(t, []ReviewerLoad{{UserId: "u2", OpenReviews: 3, WeightedLoad: 5, MaxOpenReviews: 3}}, res.ReviewerLoad)
+	require.Equal(t, 1, res.PendingReviewers)
+	require.True(t, res.ReviewerLoad[0].AtCapacity())
+}
+
+func TestClientRepositories(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		_, _ = io.WriteString(w, `{"repository":{"repository_name":"search-api","owner_team":"backend","required_reviewers":1}}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	repo, err := c.GetRepository(context.Background(), "search-api")
+	require.NoError(t, err)
+	require.Equal(t, "/repositories/get", gotPath)
+	require.Equal(t, "repository_name=search-api", gotQuery)
+	require.Equal(t, Repository{RepositoryName: "search-api", OwnerTeam: "backend", RequiredReviewers: 1}, *repo)
+}
+
+func TestClientTeamRules(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"rule":{"rule_id":3,"team_name":"backend","kind":"ALWAYS_INCLUDE","user_id":"u1","with_user_ids":["u3"],"description":"","created_at":"2025-10-24T12:34:56Z"}}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	rule, err := c.AddTeamRule(context.Background(), AddTeamRuleRequest{
+		TeamName: "backend", Kind: RuleAlwaysInclude, UserId: "u1", WithUserIds: []string{"u3"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/team/addRule", gotPath)
+	require.JSONEq(t, `{"team_name":"backend","kind":"ALWAYS_INCLUDE","user_id":"u1","with_user_ids":["u3"],"description":""}`, gotBody)
+	require.Equal(t, int64(3), rule.RuleId)
+	require.Equal(t, []string{"u3"}, rule.WithUserIds)
+}
+
+func TestClientTeamLearners(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		_, _ = io.WriteString(w, `{"team_name":"backend","learners":[{"user_id":"u4","open_shadow_reviews":2}]}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	learners, err := c.SetTeamLearners(context.Background(), "backend", []string{"u4"})
+	require.NoError(t, err)
+	require.Equal(t, "/team/setLearners", gotPath)
+	require.JSONEq(t, `{"team_name":"backend","user_ids":["u4"]}`, gotBody)
+	require.Equal(t, TeamLearners{TeamName: "backend", Learners: []Learner{{UserId: "u4", OpenShadowReviews: 2}}}, *learners)
+}
+
+func TestClientDecodesAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantCode    string
+		wantMessage string
+		wantDetails []string
+	}{
+		{
+			name:        "error response",
+			status:      http.StatusNotFound,
+			body:        `{"error":{"code":"NOT_FOUND","message":"NOT_FOUND: team not found"}}`,
+			wantCode:    CodeNotFound,
+			wantMessage: "NOT_FOUND: team not found",
+		},
+		{
+			name:        "validation details",
+			status:      http.StatusBadRequest,
+			body:        `{"error":{"code":"INVALID_PAYLOAD","message":"request does not match the API specification","details":["/team_name: value must be a string"]}}`,
+			wantCode:    CodeInvalidPayload,
+			wantMessage: "request does not match the API specification",
+			wantDetails: []string{"/team_name: value must be a string"},
+		},
+		{
+			name:        "plain text",
+			status:      http.StatusBadGateway,
+			body:        "upstream unavailable\n",
+			wantMessage: "upstream unavailable",
+		},
+		{
+			name:        "empty body",
+			status:      http.StatusServiceUnavailable,
+			wantMessage: http.StatusText(http.StatusServiceUnavailable),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			c, err := New(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 1}))
+			require.NoError(t, err)
+
+			_, err = c.GetTeam(context.Background(), "backend")
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tt.status, apiErr.StatusCode)
+			require.Equal(t, tt.wantCode, apiErr.Code)
+			require.Equal(t, tt.wantMessage, apiErr.Message)
+			require.Equal(t, tt.wantDetails, apiErr.Details)
+		})
+	}
+}
+
+func TestAPIErrorMatchesSentinel(t *testing.T) {
+	err := error(&APIError{StatusCode: http.StatusConflict, Code: CodePRMerged, Message: "merged"})
+	require.ErrorIs(t, err, ErrPRMerged)
+	require.NotErrorIs(t, err, ErrNotFound)
+
+	unknown := error(&APIError{StatusCode: http.StatusBadGateway, Message: "bad gateway"})
+	require.NotErrorIs(t, unknown, ErrInternal)
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	t.Run("get succeeds after temporary failures", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = io.WriteString(w, `{"team_name":"backend","members":[]}`)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithRetry(policy))
+		require.NoError(t, err)
+
+		team, err := c.GetTeam(context.Background(), "backend")
+		require.NoError(t, err)
+		require.Equal(t, "backend", team.TeamName)
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("merge resends the body", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "pr-1", body["pull_request_id"])
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = io.WriteString(w, `{"pr":{"pull_request_id":"pr-1","status":"MERGED"}}`)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithRetry(policy))
+		require.NoError(t, err)
+
+		pr, err := c.MergePullRequest(context.Background(), "pr-1")
+		require.NoError(t, err)
+		require.Equal(t, StatusMerged, pr.Status)
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("create is not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithRetry(policy))
+		require.NoError(t, err)
+
+		_, err = c.CreatePullRequest(context.Background(), CreatePullRequestRequest{PullRequestId: "pr-1"})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":{"code":"NOT_FOUND","message":"user not found"}}`)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithRetry(policy))
+		require.NoError(t, err)
+
+		_, err = c.SetUserActive(context.Background(), "u1", false)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("canceled context stops retries", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = c.GetTeam(ctx, "backend")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestClientStreamUserReviews(t *testing.T) {
+	var gotAccept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = io.WriteString(w, "pull_request_id,pull_request_name,author_id,status\npr-1,Fix,u1,OPEN\n")
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	body, err := c.StreamUserReviews(context.Background(), "u2", StreamCSV)
+	require.NoError(t, err)
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, "text/csv", gotAccept)
+	require.Contains(t, string(data), "pr-1,Fix,u1,OPEN")
+}
+
+func TestClientImportUsersReturnsRejectedReport(t *testing.T) {
+	var gotContentType, gotQuery, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotQuery = r.URL.RawQuery
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = io.WriteString(w, `{"report":{"dry_run":true,"applied":false,"failed":1,"rows":[{"row":2,"user_id":"","action":"error","error":"user_id is required"}]}}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	csv := "user_id,username,team_name\n,Alice,backend\n"
+	report, err := c.ImportUsers(context.Background(), strings.NewReader(csv), ImportFormatCSV, ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Failed)
+	require.False(t, report.Applied)
+	require.Len(t, report.Rows, 1)
+
+	require.Equal(t, "text/csv", gotContentType)
+	require.Equal(t, "dry_run=true", gotQuery)
+	require.Equal(t, csv, gotBody)
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyBytes ограничивает чтение тела ответа с ошибкой.
+const maxErrorBodyBytes = 64 << 10
+
+// Коды ошибок API (поле error.code).
+const (
+	CodeTeamExists     = "TEAM_EXISTS"
+	CodePRExists       = "PR_EXISTS"
+	CodePRMerged       = "PR_MERGED"
+	CodeNotAssigned    = "NOT_ASSIGNED"
+	CodeNoCandidate    = "NO_CANDIDATE"
+	CodeNotFound       = "NOT_FOUND"
+	CodeNotEmpty       = "NOT_EMPTY"
+	CodeOrgExists      = "ORG_EXISTS"
+	CodeInvalidParam   = "INVALID_PARAM"
+	CodeInvalidPayload = "INVALID_PAYLOAD"
+	CodeMissingParam   = "MISSING_PARAM"
+	CodeUnauthorized   = "UNAUTHORIZED"
+	CodeForbidden      = "FORBIDDEN"
+	CodeInternalError  = "INTERNAL_ERROR"
+)
+
+// Ошибки по кодам API: errors.Is(err, client.ErrNotFound) верно для *APIError с кодом NOT_FOUND.
+var (
+	ErrTeamExists     = errors.New(CodeTeamExists)
+	ErrPRExists       = errors.New(CodePRExists)
+	ErrPRMerged       = errors.New(CodePRMerged)
+	ErrNotAssigned    = errors.New(CodeNotAssigned)
+	ErrNoCandidate    = errors.New(CodeNoCandidate)
+	ErrNotFound       = errors.New(CodeNotFound)
+	ErrNotEmpty       = errors.New(CodeNotEmpty)
+	ErrOrgExists      = errors.New(CodeOrgExists)
+	ErrInvalidParam   = errors.New(CodeInvalidParam)
+	ErrInvalidPayload = errors.New(CodeInvalidPayload)
+	ErrMissingParam   = errors.New(CodeMissingParam)
+	ErrUnauthorized   = errors.New(CodeUnauthorized)
+	ErrForbidden      = errors.New(CodeForbidden)
+	ErrInternal       = errors.New(CodeInternalError)
+)
+
+var codeErrors = map[string]error{
+	CodeTeamExists:     ErrTeamExists,
+	CodePRExists:       ErrPRExists,
+	CodePRMerged:       ErrPRMerged,
+	CodeNotAssigned:    ErrNotAssigned,
+	CodeNoCandidate:    ErrNoCandidate,
+	CodeNotFound:       ErrNotFound,
+	CodeNotEmpty:       ErrNotEmpty,
+	CodeOrgExists:      ErrOrgExists,
+	CodeInvalidParam:   ErrInvalidParam,
+	CodeInvalidPayload: ErrInvalidPayload,
+	CodeMissingParam:   ErrMissingParam,
+	CodeUnauthorized:   ErrUnauthorized,
+	CodeForbidden:      ErrForbidden,
+	CodeInternalError:  ErrInternal,
+}
+
+// APIError — ошибка, которую вернул сервис.
+type APIError struct {
+	StatusCode int
+	// Code — значение error.code; пустое, если тело ответа не в формате errorResponse.
+	Code    string
+	Message string
+	// Details — нарушения схемы запроса при INVALID_PAYLOAD или причины отказа кандидатам,
+	// если при NO_CANDIDATE назначению помешали правила подбора команды.
+	Details []string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("http %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s (http %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Is сопоставляет ошибку с sentinel-ошибкой её кода.
+func (e *APIError) Is(target error) bool {
+	sentinel, ok := codeErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// decodeAPIError разбирает errorResponse; если тело в другом формате, сохраняет его начало как сообщение.
+func decodeAPIError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	var payload struct {
+		Error struct {
+			Code    string   `json:"code"`
+			Message string   `json:"message"`
+			Details []string `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Code != "" {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Code:       payload.Error.Code,
+			Message:    payload.Error.Message,
+			Details:    payload.Error.Details,
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
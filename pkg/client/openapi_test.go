@@ -0,0 +1,148 @@
+package client
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/AlekseyZapadovnikov/pr-manager/api"
+)
+
+// openAPISpec — часть openapi.yml, которую сверяет клиент.
+type openAPISpec struct {
+	Paths      map[string]map[string]any `yaml:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `yaml:"$ref"`
+	AllOf      []openAPISchema          `yaml:"allOf"`
+	Properties map[string]openAPISchema `yaml:"properties"`
+	Enum       []string                 `yaml:"enum"`
+}
+
+func loadSpec(t *testing.T) openAPISpec {
+	t.Helper()
+	var spec openAPISpec
+	require.NoError(t, yaml.Unmarshal(api.Spec, &spec))
+	return spec
+}
+
+func TestOperationsMatchSpec(t *testing.T) {
+	spec := loadSpec(t)
+
+	var inSpec []string
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			inSpec = append(inSpec, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var inClient []string
+	for _, op := range operations {
+		inClient = append(inClient, op.method+" "+op.path)
+	}
+
+	sort.Strings(inSpec)
+	sort.Strings(inClient)
+	require.Equal(t, inSpec, inClient, "client operations must cover openapi.yml exactly")
+}
+
+func TestErrorCodesMatchSpec(t *testing.T) {
+	spec := loadSpec(t)
+	codes := spec.Components.Schemas["ErrorResponse"].Properties["error"].Properties["code"].Enum
+	require.NotEmpty(t, codes)
+	for _, code := range codes {
+		require.Contains(t, codeErrors, code, "no sentinel error for code %s", code)
+	}
+}
+
+func TestSchemasMatchModels(t *testing.T) {
+	spec := loadSpec(t)
+
+	types := map[string]any{
+		"TeamMember":                TeamMember{},
+		"Team":                      Team{},
+		"User":                      User{},
+		"PullRequest":               PullRequest{},
+		"PullRequestShort":          PullRequestShort{},
+		"AssignmentStats":           AssignmentStats{},
+		"TeamAssignmentStat":        TeamAssignmentStat{},
+		"UserAssignmentStat":        UserAssignmentStat{},
+		"PullRequestAssignmentStat": PullRequestAssignmentStat{},
+		"TurnaroundPercentiles":     TurnaroundPercentiles{},
+		"TeamTurnaround":            TeamTurnaround{},
+		"UserTurnaround":            UserTurnaround{},
+		"TurnaroundStats":           TurnaroundStats{},
+		"TeamBulkDeactivateRequest": teamDeactivateRequest{},
+		"TeamBulkDeactivateResult":  TeamBulkDeactivateResult{},
+		"TeamPRReassignment":        TeamPRReassignment{},
+		"ReviewerReplacement":       ReviewerReplacement{},
+		"Snapshot":                  Snapshot{},
+		"SnapshotCounts":            SnapshotCounts{},
+		"ImportRowResult":           ImportRowResult{},
+		"ImportReport":              ImportReport{},
+		"ReviewActivity":            ReviewActivity{},
+		"ReviewRotation":            ReviewRotation{},
+		"Absence":                   Absence{},
+		"WorkingHours":              WorkingHours{},
+		"ReviewerLoad":              ReviewerLoad{},
+		"Repository":                Repository{},
+		"Organization":              Organization{},
+		"AffinityRule":              AffinityRule{},
+		"Learner":                   Learner{},
+		"TeamLearners":              TeamLearners{},
+		"PullRequestExplanation":    PullRequestExplanation{},
+		"AssignmentDecision":        AssignmentDecision{},
+		"DecisionCandidate":         DecisionCandidate{},
+		"CandidateLoad":             CandidateLoad{},
+	}
+
+	for name, v := range types {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := spec.Components.Schemas[name]
+			require.True(t, ok, "schema %s is missing in openapi.yml", name)
+			require.ElementsMatch(t, schemaProperties(spec, schema), jsonFields(reflect.TypeOf(v)))
+		})
+	}
+}
+
+// schemaProperties собирает имена свойств схемы с учётом allOf и $ref.
+func schemaProperties(spec openAPISpec, s openAPISchema) []string {
+	if s.Ref != "" {
+		return schemaProperties(spec, spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")])
+	}
+	var names []string
+	for _, part := range s.AllOf {
+		names = append(names, schemaProperties(spec, part)...)
+	}
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	return names
+}
+
+// jsonFields возвращает JSON-имена полей структуры, раскрывая встроенные структуры.
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch {
+		case f.Anonymous && tag == "":
+			names = append(names, jsonFields(f.Type)...)
+		case tag == "-" || !f.IsExported():
+		case tag == "":
+			names = append(names, f.Name)
+		default:
+			names = append(names, tag)
+		}
+	}
+	return names
+}
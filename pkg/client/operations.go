@@ -0,0 +1,99 @@
+package client
+
+import "net/http"
+
+// Пути операций API; каждый метод клиента вызывает ровно одну из них.
+const (
+	pathHealth                   = "/health"
+	pathTeamAdd                  = "/team/add"
+	pathTeamGet                  = "/team/get"
+	pathTeamDeactivateUsers      = "/team/deactivateUsers"
+	pathTeamAddRule              = "/team/addRule"
+	pathTeamGetRules             = "/team/getRules"
+	pathTeamDeleteRule           = "/team/deleteRule"
+	pathTeamSetLearners          = "/team/setLearners"
+	pathTeamGetLearners          = "/team/getLearners"
+	pathUsersSetIsActive         = "/users/setIsActive"
+	pathUsersGetReview           = "/users/getReview"
+	pathUsersAddAbsence          = "/users/addAbsence"
+	pathUsersGetAbsences         = "/users/getAbsences"
+	pathUsersCancelAbsence       = "/users/cancelAbsence"
+	pathUsersSetWorkingHours     = "/users/setWorkingHours"
+	pathUsersGetWorkingHours     = "/users/getWorkingHours"
+	pathUsersClearWorkingHours   = "/users/clearWorkingHours"
+	pathUsersSetCapacity         = "/users/setCapacity"
+	pathUsersGetLoad             = "/users/getLoad"
+	pathPullRequestCreate        = "/pullRequest/create"
+	pathPullRequestMerge         = "/pullRequest/merge"
+	pathPullRequestReassign      = "/pullRequest/reassign"
+	pathPullRequestActivity      = "/pullRequest/activity"
+	pathPullRequestRotations     = "/pullRequest/rotations"
+	pathPullRequestExplain       = "/pullRequest/explain"
+	pathRepositoriesSet          = "/repositories/set"
+	pathRepositoriesGet          = "/repositories/get"
+	pathRepositoriesList         = "/repositories/list"
+	pathStatsAssignments         = "/stats/assignments"
+	pathStatsTurnaround          = "/stats/turnaround"
+	pathAdminImport              = "/admin/import"
+	pathAdminExport              = "/admin/export"
+	pathAdminImportSnapshot      = "/admin/import-snapshot"
+	pathAdminOrganizationsCreate = "/admin/organizations/create"
+	pathAdminOrganizationsList   = "/admin/organizations/list"
+)
+
+// operation — метод и путь операции API.
+type operation struct {
+	method string
+	path   string
+	// idempotent разрешает повтор запроса после сетевой ошибки или временного отказа сервера.
+	idempotent bool
+}
+
+// operations — все операции, которые поддерживает клиент; тесты сверяют список с openapi.yml.
+var operations = []operation{
+	{http.MethodGet, pathHealth, true},
+	{http.MethodPost, pathTeamAdd, false},
+	{http.MethodGet, pathTeamGet, true},
+	{http.MethodPost, pathTeamDeactivateUsers, false},
+	{http.MethodPost, pathTeamAddRule, false},
+	{http.MethodGet, pathTeamGetRules, true},
+	{http.MethodPost, pathTeamDeleteRule, false},
+	{http.MethodPost, pathTeamSetLearners, true},
+	{http.MethodGet, pathTeamGetLearners, true},
+	{http.MethodPost, pathUsersSetIsActive, true},
+	{http.MethodGet, pathUsersGetReview, true},
+	{http.MethodPost, pathUsersAddAbsence, false},
+	{http.MethodGet, pathUsersGetAbsences, true},
+	{http.MethodPost, pathUsersCancelAbsence, false},
+	{http.MethodPost, pathUsersSetWorkingHours, true},
+	{http.MethodGet, pathUsersGetWorkingHours, true},
+	{http.MethodPost, pathUsersClearWorkingHours, false},
+	{http.MethodPost, pathUsersSetCapacity, true},
+	{http.MethodGet, pathUsersGetLoad, true},
+	{http.MethodPost, pathPullRequestCreate, false},
+	{http.MethodPost, pathPullRequestMerge, true},
+	{http.MethodPost, pathPullRequestReassign, false},
+	{http.MethodPost, pathPullRequestActivity, true},
+	{http.MethodGet, pathPullRequestRotations, true},
+	{http.MethodGet, pathPullRequestExplain, true},
+	{http.MethodPost, pathRepositoriesSet, true},
+	{http.MethodGet, pathRepositoriesGet, true},
+	{http.MethodGet, pathRepositoriesList, true},
+	{http.MethodGet, pathStatsAssignments, true},
+	{http.MethodGet, pathStatsTurnaround, true},
+	{http.MethodPost, pathAdminImport, false},
+	{http.MethodGet, pathAdminExport, true},
+	{http.MethodPost, pathAdminImportSnapshot, false},
+	{http.MethodPost, pathAdminOrganizationsCreate, false},
+	{http.MethodGet, pathAdminOrganizationsList, true},
+}
+
+// isIdempotent сообщает, можно ли повторять запрос method path.
+func isIdempotent(method, path string) bool {
+	for _, op := range operations {
+		if op.method == method && op.path == path {
+			return op.idempotent
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy задаёт повторы идемпотентных запросов: GET, /users/setIsActive и /pullRequest/merge.
+// Повторяются сетевые ошибки и ответы 429, 502, 503, 504; задержка растёт вдвое от Backoff до MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts — общее число попыток; 1 отключает повторы.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy используется, если клиент создан без WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+// WithRetry задаёт политику повторов; RetryPolicy{MaxAttempts: 1} отключает их.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// retryableStatus — временные отказы, после которых запрос имеет смысл повторить.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// delay возвращает паузу перед попыткой attempt (с 1); Retry-After в секундах имеет приоритет.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	d := p.Backoff << (attempt - 1)
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			d = time.Duration(secs) * time.Second
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// sleep ждёт d или отмены контекста.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isContextError отличает отмену вызывающим от сетевой ошибки.
+func isContextError(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,178 @@
+package client
+
+import "github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+
+// Типы запросов и ответов совпадают с моделями сервиса, поэтому расхождение API ловится при компиляции.
+type (
+	Team                      = models.Team
+	TeamMember                = models.TeamMember
+	User                      = models.User
+	PullRequest               = models.PullRequest
+	PullRequestShort          = models.PullRequestShort
+	PullRequestStatus         = models.PullRequestStatus
+	TeamBulkDeactivateResult  = models.TeamBulkDeactivateResult
+	TeamPRReassignment        = models.TeamPRReassignment
+	ReviewerReplacement       = models.ReviewerReplacement
+	AssignmentStats           = models.AssignmentStats
+	AssignmentStatsFilter     = models.AssignmentStatsFilter
+	UserAssignmentStat        = models.UserAssignmentStat
+	PullRequestAssignmentStat = models.PullRequestAssignmentStat
+	TeamAssignmentStat        = models.TeamAssignmentStat
+	TurnaroundStats           = models.TurnaroundStats
+	TurnaroundFilter          = models.TurnaroundFilter
+	TurnaroundPercentiles     = models.TurnaroundPercentiles
+	TeamTurnaround            = models.TeamTurnaround
+	UserTurnaround            = models.UserTurnaround
+	ImportConflictPolicy      = models.ImportConflictPolicy
+	ImportOptions             = models.ImportOptions
+	ImportRowResult           = models.ImportRowResult
+	ImportReport              = models.ImportReport
+	Snapshot                  = models.Snapshot
+	SnapshotTeam              = models.SnapshotTeam
+	SnapshotCounts            = models.SnapshotCounts
+	ReviewActivity            = models.ReviewActivity
+	ReviewRotation            = models.ReviewRotation
+	ReviewRotationFilter      = models.ReviewRotationFilter
+	Absence                   = models.Absence
+	WorkingHours              = models.WorkingHours
+	ReviewerLoad              = models.ReviewerLoad
+	PullRequestPriority       = models.PullRequestPriority
+	Repository                = models.Repository
+	Organization              = models.Organization
+	AffinityRule              = models.AffinityRule
+	AffinityRuleKind          = models.AffinityRuleKind
+	Learner                   = models.Learner
+	TeamLearners              = models.TeamLearners
+	ReviewerRole              = models.ReviewerRole
+	PullRequestExplanation    = models.PullRequestExplanation
+	AssignmentDecision        = models.AssignmentDecision
+	DecisionCandidate         = models.DecisionCandidate
+	CandidateLoad             = models.CandidateLoad
+	DecisionOperation         = models.DecisionOperation
+	AssignmentStrategy        = models.AssignmentStrategy
+	CandidateOutcome          = models.CandidateOutcome
+)
+
+// Статусы PR.
+const (
+	StatusOpen   = models.PullRequestStatusOPEN
+	StatusMerged = models.PullRequestStatusMERGED
+)
+
+// Приоритеты PR.
+const (
+	PriorityLow    = models.PullRequestPriorityLOW
+	PriorityNormal = models.PullRequestPriorityNORMAL
+	PriorityHigh   = models.PullRequestPriorityHIGH
+	PriorityUrgent = models.PullRequestPriorityURGENT
+)
+
+// Виды правил подбора ревьюверов.
+const (
+	RuleNeverPair     = models.AffinityRuleNEVERPAIR
+	RuleAlwaysInclude = models.AffinityRuleALWAYSINCLUDE
+	RuleRequirePair   = models.AffinityRuleREQUIREPAIR
+)
+
+// Роли пользователя в PR.
+const (
+	RoleReviewer = models.ReviewerRoleREVIEWER
+	RoleShadow   = models.ReviewerRoleSHADOW
+)
+
+// Исходы подбора для кандидата в решении о назначении.
+const (
+	OutcomeSelected    = models.CandidateOutcomeSELECTED
+	OutcomeExcluded    = models.CandidateOutcomeEXCLUDED
+	OutcomeNotSelected = models.CandidateOutcomeNOTSELECTED
+)
+
+// Форматы файла импорта пользователей.
+const (
+	ImportFormatCSV  = models.ImportFormatCSV
+	ImportFormatJSON = models.ImportFormatJSON
+)
+
+// CreatePullRequestRequest — параметры создания PR.
+type CreatePullRequestRequest = models.PostPullRequestCreateJSONBody
+
+// AddAbsenceRequest — параметры периода отсутствия; конец периода не включается.
+type AddAbsenceRequest = models.PostUsersAddAbsenceJSONBody
+
+// AddTeamRuleRequest — параметры правила подбора ревьюверов команды.
+type AddTeamRuleRequest = models.PostTeamAddRuleJSONBody
+
+// Тела остальных запросов.
+type (
+	teamDeactivateRequest = models.TeamBulkDeactivateRequest
+	setIsActiveRequest    = models.PostUsersSetIsActiveJSONBody
+	mergeRequest          = models.PostPullRequestMergeJSONBody
+	reassignRequest       = models.PostPullRequestReassignJSONBody
+	setLearnersRequest    = models.PostTeamSetLearnersJSONBody
+)
+
+// activityRequest — тело отметки активности ревьювера.
+type activityRequest struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+}
+
+// userRequest — тело операций, которым нужен только пользователь.
+type userRequest struct {
+	UserId string `json:"user_id"`
+}
+
+// deleteRuleRequest — тело удаления правила подбора ревьюверов.
+type deleteRuleRequest struct {
+	RuleId int64 `json:"rule_id"`
+}
+
+// cancelAbsenceRequest — тело отмены периода отсутствия.
+type cancelAbsenceRequest struct {
+	AbsenceId int64 `json:"absence_id"`
+}
+
+// CreatePullRequestResult — созданный PR и нагрузка назначенных ревьюверов.
+type CreatePullRequestResult struct {
+	PR           *PullRequest   `json:"pr"`
+	ReviewerLoad []ReviewerLoad `json:"reviewer_load"`
+	// PendingReviewers — сколько ревьюверов не хватило из-за лимитов; PR ждёт их в очереди.
+	PendingReviewers int `json:"pending_reviewers"`
+}
+
+// setCapacityRequest — тело изменения лимита открытых ревью.
+type setCapacityRequest = models.PostUsersSetCapacityJSONBody
+
+// createOrganizationRequest — тело создания организации.
+type createOrganizationRequest = models.PostOrganizationCreateJSONBody
+
+// ReassignResult — итог переназначения ревьювера.
+type ReassignResult struct {
+	PR         *PullRequest `json:"pr"`
+	ReplacedBy string       `json:"replaced_by"`
+}
+
+// UserReviews — PR, назначенные ревьюверу.
+type UserReviews struct {
+	UserId       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+}
+
+// StreamFormat — тип содержимого построчной выгрузки.
+type StreamFormat string
+
+// Форматы построчной выгрузки.
+const (
+	StreamCSV    StreamFormat = "text/csv"
+	StreamNDJSON StreamFormat = "application/x-ndjson"
+)
+
+// StatsSlice — срез статистики назначений для построчной выгрузки.
+type StatsSlice string
+
+// Срезы статистики назначений.
+const (
+	StatsByUser        StatsSlice = "user"
+	StatsByPullRequest StatsSlice = "pull_request"
+	StatsByTeam        StatsSlice = "team"
+)
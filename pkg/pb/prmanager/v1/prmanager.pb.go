@@ -0,0 +1,3064 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: prmanager/v1/prmanager.proto
+
+// Контракт gRPC API pr-manager. Операции повторяют HTTP API из api/openapi.yml
+// и выполняются тем же сервисным слоем.
+
+package prmanagerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PullRequestStatus int32
+
+const (
+	PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED PullRequestStatus = 0
+	PullRequestStatus_PULL_REQUEST_STATUS_OPEN        PullRequestStatus = 1
+	PullRequestStatus_PULL_REQUEST_STATUS_MERGED      PullRequestStatus = 2
+)
+
+// Enum value maps for PullRequestStatus.
+var (
+	PullRequestStatus_name = map[int32]string{
+		0: "PULL_REQUEST_STATUS_UNSPECIFIED",
+		1: "PULL_REQUEST_STATUS_OPEN",
+		2: "PULL_REQUEST_STATUS_MERGED",
+	}
+	PullRequestStatus_value = map[string]int32{
+		"PULL_REQUEST_STATUS_UNSPECIFIED": 0,
+		"PULL_REQUEST_STATUS_OPEN":        1,
+		"PULL_REQUEST_STATUS_MERGED":      2,
+	}
+)
+
+func (x PullRequestStatus) Enum() *PullRequestStatus {
+	p := new(PullRequestStatus)
+	*p = x
+	return p
+}
+
+func (x PullRequestStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PullRequestStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_prmanager_v1_prmanager_proto_enumTypes[0].Descriptor()
+}
+
+func (PullRequestStatus) Type() protoreflect.EnumType {
+	return &file_prmanager_v1_prmanager_proto_enumTypes[0]
+}
+
+func (x PullRequestStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PullRequestStatus.Descriptor instead.
+func (PullRequestStatus) EnumDescriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{0}
+}
+
+type TeamMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	IsActive      bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamMember) Reset() {
+	*x = TeamMember{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamMember) ProtoMessage() {}
+
+func (x *TeamMember) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamMember.ProtoReflect.Descriptor instead.
+func (*TeamMember) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TeamMember) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TeamMember) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *TeamMember) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Members       []*TeamMember          `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Team) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *Team) GetMembers() []*TeamMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	TeamName      string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	IsActive      bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *User) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type PullRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Status          PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=prmanager.v1.PullRequestStatus" json:"status,omitempty"`
+	// user_id назначенных ревьюверов (0..2).
+	AssignedReviewers []string               `protobuf:"bytes,5,rep,name=assigned_reviewers,json=assignedReviewers,proto3" json:"assigned_reviewers,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MergedAt          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=merged_at,json=mergedAt,proto3" json:"merged_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PullRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *PullRequest) GetAssignedReviewers() []string {
+	if x != nil {
+		return x.AssignedReviewers
+	}
+	return nil
+}
+
+func (x *PullRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PullRequest) GetMergedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MergedAt
+	}
+	return nil
+}
+
+type PullRequestShort struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Status          PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=prmanager.v1.PullRequestStatus" json:"status,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PullRequestShort) Reset() {
+	*x = PullRequestShort{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequestShort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequestShort) ProtoMessage() {}
+
+func (x *PullRequestShort) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequestShort.ProtoReflect.Descriptor instead.
+func (*PullRequestShort) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PullRequestShort) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+type CreatePullRequestRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreatePullRequestRequest) Reset() {
+	*x = CreatePullRequestRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePullRequestRequest) ProtoMessage() {}
+
+func (x *CreatePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreatePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+type CreatePullRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pr            *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePullRequestResponse) Reset() {
+	*x = CreatePullRequestResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePullRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePullRequestResponse) ProtoMessage() {}
+
+func (x *CreatePullRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePullRequestResponse.ProtoReflect.Descriptor instead.
+func (*CreatePullRequestResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreatePullRequestResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+type MergePullRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergePullRequestRequest) Reset() {
+	*x = MergePullRequestRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePullRequestRequest) ProtoMessage() {}
+
+func (x *MergePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*MergePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MergePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+type MergePullRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pr            *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergePullRequestResponse) Reset() {
+	*x = MergePullRequestResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePullRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePullRequestResponse) ProtoMessage() {}
+
+func (x *MergePullRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePullRequestResponse.ProtoReflect.Descriptor instead.
+func (*MergePullRequestResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MergePullRequestResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+type ReassignReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	OldUserId     string                 `protobuf:"bytes,2,opt,name=old_user_id,json=oldUserId,proto3" json:"old_user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerRequest) Reset() {
+	*x = ReassignReviewerRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerRequest) ProtoMessage() {}
+
+func (x *ReassignReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerRequest.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReassignReviewerRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetOldUserId() string {
+	if x != nil {
+		return x.OldUserId
+	}
+	return ""
+}
+
+type ReassignReviewerResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pr    *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	// user_id нового ревьювера.
+	ReplacedBy    string `protobuf:"bytes,2,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerResponse) Reset() {
+	*x = ReassignReviewerResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerResponse) ProtoMessage() {}
+
+func (x *ReassignReviewerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerResponse.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReassignReviewerResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+func (x *ReassignReviewerResponse) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+type ListReviewerPullRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewerPullRequestsRequest) Reset() {
+	*x = ListReviewerPullRequestsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewerPullRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewerPullRequestsRequest) ProtoMessage() {}
+
+func (x *ListReviewerPullRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewerPullRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewerPullRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListReviewerPullRequestsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListReviewerPullRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PullRequests  []*PullRequestShort    `protobuf:"bytes,2,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewerPullRequestsResponse) Reset() {
+	*x = ListReviewerPullRequestsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewerPullRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewerPullRequestsResponse) ProtoMessage() {}
+
+func (x *ListReviewerPullRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewerPullRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewerPullRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListReviewerPullRequestsResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListReviewerPullRequestsResponse) GetPullRequests() []*PullRequestShort {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+type StreamReviewerPullRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamReviewerPullRequestsRequest) Reset() {
+	*x = StreamReviewerPullRequestsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamReviewerPullRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamReviewerPullRequestsRequest) ProtoMessage() {}
+
+func (x *StreamReviewerPullRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamReviewerPullRequestsRequest.ProtoReflect.Descriptor instead.
+func (*StreamReviewerPullRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamReviewerPullRequestsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type StreamReviewerPullRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequest   *PullRequestShort      `protobuf:"bytes,1,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamReviewerPullRequestsResponse) Reset() {
+	*x = StreamReviewerPullRequestsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamReviewerPullRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamReviewerPullRequestsResponse) ProtoMessage() {}
+
+func (x *StreamReviewerPullRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamReviewerPullRequestsResponse.ProtoReflect.Descriptor instead.
+func (*StreamReviewerPullRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamReviewerPullRequestsResponse) GetPullRequest() *PullRequestShort {
+	if x != nil {
+		return x.PullRequest
+	}
+	return nil
+}
+
+type DeactivateTeamMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateTeamMembersRequest) Reset() {
+	*x = DeactivateTeamMembersRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateTeamMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateTeamMembersRequest) ProtoMessage() {}
+
+func (x *DeactivateTeamMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateTeamMembersRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateTeamMembersRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeactivateTeamMembersRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *DeactivateTeamMembersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type ReviewerReplacement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldUserId     string                 `protobuf:"bytes,1,opt,name=old_user_id,json=oldUserId,proto3" json:"old_user_id,omitempty"`
+	NewUserId     string                 `protobuf:"bytes,2,opt,name=new_user_id,json=newUserId,proto3" json:"new_user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewerReplacement) Reset() {
+	*x = ReviewerReplacement{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerReplacement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerReplacement) ProtoMessage() {}
+
+func (x *ReviewerReplacement) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerReplacement.ProtoReflect.Descriptor instead.
+func (*ReviewerReplacement) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReviewerReplacement) GetOldUserId() string {
+	if x != nil {
+		return x.OldUserId
+	}
+	return ""
+}
+
+func (x *ReviewerReplacement) GetNewUserId() string {
+	if x != nil {
+		return x.NewUserId
+	}
+	return ""
+}
+
+type TeamPullRequestReassignment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	Replacements  []*ReviewerReplacement `protobuf:"bytes,2,rep,name=replacements,proto3" json:"replacements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamPullRequestReassignment) Reset() {
+	*x = TeamPullRequestReassignment{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamPullRequestReassignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamPullRequestReassignment) ProtoMessage() {}
+
+func (x *TeamPullRequestReassignment) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamPullRequestReassignment.ProtoReflect.Descriptor instead.
+func (*TeamPullRequestReassignment) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TeamPullRequestReassignment) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *TeamPullRequestReassignment) GetReplacements() []*ReviewerReplacement {
+	if x != nil {
+		return x.Replacements
+	}
+	return nil
+}
+
+type DeactivateTeamMembersResponse struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	TeamName      string                         `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Deactivated   []string                       `protobuf:"bytes,2,rep,name=deactivated,proto3" json:"deactivated,omitempty"`
+	Reassignments []*TeamPullRequestReassignment `protobuf:"bytes,3,rep,name=reassignments,proto3" json:"reassignments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateTeamMembersResponse) Reset() {
+	*x = DeactivateTeamMembersResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateTeamMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateTeamMembersResponse) ProtoMessage() {}
+
+func (x *DeactivateTeamMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateTeamMembersResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateTeamMembersResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeactivateTeamMembersResponse) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *DeactivateTeamMembersResponse) GetDeactivated() []string {
+	if x != nil {
+		return x.Deactivated
+	}
+	return nil
+}
+
+func (x *DeactivateTeamMembersResponse) GetReassignments() []*TeamPullRequestReassignment {
+	if x != nil {
+		return x.Reassignments
+	}
+	return nil
+}
+
+// AssignmentStatsFilter сужает выборку PR; все поля необязательны.
+type AssignmentStatsFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Команда автора PR.
+	Team string `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	// Окно [from, to) по времени создания PR.
+	From   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Status PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=prmanager.v1.PullRequestStatus" json:"status,omitempty"`
+	// Ограничивает by_user и by_pull_request; 0 — без ограничения.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignmentStatsFilter) Reset() {
+	*x = AssignmentStatsFilter{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentStatsFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentStatsFilter) ProtoMessage() {}
+
+func (x *AssignmentStatsFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentStatsFilter.ProtoReflect.Descriptor instead.
+func (*AssignmentStatsFilter) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AssignmentStatsFilter) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *AssignmentStatsFilter) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AssignmentStatsFilter) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *AssignmentStatsFilter) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *AssignmentStatsFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UserAssignmentStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Assignments   int32                  `protobuf:"varint,3,opt,name=assignments,proto3" json:"assignments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserAssignmentStat) Reset() {
+	*x = UserAssignmentStat{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserAssignmentStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserAssignmentStat) ProtoMessage() {}
+
+func (x *UserAssignmentStat) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserAssignmentStat.ProtoReflect.Descriptor instead.
+func (*UserAssignmentStat) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserAssignmentStat) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserAssignmentStat) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserAssignmentStat) GetAssignments() int32 {
+	if x != nil {
+		return x.Assignments
+	}
+	return 0
+}
+
+type PullRequestAssignmentStat struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	ReviewerCount   int32                  `protobuf:"varint,3,opt,name=reviewer_count,json=reviewerCount,proto3" json:"reviewer_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PullRequestAssignmentStat) Reset() {
+	*x = PullRequestAssignmentStat{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequestAssignmentStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequestAssignmentStat) ProtoMessage() {}
+
+func (x *PullRequestAssignmentStat) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequestAssignmentStat.ProtoReflect.Descriptor instead.
+func (*PullRequestAssignmentStat) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PullRequestAssignmentStat) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequestAssignmentStat) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequestAssignmentStat) GetReviewerCount() int32 {
+	if x != nil {
+		return x.ReviewerCount
+	}
+	return 0
+}
+
+type TeamAssignmentStat struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TeamName     string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	OpenCount    int32                  `protobuf:"varint,2,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	MergedCount  int32                  `protobuf:"varint,3,opt,name=merged_count,json=mergedCount,proto3" json:"merged_count,omitempty"`
+	AvgReviewers float64                `protobuf:"fixed64,4,opt,name=avg_reviewers,json=avgReviewers,proto3" json:"avg_reviewers,omitempty"`
+	// Коэффициент вариации числа назначений среди активных участников; 0 — нагрузка равномерна.
+	LoadImbalance float64 `protobuf:"fixed64,5,opt,name=load_imbalance,json=loadImbalance,proto3" json:"load_imbalance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamAssignmentStat) Reset() {
+	*x = TeamAssignmentStat{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamAssignmentStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamAssignmentStat) ProtoMessage() {}
+
+func (x *TeamAssignmentStat) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamAssignmentStat.ProtoReflect.Descriptor instead.
+func (*TeamAssignmentStat) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TeamAssignmentStat) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *TeamAssignmentStat) GetOpenCount() int32 {
+	if x != nil {
+		return x.OpenCount
+	}
+	return 0
+}
+
+func (x *TeamAssignmentStat) GetMergedCount() int32 {
+	if x != nil {
+		return x.MergedCount
+	}
+	return 0
+}
+
+func (x *TeamAssignmentStat) GetAvgReviewers() float64 {
+	if x != nil {
+		return x.AvgReviewers
+	}
+	return 0
+}
+
+func (x *TeamAssignmentStat) GetLoadImbalance() float64 {
+	if x != nil {
+		return x.LoadImbalance
+	}
+	return 0
+}
+
+type GetAssignmentStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *AssignmentStatsFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssignmentStatsRequest) Reset() {
+	*x = GetAssignmentStatsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssignmentStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssignmentStatsRequest) ProtoMessage() {}
+
+func (x *GetAssignmentStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssignmentStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetAssignmentStatsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetAssignmentStatsRequest) GetFilter() *AssignmentStatsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GetAssignmentStatsResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	ByUser        []*UserAssignmentStat        `protobuf:"bytes,1,rep,name=by_user,json=byUser,proto3" json:"by_user,omitempty"`
+	ByPullRequest []*PullRequestAssignmentStat `protobuf:"bytes,2,rep,name=by_pull_request,json=byPullRequest,proto3" json:"by_pull_request,omitempty"`
+	ByTeam        []*TeamAssignmentStat        `protobuf:"bytes,3,rep,name=by_team,json=byTeam,proto3" json:"by_team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssignmentStatsResponse) Reset() {
+	*x = GetAssignmentStatsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssignmentStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssignmentStatsResponse) ProtoMessage() {}
+
+func (x *GetAssignmentStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssignmentStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetAssignmentStatsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetAssignmentStatsResponse) GetByUser() []*UserAssignmentStat {
+	if x != nil {
+		return x.ByUser
+	}
+	return nil
+}
+
+func (x *GetAssignmentStatsResponse) GetByPullRequest() []*PullRequestAssignmentStat {
+	if x != nil {
+		return x.ByPullRequest
+	}
+	return nil
+}
+
+func (x *GetAssignmentStatsResponse) GetByTeam() []*TeamAssignmentStat {
+	if x != nil {
+		return x.ByTeam
+	}
+	return nil
+}
+
+type ListTeamAssignmentStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *AssignmentStatsFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamAssignmentStatsRequest) Reset() {
+	*x = ListTeamAssignmentStatsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamAssignmentStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamAssignmentStatsRequest) ProtoMessage() {}
+
+func (x *ListTeamAssignmentStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamAssignmentStatsRequest.ProtoReflect.Descriptor instead.
+func (*ListTeamAssignmentStatsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListTeamAssignmentStatsRequest) GetFilter() *AssignmentStatsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListTeamAssignmentStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teams         []*TeamAssignmentStat  `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamAssignmentStatsResponse) Reset() {
+	*x = ListTeamAssignmentStatsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamAssignmentStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamAssignmentStatsResponse) ProtoMessage() {}
+
+func (x *ListTeamAssignmentStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamAssignmentStatsResponse.ProtoReflect.Descriptor instead.
+func (*ListTeamAssignmentStatsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListTeamAssignmentStatsResponse) GetTeams() []*TeamAssignmentStat {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type StreamUserAssignmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *AssignmentStatsFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamUserAssignmentsRequest) Reset() {
+	*x = StreamUserAssignmentsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamUserAssignmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamUserAssignmentsRequest) ProtoMessage() {}
+
+func (x *StreamUserAssignmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamUserAssignmentsRequest.ProtoReflect.Descriptor instead.
+func (*StreamUserAssignmentsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *StreamUserAssignmentsRequest) GetFilter() *AssignmentStatsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type StreamUserAssignmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stat          *UserAssignmentStat    `protobuf:"bytes,1,opt,name=stat,proto3" json:"stat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamUserAssignmentsResponse) Reset() {
+	*x = StreamUserAssignmentsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamUserAssignmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamUserAssignmentsResponse) ProtoMessage() {}
+
+func (x *StreamUserAssignmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamUserAssignmentsResponse.ProtoReflect.Descriptor instead.
+func (*StreamUserAssignmentsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *StreamUserAssignmentsResponse) GetStat() *UserAssignmentStat {
+	if x != nil {
+		return x.Stat
+	}
+	return nil
+}
+
+type StreamPullRequestAssignmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *AssignmentStatsFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPullRequestAssignmentsRequest) Reset() {
+	*x = StreamPullRequestAssignmentsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPullRequestAssignmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPullRequestAssignmentsRequest) ProtoMessage() {}
+
+func (x *StreamPullRequestAssignmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPullRequestAssignmentsRequest.ProtoReflect.Descriptor instead.
+func (*StreamPullRequestAssignmentsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StreamPullRequestAssignmentsRequest) GetFilter() *AssignmentStatsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type StreamPullRequestAssignmentsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Stat          *PullRequestAssignmentStat `protobuf:"bytes,1,opt,name=stat,proto3" json:"stat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPullRequestAssignmentsResponse) Reset() {
+	*x = StreamPullRequestAssignmentsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPullRequestAssignmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPullRequestAssignmentsResponse) ProtoMessage() {}
+
+func (x *StreamPullRequestAssignmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPullRequestAssignmentsResponse.ProtoReflect.Descriptor instead.
+func (*StreamPullRequestAssignmentsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *StreamPullRequestAssignmentsResponse) GetStat() *PullRequestAssignmentStat {
+	if x != nil {
+		return x.Stat
+	}
+	return nil
+}
+
+type GetTurnaroundStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Окно [from, to) по времени слияния; по умолчанию — stats.turnaround_window до текущей минуты.
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTurnaroundStatsRequest) Reset() {
+	*x = GetTurnaroundStatsRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTurnaroundStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTurnaroundStatsRequest) ProtoMessage() {}
+
+func (x *GetTurnaroundStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTurnaroundStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTurnaroundStatsRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetTurnaroundStatsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type TurnaroundPercentiles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	P50Seconds    float64                `protobuf:"fixed64,2,opt,name=p50_seconds,json=p50Seconds,proto3" json:"p50_seconds,omitempty"`
+	P90Seconds    float64                `protobuf:"fixed64,3,opt,name=p90_seconds,json=p90Seconds,proto3" json:"p90_seconds,omitempty"`
+	P99Seconds    float64                `protobuf:"fixed64,4,opt,name=p99_seconds,json=p99Seconds,proto3" json:"p99_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TurnaroundPercentiles) Reset() {
+	*x = TurnaroundPercentiles{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TurnaroundPercentiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TurnaroundPercentiles) ProtoMessage() {}
+
+func (x *TurnaroundPercentiles) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TurnaroundPercentiles.ProtoReflect.Descriptor instead.
+func (*TurnaroundPercentiles) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TurnaroundPercentiles) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TurnaroundPercentiles) GetP50Seconds() float64 {
+	if x != nil {
+		return x.P50Seconds
+	}
+	return 0
+}
+
+func (x *TurnaroundPercentiles) GetP90Seconds() float64 {
+	if x != nil {
+		return x.P90Seconds
+	}
+	return 0
+}
+
+func (x *TurnaroundPercentiles) GetP99Seconds() float64 {
+	if x != nil {
+		return x.P99Seconds
+	}
+	return 0
+}
+
+type TeamTurnaround struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Percentiles   *TurnaroundPercentiles `protobuf:"bytes,2,opt,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamTurnaround) Reset() {
+	*x = TeamTurnaround{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamTurnaround) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamTurnaround) ProtoMessage() {}
+
+func (x *TeamTurnaround) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamTurnaround.ProtoReflect.Descriptor instead.
+func (*TeamTurnaround) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TeamTurnaround) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *TeamTurnaround) GetPercentiles() *TurnaroundPercentiles {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+type UserTurnaround struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Percentiles   *TurnaroundPercentiles `protobuf:"bytes,2,opt,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserTurnaround) Reset() {
+	*x = UserTurnaround{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserTurnaround) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserTurnaround) ProtoMessage() {}
+
+func (x *UserTurnaround) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserTurnaround.ProtoReflect.Descriptor instead.
+func (*UserTurnaround) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UserTurnaround) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserTurnaround) GetPercentiles() *TurnaroundPercentiles {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+type GetTurnaroundStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Overall       *TurnaroundPercentiles `protobuf:"bytes,3,opt,name=overall,proto3" json:"overall,omitempty"`
+	ByTeam        []*TeamTurnaround      `protobuf:"bytes,4,rep,name=by_team,json=byTeam,proto3" json:"by_team,omitempty"`
+	ByAuthor      []*UserTurnaround      `protobuf:"bytes,5,rep,name=by_author,json=byAuthor,proto3" json:"by_author,omitempty"`
+	ByReviewer    []*UserTurnaround      `protobuf:"bytes,6,rep,name=by_reviewer,json=byReviewer,proto3" json:"by_reviewer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTurnaroundStatsResponse) Reset() {
+	*x = GetTurnaroundStatsResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTurnaroundStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTurnaroundStatsResponse) ProtoMessage() {}
+
+func (x *GetTurnaroundStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTurnaroundStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetTurnaroundStatsResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetTurnaroundStatsResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsResponse) GetOverall() *TurnaroundPercentiles {
+	if x != nil {
+		return x.Overall
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsResponse) GetByTeam() []*TeamTurnaround {
+	if x != nil {
+		return x.ByTeam
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsResponse) GetByAuthor() []*UserTurnaround {
+	if x != nil {
+		return x.ByAuthor
+	}
+	return nil
+}
+
+func (x *GetTurnaroundStatsResponse) GetByReviewer() []*UserTurnaround {
+	if x != nil {
+		return x.ByReviewer
+	}
+	return nil
+}
+
+type AddTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTeamRequest) Reset() {
+	*x = AddTeamRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTeamRequest) ProtoMessage() {}
+
+func (x *AddTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTeamRequest.ProtoReflect.Descriptor instead.
+func (*AddTeamRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AddTeamRequest) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type AddTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTeamResponse) Reset() {
+	*x = AddTeamResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTeamResponse) ProtoMessage() {}
+
+func (x *AddTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTeamResponse.ProtoReflect.Descriptor instead.
+func (*AddTeamResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AddTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetTeamRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+type GetTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamResponse) Reset() {
+	*x = GetTeamResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamResponse) ProtoMessage() {}
+
+func (x *GetTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamResponse.ProtoReflect.Descriptor instead.
+func (*GetTeamResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type SetUserActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsActive      bool                   `protobuf:"varint,2,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserActiveRequest) Reset() {
+	*x = SetUserActiveRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserActiveRequest) ProtoMessage() {}
+
+func (x *SetUserActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserActiveRequest.ProtoReflect.Descriptor instead.
+func (*SetUserActiveRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetUserActiveRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetUserActiveRequest) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type SetUserActiveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserActiveResponse) Reset() {
+	*x = SetUserActiveResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserActiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserActiveResponse) ProtoMessage() {}
+
+func (x *SetUserActiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserActiveResponse.ProtoReflect.Descriptor instead.
+func (*SetUserActiveResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SetUserActiveResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type ImportUserRow struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	TeamName string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	// По умолчанию true.
+	IsActive      *bool `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUserRow) Reset() {
+	*x = ImportUserRow{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUserRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUserRow) ProtoMessage() {}
+
+func (x *ImportUserRow) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUserRow.ProtoReflect.Descriptor instead.
+func (*ImportUserRow) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ImportUserRow) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ImportUserRow) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ImportUserRow) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *ImportUserRow) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+type ImportUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rows  []*ImportUserRow       `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	// skip (по умолчанию), update или fail.
+	OnConflict string `protobuf:"bytes,2,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"`
+	// Только проверить строки и вернуть отчёт.
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersRequest) Reset() {
+	*x = ImportUsersRequest{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersRequest) ProtoMessage() {}
+
+func (x *ImportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ImportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ImportUsersRequest) GetRows() []*ImportUserRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *ImportUsersRequest) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *ImportUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ImportRowResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Row    int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// create, update, skip, unchanged или error.
+	Action        string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowResult) Reset() {
+	*x = ImportRowResult{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowResult) ProtoMessage() {}
+
+func (x *ImportRowResult) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowResult.ProtoReflect.Descriptor instead.
+func (*ImportRowResult) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ImportRowResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowResult) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ImportRowResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportReport struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	DryRun bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Изменения сохранены.
+	Applied       bool               `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	OnConflict    string             `protobuf:"bytes,3,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"`
+	TeamsCreated  []string           `protobuf:"bytes,4,rep,name=teams_created,json=teamsCreated,proto3" json:"teams_created,omitempty"`
+	Created       int32              `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32              `protobuf:"varint,6,opt,name=updated,proto3" json:"updated,omitempty"`
+	Skipped       int32              `protobuf:"varint,7,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Failed        int32              `protobuf:"varint,8,opt,name=failed,proto3" json:"failed,omitempty"`
+	Rows          []*ImportRowResult `protobuf:"bytes,9,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportReport) Reset() {
+	*x = ImportReport{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportReport) ProtoMessage() {}
+
+func (x *ImportReport) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportReport.ProtoReflect.Descriptor instead.
+func (*ImportReport) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ImportReport) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportReport) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *ImportReport) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *ImportReport) GetTeamsCreated() []string {
+	if x != nil {
+		return x.TeamsCreated
+	}
+	return nil
+}
+
+func (x *ImportReport) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportReport) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportReport) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportReport) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportReport) GetRows() []*ImportRowResult {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type ImportUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// При ошибочных строках failed > 0, applied = false и ничего не сохраняется.
+	Report        *ImportReport `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersResponse) Reset() {
+	*x = ImportUsersResponse{}
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersResponse) ProtoMessage() {}
+
+func (x *ImportUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prmanager_v1_prmanager_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersResponse.ProtoReflect.Descriptor instead.
+func (*ImportUsersResponse) Descriptor() ([]byte, []int) {
+	return file_prmanager_v1_prmanager_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ImportUsersResponse) GetReport() *ImportReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+var File_prmanager_v1_prmanager_proto protoreflect.FileDescriptor
+
+const file_prmanager_v1_prmanager_proto_rawDesc = "" +
+	"\n" +
+	"\x1cprmanager/v1/prmanager.proto\x12\fprmanager.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"^\n" +
+	"\n" +
+	"TeamMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tis_active\x18\x03 \x01(\bR\bisActive\"W\n" +
+	"\x04Team\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x122\n" +
+	"\amembers\x18\x02 \x03(\v2\x18.prmanager.v1.TeamMemberR\amembers\"u\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActive\"\xda\x02\n" +
+	"\vPullRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x127\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1f.prmanager.v1.PullRequestStatusR\x06status\x12-\n" +
+	"\x12assigned_reviewers\x18\x05 \x03(\tR\x11assignedReviewers\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tmerged_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bmergedAt\"\xbc\x01\n" +
+	"\x10PullRequestShort\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x127\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1f.prmanager.v1.PullRequestStatusR\x06status\"\x8b\x01\n" +
+	"\x18CreatePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\"F\n" +
+	"\x19CreatePullRequestResponse\x12)\n" +
+	"\x02pr\x18\x01 \x01(\v2\x19.prmanager.v1.PullRequestR\x02pr\"A\n" +
+	"\x17MergePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\"E\n" +
+	"\x18MergePullRequestResponse\x12)\n" +
+	"\x02pr\x18\x01 \x01(\v2\x19.prmanager.v1.PullRequestR\x02pr\"a\n" +
+	"\x17ReassignReviewerRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12\x1e\n" +
+	"\vold_user_id\x18\x02 \x01(\tR\toldUserId\"f\n" +
+	"\x18ReassignReviewerResponse\x12)\n" +
+	"\x02pr\x18\x01 \x01(\v2\x19.prmanager.v1.PullRequestR\x02pr\x12\x1f\n" +
+	"\vreplaced_by\x18\x02 \x01(\tR\n" +
+	"replacedBy\":\n" +
+	"\x1fListReviewerPullRequestsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x80\x01\n" +
+	" ListReviewerPullRequestsResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12C\n" +
+	"\rpull_requests\x18\x02 \x03(\v2\x1e.prmanager.v1.PullRequestShortR\fpullRequests\"<\n" +
+	"!StreamReviewerPullRequestsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"g\n" +
+	"\"StreamReviewerPullRequestsResponse\x12A\n" +
+	"\fpull_request\x18\x01 \x01(\v2\x1e.prmanager.v1.PullRequestShortR\vpullRequest\"V\n" +
+	"\x1cDeactivateTeamMembersRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"U\n" +
+	"\x13ReviewerReplacement\x12\x1e\n" +
+	"\vold_user_id\x18\x01 \x01(\tR\toldUserId\x12\x1e\n" +
+	"\vnew_user_id\x18\x02 \x01(\tR\tnewUserId\"\x8c\x01\n" +
+	"\x1bTeamPullRequestReassignment\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12E\n" +
+	"\freplacements\x18\x02 \x03(\v2!.prmanager.v1.ReviewerReplacementR\freplacements\"\xaf\x01\n" +
+	"\x1dDeactivateTeamMembersResponse\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12 \n" +
+	"\vdeactivated\x18\x02 \x03(\tR\vdeactivated\x12O\n" +
+	"\rreassignments\x18\x03 \x03(\v2).prmanager.v1.TeamPullRequestReassignmentR\rreassignments\"\xd6\x01\n" +
+	"\x15AssignmentStatsFilter\x12\x12\n" +
+	"\x04team\x18\x01 \x01(\tR\x04team\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x127\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1f.prmanager.v1.PullRequestStatusR\x06status\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"k\n" +
+	"\x12UserAssignmentStat\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12 \n" +
+	"\vassignments\x18\x03 \x01(\x05R\vassignments\"\x96\x01\n" +
+	"\x19PullRequestAssignmentStat\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12%\n" +
+	"\x0ereviewer_count\x18\x03 \x01(\x05R\rreviewerCount\"\xbf\x01\n" +
+	"\x12TeamAssignmentStat\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12\x1d\n" +
+	"\n" +
+	"open_count\x18\x02 \x01(\x05R\topenCount\x12!\n" +
+	"\fmerged_count\x18\x03 \x01(\x05R\vmergedCount\x12#\n" +
+	"\ravg_reviewers\x18\x04 \x01(\x01R\favgReviewers\x12%\n" +
+	"\x0eload_imbalance\x18\x05 \x01(\x01R\rloadImbalance\"X\n" +
+	"\x19GetAssignmentStatsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.prmanager.v1.AssignmentStatsFilterR\x06filter\"\xe3\x01\n" +
+	"\x1aGetAssignmentStatsResponse\x129\n" +
+	"\aby_user\x18\x01 \x03(\v2 .prmanager.v1.UserAssignmentStatR\x06byUser\x12O\n" +
+	"\x0fby_pull_request\x18\x02 \x03(\v2'.prmanager.v1.PullRequestAssignmentStatR\rbyPullRequest\x129\n" +
+	"\aby_team\x18\x03 \x03(\v2 .prmanager.v1.TeamAssignmentStatR\x06byTeam\"]\n" +
+	"\x1eListTeamAssignmentStatsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.prmanager.v1.AssignmentStatsFilterR\x06filter\"Y\n" +
+	"\x1fListTeamAssignmentStatsResponse\x126\n" +
+	"\x05teams\x18\x01 \x03(\v2 .prmanager.v1.TeamAssignmentStatR\x05teams\"[\n" +
+	"\x1cStreamUserAssignmentsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.prmanager.v1.AssignmentStatsFilterR\x06filter\"U\n" +
+	"\x1dStreamUserAssignmentsResponse\x124\n" +
+	"\x04stat\x18\x01 \x01(\v2 .prmanager.v1.UserAssignmentStatR\x04stat\"b\n" +
+	"#StreamPullRequestAssignmentsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.prmanager.v1.AssignmentStatsFilterR\x06filter\"c\n" +
+	"$StreamPullRequestAssignmentsResponse\x12;\n" +
+	"\x04stat\x18\x01 \x01(\v2'.prmanager.v1.PullRequestAssignmentStatR\x04stat\"w\n" +
+	"\x19GetTurnaroundStatsRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\x90\x01\n" +
+	"\x15TurnaroundPercentiles\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12\x1f\n" +
+	"\vp50_seconds\x18\x02 \x01(\x01R\n" +
+	"p50Seconds\x12\x1f\n" +
+	"\vp90_seconds\x18\x03 \x01(\x01R\n" +
+	"p90Seconds\x12\x1f\n" +
+	"\vp99_seconds\x18\x04 \x01(\x01R\n" +
+	"p99Seconds\"t\n" +
+	"\x0eTeamTurnaround\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12E\n" +
+	"\vpercentiles\x18\x02 \x01(\v2#.prmanager.v1.TurnaroundPercentilesR\vpercentiles\"p\n" +
+	"\x0eUserTurnaround\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12E\n" +
+	"\vpercentiles\x18\x02 \x01(\v2#.prmanager.v1.TurnaroundPercentilesR\vpercentiles\"\xe8\x02\n" +
+	"\x1aGetTurnaroundStatsResponse\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12=\n" +
+	"\aoverall\x18\x03 \x01(\v2#.prmanager.v1.TurnaroundPercentilesR\aoverall\x125\n" +
+	"\aby_team\x18\x04 \x03(\v2\x1c.prmanager.v1.TeamTurnaroundR\x06byTeam\x129\n" +
+	"\tby_author\x18\x05 \x03(\v2\x1c.prmanager.v1.UserTurnaroundR\bbyAuthor\x12=\n" +
+	"\vby_reviewer\x18\x06 \x03(\v2\x1c.prmanager.v1.UserTurnaroundR\n" +
+	"byReviewer\"8\n" +
+	"\x0eAddTeamRequest\x12&\n" +
+	"\x04team\x18\x01 \x01(\v2\x12.prmanager.v1.TeamR\x04team\"9\n" +
+	"\x0fAddTeamResponse\x12&\n" +
+	"\x04team\x18\x01 \x01(\v2\x12.prmanager.v1.TeamR\x04team\"-\n" +
+	"\x0eGetTeamRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\"9\n" +
+	"\x0fGetTeamResponse\x12&\n" +
+	"\x04team\x18\x01 \x01(\v2\x12.prmanager.v1.TeamR\x04team\"L\n" +
+	"\x14SetUserActiveRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tis_active\x18\x02 \x01(\bR\bisActive\"?\n" +
+	"\x15SetUserActiveResponse\x12&\n" +
+	"\x04user\x18\x01 \x01(\v2\x12.prmanager.v1.UserR\x04user\"\x91\x01\n" +
+	"\rImportUserRow\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x12 \n" +
+	"\tis_active\x18\x04 \x01(\bH\x00R\bisActive\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_active\"\x7f\n" +
+	"\x12ImportUsersRequest\x12/\n" +
+	"\x04rows\x18\x01 \x03(\v2\x1b.prmanager.v1.ImportUserRowR\x04rows\x12\x1f\n" +
+	"\von_conflict\x18\x02 \x01(\tR\n" +
+	"onConflict\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"j\n" +
+	"\x0fImportRowResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xa0\x02\n" +
+	"\fImportReport\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\x12\x1f\n" +
+	"\von_conflict\x18\x03 \x01(\tR\n" +
+	"onConflict\x12#\n" +
+	"\rteams_created\x18\x04 \x03(\tR\fteamsCreated\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\x06 \x01(\x05R\aupdated\x12\x18\n" +
+	"\askipped\x18\a \x01(\x05R\askipped\x12\x16\n" +
+	"\x06failed\x18\b \x01(\x05R\x06failed\x121\n" +
+	"\x04rows\x18\t \x03(\v2\x1d.prmanager.v1.ImportRowResultR\x04rows\"I\n" +
+	"\x13ImportUsersResponse\x122\n" +
+	"\x06report\x18\x01 \x01(\v2\x1a.prmanager.v1.ImportReportR\x06report*v\n" +
+	"\x11PullRequestStatus\x12#\n" +
+	"\x1fPULL_REQUEST_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PULL_REQUEST_STATUS_OPEN\x10\x01\x12\x1e\n" +
+	"\x1aPULL_REQUEST_STATUS_MERGED\x10\x022\xf9\t\n" +
+	"\x12PullRequestService\x12d\n" +
+	"\x11CreatePullRequest\x12&.prmanager.v1.CreatePullRequestRequest\x1a'.prmanager.v1.CreatePullRequestResponse\x12a\n" +
+	"\x10MergePullRequest\x12%.prmanager.v1.MergePullRequestRequest\x1a&.prmanager.v1.MergePullRequestResponse\x12a\n" +
+	"\x10ReassignReviewer\x12%.prmanager.v1.ReassignReviewerRequest\x1a&.prmanager.v1.ReassignReviewerResponse\x12y\n" +
+	"\x18ListReviewerPullRequests\x12-.prmanager.v1.ListReviewerPullRequestsRequest\x1a..prmanager.v1.ListReviewerPullRequestsResponse\x12\x81\x01\n" +
+	"\x1aStreamReviewerPullRequests\x12/.prmanager.v1.StreamReviewerPullRequestsRequest\x1a0.prmanager.v1.StreamReviewerPullRequestsResponse0\x01\x12p\n" +
+	"\x15DeactivateTeamMembers\x12*.prmanager.v1.DeactivateTeamMembersRequest\x1a+.prmanager.v1.DeactivateTeamMembersResponse\x12g\n" +
+	"\x12GetAssignmentStats\x12'.prmanager.v1.GetAssignmentStatsRequest\x1a(.prmanager.v1.GetAssignmentStatsResponse\x12v\n" +
+	"\x17ListTeamAssignmentStats\x12,.prmanager.v1.ListTeamAssignmentStatsRequest\x1a-.prmanager.v1.ListTeamAssignmentStatsResponse\x12r\n" +
+	"\x15StreamUserAssignments\x12*.prmanager.v1.StreamUserAssignmentsRequest\x1a+.prmanager.v1.StreamUserAssignmentsResponse0\x01\x12\x87\x01\n" +
+	"\x1cStreamPullRequestAssignments\x121.prmanager.v1.StreamPullRequestAssignmentsRequest\x1a2.prmanager.v1.StreamPullRequestAssignmentsResponse0\x01\x12g\n" +
+	"\x12GetTurnaroundStats\x12'.prmanager.v1.GetTurnaroundStatsRequest\x1a(.prmanager.v1.GetTurnaroundStatsResponse2\xcf\x02\n" +
+	"\x0fUserTeamService\x12F\n" +
+	"\aAddTeam\x12\x1c.prmanager.v1.AddTeamRequest\x1a\x1d.prmanager.v1.AddTeamResponse\x12F\n" +
+	"\aGetTeam\x12\x1c.prmanager.v1.GetTeamRequest\x1a\x1d.prmanager.v1.GetTeamResponse\x12X\n" +
+	"\rSetUserActive\x12\".prmanager.v1.SetUserActiveRequest\x1a#.prmanager.v1.SetUserActiveResponse\x12R\n" +
+	"\vImportUsers\x12 .prmanager.v1.ImportUsersRequest\x1a!.prmanager.v1.ImportUsersResponseBKZIgithub.com/AlekseyZapadovnikov/pr-manager/pkg/pb/prmanager/v1;prmanagerv1b\x06proto3"
+
+var (
+	file_prmanager_v1_prmanager_proto_rawDescOnce sync.Once
+	file_prmanager_v1_prmanager_proto_rawDescData []byte
+)
+
+func file_prmanager_v1_prmanager_proto_rawDescGZIP() []byte {
+	file_prmanager_v1_prmanager_proto_rawDescOnce.Do(func() {
+		file_prmanager_v1_prmanager_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_prmanager_v1_prmanager_proto_rawDesc), len(file_prmanager_v1_prmanager_proto_rawDesc)))
+	})
+	return file_prmanager_v1_prmanager_proto_rawDescData
+}
+
+var file_prmanager_v1_prmanager_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_prmanager_v1_prmanager_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
+var file_prmanager_v1_prmanager_proto_goTypes = []any{
+	(PullRequestStatus)(0),                       // 0: prmanager.v1.PullRequestStatus
+	(*TeamMember)(nil),                           // 1: prmanager.v1.TeamMember
+	(*Team)(nil),                                 // 2: prmanager.v1.Team
+	(*User)(nil),                                 // 3: prmanager.v1.User
+	(*PullRequest)(nil),                          // 4: prmanager.v1.PullRequest
+	(*PullRequestShort)(nil),                     // 5: prmanager.v1.PullRequestShort
+	(*CreatePullRequestRequest)(nil),             // 6: prmanager.v1.CreatePullRequestRequest
+	(*CreatePullRequestResponse)(nil),            // 7: prmanager.v1.CreatePullRequestResponse
+	(*MergePullRequestRequest)(nil),              // 8: prmanager.v1.MergePullRequestRequest
+	(*MergePullRequestResponse)(nil),             // 9: prmanager.v1.MergePullRequestResponse
+	(*ReassignReviewerRequest)(nil),              // 10: prmanager.v1.ReassignReviewerRequest
+	(*ReassignReviewerResponse)(nil),             // 11: prmanager.v1.ReassignReviewerResponse
+	(*ListReviewerPullRequestsRequest)(nil),      // 12: prmanager.v1.ListReviewerPullRequestsRequest
+	(*ListReviewerPullRequestsResponse)(nil),     // 13: prmanager.v1.ListReviewerPullRequestsResponse
+	(*StreamReviewerPullRequestsRequest)(nil),    // 14: prmanager.v1.StreamReviewerPullRequestsRequest
+	(*StreamReviewerPullRequestsResponse)(nil),   // 15: prmanager.v1.StreamReviewerPullRequestsResponse
+	(*DeactivateTeamMembersRequest)(nil),         // 16: prmanager.v1.DeactivateTeamMembersRequest
+	(*ReviewerReplacement)(nil),                  // 17: prmanager.v1.ReviewerReplacement
+	(*TeamPullRequestReassignment)(nil),          // 18: prmanager.v1.TeamPullRequestReassignment
+	(*DeactivateTeamMembersResponse)(nil),        // 19: prmanager.v1.DeactivateTeamMembersResponse
+	(*AssignmentStatsFilter)(nil),                // 20: prmanager.v1.AssignmentStatsFilter
+	(*UserAssignmentStat)(nil),                   // 21: prmanager.v1.UserAssignmentStat
+	(*PullRequestAssignmentStat)(nil),            // 22: prmanager.v1.PullRequestAssignmentStat
+	(*TeamAssignmentStat)(nil),                   // 23: prmanager.v1.TeamAssignmentStat
+	(*GetAssignmentStatsRequest)(nil),            // 24: prmanager.v1.GetAssignmentStatsRequest
+	(*GetAssignmentStatsResponse)(nil),           // 25: prmanager.v1.GetAssignmentStatsResponse
+	(*ListTeamAssignmentStatsRequest)(nil),       // 26: prmanager.v1.ListTeamAssignmentStatsRequest
+	(*ListTeamAssignmentStatsResponse)(nil),      // 27: prmanager.v1.ListTeamAssignmentStatsResponse
+	(*StreamUserAssignmentsRequest)(nil),         // 28: prmanager.v1.StreamUserAssignmentsRequest
+	(*StreamUserAssignmentsResponse)(nil),        // 29: prmanager.v1.StreamUserAssignmentsResponse
+	(*StreamPullRequestAssignmentsRequest)(nil),  // 30: prmanager.v1.StreamPullRequestAssignmentsRequest
+	(*StreamPullRequestAssignmentsResponse)(nil), // 31: prmanager.v1.StreamPullRequestAssignmentsResponse
+	(*GetTurnaroundStatsRequest)(nil),            // 32: prmanager.v1.GetTurnaroundStatsRequest
+	(*TurnaroundPercentiles)(nil),                // 33: prmanager.v1.TurnaroundPercentiles
+	(*TeamTurnaround)(nil),                       // 34: prmanager.v1.TeamTurnaround
+	(*UserTurnaround)(nil),                       // 35: prmanager.v1.UserTurnaround
+	(*GetTurnaroundStatsResponse)(nil),           // 36: prmanager.v1.GetTurnaroundStatsResponse
+	(*AddTeamRequest)(nil),                       // 37: prmanager.v1.AddTeamRequest
+	(*AddTeamResponse)(nil),                      // 38: prmanager.v1.AddTeamResponse
+	(*GetTeamRequest)(nil),                       // 39: prmanager.v1.GetTeamRequest
+	(*GetTeamResponse)(nil),                      // 40: prmanager.v1.GetTeamResponse
+	(*SetUserActiveRequest)(nil),                 // 41: prmanager.v1.SetUserActiveRequest
+	(*SetUserActiveResponse)(nil),                // 42: prmanager.v1.SetUserActiveResponse
+	(*ImportUserRow)(nil),                        // 43: prmanager.v1.ImportUserRow
+	(*ImportUsersRequest)(nil),                   // 44: prmanager.v1.ImportUsersRequest
+	(*ImportRowResult)(nil),                      // 45: prmanager.v1.ImportRowResult
+	(*ImportReport)(nil),                         // 46: prmanager.v1.ImportReport
+	(*ImportUsersResponse)(nil),                  // 47: prmanager.v1.ImportUsersResponse
+	(*timestamppb.Timestamp)(nil),                // 48: google.protobuf.Timestamp
+}
+var file_prmanager_v1_prmanager_proto_depIdxs = []int32{
+	1,  // 0: prmanager.v1.Team.members:type_name -> prmanager.v1.TeamMember
+	0,  // 1: prmanager.v1.PullRequest.status:type_name -> prmanager.v1.PullRequestStatus
+	48, // 2: prmanager.v1.PullRequest.created_at:type_name -> google.protobuf.Timestamp
+	48, // 3: prmanager.v1.PullRequest.merged_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: prmanager.v1.PullRequestShort.status:type_name -> prmanager.v1.PullRequestStatus
+	4,  // 5: prmanager.v1.CreatePullRequestResponse.pr:type_name -> prmanager.v1.PullRequest
+	4,  // 6: prmanager.v1.MergePullRequestResponse.pr:type_name -> prmanager.v1.PullRequest
+	4,  // 7: prmanager.v1.ReassignReviewerResponse.pr:type_name -> prmanager.v1.PullRequest
+	5,  // 8: prmanager.v1.ListReviewerPullRequestsResponse.pull_requests:type_name -> prmanager.v1.PullRequestShort
+	5,  // 9: prmanager.v1.StreamReviewerPullRequestsResponse.pull_request:type_name -> prmanager.v1.PullRequestShort
+	17, // 10: prmanager.v1.TeamPullRequestReassignment.replacements:type_name -> prmanager.v1.ReviewerReplacement
+	18, // 11: prmanager.v1.DeactivateTeamMembersResponse.reassignments:type_name -> prmanager.v1.TeamPullRequestReassignment
+	48, // 12: prmanager.v1.AssignmentStatsFilter.from:type_name -> google.protobuf.Timestamp
+	48, // 13: prmanager.v1.AssignmentStatsFilter.to:type_name -> google.protobuf.Timestamp
+	0,  // 14: prmanager.v1.AssignmentStatsFilter.status:type_name -> prmanager.v1.PullRequestStatus
+	20, // 15: prmanager.v1.GetAssignmentStatsRequest.filter:type_name -> prmanager.v1.AssignmentStatsFilter
+	21, // 16: prmanager.v1.GetAssignmentStatsResponse.by_user:type_name -> prmanager.v1.UserAssignmentStat
+	22, // 17: prmanager.v1.GetAssignmentStatsResponse.by_pull_request:type_name -> prmanager.v1.PullRequestAssignmentStat
+	23, // 18: prmanager.v1.GetAssignmentStatsResponse.by_team:type_name -> prmanager.v1.TeamAssignmentStat
+	20, // 19: prmanager.v1.ListTeamAssignmentStatsRequest.filter:type_name -> prmanager.v1.AssignmentStatsFilter
+	23, // 20: prmanager.v1.ListTeamAssignmentStatsResponse.teams:type_name -> prmanager.v1.TeamAssignmentStat
+	20, // 21: prmanager.v1.StreamUserAssignmentsRequest.filter:type_name -> prmanager.v1.AssignmentStatsFilter
+	21, // 22: prmanager.v1.StreamUserAssignmentsResponse.stat:type_name -> prmanager.v1.UserAssignmentStat
+	20, // 23: prmanager.v1.StreamPullRequestAssignmentsRequest.filter:type_name -> prmanager.v1.AssignmentStatsFilter
+	22, // 24: prmanager.v1.StreamPullRequestAssignmentsResponse.stat:type_name -> prmanager.v1.PullRequestAssignmentStat
+	48, // 25: prmanager.v1.GetTurnaroundStatsRequest.from:type_name -> google.protobuf.Timestamp
+	48, // 26: prmanager.v1.GetTurnaroundStatsRequest.to:type_name -> google.protobuf.Timestamp
+	33, // 27: prmanager.v1.TeamTurnaround.percentiles:type_name -> prmanager.v1.TurnaroundPercentiles
+	33, // 28: prmanager.v1.UserTurnaround.percentiles:type_name -> prmanager.v1.TurnaroundPercentiles
+	48, // 29: prmanager.v1.GetTurnaroundStatsResponse.from:type_name -> google.protobuf.Timestamp
+	48, // 30: prmanager.v1.GetTurnaroundStatsResponse.to:type_name -> google.protobuf.Timestamp
+	33, // 31: prmanager.v1.GetTurnaroundStatsResponse.overall:type_name -> prmanager.v1.TurnaroundPercentiles
+	34, // 32: prmanager.v1.GetTurnaroundStatsResponse.by_team:type_name -> prmanager.v1.TeamTurnaround
+	35, // 33: prmanager.v1.GetTurnaroundStatsResponse.by_author:type_name -> prmanager.v1.UserTurnaround
+	35, // 34: prmanager.v1.GetTurnaroundStatsResponse.by_reviewer:type_name -> prmanager.v1.UserTurnaround
+	2,  // 35: prmanager.v1.AddTeamRequest.team:type_name -> prmanager.v1.Team
+	2,  // 36: prmanager.v1.AddTeamResponse.team:type_name -> prmanager.v1.Team
+	2,  // 37: prmanager.v1.GetTeamResponse.team:type_name -> prmanager.v1.Team
+	3,  // 38: prmanager.v1.SetUserActiveResponse.user:type_name -> prmanager.v1.User
+	43, // 39: prmanager.v1.ImportUsersRequest.rows:type_name -> prmanager.v1.ImportUserRow
+	45, // 40: prmanager.v1.ImportReport.rows:type_name -> prmanager.v1.ImportRowResult
+	46, // 41: prmanager.v1.ImportUsersResponse.report:type_name -> prmanager.v1.ImportReport
+	6,  // 42: prmanager.v1.PullRequestService.CreatePullRequest:input_type -> prmanager.v1.CreatePullRequestRequest
+	8,  // 43: prmanager.v1.PullRequestService.MergePullRequest:input_type -> prmanager.v1.MergePullRequestRequest
+	10, // 44: prmanager.v1.PullRequestService.ReassignReviewer:input_type -> prmanager.v1.ReassignReviewerRequest
+	12, // 45: prmanager.v1.PullRequestService.ListReviewerPullRequests:input_type -> prmanager.v1.ListReviewerPullRequestsRequest
+	14, // 46: prmanager.v1.PullRequestService.StreamReviewerPullRequests:input_type -> prmanager.v1.StreamReviewerPullRequestsRequest
+	16, // 47: prmanager.v1.PullRequestService.DeactivateTeamMembers:input_type -> prmanager.v1.DeactivateTeamMembersRequest
+	24, // 48: prmanager.v1.PullRequestService.GetAssignmentStats:input_type -> prmanager.v1.GetAssignmentStatsRequest
+	26, // 49: prmanager.v1.PullRequestService.ListTeamAssignmentStats:input_type -> prmanager.v1.ListTeamAssignmentStatsRequest
+	28, // 50: prmanager.v1.PullRequestService.StreamUserAssignments:input_type -> prmanager.v1.StreamUserAssignmentsRequest
+	30, // 51: prmanager.v1.PullRequestService.StreamPullRequestAssignments:input_type -> prmanager.v1.StreamPullRequestAssignmentsRequest
+	32, // 52: prmanager.v1.PullRequestService.GetTurnaroundStats:input_type -> prmanager.v1.GetTurnaroundStatsRequest
+	37, // 53: prmanager.v1.UserTeamService.AddTeam:input_type -> prmanager.v1.AddTeamRequest
+	39, // 54: prmanager.v1.UserTeamService.GetTeam:input_type -> prmanager.v1.GetTeamRequest
+	41, // 55: prmanager.v1.UserTeamService.SetUserActive:input_type -> prmanager.v1.SetUserActiveRequest
+	44, // 56: prmanager.v1.UserTeamService.ImportUsers:input_type -> prmanager.v1.ImportUsersRequest
+	7,  // 57: prmanager.v1.PullRequestService.CreatePullRequest:output_type -> prmanager.v1.CreatePullRequestResponse
+	9,  // 58: prmanager.v1.PullRequestService.MergePullRequest:output_type -> prmanager.v1.MergePullRequestResponse
+	11, // 59: prmanager.v1.PullRequestService.ReassignReviewer:output_type -> prmanager.v1.ReassignReviewerResponse
+	13, // 60: prmanager.v1.PullRequestService.ListReviewerPullRequests:output_type -> prmanager.v1.ListReviewerPullRequestsResponse
+	15, // 61: prmanager.v1.PullRequestService.StreamReviewerPullRequests:output_type -> prmanager.v1.StreamReviewerPullRequestsResponse
+	19, // 62: prmanager.v1.PullRequestService.DeactivateTeamMembers:output_type -> prmanager.v1.DeactivateTeamMembersResponse
+	25, // 63: prmanager.v1.PullRequestService.GetAssignmentStats:output_type -> prmanager.v1.GetAssignmentStatsResponse
+	27, // 64: prmanager.v1.PullRequestService.ListTeamAssignmentStats:output_type -> prmanager.v1.ListTeamAssignmentStatsResponse
+	29, // 65: prmanager.v1.PullRequestService.StreamUserAssignments:output_type -> prmanager.v1.StreamUserAssignmentsResponse
+	31, // 66: prmanager.v1.PullRequestService.StreamPullRequestAssignments:output_type -> prmanager.v1.StreamPullRequestAssignmentsResponse
+	36, // 67: prmanager.v1.PullRequestService.GetTurnaroundStats:output_type -> prmanager.v1.GetTurnaroundStatsResponse
+	38, // 68: prmanager.v1.UserTeamService.AddTeam:output_type -> prmanager.v1.AddTeamResponse
+	40, // 69: prmanager.v1.UserTeamService.GetTeam:output_type -> prmanager.v1.GetTeamResponse
+	42, // 70: prmanager.v1.UserTeamService.SetUserActive:output_type -> prmanager.v1.SetUserActiveResponse
+	47, // 71: prmanager.v1.UserTeamService.ImportUsers:output_type -> prmanager.v1.ImportUsersResponse
+	57, // [57:72] is the sub-list for method output_type
+	42, // [42:57] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
+}
+
+func init() { file_prmanager_v1_prmanager_proto_init() }
+func file_prmanager_v1_prmanager_proto_init() {
+	if File_prmanager_v1_prmanager_proto != nil {
+		return
+	}
+	file_prmanager_v1_prmanager_proto_msgTypes[42].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_prmanager_v1_prmanager_proto_rawDesc), len(file_prmanager_v1_prmanager_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   47,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_prmanager_v1_prmanager_proto_goTypes,
+		DependencyIndexes: file_prmanager_v1_prmanager_proto_depIdxs,
+		EnumInfos:         file_prmanager_v1_prmanager_proto_enumTypes,
+		MessageInfos:      file_prmanager_v1_prmanager_proto_msgTypes,
+	}.Build()
+	File_prmanager_v1_prmanager_proto = out.File
+	file_prmanager_v1_prmanager_proto_goTypes = nil
+	file_prmanager_v1_prmanager_proto_depIdxs = nil
+}
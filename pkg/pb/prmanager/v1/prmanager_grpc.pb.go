@@ -0,0 +1,768 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: prmanager/v1/prmanager.proto
+
+// Контракт gRPC API pr-manager. Операции повторяют HTTP API из api/openapi.yml
+// и выполняются тем же сервисным слоем.
+
+package prmanagerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PullRequestService_CreatePullRequest_FullMethodName            = "/prmanager.v1.PullRequestService/CreatePullRequest"
+	PullRequestService_MergePullRequest_FullMethodName             = "/prmanager.v1.PullRequestService/MergePullRequest"
+	PullRequestService_ReassignReviewer_FullMethodName             = "/prmanager.v1.PullRequestService/ReassignReviewer"
+	PullRequestService_ListReviewerPullRequests_FullMethodName     = "/prmanager.v1.PullRequestService/ListReviewerPullRequests"
+	PullRequestService_StreamReviewerPullRequests_FullMethodName   = "/prmanager.v1.PullRequestService/StreamReviewerPullRequests"
+	PullRequestService_DeactivateTeamMembers_FullMethodName        = "/prmanager.v1.PullRequestService/DeactivateTeamMembers"
+	PullRequestService_GetAssignmentStats_FullMethodName           = "/prmanager.v1.PullRequestService/GetAssignmentStats"
+	PullRequestService_ListTeamAssignmentStats_FullMethodName      = "/prmanager.v1.PullRequestService/ListTeamAssignmentStats"
+	PullRequestService_StreamUserAssignments_FullMethodName        = "/prmanager.v1.PullRequestService/StreamUserAssignments"
+	PullRequestService_StreamPullRequestAssignments_FullMethodName = "/prmanager.v1.PullRequestService/StreamPullRequestAssignments"
+	PullRequestService_GetTurnaroundStats_FullMethodName           = "/prmanager.v1.PullRequestService/GetTurnaroundStats"
+)
+
+// PullRequestServiceClient is the client API for PullRequestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PullRequestService — операции над pull request, назначениями и статистикой.
+type PullRequestServiceClient interface {
+	// CreatePullRequest создаёт PR и назначает до двух ревьюверов из команды автора.
+	CreatePullRequest(ctx context.Context, in *CreatePullRequestRequest, opts ...grpc.CallOption) (*CreatePullRequestResponse, error)
+	// MergePullRequest помечает PR как MERGED; повторный вызов возвращает тот же PR.
+	MergePullRequest(ctx context.Context, in *MergePullRequestRequest, opts ...grpc.CallOption) (*MergePullRequestResponse, error)
+	// ReassignReviewer заменяет ревьювера другим участником его команды.
+	ReassignReviewer(ctx context.Context, in *ReassignReviewerRequest, opts ...grpc.CallOption) (*ReassignReviewerResponse, error)
+	// ListReviewerPullRequests возвращает PR, где пользователь назначен ревьювером.
+	ListReviewerPullRequests(ctx context.Context, in *ListReviewerPullRequestsRequest, opts ...grpc.CallOption) (*ListReviewerPullRequestsResponse, error)
+	// StreamReviewerPullRequests отдаёт те же PR потоком, читая их из хранилища по мере отправки.
+	StreamReviewerPullRequests(ctx context.Context, in *StreamReviewerPullRequestsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamReviewerPullRequestsResponse], error)
+	// DeactivateTeamMembers массово деактивирует участников команды и переназначает их открытые PR.
+	DeactivateTeamMembers(ctx context.Context, in *DeactivateTeamMembersRequest, opts ...grpc.CallOption) (*DeactivateTeamMembersResponse, error)
+	// GetAssignmentStats возвращает статистику назначений по ревьюверам, PR и командам.
+	GetAssignmentStats(ctx context.Context, in *GetAssignmentStatsRequest, opts ...grpc.CallOption) (*GetAssignmentStatsResponse, error)
+	// ListTeamAssignmentStats возвращает только срез по командам.
+	ListTeamAssignmentStats(ctx context.Context, in *ListTeamAssignmentStatsRequest, opts ...grpc.CallOption) (*ListTeamAssignmentStatsResponse, error)
+	// StreamUserAssignments отдаёт срез по ревьюверам потоком.
+	StreamUserAssignments(ctx context.Context, in *StreamUserAssignmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamUserAssignmentsResponse], error)
+	// StreamPullRequestAssignments отдаёт срез по PR потоком.
+	StreamPullRequestAssignments(ctx context.Context, in *StreamPullRequestAssignmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamPullRequestAssignmentsResponse], error)
+	// GetTurnaroundStats возвращает перцентили времени от создания до слияния PR.
+	GetTurnaroundStats(ctx context.Context, in *GetTurnaroundStatsRequest, opts ...grpc.CallOption) (*GetTurnaroundStatsResponse, error)
+}
+
+type pullRequestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPullRequestServiceClient(cc grpc.ClientConnInterface) PullRequestServiceClient {
+	return &pullRequestServiceClient{cc}
+}
+
+func (c *pullRequestServiceClient) CreatePullRequest(ctx context.Context, in *CreatePullRequestRequest, opts ...grpc.CallOption) (*CreatePullRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePullRequestResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_CreatePullRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) MergePullRequest(ctx context.Context, in *MergePullRequestRequest, opts ...grpc.CallOption) (*MergePullRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergePullRequestResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_MergePullRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) ReassignReviewer(ctx context.Context, in *ReassignReviewerRequest, opts ...grpc.CallOption) (*ReassignReviewerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReassignReviewerResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_ReassignReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) ListReviewerPullRequests(ctx context.Context, in *ListReviewerPullRequestsRequest, opts ...grpc.CallOption) (*ListReviewerPullRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewerPullRequestsResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_ListReviewerPullRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) StreamReviewerPullRequests(ctx context.Context, in *StreamReviewerPullRequestsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamReviewerPullRequestsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PullRequestService_ServiceDesc.Streams[0], PullRequestService_StreamReviewerPullRequests_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamReviewerPullRequestsRequest, StreamReviewerPullRequestsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamReviewerPullRequestsClient = grpc.ServerStreamingClient[StreamReviewerPullRequestsResponse]
+
+func (c *pullRequestServiceClient) DeactivateTeamMembers(ctx context.Context, in *DeactivateTeamMembersRequest, opts ...grpc.CallOption) (*DeactivateTeamMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeactivateTeamMembersResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_DeactivateTeamMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) GetAssignmentStats(ctx context.Context, in *GetAssignmentStatsRequest, opts ...grpc.CallOption) (*GetAssignmentStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAssignmentStatsResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_GetAssignmentStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) ListTeamAssignmentStats(ctx context.Context, in *ListTeamAssignmentStatsRequest, opts ...grpc.CallOption) (*ListTeamAssignmentStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTeamAssignmentStatsResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_ListTeamAssignmentStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestServiceClient) StreamUserAssignments(ctx context.Context, in *StreamUserAssignmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamUserAssignmentsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PullRequestService_ServiceDesc.Streams[1], PullRequestService_StreamUserAssignments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamUserAssignmentsRequest, StreamUserAssignmentsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamUserAssignmentsClient = grpc.ServerStreamingClient[StreamUserAssignmentsResponse]
+
+func (c *pullRequestServiceClient) StreamPullRequestAssignments(ctx context.Context, in *StreamPullRequestAssignmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamPullRequestAssignmentsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PullRequestService_ServiceDesc.Streams[2], PullRequestService_StreamPullRequestAssignments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPullRequestAssignmentsRequest, StreamPullRequestAssignmentsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamPullRequestAssignmentsClient = grpc.ServerStreamingClient[StreamPullRequestAssignmentsResponse]
+
+func (c *pullRequestServiceClient) GetTurnaroundStats(ctx context.Context, in *GetTurnaroundStatsRequest, opts ...grpc.CallOption) (*GetTurnaroundStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTurnaroundStatsResponse)
+	err := c.cc.Invoke(ctx, PullRequestService_GetTurnaroundStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PullRequestServiceServer is the server API for PullRequestService service.
+// All implementations must embed UnimplementedPullRequestServiceServer
+// for forward compatibility.
+//
+// PullRequestService — операции над pull request, назначениями и статистикой.
+type PullRequestServiceServer interface {
+	// CreatePullRequest создаёт PR и назначает до двух ревьюверов из команды автора.
+	CreatePullRequest(context.Context, *CreatePullRequestRequest) (*CreatePullRequestResponse, error)
+	// MergePullRequest помечает PR как MERGED; повторный вызов возвращает тот же PR.
+	MergePullRequest(context.Context, *MergePullRequestRequest) (*MergePullRequestResponse, error)
+	// ReassignReviewer заменяет ревьювера другим участником его команды.
+	ReassignReviewer(context.Context, *ReassignReviewerRequest) (*ReassignReviewerResponse, error)
+	// ListReviewerPullRequests возвращает PR, где пользователь назначен ревьювером.
+	ListReviewerPullRequests(context.Context, *ListReviewerPullRequestsRequest) (*ListReviewerPullRequestsResponse, error)
+	// StreamReviewerPullRequests отдаёт те же PR потоком, читая их из хранилища по мере отправки.
+	StreamReviewerPullRequests(*StreamReviewerPullRequestsRequest, grpc.ServerStreamingServer[StreamReviewerPullRequestsResponse]) error
+	// DeactivateTeamMembers массово деактивирует участников команды и переназначает их открытые PR.
+	DeactivateTeamMembers(context.Context, *DeactivateTeamMembersRequest) (*DeactivateTeamMembersResponse, error)
+	// GetAssignmentStats возвращает статистику назначений по ревьюверам, PR и командам.
+	GetAssignmentStats(context.Context, *GetAssignmentStatsRequest) (*GetAssignmentStatsResponse, error)
+	// ListTeamAssignmentStats возвращает только срез по командам.
+	ListTeamAssignmentStats(context.Context, *ListTeamAssignmentStatsRequest) (*ListTeamAssignmentStatsResponse, error)
+	// StreamUserAssignments отдаёт срез по ревьюверам потоком.
+	StreamUserAssignments(*StreamUserAssignmentsRequest, grpc.ServerStreamingServer[StreamUserAssignmentsResponse]) error
+	// StreamPullRequestAssignments отдаёт срез по PR потоком.
+	StreamPullRequestAssignments(*StreamPullRequestAssignmentsRequest, grpc.ServerStreamingServer[StreamPullRequestAssignmentsResponse]) error
+	// GetTurnaroundStats возвращает перцентили времени от создания до слияния PR.
+	GetTurnaroundStats(context.Context, *GetTurnaroundStatsRequest) (*GetTurnaroundStatsResponse, error)
+	mustEmbedUnimplementedPullRequestServiceServer()
+}
+
+// UnimplementedPullRequestServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPullRequestServiceServer struct{}
+
+func (UnimplementedPullRequestServiceServer) CreatePullRequest(context.Context, *CreatePullRequestRequest) (*CreatePullRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePullRequest not implemented")
+}
+func (UnimplementedPullRequestServiceServer) MergePullRequest(context.Context, *MergePullRequestRequest) (*MergePullRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergePullRequest not implemented")
+}
+func (UnimplementedPullRequestServiceServer) ReassignReviewer(context.Context, *ReassignReviewerRequest) (*ReassignReviewerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReassignReviewer not implemented")
+}
+func (UnimplementedPullRequestServiceServer) ListReviewerPullRequests(context.Context, *ListReviewerPullRequestsRequest) (*ListReviewerPullRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewerPullRequests not implemented")
+}
+func (UnimplementedPullRequestServiceServer) StreamReviewerPullRequests(*StreamReviewerPullRequestsRequest, grpc.ServerStreamingServer[StreamReviewerPullRequestsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamReviewerPullRequests not implemented")
+}
+func (UnimplementedPullRequestServiceServer) DeactivateTeamMembers(context.Context, *DeactivateTeamMembersRequest) (*DeactivateTeamMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateTeamMembers not implemented")
+}
+func (UnimplementedPullRequestServiceServer) GetAssignmentStats(context.Context, *GetAssignmentStatsRequest) (*GetAssignmentStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssignmentStats not implemented")
+}
+func (UnimplementedPullRequestServiceServer) ListTeamAssignmentStats(context.Context, *ListTeamAssignmentStatsRequest) (*ListTeamAssignmentStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTeamAssignmentStats not implemented")
+}
+func (UnimplementedPullRequestServiceServer) StreamUserAssignments(*StreamUserAssignmentsRequest, grpc.ServerStreamingServer[StreamUserAssignmentsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUserAssignments not implemented")
+}
+func (UnimplementedPullRequestServiceServer) StreamPullRequestAssignments(*StreamPullRequestAssignmentsRequest, grpc.ServerStreamingServer[StreamPullRequestAssignmentsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPullRequestAssignments not implemented")
+}
+func (UnimplementedPullRequestServiceServer) GetTurnaroundStats(context.Context, *GetTurnaroundStatsRequest) (*GetTurnaroundStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTurnaroundStats not implemented")
+}
+func (UnimplementedPullRequestServiceServer) mustEmbedUnimplementedPullRequestServiceServer() {}
+func (UnimplementedPullRequestServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafePullRequestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PullRequestServiceServer will
+// result in compilation errors.
+type UnsafePullRequestServiceServer interface {
+	mustEmbedUnimplementedPullRequestServiceServer()
+}
+
+func RegisterPullRequestServiceServer(s grpc.ServiceRegistrar, srv PullRequestServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPullRequestServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PullRequestService_ServiceDesc, srv)
+}
+
+func _PullRequestService_CreatePullRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePullRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).CreatePullRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_CreatePullRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).CreatePullRequest(ctx, req.(*CreatePullRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_MergePullRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergePullRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).MergePullRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_MergePullRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).MergePullRequest(ctx, req.(*MergePullRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_ReassignReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).ReassignReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_ReassignReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).ReassignReviewer(ctx, req.(*ReassignReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_ListReviewerPullRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewerPullRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).ListReviewerPullRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_ListReviewerPullRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).ListReviewerPullRequests(ctx, req.(*ListReviewerPullRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_StreamReviewerPullRequests_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamReviewerPullRequestsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PullRequestServiceServer).StreamReviewerPullRequests(m, &grpc.GenericServerStream[StreamReviewerPullRequestsRequest, StreamReviewerPullRequestsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamReviewerPullRequestsServer = grpc.ServerStreamingServer[StreamReviewerPullRequestsResponse]
+
+func _PullRequestService_DeactivateTeamMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateTeamMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).DeactivateTeamMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_DeactivateTeamMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).DeactivateTeamMembers(ctx, req.(*DeactivateTeamMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_GetAssignmentStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssignmentStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).GetAssignmentStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_GetAssignmentStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).GetAssignmentStats(ctx, req.(*GetAssignmentStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_ListTeamAssignmentStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTeamAssignmentStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).ListTeamAssignmentStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_ListTeamAssignmentStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).ListTeamAssignmentStats(ctx, req.(*ListTeamAssignmentStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestService_StreamUserAssignments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUserAssignmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PullRequestServiceServer).StreamUserAssignments(m, &grpc.GenericServerStream[StreamUserAssignmentsRequest, StreamUserAssignmentsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamUserAssignmentsServer = grpc.ServerStreamingServer[StreamUserAssignmentsResponse]
+
+func _PullRequestService_StreamPullRequestAssignments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPullRequestAssignmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PullRequestServiceServer).StreamPullRequestAssignments(m, &grpc.GenericServerStream[StreamPullRequestAssignmentsRequest, StreamPullRequestAssignmentsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PullRequestService_StreamPullRequestAssignmentsServer = grpc.ServerStreamingServer[StreamPullRequestAssignmentsResponse]
+
+func _PullRequestService_GetTurnaroundStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTurnaroundStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestServiceServer).GetTurnaroundStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestService_GetTurnaroundStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestServiceServer).GetTurnaroundStats(ctx, req.(*GetTurnaroundStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PullRequestService_ServiceDesc is the grpc.ServiceDesc for PullRequestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PullRequestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prmanager.v1.PullRequestService",
+	HandlerType: (*PullRequestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePullRequest",
+			Handler:    _PullRequestService_CreatePullRequest_Handler,
+		},
+		{
+			MethodName: "MergePullRequest",
+			Handler:    _PullRequestService_MergePullRequest_Handler,
+		},
+		{
+			MethodName: "ReassignReviewer",
+			Handler:    _PullRequestService_ReassignReviewer_Handler,
+		},
+		{
+			MethodName: "ListReviewerPullRequests",
+			Handler:    _PullRequestService_ListReviewerPullRequests_Handler,
+		},
+		{
+			MethodName: "DeactivateTeamMembers",
+			Handler:    _PullRequestService_DeactivateTeamMembers_Handler,
+		},
+		{
+			MethodName: "GetAssignmentStats",
+			Handler:    _PullRequestService_GetAssignmentStats_Handler,
+		},
+		{
+			MethodName: "ListTeamAssignmentStats",
+			Handler:    _PullRequestService_ListTeamAssignmentStats_Handler,
+		},
+		{
+			MethodName: "GetTurnaroundStats",
+			Handler:    _PullRequestService_GetTurnaroundStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamReviewerPullRequests",
+			Handler:       _PullRequestService_StreamReviewerPullRequests_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamUserAssignments",
+			Handler:       _PullRequestService_StreamUserAssignments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamPullRequestAssignments",
+			Handler:       _PullRequestService_StreamPullRequestAssignments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "prmanager/v1/prmanager.proto",
+}
+
+const (
+	UserTeamService_AddTeam_FullMethodName       = "/prmanager.v1.UserTeamService/AddTeam"
+	UserTeamService_GetTeam_FullMethodName       = "/prmanager.v1.UserTeamService/GetTeam"
+	UserTeamService_SetUserActive_FullMethodName = "/prmanager.v1.UserTeamService/SetUserActive"
+	UserTeamService_ImportUsers_FullMethodName   = "/prmanager.v1.UserTeamService/ImportUsers"
+)
+
+// UserTeamServiceClient is the client API for UserTeamService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserTeamService — операции над командами и пользователями.
+type UserTeamServiceClient interface {
+	// AddTeam создаёт команду и создаёт или обновляет её участников.
+	AddTeam(ctx context.Context, in *AddTeamRequest, opts ...grpc.CallOption) (*AddTeamResponse, error)
+	// GetTeam возвращает команду с участниками.
+	GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*GetTeamResponse, error)
+	// SetUserActive меняет флаг активности пользователя.
+	SetUserActive(ctx context.Context, in *SetUserActiveRequest, opts ...grpc.CallOption) (*SetUserActiveResponse, error)
+	// ImportUsers создаёт недостающие команды и создаёт или обновляет пользователей одной транзакцией.
+	ImportUsers(ctx context.Context, in *ImportUsersRequest, opts ...grpc.CallOption) (*ImportUsersResponse, error)
+}
+
+type userTeamServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserTeamServiceClient(cc grpc.ClientConnInterface) UserTeamServiceClient {
+	return &userTeamServiceClient{cc}
+}
+
+func (c *userTeamServiceClient) AddTeam(ctx context.Context, in *AddTeamRequest, opts ...grpc.CallOption) (*AddTeamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTeamResponse)
+	err := c.cc.Invoke(ctx, UserTeamService_AddTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userTeamServiceClient) GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*GetTeamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTeamResponse)
+	err := c.cc.Invoke(ctx, UserTeamService_GetTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userTeamServiceClient) SetUserActive(ctx context.Context, in *SetUserActiveRequest, opts ...grpc.CallOption) (*SetUserActiveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserActiveResponse)
+	err := c.cc.Invoke(ctx, UserTeamService_SetUserActive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userTeamServiceClient) ImportUsers(ctx context.Context, in *ImportUsersRequest, opts ...grpc.CallOption) (*ImportUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportUsersResponse)
+	err := c.cc.Invoke(ctx, UserTeamService_ImportUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserTeamServiceServer is the server API for UserTeamService service.
+// All implementations must embed UnimplementedUserTeamServiceServer
+// for forward compatibility.
+//
+// UserTeamService — операции над командами и пользователями.
+type UserTeamServiceServer interface {
+	// AddTeam создаёт команду и создаёт или обновляет её участников.
+	AddTeam(context.Context, *AddTeamRequest) (*AddTeamResponse, error)
+	// GetTeam возвращает команду с участниками.
+	GetTeam(context.Context, *GetTeamRequest) (*GetTeamResponse, error)
+	// SetUserActive меняет флаг активности пользователя.
+	SetUserActive(context.Context, *SetUserActiveRequest) (*SetUserActiveResponse, error)
+	// ImportUsers создаёт недостающие команды и создаёт или обновляет пользователей одной транзакцией.
+	ImportUsers(context.Context, *ImportUsersRequest) (*ImportUsersResponse, error)
+	mustEmbedUnimplementedUserTeamServiceServer()
+}
+
+// UnimplementedUserTeamServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserTeamServiceServer struct{}
+
+func (UnimplementedUserTeamServiceServer) AddTeam(context.Context, *AddTeamRequest) (*AddTeamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTeam not implemented")
+}
+func (UnimplementedUserTeamServiceServer) GetTeam(context.Context, *GetTeamRequest) (*GetTeamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeam not implemented")
+}
+func (UnimplementedUserTeamServiceServer) SetUserActive(context.Context, *SetUserActiveRequest) (*SetUserActiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserActive not implemented")
+}
+func (UnimplementedUserTeamServiceServer) ImportUsers(context.Context, *ImportUsersRequest) (*ImportUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportUsers not implemented")
+}
+func (UnimplementedUserTeamServiceServer) mustEmbedUnimplementedUserTeamServiceServer() {}
+func (UnimplementedUserTeamServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeUserTeamServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserTeamServiceServer will
+// result in compilation errors.
+type UnsafeUserTeamServiceServer interface {
+	mustEmbedUnimplementedUserTeamServiceServer()
+}
+
+func RegisterUserTeamServiceServer(s grpc.ServiceRegistrar, srv UserTeamServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserTeamServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserTeamService_ServiceDesc, srv)
+}
+
+func _UserTeamService_AddTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserTeamServiceServer).AddTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserTeamService_AddTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserTeamServiceServer).AddTeam(ctx, req.(*AddTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserTeamService_GetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserTeamServiceServer).GetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserTeamService_GetTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserTeamServiceServer).GetTeam(ctx, req.(*GetTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserTeamService_SetUserActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserActiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserTeamServiceServer).SetUserActive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserTeamService_SetUserActive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserTeamServiceServer).SetUserActive(ctx, req.(*SetUserActiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserTeamService_ImportUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserTeamServiceServer).ImportUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserTeamService_ImportUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserTeamServiceServer).ImportUsers(ctx, req.(*ImportUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserTeamService_ServiceDesc is the grpc.ServiceDesc for UserTeamService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserTeamService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prmanager.v1.UserTeamService",
+	HandlerType: (*UserTeamServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddTeam",
+			Handler:    _UserTeamService_AddTeam_Handler,
+		},
+		{
+			MethodName: "GetTeam",
+			Handler:    _UserTeamService_GetTeam_Handler,
+		},
+		{
+			MethodName: "SetUserActive",
+			Handler:    _UserTeamService_SetUserActive_Handler,
+		},
+		{
+			MethodName: "ImportUsers",
+			Handler:    _UserTeamService_ImportUsers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "prmanager/v1/prmanager.proto",
+}
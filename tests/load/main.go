@@ -1,12 +1,10 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"math"
 	"math/rand"
@@ -23,7 +21,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
-	"github.com/AlekseyZapadovnikov/pr-manager/internal/models"
+	"github.com/AlekseyZapadovnikov/pr-manager/pkg/client"
 )
 
 type runConfig struct {
@@ -231,9 +229,16 @@ func run(cfg runConfig) error {
 	rand.Seed(time.Now().UnixNano())
 
 	ctx := context.Background()
-	client := &http.Client{Timeout: cfg.RequestTimeout}
+	// Повторы отключены, чтобы задержки и ошибки отражали каждый отдельный запрос.
+	api, err := client.New(cfg.BaseURL,
+		client.WithHTTPClient(&http.Client{Timeout: cfg.RequestTimeout}),
+		client.WithRetry(client.RetryPolicy{MaxAttempts: 1}),
+	)
+	if err != nil {
+		return err
+	}
 
-	if err := waitForHealthy(ctx, client, cfg.BaseURL, cfg.HealthTimeout); err != nil {
+	if err := waitForHealthy(ctx, api, cfg.HealthTimeout); err != nil {
 		return fmt.Errorf("service unhealthy: %w", err)
 	}
 	log.Printf("Service is healthy at %s", cfg.BaseURL)
@@ -252,7 +257,7 @@ func run(cfg runConfig) error {
 		BatchSize:      cfg.BatchSize,
 	}
 
-	teams, err := seedDataset(ctx, client, pool, cfg)
+	teams, err := seedDataset(ctx, api, pool, cfg)
 	if err != nil {
 		return fmt.Errorf("seed dataset: %w", err)
 	}
@@ -273,7 +278,7 @@ func run(cfg runConfig) error {
 
 	start := time.Now()
 	recorder := &metricRecorder{}
-	if err := executeLoad(ctx, client, pool, cfg, targets, recorder); err != nil {
+	if err := executeLoad(ctx, api, pool, cfg, targets, recorder); err != nil {
 		return err
 	}
 	elapsed := time.Since(start)
@@ -299,39 +304,30 @@ func (cfg runConfig) dbConnString() string {
 	return u.String()
 }
 
-func waitForHealthy(ctx context.Context, client *http.Client, baseURL string, timeout time.Duration) error {
+func waitForHealthy(ctx context.Context, api *client.Client, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	for {
 		if time.Now().After(deadline) {
 			return fmt.Errorf("timed out waiting for health")
 		}
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
-		if err != nil {
-			return err
-		}
-		resp, err := client.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			resp.Body.Close()
+		if err := api.Health(ctx); err == nil {
 			return nil
 		}
-		if resp != nil {
-			resp.Body.Close()
-		}
 		time.Sleep(1 * time.Second)
 	}
 }
 
-func seedDataset(ctx context.Context, client *http.Client, pool *pgxpool.Pool, cfg runConfig) ([]*teamScenario, error) {
+func seedDataset(ctx context.Context, api *client.Client, pool *pgxpool.Pool, cfg runConfig) ([]*teamScenario, error) {
 	prefix := fmt.Sprintf("%s-%d", cfg.DatasetPrefix, time.Now().Unix())
 	var teams []*teamScenario
 	for i := 0; i < cfg.TeamCount; i++ {
 		teamName := fmt.Sprintf("%s-team-%02d", prefix, i+1)
 		members, targets, nonTargets := buildMembers(teamName, cfg.UsersPerTeam, cfg.TargetsPerTeam)
-		payload := models.Team{
+		payload := client.Team{
 			TeamName: teamName,
 			Members:  members,
 		}
-		if err := postJSON(ctx, client, cfg.BaseURL+"/team/add", payload, http.StatusCreated); err != nil {
+		if _, err := api.AddTeam(ctx, payload); err != nil {
 			return nil, fmt.Errorf("create team %s: %w", teamName, err)
 		}
 		prTemplates, err := seedPullRequests(ctx, pool, teamName, targets, nonTargets, cfg.PRsPerTarget)
@@ -348,13 +344,13 @@ func seedDataset(ctx context.Context, client *http.Client, pool *pgxpool.Pool, c
 	return teams, nil
 }
 
-func buildMembers(teamName string, usersPerTeam, targetsPerTeam int) ([]models.TeamMember, []string, []string) {
-	members := make([]models.TeamMember, 0, usersPerTeam)
+func buildMembers(teamName string, usersPerTeam, targetsPerTeam int) ([]client.TeamMember, []string, []string) {
+	members := make([]client.TeamMember, 0, usersPerTeam)
 	var targets []string
 	var nonTargets []string
 	for i := 0; i < usersPerTeam; i++ {
 		userID := fmt.Sprintf("%s-user-%03d", teamName, i+1)
-		member := models.TeamMember{
+		member := client.TeamMember{
 			UserId:   userID,
 			Username: fmt.Sprintf("%s-user-%03d", teamName, i+1),
 			IsActive: true,
@@ -434,7 +430,7 @@ func buildTargets(teams []*teamScenario, batchSize int) []teamTarget {
 	return targets
 }
 
-func executeLoad(ctx context.Context, client *http.Client, pool *pgxpool.Pool, cfg runConfig, targets []teamTarget, recorder *metricRecorder) error {
+func executeLoad(ctx context.Context, api *client.Client, pool *pgxpool.Pool, cfg runConfig, targets []teamTarget, recorder *metricRecorder) error {
 	interval := time.Duration(float64(time.Second) / cfg.RPS)
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -451,7 +447,7 @@ func executeLoad(ctx context.Context, client *http.Client, pool *pgxpool.Pool, c
 		wg.Add(1)
 		go func(tt teamTarget) {
 			defer wg.Done()
-			duration, reassignments, err := executeRequest(ctx, client, pool, cfg.BaseURL, tt)
+			duration, reassignments, err := executeRequest(ctx, api, pool, tt)
 			recorder.record(duration, reassignments, err)
 		}(target)
 	}
@@ -459,7 +455,7 @@ func executeLoad(ctx context.Context, client *http.Client, pool *pgxpool.Pool, c
 	return nil
 }
 
-func executeRequest(ctx context.Context, client *http.Client, pool *pgxpool.Pool, baseURL string, target teamTarget) (time.Duration, int, error) {
+func executeRequest(ctx context.Context, api *client.Client, pool *pgxpool.Pool, target teamTarget) (time.Duration, int, error) {
 	target.Team.lock.Lock()
 	defer target.Team.lock.Unlock()
 
@@ -467,45 +463,15 @@ func executeRequest(ctx context.Context, client *http.Client, pool *pgxpool.Pool
 		return 0, 0, fmt.Errorf("reset team %s: %w", target.Team.Name, err)
 	}
 
-	payload := models.TeamBulkDeactivateRequest{
-		TeamName: target.Team.Name,
-		UserIDs:  target.UserIDs,
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/team/deactivateUsers", bytes.NewReader(body))
-	if err != nil {
-		return 0, 0, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
 	start := time.Now()
-	resp, err := client.Do(req)
-	if err != nil {
-		return 0, 0, err
-	}
-	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
+	result, err := api.DeactivateTeamMembers(ctx, target.Team.Name, target.UserIDs)
 	if err != nil {
 		return 0, 0, err
 	}
-	if resp.StatusCode >= 300 {
-		return 0, 0, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
-	}
-
-	var parsed struct {
-		Result *models.TeamBulkDeactivateResult `json:"result"`
-	}
-	if err := json.Unmarshal(respBody, &parsed); err != nil {
-		return 0, 0, fmt.Errorf("decode response: %w", err)
-	}
 	duration := time.Since(start)
 	reassignCount := 0
-	if parsed.Result != nil {
-		reassignCount = len(parsed.Result.Reassignments)
+	if result != nil {
+		reassignCount = len(result.Reassignments)
 	}
 	return duration, reassignCount, nil
 }
@@ -538,28 +504,6 @@ func resetTeamState(ctx context.Context, pool *pgxpool.Pool, team *teamScenario)
 	return tx.Commit(ctx)
 }
 
-func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, expectedStatus int) error {
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != expectedStatus {
-		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
-	}
-	return nil
-}
-
 func printSummary(summary loadSummary) {
 	fmt.Printf("\nLoad test summary:\n")
 	fmt.Printf("  Target RPS: %.2f, Actual RPS: %.2f\n", summary.TargetRPS, summary.ActualRPS)